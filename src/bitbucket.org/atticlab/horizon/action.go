@@ -1,8 +1,10 @@
 package horizon
 
 import (
+	"errors"
 	"net/http"
 	"net/url"
+	"strings"
 
 	"bitbucket.org/atticlab/horizon/actions"
 	"bitbucket.org/atticlab/horizon/admin"
@@ -11,6 +13,7 @@ import (
 	"bitbucket.org/atticlab/horizon/db2/history"
 	"bitbucket.org/atticlab/horizon/httpx"
 	"bitbucket.org/atticlab/horizon/log"
+	"bitbucket.org/atticlab/horizon/resource/operations"
 	"bitbucket.org/atticlab/horizon/toid"
 	"github.com/zenazn/goji/web"
 )
@@ -109,6 +112,46 @@ func (action *Action) GetCloseAtQuery() db2.CloseAtQuery {
 	return result
 }
 
+// GetOperationsFilter is a helper that returns a new history.OperationsFilter
+// initialized from the request. Operation types are passed as comma separated names.
+func (action *Action) GetOperationsFilter() (result history.OperationsFilter) {
+	if action.Err != nil {
+		return
+	}
+
+	rawTypes := action.GetString("type")
+	if rawTypes != "" {
+		for _, rawType := range strings.Split(rawTypes, ",") {
+			opType, ok := operations.TypeByName(strings.TrimSpace(rawType))
+			if !ok {
+				action.SetInvalidField("type", errors.New("unknown operation type "+rawType))
+				return
+			}
+			result.Types = append(result.Types, opType)
+		}
+	}
+
+	result.AssetCode = action.GetString("asset_code")
+	result.AssetIssuer = action.GetOptionalAddress("asset_issuer")
+	result.MinAmount = action.GetOptionalAmount("min_amount")
+	result.MaxAmount = action.GetOptionalAmount("max_amount")
+	result.CounterpartyType = action.GetOptionalAccountType("counterparty_type")
+	result.Memo = action.GetString("memo")
+	if action.Err != nil {
+		return
+	}
+
+	if result.AssetCode == "" && result.AssetIssuer != "" {
+		action.SetInvalidField("asset_code", errors.New("must be set with asset_issuer"))
+		return
+	}
+
+	if result.MaxAmount != 0 && result.MinAmount > result.MaxAmount {
+		action.SetInvalidField("max_amount", errors.New("must not be less than min_amount"))
+	}
+	return
+}
+
 // HistoryQ provides access to queries that access the history portion of
 // horizon's database.
 func (action *Action) HistoryQ() *history.Q {
@@ -80,6 +80,9 @@ func (base *Base) Execute(action interface{}) {
 			select {
 			case <-base.Ctx.Done():
 				return
+			case <-sse.ShuttingDown():
+				stream.Shutdown()
+				return
 			case <-sse.Pumped():
 				//no-op, continue onto the next iteration
 			}
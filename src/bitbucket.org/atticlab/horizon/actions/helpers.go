@@ -141,6 +141,12 @@ func (base *Base) GetOptionalAddress(name string) (result string) {
 	return helpers.GetOptionalAddress(base, name)
 }
 
+// GetOptionalAmount retrieves an amount from the action parameter of the given name.
+// Returns 0 if the parameter is empty
+func (base *Base) GetOptionalAmount(name string) xdr.Int64 {
+	return helpers.GetOptionalAmount(base, name)
+}
+
 func (base *Base) GetAccountID(name string) (result xdr.AccountId) {
 	return helpers.GetAccountID(base, name)
 }
@@ -0,0 +1,110 @@
+package horizon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"bitbucket.org/atticlab/go-smart-base/xdr"
+	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/render/hal"
+	"bitbucket.org/atticlab/horizon/resource"
+)
+
+// maxExpectedPayments is the max number of expected payments in a single reconciliation request
+const maxExpectedPayments = 1000
+
+// AccountReconciliationAction matches list of payments, expected by the account
+// (identified by memo type, memo and amount) against payments it received, less
+// reversed and refunded amounts. Expected payments are passed as json array in
+// `payments` field.
+type AccountReconciliationAction struct {
+	Action
+	Address   string
+	AssetCode string
+	Expected  []resource.ExpectedPayment
+	Records   []history.Operation
+	Resource  resource.Reconciliation
+}
+
+// JSON is a method for actions.JSON
+func (action *AccountReconciliationAction) JSON() {
+	action.Do(
+		action.loadParams,
+		action.loadRecords,
+		action.loadResource,
+		func() {
+			hal.Render(action.W, action.Resource)
+		},
+	)
+}
+
+func (action *AccountReconciliationAction) loadParams() {
+	action.Address = action.GetAddress("account_id")
+	action.AssetCode = action.GetString("asset_code")
+	rawPayments := action.GetString("payments")
+	if action.Err != nil {
+		return
+	}
+
+	if action.AssetCode == "" {
+		action.SetInvalidField("asset_code", errors.New("Can not be empty"))
+		return
+	}
+
+	err := json.Unmarshal([]byte(rawPayments), &action.Expected)
+	if err != nil {
+		action.SetInvalidField("payments", err)
+		return
+	}
+
+	if len(action.Expected) == 0 || len(action.Expected) > maxExpectedPayments {
+		action.SetInvalidField("payments", fmt.Errorf("must contain from 1 to %d payments", maxExpectedPayments))
+		return
+	}
+
+	memos := make(map[string]bool, len(action.Expected))
+	for i := range action.Expected {
+		if action.Expected[i].Memo == "" {
+			action.SetInvalidField("payments", fmt.Errorf("memo of payment %d can not be empty", i))
+			return
+		}
+
+		err = action.Expected[i].Parse()
+		if err != nil {
+			action.SetInvalidField("payments", fmt.Errorf("invalid payment %d: %s", i, err.Error()))
+			return
+		}
+
+		if action.Expected[i].MemoType == "hash" || action.Expected[i].MemoType == "return" {
+			action.Expected[i].Memo = normalizeHashMemo(action.Expected[i].Memo)
+		}
+
+		key := action.Expected[i].Key()
+		if memos[key] {
+			action.SetInvalidField("payments", fmt.Errorf("memo %s is duplicated", action.Expected[i].Memo))
+			return
+		}
+		memos[key] = true
+	}
+}
+
+func (action *AccountReconciliationAction) loadRecords() {
+	memos := make([]string, len(action.Expected))
+	for i := range action.Expected {
+		memos[i] = action.Expected[i].Memo
+	}
+
+	action.Err = action.HistoryQ().Operations().
+		ForAccount(action.Address).
+		ForTypes([]xdr.OperationType{xdr.OperationTypePayment, xdr.OperationTypePathPayment}).
+		ForDestination(action.Address).
+		ForAsset(action.AssetCode, "").
+		ForMemos(memos).
+		WithRefundedAmount().
+		Select(&action.Records)
+}
+
+func (action *AccountReconciliationAction) loadResource() {
+	action.Err = action.Resource.Populate(action.Expected, action.Records)
+}
@@ -0,0 +1,78 @@
+package horizon
+
+import (
+	"encoding/json"
+	"testing"
+
+	"bitbucket.org/atticlab/go-smart-base/xdr"
+	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/resource"
+	"github.com/guregu/null"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAccountReconciliation(t *testing.T) {
+	Convey("Reconciliation:", t, func() {
+		newPayment := func(id int64, memo, paymentAmount string) history.Operation {
+			rawDetails, err := json.Marshal(map[string]interface{}{
+				"amount":     paymentAmount,
+				"asset_code": "EUAH",
+			})
+			So(err, ShouldBeNil)
+			op := history.Operation{
+				Type:                xdr.OperationTypePayment,
+				DetailsString:       null.StringFrom(string(rawDetails)),
+				TransactionMemo:     null.StringFrom(memo),
+				TransactionMemoType: "text",
+			}
+			op.ID = id
+			return op
+		}
+
+		expected := []resource.ExpectedPayment{
+			{Memo: "order-1", Amount: "10"},
+			{Memo: "order-2", Amount: "20"},
+			{Memo: "order-3", Amount: "30"},
+			{Memo: "order-4", Amount: "40"},
+			{Memo: "order-5", Amount: "50"},
+			{MemoType: "id", Memo: "6", Amount: "60"},
+		}
+		for i := range expected {
+			So(expected[i].Parse(), ShouldBeNil)
+		}
+
+		payments := []history.Operation{
+			newPayment(1, "order-1", "10.0000000"),
+			newPayment(2, "order-2", "15.0000000"),
+			newPayment(3, "order-4", "25.0000000"),
+			newPayment(4, "order-4", "15.0000000"),
+			newPayment(5, "unknown", "1.0000000"),
+			newPayment(6, "order-5", "50.0000000"),
+			newPayment(7, "6", "60.0000000"),
+		}
+		// partially refunded payment is not settled
+		payments[5].RefundedAmount = null.IntFrom(100000000)
+
+		var result resource.Reconciliation
+		So(result.Populate(expected, payments), ShouldBeNil)
+		So(result.Matched, ShouldHaveLength, 2)
+		So(result.Matched[0].Memo, ShouldEqual, "order-1")
+		So(result.Matched[1].Memo, ShouldEqual, "order-4")
+		So(result.Matched[1].Payments, ShouldResemble, []string{"3", "4"})
+		So(result.Mismatched, ShouldHaveLength, 2)
+		So(result.Mismatched[0].Memo, ShouldEqual, "order-2")
+		So(result.Mismatched[0].ExpectedAmount, ShouldEqual, "20.0000000")
+		So(result.Mismatched[0].ReceivedAmount, ShouldEqual, "15.0000000")
+		So(result.Mismatched[1].Memo, ShouldEqual, "order-5")
+		So(result.Mismatched[1].ReceivedAmount, ShouldEqual, "40.0000000")
+		// text memo does not match expected id memo
+		So(result.Missing, ShouldHaveLength, 2)
+		So(result.Missing[0].Memo, ShouldEqual, "order-3")
+		So(result.Missing[1].MemoType, ShouldEqual, "id")
+	})
+	Convey("Hash memo normalization:", t, func() {
+		hexHash := "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+		So(normalizeHashMemo(hexHash), ShouldEqual, "47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=")
+		So(normalizeHashMemo("47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="), ShouldEqual, "47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=")
+	})
+}
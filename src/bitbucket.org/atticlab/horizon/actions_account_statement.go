@@ -0,0 +1,396 @@
+package horizon
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"bitbucket.org/atticlab/go-smart-base/amount"
+	"bitbucket.org/atticlab/go-smart-base/xdr"
+	"bitbucket.org/atticlab/horizon/db2"
+	"bitbucket.org/atticlab/horizon/db2/core"
+	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/log"
+	"bitbucket.org/atticlab/horizon/render/problem"
+	"bitbucket.org/atticlab/horizon/resource"
+)
+
+const (
+	// StatementFormatJSON renders statement as a single json document
+	StatementFormatJSON = "json"
+	// StatementFormatCSV renders statement as csv
+	StatementFormatCSV = "csv"
+
+	// statementFlushSize is the number of entries written between flushes of the response
+	statementFlushSize = 100
+)
+
+// statementHistoryBehind is returned when history is not yet ingested up to the
+// ledger the balance of the account was last changed in
+var statementHistoryBehind = problem.P{
+	Type:   "history_behind",
+	Title:  "History Is Behind",
+	Status: http.StatusServiceUnavailable,
+	Detail: "History of the account is not yet ingested up to its current balance. " +
+		"Please retry your request in a few seconds.",
+}
+
+// AccountStatementAction streams a statement of the account for a single asset:
+// opening balance, every debit/credit (including commissions, refunds and
+// reversals) in the period and closing balance.
+type AccountStatementAction struct {
+	Action
+	Address       string
+	AssetCode     string
+	AssetIssuer   string
+	Format        string
+	From          *time.Time
+	To            *time.Time
+	HistoryRecord history.Account
+}
+
+// JSON is a method for actions.JSON
+func (action *AccountStatementAction) JSON() {
+	action.Do(
+		action.loadParams,
+		action.loadRecord,
+		action.stream,
+	)
+}
+
+func (action *AccountStatementAction) loadParams() {
+	action.Address = action.GetAddress("account_id")
+	action.AssetCode = action.GetString("asset_code")
+	action.AssetIssuer = action.GetAddress("asset_issuer")
+	action.From = action.GetOptionalTime("from")
+	action.To = action.GetOptionalTime("to")
+	action.Format = action.GetString("format")
+	if action.Err != nil {
+		return
+	}
+
+	if action.AssetCode == "" {
+		action.SetInvalidField("asset_code", errors.New("Can not be empty"))
+		return
+	}
+
+	switch action.Format {
+	case "":
+		action.Format = StatementFormatJSON
+	case StatementFormatJSON, StatementFormatCSV:
+	default:
+		action.SetInvalidField("format", errors.New("Must be json or csv"))
+		return
+	}
+
+	if action.From != nil && action.To != nil && action.To.Before(*action.From) {
+		action.SetInvalidField("to", errors.New("Must not be before from"))
+	}
+}
+
+func (action *AccountStatementAction) loadRecord() {
+	action.Err = action.HistoryQ().AccountByAddress(&action.HistoryRecord, action.Address)
+}
+
+// loadOpeningBalance derives balance at `from` from the balance of the
+// trustline in stellar-core minus changes made since `from` up to the ledger
+// the trustline was last changed in. The ledger must be already ingested, so
+// history lagging behind stellar-core does not skew the balance. Only history
+// after `from` is scanned, so statements are correct when older history is pruned.
+func (action *AccountStatementAction) loadOpeningBalance() (int64, error) {
+	var balance int64
+	var balanceLedger int32
+	var trustline core.Trustline
+	err := action.CoreQ().TrustlineByAddressAndAsset(&trustline, action.Address, action.AssetCode, action.AssetIssuer)
+	switch {
+	case err == nil:
+		balance = int64(trustline.Balance)
+		balanceLedger = trustline.Lastmodified
+	case action.CoreQ().NoRows(err):
+		// no trustline, balance is zero as of the latest ledger
+		err = action.CoreQ().LatestLedger(&balanceLedger)
+		if err != nil {
+			return 0, err
+		}
+	default:
+		return 0, err
+	}
+
+	var historyLedger int32
+	err = action.HistoryQ().LatestLedger(&historyLedger)
+	if err != nil {
+		return 0, err
+	}
+
+	if historyLedger < balanceLedger {
+		return 0, &statementHistoryBehind
+	}
+
+	closedAt := db2.CloseAtQuery{Start: action.From}
+	rows, err := action.HistoryQ().Operations().ForAccount(action.Address).OnlyPayments().
+		ForAsset(action.AssetCode, action.AssetIssuer).UpToLedger(balanceLedger).ClosedAt(closedAt).Rows()
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var record history.Operation
+		err = rows.StructScan(&record)
+		if err != nil {
+			return 0, err
+		}
+
+		var entry resource.StatementEntry
+		var ok bool
+		ok, err = entry.Populate(record, action.Address, action.AssetCode, action.AssetIssuer)
+		if err != nil {
+			return 0, err
+		}
+
+		if ok {
+			balance -= entry.Change
+		}
+	}
+
+	return balance, rows.Err()
+}
+
+// stream walks through the payments of the account in [from, to)
+func (action *AccountStatementAction) stream() {
+	balance, err := action.loadOpeningBalance()
+	if err != nil {
+		action.Err = err
+		return
+	}
+
+	closedAt := db2.CloseAtQuery{Start: action.From, End: action.To}
+	rows, err := action.HistoryQ().Operations().ForAccount(action.Address).OnlyPayments().
+		ForAsset(action.AssetCode, action.AssetIssuer).ClosedAt(closedAt).Rows()
+	if err != nil {
+		action.Err = err
+		return
+	}
+	defer rows.Close()
+
+	logger := log.WithFields(log.F{
+		"service":    "account_statement",
+		"account_id": action.Address,
+	})
+
+	writer := action.newWriter()
+	err = writer.Open(balance)
+	if err != nil {
+		action.Err = err
+		return
+	}
+
+	count := 0
+	for rows.Next() {
+		var record history.Operation
+		err = rows.StructScan(&record)
+		if err != nil {
+			break
+		}
+
+		var entry resource.StatementEntry
+		var ok bool
+		ok, err = entry.Populate(record, action.Address, action.AssetCode, action.AssetIssuer)
+		if err != nil {
+			break
+		}
+
+		if !ok {
+			continue
+		}
+
+		balance += entry.Change
+		entry.SetBalance(balance)
+		err = writer.Entry(entry)
+		if err != nil {
+			break
+		}
+
+		count++
+		if count%statementFlushSize == 0 {
+			action.flush()
+		}
+	}
+
+	if err == nil {
+		err = rows.Err()
+	}
+
+	if err != nil {
+		// headers are already sent, nothing to do but to abort the stream
+		logger.WithError(err).Error("Failed to stream account statement")
+		return
+	}
+
+	err = writer.Close(balance)
+	if err != nil {
+		logger.WithError(err).Error("Failed to write account statement")
+		return
+	}
+	action.flush()
+}
+
+func (action *AccountStatementAction) flush() {
+	if f, ok := action.W.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (action *AccountStatementAction) newWriter() statementWriter {
+	header := statementHeader{
+		AccountID:   action.Address,
+		AssetCode:   action.AssetCode,
+		AssetIssuer: action.AssetIssuer,
+		From:        action.From,
+		To:          action.To,
+	}
+
+	if action.Format == StatementFormatCSV {
+		action.W.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		action.W.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"statement_%s_%s.csv\"", action.Address, action.AssetCode))
+		return &csvStatementWriter{w: csv.NewWriter(action.W)}
+	}
+
+	action.W.Header().Set("Content-Type", "application/json; charset=utf-8")
+	return &jsonStatementWriter{w: action.W, header: header}
+}
+
+type statementHeader struct {
+	AccountID      string     `json:"account_id"`
+	AssetCode      string     `json:"asset_code"`
+	AssetIssuer    string     `json:"asset_issuer"`
+	From           *time.Time `json:"from,omitempty"`
+	To             *time.Time `json:"to,omitempty"`
+	OpeningBalance string     `json:"opening_balance"`
+}
+
+// statementWriter writes statement to the response in the requested format
+type statementWriter interface {
+	// Writes header of the statement with opening balance
+	Open(openingBalance int64) error
+	// Writes single entry
+	Entry(entry resource.StatementEntry) error
+	// Writes closing balance and finishes the statement
+	Close(closingBalance int64) error
+}
+
+// jsonStatementWriter writes statement as json object with `entries` array.
+// Entries are marshaled one by one, so the whole statement is never kept in memory.
+type jsonStatementWriter struct {
+	w       io.Writer
+	header  statementHeader
+	entries int
+}
+
+func (w *jsonStatementWriter) Open(openingBalance int64) error {
+	w.header.OpeningBalance = amount.String(xdr.Int64(openingBalance))
+	rawHeader, err := json.Marshal(w.header)
+	if err != nil {
+		return err
+	}
+
+	// cut closing bracket to append entries
+	_, err = w.w.Write(rawHeader[:len(rawHeader)-1])
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w.w, ",\"entries\":[")
+	return err
+}
+
+func (w *jsonStatementWriter) Entry(entry resource.StatementEntry) error {
+	raw, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	if w.entries > 0 {
+		_, err = io.WriteString(w.w, ",")
+		if err != nil {
+			return err
+		}
+	}
+	w.entries++
+
+	_, err = w.w.Write(raw)
+	return err
+}
+
+func (w *jsonStatementWriter) Close(closingBalance int64) error {
+	_, err := fmt.Fprintf(w.w, "],\"closing_balance\":%q}", amount.String(xdr.Int64(closingBalance)))
+	return err
+}
+
+// csvStatementWriter writes statement as csv. Opening and closing balances are
+// written as separate rows.
+type csvStatementWriter struct {
+	w *csv.Writer
+}
+
+var csvStatementColumns = []string{
+	"id",
+	"transaction_hash",
+	"type",
+	"closed_at",
+	"counterparty",
+	"debit",
+	"credit",
+	"commission",
+	"balance",
+}
+
+func (w *csvStatementWriter) Open(openingBalance int64) error {
+	err := w.w.Write(csvStatementColumns)
+	if err != nil {
+		return err
+	}
+
+	return w.writeBalance("opening_balance", openingBalance)
+}
+
+func (w *csvStatementWriter) Entry(entry resource.StatementEntry) error {
+	err := w.w.Write([]string{
+		entry.ID,
+		entry.TransactionHash,
+		entry.Type,
+		entry.ClosedAt.UTC().Format(time.RFC3339),
+		entry.Counterparty,
+		entry.Debit,
+		entry.Credit,
+		entry.Commission,
+		entry.Balance,
+	})
+	if err != nil {
+		return err
+	}
+
+	w.w.Flush()
+	return w.w.Error()
+}
+
+func (w *csvStatementWriter) Close(closingBalance int64) error {
+	return w.writeBalance("closing_balance", closingBalance)
+}
+
+func (w *csvStatementWriter) writeBalance(typ string, balance int64) error {
+	row := make([]string, len(csvStatementColumns))
+	row[2] = typ
+	row[len(row)-1] = amount.String(xdr.Int64(balance))
+	err := w.w.Write(row)
+	if err != nil {
+		return err
+	}
+
+	w.w.Flush()
+	return w.w.Error()
+}
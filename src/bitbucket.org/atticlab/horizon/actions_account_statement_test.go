@@ -0,0 +1,204 @@
+package horizon
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"bitbucket.org/atticlab/go-smart-base/keypair"
+	"bitbucket.org/atticlab/go-smart-base/xdr"
+	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/resource"
+	"bitbucket.org/atticlab/horizon/test"
+	"bitbucket.org/atticlab/horizon/toid"
+	"github.com/guregu/null"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAccountStatement(t *testing.T) {
+	Convey("Account statement:", t, func() {
+		account, err := keypair.Random()
+		So(err, ShouldBeNil)
+		counterparty, err := keypair.Random()
+		So(err, ShouldBeNil)
+
+		newOp := func(typ xdr.OperationType, opDetails map[string]interface{}) history.Operation {
+			rawDetails, err := json.Marshal(opDetails)
+			So(err, ShouldBeNil)
+			op := history.Operation{
+				Type:          typ,
+				DetailsString: null.StringFrom(string(rawDetails)),
+			}
+			op.ID = 1
+			return op
+		}
+
+		Convey("Outgoing payment with commission", func() {
+			op := newOp(xdr.OperationTypePayment, map[string]interface{}{
+				"from":       account.Address(),
+				"to":         counterparty.Address(),
+				"amount":     "10.0000000",
+				"asset_code": "EUAH",
+				"fee": map[string]interface{}{
+					"amount_changed": "0.5000000",
+				},
+			})
+			var entry resource.StatementEntry
+			ok, err := entry.Populate(op, account.Address(), "EUAH", "")
+			So(err, ShouldBeNil)
+			So(ok, ShouldBeTrue)
+			So(entry.Counterparty, ShouldEqual, counterparty.Address())
+			So(entry.Debit, ShouldEqual, "10.0000000")
+			So(entry.Commission, ShouldEqual, "0.5000000")
+			So(entry.Change, ShouldEqual, -105000000)
+
+			Convey("Is ignored for other asset", func() {
+				ok, err = entry.Populate(op, account.Address(), "USD", "")
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+			})
+			Convey("Is credit for receiver", func() {
+				ok, err = entry.Populate(op, counterparty.Address(), "EUAH", "")
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+				So(entry.Counterparty, ShouldEqual, account.Address())
+				So(entry.Change, ShouldEqual, 100000000)
+			})
+		})
+		Convey("Payment reversal returns commission", func() {
+			op := newOp(xdr.OperationTypePaymentReversal, map[string]interface{}{
+				"source_account": counterparty.Address(),
+				"payment_source": account.Address(),
+				"amount":         "10.0000000",
+				"commission":     "0.5000000",
+				"asset_code":     "EUAH",
+			})
+			var entry resource.StatementEntry
+			ok, err := entry.Populate(op, account.Address(), "EUAH", "")
+			So(err, ShouldBeNil)
+			So(ok, ShouldBeTrue)
+			So(entry.Credit, ShouldEqual, "10.0000000")
+			So(entry.Change, ShouldEqual, 105000000)
+
+			ok, err = entry.Populate(op, counterparty.Address(), "EUAH", "")
+			So(err, ShouldBeNil)
+			So(ok, ShouldBeTrue)
+			So(entry.Change, ShouldEqual, -100000000)
+		})
+		Convey("Writers", func() {
+			entry := resource.StatementEntry{ID: "1", Type: "payment", Credit: "1.0000000"}
+			entry.SetBalance(30000000)
+			var buf bytes.Buffer
+			Convey("JSON", func() {
+				writer := jsonStatementWriter{w: &buf, header: statementHeader{AccountID: account.Address(), AssetCode: "EUAH"}}
+				So(writer.Open(20000000), ShouldBeNil)
+				So(writer.Entry(entry), ShouldBeNil)
+				So(writer.Entry(entry), ShouldBeNil)
+				So(writer.Close(40000000), ShouldBeNil)
+
+				var result map[string]interface{}
+				So(json.Unmarshal(buf.Bytes(), &result), ShouldBeNil)
+				So(result["opening_balance"], ShouldEqual, "2.0000000")
+				So(result["closing_balance"], ShouldEqual, "4.0000000")
+				So(result["entries"], ShouldHaveLength, 2)
+			})
+			Convey("CSV", func() {
+				writer := csvStatementWriter{w: csv.NewWriter(&buf)}
+				So(writer.Open(20000000), ShouldBeNil)
+				So(writer.Entry(entry), ShouldBeNil)
+				So(writer.Close(30000000), ShouldBeNil)
+
+				records, err := csv.NewReader(&buf).ReadAll()
+				So(err, ShouldBeNil)
+				So(records, ShouldHaveLength, 4)
+				So(records[1][2], ShouldEqual, "opening_balance")
+				So(records[2][len(csvStatementColumns)-1], ShouldEqual, "3.0000000")
+				So(records[3][len(csvStatementColumns)-1], ShouldEqual, "3.0000000")
+			})
+		})
+	})
+}
+
+func TestAccountStatementOpeningBalance(t *testing.T) {
+	test.LoadScenario("base")
+	app := NewTestApp()
+	defer app.Close()
+	rh := NewRequestHelper(app)
+
+	Convey("Account statement opening balance:", t, func() {
+		account := "GAWIB7ETYGSWULO4VB7D6S42YLPGIC7TY7Y2SSJKVOTMQXV5TILYWBUA"
+		counterparty := "GCO5BZT5V3N3SK2CD5UKDSEQJBYFSIMYDV2B75SLKWEXLRYF5GNORYCG"
+		issuer, err := keypair.Random()
+		So(err, ShouldBeNil)
+		otherIssuer, err := keypair.Random()
+		So(err, ShouldBeNil)
+
+		coreRepo := app.CoreRepo(nil)
+		horizonRepo := app.HorizonRepo(nil)
+		insertTrustline := func(issuer string, balance int64, lastModified int32) {
+			_, err := coreRepo.ExecRaw("INSERT INTO trustlines VALUES ($1, 1, $2, 'EUAH', 9223372036854775807, $3, 1, $4)",
+				account, issuer, balance, lastModified)
+			So(err, ShouldBeNil)
+		}
+		insertPayment := func(ledger int32, from, to, issuer, amount string) {
+			txID := toid.New(ledger, 1, 0).ToInt64()
+			opID := toid.New(ledger, 1, 1).ToInt64()
+			_, err := horizonRepo.ExecRaw(`INSERT INTO history_transactions (transaction_hash, ledger_sequence,
+				application_order, account, account_sequence, fee_paid, operation_count, id, tx_envelope, tx_result,
+				tx_meta, tx_fee_meta) VALUES ($1, $2, 1, $3, 1, 0, 1, $4, '', '', '', '')`,
+				fmt.Sprintf("%064d", txID), ledger, from, txID)
+			So(err, ShouldBeNil)
+
+			rawDetails, err := json.Marshal(map[string]interface{}{
+				"from":         from,
+				"to":           to,
+				"amount":       amount,
+				"asset_type":   "credit_alphanum4",
+				"asset_code":   "EUAH",
+				"asset_issuer": issuer,
+			})
+			So(err, ShouldBeNil)
+			_, err = horizonRepo.ExecRaw("INSERT INTO history_operations VALUES ($1, $2, 1, $3, $4, $5)",
+				opID, txID, xdr.OperationTypePayment, string(rawDetails), from)
+			So(err, ShouldBeNil)
+
+			for i, address := range []string{from, to} {
+				_, err = horizonRepo.ExecRaw(`INSERT INTO history_operation_participants (id, history_operation_id, history_account_id)
+					SELECT $1, $2, id FROM history_accounts WHERE address = $3`, opID*2+int64(i), opID, address)
+				So(err, ShouldBeNil)
+			}
+		}
+		openingBalance := func() (int, string) {
+			w := rh.Get(fmt.Sprintf("/accounts/%s/statement?asset_code=EUAH&asset_issuer=%s", account, issuer.Address()), test.RequestHelperNoop)
+			if w.Code != 200 {
+				return w.Code, ""
+			}
+
+			var result map[string]interface{}
+			So(json.Unmarshal(w.Body.Bytes(), &result), ShouldBeNil)
+			return w.Code, result["opening_balance"].(string)
+		}
+
+		// 50 received in ledger 4 and 20 sent in ledger 5 leave 30
+		insertPayment(4, counterparty, account, issuer.Address(), "50.0000000")
+		insertPayment(5, account, counterparty, issuer.Address(), "20.0000000")
+		insertTrustline(issuer.Address(), 300000000, 5)
+		// asset with the same code of another issuer is not mixed in
+		insertTrustline(otherIssuer.Address(), 1000000000, 5)
+
+		Convey("balance before the first payment is zero", func() {
+			code, balance := openingBalance()
+			So(code, ShouldEqual, 200)
+			So(balance, ShouldEqual, "0.0000000")
+		})
+
+		Convey("history behind stellar-core is not used", func() {
+			_, err := coreRepo.ExecRaw("UPDATE trustlines SET lastmodified = 6 WHERE issuer = $1", issuer.Address())
+			So(err, ShouldBeNil)
+			code, _ := openingBalance()
+			So(code, ShouldEqual, 503)
+		})
+	})
+}
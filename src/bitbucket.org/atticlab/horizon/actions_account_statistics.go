@@ -3,6 +3,7 @@ package horizon
 import (
 	"bitbucket.org/atticlab/go-smart-base/xdr"
 	"bitbucket.org/atticlab/horizon/accounttypes"
+	conf "bitbucket.org/atticlab/horizon/config"
 	"bitbucket.org/atticlab/horizon/db2/history"
 	"bitbucket.org/atticlab/horizon/redis"
 	"bitbucket.org/atticlab/horizon/render/hal"
@@ -98,6 +99,11 @@ func (action *AccountStatisticsAction) loadFromCache() {
 		return
 	}
 
+	if action.App.config.StatisticsBackend == conf.StatisticsBackendPostgres {
+		action.loadFromPostgresCache()
+		return
+	}
+
 	conn := redis.NewConnectionProvider().GetConnection()
 	defer conn.Close()
 	stats, err := redis.NewAccountStatisticsProvider(conn).Get(action.Address, action.AssetCode, accounttype.GetAll())
@@ -111,6 +117,25 @@ func (action *AccountStatisticsAction) loadFromCache() {
 	}
 }
 
+func (action *AccountStatisticsAction) loadFromPostgresCache() {
+	cache, err := action.HistoryQ().StatisticsCacheByAccount(action.Address, action.AssetCode, time.Now())
+	if err != nil {
+		action.Err = &problem.ServerError
+		return
+	}
+
+	if cache == nil {
+		return
+	}
+
+	stats, err := cache.Statistics()
+	if err != nil {
+		action.Err = &problem.ServerError
+		return
+	}
+	action.mapToArray(stats)
+}
+
 func (action *AccountStatisticsAction) loadResource() {
 	action.Err = action.Resource.Populate(
 		action.Ctx,
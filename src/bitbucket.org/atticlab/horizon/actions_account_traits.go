@@ -6,11 +6,14 @@ import (
 	"bitbucket.org/atticlab/horizon/render/hal"
 	"bitbucket.org/atticlab/horizon/render/sse"
 	"bitbucket.org/atticlab/horizon/resource"
+	"time"
 )
 
 // This file contains the actions:
 //
 // AccountTraitsIndexAction: pages of account's traits in order of creation of accounts
+// AccountTraitsAction: traits and active freezes of the account
+// AccountTraitsHistoryAction: pages of changes of account's traits
 type AccountTraitsIndexAction struct {
 	Action
 	PagingParams db2.PageQuery
@@ -73,6 +76,7 @@ type AccountTraitsAction struct {
 	Action
 	Address        string
 	HistoryRecord  history.Account
+	Freezes        []history.AccountFreeze
 	Resource       resource.AccountTraits
 }
 
@@ -111,6 +115,11 @@ func (action *AccountTraitsAction) loadRecord() {
 	}
 
 	action.Err = action.HistoryQ().AccountByAddress(&action.HistoryRecord, action.Address)
+	if action.Err != nil {
+		return
+	}
+
+	action.Freezes, action.Err = action.HistoryQ().ActiveAccountFreezes(action.Address, time.Now())
 }
 
 func (action *AccountTraitsAction) loadResource() {
@@ -118,4 +127,56 @@ func (action *AccountTraitsAction) loadResource() {
 		action.Ctx,
 		action.HistoryRecord,
 	)
+	if action.Err != nil {
+		return
+	}
+
+	action.Resource.PopulateFreezes(action.Freezes)
+}
+
+// AccountTraitsHistoryAction renders pages of changes of account's traits,
+// including temporary freezes
+type AccountTraitsHistoryAction struct {
+	Action
+	Address      string
+	PagingParams db2.PageQuery
+	Records      []history.AccountTraitsEvent
+	Page         hal.Page
+}
+
+// JSON is a method for actions.JSON
+func (action *AccountTraitsHistoryAction) JSON() {
+	action.Do(
+		action.loadParams,
+		action.loadRecords,
+		action.loadPage,
+		func() { hal.Render(action.W, action.Page) },
+	)
+}
+
+func (action *AccountTraitsHistoryAction) loadParams() {
+	action.ValidateCursorAsDefault()
+	action.Address = action.GetAddress("account_id")
+	action.PagingParams = action.GetPageQuery()
+}
+
+func (action *AccountTraitsHistoryAction) loadRecords() {
+	action.Err = action.HistoryQ().AccountTraitsEvents().
+		ForAccount(action.Address).
+		Page(action.PagingParams).
+		Select(&action.Records)
+}
+
+func (action *AccountTraitsHistoryAction) loadPage() {
+	for _, record := range action.Records {
+		var res resource.AccountTraitsEvent
+		res.Populate(action.Ctx, record)
+		action.Page.Add(res)
+	}
+	action.Page.BaseURL = action.BaseURL()
+	action.Page.BasePath = action.Path()
+	action.Page.Limit = action.PagingParams.Limit
+	action.Page.Cursor = action.PagingParams.Cursor
+	action.Page.Order = action.PagingParams.Order
+	action.Page.PopulateLinks()
 }
@@ -1,7 +1,9 @@
 package horizon
 
 import (
+	"bitbucket.org/atticlab/go-smart-base/xdr"
 	"bitbucket.org/atticlab/horizon/db2"
+	"bitbucket.org/atticlab/horizon/db2/core"
 	"bitbucket.org/atticlab/horizon/db2/history"
 	"bitbucket.org/atticlab/horizon/render/hal"
 	"bitbucket.org/atticlab/horizon/render/sse"
@@ -11,6 +13,7 @@ import (
 // This file contains the actions:
 //
 // AssetIndexAction: pages of assets in order of creation
+// AssetShowAction: single asset with its supply and holders
 // AssetIndexAction renders a page of asset resources, identified by
 // a normal page query, ordered by the operation id that created them.
 type AssetIndexAction struct {
@@ -39,7 +42,10 @@ func (action *AssetIndexAction) SSE(stream sse.Stream) {
 			stream.SetLimit(int(action.PagingParams.Limit))
 			var res resource.HistoryAsset
 			for _, record := range action.Records[stream.SentCount():] {
-				res.Populate(action.Ctx, record)
+				action.Err = res.Populate(action.Ctx, record)
+				if action.Err != nil {
+					return
+				}
 				stream.Send(sse.Event{ID: res.PagingToken(), Data: res})
 			}
 		},
@@ -59,7 +65,10 @@ func (action *AssetIndexAction) loadRecords() {
 func (action *AssetIndexAction) loadPage() {
 	for _, record := range action.Records {
 		var res resource.HistoryAsset
-		res.Populate(action.Ctx, record)
+		action.Err = res.Populate(action.Ctx, record)
+		if action.Err != nil {
+			return
+		}
 		action.Page.Add(res)
 	}
 	action.Page.BaseURL = action.BaseURL()
@@ -69,3 +78,50 @@ func (action *AssetIndexAction) loadPage() {
 	action.Page.Order = action.PagingParams.Order
 	action.Page.PopulateLinks()
 }
+
+// AssetShowAction renders an asset found by its code and issuer with
+// circulating supply and number of holders.
+type AssetShowAction struct {
+	Action
+	Code     string
+	Issuer   string
+	Record   history.Asset
+	Stats    core.AssetStats
+	Resource resource.AssetDetails
+}
+
+// JSON is a method for actions.JSON
+func (action *AssetShowAction) JSON() {
+	action.Do(
+		action.loadParams,
+		action.loadRecord,
+		action.loadResource,
+		func() { hal.Render(action.W, action.Resource) },
+	)
+}
+
+func (action *AssetShowAction) loadParams() {
+	action.Code = action.GetString("code")
+	action.Issuer = action.GetAddress("issuer")
+}
+
+func (action *AssetShowAction) loadRecord() {
+	action.Err = action.HistoryQ().AssetByParams(&action.Record, int(assetTypeByCode(action.Code)), action.Code, action.Issuer)
+	if action.Err != nil {
+		return
+	}
+
+	action.Err = action.CoreQ().AssetStats(&action.Stats, action.Code, action.Issuer)
+}
+
+func (action *AssetShowAction) loadResource() {
+	action.Err = action.Resource.Populate(action.Ctx, action.Record, action.Stats)
+}
+
+// assetTypeByCode returns type of credit asset by length of its code
+func assetTypeByCode(code string) xdr.AssetType {
+	if len(code) > 4 {
+		return xdr.AssetTypeAssetTypeCreditAlphanum12
+	}
+	return xdr.AssetTypeAssetTypeCreditAlphanum4
+}
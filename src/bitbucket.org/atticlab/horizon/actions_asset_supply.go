@@ -0,0 +1,110 @@
+package horizon
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"time"
+
+	"bitbucket.org/atticlab/horizon/db2/core"
+	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/render/hal"
+	"bitbucket.org/atticlab/horizon/resource"
+)
+
+const (
+	// SupplyFormatJSON renders supply as a json document
+	SupplyFormatJSON = "json"
+	// SupplyFormatCSV renders daily supply history as csv
+	SupplyFormatCSV = "csv"
+)
+
+// AssetSupplyAction renders supply of the asset: total amount held by
+// non-issuer accounts, its breakdown by holder account type and daily history
+// of issuance and redemption in [from, to).
+type AssetSupplyAction struct {
+	Action
+	Code          string
+	Issuer        string
+	Format        string
+	From          *time.Time
+	To            *time.Time
+	Asset         history.Asset
+	ByAccountType []core.AssetSupplyByAccountType
+	History       []history.AssetDailySupply
+	Resource      resource.AssetSupply
+}
+
+// JSON is a method for actions.JSON
+func (action *AssetSupplyAction) JSON() {
+	action.Do(
+		action.loadParams,
+		action.loadRecords,
+		action.loadResource,
+		action.render,
+	)
+}
+
+func (action *AssetSupplyAction) loadParams() {
+	action.Code = action.GetString("code")
+	action.Issuer = action.GetAddress("issuer")
+	action.From = action.GetOptionalTime("from")
+	action.To = action.GetOptionalTime("to")
+	action.Format = action.GetString("format")
+	if action.Err != nil {
+		return
+	}
+
+	switch action.Format {
+	case "":
+		action.Format = SupplyFormatJSON
+	case SupplyFormatJSON, SupplyFormatCSV:
+	default:
+		action.SetInvalidField("format", errors.New("Must be json or csv"))
+		return
+	}
+
+	if action.From != nil && action.To != nil && action.To.Before(*action.From) {
+		action.SetInvalidField("to", errors.New("Must not be before from"))
+	}
+}
+
+func (action *AssetSupplyAction) loadRecords() {
+	action.Err = action.HistoryQ().AssetByParams(&action.Asset, int(assetTypeByCode(action.Code)), action.Code, action.Issuer)
+	if action.Err != nil {
+		return
+	}
+
+	action.Err = action.CoreQ().AssetSupplyByAccountType(&action.ByAccountType, action.Code, action.Issuer)
+	if action.Err != nil {
+		return
+	}
+
+	action.History, action.Err = action.HistoryQ().AssetSupplyHistory(action.Code, action.Issuer, action.From, action.To)
+}
+
+func (action *AssetSupplyAction) loadResource() {
+	action.Resource.Populate(action.Ctx, action.Code, action.Issuer, action.ByAccountType, action.History)
+}
+
+func (action *AssetSupplyAction) render() {
+	if action.Format != SupplyFormatCSV {
+		hal.Render(action.W, action.Resource)
+		return
+	}
+
+	action.W.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	action.W.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"supply_%s_%s.csv\"", action.Code, action.Issuer))
+
+	w := csv.NewWriter(action.W)
+	rows := [][]string{{"date", "issued", "redeemed", "supply"}}
+	for _, day := range action.Resource.History {
+		rows = append(rows, []string{day.Date, day.Issued, day.Redeemed, day.Supply})
+	}
+
+	err := w.WriteAll(rows)
+	if err != nil {
+		// headers are already sent, nothing to do but to abort the response
+		action.Log.WithField("err", err).Error("Failed to write asset supply")
+	}
+}
@@ -0,0 +1,69 @@
+package horizon
+
+import (
+	"encoding/json"
+	"testing"
+
+	"bitbucket.org/atticlab/horizon/resource"
+	"bitbucket.org/atticlab/horizon/test"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAssetActions(t *testing.T) {
+	test.LoadScenario("base")
+	app := NewTestApp()
+	defer app.Close()
+	rh := NewRequestHelper(app)
+
+	Convey("Asset Actions:", t, func() {
+		Convey("GET /assets/:code/:issuer", func() {
+			w := rh.Get("/assets/AUAH/GAWIB7ETYGSWULO4VB7D6S42YLPGIC7TY7Y2SSJKVOTMQXV5TILYWBUA", test.RequestHelperNoop)
+			So(w.Code, ShouldEqual, 200)
+
+			var result resource.AssetDetails
+			err := json.Unmarshal(w.Body.Bytes(), &result)
+			So(err, ShouldBeNil)
+			So(result.Code, ShouldEqual, "AUAH")
+			So(result.IsAnonymous, ShouldBeTrue)
+			So(result.Status, ShouldEqual, "active")
+			So(result.RegulatoryFlags, ShouldBeEmpty)
+			So(result.CirculatingSupply, ShouldEqual, "0.0000000")
+			So(result.Holders, ShouldEqual, 0)
+		})
+
+		Convey("GET /assets/:code/:issuer of unknown asset", func() {
+			w := rh.Get("/assets/USD/GAWIB7ETYGSWULO4VB7D6S42YLPGIC7TY7Y2SSJKVOTMQXV5TILYWBUA", test.RequestHelperNoop)
+			So(w.Code, ShouldEqual, 404)
+		})
+
+		Convey("GET /assets/:code/:issuer/supply", func() {
+			w := rh.Get("/assets/AUAH/GAWIB7ETYGSWULO4VB7D6S42YLPGIC7TY7Y2SSJKVOTMQXV5TILYWBUA/supply", test.RequestHelperNoop)
+			So(w.Code, ShouldEqual, 200)
+
+			var result resource.AssetSupply
+			err := json.Unmarshal(w.Body.Bytes(), &result)
+			So(err, ShouldBeNil)
+			So(result.AssetCode, ShouldEqual, "AUAH")
+			So(result.TotalIssued, ShouldEqual, "0.0000000")
+			So(result.ByAccountType, ShouldBeEmpty)
+			So(result.History, ShouldBeEmpty)
+		})
+
+		Convey("GET /assets/:code/:issuer/supply as csv", func() {
+			w := rh.Get("/assets/AUAH/GAWIB7ETYGSWULO4VB7D6S42YLPGIC7TY7Y2SSJKVOTMQXV5TILYWBUA/supply?format=csv", test.RequestHelperNoop)
+			So(w.Code, ShouldEqual, 200)
+			So(w.Header().Get("Content-Type"), ShouldStartWith, "text/csv")
+			So(w.Body.String(), ShouldEqual, "date,issued,redeemed,supply\n")
+		})
+
+		Convey("GET /assets/:code/:issuer/supply with invalid format", func() {
+			w := rh.Get("/assets/AUAH/GAWIB7ETYGSWULO4VB7D6S42YLPGIC7TY7Y2SSJKVOTMQXV5TILYWBUA/supply?format=xml", test.RequestHelperNoop)
+			So(w.Code, ShouldEqual, 400)
+		})
+
+		Convey("GET /assets/:code/:issuer with invalid issuer", func() {
+			w := rh.Get("/assets/UAH/invalid", test.RequestHelperNoop)
+			So(w.Code, ShouldEqual, 400)
+		})
+	})
+}
@@ -0,0 +1,73 @@
+package horizon
+
+import (
+	"bitbucket.org/atticlab/horizon/db2"
+	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/render/hal"
+	"bitbucket.org/atticlab/horizon/resource"
+)
+
+// This file contains the actions:
+//
+// ComplianceAlertsIndexAction: pages of alerts on suspicious activity detected on ingestion
+type ComplianceAlertsIndexAction struct {
+	Action
+	Account      string
+	Rule         string
+	Acknowledged *bool
+	PagingParams db2.PageQuery
+	Records      []history.ComplianceAlert
+	Page         hal.Page
+}
+
+// JSON is a method for actions.JSON
+func (action *ComplianceAlertsIndexAction) JSON() {
+	action.Do(
+		action.loadParams,
+		action.loadRecords,
+		action.loadPage,
+		func() { hal.Render(action.W, action.Page) },
+	)
+}
+
+func (action *ComplianceAlertsIndexAction) loadParams() {
+	action.ValidateCursorAsDefault()
+	action.Account = action.GetOptionalAddress("account_id")
+	action.Rule = action.GetString("rule")
+	action.Acknowledged = action.GetOptionalBool("acknowledged")
+	action.PagingParams = action.GetPageQuery()
+}
+
+func (action *ComplianceAlertsIndexAction) loadRecords() {
+	alerts := action.HistoryQ().ComplianceAlerts()
+	if action.Account != "" {
+		alerts.ForAccount(action.Account)
+	}
+
+	if action.Rule != "" {
+		alerts.ForRule(action.Rule)
+	}
+
+	if action.Acknowledged != nil {
+		alerts.Acknowledged(*action.Acknowledged)
+	}
+
+	action.Err = alerts.Page(action.PagingParams).Select(&action.Records)
+}
+
+func (action *ComplianceAlertsIndexAction) loadPage() {
+	for _, record := range action.Records {
+		var res resource.ComplianceAlert
+		action.Err = res.Populate(action.Ctx, record)
+		if action.Err != nil {
+			return
+		}
+		action.Page.Add(res)
+	}
+	action.Page.BaseURL = action.BaseURL()
+	action.Page.BasePath = action.Path()
+	action.Page.Limit = action.PagingParams.Limit
+	action.Page.Cursor = action.PagingParams.Cursor
+	action.Page.Order = action.PagingParams.Order
+	action.Page.PopulateLinks()
+}
@@ -0,0 +1,118 @@
+package horizon
+
+import (
+	"bitbucket.org/atticlab/horizon/db2"
+	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/render/hal"
+	"bitbucket.org/atticlab/horizon/render/problem"
+	"bitbucket.org/atticlab/horizon/resource"
+	"github.com/go-errors/errors"
+)
+
+// This file contains the actions:
+//
+// DisputeIndexAction: pages of dispute cases
+// DisputeShowAction: single dispute case with its notes
+type DisputeIndexAction struct {
+	Action
+	Account      string
+	PaymentID    int64
+	State        history.DisputeState
+	PagingParams db2.PageQuery
+	Records      []history.Dispute
+	Page         hal.Page
+}
+
+// JSON is a method for actions.JSON
+func (action *DisputeIndexAction) JSON() {
+	action.Do(
+		action.loadParams,
+		action.loadRecords,
+		action.loadPage,
+		func() { hal.Render(action.W, action.Page) },
+	)
+}
+
+func (action *DisputeIndexAction) loadParams() {
+	action.ValidateCursorAsDefault()
+	action.Account = action.GetOptionalAddress("account_id")
+	action.PaymentID = action.GetInt64("payment_id")
+	action.State = history.DisputeState(action.GetString("state"))
+	action.PagingParams = action.GetPageQuery()
+	if action.Err == nil && action.State != "" && !action.State.IsValid() {
+		action.SetInvalidField("state", errors.New("unknown state"))
+	}
+}
+
+func (action *DisputeIndexAction) loadRecords() {
+	disputes := action.HistoryQ().Disputes()
+	if action.Account != "" {
+		disputes.ForAccount(action.Account)
+	}
+
+	if action.PaymentID != 0 {
+		disputes.ForPayment(action.PaymentID)
+	}
+
+	if action.State != "" {
+		disputes.InState(action.State)
+	}
+
+	action.Err = disputes.Page(action.PagingParams).Select(&action.Records)
+}
+
+func (action *DisputeIndexAction) loadPage() {
+	for _, record := range action.Records {
+		var res resource.Dispute
+		res.Populate(action.Ctx, record)
+		action.Page.Add(res)
+	}
+	action.Page.BaseURL = action.BaseURL()
+	action.Page.BasePath = action.Path()
+	action.Page.Limit = action.PagingParams.Limit
+	action.Page.Cursor = action.PagingParams.Cursor
+	action.Page.Order = action.PagingParams.Order
+	action.Page.PopulateLinks()
+}
+
+// DisputeShowAction renders a dispute case found by its id.
+type DisputeShowAction struct {
+	Action
+	ID       int64
+	Record   *history.Dispute
+	Notes    []history.DisputeNote
+	Resource resource.Dispute
+}
+
+// JSON is a method for actions.JSON
+func (action *DisputeShowAction) JSON() {
+	action.Do(
+		action.loadParams,
+		action.loadRecord,
+		action.loadResource,
+		func() { hal.Render(action.W, action.Resource) },
+	)
+}
+
+func (action *DisputeShowAction) loadParams() {
+	action.ID = action.GetInt64("id")
+}
+
+func (action *DisputeShowAction) loadRecord() {
+	action.Record, action.Err = action.HistoryQ().DisputeByID(action.ID)
+	if action.Err != nil {
+		return
+	}
+
+	if action.Record == nil {
+		action.Err = &problem.NotFound
+		return
+	}
+
+	action.Notes, action.Err = action.HistoryQ().DisputeNotes(action.ID)
+}
+
+func (action *DisputeShowAction) loadResource() {
+	action.Resource.Populate(action.Ctx, *action.Record)
+	action.Err = action.Resource.PopulateNotes(action.Notes)
+}
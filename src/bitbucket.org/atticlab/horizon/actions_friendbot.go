@@ -1,16 +1,29 @@
 package horizon
 
 import (
+	"database/sql"
+	"errors"
+	"net"
 	"net/http"
+	"strings"
 
+	"bitbucket.org/atticlab/go-smart-base/xdr"
+	"bitbucket.org/atticlab/horizon/db2/core"
+	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/friendbot"
 	"bitbucket.org/atticlab/horizon/render/hal"
 	"bitbucket.org/atticlab/horizon/render/problem"
 )
 
-// FriendbotAction causes an account at `Address` to be created.
+// FriendbotAction causes an account at `Address` to be created with
+// `AccountType` and/or funded with the asset.
 type FriendbotAction struct {
 	TransactionCreateAction
-	Address string
+	Address     string
+	AccountType *xdr.AccountType
+	AssetCode   string
+	AssetIssuer string
+	Request     friendbot.Request
 }
 
 // JSON is a method for actions.JSON
@@ -18,7 +31,9 @@ func (action *FriendbotAction) JSON() {
 
 	action.Do(
 		action.checkEnabled,
-		action.loadAddress,
+		action.loadParams,
+		action.loadRequest,
+		action.takeQuotas,
 		action.loadResult,
 		action.loadResource,
 
@@ -41,10 +56,141 @@ func (action *FriendbotAction) checkEnabled() {
 	}
 }
 
-func (action *FriendbotAction) loadAddress() {
+func (action *FriendbotAction) loadParams() {
 	action.Address = action.GetAddress("addr")
+	action.AccountType = action.GetOptionalAccountType("account_type")
+	action.AssetCode = action.GetString("asset_code")
+	action.AssetIssuer = action.GetOptionalAddress("asset_issuer")
+	if action.Err != nil {
+		return
+	}
+
+	if (action.AssetCode == "") != (action.AssetIssuer == "") {
+		action.SetInvalidField("asset_issuer", errors.New("asset_code and asset_issuer must be set together"))
+	}
+}
+
+// loadRequest decides whether account must be created and checks that
+// requested account type and asset are allowed
+func (action *FriendbotAction) loadRequest() {
+	bot := action.App.friendbot
+	action.Request = friendbot.Request{
+		Address:     action.Address,
+		AccountType: bot.DefaultAccountType(),
+		AssetCode:   action.AssetCode,
+		AssetIssuer: action.AssetIssuer,
+	}
+
+	var account core.Account
+	err := action.CoreQ().AccountByAddress(&account, action.Address)
+	switch {
+	case err == sql.ErrNoRows:
+		action.Request.CreateAccount = true
+	case err != nil:
+		action.Err = err
+		return
+	}
+
+	if action.AccountType != nil {
+		if !action.Request.CreateAccount {
+			action.SetInvalidField("account_type", errors.New("account already exists"))
+			return
+		}
+		if !bot.IsAllowedType(*action.AccountType) {
+			action.SetInvalidField("account_type", errors.New("friendbot is not allowed to create accounts of this type"))
+			return
+		}
+		action.Request.AccountType = *action.AccountType
+	}
+
+	if action.AssetCode == "" {
+		if !action.Request.CreateAccount {
+			action.SetInvalidField("addr", errors.New("account already exists, specify asset to fund"))
+		}
+		return
+	}
+
+	var asset history.Asset
+	err = action.HistoryQ().AssetByParams(&asset, int(assetTypeByCode(action.AssetCode)), action.AssetCode, action.AssetIssuer)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			action.SetInvalidField("asset_code", errors.New("asset does not exist"))
+			return
+		}
+		action.Err = err
+		return
+	}
+
+	if !asset.IsActive() {
+		action.SetInvalidField("asset_code", errors.New("asset is not active"))
+	}
+}
+
+// takeQuotas counts only valid requests, so mistyped requests do not exhaust
+// quotas of the client
+func (action *FriendbotAction) takeQuotas() {
+	ip := clientIP(action.peerAddr(), action.R.Header.Get("X-Forwarded-For"), action.App.config.Friendbot.TrustedProxies)
+	err := action.App.friendbot.TakeQuotas(ip, action.Address)
+	if err == friendbot.ErrQuotaExceeded {
+		exceeded := problem.RateLimitExceeded
+		exceeded.Detail = "Friendbot quota of your IP or the account is exhausted. Try again later."
+		action.Err = &exceeded
+		return
+	}
+	action.Err = err
 }
 
 func (action *FriendbotAction) loadResult() {
-	action.Result = action.App.friendbot.Pay(action.Ctx, action.Address)
+	action.Result = action.App.friendbot.Fund(action.Ctx, action.Request)
+}
+
+// peerAddr returns address of the peer the request was received from
+func (action *FriendbotAction) peerAddr() string {
+	if peer, ok := action.GojiCtx.Env[peerAddrEnvKey].(string); ok {
+		return peer
+	}
+	return action.R.RemoteAddr
+}
+
+// clientIP returns IP of the client counted by quota. X-Forwarded-For is used
+// only if request is received from trusted proxy: the rightmost address, which
+// is not a trusted proxy, is the client, as addresses on the left are set by
+// the client itself.
+func clientIP(peer, forwardedFor string, trusted []*net.IPNet) string {
+	ip := hostIP(peer)
+	if !isTrustedProxy(ip, trusted) || forwardedFor == "" {
+		return ip
+	}
+
+	forwarded := strings.Split(forwardedFor, ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		ip = hostIP(strings.TrimSpace(forwarded[i]))
+		if !isTrustedProxy(ip, trusted) {
+			return ip
+		}
+	}
+	return ip
+}
+
+// hostIP returns IP of the address without port
+func hostIP(addr string) string {
+	ip, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return ip
+}
+
+func isTrustedProxy(ip string, trusted []*net.IPNet) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+
+	for _, network := range trusted {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
 }
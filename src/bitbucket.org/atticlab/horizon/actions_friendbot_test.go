@@ -0,0 +1,85 @@
+package horizon
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"bitbucket.org/atticlab/go-smart-base/keypair"
+	"bitbucket.org/atticlab/horizon/friendbot"
+	"bitbucket.org/atticlab/horizon/test"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFriendbotActions(t *testing.T) {
+	test.LoadScenario("base")
+	app := NewTestApp()
+	defer app.Close()
+	rh := NewRequestHelper(app)
+
+	Convey("Friendbot Actions:", t, func() {
+		newAccount, err := keypair.Random()
+		So(err, ShouldBeNil)
+
+		Convey("GET /friendbot when disabled", func() {
+			w := rh.Get("/friendbot?addr="+newAccount.Address(), test.RequestHelperNoop)
+			So(w.Code, ShouldEqual, 403)
+		})
+
+		pool, err := friendbot.NewPool([]string{"SAQWC7EPIYF3XGILYVJM4LVAVSLZKT27CTEI3AFBHU2VRCMQ3P3INPG5"})
+		So(err, ShouldBeNil)
+		app.friendbot = &friendbot.Bot{
+			Submitter: app.submitter,
+			Network:   app.networkPassphrase,
+			Pool:      pool,
+		}
+		defer func() { app.friendbot = nil }()
+
+		Convey("account type is not allowed", func() {
+			w := rh.Get("/friendbot?account_type=2&addr="+newAccount.Address(), test.RequestHelperNoop)
+			So(w.Code, ShouldEqual, 400)
+		})
+
+		Convey("existing account without asset", func() {
+			w := rh.Get("/friendbot?addr=GAWIB7ETYGSWULO4VB7D6S42YLPGIC7TY7Y2SSJKVOTMQXV5TILYWBUA", test.RequestHelperNoop)
+			So(w.Code, ShouldEqual, 400)
+		})
+
+		Convey("unknown asset", func() {
+			w := rh.Get("/friendbot?asset_code=USD&asset_issuer=GAWIB7ETYGSWULO4VB7D6S42YLPGIC7TY7Y2SSJKVOTMQXV5TILYWBUA&addr="+newAccount.Address(), test.RequestHelperNoop)
+			So(w.Code, ShouldEqual, 400)
+		})
+
+		Convey("asset code without issuer", func() {
+			w := rh.Get("/friendbot?asset_code=AUAH&addr="+newAccount.Address(), test.RequestHelperNoop)
+			So(w.Code, ShouldEqual, 400)
+		})
+
+		Convey("quota is exceeded", func() {
+			app.friendbot.IPQuota = friendbot.NewMemoryQuota(0, time.Hour)
+			w := rh.Get("/friendbot?addr="+newAccount.Address(), test.RequestHelperNoop)
+			So(w.Code, ShouldEqual, 429)
+		})
+	})
+}
+
+func TestFriendbotClientIP(t *testing.T) {
+	Convey("clientIP", t, func() {
+		_, proxies, err := net.ParseCIDR("10.0.0.0/8")
+		So(err, ShouldBeNil)
+		trusted := []*net.IPNet{proxies}
+
+		Convey("ignores X-Forwarded-For without trusted proxies", func() {
+			So(clientIP("1.2.3.4:5000", "5.6.7.8", nil), ShouldEqual, "1.2.3.4")
+		})
+
+		Convey("ignores X-Forwarded-For of untrusted peer", func() {
+			So(clientIP("1.2.3.4:5000", "5.6.7.8", trusted), ShouldEqual, "1.2.3.4")
+		})
+
+		Convey("uses rightmost untrusted address behind trusted proxy", func() {
+			So(clientIP("10.0.0.1:5000", "9.9.9.9, 5.6.7.8, 10.0.0.2", trusted), ShouldEqual, "5.6.7.8")
+			So(clientIP("10.0.0.1:5000", "", trusted), ShouldEqual, "10.0.0.1")
+		})
+	})
+}
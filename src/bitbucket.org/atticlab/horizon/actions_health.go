@@ -0,0 +1,27 @@
+package horizon
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ServeLiveness reports that the process is up and serving requests. It does
+// not check dependencies, so orchestrator does not restart instance while
+// databases are unavailable.
+func (a *App) ServeLiveness(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(healthCheck{Status: healthStatusOK})
+}
+
+// ServeReadiness reports state of dependencies and ingestion lag. Responds with
+// 503 if any of the checks are failing, so instance is removed from load
+// balancing until it recovers.
+func (a *App) ServeReadiness(w http.ResponseWriter, r *http.Request) {
+	report := a.CheckReadiness()
+
+	w.Header().Set("Content-Type", "application/json")
+	if !report.Ready() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(report)
+}
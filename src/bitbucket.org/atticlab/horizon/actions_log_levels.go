@@ -0,0 +1,78 @@
+package horizon
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"bitbucket.org/atticlab/horizon/log"
+	"github.com/Sirupsen/logrus"
+)
+
+// logLevels is a representation of log levels served on admin port
+type logLevels struct {
+	Default  string            `json:"default"`
+	Services map[string]string `json:"services"`
+}
+
+// logLevelUpdate changes level of the service. Empty service changes default
+// level, empty level resets service to default level.
+type logLevelUpdate struct {
+	Service string `json:"service"`
+	Level   string `json:"level"`
+}
+
+// ServeLogLevels shows log levels on GET and changes level of the service on
+// PUT or POST. Served on admin port only.
+func (a *App) ServeLogLevels(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+	case "PUT", "POST":
+		var update logLevelUpdate
+		err := json.NewDecoder(r.Body).Decode(&update)
+		if err != nil {
+			http.Error(w, "invalid body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		err = applyLogLevelUpdate(log.DefaultLevels, update)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.WithField("service", update.Service).WithField("level", update.Level).Warn("Log level changed")
+	default:
+		w.Header().Set("Allow", "GET, PUT, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	levels := logLevels{
+		Default:  log.DefaultLevels.Default().String(),
+		Services: make(map[string]string),
+	}
+	for service, level := range log.DefaultLevels.Services() {
+		levels.Services[service] = level.String()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(levels)
+}
+
+func applyLogLevelUpdate(levels *log.Levels, update logLevelUpdate) error {
+	if update.Service != "" && update.Level == "" {
+		levels.Reset(update.Service)
+		return nil
+	}
+
+	level, err := logrus.ParseLevel(update.Level)
+	if err != nil {
+		return err
+	}
+
+	if update.Service == "" {
+		levels.SetDefault(level)
+	} else {
+		levels.Set(update.Service, level)
+	}
+	return nil
+}
@@ -13,8 +13,17 @@ type MetricsAction struct {
 	Snapshot map[string]interface{}
 }
 
+// metricsFormatPrometheus requests metrics in the Prometheus text exposition
+// format. Served on the public port only while admin server is disabled.
+const metricsFormatPrometheus = "prometheus"
+
 // JSON is a method for actions.JSON
 func (action *MetricsAction) JSON() {
+	if action.GetString("format") == metricsFormatPrometheus && action.App.config.AdminPort == 0 {
+		action.App.ServePrometheusMetrics(action.W, action.R)
+		return
+	}
+
 	action.App.UpdateMetrics(action.Ctx)
 	action.LoadSnapshot()
 	action.Snapshot["_links"] = map[string]interface{}{
@@ -0,0 +1,38 @@
+package horizon
+
+import (
+	"testing"
+
+	"bitbucket.org/atticlab/horizon/prometheus"
+	"bitbucket.org/atticlab/horizon/test"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMetricsActions(t *testing.T) {
+	test.LoadScenario("base")
+	app := NewTestApp()
+	defer app.Close()
+	rh := NewRequestHelper(app)
+
+	Convey("Metrics", t, func() {
+		Convey("json by default", func() {
+			w := rh.Get("/metrics", test.RequestHelperNoop)
+			So(w.Code, ShouldEqual, 200)
+			So(w.Header().Get("Content-Type"), ShouldNotEqual, prometheus.ContentType)
+		})
+		Convey("prometheus on the public port without admin server", func() {
+			w := rh.Get("/metrics?format=prometheus", test.RequestHelperNoop)
+			So(w.Code, ShouldEqual, 200)
+			So(w.Header().Get("Content-Type"), ShouldEqual, prometheus.ContentType)
+			So(w.Body.String(), ShouldContainSubstring, prometheus.Namespace)
+		})
+		Convey("prometheus is served by admin server only, if it's enabled", func() {
+			app.config.AdminPort = 8001
+			defer func() { app.config.AdminPort = 0 }()
+
+			w := rh.Get("/metrics?format=prometheus", test.RequestHelperNoop)
+			So(w.Code, ShouldEqual, 200)
+			So(w.Header().Get("Content-Type"), ShouldNotEqual, prometheus.ContentType)
+		})
+	})
+}
@@ -26,6 +26,7 @@ type OperationIndexAction struct {
 	TransactionFilter string
 	PagingParams      db2.PageQuery
 	CloseAtQuery      db2.CloseAtQuery
+	Filter            history.OperationsFilter
 	Records           []history.Operation
 	Page              hal.Page
 }
@@ -79,6 +80,7 @@ func (action *OperationIndexAction) loadParams() {
 	action.TransactionFilter = action.GetString("tx_id")
 	action.PagingParams = action.GetPageQuery()
 	action.CloseAtQuery = action.GetCloseAtQuery()
+	action.Filter = action.GetOperationsFilter()
 }
 
 func (action *OperationIndexAction) loadRecords() {
@@ -87,7 +89,7 @@ func (action *OperationIndexAction) loadRecords() {
 	}
 
 	q := action.HistoryQ()
-	ops := q.Operations()
+	ops := q.Operations().WithRefundedAmount()
 
 	switch {
 	case action.AccountFilter != "":
@@ -100,7 +102,7 @@ func (action *OperationIndexAction) loadRecords() {
 		ops.ForTransaction(action.TransactionFilter)
 	}
 
-	action.Err = ops.Page(action.PagingParams).ClosedAt(action.CloseAtQuery).Select(&action.Records)
+	action.Err = ops.Filter(action.Filter).Page(action.PagingParams).ClosedAt(action.CloseAtQuery).Select(&action.Records)
 }
 
 func (action *OperationIndexAction) loadPage() {
@@ -138,7 +140,7 @@ func (action *OperationShowAction) loadParams() {
 }
 
 func (action *OperationShowAction) loadRecord() {
-	action.Err = action.HistoryQ().OperationByID(&action.Record, action.ID)
+	action.Err = action.HistoryQ().OperationWithRefundsByID(&action.Record, action.ID)
 }
 
 func (action *OperationShowAction) loadResource() {
@@ -1,14 +1,13 @@
 package horizon
 
 import (
-	"bitbucket.org/atticlab/horizon/db2/history"
+	registry "bitbucket.org/atticlab/horizon/options"
 	"bitbucket.org/atticlab/horizon/render/hal"
 	"bitbucket.org/atticlab/horizon/resource/options"
 	"bitbucket.org/atticlab/horizon/txsub/transactions"
-	"bitbucket.org/atticlab/horizon/render/problem"
 )
 
-// OptionsAction renders options.
+// OptionsAction renders effective values of runtime-editable options.
 type OptionsAction struct {
 	Action
 	Response options.Options
@@ -25,20 +24,11 @@ func (action *OptionsAction) JSON() {
 }
 
 func (action *OptionsAction) loadRecord() {
-	rawMaxReversalDuration, err := action.HistoryQ().OptionsByName(history.OPTIONS_MAX_REVERSAL_DURATION)
-	if err != nil {
-		action.Log.WithError(err).Error("Failed to get max reversal duration")
-		action.Err = &problem.ServerError
-		return
-	}
+	action.Response.MaxReversalDuration.Populate(action.App.options.Duration(registry.MaxReversalDuration, transactions.MAX_REVERSE_TIME))
 
-	var maxReversalDuration history.MaxReversalDuration
-	if rawMaxReversalDuration != nil {
-		maxReversalDuration = history.MaxReversalDuration(*rawMaxReversalDuration)
-	} else {
-		maxReversalDuration = *history.NewMaxReversalDuration()
-		maxReversalDuration.SetMaxDuration(transactions.MAX_REVERSE_TIME)
+	values := action.App.options.Values()
+	action.Response.Values = make([]options.Option, len(values))
+	for i := range values {
+		action.Response.Values[i].Populate(values[i])
 	}
-
-	action.Response.MaxReversalDuration.Populate(maxReversalDuration)
 }
@@ -19,6 +19,7 @@ type PaymentsIndexAction struct {
 	TransactionFilter string
 	PagingParams      db2.PageQuery
 	CloseAtQuery      db2.CloseAtQuery
+	Filter            history.OperationsFilter
 	Records           []history.Operation
 	Page              hal.Page
 }
@@ -71,11 +72,12 @@ func (action *PaymentsIndexAction) loadParams() {
 	action.TransactionFilter = action.GetString("tx_id")
 	action.PagingParams = action.GetPageQuery()
 	action.CloseAtQuery = action.GetCloseAtQuery()
+	action.Filter = action.GetOperationsFilter()
 }
 
 func (action *PaymentsIndexAction) loadRecords() {
 	q := action.HistoryQ()
-	ops := q.Operations().OnlyPayments()
+	ops := q.Operations().OnlyPayments().WithRefundedAmount()
 
 	switch {
 	case action.AccountFilter != "":
@@ -88,7 +90,7 @@ func (action *PaymentsIndexAction) loadRecords() {
 		ops.ForTransaction(action.TransactionFilter)
 	}
 
-	action.Err = ops.Page(action.PagingParams).ClosedAt(action.CloseAtQuery).Select(&action.Records)
+	action.Err = ops.Filter(action.Filter).Page(action.PagingParams).ClosedAt(action.CloseAtQuery).Select(&action.Records)
 }
 
 func (action *PaymentsIndexAction) loadPage() {
@@ -0,0 +1,72 @@
+package horizon
+
+import (
+	"bitbucket.org/atticlab/horizon/db2"
+	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/render/hal"
+	"bitbucket.org/atticlab/horizon/resource"
+)
+
+// ReversalWindowIndexAction returns a paged slice of payment reversal windows.
+// Payments not matching any window use max_reversal_duration option.
+type ReversalWindowIndexAction struct {
+	Action
+	AssetCode         string
+	AssetIssuer       string
+	AccountTypeFilter *int32
+	PagingParams      db2.PageQuery
+	Records           []history.ReversalWindow
+	Page              hal.Page
+}
+
+// JSON is a method for actions.JSON
+func (action *ReversalWindowIndexAction) JSON() {
+	action.Do(
+		action.loadParams,
+		action.loadRecords,
+		action.loadPage,
+		func() {
+			hal.Render(action.W, action.Page)
+		},
+	)
+}
+
+func (action *ReversalWindowIndexAction) loadParams() {
+	action.ValidateCursorAsDefault()
+	action.AssetCode = action.GetString("asset_code")
+	action.AssetIssuer = action.GetOptionalAddress("asset_issuer")
+	action.AccountTypeFilter = action.GetInt32Pointer("account_type")
+	action.PagingParams = action.GetPageQuery()
+}
+
+func (action *ReversalWindowIndexAction) loadRecords() {
+	q := action.HistoryQ().ReversalWindows()
+	if action.AssetCode != "" {
+		q.ForAsset(action.AssetCode)
+	}
+
+	if action.AssetIssuer != "" {
+		q.ForAssetIssuer(action.AssetIssuer)
+	}
+
+	if action.AccountTypeFilter != nil {
+		q.ForAccountType(*action.AccountTypeFilter)
+	}
+
+	action.Err = q.Page(action.PagingParams).Select(&action.Records)
+}
+
+func (action *ReversalWindowIndexAction) loadPage() {
+	for _, record := range action.Records {
+		var res resource.ReversalWindow
+		res.Populate(record)
+		action.Page.Add(res)
+	}
+
+	action.Page.BaseURL = action.BaseURL()
+	action.Page.BasePath = action.Path()
+	action.Page.Limit = action.PagingParams.Limit
+	action.Page.Cursor = action.PagingParams.Cursor
+	action.Page.Order = action.PagingParams.Order
+	action.Page.PopulateLinks()
+}
@@ -24,6 +24,7 @@ func (action *RootAction) JSON() {
 		action.App.coreVersion,
 		action.App.networkPassphrase,
 	)
+	res.IngestionLeader = action.App.IngestionLeader()
 
 	hal.Render(action.W, res)
 }
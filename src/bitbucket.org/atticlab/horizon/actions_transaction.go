@@ -1,6 +1,9 @@
 package horizon
 
 import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"net/http"
 
 	"bitbucket.org/atticlab/horizon/db2"
@@ -23,12 +26,14 @@ import (
 // Allows to select operations by before & after filters. Time must be passed as 2006-01-02T15:04:05Z
 type TransactionIndexAction struct {
 	Action
-	LedgerFilter  int32
-	AccountFilter string
-	PagingParams  db2.PageQuery
-	CloseAtQuery  db2.CloseAtQuery
-	Records       []history.Transaction
-	Page          hal.Page
+	LedgerFilter   int32
+	AccountFilter  string
+	MemoFilter     string
+	MemoTypeFilter string
+	PagingParams   db2.PageQuery
+	CloseAtQuery   db2.CloseAtQuery
+	Records        []history.Transaction
+	Page           hal.Page
 }
 
 // JSON is a method for actions.JSON
@@ -65,8 +70,26 @@ func (action *TransactionIndexAction) loadParams() {
 	action.ValidateCursorAsDefault()
 	action.AccountFilter = action.GetString("account_id")
 	action.LedgerFilter = action.GetInt32("ledger_id")
+	action.MemoTypeFilter = action.GetString("memo_type")
+	action.MemoFilter = action.GetString("memo")
 	action.PagingParams = action.GetPageQuery()
 	action.CloseAtQuery = action.GetCloseAtQuery()
+	if action.Err != nil {
+		return
+	}
+
+	switch action.MemoTypeFilter {
+	case "", "text", "id":
+	case "hash", "return":
+		action.MemoFilter = normalizeHashMemo(action.MemoFilter)
+	default:
+		action.SetInvalidField("memo_type", errors.New("must be one of: text, id, hash, return"))
+		return
+	}
+
+	if action.MemoFilter == "" && action.MemoTypeFilter != "" {
+		action.SetInvalidField("memo", errors.New("must be set with memo_type"))
+	}
 }
 
 func (action *TransactionIndexAction) loadRecords() {
@@ -84,6 +107,10 @@ func (action *TransactionIndexAction) loadRecords() {
 		txs.ForLedger(action.LedgerFilter)
 	}
 
+	if action.MemoFilter != "" {
+		txs.ForMemo(action.MemoTypeFilter, action.MemoFilter)
+	}
+
 	action.Err = txs.Page(action.PagingParams).ClosedAt(action.CloseAtQuery).Select(&action.Records)
 }
 
@@ -106,6 +133,21 @@ func (action *TransactionIndexAction) loadPage() {
 	action.Page.PopulateLinks()
 }
 
+// normalizeHashMemo converts hex encoded hash memo into base64 encoding used to
+// store hash memos. Any other value is returned as is.
+func normalizeHashMemo(memo string) string {
+	if len(memo) != 64 {
+		return memo
+	}
+
+	rawHash, err := hex.DecodeString(memo)
+	if err != nil {
+		return memo
+	}
+
+	return base64.StdEncoding.EncodeToString(rawHash)
+}
+
 // TransactionShowAction renders a ledger found by its sequence number.
 type TransactionShowAction struct {
 	Action
@@ -189,6 +231,11 @@ func (action *TransactionCreateAction) loadResource() {
 		return
 	}
 
+	if action.Result.Err == results.ErrShuttingDown {
+		action.Err = &problem.ShuttingDown
+		return
+	}
+
 	switch err := action.Result.Err.(type) {
 	case *results.RestrictedTransactionError:
 		rcr := resource.TransactionResultCodes{}
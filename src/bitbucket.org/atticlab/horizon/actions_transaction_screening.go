@@ -0,0 +1,37 @@
+package horizon
+
+import (
+	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/render/hal"
+	"bitbucket.org/atticlab/horizon/resource"
+)
+
+// This file contains the actions:
+//
+// TransactionScreeningAction: results of counterparties screening of the transaction
+type TransactionScreeningAction struct {
+	Action
+	Hash     string
+	Records  []history.ScreeningResult
+	Resource resource.TransactionScreening
+}
+
+// JSON is a method for actions.JSON
+func (action *TransactionScreeningAction) JSON() {
+	action.Do(
+		action.loadParams,
+		action.loadRecords,
+		func() {
+			action.Resource.Populate(action.Ctx, action.Hash, action.Records)
+			hal.Render(action.W, action.Resource)
+		},
+	)
+}
+
+func (action *TransactionScreeningAction) loadParams() {
+	action.Hash = action.GetString("tx_id")
+}
+
+func (action *TransactionScreeningAction) loadRecords() {
+	action.Err = action.HistoryQ().ScreeningResultsByTransaction(&action.Records, action.Hash)
+}
@@ -0,0 +1,70 @@
+package admin
+
+import (
+	"time"
+
+	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/render/problem"
+	"github.com/go-errors/errors"
+)
+
+// AcknowledgeComplianceAlertAction marks compliance alert as reviewed
+type AcknowledgeComplianceAlertAction struct {
+	AdminAction
+	ID      int64
+	Comment string
+
+	stored *history.ComplianceAlert
+}
+
+func NewAcknowledgeComplianceAlertAction(adminAction AdminAction) *AcknowledgeComplianceAlertAction {
+	return &AcknowledgeComplianceAlertAction{
+		AdminAction: adminAction,
+	}
+}
+
+func (action *AcknowledgeComplianceAlertAction) Validate() {
+	action.loadParams()
+	if action.Err != nil {
+		return
+	}
+
+	var err error
+	action.stored, err = action.HistoryQ().ComplianceAlertByID(action.ID)
+	if err != nil {
+		action.Log.WithStack(err).WithError(err).Error("Failed to get compliance alert")
+		action.Err = &problem.ServerError
+		return
+	}
+
+	if action.stored == nil {
+		action.Err = &problem.NotFound
+		return
+	}
+
+	if action.stored.Acknowledged {
+		action.SetInvalidField("id", errors.New("alert is already acknowledged"))
+		return
+	}
+}
+
+func (action *AcknowledgeComplianceAlertAction) Apply() {
+	if action.Err != nil {
+		return
+	}
+
+	_, err := action.HistoryQ().ComplianceAlertAcknowledge(action.ID, action.Comment, time.Now())
+	if err != nil {
+		action.Log.WithError(err).Error("Failed to acknowledge compliance alert")
+		action.Err = &problem.ServerError
+		return
+	}
+}
+
+func (action *AcknowledgeComplianceAlertAction) loadParams() {
+	action.ID = action.GetInt64("id")
+	action.Comment = action.GetString("comment")
+	if action.Err == nil && action.ID <= 0 {
+		action.SetInvalidField("id", errors.New("must be positive"))
+	}
+}
@@ -0,0 +1,64 @@
+package admin
+
+import (
+	"testing"
+
+	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/render/problem"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestActionsAcknowledgeComplianceAlert(t *testing.T) {
+	Convey("Acknowledge compliance alert", t, func() {
+		historyQ := &history.QMock{}
+		Convey("Invalid id", func() {
+			action := NewAcknowledgeComplianceAlertAction(NewAdminAction(map[string]interface{}{
+				"id": "not_id",
+			}, historyQ))
+			action.Validate()
+			So(action.Err, ShouldBeInvalidField, "id")
+		})
+		Convey("Missing id", func() {
+			action := NewAcknowledgeComplianceAlertAction(NewAdminAction(map[string]interface{}{
+				"comment": "reviewed",
+			}, historyQ))
+			action.Validate()
+			So(action.Err, ShouldBeInvalidField, "id")
+		})
+		Convey("Not found", func() {
+			historyQ.On("ComplianceAlertByID", int64(10)).Return(nil, nil).Once()
+			action := NewAcknowledgeComplianceAlertAction(NewAdminAction(map[string]interface{}{
+				"id": 10,
+			}, historyQ))
+			action.Validate()
+			So(action.Err, ShouldEqual, &problem.NotFound)
+		})
+		Convey("Already acknowledged", func() {
+			historyQ.On("ComplianceAlertByID", int64(10)).Return(&history.ComplianceAlert{
+				ID:           10,
+				Acknowledged: true,
+			}, nil).Once()
+			action := NewAcknowledgeComplianceAlertAction(NewAdminAction(map[string]interface{}{
+				"id": 10,
+			}, historyQ))
+			action.Validate()
+			So(action.Err, ShouldBeInvalidField, "id")
+		})
+		Convey("Acknowledge", func() {
+			historyQ.On("ComplianceAlertByID", int64(10)).Return(&history.ComplianceAlert{
+				ID: 10,
+			}, nil).Once()
+			historyQ.On("ComplianceAlertAcknowledge", int64(10), "false positive", mock.AnythingOfType("time.Time")).Return(true, nil).Once()
+			action := NewAcknowledgeComplianceAlertAction(NewAdminAction(map[string]interface{}{
+				"id":      10,
+				"comment": "false positive",
+			}, historyQ))
+			action.Validate()
+			So(action.Err, ShouldBeNil)
+			action.Apply()
+			So(action.Err, ShouldBeNil)
+			historyQ.AssertExpectations(t)
+		})
+	})
+}
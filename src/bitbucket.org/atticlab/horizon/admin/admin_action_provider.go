@@ -5,6 +5,8 @@ import (
 	"bitbucket.org/atticlab/horizon/log"
 	"fmt"
 	"github.com/go-errors/errors"
+	"github.com/guregu/null"
+	"time"
 )
 
 type AdminActionProviderInterface interface {
@@ -12,8 +14,10 @@ type AdminActionProviderInterface interface {
 }
 
 type AdminActionProvider struct {
-	log      *log.Entry
-	historyQ history.QInterface
+	log         *log.Entry
+	historyQ    history.QInterface
+	operationID null.Int
+	closedAt    time.Time
 }
 
 func NewAdminActionProvider(historyQ history.QInterface) *AdminActionProvider {
@@ -23,6 +27,14 @@ func NewAdminActionProvider(historyQ history.QInterface) *AdminActionProvider {
 	}
 }
 
+// ForOperation sets id of the ingested administrative operation and close
+// time of its ledger, which are passed to created actions
+func (p *AdminActionProvider) ForOperation(id int64, closedAt time.Time) *AdminActionProvider {
+	p.operationID = null.IntFrom(id)
+	p.closedAt = closedAt
+	return p
+}
+
 func (p *AdminActionProvider) CreateNewParser(data map[string]interface{}) (AdminActionInterface, error) {
 	if len(data) > 1 {
 		return nil, errors.New("Only one operation per time can be processed")
@@ -33,6 +45,8 @@ func (p *AdminActionProvider) CreateNewParser(data map[string]interface{}) (Admi
 			return nil, err
 		}
 		adminAction := NewAdminAction(value, p.historyQ)
+		adminAction.OperationID = p.operationID
+		adminAction.ClosedAt = p.closedAt
 		switch AdminActionSubject(key) {
 		case SubjectCommission:
 			return NewSetCommissionAction(adminAction), nil
@@ -43,7 +57,19 @@ func (p *AdminActionProvider) CreateNewParser(data map[string]interface{}) (Admi
 		case SubjectAsset:
 			return NewManageAssetsAction(adminAction), nil
 		case SubjectMaxPaymentReversalDuration:
-			return NewManageMaxReversalDurationAction(adminAction), nil
+			return NewMaxReversalDurationAction(adminAction), nil
+		case SubjectScreeningList:
+			return NewManageScreeningListAction(adminAction), nil
+		case SubjectComplianceAlert:
+			return NewAcknowledgeComplianceAlertAction(adminAction), nil
+		case SubjectAccountFreeze:
+			return NewFreezeAccountAction(adminAction), nil
+		case SubjectOption:
+			return NewSetOptionAction(adminAction), nil
+		case SubjectReversalWindow:
+			return NewSetReversalWindowAction(adminAction), nil
+		case SubjectDispute:
+			return NewManageDisputeAction(adminAction), nil
 		default:
 			return nil, errors.New("unknown admin action")
 		}
@@ -0,0 +1,194 @@
+package admin
+
+import (
+	"database/sql"
+	"time"
+
+	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/render/problem"
+	"github.com/go-errors/errors"
+	"github.com/guregu/null"
+)
+
+// FreezeAccountAction temporary restricts payments of the account or lifts
+// previously created freeze. Freeze may be limited to single asset and
+// is lifted automatically after expiration time, if set.
+type FreezeAccountAction struct {
+	AdminAction
+	Address    string
+	AssetCode  string
+	BlockIn    bool
+	BlockOut   bool
+	ReasonCode history.FreezeReasonCode
+	Comment    string
+	ExpiresAt  *time.Time
+	// id of the freeze to be lifted
+	FreezeID int64
+	Remove   bool
+
+	freeze *history.AccountFreeze
+}
+
+func NewFreezeAccountAction(adminAction AdminAction) *FreezeAccountAction {
+	return &FreezeAccountAction{
+		AdminAction: adminAction,
+	}
+}
+
+func (action *FreezeAccountAction) Validate() {
+	action.loadParams()
+	if action.Err != nil {
+		return
+	}
+
+	var account history.Account
+	err := action.HistoryQ().AccountByAddress(&account, action.Address)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			action.Log.WithStack(err).WithError(err).Error("Failed to get account")
+			action.Err = &problem.ServerError
+			return
+		}
+		action.Err = &problem.NotFound
+		return
+	}
+
+	if !action.Remove {
+		return
+	}
+
+	action.freeze, err = action.HistoryQ().AccountFreezeByID(action.FreezeID)
+	if err != nil {
+		action.Log.WithStack(err).WithError(err).Error("Failed to get account freeze")
+		action.Err = &problem.ServerError
+		return
+	}
+
+	if action.freeze == nil || action.freeze.Address != action.Address {
+		action.Err = &problem.NotFound
+		return
+	}
+
+	if action.freeze.LiftedAt.Valid {
+		// operation lifted the freeze is reingested
+		liftedBy, err := action.HistoryQ().AccountFreezeLiftedBy(action.freeze.ID, action.OperationID)
+		if err != nil {
+			action.Log.WithStack(err).WithError(err).Error("Failed to check lift of account freeze")
+			action.Err = &problem.ServerError
+			return
+		}
+
+		if !liftedBy {
+			action.SetInvalidField("freeze_id", errors.New("freeze is already lifted"))
+			return
+		}
+	}
+}
+
+func (action *FreezeAccountAction) Apply() {
+	if action.Err != nil {
+		return
+	}
+
+	if action.Remove {
+		action.unfreeze()
+	} else {
+		action.doFreeze()
+	}
+}
+
+func (action *FreezeAccountAction) doFreeze() {
+	freeze := history.AccountFreeze{
+		Address:                action.Address,
+		AssetCode:              action.AssetCode,
+		BlockIncomingPayments:  action.BlockIn,
+		BlockOutcomingPayments: action.BlockOut,
+		ReasonCode:             action.ReasonCode,
+		Comment:                action.Comment,
+		ExpiresAt:              null.TimeFromPtr(action.ExpiresAt),
+		CreatedAt:              action.Now(),
+		OperationID:            action.OperationID,
+	}
+	err := action.HistoryQ().AccountFreezeInsert(&freeze)
+	if err != nil {
+		action.Log.WithError(err).Error("Failed to freeze account")
+		action.Err = &problem.ServerError
+		return
+	}
+
+	action.storeEvent(history.AccountTraitsEventFreeze, &freeze, action.Comment)
+}
+
+func (action *FreezeAccountAction) unfreeze() {
+	_, err := action.HistoryQ().AccountFreezeLift(action.FreezeID, action.Now())
+	if err != nil {
+		action.Log.WithError(err).Error("Failed to lift account freeze")
+		action.Err = &problem.ServerError
+		return
+	}
+
+	action.storeEvent(history.AccountTraitsEventUnfreeze, action.freeze, action.Comment)
+}
+
+func (action *FreezeAccountAction) storeEvent(eventType history.AccountTraitsEventType, freeze *history.AccountFreeze, comment string) {
+	err := storeTraitsEvent(action.HistoryQ(), eventType, freeze, comment, action.OperationID, action.Now())
+	if err != nil {
+		action.Log.WithError(err).Error("Failed to store traits history")
+		action.Err = &problem.ServerError
+		return
+	}
+}
+
+// storeTraitsEvent records creation or lifting of the freeze by the operation at close time of its ledger
+// in account's traits history
+func storeTraitsEvent(historyQ history.QInterface, eventType history.AccountTraitsEventType, freeze *history.AccountFreeze, comment string, operationID null.Int, at time.Time) error {
+	return historyQ.AccountTraitsEventInsert(&history.AccountTraitsEvent{
+		Address:                freeze.Address,
+		Event:                  eventType,
+		FreezeID:               null.IntFrom(freeze.ID),
+		AssetCode:              freeze.AssetCode,
+		BlockIncomingPayments:  freeze.BlockIncomingPayments,
+		BlockOutcomingPayments: freeze.BlockOutcomingPayments,
+		ReasonCode:             string(freeze.ReasonCode),
+		Comment:                comment,
+		ExpiresAt:              freeze.ExpiresAt,
+		CreatedAt:              at,
+		OperationID:            operationID,
+	})
+}
+
+func (action *FreezeAccountAction) loadParams() {
+	action.Address = action.GetAddress("account_id")
+	action.Comment = action.GetString("comment")
+	action.Remove = action.GetBool("remove")
+	if action.Remove {
+		action.FreezeID = action.GetInt64("freeze_id")
+		if action.Err == nil && action.FreezeID <= 0 {
+			action.SetInvalidField("freeze_id", errors.New("must be positive"))
+		}
+		return
+	}
+
+	action.AssetCode = action.GetString("asset_code")
+	if action.Err == nil && len(action.AssetCode) > 12 {
+		action.SetInvalidField("asset_code", errors.New("must not be longer than 12 characters"))
+		return
+	}
+
+	blockIn := action.GetOptionalBool("block_incoming_payments")
+	blockOut := action.GetOptionalBool("block_outcoming_payments")
+	action.BlockIn = blockIn != nil && *blockIn
+	action.BlockOut = blockOut != nil && *blockOut
+	if action.Err == nil && !action.BlockIn && !action.BlockOut {
+		action.SetInvalidField("block_incoming_payments", errors.New("incoming or outcoming payments must be frozen"))
+		return
+	}
+
+	action.ReasonCode = history.FreezeReasonCode(action.GetString("reason_code"))
+	if action.Err == nil && !action.ReasonCode.IsValid() {
+		action.SetInvalidField("reason_code", errors.New("unknown reason code"))
+		return
+	}
+
+	action.ExpiresAt = action.GetOptionalTime("expires_at")
+}
@@ -0,0 +1,191 @@
+package admin
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"bitbucket.org/atticlab/go-smart-base/keypair"
+	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/render/problem"
+	"github.com/guregu/null"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestActionsFreezeAccount(t *testing.T) {
+	account, err := keypair.Random()
+	assert.Nil(t, err)
+
+	closedAt := time.Date(2017, time.March, 11, 12, 0, 0, 0, time.UTC)
+	Convey("Freeze account", t, func() {
+		historyQ := &history.QMock{}
+		Convey("Invalid params", func() {
+			Convey("Nothing to freeze", func() {
+				action := NewFreezeAccountAction(NewAdminAction(map[string]interface{}{
+					"account_id":  account.Address(),
+					"reason_code": "sanctions",
+				}, historyQ))
+				action.Validate()
+				So(action.Err, ShouldBeInvalidField, "block_incoming_payments")
+			})
+			Convey("Unknown reason code", func() {
+				action := NewFreezeAccountAction(NewAdminAction(map[string]interface{}{
+					"account_id":              account.Address(),
+					"block_incoming_payments": true,
+					"reason_code":             "because",
+				}, historyQ))
+				action.Validate()
+				So(action.Err, ShouldBeInvalidField, "reason_code")
+			})
+			Convey("Invalid expiration time", func() {
+				action := NewFreezeAccountAction(NewAdminAction(map[string]interface{}{
+					"account_id":              account.Address(),
+					"block_incoming_payments": true,
+					"reason_code":             "sanctions",
+					"expires_at":              "tomorrow",
+				}, historyQ))
+				action.Validate()
+				So(action.Err, ShouldBeInvalidField, "expires_at")
+			})
+			Convey("Missing freeze id", func() {
+				action := NewFreezeAccountAction(NewAdminAction(map[string]interface{}{
+					"account_id": account.Address(),
+					"remove":     true,
+				}, historyQ))
+				action.Validate()
+				So(action.Err, ShouldBeInvalidField, "freeze_id")
+			})
+		})
+		Convey("Account does not exist", func() {
+			historyQ.On("AccountByAddress", account.Address()).Return(nil, sql.ErrNoRows).Once()
+			action := NewFreezeAccountAction(NewAdminAction(map[string]interface{}{
+				"account_id":              account.Address(),
+				"block_incoming_payments": true,
+				"reason_code":             "sanctions",
+			}, historyQ))
+			action.Validate()
+			So(action.Err, ShouldEqual, &problem.NotFound)
+		})
+		historyQ.On("AccountByAddress", account.Address()).Return(history.Account{Address: account.Address()}, nil)
+		Convey("Freeze", func() {
+			expiresAt := time.Date(2030, time.January, 2, 15, 4, 5, 0, time.UTC)
+			historyQ.On("AccountFreezeInsert", mock.AnythingOfType("*history.AccountFreeze")).Run(func(args mock.Arguments) {
+				freeze := args.Get(0).(*history.AccountFreeze)
+				So(freeze.AssetCode, ShouldEqual, "UAH")
+				So(freeze.BlockOutcomingPayments, ShouldBeTrue)
+				So(freeze.BlockIncomingPayments, ShouldBeFalse)
+				So(freeze.ExpiresAt, ShouldResemble, null.TimeFrom(expiresAt))
+				So(freeze.CreatedAt, ShouldEqual, closedAt)
+				freeze.ID = 5
+			}).Return(nil).Once()
+			historyQ.On("AccountTraitsEventInsert", &history.AccountTraitsEvent{
+				Address:                account.Address(),
+				Event:                  history.AccountTraitsEventFreeze,
+				FreezeID:               null.IntFrom(5),
+				AssetCode:              "UAH",
+				BlockOutcomingPayments: true,
+				ReasonCode:             "fraud_investigation",
+				Comment:                "case 42",
+				ExpiresAt:              null.TimeFrom(expiresAt),
+				CreatedAt:              closedAt,
+				OperationID:            null.IntFrom(100),
+			}).Return(nil).Once()
+			action := NewFreezeAccountAction(NewAdminAction(map[string]interface{}{
+				"account_id":               account.Address(),
+				"asset_code":               "UAH",
+				"block_outcoming_payments": true,
+				"reason_code":              "fraud_investigation",
+				"comment":                  "case 42",
+				"expires_at":               "2030-01-02T15:04:05Z",
+			}, historyQ))
+			action.OperationID = null.IntFrom(100)
+			action.ClosedAt = closedAt
+			action.Validate()
+			So(action.Err, ShouldBeNil)
+			action.Apply()
+			So(action.Err, ShouldBeNil)
+			historyQ.AssertExpectations(t)
+		})
+		Convey("Unfreeze", func() {
+			freeze := &history.AccountFreeze{
+				ID:                    5,
+				Address:               account.Address(),
+				BlockIncomingPayments: true,
+				ReasonCode:            history.FreezeReasonSanctions,
+			}
+			Convey("Freeze of other account", func() {
+				historyQ.On("AccountFreezeByID", int64(5)).Return(&history.AccountFreeze{ID: 5, Address: "other"}, nil).Once()
+				action := NewFreezeAccountAction(NewAdminAction(map[string]interface{}{
+					"account_id": account.Address(),
+					"freeze_id":  5,
+					"remove":     true,
+				}, historyQ))
+				action.Validate()
+				So(action.Err, ShouldEqual, &problem.NotFound)
+			})
+			Convey("Already lifted", func() {
+				freeze.LiftedAt = null.TimeFrom(time.Now())
+				historyQ.On("AccountFreezeByID", int64(5)).Return(freeze, nil).Once()
+				historyQ.On("AccountFreezeLiftedBy", int64(5), null.IntFrom(100)).Return(false, nil).Once()
+				action := NewFreezeAccountAction(NewAdminAction(map[string]interface{}{
+					"account_id": account.Address(),
+					"freeze_id":  5,
+					"remove":     true,
+				}, historyQ))
+				action.OperationID = null.IntFrom(100)
+				action.Validate()
+				So(action.Err, ShouldBeInvalidField, "freeze_id")
+			})
+			unfreezeEvent := &history.AccountTraitsEvent{
+				Address:               account.Address(),
+				Event:                 history.AccountTraitsEventUnfreeze,
+				FreezeID:              null.IntFrom(5),
+				BlockIncomingPayments: true,
+				ReasonCode:            "sanctions",
+				Comment:               "cleared",
+				CreatedAt:             closedAt,
+				OperationID:           null.IntFrom(100),
+			}
+			Convey("Reingested lift", func() {
+				freeze.LiftedAt = null.TimeFrom(closedAt)
+				historyQ.On("AccountFreezeByID", int64(5)).Return(freeze, nil).Once()
+				historyQ.On("AccountFreezeLiftedBy", int64(5), null.IntFrom(100)).Return(true, nil).Once()
+				historyQ.On("AccountFreezeLift", int64(5), closedAt).Return(false, nil).Once()
+				historyQ.On("AccountTraitsEventInsert", unfreezeEvent).Return(nil).Once()
+				action := NewFreezeAccountAction(NewAdminAction(map[string]interface{}{
+					"account_id": account.Address(),
+					"freeze_id":  5,
+					"remove":     true,
+					"comment":    "cleared",
+				}, historyQ))
+				action.OperationID = null.IntFrom(100)
+				action.ClosedAt = closedAt
+				action.Validate()
+				So(action.Err, ShouldBeNil)
+				action.Apply()
+				So(action.Err, ShouldBeNil)
+				historyQ.AssertExpectations(t)
+			})
+			Convey("Lifted", func() {
+				historyQ.On("AccountFreezeByID", int64(5)).Return(freeze, nil).Once()
+				historyQ.On("AccountFreezeLift", int64(5), closedAt).Return(true, nil).Once()
+				historyQ.On("AccountTraitsEventInsert", unfreezeEvent).Return(nil).Once()
+				action := NewFreezeAccountAction(NewAdminAction(map[string]interface{}{
+					"account_id": account.Address(),
+					"freeze_id":  5,
+					"remove":     true,
+					"comment":    "cleared",
+				}, historyQ))
+				action.OperationID = null.IntFrom(100)
+				action.ClosedAt = closedAt
+				action.Validate()
+				So(action.Err, ShouldBeNil)
+				action.Apply()
+				So(action.Err, ShouldBeNil)
+				historyQ.AssertExpectations(t)
+			})
+		})
+	})
+}
@@ -10,6 +10,12 @@ const (
 	SubjectAccountLimits              AdminActionSubject = "account_limits"
 	SubjectAsset                      AdminActionSubject = "asset"
 	SubjectMaxPaymentReversalDuration AdminActionSubject = "max_reversal_duration"
+	SubjectScreeningList              AdminActionSubject = "screening_list"
+	SubjectComplianceAlert            AdminActionSubject = "compliance_alert"
+	SubjectAccountFreeze              AdminActionSubject = "account_freeze"
+	SubjectOption                     AdminActionSubject = "option"
+	SubjectReversalWindow             AdminActionSubject = "reversal_window"
+	SubjectDispute                    AdminActionSubject = "dispute"
 )
 
 type InvalidFieldError struct {
@@ -1,12 +1,27 @@
 package admin
 
 import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"regexp"
+
 	"bitbucket.org/atticlab/go-smart-base/xdr"
 	"bitbucket.org/atticlab/horizon/db2/history"
 	"bitbucket.org/atticlab/horizon/render/problem"
-	"database/sql"
+	"github.com/go-errors/errors"
 )
 
+const (
+	maxAssetNameLength = 64
+	// amounts are stored with 7 decimal places
+	maxAssetDecimals = 7
+)
+
+var regulatoryFlagRegexp = regexp.MustCompile("^[a-z0-9_]{1,32}$")
+
+// ManageAssetsAction adds, updates or removes asset. Metadata and status are
+// changed only if set, new assets are active by default.
 type ManageAssetsAction struct {
 	AdminAction
 	asset       xdr.Asset
@@ -14,6 +29,14 @@ type ManageAssetsAction struct {
 	delete      bool
 	isAnonymous bool
 	storedAsset history.Asset
+
+	name            *string
+	decimals        *int32
+	description     *string
+	logoURL         *string
+	regulatoryFlags []string
+	hasFlags        bool
+	status          *string
 }
 
 func NewManageAssetsAction(adminAction AdminAction) *ManageAssetsAction {
@@ -23,7 +46,6 @@ func NewManageAssetsAction(adminAction AdminAction) *ManageAssetsAction {
 }
 
 func (action *ManageAssetsAction) Validate() {
-	action.Err = &problem.NotFound
 	action.loadParams()
 	if action.Err != nil {
 		return
@@ -56,6 +78,56 @@ func (action *ManageAssetsAction) Validate() {
 	action.storedAsset.Code = code
 	action.storedAsset.Issuer = issuer
 	action.storedAsset.IsAnonymous = action.isAnonymous
+
+	if action.isNew {
+		action.storedAsset.Decimals = history.DefaultAssetDecimals
+		action.storedAsset.Status = history.AssetStatusActive
+	}
+
+	action.applyMetadata()
+}
+
+func (action *ManageAssetsAction) applyMetadata() {
+	if action.name != nil {
+		action.storedAsset.Name = *action.name
+	}
+
+	if action.decimals != nil {
+		action.storedAsset.Decimals = int(*action.decimals)
+	}
+
+	if action.description != nil {
+		action.storedAsset.Description = *action.description
+	}
+
+	if action.logoURL != nil {
+		action.storedAsset.LogoURL = *action.logoURL
+	}
+
+	if action.hasFlags {
+		err := action.storedAsset.SetRegulatoryFlags(action.regulatoryFlags)
+		if err != nil {
+			action.Log.WithError(err).Error("Failed to set regulatory flags")
+			action.Err = &problem.ServerError
+			return
+		}
+	}
+
+	if action.status != nil {
+		status := history.AssetStatus(*action.status)
+		if !action.storedAsset.Status.CanMoveTo(status) {
+			action.SetInvalidField("status", fmt.Errorf("asset in status %s can not be moved to %s", action.storedAsset.Status, status))
+			return
+		}
+		action.storedAsset.Status = status
+	}
+}
+
+// ChangesAssets returns true if applied action changes assets, so horizon
+// instances must drop cached copies after the change is committed
+func ChangesAssets(action AdminActionInterface) bool {
+	_, ok := action.(*ManageAssetsAction)
+	return ok
 }
 
 func (action *ManageAssetsAction) Apply() {
@@ -80,4 +152,42 @@ func (action *ManageAssetsAction) loadParams() {
 	action.asset = action.GetAsset("")
 	action.delete = action.GetBool("delete")
 	action.isAnonymous = action.GetBool("is_anonymous")
+
+	action.name = action.GetOptionalString("name")
+	if action.name != nil && len(*action.name) > maxAssetNameLength {
+		action.SetInvalidField("name", fmt.Errorf("must not be longer than %d characters", maxAssetNameLength))
+		return
+	}
+
+	action.decimals = action.GetInt32Pointer("decimals")
+	if action.decimals != nil && (*action.decimals < 0 || *action.decimals > maxAssetDecimals) {
+		action.SetInvalidField("decimals", fmt.Errorf("must be between 0 and %d", maxAssetDecimals))
+		return
+	}
+
+	action.description = action.GetOptionalString("description")
+
+	action.logoURL = action.GetOptionalString("logo_url")
+	if action.logoURL != nil && *action.logoURL != "" {
+		logoURL, err := url.Parse(*action.logoURL)
+		if err != nil || (logoURL.Scheme != "http" && logoURL.Scheme != "https") || logoURL.Host == "" {
+			action.SetInvalidField("logo_url", errors.New("must be absolute http(s) url"))
+			return
+		}
+	}
+
+	_, action.hasFlags = action.rawData["regulatory_flags"]
+	action.regulatoryFlags = action.GetStringArray("regulatory_flags")
+	for _, flag := range action.regulatoryFlags {
+		if !regulatoryFlagRegexp.MatchString(flag) {
+			action.SetInvalidField("regulatory_flags", fmt.Errorf("invalid flag %q", flag))
+			return
+		}
+	}
+
+	action.status = action.GetOptionalString("status")
+	if action.status != nil && !history.AssetStatus(*action.status).IsValid() {
+		action.SetInvalidField("status", errors.New("unknown status"))
+		return
+	}
 }
@@ -53,6 +53,30 @@ func TestActionsManageAssets(t *testing.T) {
 			So(action.Err, ShouldNotBeNil)
 			So(action.Err, ShouldBeInvalidField, "is_anonymous")
 		})
+		Convey("Invalid status", func() {
+			assetData["status"] = "frozen"
+			action := NewManageAssetsAction(NewAdminAction(assetData, historyQ))
+			action.Validate()
+			So(action.Err, ShouldBeInvalidField, "status")
+		})
+		Convey("Invalid decimals", func() {
+			assetData["decimals"] = "8"
+			action := NewManageAssetsAction(NewAdminAction(assetData, historyQ))
+			action.Validate()
+			So(action.Err, ShouldBeInvalidField, "decimals")
+		})
+		Convey("Invalid logo url", func() {
+			assetData["logo_url"] = "ftp://example.com/logo.png"
+			action := NewManageAssetsAction(NewAdminAction(assetData, historyQ))
+			action.Validate()
+			So(action.Err, ShouldBeInvalidField, "logo_url")
+		})
+		Convey("Invalid regulatory flags", func() {
+			assetData["regulatory_flags"] = []string{"Requires KYC"}
+			action := NewManageAssetsAction(NewAdminAction(assetData, historyQ))
+			action.Validate()
+			So(action.Err, ShouldBeInvalidField, "regulatory_flags")
+		})
 		Convey("delete nonexistsing asset", func() {
 			assetData["delete"] = "true"
 			action := NewManageAssetsAction(NewAdminAction(assetData, historyQ))
@@ -71,6 +95,56 @@ func TestActionsManageAssets(t *testing.T) {
 			So(err, ShouldBeNil)
 			So(storedAsset.Id, ShouldNotEqual, 0)
 			So(storedAsset.IsAnonymous, ShouldEqual, false)
+			So(storedAsset.Status, ShouldEqual, history.AssetStatusActive)
+			So(storedAsset.Decimals, ShouldEqual, history.DefaultAssetDecimals)
+			Convey("update metadata", func() {
+				assetData["name"] = "US Dollar"
+				assetData["decimals"] = "4"
+				assetData["logo_url"] = "https://example.com/usd.png"
+				assetData["regulatory_flags"] = []string{"requires_kyc"}
+				action := NewManageAssetsAction(NewAdminAction(assetData, historyQ))
+				action.Validate()
+				So(action.Err, ShouldBeNil)
+				action.Apply()
+				So(action.Err, ShouldBeNil)
+
+				delete(assetData, "name")
+				delete(assetData, "decimals")
+				delete(assetData, "logo_url")
+				delete(assetData, "regulatory_flags")
+				assetData["description"] = "Dollar of the test bank"
+				action = NewManageAssetsAction(NewAdminAction(assetData, historyQ))
+				action.Validate()
+				So(action.Err, ShouldBeNil)
+				action.Apply()
+				So(action.Err, ShouldBeNil)
+
+				var storedAsset history.Asset
+				err := historyQ.AssetByParams(&storedAsset, int(assets.AssetTypeMap[assetData["asset_type"].(string)]),
+					assetData["asset_code"].(string), assetData["asset_issuer"].(string))
+				So(err, ShouldBeNil)
+				So(storedAsset.Name, ShouldEqual, "US Dollar")
+				So(storedAsset.Decimals, ShouldEqual, 4)
+				So(storedAsset.LogoURL, ShouldEqual, "https://example.com/usd.png")
+				So(storedAsset.Description, ShouldEqual, "Dollar of the test bank")
+				flags, err := storedAsset.RegulatoryFlags()
+				So(err, ShouldBeNil)
+				So(flags, ShouldResemble, []string{"requires_kyc"})
+			})
+			Convey("status lifecycle", func() {
+				setStatus := func(status history.AssetStatus) error {
+					assetData["status"] = string(status)
+					action := NewManageAssetsAction(NewAdminAction(assetData, historyQ))
+					action.Validate()
+					action.Apply()
+					return action.Err
+				}
+
+				So(setStatus(history.AssetStatusSuspended), ShouldBeNil)
+				So(setStatus(history.AssetStatusActive), ShouldBeNil)
+				So(setStatus(history.AssetStatusRetired), ShouldBeNil)
+				So(setStatus(history.AssetStatusActive), ShouldBeInvalidField, "status")
+			})
 			Convey("update", func() {
 				assetData["is_anonymous"] = "true"
 				action := NewManageAssetsAction(NewAdminAction(assetData, historyQ))
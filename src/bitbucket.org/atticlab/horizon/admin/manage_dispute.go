@@ -0,0 +1,292 @@
+package admin
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"bitbucket.org/atticlab/go-smart-base/amount"
+	"bitbucket.org/atticlab/go-smart-base/xdr"
+	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/db2/history/details"
+	"bitbucket.org/atticlab/horizon/render/problem"
+	"github.com/go-errors/errors"
+	"github.com/guregu/null"
+)
+
+// ManageDisputeAction opens dispute case against a payment or moves existing
+// case to the next state. Unless disabled, opening of the dispute freezes all
+// outgoing payments of the disputed asset from payment receiver, not only the
+// disputed amount, until the dispute is closed. Dispute is moved to reversed
+// state on ingestion of reversal or refund returning the disputed amount.
+// Only plain payments can be disputed, as path payments can not be reversed
+// or refunded.
+type ManageDisputeAction struct {
+	AdminAction
+	// id of existing dispute, zero if new dispute must be opened
+	DisputeID int64
+	PaymentID int64
+	// freeze all outgoing payments of the asset from payment receiver,
+	// applies to new dispute only
+	FreezeAsset bool
+	State       history.DisputeState
+	Note        string
+	Evidence    []string
+
+	dispute *history.Dispute
+}
+
+func NewManageDisputeAction(adminAction AdminAction) *ManageDisputeAction {
+	return &ManageDisputeAction{
+		AdminAction: adminAction,
+	}
+}
+
+func (action *ManageDisputeAction) Validate() {
+	action.loadParams()
+	if action.Err != nil {
+		return
+	}
+
+	if action.DisputeID == 0 {
+		action.validateOpen()
+	} else {
+		action.validateUpdate()
+	}
+}
+
+func (action *ManageDisputeAction) validateOpen() {
+	var operation history.Operation
+	err := action.HistoryQ().OperationByID(&operation, action.PaymentID)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			action.Log.WithStack(err).WithError(err).Error("Failed to get payment")
+			action.Err = &problem.ServerError
+			return
+		}
+		action.Err = &problem.NotFound
+		return
+	}
+
+	if operation.Type != xdr.OperationTypePayment {
+		// path payments can not be reversed or refunded, so dispute on them can not be resolved
+		action.SetInvalidField("payment_id", errors.New("operation is not a payment, only payments can be disputed"))
+		return
+	}
+
+	var payment details.Payment
+	err = operation.UnmarshalDetails(&payment)
+	if err != nil {
+		action.Log.WithStack(err).WithError(err).Error("Failed to get payment details")
+		action.Err = &problem.ServerError
+		return
+	}
+
+	active, err := action.HistoryQ().ActiveDisputeByPayment(action.PaymentID)
+	if err != nil {
+		action.Log.WithStack(err).WithError(err).Error("Failed to get active dispute")
+		action.Err = &problem.ServerError
+		return
+	}
+
+	if active != nil {
+		action.SetInvalidField("payment_id", fmt.Errorf("payment is already disputed in case %d", active.ID))
+		return
+	}
+
+	returned, err := action.HistoryQ().PaymentRefundedAmount(action.PaymentID)
+	if err != nil {
+		action.Log.WithStack(err).WithError(err).Error("Failed to get returned amount of the payment")
+		action.Err = &problem.ServerError
+		return
+	}
+
+	disputed := int64(amount.MustParse(payment.Amount)) - returned
+	if disputed <= 0 {
+		action.SetInvalidField("payment_id", errors.New("payment was already returned"))
+		return
+	}
+
+	action.dispute = &history.Dispute{
+		PaymentID:  action.PaymentID,
+		State:      history.DisputeStateOpen,
+		Claimant:   payment.From,
+		Respondent: payment.To,
+		AssetCode:  payment.Asset.Code,
+		Amount:     disputed,
+	}
+}
+
+func (action *ManageDisputeAction) validateUpdate() {
+	var err error
+	action.dispute, err = action.HistoryQ().DisputeByID(action.DisputeID)
+	if err != nil {
+		action.Log.WithStack(err).WithError(err).Error("Failed to get dispute")
+		action.Err = &problem.ServerError
+		return
+	}
+
+	if action.dispute == nil {
+		action.Err = &problem.NotFound
+		return
+	}
+
+	if action.State == "" {
+		if action.Note == "" && len(action.Evidence) == 0 {
+			action.SetInvalidField("note", errors.New("note, evidence or state must be set"))
+		}
+		return
+	}
+
+	if action.State == history.DisputeStateReversed {
+		action.SetInvalidField("state", errors.New("dispute is moved to reversed state on ingestion of reversal or refund"))
+		return
+	}
+
+	if !action.dispute.State.CanMoveTo(action.State) {
+		action.SetInvalidField("state", fmt.Errorf("dispute in state %s can not be moved to %s", action.dispute.State, action.State))
+		return
+	}
+}
+
+func (action *ManageDisputeAction) Apply() {
+	if action.Err != nil {
+		return
+	}
+
+	var err error
+	if action.DisputeID == 0 {
+		err = action.open()
+	} else {
+		err = action.update()
+	}
+
+	if err != nil {
+		action.Log.WithError(err).Error("Failed to manage dispute")
+		action.Err = &problem.ServerError
+		return
+	}
+}
+
+func (action *ManageDisputeAction) open() error {
+	if action.FreezeAsset {
+		freeze := history.AccountFreeze{
+			Address:                action.dispute.Respondent,
+			AssetCode:              action.dispute.AssetCode,
+			BlockOutcomingPayments: true,
+			ReasonCode:             history.FreezeReasonDispute,
+			Comment:                fmt.Sprintf("Dispute on payment %d", action.PaymentID),
+			CreatedAt:              action.Now(),
+			OperationID:            action.OperationID,
+		}
+		err := action.HistoryQ().AccountFreezeInsert(&freeze)
+		if err != nil {
+			return err
+		}
+
+		err = storeTraitsEvent(action.HistoryQ(), history.AccountTraitsEventFreeze, &freeze, freeze.Comment, action.OperationID, action.Now())
+		if err != nil {
+			return err
+		}
+		action.dispute.FreezeID = null.IntFrom(freeze.ID)
+	}
+
+	err := action.HistoryQ().DisputeInsert(action.dispute)
+	if err != nil {
+		return err
+	}
+
+	return action.storeNote(history.DisputeStateOpen)
+}
+
+func (action *ManageDisputeAction) update() error {
+	if action.State != "" {
+		action.dispute.State = action.State
+		_, err := action.HistoryQ().DisputeUpdate(action.dispute)
+		if err != nil {
+			return err
+		}
+
+		if !action.State.IsActive() {
+			err = UnlockDisputedFunds(action.HistoryQ(), action.dispute, action.Note, action.OperationID, action.Now())
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return action.storeNote(action.State)
+}
+
+func (action *ManageDisputeAction) storeNote(state history.DisputeState) error {
+	note := history.DisputeNote{
+		DisputeID: action.dispute.ID,
+		State:     state,
+		Note:      action.Note,
+	}
+	err := note.SetEvidence(action.Evidence)
+	if err != nil {
+		return err
+	}
+
+	return action.HistoryQ().DisputeNoteInsert(&note)
+}
+
+// UnlockDisputedFunds lifts freeze created on opening of the dispute, if any.
+// Operation is the administrative or returning operation closed the dispute,
+// `at` is the close time of its ledger.
+func UnlockDisputedFunds(historyQ history.QInterface, dispute *history.Dispute, comment string, operationID null.Int, at time.Time) error {
+	if !dispute.FreezeID.Valid {
+		return nil
+	}
+
+	freeze, err := historyQ.AccountFreezeByID(dispute.FreezeID.Int64)
+	if err != nil {
+		return err
+	}
+
+	// freeze might be already lifted by administrator
+	if freeze == nil || freeze.LiftedAt.Valid {
+		return nil
+	}
+
+	_, err = historyQ.AccountFreezeLift(freeze.ID, at)
+	if err != nil {
+		return err
+	}
+
+	if comment == "" {
+		comment = fmt.Sprintf("Dispute %d is %s", dispute.ID, dispute.State)
+	}
+	return storeTraitsEvent(historyQ, history.AccountTraitsEventUnfreeze, freeze, comment, operationID, at)
+}
+
+func (action *ManageDisputeAction) loadParams() {
+	action.DisputeID = action.GetInt64("dispute_id")
+	action.Note = action.GetString("note")
+	action.Evidence = action.GetStringArray("evidence")
+	if action.Err != nil {
+		return
+	}
+
+	if action.DisputeID < 0 {
+		action.SetInvalidField("dispute_id", errors.New("must be positive"))
+		return
+	}
+
+	if action.DisputeID == 0 {
+		action.PaymentID = action.GetInt64("payment_id")
+		freezeAsset := action.GetOptionalBool("freeze_asset")
+		action.FreezeAsset = freezeAsset == nil || *freezeAsset
+		if action.Err == nil && action.PaymentID <= 0 {
+			action.SetInvalidField("payment_id", errors.New("must be positive"))
+		}
+		return
+	}
+
+	action.State = history.DisputeState(action.GetString("state"))
+	if action.Err == nil && action.State != "" && !action.State.IsValid() {
+		action.SetInvalidField("state", errors.New("unknown state"))
+		return
+	}
+}
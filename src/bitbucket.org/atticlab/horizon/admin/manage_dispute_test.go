@@ -0,0 +1,198 @@
+package admin
+
+import (
+	"encoding/json"
+	"testing"
+
+	"bitbucket.org/atticlab/go-smart-base/keypair"
+	"bitbucket.org/atticlab/go-smart-base/xdr"
+	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/db2/history/details"
+	"bitbucket.org/atticlab/horizon/render/problem"
+	"github.com/guregu/null"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestActionsManageDispute(t *testing.T) {
+	sender, err := keypair.Random()
+	assert.Nil(t, err)
+	merchant, err := keypair.Random()
+	assert.Nil(t, err)
+
+	paymentID := int64(12884905985)
+	paymentDetails, err := json.Marshal(details.Payment{
+		From:   sender.Address(),
+		To:     merchant.Address(),
+		Amount: "100.0000000",
+		Asset: details.Asset{
+			Type: "credit_alphanum4",
+			Code: "UAH",
+		},
+	})
+	assert.Nil(t, err)
+	payment := history.Operation{
+		Type:          xdr.OperationTypePayment,
+		SourceAccount: sender.Address(),
+		DetailsString: null.StringFrom(string(paymentDetails)),
+	}
+
+	Convey("Manage dispute", t, func() {
+		historyQ := &history.QMock{}
+		Convey("Invalid state", func() {
+			action := NewManageDisputeAction(NewAdminAction(map[string]interface{}{
+				"dispute_id": 1,
+				"state":      "closed",
+			}, historyQ))
+			action.Validate()
+			So(action.Err, ShouldBeInvalidField, "state")
+		})
+		Convey("Missing payment", func() {
+			action := NewManageDisputeAction(NewAdminAction(map[string]interface{}{
+				"note": "Goods were not delivered",
+			}, historyQ))
+			action.Validate()
+			So(action.Err, ShouldBeInvalidField, "payment_id")
+		})
+		Convey("Open", func() {
+			historyQ.On("OperationByID", mock.Anything, paymentID).Run(func(args mock.Arguments) {
+				*args.Get(0).(*history.Operation) = payment
+			}).Return(nil).Once()
+			Convey("Payment is already disputed", func() {
+				historyQ.On("ActiveDisputeByPayment", paymentID).Return(&history.Dispute{ID: 3}, nil).Once()
+				action := NewManageDisputeAction(NewAdminAction(map[string]interface{}{
+					"payment_id": paymentID,
+				}, historyQ))
+				action.Validate()
+				So(action.Err, ShouldBeInvalidField, "payment_id")
+			})
+			Convey("Path payment", func() {
+				pathPayment := payment
+				pathPayment.Type = xdr.OperationTypePathPayment
+				historyQ.On("OperationByID", mock.Anything, int64(12884905986)).Run(func(args mock.Arguments) {
+					*args.Get(0).(*history.Operation) = pathPayment
+				}).Return(nil).Once()
+				action := NewManageDisputeAction(NewAdminAction(map[string]interface{}{
+					"payment_id": 12884905986,
+				}, historyQ))
+				action.Validate()
+				So(action.Err, ShouldBeInvalidField, "payment_id")
+			})
+			Convey("Without freeze", func() {
+				historyQ.On("ActiveDisputeByPayment", paymentID).Return(nil, nil).Once()
+				historyQ.On("PaymentRefundedAmount", paymentID).Return(int64(0), nil).Once()
+				historyQ.On("DisputeInsert", mock.AnythingOfType("*history.Dispute")).Run(func(args mock.Arguments) {
+					dispute := args.Get(0).(*history.Dispute)
+					So(dispute.Amount, ShouldEqual, 1000000000)
+					So(dispute.FreezeID.Valid, ShouldBeFalse)
+				}).Return(nil).Once()
+				historyQ.On("DisputeNoteInsert", mock.AnythingOfType("*history.DisputeNote")).Return(nil).Once()
+				action := NewManageDisputeAction(NewAdminAction(map[string]interface{}{
+					"payment_id":   paymentID,
+					"freeze_asset": false,
+				}, historyQ))
+				action.Validate()
+				So(action.Err, ShouldBeNil)
+				action.Apply()
+				So(action.Err, ShouldBeNil)
+				historyQ.AssertExpectations(t)
+			})
+			Convey("Asset is frozen", func() {
+				historyQ.On("ActiveDisputeByPayment", paymentID).Return(nil, nil).Once()
+				historyQ.On("PaymentRefundedAmount", paymentID).Return(int64(400000000), nil).Once()
+				historyQ.On("AccountFreezeInsert", mock.AnythingOfType("*history.AccountFreeze")).Run(func(args mock.Arguments) {
+					freeze := args.Get(0).(*history.AccountFreeze)
+					So(freeze.Address, ShouldEqual, merchant.Address())
+					So(freeze.AssetCode, ShouldEqual, "UAH")
+					So(freeze.BlockOutcomingPayments, ShouldBeTrue)
+					So(freeze.ReasonCode, ShouldEqual, history.FreezeReasonDispute)
+					freeze.ID = 7
+				}).Return(nil).Once()
+				historyQ.On("AccountTraitsEventInsert", mock.AnythingOfType("*history.AccountTraitsEvent")).Return(nil).Once()
+				historyQ.On("DisputeInsert", mock.AnythingOfType("*history.Dispute")).Run(func(args mock.Arguments) {
+					dispute := args.Get(0).(*history.Dispute)
+					So(dispute.State, ShouldEqual, history.DisputeStateOpen)
+					So(dispute.Claimant, ShouldEqual, sender.Address())
+					So(dispute.Respondent, ShouldEqual, merchant.Address())
+					So(dispute.Amount, ShouldEqual, 600000000)
+					So(dispute.FreezeID, ShouldResemble, null.IntFrom(7))
+					dispute.ID = 10
+				}).Return(nil).Once()
+				historyQ.On("DisputeNoteInsert", &history.DisputeNote{
+					DisputeID:      10,
+					State:          history.DisputeStateOpen,
+					Note:           "Goods were not delivered",
+					EvidenceString: `["ticket-42"]`,
+				}).Return(nil).Once()
+				action := NewManageDisputeAction(NewAdminAction(map[string]interface{}{
+					"payment_id": paymentID,
+					"note":       "Goods were not delivered",
+					"evidence":   []interface{}{"ticket-42"},
+				}, historyQ))
+				action.Validate()
+				So(action.Err, ShouldBeNil)
+				action.Apply()
+				So(action.Err, ShouldBeNil)
+				historyQ.AssertExpectations(t)
+			})
+		})
+		Convey("Update", func() {
+			dispute := history.Dispute{
+				ID:       10,
+				State:    history.DisputeStateMerchantResponded,
+				FreezeID: null.IntFrom(7),
+			}
+			Convey("Not found", func() {
+				historyQ.On("DisputeByID", int64(11)).Return(nil, nil).Once()
+				action := NewManageDisputeAction(NewAdminAction(map[string]interface{}{
+					"dispute_id": 11,
+					"state":      "resolved",
+				}, historyQ))
+				action.Validate()
+				So(action.Err, ShouldEqual, &problem.NotFound)
+			})
+			Convey("Invalid transition", func() {
+				historyQ.On("DisputeByID", int64(10)).Return(&dispute, nil).Once()
+				action := NewManageDisputeAction(NewAdminAction(map[string]interface{}{
+					"dispute_id": 10,
+					"state":      "open",
+				}, historyQ))
+				action.Validate()
+				So(action.Err, ShouldBeInvalidField, "state")
+			})
+			Convey("Reversed state is set on ingestion", func() {
+				historyQ.On("DisputeByID", int64(10)).Return(&dispute, nil).Once()
+				action := NewManageDisputeAction(NewAdminAction(map[string]interface{}{
+					"dispute_id": 10,
+					"state":      "reversed",
+				}, historyQ))
+				action.Validate()
+				So(action.Err, ShouldBeInvalidField, "state")
+			})
+			Convey("Resolved unlocks funds", func() {
+				historyQ.On("DisputeByID", int64(10)).Return(&dispute, nil).Once()
+				historyQ.On("DisputeUpdate", mock.AnythingOfType("*history.Dispute")).Run(func(args mock.Arguments) {
+					So(args.Get(0).(*history.Dispute).State, ShouldEqual, history.DisputeStateResolved)
+				}).Return(true, nil).Once()
+				historyQ.On("AccountFreezeByID", int64(7)).Return(&history.AccountFreeze{
+					ID:      7,
+					Address: merchant.Address(),
+				}, nil).Once()
+				historyQ.On("AccountFreezeLift", int64(7), mock.AnythingOfType("time.Time")).Return(true, nil).Once()
+				historyQ.On("AccountTraitsEventInsert", mock.AnythingOfType("*history.AccountTraitsEvent")).Return(nil).Once()
+				historyQ.On("DisputeNoteInsert", mock.AnythingOfType("*history.DisputeNote")).Return(nil).Once()
+				action := NewManageDisputeAction(NewAdminAction(map[string]interface{}{
+					"dispute_id": 10,
+					"state":      "resolved",
+					"note":       "Goods were delivered",
+				}, historyQ))
+				action.Validate()
+				So(action.Err, ShouldBeNil)
+				action.Apply()
+				So(action.Err, ShouldBeNil)
+				historyQ.AssertExpectations(t)
+			})
+		})
+	})
+}
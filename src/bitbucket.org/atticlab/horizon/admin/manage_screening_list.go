@@ -0,0 +1,76 @@
+package admin
+
+import (
+	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/render/problem"
+)
+
+// ManageScreeningListAction adds account to or removes account from the list
+// counterparties of payments are screened against
+type ManageScreeningListAction struct {
+	AdminAction
+	Address string
+	Reason  string
+	Remove  bool
+
+	stored *history.ScreeningListEntry
+}
+
+func NewManageScreeningListAction(adminAction AdminAction) *ManageScreeningListAction {
+	return &ManageScreeningListAction{
+		AdminAction: adminAction,
+	}
+}
+
+func (action *ManageScreeningListAction) Validate() {
+	action.loadParams()
+	if action.Err != nil {
+		return
+	}
+
+	var err error
+	action.stored, err = action.HistoryQ().ScreeningListEntryByAddress(action.Address)
+	if err != nil {
+		action.Log.WithStack(err).WithError(err).Error("Failed to get screening list entry")
+		action.Err = &problem.ServerError
+		return
+	}
+
+	if action.Remove && action.stored == nil {
+		action.Err = &problem.NotFound
+		return
+	}
+}
+
+func (action *ManageScreeningListAction) Apply() {
+	if action.Err != nil {
+		return
+	}
+
+	var err error
+	if action.Remove {
+		_, err = action.HistoryQ().ScreeningListDelete(action.Address)
+	} else {
+		entry := history.ScreeningListEntry{
+			Address: action.Address,
+			Reason:  action.Reason,
+		}
+		if action.stored != nil {
+			_, err = action.HistoryQ().ScreeningListUpdate(&entry)
+		} else {
+			err = action.HistoryQ().ScreeningListInsert(&entry)
+		}
+	}
+
+	if err != nil {
+		action.Log.WithError(err).Error("Failed to manage screening list")
+		action.Err = &problem.ServerError
+		return
+	}
+}
+
+func (action *ManageScreeningListAction) loadParams() {
+	action.Address = action.GetAddress("account_id")
+	action.Reason = action.GetString("reason")
+	action.Remove = action.GetBool("remove")
+}
@@ -0,0 +1,95 @@
+package admin
+
+import (
+	"testing"
+
+	"bitbucket.org/atticlab/go-smart-base/keypair"
+	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/render/problem"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionsManageScreeningList(t *testing.T) {
+	account, err := keypair.Random()
+	assert.Nil(t, err)
+
+	Convey("Manage screening list", t, func() {
+		historyQ := &history.QMock{}
+		Convey("Invalid account", func() {
+			action := NewManageScreeningListAction(NewAdminAction(map[string]interface{}{
+				"account_id": "invalid_id",
+			}, historyQ))
+			action.Validate()
+			So(action.Err, ShouldBeInvalidField, "account_id")
+		})
+		Convey("Invalid remove", func() {
+			action := NewManageScreeningListAction(NewAdminAction(map[string]interface{}{
+				"account_id": account.Address(),
+				"remove":     "not_bool",
+			}, historyQ))
+			action.Validate()
+			So(action.Err, ShouldBeInvalidField, "remove")
+		})
+		Convey("Remove not listed", func() {
+			historyQ.On("ScreeningListEntryByAddress", account.Address()).Return(nil, nil).Once()
+			action := NewManageScreeningListAction(NewAdminAction(map[string]interface{}{
+				"account_id": account.Address(),
+				"remove":     true,
+			}, historyQ))
+			action.Validate()
+			So(action.Err, ShouldEqual, &problem.NotFound)
+		})
+		Convey("Add", func() {
+			historyQ.On("ScreeningListEntryByAddress", account.Address()).Return(nil, nil).Once()
+			entry := &history.ScreeningListEntry{
+				Address: account.Address(),
+				Reason:  "sanctions",
+			}
+			historyQ.On("ScreeningListInsert", entry).Return(nil).Once()
+			action := NewManageScreeningListAction(NewAdminAction(map[string]interface{}{
+				"account_id": account.Address(),
+				"reason":     "sanctions",
+			}, historyQ))
+			action.Validate()
+			So(action.Err, ShouldBeNil)
+			action.Apply()
+			So(action.Err, ShouldBeNil)
+			historyQ.AssertExpectations(t)
+		})
+		Convey("Update", func() {
+			entry := &history.ScreeningListEntry{
+				Address: account.Address(),
+				Reason:  "watch list",
+			}
+			historyQ.On("ScreeningListEntryByAddress", account.Address()).Return(&history.ScreeningListEntry{
+				Address: account.Address(),
+			}, nil).Once()
+			historyQ.On("ScreeningListUpdate", entry).Return(true, nil).Once()
+			action := NewManageScreeningListAction(NewAdminAction(map[string]interface{}{
+				"account_id": account.Address(),
+				"reason":     "watch list",
+			}, historyQ))
+			action.Validate()
+			So(action.Err, ShouldBeNil)
+			action.Apply()
+			So(action.Err, ShouldBeNil)
+			historyQ.AssertExpectations(t)
+		})
+		Convey("Remove", func() {
+			historyQ.On("ScreeningListEntryByAddress", account.Address()).Return(&history.ScreeningListEntry{
+				Address: account.Address(),
+			}, nil).Once()
+			historyQ.On("ScreeningListDelete", account.Address()).Return(true, nil).Once()
+			action := NewManageScreeningListAction(NewAdminAction(map[string]interface{}{
+				"account_id": account.Address(),
+				"remove":     true,
+			}, historyQ))
+			action.Validate()
+			So(action.Err, ShouldBeNil)
+			action.Apply()
+			So(action.Err, ShouldBeNil)
+			historyQ.AssertExpectations(t)
+		})
+	})
+}
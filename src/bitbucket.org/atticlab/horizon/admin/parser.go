@@ -5,7 +5,9 @@ import (
 	"bitbucket.org/atticlab/horizon/db2/history"
 	"bitbucket.org/atticlab/horizon/helpers"
 	"bitbucket.org/atticlab/horizon/log"
+	"github.com/guregu/null"
 	"github.com/spf13/cast"
+	"time"
 )
 
 type AdminActionInterface interface {
@@ -21,6 +23,12 @@ type AdminAction struct {
 	Err     error
 	rawData map[string]interface{}
 	Log     *log.Entry
+	// OperationID is the id of the ingested administrative operation. Not set,
+	// when action is validated on submission.
+	OperationID null.Int
+	// ClosedAt is the close time of the ledger of the ingested administrative
+	// operation. Zero, when action is validated on submission.
+	ClosedAt time.Time
 
 	hq history.QInterface
 }
@@ -33,6 +41,15 @@ func (action *AdminAction) GetError() error {
 	return action.Err
 }
 
+// Now returns close time of the ledger of the ingested operation, so changes
+// are timestamped the same way on reingestion. Returns current time on submission.
+func (action *AdminAction) Now() time.Time {
+	if action.ClosedAt.IsZero() {
+		return time.Now()
+	}
+	return action.ClosedAt
+}
+
 func NewAdminAction(data map[string]interface{}, hq history.QInterface) AdminAction {
 	return AdminAction{
 		rawData: data,
@@ -97,6 +114,45 @@ func (p *AdminAction) GetOptionalAsset(prefix string) *xdr.Asset {
 	return helpers.GetOptionalAsset(p, prefix)
 }
 
+func (p *AdminAction) GetOptionalTime(name string) *time.Time {
+	return helpers.GetOptionalTime(p, name)
+}
+
 func (p *AdminAction) GetOptionalAmount(name string) int64 {
 	return int64(helpers.GetOptionalAmount(p, name))
 }
+
+func (p *AdminAction) GetStringArray(name string) []string {
+	if p.Err != nil {
+		return nil
+	}
+	value, ok := p.rawData[name]
+	if !ok {
+		return nil
+	}
+	result, err := cast.ToStringSliceE(value)
+	if err != nil {
+		p.SetInvalidField(name, err)
+		return nil
+	}
+	return result
+}
+
+// GetOptionalString returns nil, if field is not set
+func (p *AdminAction) GetOptionalString(name string) *string {
+	if p.Err != nil {
+		return nil
+	}
+	if _, ok := p.rawData[name]; !ok {
+		return nil
+	}
+	result := p.GetString(name)
+	if p.Err != nil {
+		return nil
+	}
+	return &result
+}
+
+func (p *AdminAction) GetInt32Pointer(name string) *int32 {
+	return helpers.GetInt32Pointer(p, name)
+}
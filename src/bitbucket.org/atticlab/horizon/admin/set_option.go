@@ -0,0 +1,121 @@
+package admin
+
+import (
+	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/options"
+	"bitbucket.org/atticlab/horizon/render/problem"
+	"github.com/go-errors/errors"
+	"strconv"
+)
+
+// SetOptionAction stores value of the system option or removes stored value,
+// so the configured one is used
+type SetOptionAction struct {
+	AdminAction
+	Name   string
+	Value  int64
+	Remove bool
+
+	definition options.Definition
+}
+
+func NewSetOptionAction(adminAction AdminAction) *SetOptionAction {
+	return &SetOptionAction{
+		AdminAction: adminAction,
+	}
+}
+
+// NewMaxReversalDurationAction converts legacy max payment reversal duration
+// action, storing number of seconds, to the SetOptionAction
+func NewMaxReversalDurationAction(adminAction AdminAction) *SetOptionAction {
+	seconds := adminAction.GetInt64("max_reversal_duration")
+	if adminAction.Err == nil {
+		adminAction.rawData = map[string]interface{}{
+			"name":  options.MaxReversalDuration,
+			"value": strconv.FormatInt(seconds, 10),
+		}
+	}
+	return NewSetOptionAction(adminAction)
+}
+
+// ChangesOptions returns true if applied action changes system options,
+// so horizon instances must reload them
+func ChangesOptions(action AdminActionInterface) bool {
+	switch action.(type) {
+	case *SetOptionAction:
+		return true
+	}
+	return false
+}
+
+func (action *SetOptionAction) Validate() {
+	action.loadParams()
+}
+
+func (action *SetOptionAction) Apply() {
+	if action.Err != nil {
+		return
+	}
+
+	var err error
+	if action.Remove {
+		_, err = action.HistoryQ().OptionsDelete(action.Name)
+	} else {
+		err = action.store()
+	}
+
+	if err != nil {
+		action.Log.WithField("option", action.Name).WithError(err).Error("Failed to set option")
+		action.Err = &problem.ServerError
+		return
+	}
+}
+
+func (action *SetOptionAction) store() error {
+	stored, err := action.HistoryQ().OptionsByName(action.Name)
+	if err != nil {
+		return err
+	}
+
+	option := history.Options{
+		Name: action.Name,
+		Data: action.definition.Format(action.Value),
+	}
+	if stored != nil {
+		_, err = action.HistoryQ().OptionsUpdate(&option)
+		return err
+	}
+	return action.HistoryQ().OptionsInsert(&option)
+}
+
+func (action *SetOptionAction) loadParams() {
+	action.Name = action.GetString("name")
+	remove := action.GetOptionalBool("remove")
+	action.Remove = remove != nil && *remove
+	if action.Err != nil {
+		return
+	}
+
+	var ok bool
+	action.definition, ok = options.Find(action.Name)
+	if !ok {
+		action.SetInvalidField("name", errors.New("unknown option"))
+		return
+	}
+
+	if action.Remove {
+		return
+	}
+
+	rawValue := action.GetString("value")
+	if action.Err != nil {
+		return
+	}
+
+	var err error
+	action.Value, err = action.definition.Parse(rawValue)
+	if err != nil {
+		action.SetInvalidField("value", err)
+		return
+	}
+}
@@ -0,0 +1,105 @@
+package admin
+
+import (
+	"testing"
+
+	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/options"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestActionsSetOption(t *testing.T) {
+	Convey("Set option", t, func() {
+		historyQ := &history.QMock{}
+		Convey("Unknown option", func() {
+			action := NewSetOptionAction(NewAdminAction(map[string]interface{}{
+				"name":  "unknown",
+				"value": "10",
+			}, historyQ))
+			action.Validate()
+			So(action.Err, ShouldBeInvalidField, "name")
+		})
+		Convey("Invalid value", func() {
+			action := NewSetOptionAction(NewAdminAction(map[string]interface{}{
+				"name":  options.StatisticsTimeout,
+				"value": "0",
+			}, historyQ))
+			action.Validate()
+			So(action.Err, ShouldBeInvalidField, "value")
+		})
+		Convey("Insert", func() {
+			option := history.Options{
+				Name: options.AnonymousMaxBalance,
+				Data: "15000.0000000",
+			}
+			historyQ.On("OptionsByName", options.AnonymousMaxBalance).Return(nil, nil).Once()
+			historyQ.On("OptionsInsert", &option).Return(nil).Once()
+			action := NewSetOptionAction(NewAdminAction(map[string]interface{}{
+				"name":  options.AnonymousMaxBalance,
+				"value": "15000",
+			}, historyQ))
+			action.Validate()
+			So(action.Err, ShouldBeNil)
+			action.Apply()
+			So(action.Err, ShouldBeNil)
+			So(ChangesOptions(action), ShouldBeTrue)
+			historyQ.AssertExpectations(t)
+		})
+		Convey("Update", func() {
+			option := history.Options{
+				Name: options.StatisticsTimeout,
+				Data: "120",
+			}
+			historyQ.On("OptionsByName", options.StatisticsTimeout).Return(&history.Options{
+				Name: options.StatisticsTimeout,
+				Data: "60",
+			}, nil).Once()
+			historyQ.On("OptionsUpdate", &option).Return(true, nil).Once()
+			action := NewSetOptionAction(NewAdminAction(map[string]interface{}{
+				"name":  options.StatisticsTimeout,
+				"value": "2m",
+			}, historyQ))
+			action.Validate()
+			So(action.Err, ShouldBeNil)
+			action.Apply()
+			So(action.Err, ShouldBeNil)
+			historyQ.AssertExpectations(t)
+		})
+		Convey("Legacy max reversal duration", func() {
+			option := history.Options{
+				Name: options.MaxReversalDuration,
+				Data: "3600",
+			}
+			historyQ.On("OptionsByName", options.MaxReversalDuration).Return(nil, nil).Once()
+			historyQ.On("OptionsInsert", &option).Return(nil).Once()
+			action := NewMaxReversalDurationAction(NewAdminAction(map[string]interface{}{
+				"max_reversal_duration": 3600,
+			}, historyQ))
+			action.Validate()
+			So(action.Err, ShouldBeNil)
+			action.Apply()
+			So(action.Err, ShouldBeNil)
+			So(ChangesOptions(action), ShouldBeTrue)
+			historyQ.AssertExpectations(t)
+		})
+		Convey("Legacy negative max reversal duration", func() {
+			action := NewMaxReversalDurationAction(NewAdminAction(map[string]interface{}{
+				"max_reversal_duration": -1,
+			}, historyQ))
+			action.Validate()
+			So(action.Err, ShouldBeInvalidField, "value")
+		})
+		Convey("Remove", func() {
+			historyQ.On("OptionsDelete", options.SubmissionTimeout).Return(true, nil).Once()
+			action := NewSetOptionAction(NewAdminAction(map[string]interface{}{
+				"name":   options.SubmissionTimeout,
+				"remove": true,
+			}, historyQ))
+			action.Validate()
+			So(action.Err, ShouldBeNil)
+			action.Apply()
+			So(action.Err, ShouldBeNil)
+			historyQ.AssertExpectations(t)
+		})
+	})
+}
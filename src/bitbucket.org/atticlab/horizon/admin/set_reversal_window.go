@@ -0,0 +1,113 @@
+package admin
+
+import (
+	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/render/problem"
+	"github.com/go-errors/errors"
+	"github.com/guregu/null"
+)
+
+// SetReversalWindowAction sets period within which payments of the asset between
+// accounts of specified types can be reversed. Empty asset code, asset issuer and
+// missing account types match any asset, issuer and type.
+type SetReversalWindowAction struct {
+	AdminAction
+	AssetCode    string
+	AssetIssuer  string
+	SenderType   *int32
+	ReceiverType *int32
+	// seconds payment can be reversed within. Zero means payments can not be reversed
+	Duration int64
+	Delete   bool
+
+	stored *history.ReversalWindow
+}
+
+func NewSetReversalWindowAction(adminAction AdminAction) *SetReversalWindowAction {
+	return &SetReversalWindowAction{
+		AdminAction: adminAction,
+	}
+}
+
+func (action *SetReversalWindowAction) Validate() {
+	action.loadParams()
+	if action.Err != nil {
+		return
+	}
+
+	var err error
+	action.stored, err = action.HistoryQ().ReversalWindowByKey(action.AssetCode, action.AssetIssuer, action.SenderType, action.ReceiverType)
+	if err != nil {
+		action.Log.WithStack(err).WithError(err).Error("Failed to get reversal window")
+		action.Err = &problem.ServerError
+		return
+	}
+
+	if action.stored == nil && action.Delete {
+		action.Err = &problem.NotFound
+		return
+	}
+}
+
+func (action *SetReversalWindowAction) Apply() {
+	if action.Err != nil {
+		return
+	}
+
+	var err error
+	switch {
+	case action.Delete:
+		_, err = action.HistoryQ().ReversalWindowDelete(action.stored.ID)
+	case action.stored != nil:
+		action.stored.Duration = action.Duration
+		_, err = action.HistoryQ().ReversalWindowUpdate(action.stored)
+	default:
+		err = action.HistoryQ().ReversalWindowInsert(&history.ReversalWindow{
+			AssetCode:    action.AssetCode,
+			AssetIssuer:  action.AssetIssuer,
+			SenderType:   toNullInt(action.SenderType),
+			ReceiverType: toNullInt(action.ReceiverType),
+			Duration:     action.Duration,
+		})
+	}
+
+	if err != nil {
+		action.Log.WithError(err).Error("Failed to set reversal window")
+		action.Err = &problem.ServerError
+		return
+	}
+}
+
+func toNullInt(value *int32) null.Int {
+	if value == nil {
+		return null.Int{}
+	}
+	return null.IntFrom(int64(*value))
+}
+
+func (action *SetReversalWindowAction) loadParams() {
+	action.AssetCode = action.GetString("asset_code")
+	action.AssetIssuer = action.GetOptionalAddress("asset_issuer")
+	action.SenderType = action.GetOptionalRawAccountType("sender_type")
+	action.ReceiverType = action.GetOptionalRawAccountType("receiver_type")
+	action.Delete = action.GetBool("delete")
+	action.Duration = action.GetInt64("duration")
+	if action.Err != nil {
+		return
+	}
+
+	if len(action.AssetCode) > 12 {
+		action.SetInvalidField("asset_code", errors.New("must not be longer than 12 characters"))
+		return
+	}
+
+	if action.AssetIssuer != "" && action.AssetCode == "" {
+		action.SetInvalidField("asset_issuer", errors.New("must be empty, if asset_code is empty"))
+		return
+	}
+
+	if action.Duration < 0 {
+		action.SetInvalidField("duration", errors.New("must not be negative"))
+		return
+	}
+}
@@ -0,0 +1,110 @@
+package admin
+
+import (
+	"testing"
+
+	"bitbucket.org/atticlab/go-smart-base/keypair"
+	"bitbucket.org/atticlab/go-smart-base/xdr"
+	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/render/problem"
+	"github.com/guregu/null"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestActionsSetReversalWindow(t *testing.T) {
+	Convey("Set reversal window", t, func() {
+		historyQ := &history.QMock{}
+		merchant := int32(xdr.AccountTypeAccountMerchant)
+		issuer, err := keypair.Random()
+		So(err, ShouldBeNil)
+		Convey("Negative duration", func() {
+			action := NewSetReversalWindowAction(NewAdminAction(map[string]interface{}{
+				"asset_code": "UAH",
+				"duration":   -1,
+			}, historyQ))
+			action.Validate()
+			So(action.Err, ShouldBeInvalidField, "duration")
+		})
+		Convey("Invalid account type", func() {
+			action := NewSetReversalWindowAction(NewAdminAction(map[string]interface{}{
+				"sender_type": 100,
+				"duration":    10,
+			}, historyQ))
+			action.Validate()
+			So(action.Err, ShouldBeInvalidField, "sender_type")
+		})
+		Convey("Delete not existing", func() {
+			historyQ.On("ReversalWindowByKey", "UAH", "", (*int32)(nil), &merchant).Return(nil, nil).Once()
+			action := NewSetReversalWindowAction(NewAdminAction(map[string]interface{}{
+				"asset_code":    "UAH",
+				"receiver_type": merchant,
+				"delete":        true,
+			}, historyQ))
+			action.Validate()
+			So(action.Err, ShouldEqual, &problem.NotFound)
+		})
+		Convey("Insert", func() {
+			historyQ.On("ReversalWindowByKey", "UAH", "", (*int32)(nil), &merchant).Return(nil, nil).Once()
+			historyQ.On("ReversalWindowInsert", &history.ReversalWindow{
+				AssetCode:    "UAH",
+				ReceiverType: null.IntFrom(int64(merchant)),
+				Duration:     2592000,
+			}).Return(nil).Once()
+			action := NewSetReversalWindowAction(NewAdminAction(map[string]interface{}{
+				"asset_code":    "UAH",
+				"receiver_type": merchant,
+				"duration":      2592000,
+			}, historyQ))
+			action.Validate()
+			So(action.Err, ShouldBeNil)
+			action.Apply()
+			So(action.Err, ShouldBeNil)
+			historyQ.AssertExpectations(t)
+		})
+		Convey("Issuer without asset code", func() {
+			action := NewSetReversalWindowAction(NewAdminAction(map[string]interface{}{
+				"asset_issuer": issuer.Address(),
+				"duration":     10,
+			}, historyQ))
+			action.Validate()
+			So(action.Err, ShouldBeInvalidField, "asset_issuer")
+		})
+		Convey("Insert for asset issuer", func() {
+			historyQ.On("ReversalWindowByKey", "UAH", issuer.Address(), (*int32)(nil), (*int32)(nil)).Return(nil, nil).Once()
+			historyQ.On("ReversalWindowInsert", &history.ReversalWindow{
+				AssetCode:   "UAH",
+				AssetIssuer: issuer.Address(),
+				Duration:    3600,
+			}).Return(nil).Once()
+			action := NewSetReversalWindowAction(NewAdminAction(map[string]interface{}{
+				"asset_code":   "UAH",
+				"asset_issuer": issuer.Address(),
+				"duration":     3600,
+			}, historyQ))
+			action.Validate()
+			So(action.Err, ShouldBeNil)
+			action.Apply()
+			So(action.Err, ShouldBeNil)
+			historyQ.AssertExpectations(t)
+		})
+		Convey("Update", func() {
+			stored := history.ReversalWindow{
+				ID:       5,
+				Duration: 100,
+			}
+			historyQ.On("ReversalWindowByKey", "", "", (*int32)(nil), (*int32)(nil)).Return(&stored, nil).Once()
+			historyQ.On("ReversalWindowUpdate", &history.ReversalWindow{
+				ID:       5,
+				Duration: 0,
+			}).Return(true, nil).Once()
+			action := NewSetReversalWindowAction(NewAdminAction(map[string]interface{}{
+				"duration": 0,
+			}, historyQ))
+			action.Validate()
+			So(action.Err, ShouldBeNil)
+			action.Apply()
+			So(action.Err, ShouldBeNil)
+			historyQ.AssertExpectations(t)
+		})
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"bitbucket.org/atticlab/horizon/db2/history"
 	"bitbucket.org/atticlab/horizon/render/problem"
 	"database/sql"
+	"github.com/go-errors/errors"
 )
 
 type SetTraitsAction struct {
@@ -11,6 +12,9 @@ type SetTraitsAction struct {
 	Address  string
 	BlockIn  *bool
 	BlockOut *bool
+	// optional reason code and comment stored in traits history
+	ReasonCode history.FreezeReasonCode
+	Comment    string
 
 	account  history.Account
 }
@@ -54,10 +58,34 @@ func (action *SetTraitsAction) Apply() {
 	}
 
 	action.Err = action.HistoryQ().AccountUpdate(&action.account)
+	if action.Err != nil {
+		return
+	}
+
+	err := action.HistoryQ().AccountTraitsEventInsert(&history.AccountTraitsEvent{
+		Address:                action.account.Address,
+		Event:                  history.AccountTraitsEventSetTraits,
+		BlockIncomingPayments:  action.account.BlockIncomingPayments,
+		BlockOutcomingPayments: action.account.BlockOutcomingPayments,
+		ReasonCode:             string(action.ReasonCode),
+		Comment:                action.Comment,
+		CreatedAt:              action.Now(),
+		OperationID:            action.OperationID,
+	})
+	if err != nil {
+		action.Log.WithError(err).Error("Failed to store traits history")
+		action.Err = &problem.ServerError
+		return
+	}
 }
 
 func (action *SetTraitsAction) loadParams() {
 	action.Address = action.GetAddress("account_id")
 	action.BlockIn = action.GetOptionalBool("block_incoming_payments")
 	action.BlockOut = action.GetOptionalBool("block_outcoming_payments")
+	action.ReasonCode = history.FreezeReasonCode(action.GetString("reason_code"))
+	action.Comment = action.GetString("comment")
+	if action.Err == nil && action.ReasonCode != "" && !action.ReasonCode.IsValid() {
+		action.SetInvalidField("reason_code", errors.New("unknown reason code"))
+	}
 }
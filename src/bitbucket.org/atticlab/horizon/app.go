@@ -6,6 +6,8 @@ import (
 	"io/ioutil"
 	"net/http"
 	"runtime"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"bitbucket.org/atticlab/go-smart-base/build"
@@ -16,10 +18,14 @@ import (
 	"bitbucket.org/atticlab/horizon/db2/history"
 	"bitbucket.org/atticlab/horizon/friendbot"
 	"bitbucket.org/atticlab/horizon/ingest"
+	"bitbucket.org/atticlab/horizon/leader"
 	"bitbucket.org/atticlab/horizon/log"
+	"bitbucket.org/atticlab/horizon/options"
 	"bitbucket.org/atticlab/horizon/paths"
 	"bitbucket.org/atticlab/horizon/pump"
+	hredis "bitbucket.org/atticlab/horizon/redis"
 	"bitbucket.org/atticlab/horizon/render/sse"
+	"bitbucket.org/atticlab/horizon/retention"
 	"bitbucket.org/atticlab/horizon/txsub"
 	"github.com/garyburd/redigo/redis"
 	"github.com/rcrowley/go-metrics"
@@ -30,6 +36,8 @@ import (
 
 var appContextKey = 0
 
+const defaultShutdownTimeout = 15 * time.Second
+
 // You can override this variable using: gb build -ldflags "-X main.version aabbccdd"
 var version = ""
 
@@ -51,6 +59,12 @@ type App struct {
 	paths             paths.Finder
 	friendbot         *friendbot.Bot
 	ingester          *ingest.System
+	ingestLeader      *leader.Elector
+	pruner            *retention.Pruner
+	options           *options.Registry
+	adminServer       *graceful.Server
+	// draining is set to 1 on shutdown, accessed atomically
+	draining int32
 
 	// metrics
 	metrics                metrics.Registry
@@ -59,6 +73,8 @@ type App struct {
 	horizonConnGauge       metrics.Gauge
 	stellarCoreConnGauge   metrics.Gauge
 	goroutineGauge         metrics.Gauge
+	redisAvailableGauge    metrics.Gauge
+	redisActiveConnGauge   metrics.Gauge
 
 	sharedCache *cache.SharedCache
 
@@ -116,7 +132,7 @@ func (a *App) Serve() {
 
 		ShutdownInitiated: func() {
 			log.Info("received signal, gracefully stopping")
-			a.Close()
+			a.Shutdown()
 		},
 	}
 
@@ -124,6 +140,11 @@ func (a *App) Serve() {
 
 	sse.SetPump(a.pump.Subscribe())
 
+	if a.config.AdminPort != 0 {
+		a.adminServer = a.newAdminServer()
+		go a.serveAdmin()
+	}
+
 	log.Infof("Starting horizon on %s", addr)
 
 	var err error
@@ -140,6 +161,52 @@ func (a *App) Serve() {
 	log.Info("stopped")
 }
 
+// Shutdown drains the app before closing it: open streams are ended with a
+// retry hint, new submissions are rejected while open ones wait for results,
+// and running ingestion session flushes the current ledger. Waiting is bounded
+// by the configured shutdown timeout.
+func (a *App) Shutdown() {
+	timeout := a.config.ShutdownTimeout
+	if timeout <= 0 {
+		timeout = defaultShutdownTimeout
+	}
+
+	// readiness fails from now on, so load balancer stops routing requests to
+	// the instance while it drains
+	atomic.StoreInt32(&a.draining, 1)
+	sse.Shutdown()
+
+	var wg sync.WaitGroup
+	if a.submitter != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a.submitter.Drain(a.ctx, timeout)
+		}()
+	}
+
+	if a.ingester != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a.ingester.Shutdown(timeout)
+			// let another replica take over ingestion without waiting for lease expiration
+			if a.ingestLeader != nil {
+				a.ingestLeader.Resign()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	// admin server is stopped last, so readiness reports draining instance
+	// until the end
+	if a.adminServer != nil {
+		a.adminServer.Stop(timeout)
+	}
+	a.Close()
+}
+
 // Close cancels the app and forces the closure of db connections
 func (a *App) Close() {
 	a.cancel()
@@ -148,10 +215,28 @@ func (a *App) Close() {
 		a.ingester.Close()
 	}
 
+	if a.pruner != nil {
+		a.pruner.Close()
+	}
+
 	a.historyQ.Repo.DB.Close()
 	a.coreQ.Repo.DB.Close()
 }
 
+// IsDraining returns true once shutdown of the app has started
+func (a *App) IsDraining() bool {
+	return atomic.LoadInt32(&a.draining) == 1
+}
+
+// IngestionLeader returns id of the replica elected to ingest, empty if
+// election is disabled or the leader is unknown
+func (a *App) IngestionLeader() string {
+	if a.ingestLeader == nil {
+		return ""
+	}
+	return a.ingestLeader.Leader()
+}
+
 func (a *App) SharedCache() *cache.SharedCache {
 	return a.sharedCache
 }
@@ -268,4 +353,16 @@ func (a *App) UpdateMetrics(ctx context.Context) {
 
 	a.horizonConnGauge.Update(int64(a.historyQ.Repo.DB.Stats().OpenConnections))
 	a.stellarCoreConnGauge.Update(int64(a.coreQ.Repo.DB.Stats().OpenConnections))
+
+	if a.redisAvailableGauge != nil {
+		var available int64
+		if hredis.IsAvailable() {
+			available = 1
+		}
+		a.redisAvailableGauge.Update(available)
+	}
+
+	if a.redisActiveConnGauge != nil && a.redis != nil {
+		a.redisActiveConnGauge.Update(int64(a.redis.ActiveCount()))
+	}
 }
@@ -0,0 +1,267 @@
+package archive
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"bitbucket.org/atticlab/horizon/db2"
+	"bitbucket.org/atticlab/horizon/log"
+)
+
+// Exporter writes history of ledger range into archive directory
+type Exporter struct {
+	repo      *db2.Repo
+	dir       string
+	format    string
+	chunkSize int32
+	log       *log.Entry
+}
+
+// NewExporter creates new exporter writing archive of the format into dir
+func NewExporter(repo *db2.Repo, dir, format string, chunkSize int32) (*Exporter, error) {
+	if !IsValidFormat(format) {
+		return nil, fmt.Errorf("unsupported format %s", format)
+	}
+
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+
+	return &Exporter{
+		repo:      repo,
+		dir:       dir,
+		format:    format,
+		chunkSize: chunkSize,
+		log:       log.WithField("service", "archive_exporter"),
+	}, nil
+}
+
+// Export writes ledgers [fromLedger, toLedger] into the archive. Manifest is
+// written last, so archive without manifest is incomplete.
+func (e *Exporter) Export(fromLedger, toLedger int32) (*Manifest, error) {
+	if fromLedger <= 0 || toLedger < fromLedger {
+		return nil, errors.New("invalid ledger range")
+	}
+
+	err := os.MkdirAll(e.dir, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &Manifest{
+		Version:    ManifestVersion,
+		Format:     e.format,
+		FromLedger: fromLedger,
+		ToLedger:   toLedger,
+		ChunkSize:  e.chunkSize,
+		CreatedAt:  time.Now().UTC(),
+	}
+
+	for start := fromLedger; start <= toLedger; start += e.chunkSize {
+		end := start + e.chunkSize - 1
+		if end > toLedger || end < start {
+			end = toLedger
+		}
+
+		chunk, err := e.exportChunk(start, end)
+		if err != nil {
+			return nil, err
+		}
+
+		manifest.Chunks = append(manifest.Chunks, *chunk)
+	}
+
+	manifest.State, err = e.exportState(toLedger)
+	if err != nil {
+		return nil, err
+	}
+
+	err = WriteManifest(e.dir, manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// exportChunk writes all tables of ledgers [fromLedger, toLedger] using single
+// repeatable read transaction, so the files are consistent with each other.
+func (e *Exporter) exportChunk(fromLedger, toLedger int32) (*Chunk, error) {
+	repo, err := e.beginSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	defer repo.Rollback()
+
+	chunk := Chunk{
+		FromLedger: fromLedger,
+		ToLedger:   toLedger,
+	}
+
+	for _, table := range Tables {
+		file := File{
+			Table: table.Name,
+			Name:  fileName(table.Name, chunk, e.format),
+		}
+
+		start, end := table.Range(fromLedger, toLedger)
+		err = writeFileAtomic(filepath.Join(e.dir, file.Name), func(w io.Writer) error {
+			return e.writeTable(repo, w, table, "WHERE t.%[1]s >= ? AND t.%[1]s < ?", &file, start, end)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		chunk.Files = append(chunk.Files, file)
+	}
+
+	e.log.WithFields(log.F{
+		"from_ledger": fromLedger,
+		"to_ledger":   toLedger,
+	}).Info("Exported chunk")
+	return &chunk, nil
+}
+
+// exportState writes all rows of StateTables using single repeatable read
+// transaction
+func (e *Exporter) exportState(toLedger int32) ([]File, error) {
+	repo, err := e.beginSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	defer repo.Rollback()
+
+	var files []File
+	for _, table := range StateTables {
+		file := File{
+			Table: table.Name,
+			Name:  stateFileName(table.Name, toLedger, e.format),
+		}
+
+		err = writeFileAtomic(filepath.Join(e.dir, file.Name), func(w io.Writer) error {
+			return e.writeTable(repo, w, table, "", &file)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, file)
+	}
+
+	e.log.WithField("tables", len(files)).Info("Exported state")
+	return files, nil
+}
+
+// beginSnapshot starts read only repeatable read transaction
+func (e *Exporter) beginSnapshot() (*db2.Repo, error) {
+	repo := e.repo.Clone()
+	err := repo.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = repo.ExecRaw("SET TRANSACTION ISOLATION LEVEL REPEATABLE READ READ ONLY")
+	if err != nil {
+		repo.Rollback()
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+// writeTable writes rows of the table matching where clause and fills rows
+// count, size and checksum of the file. Where clause may refer to IDCol as %[1]s.
+func (e *Exporter) writeTable(repo *db2.Repo, w io.Writer, table Table, where string, file *File, args ...interface{}) error {
+	hash := sha256.New()
+	cw := &countingWriter{w: io.MultiWriter(w, hash)}
+
+	var rw rowWriter
+	switch e.format {
+	case FormatParquet:
+		columns, err := tableColumns(repo, table.Name)
+		if err != nil {
+			return err
+		}
+		rw = newParquetRowWriter(cw, columns)
+	default:
+		rw = newJSONLWriter(cw)
+	}
+
+	if where != "" {
+		where = fmt.Sprintf(where, table.IDCol)
+	}
+
+	rows, err := repo.QueryRaw(
+		fmt.Sprintf("SELECT row_to_json(t)::text FROM %s t %s ORDER BY t.%s", table.Name, where, table.IDCol),
+		args...,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row []byte
+		err = rows.Scan(&row)
+		if err != nil {
+			return err
+		}
+
+		err = rw.Write(row)
+		if err != nil {
+			return err
+		}
+		file.Rows++
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return err
+	}
+
+	err = rw.Close()
+	if err != nil {
+		return err
+	}
+
+	file.Size = cw.n
+	file.SHA256 = hex.EncodeToString(hash.Sum(nil))
+	return nil
+}
+
+// tableColumns returns parquet columns of the table in definition order.
+// Integer columns are stored as INT64, others as strings.
+func tableColumns(repo *db2.Repo, table string) ([]parquetColumn, error) {
+	var rows []struct {
+		Name     string `db:"column_name"`
+		DataType string `db:"data_type"`
+	}
+	err := repo.SelectRaw(
+		&rows,
+		"SELECT column_name, data_type FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = ? ORDER BY ordinal_position",
+		table,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("table %s not found", table)
+	}
+
+	columns := make([]parquetColumn, len(rows))
+	for i, row := range rows {
+		columns[i] = parquetColumn{Name: row.Name, Type: parquetTypeByteArray}
+		switch row.DataType {
+		case "smallint", "integer", "bigint":
+			columns[i].Type = parquetTypeInt64
+		}
+	}
+	return columns, nil
+}
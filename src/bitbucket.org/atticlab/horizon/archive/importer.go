@@ -0,0 +1,250 @@
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"bitbucket.org/atticlab/horizon/db2"
+	"bitbucket.org/atticlab/horizon/log"
+)
+
+const (
+	// maxLineSize is the max size of a single row in jsonl file
+	maxLineSize = 64 * 1024 * 1024
+	// batchRows is the max number of rows inserted by a single statement
+	batchRows = 1000
+	// batchSize is the max size of rows inserted by a single statement
+	batchSize = 16 * 1024 * 1024
+)
+
+// sequences of serial ids, which must be moved past imported rows
+var sequences = []Table{
+	{"history_operation_participants", "id", false},
+	{"history_transaction_participants", "id", false},
+	{"asset_supply_changes", "id", false},
+	{"compliance_alerts", "id", false},
+	{"asset", "id", false},
+	{"account_freezes", "id", false},
+	{"account_traits_history", "id", false},
+	{"disputes", "id", false},
+	{"dispute_notes", "id", false},
+}
+
+// Importer populates empty history db from the archive
+type Importer struct {
+	repo *db2.Repo
+	dir  string
+	log  *log.Entry
+}
+
+// NewImporter creates new importer reading archive from dir
+func NewImporter(repo *db2.Repo, dir string) *Importer {
+	return &Importer{
+		repo: repo,
+		dir:  dir,
+		log:  log.WithField("service", "archive_importer"),
+	}
+}
+
+// Import verifies checksums of all files of the archive and imports them. Each
+// chunk is imported in a separate db transaction. Only jsonl archives can be
+// imported, parquet archives are meant for analytics outside of horizon.
+func (i *Importer) Import() (*Manifest, error) {
+	manifest, err := ReadManifest(i.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if manifest.Format != FormatJSONL {
+		return nil, fmt.Errorf("import of %s archives is not supported", manifest.Format)
+	}
+
+	for _, chunk := range manifest.Chunks {
+		for _, file := range chunk.Files {
+			err = file.Verify(i.dir)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, file := range manifest.State {
+		err = file.Verify(i.dir)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = i.ensureEmpty()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, chunk := range manifest.Chunks {
+		err = i.importChunk(chunk)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = i.importState(manifest.State)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, seq := range sequences {
+		_, err = i.repo.ExecRaw(fmt.Sprintf(
+			"SELECT setval('%s_id_seq', COALESCE((SELECT MAX(%s) FROM %s), 0) + 1, false)",
+			seq.Name, seq.IDCol, seq.Name,
+		))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return manifest, nil
+}
+
+// ensureEmpty returns error if history db already contains ingested ledgers
+func (i *Importer) ensureEmpty() error {
+	for _, table := range append(Tables, StateTables...) {
+		var exists bool
+		err := i.repo.GetRaw(&exists, fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s)", table.Name))
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			return errors.New("history db is not empty: " + table.Name)
+		}
+	}
+	return nil
+}
+
+func (i *Importer) importChunk(chunk Chunk) error {
+	err := i.importFiles(Tables, chunk.Files)
+	if err != nil {
+		return err
+	}
+
+	i.log.WithFields(log.F{
+		"from_ledger": chunk.FromLedger,
+		"to_ledger":   chunk.ToLedger,
+	}).Info("Imported chunk")
+	return nil
+}
+
+func (i *Importer) importState(files []File) error {
+	if len(files) == 0 {
+		return nil
+	}
+
+	err := i.importFiles(StateTables, files)
+	if err != nil {
+		return err
+	}
+
+	i.log.WithField("tables", len(files)).Info("Imported state")
+	return nil
+}
+
+// importFiles imports files in a single db transaction. Files are imported in
+// order of tables, so that referenced rows exist.
+func (i *Importer) importFiles(tables []Table, files []File) error {
+	repo := i.repo.Clone()
+	err := repo.Begin()
+	if err != nil {
+		return err
+	}
+	defer repo.Rollback()
+
+	for _, table := range tables {
+		for _, file := range files {
+			if file.Table != table.Name {
+				continue
+			}
+
+			err = i.importFile(repo, file)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return repo.Commit()
+}
+
+func (i *Importer) importFile(repo *db2.Repo, file File) error {
+	f, err := os.Open(filepath.Join(i.dir, file.Name))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	query := fmt.Sprintf("INSERT INTO %s SELECT * FROM json_populate_recordset(NULL::%s, ?::json)", file.Table, file.Table)
+
+	var (
+		rows    int64
+		batch   bytes.Buffer
+		batched int
+	)
+
+	flush := func() error {
+		if batched == 0 {
+			return nil
+		}
+
+		batch.WriteByte(']')
+		_, err := repo.ExecRaw(query, batch.String())
+		batch.Reset()
+		batched = 0
+		return err
+	}
+
+	scanner := bufio.NewScanner(gz)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		if batched == 0 {
+			batch.WriteByte('[')
+		} else {
+			batch.WriteByte(',')
+		}
+		batch.Write(scanner.Bytes())
+		batched++
+		rows++
+
+		if batched >= batchRows || batch.Len() >= batchSize {
+			err = flush()
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return err
+	}
+
+	err = flush()
+	if err != nil {
+		return err
+	}
+
+	if rows != file.Rows {
+		return fmt.Errorf("%s: expected %d rows, got %d", file.Name, file.Rows, rows)
+	}
+
+	return nil
+}
@@ -0,0 +1,231 @@
+// Package archive exports ingested history into files, which can be handed off
+// to third parties without access to the db, and imports such archives back
+// into an empty history db.
+//
+// Archive is a directory with manifest.json, a file per table per chunk of
+// ledgers and a file per state table. Manifest lists all files with their
+// checksums.
+package archive
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"bitbucket.org/atticlab/horizon/toid"
+)
+
+const (
+	// FormatJSONL - gzipped json lines, row per line
+	FormatJSONL = "jsonl"
+	// FormatParquet - parquet file with integer columns stored as optional INT64
+	// and other columns as optional utf8 strings. Intended for analytics, can't
+	// be imported back into history db.
+	FormatParquet = "parquet"
+
+	// ManifestName is the name of the manifest file in the archive
+	ManifestName = "manifest.json"
+	// ManifestVersion is the current version of the manifest
+	ManifestVersion = 2
+	// DefaultChunkSize is the default number of ledgers in a single chunk
+	DefaultChunkSize = 10000
+)
+
+// Table describes history table included into archive
+type Table struct {
+	Name  string
+	IDCol string
+	// BySequence is true if IDCol holds ledger sequence instead of total order id
+	BySequence bool
+}
+
+// Tables are ordered so that rows are imported after rows they refer to.
+var Tables = []Table{
+	{"history_ledgers", "id", false},
+	{"history_accounts", "id", false},
+	{"history_transactions", "id", false},
+	{"history_transaction_participants", "history_transaction_id", false},
+	{"history_operations", "id", false},
+	{"history_operation_participants", "history_operation_id", false},
+	{"history_effects", "history_operation_id", false},
+	{"payment_refunds", "id", false},
+	{"asset_supply_changes", "operation_id", false},
+	{"compliance_alerts", "ledger_sequence", true},
+}
+
+// StateTables are not split by ledgers, they are exported as a whole after all
+// chunks and hold state at the moment of export. IDCol is used to order rows.
+var StateTables = []Table{
+	{"asset", "id", false},
+	{"account_statistics", "address", false},
+	{"account_freezes", "id", false},
+	{"account_traits_history", "id", false},
+	{"disputes", "id", false},
+	{"dispute_notes", "id", false},
+}
+
+// Manifest describes content of the archive
+type Manifest struct {
+	Version    int       `json:"version"`
+	Format     string    `json:"format"`
+	FromLedger int32     `json:"from_ledger"`
+	ToLedger   int32     `json:"to_ledger"`
+	ChunkSize  int32     `json:"chunk_size"`
+	CreatedAt  time.Time `json:"created_at"`
+	Chunks     []Chunk   `json:"chunks"`
+	// State holds files of StateTables, empty in archives of version 1
+	State []File `json:"state"`
+}
+
+// Chunk is a range of ledgers exported into separate files
+type Chunk struct {
+	FromLedger int32  `json:"from_ledger"`
+	ToLedger   int32  `json:"to_ledger"`
+	Files      []File `json:"files"`
+}
+
+// File is a single file of the archive
+type File struct {
+	Table  string `json:"table"`
+	Name   string `json:"name"`
+	Rows   int64  `json:"rows"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// IsValidFormat returns true if format is supported by the exporter
+func IsValidFormat(format string) bool {
+	return format == FormatJSONL || format == FormatParquet
+}
+
+// IDRange returns range [start, end) of total order ids of rows belonging to ledgers
+// [fromLedger, toLedger]. Range of the first ledger includes rows with ids lower
+// than the ledger's toid (e.g. master account).
+func IDRange(fromLedger, toLedger int32) (int64, int64) {
+	var start int64
+	if fromLedger > 1 {
+		startID := toid.ID{LedgerSequence: fromLedger}
+		start = startID.ToInt64()
+	}
+
+	endID := toid.ID{LedgerSequence: toLedger + 1}
+	return start, endID.ToInt64()
+}
+
+// Range returns range [start, end) of IDCol values of rows belonging to ledgers
+// [fromLedger, toLedger]
+func (t Table) Range(fromLedger, toLedger int32) (int64, int64) {
+	if t.BySequence {
+		return int64(fromLedger), int64(toLedger) + 1
+	}
+	return IDRange(fromLedger, toLedger)
+}
+
+// fileName returns name of the file of the table in the chunk
+func fileName(table string, chunk Chunk, format string) string {
+	return fmt.Sprintf("%s_%d_%d.%s", table, chunk.FromLedger, chunk.ToLedger, fileExt(format))
+}
+
+// stateFileName returns name of the file of the state table exported along
+// with ledgers up to toLedger
+func stateFileName(table string, toLedger int32, format string) string {
+	return fmt.Sprintf("%s_state_%d.%s", table, toLedger, fileExt(format))
+}
+
+func fileExt(format string) string {
+	if format == FormatParquet {
+		return "parquet"
+	}
+	return "jsonl.gz"
+}
+
+// ReadManifest reads manifest of the archive in dir
+func ReadManifest(dir string) (*Manifest, error) {
+	file, err := os.Open(filepath.Join(dir, ManifestName))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var manifest Manifest
+	err = json.NewDecoder(file).Decode(&manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	if manifest.Version < 1 || manifest.Version > ManifestVersion {
+		return nil, fmt.Errorf("unsupported manifest version %d", manifest.Version)
+	}
+
+	return &manifest, nil
+}
+
+// WriteManifest writes manifest of the archive into dir
+func WriteManifest(dir string, manifest *Manifest) error {
+	raw, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(filepath.Join(dir, ManifestName), func(w io.Writer) error {
+		_, err := w.Write(raw)
+		return err
+	})
+}
+
+// Verify checks that size and checksum of the file in dir match the manifest
+func (f *File) Verify(dir string) error {
+	file, err := os.Open(filepath.Join(dir, f.Name))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return err
+	}
+
+	if size != f.Size {
+		return fmt.Errorf("%s: expected size %d, got %d", f.Name, f.Size, size)
+	}
+
+	checksum := hex.EncodeToString(hash.Sum(nil))
+	if checksum != f.SHA256 {
+		return fmt.Errorf("%s: checksum mismatch", f.Name)
+	}
+
+	return nil
+}
+
+// writeFileAtomic writes data into temporary file and renames it to name, when done
+func writeFileAtomic(name string, write func(w io.Writer) error) error {
+	tmpName := name + ".tmp"
+	file, err := os.Create(tmpName)
+	if err != nil {
+		return err
+	}
+
+	err = write(file)
+	if err == nil {
+		err = file.Sync()
+	}
+
+	closeErr := file.Close()
+	if err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+
+	return os.Rename(tmpName, name)
+}
@@ -0,0 +1,442 @@
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"bitbucket.org/atticlab/horizon/toid"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestArchive(t *testing.T) {
+	Convey("Archive:", t, func() {
+		Convey("IDRange", func() {
+			start, end := IDRange(1, 10)
+			So(start, ShouldEqual, 0)
+			So(end, ShouldEqual, toid.New(11, 0, 0).ToInt64())
+
+			start, end = IDRange(11, 20)
+			So(start, ShouldEqual, toid.New(11, 0, 0).ToInt64())
+			So(end, ShouldEqual, toid.New(21, 0, 0).ToInt64())
+		})
+		Convey("Table range", func() {
+			start, end := Table{"compliance_alerts", "ledger_sequence", true}.Range(11, 20)
+			So(start, ShouldEqual, 11)
+			So(end, ShouldEqual, 21)
+
+			start, end = Table{"payment_refunds", "id", false}.Range(11, 20)
+			So(start, ShouldEqual, toid.New(11, 0, 0).ToInt64())
+			So(end, ShouldEqual, toid.New(21, 0, 0).ToInt64())
+		})
+		Convey("File name", func() {
+			chunk := Chunk{FromLedger: 1, ToLedger: 100}
+			So(fileName("history_ledgers", chunk, FormatJSONL), ShouldEqual, "history_ledgers_1_100.jsonl.gz")
+			So(fileName("history_ledgers", chunk, FormatParquet), ShouldEqual, "history_ledgers_1_100.parquet")
+			So(stateFileName("disputes", 100, FormatJSONL), ShouldEqual, "disputes_state_100.jsonl.gz")
+		})
+		Convey("jsonToString", func() {
+			value, err := jsonToString(json.RawMessage(`"abc"`))
+			So(err, ShouldBeNil)
+			So(*value, ShouldEqual, "abc")
+
+			value, err = jsonToString(json.RawMessage(`{"a": 1}`))
+			So(err, ShouldBeNil)
+			So(*value, ShouldEqual, `{"a": 1}`)
+
+			value, err = jsonToString(json.RawMessage(`10`))
+			So(err, ShouldBeNil)
+			So(*value, ShouldEqual, "10")
+
+			value, err = jsonToString(json.RawMessage(`null`))
+			So(err, ShouldBeNil)
+			So(value, ShouldBeNil)
+
+			value, err = jsonToString(nil)
+			So(err, ShouldBeNil)
+			So(value, ShouldBeNil)
+		})
+		Convey("Manifest and checksums", func() {
+			dir, err := ioutil.TempDir("", "archive")
+			So(err, ShouldBeNil)
+			defer os.RemoveAll(dir)
+
+			data := []byte("content")
+			err = ioutil.WriteFile(filepath.Join(dir, "file"), data, 0644)
+			So(err, ShouldBeNil)
+			checksum := sha256.Sum256(data)
+
+			manifest := &Manifest{
+				Version:    ManifestVersion,
+				Format:     FormatJSONL,
+				FromLedger: 1,
+				ToLedger:   2,
+				ChunkSize:  DefaultChunkSize,
+				Chunks: []Chunk{{
+					FromLedger: 1,
+					ToLedger:   2,
+					Files: []File{{
+						Table:  "history_ledgers",
+						Name:   "file",
+						Rows:   1,
+						Size:   int64(len(data)),
+						SHA256: hex.EncodeToString(checksum[:]),
+					}},
+				}},
+			}
+			So(WriteManifest(dir, manifest), ShouldBeNil)
+
+			stored, err := ReadManifest(dir)
+			So(err, ShouldBeNil)
+			So(stored.Chunks, ShouldResemble, manifest.Chunks)
+
+			file := stored.Chunks[0].Files[0]
+			So(file.Verify(dir), ShouldBeNil)
+
+			err = ioutil.WriteFile(filepath.Join(dir, "file"), []byte("CONTENT"), 0644)
+			So(err, ShouldBeNil)
+			So(file.Verify(dir), ShouldNotBeNil)
+		})
+		Convey("JSONL writer", func() {
+			var buf bytes.Buffer
+			w := newJSONLWriter(&buf)
+			So(w.Write([]byte(`{"id":1}`)), ShouldBeNil)
+			So(w.Write([]byte(`{"id":2}`)), ShouldBeNil)
+			So(w.Close(), ShouldBeNil)
+
+			gz, err := gzip.NewReader(&buf)
+			So(err, ShouldBeNil)
+			content, err := ioutil.ReadAll(gz)
+			So(err, ShouldBeNil)
+			So(string(content), ShouldEqual, "{\"id\":1}\n{\"id\":2}\n")
+		})
+		Convey("Parquet writer", func() {
+			var buf bytes.Buffer
+			columns := []parquetColumn{
+				{Name: "id", Type: parquetTypeInt64},
+				{Name: "memo", Type: parquetTypeByteArray},
+			}
+			w := newParquetRowWriter(&buf, columns)
+			total := parquetRowGroupRows*2 + 1
+			for i := 0; i < total; i++ {
+				row := fmt.Sprintf(`{"id":%d,"memo":null}`, int64(i)<<32)
+				if i%3 == 0 {
+					row = fmt.Sprintf(`{"id":%d,"memo":"memo %d"}`, int64(i)<<32, i)
+				}
+				So(w.Write([]byte(row)), ShouldBeNil)
+			}
+			So(w.Close(), ShouldBeNil)
+
+			file, err := readParquet(buf.Bytes())
+			So(err, ShouldBeNil)
+			So(file.Columns, ShouldResemble, columns)
+			So(file.RowGroups, ShouldEqual, 3)
+			So(file.NumRows, ShouldEqual, total)
+			So(len(file.Rows), ShouldEqual, total)
+			for i, row := range file.Rows {
+				So(row[0], ShouldEqual, int64(i)<<32)
+				if i%3 == 0 {
+					So(row[1], ShouldEqual, fmt.Sprintf("memo %d", i))
+				} else {
+					So(row[1], ShouldBeNil)
+				}
+			}
+
+			Convey("empty", func() {
+				var buf bytes.Buffer
+				w := newParquetRowWriter(&buf, columns)
+				So(w.Close(), ShouldBeNil)
+
+				file, err := readParquet(buf.Bytes())
+				So(err, ShouldBeNil)
+				So(file.RowGroups, ShouldEqual, 0)
+				So(file.Rows, ShouldBeEmpty)
+			})
+			Convey("not integer value", func() {
+				w := newParquetRowWriter(&bytes.Buffer{}, columns)
+				So(w.Write([]byte(`{"id":"a","memo":null}`)), ShouldNotBeNil)
+			})
+		})
+		Convey("Definition levels", func() {
+			levels := encodeDefinitionLevels([]bool{true, true, false, true})
+			So(levels, ShouldResemble, []byte{2 << 1, 1, 1 << 1, 0, 1 << 1, 1})
+		})
+		Convey("Thrift compact", func() {
+			var t thriftWriter
+			t.StructBegin()
+			t.FieldI32(1, 3)
+			t.FieldString(4, "ab")
+			t.FieldI64(20, -1)
+			t.StructEnd()
+			So(t.Bytes(), ShouldResemble, []byte{0x15, 6, 0x38, 2, 'a', 'b', 0x06, 40, 1, 0})
+		})
+	})
+}
+
+// parquetFile is the content of parquet file decoded by readParquet
+type parquetFile struct {
+	Columns   []parquetColumn
+	NumRows   int64
+	RowGroups int
+	// values are nil, int64 or string
+	Rows [][]interface{}
+}
+
+// readParquet decodes parquet file independently of parquetWriter: footer and
+// page headers are decoded by generic thrift compact reader, offsets, sizes
+// and counts are taken from metadata as any parquet reader does.
+func readParquet(content []byte) (*parquetFile, error) {
+	if len(content) < 12 || string(content[:4]) != "PAR1" || string(content[len(content)-4:]) != "PAR1" {
+		return nil, errors.New("no magic")
+	}
+
+	footerLen := int(binary.LittleEndian.Uint32(content[len(content)-8 : len(content)-4]))
+	if footerLen > len(content)-12 {
+		return nil, errors.New("invalid footer length")
+	}
+
+	meta, err := readThriftStruct(bytes.NewReader(content[len(content)-8-footerLen : len(content)-8]))
+	if err != nil {
+		return nil, err
+	}
+
+	var file parquetFile
+	schema := meta[2].([]interface{})
+	for _, element := range schema[1:] {
+		element := element.(map[int16]interface{})
+		file.Columns = append(file.Columns, parquetColumn{
+			Name: string(element[4].([]byte)),
+			Type: int32(element[1].(int64)),
+		})
+	}
+	file.NumRows = meta[3].(int64)
+
+	groups, _ := meta[4].([]interface{})
+	file.RowGroups = len(groups)
+	for _, group := range groups {
+		group := group.(map[int16]interface{})
+		numRows := int(group[3].(int64))
+		values := make([][]interface{}, len(file.Columns))
+		for i, chunk := range group[1].([]interface{}) {
+			chunkMeta := chunk.(map[int16]interface{})[3].(map[int16]interface{})
+			if chunkMeta[4].(int64) != parquetCodecGzip {
+				return nil, errors.New("unexpected codec")
+			}
+
+			values[i], err = readParquetPage(content, chunkMeta[9].(int64), file.Columns[i].Type)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(values[i]) != numRows || chunkMeta[5].(int64) != int64(numRows) {
+				return nil, fmt.Errorf("column %d: expected %d values, got %d", i, numRows, len(values[i]))
+			}
+		}
+
+		for row := 0; row < numRows; row++ {
+			fields := make([]interface{}, len(file.Columns))
+			for i := range fields {
+				fields[i] = values[i][row]
+			}
+			file.Rows = append(file.Rows, fields)
+		}
+	}
+
+	return &file, nil
+}
+
+// readParquetPage decodes values of the data page v1 starting at offset
+func readParquetPage(content []byte, offset int64, typ int32) ([]interface{}, error) {
+	reader := bytes.NewReader(content[offset:])
+	header, err := readThriftStruct(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	if header[1].(int64) != parquetPageTypeData {
+		return nil, errors.New("unexpected page type")
+	}
+
+	compressed := make([]byte, header[3].(int64))
+	_, err = io.ReadFull(reader, compressed)
+	if err != nil {
+		return nil, err
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, err
+	}
+	page, err := ioutil.ReadAll(gz)
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(page)) != header[2].(int64) {
+		return nil, errors.New("unexpected uncompressed size")
+	}
+
+	numValues := int(header[5].(map[int16]interface{})[1].(int64))
+	levelsLen := binary.LittleEndian.Uint32(page)
+	levels := bytes.NewReader(page[4 : 4+levelsLen])
+	data := bytes.NewReader(page[4+levelsLen:])
+
+	// RLE/bit-packing hybrid with bit width 1
+	var defined []bool
+	for len(defined) < numValues {
+		runHeader, err := binary.ReadUvarint(levels)
+		if err != nil {
+			return nil, err
+		}
+
+		if runHeader&1 == 0 {
+			value, err := levels.ReadByte()
+			if err != nil {
+				return nil, err
+			}
+			for j := uint64(0); j < runHeader>>1; j++ {
+				defined = append(defined, value == 1)
+			}
+			continue
+		}
+
+		for j := uint64(0); j < runHeader>>1; j++ {
+			packed, err := levels.ReadByte()
+			if err != nil {
+				return nil, err
+			}
+			for bit := uint(0); bit < 8; bit++ {
+				defined = append(defined, packed&(1<<bit) != 0)
+			}
+		}
+	}
+
+	result := make([]interface{}, numValues)
+	for i := range result {
+		if !defined[i] {
+			continue
+		}
+
+		switch typ {
+		case parquetTypeInt64:
+			var value int64
+			err = binary.Read(data, binary.LittleEndian, &value)
+			result[i] = value
+		case parquetTypeByteArray:
+			var size uint32
+			err = binary.Read(data, binary.LittleEndian, &size)
+			if err != nil {
+				return nil, err
+			}
+			value := make([]byte, size)
+			_, err = io.ReadFull(data, value)
+			result[i] = string(value)
+		default:
+			err = errors.New("unexpected type")
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if data.Len() != 0 {
+		return nil, errors.New("unexpected data after values")
+	}
+	return result, nil
+}
+
+// readThriftStruct decodes struct of thrift compact protocol into map of field
+// ids to values: int64, bool, []byte, []interface{} or nested map
+func readThriftStruct(r *bytes.Reader) (map[int16]interface{}, error) {
+	result := make(map[int16]interface{})
+	var lastField int16
+	for {
+		header, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+
+		typ := header & 0x0f
+		if typ == 0 {
+			return result, nil
+		}
+
+		if delta := int16(header >> 4); delta != 0 {
+			lastField += delta
+		} else {
+			id, err := binary.ReadVarint(r)
+			if err != nil {
+				return nil, err
+			}
+			lastField = int16(id)
+		}
+
+		switch typ {
+		case 1, 2:
+			// booleans of struct fields are encoded in the type
+			result[lastField] = typ == 1
+			continue
+		}
+
+		result[lastField], err = readThriftValue(r, typ)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func readThriftValue(r *bytes.Reader, typ byte) (interface{}, error) {
+	switch typ {
+	case 1, 2:
+		value, err := r.ReadByte()
+		return value == 1, err
+	case 3:
+		value, err := r.ReadByte()
+		return int64(int8(value)), err
+	case 4, 5, 6:
+		return binary.ReadVarint(r)
+	case 8:
+		size, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, err
+		}
+		value := make([]byte, size)
+		_, err = io.ReadFull(r, value)
+		return value, err
+	case 9, 10:
+		header, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+
+		size := uint64(header >> 4)
+		if size == 15 {
+			size, err = binary.ReadUvarint(r)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		list := make([]interface{}, size)
+		for i := range list {
+			list[i], err = readThriftValue(r, header&0x0f)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return list, nil
+	case 12:
+		return readThriftStruct(r)
+	}
+	return nil, fmt.Errorf("unsupported thrift type %d", typ)
+}
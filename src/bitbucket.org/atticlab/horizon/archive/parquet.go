@@ -0,0 +1,407 @@
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Minimal parquet writer. Rows are buffered and written as row groups of at
+// most parquetRowGroupRows rows or parquetRowGroupSize bytes, so memory used by
+// the writer does not depend on the size of the file. Each column chunk is a
+// single gzipped data page. Columns are optional: integer columns are stored as
+// INT64, all other columns as utf8 byte arrays.
+// See https://github.com/apache/parquet-format for the format description.
+
+var parquetMagic = []byte("PAR1")
+
+const (
+	// parquetRowGroupRows is the max number of rows in a row group
+	parquetRowGroupRows = 10000
+	// parquetRowGroupSize is the max size of plain encoded values of a row group
+	parquetRowGroupSize = 64 * 1024 * 1024
+)
+
+// parquet-format enum values
+const (
+	parquetTypeInt64          = 2
+	parquetTypeByteArray      = 6
+	parquetRepetitionRequired = 0
+	parquetRepetitionOptional = 1
+	parquetConvertedTypeUTF8  = 0
+	parquetEncodingPlain      = 0
+	parquetEncodingRLE        = 3
+	parquetCodecGzip          = 2
+	parquetPageTypeData       = 0
+)
+
+// parquetColumn describes column of the file
+type parquetColumn struct {
+	Name string
+	// Type is parquetTypeInt64 or parquetTypeByteArray
+	Type int32
+}
+
+// parquetColumnBuffer holds values of the column in the current row group
+type parquetColumnBuffer struct {
+	// defined is false for null values
+	defined []bool
+	// plain encoded not null values
+	data bytes.Buffer
+}
+
+type parquetColumnChunk struct {
+	offset           int64
+	numValues        int64
+	uncompressedSize int64
+	compressedSize   int64
+}
+
+type parquetRowGroup struct {
+	chunks    []parquetColumnChunk
+	rows      int64
+	totalSize int64
+}
+
+type parquetWriter struct {
+	w       *countingWriter
+	columns []parquetColumn
+	buffers []parquetColumnBuffer
+	// rows of the current row group
+	groupRows int64
+	groupSize int
+	groups    []parquetRowGroup
+	rows      int64
+}
+
+func newParquetWriter(w io.Writer, columns []parquetColumn) *parquetWriter {
+	return &parquetWriter{
+		w:       &countingWriter{w: w},
+		columns: columns,
+		buffers: make([]parquetColumnBuffer, len(columns)),
+	}
+}
+
+// Append adds a row. len(row) must be equal to the number of columns, values
+// of INT64 columns must be decimal integers.
+func (pw *parquetWriter) Append(row []*string) error {
+	for i, column := range pw.columns {
+		buffer := &pw.buffers[i]
+		value := row[i]
+		buffer.defined = append(buffer.defined, value != nil)
+		if value == nil {
+			continue
+		}
+
+		size := buffer.data.Len()
+		switch column.Type {
+		case parquetTypeInt64:
+			parsed, err := strconv.ParseInt(*value, 10, 64)
+			if err != nil {
+				return fmt.Errorf("column %s: %s", column.Name, err.Error())
+			}
+			binary.Write(&buffer.data, binary.LittleEndian, parsed)
+		default:
+			binary.Write(&buffer.data, binary.LittleEndian, uint32(len(*value)))
+			buffer.data.WriteString(*value)
+		}
+		pw.groupSize += buffer.data.Len() - size
+	}
+	pw.groupRows++
+	pw.rows++
+
+	if pw.groupRows >= parquetRowGroupRows || pw.groupSize >= parquetRowGroupSize {
+		return pw.flush()
+	}
+	return nil
+}
+
+// flush writes buffered rows as a row group
+func (pw *parquetWriter) flush() error {
+	if pw.groupRows == 0 {
+		return nil
+	}
+
+	if pw.w.n == 0 {
+		_, err := pw.w.Write(parquetMagic)
+		if err != nil {
+			return err
+		}
+	}
+
+	group := parquetRowGroup{
+		chunks: make([]parquetColumnChunk, len(pw.columns)),
+		rows:   pw.groupRows,
+	}
+	for i := range pw.columns {
+		var err error
+		group.chunks[i], err = pw.writeColumn(&pw.buffers[i])
+		if err != nil {
+			return err
+		}
+		group.totalSize += group.chunks[i].uncompressedSize
+		pw.buffers[i] = parquetColumnBuffer{}
+	}
+
+	pw.groups = append(pw.groups, group)
+	pw.groupRows = 0
+	pw.groupSize = 0
+	return nil
+}
+
+// Close writes buffered rows and the footer of the file
+func (pw *parquetWriter) Close() error {
+	err := pw.flush()
+	if err != nil {
+		return err
+	}
+
+	if pw.w.n == 0 {
+		_, err = pw.w.Write(parquetMagic)
+		if err != nil {
+			return err
+		}
+	}
+
+	var footer thriftWriter
+	pw.writeFileMetaData(&footer)
+	_, err = pw.w.Write(footer.Bytes())
+	if err != nil {
+		return err
+	}
+
+	err = binary.Write(pw.w, binary.LittleEndian, uint32(footer.Len()))
+	if err != nil {
+		return err
+	}
+
+	_, err = pw.w.Write(parquetMagic)
+	return err
+}
+
+func (pw *parquetWriter) writeColumn(buffer *parquetColumnBuffer) (chunk parquetColumnChunk, err error) {
+	var page bytes.Buffer
+
+	// definition levels
+	levels := encodeDefinitionLevels(buffer.defined)
+	binary.Write(&page, binary.LittleEndian, uint32(len(levels)))
+	page.Write(levels)
+	page.Write(buffer.data.Bytes())
+
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	_, err = gz.Write(page.Bytes())
+	if err != nil {
+		return
+	}
+
+	err = gz.Close()
+	if err != nil {
+		return
+	}
+
+	var header thriftWriter
+	header.StructBegin()
+	header.FieldI32(1, parquetPageTypeData)
+	header.FieldI32(2, int32(page.Len()))
+	header.FieldI32(3, int32(compressed.Len()))
+	header.FieldStructBegin(5)
+	header.FieldI32(1, int32(len(buffer.defined)))
+	header.FieldI32(2, parquetEncodingPlain)
+	header.FieldI32(3, parquetEncodingRLE)
+	header.FieldI32(4, parquetEncodingRLE)
+	header.StructEnd()
+	header.StructEnd()
+
+	chunk.offset = pw.w.n
+	chunk.numValues = int64(len(buffer.defined))
+	chunk.uncompressedSize = int64(header.Len() + page.Len())
+	chunk.compressedSize = int64(header.Len() + compressed.Len())
+
+	_, err = pw.w.Write(header.Bytes())
+	if err != nil {
+		return
+	}
+
+	_, err = pw.w.Write(compressed.Bytes())
+	return
+}
+
+func (pw *parquetWriter) writeFileMetaData(t *thriftWriter) {
+	t.StructBegin()
+	t.FieldI32(1, 1)
+
+	// schema: root element followed by columns
+	t.FieldListBegin(2, thriftTypeStruct, len(pw.columns)+1)
+	t.StructBegin()
+	t.FieldI32(3, parquetRepetitionRequired)
+	t.FieldString(4, "schema")
+	t.FieldI32(5, int32(len(pw.columns)))
+	t.StructEnd()
+	for _, column := range pw.columns {
+		t.StructBegin()
+		t.FieldI32(1, column.Type)
+		t.FieldI32(3, parquetRepetitionOptional)
+		t.FieldString(4, column.Name)
+		if column.Type == parquetTypeByteArray {
+			t.FieldI32(6, parquetConvertedTypeUTF8)
+		}
+		t.StructEnd()
+	}
+
+	t.FieldI64(3, pw.rows)
+
+	t.FieldListBegin(4, thriftTypeStruct, len(pw.groups))
+	for _, group := range pw.groups {
+		t.StructBegin()
+		t.FieldListBegin(1, thriftTypeStruct, len(pw.columns))
+		for i, column := range pw.columns {
+			chunk := group.chunks[i]
+			t.StructBegin()
+			t.FieldI64(2, chunk.offset)
+			t.FieldStructBegin(3)
+			t.FieldI32(1, column.Type)
+			t.FieldListBegin(2, thriftTypeI32, 2)
+			t.I32(parquetEncodingPlain)
+			t.I32(parquetEncodingRLE)
+			t.FieldListBegin(3, thriftTypeBinary, 1)
+			t.String(column.Name)
+			t.FieldI32(4, parquetCodecGzip)
+			t.FieldI64(5, chunk.numValues)
+			t.FieldI64(6, chunk.uncompressedSize)
+			t.FieldI64(7, chunk.compressedSize)
+			t.FieldI64(9, chunk.offset)
+			t.StructEnd()
+			t.StructEnd()
+		}
+		t.FieldI64(2, group.totalSize)
+		t.FieldI64(3, group.rows)
+		t.StructEnd()
+	}
+
+	t.FieldString(6, "horizon archive")
+	t.StructEnd()
+}
+
+// encodeDefinitionLevels encodes definition levels (0 - null, 1 - defined) using
+// RLE runs of the RLE/bit-packing hybrid encoding with bit width 1.
+func encodeDefinitionLevels(defined []bool) []byte {
+	var result bytes.Buffer
+	for i := 0; i < len(defined); {
+		level := defined[i]
+		run := 1
+		for i+run < len(defined) && defined[i+run] == level {
+			run++
+		}
+
+		writeUvarint(&result, uint64(run)<<1)
+		if level {
+			result.WriteByte(1)
+		} else {
+			result.WriteByte(0)
+		}
+		i += run
+	}
+	return result.Bytes()
+}
+
+func writeUvarint(buf *bytes.Buffer, value uint64) {
+	var raw [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(raw[:], value)
+	buf.Write(raw[:n])
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+// thrift compact protocol types
+const (
+	thriftTypeI32    = 5
+	thriftTypeI64    = 6
+	thriftTypeBinary = 8
+	thriftTypeList   = 9
+	thriftTypeStruct = 12
+)
+
+// thriftWriter encodes structs using thrift compact protocol
+type thriftWriter struct {
+	bytes.Buffer
+	lastField  int16
+	fieldStack []int16
+}
+
+func (t *thriftWriter) StructBegin() {
+	t.fieldStack = append(t.fieldStack, t.lastField)
+	t.lastField = 0
+}
+
+func (t *thriftWriter) StructEnd() {
+	t.WriteByte(0)
+	t.lastField = t.fieldStack[len(t.fieldStack)-1]
+	t.fieldStack = t.fieldStack[:len(t.fieldStack)-1]
+}
+
+func (t *thriftWriter) fieldHeader(id int16, typ byte) {
+	delta := id - t.lastField
+	if delta > 0 && delta <= 15 {
+		t.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		t.WriteByte(typ)
+		writeUvarint(&t.Buffer, zigzag(int64(id)))
+	}
+	t.lastField = id
+}
+
+func (t *thriftWriter) FieldI32(id int16, value int32) {
+	t.fieldHeader(id, thriftTypeI32)
+	t.I32(value)
+}
+
+func (t *thriftWriter) FieldI64(id int16, value int64) {
+	t.fieldHeader(id, thriftTypeI64)
+	writeUvarint(&t.Buffer, zigzag(value))
+}
+
+func (t *thriftWriter) FieldString(id int16, value string) {
+	t.fieldHeader(id, thriftTypeBinary)
+	t.String(value)
+}
+
+func (t *thriftWriter) FieldStructBegin(id int16) {
+	t.fieldHeader(id, thriftTypeStruct)
+	t.StructBegin()
+}
+
+func (t *thriftWriter) FieldListBegin(id int16, elemType byte, size int) {
+	t.fieldHeader(id, thriftTypeList)
+	if size < 15 {
+		t.WriteByte(byte(size)<<4 | elemType)
+		return
+	}
+	t.WriteByte(0xf0 | elemType)
+	writeUvarint(&t.Buffer, uint64(size))
+}
+
+func (t *thriftWriter) I32(value int32) {
+	writeUvarint(&t.Buffer, zigzag(int64(value)))
+}
+
+func (t *thriftWriter) String(value string) {
+	writeUvarint(&t.Buffer, uint64(len(value)))
+	t.WriteString(value)
+}
+
+func zigzag(value int64) uint64 {
+	return uint64((value << 1) ^ (value >> 63))
+}
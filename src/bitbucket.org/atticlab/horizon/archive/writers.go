@@ -0,0 +1,95 @@
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+)
+
+// rowWriter writes rows, encoded as json objects, into the file of specific format
+type rowWriter interface {
+	Write(row []byte) error
+	Close() error
+}
+
+// jsonlWriter writes rows as gzipped json lines
+type jsonlWriter struct {
+	gz *gzip.Writer
+}
+
+func newJSONLWriter(w io.Writer) *jsonlWriter {
+	return &jsonlWriter{
+		gz: gzip.NewWriter(w),
+	}
+}
+
+func (w *jsonlWriter) Write(row []byte) error {
+	_, err := w.gz.Write(row)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.gz.Write([]byte{'\n'})
+	return err
+}
+
+func (w *jsonlWriter) Close() error {
+	return w.gz.Close()
+}
+
+// parquetRowWriter converts json rows into columns of parquet file
+type parquetRowWriter struct {
+	columns []parquetColumn
+	pw      *parquetWriter
+}
+
+func newParquetRowWriter(w io.Writer, columns []parquetColumn) *parquetRowWriter {
+	return &parquetRowWriter{
+		columns: columns,
+		pw:      newParquetWriter(w, columns),
+	}
+}
+
+func (w *parquetRowWriter) Write(row []byte) error {
+	var fields map[string]json.RawMessage
+	err := json.Unmarshal(row, &fields)
+	if err != nil {
+		return err
+	}
+
+	values := make([]*string, len(w.columns))
+	for i, column := range w.columns {
+		values[i], err = jsonToString(fields[column.Name])
+		if err != nil {
+			return err
+		}
+	}
+
+	return w.pw.Append(values)
+}
+
+func (w *parquetRowWriter) Close() error {
+	return w.pw.Close()
+}
+
+// jsonToString converts json value into string. Strings are unquoted, objects,
+// arrays, numbers and booleans are kept as json text. Returns nil for null.
+func jsonToString(raw json.RawMessage) (*string, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return nil, nil
+	}
+
+	var result string
+	if raw[0] == '"' {
+		err := json.Unmarshal(raw, &result)
+		if err != nil {
+			return nil, err
+		}
+		return &result, nil
+	}
+
+	result = string(raw)
+	return &result, nil
+}
@@ -34,6 +34,12 @@ func NewHistoryAsset(db history.QInterface) *HistoryAsset {
 	}
 }
 
+// InvalidateHistoryAssets removes all assets from the cache, so changes of the
+// assets are seen on next lookup
+func InvalidateHistoryAssets() {
+	getHistoryAssetCache().Flush()
+}
+
 // Get looks up the history.Asset for the given xdr.Asset.
 func (c *HistoryAsset) Get(asset xdr.Asset) (*history.Asset, error) {
 	var typ xdr.AssetType
@@ -37,11 +37,14 @@ func TestHistoryAsset(t *testing.T) {
 	})
 	Convey("Get assets:", t, func() {
 		expected := history.Asset{
-			Id:          1,
-			Type:        int(xdr.AssetTypeAssetTypeCreditAlphanum4),
-			Code:        "UAH",
-			Issuer:      config.BankMasterKey,
-			IsAnonymous: false,
+			Id:                    1,
+			Type:                  int(xdr.AssetTypeAssetTypeCreditAlphanum4),
+			Code:                  "UAH",
+			Issuer:                config.BankMasterKey,
+			IsAnonymous:           false,
+			Decimals:              history.DefaultAssetDecimals,
+			Status:                history.AssetStatusActive,
+			RegulatoryFlagsString: "[]",
 		}
 		storedAsset, err := c.Get(xdrAsset)
 		So(err, ShouldBeNil)
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"log"
+
+	"bitbucket.org/atticlab/horizon/archive"
+	"bitbucket.org/atticlab/horizon/db2"
+	hlog "bitbucket.org/atticlab/horizon/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "export ledger history into archive",
+	Long:  "export writes ingested history of the ledger range into chunked files with a manifest and checksums",
+	Run: func(cmd *cobra.Command, args []string) {
+		from, _ := cmd.Flags().GetInt32("from-ledger")
+		to, _ := cmd.Flags().GetInt32("to-ledger")
+		format, _ := cmd.Flags().GetString("format")
+		out, _ := cmd.Flags().GetString("out")
+		chunkSize, _ := cmd.Flags().GetInt32("chunk-size")
+
+		if from <= 0 || to < from {
+			cmd.Usage()
+			log.Fatal("invalid ledger range")
+		}
+
+		db, err := db2.Open(viper.GetString("db-url"))
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		exporter, err := archive.NewExporter(db, out, format, chunkSize)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		manifest, err := exporter.Export(from, to)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		hlog.WithField("chunks", len(manifest.Chunks)).Infof("export: complete, archive written to %s", out)
+	},
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "import ledger history from archive",
+	Long:  "import populates an empty history db from the archive created by export. Only jsonl archives can be imported.",
+	Run: func(cmd *cobra.Command, args []string) {
+		in, _ := cmd.Flags().GetString("in")
+
+		db, err := db2.Open(viper.GetString("db-url"))
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		manifest, err := archive.NewImporter(db, in).Import()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		hlog.WithField("chunks", len(manifest.Chunks)).Infof(
+			"import: complete, ledgers %d-%d imported", manifest.FromLedger, manifest.ToLedger,
+		)
+	},
+}
+
+func init() {
+	exportCmd.Flags().Int32("from-ledger", 1, "first ledger to export")
+	exportCmd.Flags().Int32("to-ledger", 0, "last ledger to export")
+	exportCmd.Flags().String("format", archive.FormatJSONL, "archive format: jsonl or parquet (parquet archives can't be imported)")
+	exportCmd.Flags().String("out", "archive", "directory the archive is written to")
+	exportCmd.Flags().Int32("chunk-size", archive.DefaultChunkSize, "number of ledgers per chunk")
+
+	importCmd.Flags().String("in", "archive", "directory the archive is read from")
+}
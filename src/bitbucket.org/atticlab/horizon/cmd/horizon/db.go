@@ -108,6 +108,7 @@ var dbReingestCmd = &cobra.Command{
 			Repo: hdb,
 		}, time.Duration(1)*time.Minute, time.Duration(10)*time.Second)
 		i := ingest.New(passphrase, cdb, hdb, cache)
+		i.Genesis = config.Genesis
 		logStatus := func(stage string) {
 			count := i.Metrics.IngestLedgerTimer.Count()
 			rate := i.Metrics.IngestLedgerTimer.RateMean()
@@ -2,17 +2,22 @@ package main
 
 import (
 	"log"
+	"net"
 	"runtime"
 
 	"bitbucket.org/atticlab/go-smart-base/amount"
 	"bitbucket.org/atticlab/horizon"
+	"bitbucket.org/atticlab/horizon/compliance"
 	conf "bitbucket.org/atticlab/horizon/config"
 	hlog "bitbucket.org/atticlab/horizon/log"
+	"bitbucket.org/atticlab/horizon/resource"
+	"bitbucket.org/atticlab/horizon/retention"
 	"github.com/PuerkitoBio/throttled"
 	"github.com/Sirupsen/logrus"
 	"github.com/joho/godotenv"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"strings"
 	"time"
 )
 
@@ -36,25 +41,73 @@ func init() {
 	viper.SetDefault("autopump", false)
 
 	viper.BindEnv("port", "PORT")
+	viper.BindEnv("admin-port", "ADMIN_PORT")
 	viper.BindEnv("autopump", "AUTOPUMP")
 	viper.BindEnv("db-url", "DATABASE_URL")
 	viper.BindEnv("stellar-core-db-url", "STELLAR_CORE_DATABASE_URL")
 	viper.BindEnv("stellar-core-url", "STELLAR_CORE_URL")
 	viper.BindEnv("friendbot-secret", "FRIENDBOT_SECRET")
+	viper.BindEnv("friendbot-pool-secrets", "FRIENDBOT_POOL_SECRETS")
+	viper.BindEnv("friendbot-account-types", "FRIENDBOT_ACCOUNT_TYPES")
+	viper.BindEnv("friendbot-amount", "FRIENDBOT_AMOUNT")
+	viper.BindEnv("friendbot-quota-per-ip", "FRIENDBOT_QUOTA_PER_IP")
+	viper.BindEnv("friendbot-quota-per-address", "FRIENDBOT_QUOTA_PER_ADDRESS")
+	viper.BindEnv("friendbot-quota-window", "FRIENDBOT_QUOTA_WINDOW")
+	viper.BindEnv("friendbot-trusted-proxies", "FRIENDBOT_TRUSTED_PROXIES")
 	viper.BindEnv("per-hour-rate-limit", "PER_HOUR_RATE_LIMIT")
 	viper.BindEnv("redis-url", "REDIS_URL")
 	viper.BindEnv("ruby-horizon-url", "RUBY_HORIZON_URL")
 	viper.BindEnv("log-level", "LOG_LEVEL")
+	viper.BindEnv("log-service-levels", "LOG_SERVICE_LEVELS")
+	viper.BindEnv("log-format", "LOG_FORMAT")
 	viper.BindEnv("sentry-dsn", "SENTRY_DSN")
 	viper.BindEnv("loggly-token", "LOGGLY_TOKEN")
 	viper.BindEnv("loggly-host", "LOGGLY_HOST")
 	viper.BindEnv("tls-cert", "TLS_CERT")
 	viper.BindEnv("tls-key", "TLS_KEY")
 	viper.BindEnv("ingest", "INGEST")
+	viper.BindEnv("ingest-leader-election", "INGEST_LEADER_ELECTION")
+	viper.BindEnv("ingest-leader-ttl", "INGEST_LEADER_TTL")
 	viper.BindEnv("network-passphrase", "NETWORK_PASSPHRASE")
 	viper.BindEnv("bank-master-key", "BANK_MASTER_KEY")
 	viper.BindEnv("general-agent-key", "GENERAL_AGENT_KEY")
 	viper.BindEnv("bank-commission-key", "BANK_COMMISSION_KEY")
+	viper.BindEnv("genesis-file", "GENESIS_FILE")
+
+	viper.BindEnv("retention-effects-days", "RETENTION_EFFECTS_DAYS")
+	viper.BindEnv("retention-operations-days", "RETENTION_OPERATIONS_DAYS")
+	viper.BindEnv("retention-transactions-days", "RETENTION_TRANSACTIONS_DAYS")
+	viper.BindEnv("retention-batch-size", "RETENTION_BATCH_SIZE")
+	viper.BindEnv("retention-interval", "RETENTION_INTERVAL")
+	viper.BindEnv("retention-export-dir", "RETENTION_EXPORT_DIR")
+
+	viper.BindEnv("screening-list", "SCREENING_LIST")
+	viper.BindEnv("screening-list-file", "SCREENING_LIST_FILE")
+	viper.BindEnv("screening-url", "SCREENING_URL")
+	viper.BindEnv("screening-timeout", "SCREENING_TIMEOUT")
+	viper.BindEnv("screening-fail-closed", "SCREENING_FAIL_CLOSED")
+
+	viper.BindEnv("stats-backend", "STATS_BACKEND")
+	viper.BindEnv("txsub-backend", "TXSUB_BACKEND")
+	viper.BindEnv("tracing-exporter", "TRACING_EXPORTER")
+	viper.BindEnv("tracing-otlp-endpoint", "TRACING_OTLP_ENDPOINT")
+	viper.BindEnv("tracing-file", "TRACING_FILE")
+	viper.BindEnv("tracing-sample-ratio", "TRACING_SAMPLE_RATIO")
+	viper.BindEnv("health-max-ingestion-lag", "HEALTH_MAX_INGESTION_LAG")
+	viper.BindEnv("health-check-timeout", "HEALTH_CHECK_TIMEOUT")
+	viper.BindEnv("shutdown-timeout", "SHUTDOWN_TIMEOUT")
+	viper.BindEnv("redis-sentinel-addrs", "REDIS_SENTINEL_ADDRS")
+	viper.BindEnv("redis-sentinel-master", "REDIS_SENTINEL_MASTER")
+	viper.BindEnv("redis-cluster-addrs", "REDIS_CLUSTER_ADDRS")
+	viper.BindEnv("redis-degrade-to-postgres", "REDIS_DEGRADE_TO_POSTGRES")
+	viper.BindEnv("redis-health-interval", "REDIS_HEALTH_INTERVAL")
+
+	viper.BindEnv("compliance-alerts", "COMPLIANCE_ALERTS")
+	viper.BindEnv("compliance-structuring-count", "COMPLIANCE_STRUCTURING_COUNT")
+	viper.BindEnv("compliance-structuring-window", "COMPLIANCE_STRUCTURING_WINDOW")
+	viper.BindEnv("compliance-rapid-flow-window", "COMPLIANCE_RAPID_FLOW_WINDOW")
+	viper.BindEnv("compliance-spike-factor", "COMPLIANCE_SPIKE_FACTOR")
+	viper.BindEnv("compliance-spike-min-amount", "COMPLIANCE_SPIKE_MIN_AMOUNT")
 
 	viper.BindEnv("restrictions-anonymous-user-max-daily-outcome", "RESTRICTIONS_ANONYMOUS_USER_MAX_DAILY_OUTCOME")
 	viper.BindEnv("restrictions-anonymous-user-max-monthly-outcome", "RESTRICTIONS_ANONYMOUS_USER_MAX_MONTHLY_OUTCOME")
@@ -96,6 +149,12 @@ func init() {
 		"tcp port to listen on for http requests",
 	)
 
+	rootCmd.Flags().Int(
+		"admin-port",
+		0,
+		"tcp port to serve prometheus metrics, log levels and health checks on, disabled if zero",
+	)
+
 	rootCmd.Flags().Bool(
 		"autopump",
 		false,
@@ -111,7 +170,7 @@ func init() {
 	rootCmd.Flags().String(
 		"redis-url",
 		"",
-		"redis to connect with, for rate limiting",
+		"redis to connect with, for rate limiting and statistics. In sentinel and cluster modes only password is used",
 	)
 
 	rootCmd.Flags().String(
@@ -120,6 +179,18 @@ func init() {
 		"Minimum log severity (debug, info, warn, error) to log",
 	)
 
+	rootCmd.Flags().String(
+		"log-service-levels",
+		"",
+		"Comma separated log levels of services overriding log-level, e.g. statistics_manager=debug,submitter=info",
+	)
+
+	rootCmd.Flags().String(
+		"log-format",
+		"text",
+		"Format of logs: text or json",
+	)
+
 	rootCmd.Flags().String(
 		"sentry-dsn",
 		"",
@@ -144,6 +215,48 @@ func init() {
 		"Secret seed for friendbot functionality. When empty, friendbot will be disabled",
 	)
 
+	rootCmd.Flags().String(
+		"friendbot-pool-secrets",
+		"",
+		"Comma separated secret seeds of additional friendbot funding accounts. Concurrent requests are sent from different accounts",
+	)
+
+	rootCmd.Flags().String(
+		"friendbot-account-types",
+		"anonymous_user,registered_user",
+		"Comma separated types of accounts friendbot may create, the first one is the default",
+	)
+
+	rootCmd.Flags().String(
+		"friendbot-amount",
+		"100",
+		"Amount of the asset friendbot sends on request",
+	)
+
+	rootCmd.Flags().Int(
+		"friendbot-quota-per-ip",
+		0,
+		"Number of friendbot requests allowed from one IP during friendbot-quota-window, not limited if zero",
+	)
+
+	rootCmd.Flags().Int(
+		"friendbot-quota-per-address",
+		0,
+		"Number of friendbot requests allowed for one account during friendbot-quota-window, not limited if zero",
+	)
+
+	rootCmd.Flags().Int(
+		"friendbot-quota-window",
+		86400,
+		"Number of seconds friendbot quotas are counted over",
+	)
+
+	rootCmd.Flags().String(
+		"friendbot-trusted-proxies",
+		"",
+		"Comma separated IPs or CIDR networks of proxies, which X-Forwarded-For header is trusted to count friendbot-quota-per-ip",
+	)
+
 	rootCmd.Flags().String(
 		"tls-cert",
 		"",
@@ -162,6 +275,18 @@ func init() {
 		"causes this horizon process to ingest data from stellar-core into horizon's db",
 	)
 
+	rootCmd.Flags().String(
+		"ingest-leader-election",
+		conf.IngestLeaderElectionNone,
+		"Lease electing the only ingesting process among horizon replicas with ingestion enabled: postgres or redis. Disabled if empty",
+	)
+
+	rootCmd.Flags().Int(
+		"ingest-leader-ttl",
+		10,
+		"Number of seconds ingestion leader holds the lease without renewal. Another replica takes over ingestion within this time after the leader stops",
+	)
+
 	rootCmd.Flags().String(
 		"network-passphrase",
 		"",
@@ -186,6 +311,211 @@ func init() {
 		"Bank's commission key",
 	)
 
+	rootCmd.Flags().String(
+		"genesis-file",
+		"",
+		"JSON file listing system accounts and assets stored on ingestion of the first ledger, and anonymous restrictions of assets. "+
+			"If empty, bank, general agent and commission accounts and anonymous EUAH asset are stored",
+	)
+
+	// Retention policy
+
+	rootCmd.Flags().Int(
+		"retention-effects-days",
+		0,
+		"Number of days effects are kept for. 0 - forever",
+	)
+
+	rootCmd.Flags().Int(
+		"retention-operations-days",
+		0,
+		"Number of days operations are kept for. 0 - forever",
+	)
+
+	rootCmd.Flags().Int(
+		"retention-transactions-days",
+		0,
+		"Number of days transactions are kept for. 0 - forever",
+	)
+
+	rootCmd.Flags().Int(
+		"retention-batch-size",
+		retention.DefaultBatchSize,
+		"Number of ledgers pruned in a single db transaction",
+	)
+
+	rootCmd.Flags().Int(
+		"retention-interval",
+		int(retention.DefaultInterval/time.Minute),
+		"Number of minutes between pruning sessions",
+	)
+
+	rootCmd.Flags().String(
+		"retention-export-dir",
+		"",
+		"Directory pruned history is exported to. When empty, pruned history is not exported",
+	)
+
+	// Screening
+
+	rootCmd.Flags().Bool(
+		"screening-list",
+		false,
+		"Screen counterparties of payments against the screening list managed by admin operations",
+	)
+
+	rootCmd.Flags().String(
+		"screening-list-file",
+		"",
+		"File with additional addresses of the screening list, one per line",
+	)
+
+	rootCmd.Flags().String(
+		"screening-url",
+		"",
+		"URL of the external screening service. When empty, the service is not called",
+	)
+
+	rootCmd.Flags().Int(
+		"screening-timeout",
+		2000,
+		"Number of milliseconds to wait for the external screening service response",
+	)
+
+	rootCmd.Flags().Bool(
+		"screening-fail-closed",
+		false,
+		"Reject payments when the external screening service is unavailable",
+	)
+
+	rootCmd.Flags().String(
+		"stats-backend",
+		conf.StatisticsBackendRedis,
+		"Storage of account statistics updated with submitted payments: redis or postgres. Redis is not required for postgres, if rate limiting is disabled",
+	)
+
+	rootCmd.Flags().String(
+		"txsub-backend",
+		conf.TxSubBackendMemory,
+		"Storage of open submissions and sequence reservations: memory, redis or postgres. Must be shared, if several horizon instances submit transactions",
+	)
+
+	// Tracing
+
+	rootCmd.Flags().String(
+		"tracing-exporter",
+		"",
+		"Exporter of trace spans: otlp, stdout or file. Tracing is disabled if empty",
+	)
+
+	rootCmd.Flags().String(
+		"tracing-otlp-endpoint",
+		"http://localhost:4318",
+		"Base url of OpenTelemetry collector accepting OTLP/HTTP, used by otlp exporter",
+	)
+
+	rootCmd.Flags().String(
+		"tracing-file",
+		"traces.json",
+		"File trace spans are appended to by file exporter",
+	)
+
+	rootCmd.Flags().Float64(
+		"tracing-sample-ratio",
+		1,
+		"Share (0..1) of traces started by horizon, which are exported. Incoming traces follow the decision of the caller",
+	)
+
+	// Health checks
+
+	rootCmd.Flags().Int(
+		"health-max-ingestion-lag",
+		10,
+		"Number of ledgers history may fall behind stellar-core before /ready reports instance as not ready, not checked if zero",
+	)
+
+	rootCmd.Flags().Int(
+		"health-check-timeout",
+		2000,
+		"Number of milliseconds to wait for each dependency checked by /ready",
+	)
+
+	rootCmd.Flags().Int(
+		"shutdown-timeout",
+		15,
+		"Number of seconds shutdown waits for open transaction submissions and ingestion to finish",
+	)
+
+	// Redis high availability
+
+	rootCmd.Flags().String(
+		"redis-sentinel-addrs",
+		"",
+		"Comma separated host:port list of Redis Sentinels. When set, master is discovered via sentinels",
+	)
+
+	rootCmd.Flags().String(
+		"redis-sentinel-master",
+		"mymaster",
+		"Name of the master monitored by Redis Sentinels",
+	)
+
+	rootCmd.Flags().String(
+		"redis-cluster-addrs",
+		"",
+		"Comma separated host:port list of Redis Cluster nodes. When set, Redis Cluster is used",
+	)
+
+	rootCmd.Flags().Bool(
+		"redis-degrade-to-postgres",
+		false,
+		"Keep statistics and shared txsub state in history db and skip rate limiting while Redis is unavailable",
+	)
+
+	rootCmd.Flags().Int(
+		"redis-health-interval",
+		5,
+		"Number of seconds between Redis health checks",
+	)
+
+	// Compliance alerts
+
+	rootCmd.Flags().Bool(
+		"compliance-alerts",
+		false,
+		"Check ingested payments for suspicious activity and create compliance alerts",
+	)
+
+	rootCmd.Flags().Int(
+		"compliance-structuring-count",
+		compliance.DefaultStructuringCount,
+		"Number of payments just under the operation limit, which raises a structuring alert",
+	)
+
+	rootCmd.Flags().Int(
+		"compliance-structuring-window",
+		int(compliance.DefaultStructuringWindow/time.Hour),
+		"Number of hours payments just under the operation limit are counted for",
+	)
+
+	rootCmd.Flags().Int(
+		"compliance-rapid-flow-window",
+		int(compliance.DefaultRapidFlowWindow/time.Minute),
+		"Number of minutes within which funds passed through an anonymous account raise an alert",
+	)
+
+	rootCmd.Flags().Float64(
+		"compliance-spike-factor",
+		compliance.DefaultSpikeFactor,
+		"Ratio of daily volume to average daily volume, which raises a volume spike alert",
+	)
+
+	rootCmd.Flags().String(
+		"compliance-spike-min-amount",
+		"1000.0",
+		"Daily volume below this amount never raises a volume spike alert",
+	)
+
 	// User restrictions
 
 	rootCmd.Flags().String(
@@ -219,6 +549,8 @@ func init() {
 	)
 
 	rootCmd.AddCommand(dbCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(importCmd)
 
 	viper.BindPFlags(rootCmd.Flags())
 }
@@ -253,6 +585,16 @@ func initConfig() {
 		log.Fatalf("Could not parse log-level: %v", viper.GetString("log-level"))
 	}
 
+	serviceLevels, err := hlog.ParseServiceLevels(viper.GetString("log-service-levels"))
+	if err != nil {
+		log.Fatalf("Could not parse log-service-levels: %v", err)
+	}
+
+	logFormat := viper.GetString("log-format")
+	if logFormat != "" && logFormat != "text" && logFormat != "json" {
+		log.Fatalf("Invalid config: unknown log-format %s. Must be text or json.", logFormat)
+	}
+
 	hlog.DefaultLogger.Level = ll
 
 	cert, key := viper.GetString("tls-cert"), viper.GetString("tls-key")
@@ -279,6 +621,22 @@ func initConfig() {
 		adminSigValid = 60
 	}
 
+	statisticsBackend := viper.GetString("stats-backend")
+	if statisticsBackend == "" {
+		statisticsBackend = conf.StatisticsBackendRedis
+	}
+	if statisticsBackend != conf.StatisticsBackendRedis && statisticsBackend != conf.StatisticsBackendPostgres {
+		log.Fatalf("Invalid config: unknown stats-backend %s. Must be redis or postgres.", statisticsBackend)
+	}
+
+	txsubBackend := viper.GetString("txsub-backend")
+	if txsubBackend == "" {
+		txsubBackend = conf.TxSubBackendMemory
+	}
+	if txsubBackend != conf.TxSubBackendMemory && txsubBackend != conf.TxSubBackendRedis && txsubBackend != conf.TxSubBackendPostgres {
+		log.Fatalf("Invalid config: unknown txsub-backend %s. Must be memory, redis or postgres.", txsubBackend)
+	}
+
 	statisticsTimeout := viper.GetInt("stats-timeout")
 	if statisticsTimeout == 0 {
 		statisticsTimeout = 60
@@ -289,28 +647,222 @@ func initConfig() {
 		processedOpTimeout = statisticsTimeout / 2
 	}
 
+	genesis := getGenesis()
+
 	config = conf.Config{
 		DatabaseURL:               viper.GetString("db-url"),
 		StellarCoreDatabaseURL:    viper.GetString("stellar-core-db-url"),
 		StellarCoreURL:            viper.GetString("stellar-core-url"),
 		Autopump:                  viper.GetBool("autopump"),
 		Port:                      viper.GetInt("port"),
+		AdminPort:                 viper.GetInt("admin-port"),
 		RateLimit:                 getRateLimit(),
 		RedisURL:                  viper.GetString("redis-url"),
 		LogLevel:                  ll,
+		LogServiceLevels:          serviceLevels,
+		LogJSON:                   logFormat == "json",
 		SentryDSN:                 viper.GetString("sentry-dsn"),
 		LogglyToken:               viper.GetString("loggly-token"),
 		LogglyHost:                viper.GetString("loggly-host"),
 		FriendbotSecret:           viper.GetString("friendbot-secret"),
+		Friendbot:                 getFriendbotConfig(),
 		TLSCert:                   cert,
 		TLSKey:                    key,
 		Ingest:                    viper.GetBool("ingest"),
+		IngestLeader:              getIngestLeaderConfig(),
 		BankMasterKey:             viper.GetString("bank-master-key"),
 		BankCommissionKey:         viper.GetString("bank-commission-key"),
 		AnonymousUserRestrictions: getAnonymousUserRestrictions(),
+		Genesis:                   genesis,
+		AssetRestrictions:         genesis.AnonymousAssetRestrictions(),
 		AdminSignatureValid:       time.Duration(adminSigValid) * time.Second,
+		StatisticsBackend:         statisticsBackend,
+		TxSubBackend:              txsubBackend,
 		StatisticsTimeout:         time.Duration(statisticsTimeout) * time.Second,
 		ProcessedOpTimeout:        time.Duration(processedOpTimeout) * time.Second,
+		Retention:                 getRetentionPolicy(),
+		Screening:                 getScreeningConfig(),
+		Compliance:                getComplianceConfig(),
+		Redis:                     getRedisConfig(),
+		Tracing:                   getTracingConfig(),
+		Health:                    getHealthConfig(),
+		ShutdownTimeout:           time.Duration(viper.GetInt("shutdown-timeout")) * time.Second,
+	}
+}
+
+func getIngestLeaderConfig() conf.IngestLeaderConfig {
+	result := conf.IngestLeaderConfig{
+		Election: viper.GetString("ingest-leader-election"),
+		LeaseTTL: time.Duration(viper.GetInt("ingest-leader-ttl")) * time.Second,
+	}
+
+	switch result.Election {
+	case conf.IngestLeaderElectionNone, conf.IngestLeaderElectionPostgres, conf.IngestLeaderElectionRedis:
+	default:
+		log.Fatalf("Invalid config: unknown ingest-leader-election %s. Must be postgres or redis.", result.Election)
+	}
+
+	if result.IsEnabled() && result.LeaseTTL < 3*time.Second {
+		log.Fatal("Invalid config: ingest-leader-ttl must be at least 3 seconds")
+	}
+
+	return result
+}
+
+func getFriendbotConfig() conf.FriendbotConfig {
+	result := conf.FriendbotConfig{
+		PoolSecrets:     splitList(viper.GetString("friendbot-pool-secrets")),
+		QuotaPerIP:      viper.GetInt("friendbot-quota-per-ip"),
+		QuotaPerAddress: viper.GetInt("friendbot-quota-per-address"),
+		QuotaWindow:     time.Duration(viper.GetInt("friendbot-quota-window")) * time.Second,
+	}
+
+	for _, name := range splitList(viper.GetString("friendbot-account-types")) {
+		accountType, ok := resource.AccountTypeByName(name)
+		if !ok {
+			log.Fatalf("Invalid config: unknown friendbot-account-types %s", name)
+		}
+		result.AccountTypes = append(result.AccountTypes, accountType)
+	}
+
+	var err error
+	result.Amount, err = parseAmount(viper.GetString("friendbot-amount"))
+	if err != nil || result.Amount <= 0 {
+		log.Fatalf("Invalid config: friendbot-amount must be positive amount: %s", viper.GetString("friendbot-amount"))
+	}
+
+	if result.QuotaPerIP < 0 || result.QuotaPerAddress < 0 {
+		log.Fatal("Invalid config: friendbot quotas must not be negative")
+	}
+
+	if (result.QuotaPerIP != 0 || result.QuotaPerAddress != 0) && result.QuotaWindow <= 0 {
+		log.Fatal("Invalid config: friendbot-quota-window must be positive")
+	}
+
+	for _, proxy := range splitList(viper.GetString("friendbot-trusted-proxies")) {
+		if !strings.Contains(proxy, "/") {
+			if strings.Contains(proxy, ":") {
+				proxy += "/128"
+			} else {
+				proxy += "/32"
+			}
+		}
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			log.Fatalf("Invalid config: friendbot-trusted-proxies: %s", err)
+		}
+		result.TrustedProxies = append(result.TrustedProxies, network)
+	}
+
+	return result
+}
+
+func getHealthConfig() conf.HealthConfig {
+	maxLag := viper.GetInt("health-max-ingestion-lag")
+	if maxLag < 0 {
+		log.Fatal("Invalid config: health-max-ingestion-lag must not be negative")
+	}
+
+	timeout := viper.GetInt("health-check-timeout")
+	if timeout <= 0 {
+		log.Fatal("Invalid config: health-check-timeout must be positive")
+	}
+
+	return conf.HealthConfig{
+		MaxIngestionLag: int32(maxLag),
+		CheckTimeout:    time.Duration(timeout) * time.Millisecond,
+	}
+}
+
+func getTracingConfig() conf.TracingConfig {
+	result := conf.TracingConfig{
+		Exporter:     viper.GetString("tracing-exporter"),
+		OTLPEndpoint: viper.GetString("tracing-otlp-endpoint"),
+		File:         viper.GetString("tracing-file"),
+		SampleRatio:  viper.GetFloat64("tracing-sample-ratio"),
+		ServiceName:  "horizon",
+	}
+
+	if result.SampleRatio < 0 || result.SampleRatio > 1 {
+		log.Fatal("Invalid config: tracing-sample-ratio must be between 0 and 1")
+	}
+
+	switch result.Exporter {
+	case conf.TracingExporterNone, conf.TracingExporterStdout:
+	case conf.TracingExporterOTLP:
+		if result.OTLPEndpoint == "" {
+			log.Fatal("Invalid config: tracing-otlp-endpoint is required for otlp exporter")
+		}
+	case conf.TracingExporterFile:
+		if result.File == "" {
+			log.Fatal("Invalid config: tracing-file is required for file exporter")
+		}
+	default:
+		log.Fatalf("Invalid config: unknown tracing-exporter %s. Must be otlp, stdout or file.", result.Exporter)
+	}
+
+	return result
+}
+
+func getRetentionPolicy() conf.RetentionPolicy {
+	day := 24 * time.Hour
+	return conf.RetentionPolicy{
+		Effects:      time.Duration(viper.GetInt("retention-effects-days")) * day,
+		Operations:   time.Duration(viper.GetInt("retention-operations-days")) * day,
+		Transactions: time.Duration(viper.GetInt("retention-transactions-days")) * day,
+		BatchSize:    int32(viper.GetInt("retention-batch-size")),
+		Interval:     time.Duration(viper.GetInt("retention-interval")) * time.Minute,
+		ExportDir:    viper.GetString("retention-export-dir"),
+	}
+}
+
+func getScreeningConfig() conf.ScreeningConfig {
+	return conf.ScreeningConfig{
+		ListEnabled: viper.GetBool("screening-list"),
+		ListFile:    viper.GetString("screening-list-file"),
+		URL:         viper.GetString("screening-url"),
+		Timeout:     time.Duration(viper.GetInt("screening-timeout")) * time.Millisecond,
+		FailClosed:  viper.GetBool("screening-fail-closed"),
+	}
+}
+
+func getRedisConfig() conf.RedisConfig {
+	return conf.RedisConfig{
+		SentinelAddrs:       splitList(viper.GetString("redis-sentinel-addrs")),
+		SentinelMaster:      viper.GetString("redis-sentinel-master"),
+		ClusterAddrs:        splitList(viper.GetString("redis-cluster-addrs")),
+		DegradeToPostgres:   viper.GetBool("redis-degrade-to-postgres"),
+		HealthCheckInterval: time.Duration(viper.GetInt("redis-health-interval")) * time.Second,
+	}
+}
+
+// splitList splits comma separated list, skipping empty items
+func splitList(list string) []string {
+	var result []string
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func getComplianceConfig() conf.ComplianceConfig {
+	spikeMinAmount, err := parseAmount(viper.GetString("compliance-spike-min-amount"))
+	if err != nil {
+		log.Fatalf("Could not parse compliance-spike-min-amount: %v", viper.GetString("compliance-spike-min-amount"))
+	}
+
+	return conf.ComplianceConfig{
+		Enabled:           viper.GetBool("compliance-alerts"),
+		StructuringRatio:  compliance.DefaultStructuringRatio,
+		StructuringCount:  viper.GetInt("compliance-structuring-count"),
+		StructuringWindow: time.Duration(viper.GetInt("compliance-structuring-window")) * time.Hour,
+		RapidFlowRatio:    compliance.DefaultRapidFlowRatio,
+		RapidFlowWindow:   time.Duration(viper.GetInt("compliance-rapid-flow-window")) * time.Minute,
+		SpikeFactor:       viper.GetFloat64("compliance-spike-factor"),
+		SpikeMinAmount:    spikeMinAmount,
 	}
 }
 
@@ -369,6 +921,23 @@ func getAnonymousUserRestrictions() conf.AnonymousUserRestrictions {
 	return restrictions
 }
 
+func getGenesis() *conf.Genesis {
+	path := viper.GetString("genesis-file")
+	if path == "" {
+		return conf.DefaultGenesis(
+			viper.GetString("bank-master-key"),
+			viper.GetString("general-agent-key"),
+			viper.GetString("bank-commission-key"),
+		)
+	}
+
+	genesis, err := conf.LoadGenesis(path)
+	if err != nil {
+		log.Fatalf("Invalid config: could not load genesis-file %s: %s", path, err)
+	}
+	return genesis
+}
+
 func parseAmount(strAmount string) (int64, error) {
 	xdrAmount, err := amount.Parse(strAmount)
 	intAmount := int64(xdrAmount)
@@ -0,0 +1,120 @@
+// Package compliance contains rules detecting suspicious activity in payments
+// ingested into the history database.
+package compliance
+
+import (
+	"time"
+
+	"bitbucket.org/atticlab/go-smart-base/xdr"
+	conf "bitbucket.org/atticlab/horizon/config"
+	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/log"
+)
+
+const (
+	DefaultStructuringRatio  = 0.9
+	DefaultStructuringCount  = 3
+	DefaultStructuringWindow = 24 * time.Hour
+	DefaultRapidFlowRatio    = 0.9
+	DefaultRapidFlowWindow   = time.Hour
+	DefaultSpikeFactor       = 5.0
+)
+
+// Payment is a payment or path payment ingested into the history database
+type Payment struct {
+	OperationID  int64
+	From         string
+	FromType     xdr.AccountType
+	To           string
+	ToType       xdr.AccountType
+	SourceAsset  string
+	SourceAmount xdr.Int64
+	DestAsset    string
+	DestAmount   xdr.Int64
+}
+
+// Ledger holds payments ingested in a single ledger
+type Ledger struct {
+	Sequence int32
+	ClosedAt time.Time
+	Payments []Payment
+}
+
+// Rule checks payments of the ledger and returns alerts on suspicious activity
+type Rule interface {
+	// Name returns name of the rule, stored with each alert
+	Name() string
+	// Window returns period, during which alert is not raised again for the same
+	// account and asset while previous one is not acknowledged
+	Window() time.Duration
+	Check(data DataProvider, ledger *Ledger) ([]history.ComplianceAlert, error)
+}
+
+// Engine applies rules to ingested ledgers
+type Engine struct {
+	rules []Rule
+	log   *log.Entry
+}
+
+// NewEngine creates engine with all rules configured by config
+func NewEngine(config conf.ComplianceConfig) *Engine {
+	return NewEngineWithRules(
+		NewStructuringRule(config.StructuringRatio, config.StructuringCount, config.StructuringWindow),
+		NewRapidFlowRule(config.RapidFlowRatio, config.RapidFlowWindow),
+		NewVolumeSpikeRule(config.SpikeFactor, config.SpikeMinAmount),
+	)
+}
+
+// NewEngineWithRules creates engine applying specified rules
+func NewEngineWithRules(rules ...Rule) *Engine {
+	return &Engine{
+		rules: rules,
+		log:   log.WithField("service", "compliance"),
+	}
+}
+
+// Evaluate applies rules to the ledger and returns new alerts. Alerts already
+// raised and not acknowledged within rule's window are skipped. Failure of a
+// rule fails the evaluation, so the ledger is ingested again with its alerts.
+func (e *Engine) Evaluate(data DataProvider, ledger *Ledger) ([]history.ComplianceAlert, error) {
+	if len(ledger.Payments) == 0 {
+		return nil, nil
+	}
+
+	var result []history.ComplianceAlert
+	for _, rule := range e.rules {
+		alerts, err := rule.Check(data, ledger)
+		if err != nil {
+			e.log.WithError(err).WithField("rule", rule.Name()).WithField("ledger", ledger.Sequence).Error("Failed to check rule")
+			return nil, err
+		}
+
+		for _, alert := range alerts {
+			exists, err := data.HasOpenAlert(rule.Name(), alert.Address, alert.AssetCode, ledger.ClosedAt.Add(-rule.Window()))
+			if err != nil {
+				e.log.WithError(err).WithField("rule", rule.Name()).Error("Failed to check existing alerts")
+				return nil, err
+			}
+
+			if exists {
+				continue
+			}
+
+			alert.Rule = rule.Name()
+			alert.LedgerSequence = ledger.Sequence
+			result = append(result, alert)
+		}
+	}
+
+	return result, nil
+}
+
+func newAlert(address, assetCode, description string, operations []int64) (history.ComplianceAlert, error) {
+	alert := history.ComplianceAlert{
+		Address:     address,
+		AssetCode:   assetCode,
+		Description: description,
+	}
+	err := alert.SetOperations(operations)
+	return alert, err
+}
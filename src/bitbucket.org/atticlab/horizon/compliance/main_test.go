@@ -0,0 +1,212 @@
+package compliance
+
+import (
+	"testing"
+	"time"
+
+	"bitbucket.org/atticlab/go-smart-base/amount"
+	"bitbucket.org/atticlab/go-smart-base/xdr"
+	"bitbucket.org/atticlab/horizon/db2/history"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// testProvider serves data from memory
+type testProvider struct {
+	limits     map[string]*history.AccountLimits
+	stats      map[string]history.AccountStatistics
+	payments   []Payment
+	openAlerts map[string]bool
+}
+
+func (p *testProvider) AccountLimits(address, assetCode string) (*history.AccountLimits, error) {
+	return p.limits[address], nil
+}
+
+func (p *testProvider) AccountStatistics(address, assetCode string, now time.Time) (map[xdr.AccountType]history.AccountStatistics, error) {
+	result := make(map[xdr.AccountType]history.AccountStatistics)
+	if stats, ok := p.stats[address]; ok {
+		result[xdr.AccountTypeAccountAnonymousUser] = stats
+	}
+	return result, nil
+}
+
+func (p *testProvider) Payments(query PaymentsQuery) ([]Payment, error) {
+	var result []Payment
+	for _, payment := range p.payments {
+		paymentAmount := payment.DestAmount
+		if query.Source != "" {
+			if payment.From != query.Source {
+				continue
+			}
+			paymentAmount = payment.SourceAmount
+		} else if payment.To != query.Destination {
+			continue
+		}
+
+		if (query.MinAmount > 0 && paymentAmount < query.MinAmount) || (query.MaxAmount > 0 && paymentAmount > query.MaxAmount) {
+			continue
+		}
+		result = append(result, payment)
+	}
+	return result, nil
+}
+
+func (p *testProvider) HasOpenAlert(rule, address, assetCode string, since time.Time) (bool, error) {
+	return p.openAlerts[rule+address], nil
+}
+
+func payment(id int64, from, to string, fromType xdr.AccountType, rawAmount string) Payment {
+	return Payment{
+		OperationID:  id,
+		From:         from,
+		FromType:     fromType,
+		To:           to,
+		ToType:       xdr.AccountTypeAccountRegisteredUser,
+		SourceAsset:  "UAH",
+		SourceAmount: amount.MustParse(rawAmount),
+		DestAsset:    "UAH",
+		DestAmount:   amount.MustParse(rawAmount),
+	}
+}
+
+func TestCompliance(t *testing.T) {
+	closedAt := time.Date(2017, time.March, 11, 12, 0, 0, 0, time.UTC)
+	Convey("Structuring", t, func() {
+		provider := &testProvider{
+			limits: map[string]*history.AccountLimits{
+				"source": {MaxOperationOut: int64(amount.MustParse("100"))},
+			},
+			payments: []Payment{
+				payment(1, "source", "a", xdr.AccountTypeAccountRegisteredUser, "95"),
+				payment(2, "source", "b", xdr.AccountTypeAccountRegisteredUser, "50"),
+				payment(3, "source", "c", xdr.AccountTypeAccountRegisteredUser, "99"),
+			},
+		}
+		rule := NewStructuringRule(DefaultStructuringRatio, DefaultStructuringCount, DefaultStructuringWindow)
+		current := payment(4, "source", "d", xdr.AccountTypeAccountRegisteredUser, "91")
+		ledger := &Ledger{Sequence: 10, ClosedAt: closedAt, Payments: []Payment{current}}
+		Convey("Not enough payments under limit", func() {
+			alerts, err := rule.Check(provider, ledger)
+			So(err, ShouldBeNil)
+			So(alerts, ShouldBeEmpty)
+		})
+		Convey("Alert raised", func() {
+			provider.payments = append(provider.payments, current)
+			alerts, err := rule.Check(provider, ledger)
+			So(err, ShouldBeNil)
+			So(len(alerts), ShouldEqual, 1)
+			So(alerts[0].Address, ShouldEqual, "source")
+			ids, err := alerts[0].Operations()
+			So(err, ShouldBeNil)
+			So(ids, ShouldResemble, []int64{1, 3, 4})
+		})
+		Convey("Account without limits", func() {
+			provider.payments = append(provider.payments, current)
+			provider.limits = nil
+			alerts, err := rule.Check(provider, ledger)
+			So(err, ShouldBeNil)
+			So(alerts, ShouldBeEmpty)
+		})
+	})
+	Convey("Rapid flow", t, func() {
+		provider := &testProvider{
+			payments: []Payment{
+				payment(1, "agent", "anonymous", xdr.AccountTypeAccountGeneralAgent, "100"),
+				payment(2, "anonymous", "a", xdr.AccountTypeAccountAnonymousUser, "40"),
+			},
+		}
+		rule := NewRapidFlowRule(DefaultRapidFlowRatio, DefaultRapidFlowWindow)
+		current := payment(3, "anonymous", "b", xdr.AccountTypeAccountAnonymousUser, "30")
+		provider.payments = append(provider.payments, current)
+		ledger := &Ledger{Sequence: 10, ClosedAt: closedAt, Payments: []Payment{current}}
+		Convey("Most of funds is kept", func() {
+			alerts, err := rule.Check(provider, ledger)
+			So(err, ShouldBeNil)
+			So(alerts, ShouldBeEmpty)
+		})
+		Convey("Funds passed through", func() {
+			current = payment(4, "anonymous", "c", xdr.AccountTypeAccountAnonymousUser, "25")
+			provider.payments = append(provider.payments, current)
+			ledger.Payments = []Payment{current}
+			alerts, err := rule.Check(provider, ledger)
+			So(err, ShouldBeNil)
+			So(len(alerts), ShouldEqual, 1)
+			ids, err := alerts[0].Operations()
+			So(err, ShouldBeNil)
+			So(ids, ShouldResemble, []int64{1, 2, 3, 4})
+		})
+		Convey("Registered user is not checked", func() {
+			current = payment(4, "registered", "c", xdr.AccountTypeAccountRegisteredUser, "25")
+			ledger.Payments = []Payment{current}
+			alerts, err := rule.Check(provider, ledger)
+			So(err, ShouldBeNil)
+			So(alerts, ShouldBeEmpty)
+		})
+	})
+	Convey("Volume spike", t, func() {
+		provider := &testProvider{
+			stats: map[string]history.AccountStatistics{
+				// 10 days of 100 on average, today 600
+				"source": {
+					DailyOutcome:   int64(amount.MustParse("600")),
+					MonthlyOutcome: int64(amount.MustParse("1600")),
+					AnnualOutcome:  int64(amount.MustParse("1600")),
+				},
+			},
+		}
+		ledger := &Ledger{
+			Sequence: 10,
+			ClosedAt: closedAt,
+			Payments: []Payment{payment(1, "source", "dest", xdr.AccountTypeAccountRegisteredUser, "600")},
+		}
+		Convey("Spike detected", func() {
+			alerts, err := NewVolumeSpikeRule(DefaultSpikeFactor, 0).Check(provider, ledger)
+			So(err, ShouldBeNil)
+			So(len(alerts), ShouldEqual, 1)
+			So(alerts[0].Address, ShouldEqual, "source")
+			So(alerts[0].Description, ShouldContainSubstring, "outgoing")
+		})
+		Convey("Small volume", func() {
+			alerts, err := NewVolumeSpikeRule(DefaultSpikeFactor, int64(amount.MustParse("1000"))).Check(provider, ledger)
+			So(err, ShouldBeNil)
+			So(alerts, ShouldBeEmpty)
+		})
+		Convey("No spike", func() {
+			alerts, err := NewVolumeSpikeRule(10, 0).Check(provider, ledger)
+			So(err, ShouldBeNil)
+			So(alerts, ShouldBeEmpty)
+		})
+		Convey("Average daily", func() {
+			So(averageDaily(600, 1600, 1600, closedAt), ShouldEqual, 100)
+			firstDay := time.Date(2017, time.March, 1, 12, 0, 0, 0, time.UTC)
+			So(averageDaily(600, 600, 6500, firstDay), ShouldEqual, 100)
+			So(averageDaily(600, 600, 600, time.Date(2017, time.January, 1, 12, 0, 0, 0, time.UTC)), ShouldEqual, 0)
+		})
+	})
+	Convey("Engine skips open alerts", t, func() {
+		provider := &testProvider{
+			stats: map[string]history.AccountStatistics{
+				"source": {
+					DailyOutcome:   int64(amount.MustParse("600")),
+					MonthlyOutcome: int64(amount.MustParse("1600")),
+				},
+			},
+		}
+		ledger := &Ledger{
+			Sequence: 10,
+			ClosedAt: closedAt,
+			Payments: []Payment{payment(1, "source", "dest", xdr.AccountTypeAccountRegisteredUser, "600")},
+		}
+		engine := NewEngineWithRules(NewVolumeSpikeRule(DefaultSpikeFactor, 0))
+		alerts, err := engine.Evaluate(provider, ledger)
+		So(err, ShouldBeNil)
+		So(len(alerts), ShouldEqual, 1)
+		So(alerts[0].Rule, ShouldEqual, RuleVolumeSpike)
+		So(alerts[0].LedgerSequence, ShouldEqual, 10)
+
+		provider.openAlerts = map[string]bool{RuleVolumeSpike + "source": true}
+		alerts, err = engine.Evaluate(provider, ledger)
+		So(err, ShouldBeNil)
+		So(alerts, ShouldBeEmpty)
+	})
+}
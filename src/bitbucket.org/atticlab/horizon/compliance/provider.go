@@ -0,0 +1,160 @@
+package compliance
+
+import (
+	"time"
+
+	"bitbucket.org/atticlab/go-smart-base/amount"
+	"bitbucket.org/atticlab/go-smart-base/xdr"
+	"bitbucket.org/atticlab/horizon/db2"
+	"bitbucket.org/atticlab/horizon/db2/history"
+)
+
+// MaxPayments is the max number of the newest payments loaded by a single
+// DBProvider query, so a busy account does not load its whole history
+const MaxPayments = 10000
+
+// PaymentsQuery specifies payments loaded by DataProvider. Either Source or
+// Destination must be set. Zero amount bound is ignored.
+type PaymentsQuery struct {
+	Source      string
+	Destination string
+	AssetCode   string
+	MinAmount   xdr.Int64
+	MaxAmount   xdr.Int64
+	Since       time.Time
+}
+
+// DataProvider provides history data required by rules
+type DataProvider interface {
+	// AccountLimits returns limits of the account for the asset. If account has no limits, returns nil
+	AccountLimits(address, assetCode string) (*history.AccountLimits, error)
+	// AccountStatistics returns statistics of the account for the asset by counterparty type
+	AccountStatistics(address, assetCode string, now time.Time) (map[xdr.AccountType]history.AccountStatistics, error)
+	// Payments returns payments matching the query. Amount and asset are
+	// checked against the sent side for Source and the received side for Destination.
+	// Implementation may return only the newest payments of a busy account.
+	Payments(query PaymentsQuery) ([]Payment, error)
+	// HasOpenAlert returns true if not acknowledged alert was created since `since`
+	HasOpenAlert(rule, address, assetCode string, since time.Time) (bool, error)
+}
+
+// DBProvider loads data from the history database
+type DBProvider struct {
+	q *history.Q
+}
+
+// NewDBProvider creates new data provider using history db
+func NewDBProvider(q *history.Q) *DBProvider {
+	return &DBProvider{
+		q: q,
+	}
+}
+
+func (p *DBProvider) AccountLimits(address, assetCode string) (*history.AccountLimits, error) {
+	var limits history.AccountLimits
+	err := p.q.GetAccountLimits(&limits, address, assetCode)
+	if err != nil {
+		if p.q.Repo.NoRows(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &limits, nil
+}
+
+func (p *DBProvider) AccountStatistics(address, assetCode string, now time.Time) (map[xdr.AccountType]history.AccountStatistics, error) {
+	stats := make(map[xdr.AccountType]history.AccountStatistics)
+	err := p.q.GetStatisticsByAccountAndAsset(stats, address, assetCode, now)
+	return stats, err
+}
+
+func (p *DBProvider) Payments(query PaymentsQuery) ([]Payment, error) {
+	ops := p.q.Operations().
+		ForTypes([]xdr.OperationType{xdr.OperationTypePayment, xdr.OperationTypePathPayment}).
+		ForAsset(query.AssetCode, "").
+		ClosedAt(db2.CloseAtQuery{Start: &query.Since}).
+		Page(db2.PageQuery{Order: db2.OrderDescending, Limit: MaxPayments})
+	if query.Source != "" {
+		ops = ops.ForSource(query.Source)
+	}
+	if query.Destination != "" {
+		ops = ops.ForDestination(query.Destination)
+	}
+
+	var records []history.Operation
+	err := ops.Select(&records)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Payment
+	for _, record := range records {
+		payment, err := paymentFromOperation(&record)
+		if err != nil {
+			return nil, err
+		}
+
+		var asset string
+		var paymentAmount xdr.Int64
+		if query.Source != "" {
+			asset, paymentAmount = payment.SourceAsset, payment.SourceAmount
+		} else {
+			asset, paymentAmount = payment.DestAsset, payment.DestAmount
+		}
+
+		if asset != query.AssetCode || (query.MinAmount > 0 && paymentAmount < query.MinAmount) ||
+			(query.MaxAmount > 0 && paymentAmount > query.MaxAmount) {
+			continue
+		}
+		result = append(result, *payment)
+	}
+
+	return result, nil
+}
+
+func (p *DBProvider) HasOpenAlert(rule, address, assetCode string, since time.Time) (bool, error) {
+	return p.q.HasOpenComplianceAlert(rule, address, assetCode, since)
+}
+
+// paymentDetails are details of payment and path payment operations
+type paymentDetails struct {
+	From            string `json:"from"`
+	To              string `json:"to"`
+	Amount          string `json:"amount"`
+	AssetCode       string `json:"asset_code"`
+	SourceAmount    string `json:"source_amount"`
+	SourceAssetCode string `json:"source_asset_code"`
+}
+
+func paymentFromOperation(op *history.Operation) (*Payment, error) {
+	var details paymentDetails
+	err := op.UnmarshalDetails(&details)
+	if err != nil {
+		return nil, err
+	}
+
+	destAmount, err := amount.Parse(details.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	payment := Payment{
+		OperationID:  op.ID,
+		From:         details.From,
+		To:           details.To,
+		SourceAsset:  details.AssetCode,
+		SourceAmount: destAmount,
+		DestAsset:    details.AssetCode,
+		DestAmount:   destAmount,
+	}
+
+	if op.Type == xdr.OperationTypePathPayment {
+		payment.SourceAsset = details.SourceAssetCode
+		payment.SourceAmount, err = amount.Parse(details.SourceAmount)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &payment, nil
+}
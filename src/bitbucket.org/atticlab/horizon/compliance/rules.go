@@ -0,0 +1,301 @@
+package compliance
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"bitbucket.org/atticlab/go-smart-base/amount"
+	"bitbucket.org/atticlab/go-smart-base/xdr"
+	"bitbucket.org/atticlab/horizon/db2/history"
+)
+
+const (
+	RuleStructuring = "structuring"
+	RuleRapidFlow   = "rapid_flow"
+	RuleVolumeSpike = "volume_spike"
+)
+
+// accountAsset identifies account's payments of the asset in one direction
+type accountAsset struct {
+	Address   string
+	AssetCode string
+	Income    bool
+}
+
+// paymentGroups groups payments of the ledger by sender and sent asset. If
+// `filter` is not nil, only payments it returns true for are grouped.
+func paymentGroups(ledger *Ledger, filter func(payment *Payment) bool) ([]accountAsset, map[accountAsset][]Payment) {
+	var keys []accountAsset
+	groups := make(map[accountAsset][]Payment)
+	for _, payment := range ledger.Payments {
+		if filter != nil && !filter(&payment) {
+			continue
+		}
+
+		key := accountAsset{Address: payment.From, AssetCode: payment.SourceAsset}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], payment)
+	}
+	return keys, groups
+}
+
+func operationIDs(payments []Payment) []int64 {
+	result := make([]int64, 0, len(payments))
+	for _, payment := range payments {
+		result = append(result, payment.OperationID)
+	}
+	return result
+}
+
+// StructuringRule detects accounts sending many payments just under max_operation_out limit
+type StructuringRule struct {
+	ratio  float64
+	count  int
+	window time.Duration
+}
+
+func NewStructuringRule(ratio float64, count int, window time.Duration) *StructuringRule {
+	return &StructuringRule{
+		ratio:  ratio,
+		count:  count,
+		window: window,
+	}
+}
+
+func (r *StructuringRule) Name() string {
+	return RuleStructuring
+}
+
+func (r *StructuringRule) Window() time.Duration {
+	return r.window
+}
+
+func (r *StructuringRule) Check(data DataProvider, ledger *Ledger) ([]history.ComplianceAlert, error) {
+	var result []history.ComplianceAlert
+	keys, groups := paymentGroups(ledger, nil)
+	for _, key := range keys {
+		limits, err := data.AccountLimits(key.Address, key.AssetCode)
+		if err != nil {
+			return nil, err
+		}
+
+		if limits == nil || limits.MaxOperationOut <= 0 {
+			continue
+		}
+
+		maxAmount := xdr.Int64(limits.MaxOperationOut)
+		minAmount := xdr.Int64(math.Ceil(float64(maxAmount) * r.ratio))
+		underLimit := false
+		for _, payment := range groups[key] {
+			if payment.SourceAmount >= minAmount && payment.SourceAmount <= maxAmount {
+				underLimit = true
+				break
+			}
+		}
+
+		if !underLimit {
+			continue
+		}
+
+		payments, err := data.Payments(PaymentsQuery{
+			Source:    key.Address,
+			AssetCode: key.AssetCode,
+			MinAmount: minAmount,
+			MaxAmount: maxAmount,
+			Since:     ledger.ClosedAt.Add(-r.window),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if len(payments) < r.count {
+			continue
+		}
+
+		alert, err := newAlert(key.Address, key.AssetCode, fmt.Sprintf(
+			"%d payments of %s between %s and %s (max operation out limit) within %s",
+			len(payments), key.AssetCode, amount.String(minAmount), amount.String(maxAmount), r.window,
+		), operationIDs(payments))
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, alert)
+	}
+
+	return result, nil
+}
+
+// RapidFlowRule detects anonymous accounts sending out most of the funds
+// shortly after receiving them
+type RapidFlowRule struct {
+	ratio  float64
+	window time.Duration
+}
+
+func NewRapidFlowRule(ratio float64, window time.Duration) *RapidFlowRule {
+	return &RapidFlowRule{
+		ratio:  ratio,
+		window: window,
+	}
+}
+
+func (r *RapidFlowRule) Name() string {
+	return RuleRapidFlow
+}
+
+func (r *RapidFlowRule) Window() time.Duration {
+	return r.window
+}
+
+func (r *RapidFlowRule) Check(data DataProvider, ledger *Ledger) ([]history.ComplianceAlert, error) {
+	var result []history.ComplianceAlert
+	keys, _ := paymentGroups(ledger, func(payment *Payment) bool {
+		return payment.FromType == xdr.AccountTypeAccountAnonymousUser
+	})
+
+	since := ledger.ClosedAt.Add(-r.window)
+	for _, key := range keys {
+		incoming, err := data.Payments(PaymentsQuery{
+			Destination: key.Address,
+			AssetCode:   key.AssetCode,
+			Since:       since,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var income xdr.Int64
+		for _, payment := range incoming {
+			income += payment.DestAmount
+		}
+
+		if income == 0 {
+			continue
+		}
+
+		outgoing, err := data.Payments(PaymentsQuery{
+			Source:    key.Address,
+			AssetCode: key.AssetCode,
+			Since:     since,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var outcome xdr.Int64
+		for _, payment := range outgoing {
+			outcome += payment.SourceAmount
+		}
+
+		if float64(outcome) < float64(income)*r.ratio {
+			continue
+		}
+
+		alert, err := newAlert(key.Address, key.AssetCode, fmt.Sprintf(
+			"Anonymous account received %s and sent %s %s within %s",
+			amount.String(income), amount.String(outcome), key.AssetCode, r.window,
+		), append(operationIDs(incoming), operationIDs(outgoing)...))
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, alert)
+	}
+
+	return result, nil
+}
+
+// VolumeSpikeRule detects accounts whose daily volume exceeds their average
+// daily volume for the current month (or year on the first day of month)
+type VolumeSpikeRule struct {
+	factor    float64
+	minAmount int64
+}
+
+func NewVolumeSpikeRule(factor float64, minAmount int64) *VolumeSpikeRule {
+	return &VolumeSpikeRule{
+		factor:    factor,
+		minAmount: minAmount,
+	}
+}
+
+func (r *VolumeSpikeRule) Name() string {
+	return RuleVolumeSpike
+}
+
+func (r *VolumeSpikeRule) Window() time.Duration {
+	return 24 * time.Hour
+}
+
+func (r *VolumeSpikeRule) Check(data DataProvider, ledger *Ledger) ([]history.ComplianceAlert, error) {
+	var keys []accountAsset
+	groups := make(map[accountAsset][]Payment)
+	add := func(key accountAsset, payment Payment) {
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], payment)
+	}
+	for _, payment := range ledger.Payments {
+		add(accountAsset{Address: payment.From, AssetCode: payment.SourceAsset}, payment)
+		add(accountAsset{Address: payment.To, AssetCode: payment.DestAsset, Income: true}, payment)
+	}
+
+	var result []history.ComplianceAlert
+	for _, key := range keys {
+		stats, err := data.AccountStatistics(key.Address, key.AssetCode, ledger.ClosedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		var daily, monthly, annual int64
+		for _, stat := range stats {
+			if key.Income {
+				daily, monthly, annual = daily+stat.DailyIncome, monthly+stat.MonthlyIncome, annual+stat.AnnualIncome
+			} else {
+				daily, monthly, annual = daily+stat.DailyOutcome, monthly+stat.MonthlyOutcome, annual+stat.AnnualOutcome
+			}
+		}
+
+		if daily < r.minAmount {
+			continue
+		}
+
+		average := averageDaily(daily, monthly, annual, ledger.ClosedAt)
+		if average <= 0 || float64(daily) <= float64(average)*r.factor {
+			continue
+		}
+
+		direction := "outgoing"
+		if key.Income {
+			direction = "incoming"
+		}
+		alert, err := newAlert(key.Address, key.AssetCode, fmt.Sprintf(
+			"Daily %s volume %s %s exceeds average daily volume %s more than %.1f times",
+			direction, amount.String(xdr.Int64(daily)), key.AssetCode, amount.String(xdr.Int64(average)), r.factor,
+		), operationIDs(groups[key]))
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, alert)
+	}
+
+	return result, nil
+}
+
+// averageDaily returns average daily volume for previous days of the month.
+// On the first day of month previous days of the year are used. Returns 0, if
+// there are no previous days.
+func averageDaily(daily, monthly, annual int64, now time.Time) int64 {
+	if now.Day() > 1 {
+		return (monthly - daily) / int64(now.Day()-1)
+	}
+
+	if now.YearDay() > 1 {
+		return (annual - daily) / int64(now.YearDay()-1)
+	}
+
+	return 0
+}
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"time"
+)
+
+// ComplianceConfig holds thresholds of the suspicious activity rules applied to
+// payments on ingestion.
+type ComplianceConfig struct {
+	// if true, ingested payments are checked and compliance alerts are created
+	Enabled bool
+	// payment is considered to be just under the limit, if it's amount is at
+	// least StructuringRatio of max_operation_out
+	StructuringRatio float64
+	// number of payments just under the limit, which raises an alert
+	StructuringCount int
+	// period payments just under the limit are counted for
+	StructuringWindow time.Duration
+	// anonymous account sending out at least RapidFlowRatio of received amount
+	// within RapidFlowWindow raises an alert
+	RapidFlowRatio  float64
+	RapidFlowWindow time.Duration
+	// daily volume exceeding average daily volume by SpikeFactor raises an alert
+	SpikeFactor float64
+	// daily volume below SpikeMinAmount never raises an alert
+	SpikeMinAmount int64
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"net"
+	"time"
+
+	"bitbucket.org/atticlab/go-smart-base/xdr"
+)
+
+// FriendbotConfig configures funding of accounts by friendbot on test networks
+type FriendbotConfig struct {
+	// PoolSecrets are seeds of funding accounts used along with FriendbotSecret.
+	// Each funding account submits one transaction at a time, so concurrent
+	// requests do not contend for the same sequence.
+	PoolSecrets []string
+	// AccountTypes friendbot is allowed to create, the first one is used if
+	// request does not specify the type
+	AccountTypes []xdr.AccountType
+	// Amount of the asset sent to the account on request
+	Amount int64
+	// QuotaPerIP is a number of requests allowed from one IP during
+	// QuotaWindow, not limited if zero
+	QuotaPerIP int
+	// QuotaPerAddress is a number of requests allowed for one account during
+	// QuotaWindow, not limited if zero
+	QuotaPerAddress int
+	QuotaWindow     time.Duration
+	// TrustedProxies are networks of proxies, which X-Forwarded-For header is
+	// trusted to get client IP counted by QuotaPerIP. Address of the peer is
+	// counted if empty
+	TrustedProxies []*net.IPNet
+}
+
+// Secrets returns seeds of all funding accounts, starting with primary
+func (c FriendbotConfig) Secrets(primary string) []string {
+	result := make([]string, 0, len(c.PoolSecrets)+1)
+	if primary != "" {
+		result = append(result, primary)
+	}
+	return append(result, c.PoolSecrets...)
+}
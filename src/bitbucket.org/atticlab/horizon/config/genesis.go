@@ -0,0 +1,239 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"bitbucket.org/atticlab/go-smart-base/amount"
+	"bitbucket.org/atticlab/go-smart-base/strkey"
+	"bitbucket.org/atticlab/go-smart-base/xdr"
+)
+
+// Genesis lists system accounts and assets stored on ingestion of the first
+// ledger
+type Genesis struct {
+	Accounts []GenesisAccount
+	Assets   []GenesisAsset
+}
+
+// GenesisAccount is a system account of the network
+type GenesisAccount struct {
+	Address string
+	Type    xdr.AccountType
+}
+
+// GenesisAsset is an asset issued by the bank
+type GenesisAsset struct {
+	Code        string
+	Issuer      string
+	IsAnonymous bool
+	// AnonymousRestrictions override AnonymousUserRestrictions for the asset.
+	// Global restrictions are used if nil
+	AnonymousRestrictions *AssetRestrictions
+}
+
+// AssetKey identifies asset by code and issuer
+type AssetKey struct {
+	Code   string
+	Issuer string
+}
+
+// AssetRestrictions override amounts of AnonymousUserRestrictions for the
+// asset. Amounts, which are nil, are taken from global restrictions, so
+// runtime changes of them apply to the asset.
+type AssetRestrictions struct {
+	MaxDailyOutcome   *int64
+	MaxMonthlyOutcome *int64
+	MaxAnnualOutcome  *int64
+	MaxBalance        *int64
+}
+
+// Apply returns global restrictions with amounts overridden for the asset
+func (r AssetRestrictions) Apply(global AnonymousUserRestrictions) AnonymousUserRestrictions {
+	result := global
+	for _, value := range []struct {
+		override *int64
+		dest     *int64
+	}{
+		{r.MaxDailyOutcome, &result.MaxDailyOutcome},
+		{r.MaxMonthlyOutcome, &result.MaxMonthlyOutcome},
+		{r.MaxAnnualOutcome, &result.MaxAnnualOutcome},
+		{r.MaxBalance, &result.MaxBalance},
+	} {
+		if value.override != nil {
+			*value.dest = *value.override
+		}
+	}
+	return result
+}
+
+// genesisAccountTypes maps names of account types used in genesis file
+var genesisAccountTypes = map[string]xdr.AccountType{
+	"bank":               xdr.AccountTypeAccountBank,
+	"general_agent":      xdr.AccountTypeAccountGeneralAgent,
+	"commission":         xdr.AccountTypeAccountCommission,
+	"distribution_agent": xdr.AccountTypeAccountDistributionAgent,
+	"settlement_agent":   xdr.AccountTypeAccountSettlementAgent,
+	"exchange_agent":     xdr.AccountTypeAccountExchangeAgent,
+	"merchant":           xdr.AccountTypeAccountMerchant,
+}
+
+// genesisFile is a JSON representation of genesis. Amounts of restrictions are
+// decimal strings, omitted amounts are taken from global restrictions.
+type genesisFile struct {
+	Accounts []struct {
+		Address string `json:"address"`
+		Type    string `json:"type"`
+	} `json:"accounts"`
+	Assets []struct {
+		Code                  string `json:"code"`
+		Issuer                string `json:"issuer"`
+		IsAnonymous           bool   `json:"is_anonymous"`
+		AnonymousRestrictions *struct {
+			MaxDailyOutcome   string `json:"max_daily_outcome"`
+			MaxMonthlyOutcome string `json:"max_monthly_outcome"`
+			MaxAnnualOutcome  string `json:"max_annual_outcome"`
+			MaxBalance        string `json:"max_balance"`
+		} `json:"anonymous_restrictions"`
+	} `json:"assets"`
+}
+
+// DefaultGenesis creates genesis with bank, general agent and commission
+// accounts and anonymous EUAH asset issued by the bank
+func DefaultGenesis(bankMasterKey, generalAgentKey, commissionKey string) *Genesis {
+	return &Genesis{
+		Accounts: []GenesisAccount{
+			{Address: bankMasterKey, Type: xdr.AccountTypeAccountBank},
+			{Address: generalAgentKey, Type: xdr.AccountTypeAccountGeneralAgent},
+			{Address: commissionKey, Type: xdr.AccountTypeAccountCommission},
+		},
+		Assets: []GenesisAsset{
+			{Code: "EUAH", Issuer: bankMasterKey, IsAnonymous: true},
+		},
+	}
+}
+
+// LoadGenesis reads genesis from JSON file. Issuer of asset defaults to the
+// bank account.
+func LoadGenesis(path string) (*Genesis, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file genesisFile
+	err = json.Unmarshal(data, &file)
+	if err != nil {
+		return nil, fmt.Errorf("invalid genesis file: %s", err)
+	}
+
+	var result Genesis
+	var bank string
+	for _, account := range file.Accounts {
+		accountType, ok := genesisAccountTypes[account.Type]
+		if !ok {
+			return nil, fmt.Errorf("unknown type %s of genesis account %s", account.Type, account.Address)
+		}
+		if bank == "" && accountType == xdr.AccountTypeAccountBank {
+			bank = account.Address
+		}
+		result.Accounts = append(result.Accounts, GenesisAccount{Address: account.Address, Type: accountType})
+	}
+
+	for _, asset := range file.Assets {
+		item := GenesisAsset{
+			Code:        asset.Code,
+			Issuer:      asset.Issuer,
+			IsAnonymous: asset.IsAnonymous,
+		}
+		if item.Issuer == "" {
+			item.Issuer = bank
+		}
+
+		if asset.AnonymousRestrictions != nil {
+			var assetRestrictions AssetRestrictions
+			for _, value := range []struct {
+				amount string
+				dest   **int64
+			}{
+				{asset.AnonymousRestrictions.MaxDailyOutcome, &assetRestrictions.MaxDailyOutcome},
+				{asset.AnonymousRestrictions.MaxMonthlyOutcome, &assetRestrictions.MaxMonthlyOutcome},
+				{asset.AnonymousRestrictions.MaxAnnualOutcome, &assetRestrictions.MaxAnnualOutcome},
+				{asset.AnonymousRestrictions.MaxBalance, &assetRestrictions.MaxBalance},
+			} {
+				if value.amount == "" {
+					continue
+				}
+				parsed, err := amount.Parse(value.amount)
+				if err != nil {
+					return nil, fmt.Errorf("invalid anonymous restrictions of asset %s: %s", asset.Code, err)
+				}
+				parsedAmount := int64(parsed)
+				*value.dest = &parsedAmount
+			}
+			item.AnonymousRestrictions = &assetRestrictions
+		}
+
+		result.Assets = append(result.Assets, item)
+	}
+
+	return &result, result.Validate()
+}
+
+// Validate checks addresses of accounts and assets
+func (g *Genesis) Validate() error {
+	for _, account := range g.Accounts {
+		_, err := strkey.Decode(strkey.VersionByteAccountID, account.Address)
+		if err != nil {
+			return fmt.Errorf("invalid address %s of genesis account: %s", account.Address, err)
+		}
+	}
+
+	assets := make(map[AssetKey]bool, len(g.Assets))
+	for _, asset := range g.Assets {
+		if len(asset.Code) == 0 || len(asset.Code) > 12 {
+			return fmt.Errorf("invalid code %q of genesis asset", asset.Code)
+		}
+		key := asset.Key()
+		if assets[key] {
+			return fmt.Errorf("duplicate genesis asset %s issued by %s", asset.Code, asset.Issuer)
+		}
+		assets[key] = true
+
+		_, err := strkey.Decode(strkey.VersionByteAccountID, asset.Issuer)
+		if err != nil {
+			return fmt.Errorf("invalid issuer %q of genesis asset %s: %s", asset.Issuer, asset.Code, err)
+		}
+	}
+	return nil
+}
+
+// AnonymousAssetRestrictions returns restrictions of anonymous users overridden
+// for assets
+func (g *Genesis) AnonymousAssetRestrictions() map[AssetKey]AssetRestrictions {
+	result := make(map[AssetKey]AssetRestrictions)
+	if g == nil {
+		return result
+	}
+
+	for _, asset := range g.Assets {
+		if asset.AnonymousRestrictions != nil {
+			result[asset.Key()] = *asset.AnonymousRestrictions
+		}
+	}
+	return result
+}
+
+// Key returns code and issuer of the asset
+func (a GenesisAsset) Key() AssetKey {
+	return AssetKey{Code: a.Code, Issuer: a.Issuer}
+}
+
+// AssetType returns type of the asset by length of its code
+func (a GenesisAsset) AssetType() xdr.AssetType {
+	if len(a.Code) <= 4 {
+		return xdr.AssetTypeAssetTypeCreditAlphanum4
+	}
+	return xdr.AssetTypeAssetTypeCreditAlphanum12
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"bitbucket.org/atticlab/go-smart-base/keypair"
+	"bitbucket.org/atticlab/go-smart-base/xdr"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGenesis(t *testing.T) {
+	Convey("LoadGenesis", t, func() {
+		bank, err := keypair.Random()
+		So(err, ShouldBeNil)
+		issuer, err := keypair.Random()
+		So(err, ShouldBeNil)
+
+		file, err := ioutil.TempFile("", "genesis")
+		So(err, ShouldBeNil)
+		defer os.Remove(file.Name())
+
+		write := func(data string) {
+			err := ioutil.WriteFile(file.Name(), []byte(data), 0644)
+			So(err, ShouldBeNil)
+		}
+
+		Convey("Assets with the same code are distinguished by issuer", func() {
+			write(fmt.Sprintf(`{
+				"accounts": [{"address": "%s", "type": "bank"}],
+				"assets": [
+					{"code": "EUAH", "is_anonymous": true, "anonymous_restrictions": {"max_balance": "100"}},
+					{"code": "EUAH", "issuer": "%s"}
+				]
+			}`, bank.Address(), issuer.Address()))
+
+			genesis, err := LoadGenesis(file.Name())
+			So(err, ShouldBeNil)
+			So(genesis.Accounts, ShouldResemble, []GenesisAccount{
+				{Address: bank.Address(), Type: xdr.AccountTypeAccountBank},
+			})
+			So(len(genesis.Assets), ShouldEqual, 2)
+			So(genesis.Assets[0].Issuer, ShouldEqual, bank.Address())
+			So(genesis.Assets[1].Issuer, ShouldEqual, issuer.Address())
+
+			restrictions := genesis.AnonymousAssetRestrictions()
+			So(len(restrictions), ShouldEqual, 1)
+			bankRestrictions, ok := restrictions[AssetKey{Code: "EUAH", Issuer: bank.Address()}]
+			So(ok, ShouldBeTrue)
+			So(*bankRestrictions.MaxBalance, ShouldEqual, 1000000000)
+			// omitted amounts are taken from global restrictions when applied
+			So(bankRestrictions.MaxDailyOutcome, ShouldBeNil)
+
+			applied := bankRestrictions.Apply(AnonymousUserRestrictions{MaxDailyOutcome: 5, MaxBalance: 10})
+			So(applied, ShouldResemble, AnonymousUserRestrictions{MaxDailyOutcome: 5, MaxBalance: 1000000000})
+		})
+
+		Convey("Duplicate asset of the same issuer is rejected", func() {
+			write(fmt.Sprintf(`{
+				"accounts": [{"address": "%s", "type": "bank"}],
+				"assets": [{"code": "EUAH"}, {"code": "EUAH", "issuer": "%s"}]
+			}`, bank.Address(), bank.Address()))
+
+			_, err := LoadGenesis(file.Name())
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Invalid amount of restrictions is rejected", func() {
+			write(fmt.Sprintf(`{
+				"accounts": [{"address": "%s", "type": "bank"}],
+				"assets": [{"code": "EUAH", "anonymous_restrictions": {"max_balance": "many"}}]
+			}`, bank.Address()))
+
+			_, err := LoadGenesis(file.Name())
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Unknown account type is rejected", func() {
+			write(fmt.Sprintf(`{"accounts": [{"address": "%s", "type": "king"}]}`, bank.Address()))
+
+			_, err := LoadGenesis(file.Name())
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,12 @@
+package config
+
+import "time"
+
+// HealthConfig configures readiness checks of the instance
+type HealthConfig struct {
+	// MaxIngestionLag is a number of ledgers history may fall behind stellar-core
+	// before instance is not ready, lag is not checked if zero
+	MaxIngestionLag int32
+	// CheckTimeout limits time each dependency check may take
+	CheckTimeout time.Duration
+}
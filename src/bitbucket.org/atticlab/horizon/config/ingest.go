@@ -0,0 +1,27 @@
+package config
+
+import "time"
+
+// Backends of the lease electing the only ingesting horizon instance
+const (
+	// IngestLeaderElectionNone disables election. Must be used only if there is
+	// a single horizon instance with ingestion enabled.
+	IngestLeaderElectionNone     = ""
+	IngestLeaderElectionPostgres = "postgres"
+	IngestLeaderElectionRedis    = "redis"
+)
+
+// IngestLeaderConfig configures election of the leader, which is the only
+// instance ingesting, when several instances have ingestion enabled
+type IngestLeaderConfig struct {
+	// Election is one of IngestLeaderElection constants
+	Election string
+	// LeaseTTL is a time lease is held by the leader without renewal. Failover
+	// takes at most LeaseTTL after the leader stops.
+	LeaseTTL time.Duration
+}
+
+// IsEnabled returns true if leader is elected
+func (c IngestLeaderConfig) IsEnabled() bool {
+	return c.Election != IngestLeaderElectionNone
+}
@@ -13,26 +13,59 @@ type Config struct {
 	StellarCoreDatabaseURL string
 	StellarCoreURL         string
 	Port                   int
+	// port of the admin server exposing prometheus metrics, disabled if zero
+	AdminPort              int
 	Autopump               bool
 	RateLimit              *throttled.RateQuota
+	// single redis node to connect with. For sentinel and cluster modes only password is used
 	RedisURL               string
 	LogLevel               logrus.Level
+	// levels of services overriding LogLevel, adjustable at runtime on admin port
+	LogServiceLevels       map[string]logrus.Level
+	// write logs as JSON instead of text
+	LogJSON                bool
 	SentryDSN              string
 	LogglyHost             string
 	LogglyToken            string
 	FriendbotSecret        string
+	// asset funding, account types, quotas and funding accounts of friendbot
+	Friendbot              FriendbotConfig
 	// TLSCert is a path to a certificate file to use for horizon's TLS config
 	TLSCert                   string
 	// TLSKey is the path to a private key file to use for horizon's TLS config
 	TLSKey                    string
 	Ingest                    bool
+	// election of the only ingesting instance among several with Ingest enabled
+	IngestLeader              IngestLeaderConfig
 	BankMasterKey             string
 	BankCommissionKey         string
 	AnonymousUserRestrictions AnonymousUserRestrictions
+	// restrictions of anonymous users overriding AnonymousUserRestrictions for assets
+	AssetRestrictions         map[AssetKey]AssetRestrictions
+	// system accounts and assets stored on ingestion of the first ledger
+	Genesis                   *Genesis
 	// time admin signature valid in seconds
 	AdminSignatureValid       time.Duration
+	// storage of user statistics: redis or postgres
+	StatisticsBackend         string
+	// storage of open submissions shared by horizon replicas: memory, redis or postgres
+	TxSubBackend              string
 	// time user statistics is stored in redis
 	StatisticsTimeout         time.Duration
 	// time flag for processed operation is stored
 	ProcessedOpTimeout        time.Duration
+	// retention policy for history tables
+	Retention                 RetentionPolicy
+	// screening of counterparties of payment-like operations
+	Screening                 ScreeningConfig
+	// suspicious activity rules applied on ingestion
+	Compliance                ComplianceConfig
+	// sentinel, cluster and degrade mode settings of redis
+	Redis                     RedisConfig
+	// export of trace spans
+	Tracing                   TracingConfig
+	// thresholds of readiness checks
+	Health                    HealthConfig
+	// time shutdown waits for open submissions and ingestion to finish
+	ShutdownTimeout           time.Duration
 }
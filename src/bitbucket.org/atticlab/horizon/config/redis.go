@@ -0,0 +1,33 @@
+package config
+
+import (
+	"time"
+)
+
+// RedisConfig holds settings of high available Redis deployments. If neither
+// sentinels nor cluster nodes are set, single node from RedisURL is used.
+type RedisConfig struct {
+	// addresses (host:port) of Redis Sentinel instances monitoring the master
+	SentinelAddrs []string
+	// name of the master monitored by sentinels
+	SentinelMaster string
+	// addresses (host:port) of Redis Cluster nodes used to discover the cluster
+	ClusterAddrs []string
+	// if true, statistics, open submissions and sequence reservations are kept
+	// in history db and rate limiting is skipped while Redis is unavailable.
+	// Otherwise open submissions and sequence reservations are not shared by
+	// replicas while Redis is unavailable.
+	DegradeToPostgres bool
+	// interval of Redis health checks
+	HealthCheckInterval time.Duration
+}
+
+// IsSentinel returns true if master must be discovered via Redis Sentinel
+func (c *RedisConfig) IsSentinel() bool {
+	return len(c.SentinelAddrs) != 0
+}
+
+// IsCluster returns true if Redis Cluster must be used
+func (c *RedisConfig) IsCluster() bool {
+	return len(c.ClusterAddrs) != 0
+}
@@ -0,0 +1,24 @@
+package config
+
+import (
+	"time"
+)
+
+// RetentionPolicy holds periods history data is kept for. Zero period means
+// data is kept forever.
+type RetentionPolicy struct {
+	Effects      time.Duration
+	Operations   time.Duration
+	Transactions time.Duration
+	// number of ledgers pruned in a single db transaction
+	BatchSize int32
+	// time between pruning sessions
+	Interval time.Duration
+	// directory pruned rows are exported to. Export is disabled if empty
+	ExportDir string
+}
+
+// IsEnabled returns true if any of history tables must be pruned
+func (p *RetentionPolicy) IsEnabled() bool {
+	return p.Effects > 0 || p.Operations > 0 || p.Transactions > 0
+}
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"time"
+)
+
+// ScreeningConfig holds settings of the counterparties screening performed on
+// submission of payment-like operations.
+type ScreeningConfig struct {
+	// if true, counterparties are screened against the screening list managed by admin op
+	ListEnabled bool
+	// path to file with additional addresses of the screening list, one per line
+	ListFile string
+	// url of the external screening service. Service is not called if empty
+	URL string
+	// max time to wait for response of the external screening service
+	Timeout time.Duration
+	// if true, operation is rejected when the external screening service is unavailable
+	FailClosed bool
+}
+
+// IsEnabled returns true if any of screening validators is configured
+func (c *ScreeningConfig) IsEnabled() bool {
+	return c.ListEnabled || c.ListFile != "" || c.URL != ""
+}
@@ -0,0 +1,7 @@
+package config
+
+// Storages of account statistics updated with submitted, but not yet ingested payments
+const (
+	StatisticsBackendRedis    = "redis"
+	StatisticsBackendPostgres = "postgres"
+)
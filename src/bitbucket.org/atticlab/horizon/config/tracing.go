@@ -0,0 +1,24 @@
+package config
+
+// Exporters of trace spans
+const (
+	TracingExporterNone   = ""
+	TracingExporterOTLP   = "otlp"
+	TracingExporterStdout = "stdout"
+	TracingExporterFile   = "file"
+)
+
+// TracingConfig configures export of trace spans
+type TracingConfig struct {
+	// Exporter is one of TracingExporter constants, tracing is disabled if empty
+	Exporter string
+	// OTLPEndpoint is a base url of OpenTelemetry collector accepting OTLP/HTTP
+	OTLPEndpoint string
+	// File is a path to file spans are appended to by file exporter
+	File string
+	// SampleRatio is a share (0..1) of traces started by horizon, which are
+	// exported. Incoming traces follow the decision of the caller.
+	SampleRatio float64
+	// ServiceName is reported to collector as service.name resource attribute
+	ServiceName string
+}
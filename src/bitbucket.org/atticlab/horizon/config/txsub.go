@@ -0,0 +1,10 @@
+package config
+
+// Storages of open submissions and sequence reservations of transaction submission system
+const (
+	// TxSubBackendMemory keeps state in memory of the process. Must be used
+	// only if there is a single horizon instance.
+	TxSubBackendMemory   = "memory"
+	TxSubBackendRedis    = "redis"
+	TxSubBackendPostgres = "postgres"
+)
@@ -135,6 +135,28 @@ type Trustline struct {
 	Tlimit    xdr.Int64
 	Balance   xdr.Int64
 	Flags     int32
+	// ledger the trustline was last changed in
+	Lastmodified int32
+}
+
+// AssetStats is a summary of trustlines of an asset
+type AssetStats struct {
+	// sum of balances of all trustlines
+	Supply int64 `db:"supply"`
+	// number of accounts trusting the asset
+	Trustlines int64 `db:"trustlines"`
+	// number of accounts with positive balance
+	Holders int64 `db:"holders"`
+}
+
+// AssetSupplyByAccountType is a summary of trustlines of an asset held by
+// accounts of one type
+type AssetSupplyByAccountType struct {
+	AccountType xdr.AccountType `db:"accounttype"`
+	// number of accounts with positive balance
+	Holders int64 `db:"holders"`
+	// sum of balances of the accounts
+	Amount int64 `db:"amount"`
 }
 
 func AssetFromDB(typ xdr.AssetType, code string, issuer string) (result xdr.Asset, err error) {
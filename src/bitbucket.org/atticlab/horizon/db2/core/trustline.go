@@ -64,6 +64,32 @@ func (q *Q) TrustlineByAddressAndAsset(dest interface{}, addy string, assetCode
 	return q.Get(dest, sql)
 }
 
+// AssetStats loads circulating supply and number of holders of the asset from
+// its trustlines
+func (q *Q) AssetStats(dest *AssetStats, assetCode string, issuer string) error {
+	sql := sq.Select(
+		"COALESCE(SUM(tl.balance), 0) as supply",
+		"COUNT(*) as trustlines",
+		"COALESCE(SUM(CASE WHEN tl.balance > 0 THEN 1 ELSE 0 END), 0) as holders",
+	).From("trustlines tl").Where("tl.assetcode = ? AND tl.issuer = ?", assetCode, issuer)
+	return q.Get(dest, sql)
+}
+
+// AssetSupplyByAccountType loads balances of the asset held by non-issuer
+// accounts grouped by account type
+func (q *Q) AssetSupplyByAccountType(dest *[]AssetSupplyByAccountType, assetCode string, issuer string) error {
+	sql := sq.Select(
+		"a.accounttype",
+		"COALESCE(SUM(CASE WHEN tl.balance > 0 THEN 1 ELSE 0 END), 0) as holders",
+		"COALESCE(SUM(tl.balance), 0) as amount",
+	).From("trustlines tl").
+		Join("accounts a ON a.accountid = tl.accountid").
+		Where("tl.assetcode = ? AND tl.issuer = ? AND tl.accountid <> tl.issuer", assetCode, issuer).
+		GroupBy("a.accounttype").
+		OrderBy("a.accounttype")
+	return q.Select(dest, sql)
+}
+
 var selectTrustline = sq.Select(
 	"tl.accountid",
 	"tl.assettype",
@@ -72,4 +98,5 @@ var selectTrustline = sq.Select(
 	"tl.tlimit",
 	"tl.balance",
 	"tl.flags",
+	"tl.lastmodified",
 ).From("trustlines tl")
@@ -0,0 +1,314 @@
+package history
+
+import (
+	"time"
+
+	"bitbucket.org/atticlab/horizon/db2"
+	"bitbucket.org/atticlab/horizon/log"
+	"github.com/guregu/null"
+	sq "github.com/lann/squirrel"
+)
+
+// FreezeReasonCode explains why account was frozen
+type FreezeReasonCode string
+
+const (
+	FreezeReasonFraudInvestigation FreezeReasonCode = "fraud_investigation"
+	FreezeReasonSanctions          FreezeReasonCode = "sanctions"
+	FreezeReasonCourtOrder         FreezeReasonCode = "court_order"
+	FreezeReasonKYCReview          FreezeReasonCode = "kyc_review"
+	FreezeReasonDispute            FreezeReasonCode = "dispute"
+	FreezeReasonOther              FreezeReasonCode = "other"
+)
+
+// IsValid returns true if reason code is known
+func (c FreezeReasonCode) IsValid() bool {
+	switch c {
+	case FreezeReasonFraudInvestigation, FreezeReasonSanctions, FreezeReasonCourtOrder,
+		FreezeReasonKYCReview, FreezeReasonDispute, FreezeReasonOther:
+		return true
+	}
+	return false
+}
+
+// AccountTraitsEventType is a type of change of account's traits
+type AccountTraitsEventType string
+
+const (
+	// traits of the account were set permanently
+	AccountTraitsEventSetTraits AccountTraitsEventType = "set_traits"
+	AccountTraitsEventFreeze    AccountTraitsEventType = "freeze"
+	AccountTraitsEventUnfreeze  AccountTraitsEventType = "unfreeze"
+	// freeze was lifted automatically on expiration
+	AccountTraitsEventExpire AccountTraitsEventType = "expire"
+)
+
+// AccountFreeze is a row of data from the `account_freezes` table - temporary
+// restriction of account's payments
+type AccountFreeze struct {
+	ID                     int64            `db:"id"`
+	Address                string           `db:"address"`
+	AssetCode              string           `db:"asset_code"` // empty if all assets are frozen
+	BlockIncomingPayments  bool             `db:"block_incoming_payments"`
+	BlockOutcomingPayments bool             `db:"block_outcoming_payments"`
+	ReasonCode             FreezeReasonCode `db:"reason_code"`
+	Comment                string           `db:"comment"`
+	ExpiresAt              null.Time        `db:"expires_at"`
+	LiftedAt               null.Time        `db:"lifted_at"`
+	CreatedAt              time.Time        `db:"created_at"`
+	// administrative operation created the freeze, if any
+	OperationID null.Int `db:"operation_id"`
+}
+
+// IsActive returns true if freeze was not lifted and is not expired at `now`
+func (freeze *AccountFreeze) IsActive(now time.Time) bool {
+	return !freeze.LiftedAt.Valid && (!freeze.ExpiresAt.Valid || freeze.ExpiresAt.Time.After(now))
+}
+
+// AppliesTo returns true if payments of the asset are restricted by the freeze
+func (freeze *AccountFreeze) AppliesTo(assetCode string) bool {
+	return freeze.AssetCode == "" || freeze.AssetCode == assetCode
+}
+
+// AccountTraitsEvent is a row of data from the `account_traits_history` table
+type AccountTraitsEvent struct {
+	ID                     int64                  `db:"id"`
+	Address                string                 `db:"address"`
+	Event                  AccountTraitsEventType `db:"event"`
+	FreezeID               null.Int               `db:"freeze_id"`
+	AssetCode              string                 `db:"asset_code"`
+	BlockIncomingPayments  bool                   `db:"block_incoming_payments"`
+	BlockOutcomingPayments bool                   `db:"block_outcoming_payments"`
+	ReasonCode             string                 `db:"reason_code"`
+	Comment                string                 `db:"comment"`
+	ExpiresAt              null.Time              `db:"expires_at"`
+	CreatedAt              time.Time              `db:"created_at"`
+	// administrative operation caused the event, if any
+	OperationID null.Int `db:"operation_id"`
+}
+
+// PagingToken returns a cursor for this event
+func (event *AccountTraitsEvent) PagingToken() string {
+	id := TotalOrderID{ID: event.ID}
+	return id.PagingToken()
+}
+
+// AccountTraitsEventsQ is a helper struct to aid in configuring queries that loads
+// slices of AccountTraitsEvent structs.
+type AccountTraitsEventsQ struct {
+	Err    error
+	parent *Q
+	sql    sq.SelectBuilder
+}
+
+// AccountFreezeByID tries to select freeze by id. If not found, returns nil,nil
+func (q *Q) AccountFreezeByID(id int64) (*AccountFreeze, error) {
+	var freeze AccountFreeze
+	err := q.Get(&freeze, selectAccountFreeze.Where("af.id = ?", id))
+	if err != nil {
+		if q.Repo.NoRows(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &freeze, nil
+}
+
+// ActiveAccountFreezes selects freezes of the account, which are not lifted and not expired at `now`
+func (q *Q) ActiveAccountFreezes(address string, now time.Time) ([]AccountFreeze, error) {
+	var freezes []AccountFreeze
+	err := q.Select(&freezes, selectAccountFreeze.
+		Where("af.address = ? AND af.lifted_at IS NULL AND (af.expires_at IS NULL OR af.expires_at > ?)", address, now).
+		OrderBy("af.id asc"))
+	return freezes, err
+}
+
+// AccountFreezeInsert stores new freeze and sets its id. If freeze of the same
+// administrative operation is already stored (operation is reingested), sets
+// id of the stored one.
+func (q *Q) AccountFreezeInsert(freeze *AccountFreeze) error {
+	if freeze == nil {
+		return nil
+	}
+
+	if freeze.OperationID.Valid {
+		err := q.Get(&freeze.ID, sq.Select("af.id").From("account_freezes af").Where("af.operation_id = ?", freeze.OperationID))
+		if err == nil || !q.Repo.NoRows(err) {
+			return err
+		}
+	}
+
+	insert := sq.Insert("account_freezes").Columns(
+		"address",
+		"asset_code",
+		"block_incoming_payments",
+		"block_outcoming_payments",
+		"reason_code",
+		"comment",
+		"expires_at",
+		"created_at",
+		"operation_id",
+	).Values(
+		freeze.Address,
+		freeze.AssetCode,
+		freeze.BlockIncomingPayments,
+		freeze.BlockOutcomingPayments,
+		string(freeze.ReasonCode),
+		freeze.Comment,
+		freeze.ExpiresAt,
+		createdAt(freeze.CreatedAt),
+		freeze.OperationID,
+	).Suffix("RETURNING id")
+	err := q.Get(&freeze.ID, insert)
+	if err != nil {
+		log.WithStack(err).WithError(err).WithField("address", freeze.Address).Error("Failed to insert account freeze")
+	}
+	return err
+}
+
+// AccountFreezeLift marks freeze as lifted. Returns false, if freeze does not exist or already lifted
+func (q *Q) AccountFreezeLift(id int64, at time.Time) (bool, error) {
+	update := sq.Update("account_freezes").Set("lifted_at", at).Where("id = ? AND lifted_at IS NULL", id)
+	result, err := q.Exec(update)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	return rows != 0, err
+}
+
+// AccountTraitsEventInsert stores change of account's traits. Event of the same
+// administrative operation, type and freeze is stored once.
+func (q *Q) AccountTraitsEventInsert(event *AccountTraitsEvent) error {
+	if event == nil {
+		return nil
+	}
+
+	exists, err := q.accountTraitsEventExists(event.OperationID, event.Event, event.FreezeID)
+	if err != nil || exists {
+		return err
+	}
+
+	insert := sq.Insert("account_traits_history").Columns(
+		"address",
+		"event",
+		"freeze_id",
+		"asset_code",
+		"block_incoming_payments",
+		"block_outcoming_payments",
+		"reason_code",
+		"comment",
+		"expires_at",
+		"created_at",
+		"operation_id",
+	).Values(
+		event.Address,
+		string(event.Event),
+		event.FreezeID,
+		event.AssetCode,
+		event.BlockIncomingPayments,
+		event.BlockOutcomingPayments,
+		event.ReasonCode,
+		event.Comment,
+		event.ExpiresAt,
+		createdAt(event.CreatedAt),
+		event.OperationID,
+	)
+	_, err = q.Exec(insert)
+	if err != nil {
+		log.WithStack(err).WithError(err).WithField("address", event.Address).Error("Failed to insert account traits event")
+	}
+	return err
+}
+
+// AccountFreezeLiftedBy returns true if the freeze was lifted by the
+// administrative operation, i.e. the operation is reingested
+func (q *Q) AccountFreezeLiftedBy(freezeID int64, operationID null.Int) (bool, error) {
+	return q.accountTraitsEventExists(operationID, AccountTraitsEventUnfreeze, null.IntFrom(freezeID))
+}
+
+// accountTraitsEventExists returns true if event of the administrative
+// operation, type and freeze is stored. Always false for events without operation.
+func (q *Q) accountTraitsEventExists(operationID null.Int, eventType AccountTraitsEventType, freezeID null.Int) (bool, error) {
+	if !operationID.Valid {
+		return false, nil
+	}
+
+	var exists bool
+	err := q.GetRaw(&exists, `SELECT EXISTS(
+		SELECT 1 FROM account_traits_history
+		WHERE operation_id = ? AND event = ? AND COALESCE(freeze_id, 0) = COALESCE(?::bigint, 0))`,
+		operationID, string(eventType), freezeID)
+	return exists, err
+}
+
+// createdAt returns creation time of the row, current time if not set
+func createdAt(at time.Time) time.Time {
+	if at.IsZero() {
+		return time.Now()
+	}
+	return at
+}
+
+// AccountFreezesExpire records expire event for each freeze, which is not lifted
+// and expired by `now`. Event is recorded once per freeze with creation time
+// equal to expiration time of the freeze. Returns number of recorded events.
+func (q *Q) AccountFreezesExpire(now time.Time) (int64, error) {
+	result, err := q.ExecRaw(`
+		INSERT INTO account_traits_history (address, event, freeze_id, asset_code, block_incoming_payments,
+			block_outcoming_payments, reason_code, comment, expires_at, created_at)
+		SELECT af.address, ?, af.id, af.asset_code, af.block_incoming_payments,
+			af.block_outcoming_payments, af.reason_code, '', af.expires_at, af.expires_at
+		FROM account_freezes af
+		WHERE af.lifted_at IS NULL AND af.expires_at <= ?
+			AND NOT EXISTS (
+				SELECT 1 FROM account_traits_history ath
+				WHERE ath.freeze_id = af.id AND ath.event = ?
+			)`,
+		string(AccountTraitsEventExpire), now, string(AccountTraitsEventExpire),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// AccountTraitsEvents provides a helper to filter rows from the `account_traits_history` table
+func (q *Q) AccountTraitsEvents() *AccountTraitsEventsQ {
+	return &AccountTraitsEventsQ{
+		parent: q,
+		sql:    selectAccountTraitsEvent,
+	}
+}
+
+// ForAccount filters events by account
+func (q *AccountTraitsEventsQ) ForAccount(address string) *AccountTraitsEventsQ {
+	q.sql = q.sql.Where("ath.address = ?", address)
+	return q
+}
+
+// Page specifies the paging constraints for the query being built by `q`.
+func (q *AccountTraitsEventsQ) Page(page db2.PageQuery) *AccountTraitsEventsQ {
+	if q.Err != nil {
+		return q
+	}
+
+	q.sql, q.Err = page.ApplyTo(q.sql, "ath.id")
+	return q
+}
+
+// Select loads the results of the query specified by `q` into `dest`.
+func (q *AccountTraitsEventsQ) Select(dest interface{}) error {
+	if q.Err != nil {
+		return q.Err
+	}
+
+	q.Err = q.parent.Select(dest, q.sql)
+	return q.Err
+}
+
+var selectAccountFreeze = sq.Select("af.*").From("account_freezes af")
+var selectAccountTraitsEvent = sq.Select("ath.*").From("account_traits_history ath")
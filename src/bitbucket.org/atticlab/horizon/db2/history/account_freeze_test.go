@@ -0,0 +1,78 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"bitbucket.org/atticlab/go-smart-base/keypair"
+	"bitbucket.org/atticlab/horizon/test"
+	"github.com/guregu/null"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAccountFreezeQ(t *testing.T) {
+	tt := test.Start(t).Scenario("base")
+	defer tt.Finish()
+
+	q := Q{tt.HorizonRepo()}
+	Convey("Account freezes", t, func() {
+		account, err := keypair.Random()
+		So(err, ShouldBeNil)
+
+		countEvents := func(event AccountTraitsEventType) int {
+			var events []AccountTraitsEvent
+			So(q.AccountTraitsEvents().ForAccount(account.Address()).Select(&events), ShouldBeNil)
+			count := 0
+			for _, stored := range events {
+				if stored.Event == event {
+					count++
+				}
+			}
+			return count
+		}
+
+		Convey("reingested operation does not duplicate freeze and event", func() {
+			for i := 0; i < 2; i++ {
+				freeze := AccountFreeze{
+					Address:               account.Address(),
+					BlockIncomingPayments: true,
+					ReasonCode:            FreezeReasonOther,
+					OperationID:           null.IntFrom(100),
+				}
+				So(q.AccountFreezeInsert(&freeze), ShouldBeNil)
+				So(freeze.ID, ShouldNotEqual, 0)
+				So(q.AccountTraitsEventInsert(&AccountTraitsEvent{
+					Address:     account.Address(),
+					Event:       AccountTraitsEventFreeze,
+					FreezeID:    null.IntFrom(freeze.ID),
+					OperationID: freeze.OperationID,
+				}), ShouldBeNil)
+			}
+
+			freezes, err := q.ActiveAccountFreezes(account.Address(), time.Now())
+			So(err, ShouldBeNil)
+			So(len(freezes), ShouldEqual, 1)
+			So(countEvents(AccountTraitsEventFreeze), ShouldEqual, 1)
+		})
+
+		Convey("expiration is recorded once", func() {
+			expiresAt := time.Now().Add(-time.Hour)
+			freeze := AccountFreeze{
+				Address:                account.Address(),
+				BlockOutcomingPayments: true,
+				ReasonCode:             FreezeReasonOther,
+				ExpiresAt:              null.TimeFrom(expiresAt),
+			}
+			So(q.AccountFreezeInsert(&freeze), ShouldBeNil)
+
+			expired, err := q.AccountFreezesExpire(time.Now())
+			So(err, ShouldBeNil)
+			So(expired, ShouldBeGreaterThanOrEqualTo, 1)
+
+			expired, err = q.AccountFreezesExpire(time.Now())
+			So(err, ShouldBeNil)
+			So(expired, ShouldEqual, 0)
+			So(countEvents(AccountTraitsEventExpire), ShouldEqual, 1)
+		})
+	})
+}
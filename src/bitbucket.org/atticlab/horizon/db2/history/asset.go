@@ -1,11 +1,81 @@
 package history
 
 import (
+	"encoding/json"
+
 	"bitbucket.org/atticlab/horizon/db2"
 	sq "github.com/lann/squirrel"
 	"bitbucket.org/atticlab/go-smart-base/xdr"
+	"github.com/go-errors/errors"
+)
+
+// DefaultAssetDecimals is the number of decimal places used to display amounts
+// of assets created without metadata
+const DefaultAssetDecimals = 2
+
+// AssetStatus is a stage of asset lifecycle
+type AssetStatus string
+
+const (
+	AssetStatusActive AssetStatus = "active"
+	// payments and trust lines of suspended asset are rejected until it's
+	// activated again
+	AssetStatusSuspended AssetStatus = "suspended"
+	// asset is withdrawn from circulation, can not be activated again
+	AssetStatusRetired AssetStatus = "retired"
 )
 
+// IsValid returns true if status is known
+func (s AssetStatus) IsValid() bool {
+	switch s {
+	case AssetStatusActive, AssetStatusSuspended, AssetStatusRetired:
+		return true
+	}
+	return false
+}
+
+// CanMoveTo returns true if asset in status `s` can be moved to status `next`.
+// Retired assets can not be changed.
+func (s AssetStatus) CanMoveTo(next AssetStatus) bool {
+	if s == AssetStatusRetired {
+		return next == AssetStatusRetired
+	}
+	return next.IsValid()
+}
+
+// IsActive returns true if asset can be used in transactions
+func (asset *Asset) IsActive() bool {
+	return asset.Status == AssetStatusActive
+}
+
+// RegulatoryFlags returns regulatory flags of the asset
+func (asset *Asset) RegulatoryFlags() ([]string, error) {
+	result := []string{}
+	if asset.RegulatoryFlagsString == "" {
+		return result, nil
+	}
+
+	err := json.Unmarshal([]byte(asset.RegulatoryFlagsString), &result)
+	if err != nil {
+		err = errors.Wrap(err, 1)
+	}
+	return result, err
+}
+
+// SetRegulatoryFlags sets regulatory flags of the asset
+func (asset *Asset) SetRegulatoryFlags(flags []string) error {
+	if flags == nil {
+		flags = []string{}
+	}
+
+	data, err := json.Marshal(flags)
+	if err != nil {
+		return err
+	}
+	asset.RegulatoryFlagsString = string(data)
+	return nil
+}
+
 // Assets provides a helper to filter rows from the `asset` table
 // with pre-defined filters.  See `AssetQ` methods for the available filters.
 func (q *Q) Assets() *AssetQ {
@@ -52,7 +122,19 @@ func (q *Q) InsertAsset(asset *Asset) (err error) {
 		return
 	}
 
-	insert := insertAsset.Values(asset.Type, asset.Code, asset.Issuer, asset.IsAnonymous)
+	setAssetDefaults(asset)
+	insert := insertAsset.Values(
+		asset.Type,
+		asset.Code,
+		asset.Issuer,
+		asset.IsAnonymous,
+		asset.Name,
+		asset.Decimals,
+		asset.Description,
+		asset.LogoURL,
+		asset.RegulatoryFlagsString,
+		asset.Status,
+	)
 	_, err = q.Exec(insert)
 	return err
 }
@@ -61,11 +143,18 @@ func (q *Q) UpdateAsset(asset *Asset) (bool, error) {
 	if asset == nil {
 		return false, nil
 	}
+	setAssetDefaults(asset)
 	update := updateAsset.SetMap(map[string]interface{}{
-		"type":         asset.Type,
-		"code":         asset.Code,
-		"issuer":       asset.Issuer,
-		"is_anonymous": asset.IsAnonymous,
+		"type":             asset.Type,
+		"code":             asset.Code,
+		"issuer":           asset.Issuer,
+		"is_anonymous":     asset.IsAnonymous,
+		"name":             asset.Name,
+		"decimals":         asset.Decimals,
+		"description":      asset.Description,
+		"logo_url":         asset.LogoURL,
+		"regulatory_flags": asset.RegulatoryFlagsString,
+		"status":           asset.Status,
 	}).Where("id = ?", asset.Id)
 	result, err := q.Exec(update)
 	if err != nil {
@@ -88,9 +177,30 @@ func (q *Q) DeleteAsset(id int64) (bool, error) {
 	return rows != 0, err
 }
 
+// setAssetDefaults fills fields of asset created before metadata was known
+func setAssetDefaults(asset *Asset) {
+	if asset.Status == "" {
+		asset.Status = AssetStatusActive
+	}
+	if asset.RegulatoryFlagsString == "" {
+		asset.RegulatoryFlagsString = "[]"
+	}
+}
+
 var (
 	selectAsset = sq.Select("a.*").From("asset a")
-	insertAsset = sq.Insert("asset").Columns("type", "code", "issuer", "is_anonymous")
+	insertAsset = sq.Insert("asset").Columns(
+		"type",
+		"code",
+		"issuer",
+		"is_anonymous",
+		"name",
+		"decimals",
+		"description",
+		"logo_url",
+		"regulatory_flags",
+		"status",
+	)
 	updateAsset = sq.Update("asset")
 	deleteAsset      = sq.Delete("asset")
 )
@@ -0,0 +1,83 @@
+package history
+
+import (
+	"time"
+
+	"bitbucket.org/atticlab/horizon/log"
+	sq "github.com/lann/squirrel"
+)
+
+// AssetSupplyChange is a row of data from the `asset_supply_changes` table -
+// amount of the asset issued by payment from its issuer or redeemed by
+// payment to the issuer
+type AssetSupplyChange struct {
+	ID          int64     `db:"id"`
+	OperationID int64     `db:"operation_id"`
+	AssetCode   string    `db:"asset_code"`
+	AssetIssuer string    `db:"asset_issuer"`
+	Issued      int64     `db:"issued"`
+	Redeemed    int64     `db:"redeemed"`
+	ClosedAt    time.Time `db:"closed_at"`
+}
+
+// AssetDailySupply is amount of the asset issued and redeemed during the day
+// and supply at the end of the day
+type AssetDailySupply struct {
+	Day      time.Time `db:"day"`
+	Issued   int64     `db:"issued"`
+	Redeemed int64     `db:"redeemed"`
+	Supply   int64     `db:"supply"`
+}
+
+// AssetSupplyChangeInsert stores amount issued or redeemed by the operation
+func (q *Q) AssetSupplyChangeInsert(change *AssetSupplyChange) error {
+	if change == nil {
+		return nil
+	}
+
+	insert := insertAssetSupplyChange.Values(
+		change.OperationID,
+		change.AssetCode,
+		change.AssetIssuer,
+		change.Issued,
+		change.Redeemed,
+		change.ClosedAt,
+	).Suffix("RETURNING id")
+	err := q.Get(&change.ID, insert)
+	if err != nil {
+		log.WithStack(err).WithError(err).WithField("change", *change).Error("Failed to insert asset supply change")
+	}
+	return err
+}
+
+// AssetSupplyHistory loads daily supply of the asset in [from, to). Supply of
+// the day includes all changes since the asset was issued, days without
+// changes are omitted.
+func (q *Q) AssetSupplyHistory(assetCode, assetIssuer string, from, to *time.Time) ([]AssetDailySupply, error) {
+	query := `
+		SELECT d.day, d.issued, d.redeemed, d.supply FROM (
+			SELECT
+				date_trunc('day', c.closed_at) as day,
+				SUM(c.issued) as issued,
+				SUM(c.redeemed) as redeemed,
+				SUM(SUM(c.issued) - SUM(c.redeemed)) OVER (ORDER BY date_trunc('day', c.closed_at)) as supply
+			FROM asset_supply_changes c
+			WHERE c.asset_code = $1 AND c.asset_issuer = $2
+			GROUP BY date_trunc('day', c.closed_at)
+		) d
+		WHERE ($3::timestamp IS NULL OR d.day >= $3) AND ($4::timestamp IS NULL OR d.day < $4)
+		ORDER BY d.day ASC`
+
+	var result []AssetDailySupply
+	err := q.SelectRaw(&result, query, assetCode, assetIssuer, from, to)
+	return result, err
+}
+
+var insertAssetSupplyChange = sq.Insert("asset_supply_changes").Columns(
+	"operation_id",
+	"asset_code",
+	"asset_issuer",
+	"issued",
+	"redeemed",
+	"closed_at",
+)
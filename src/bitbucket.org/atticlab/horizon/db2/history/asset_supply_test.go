@@ -0,0 +1,66 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"bitbucket.org/atticlab/go-smart-base/keypair"
+	"bitbucket.org/atticlab/horizon/test"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAssetSupplyQ(t *testing.T) {
+	tt := test.Start(t).Scenario("base")
+	defer tt.Finish()
+
+	q := Q{tt.HorizonRepo()}
+	Convey("Asset supply history", t, func() {
+		issuer, err := keypair.Random()
+		So(err, ShouldBeNil)
+
+		day := time.Date(2017, 3, 1, 0, 0, 0, 0, time.UTC)
+		changes := []AssetSupplyChange{
+			{OperationID: 1, Issued: 1000, ClosedAt: day.Add(time.Hour)},
+			{OperationID: 2, Redeemed: 300, ClosedAt: day.Add(2 * time.Hour)},
+			{OperationID: 3, Issued: 500, ClosedAt: day.Add(26 * time.Hour)},
+			{OperationID: 4, Redeemed: 200, ClosedAt: day.Add(72 * time.Hour)},
+		}
+		for i := range changes {
+			changes[i].AssetCode = "EUAH"
+			changes[i].AssetIssuer = issuer.Address()
+			So(q.AssetSupplyChangeInsert(&changes[i]), ShouldBeNil)
+			So(changes[i].ID, ShouldNotEqual, 0)
+		}
+
+		Convey("aggregates changes by day", func() {
+			daily, err := q.AssetSupplyHistory("EUAH", issuer.Address(), nil, nil)
+			So(err, ShouldBeNil)
+			So(len(daily), ShouldEqual, 3)
+
+			So(daily[0].Day.Equal(day), ShouldBeTrue)
+			So(daily[0].Issued, ShouldEqual, 1000)
+			So(daily[0].Redeemed, ShouldEqual, 300)
+			So(daily[0].Supply, ShouldEqual, 700)
+
+			So(daily[1].Supply, ShouldEqual, 1200)
+			So(daily[2].Redeemed, ShouldEqual, 200)
+			So(daily[2].Supply, ShouldEqual, 1000)
+		})
+
+		Convey("supply of the period includes earlier changes", func() {
+			from := day.Add(24 * time.Hour)
+			to := day.Add(48 * time.Hour)
+			daily, err := q.AssetSupplyHistory("EUAH", issuer.Address(), &from, &to)
+			So(err, ShouldBeNil)
+			So(len(daily), ShouldEqual, 1)
+			So(daily[0].Issued, ShouldEqual, 500)
+			So(daily[0].Supply, ShouldEqual, 1200)
+		})
+
+		Convey("ignores other assets", func() {
+			daily, err := q.AssetSupplyHistory("USD", issuer.Address(), nil, nil)
+			So(err, ShouldBeNil)
+			So(daily, ShouldBeEmpty)
+		})
+	})
+}
@@ -0,0 +1,185 @@
+package history
+
+import (
+	"encoding/json"
+	"time"
+
+	"bitbucket.org/atticlab/horizon/db2"
+	"bitbucket.org/atticlab/horizon/log"
+	"github.com/go-errors/errors"
+	"github.com/guregu/null"
+	sq "github.com/lann/squirrel"
+)
+
+// ComplianceAlert is a row of data from the `compliance_alerts` table - suspicious
+// activity detected on ingestion
+type ComplianceAlert struct {
+	ID             int64  `db:"id"`
+	Rule           string `db:"rule"`
+	Address        string `db:"address"`
+	AssetCode      string `db:"asset_code"`
+	LedgerSequence int32  `db:"ledger_sequence"`
+	Description    string `db:"description"`
+	// json array of ids of operations the alert is based on
+	OperationsString string    `db:"operations"`
+	Acknowledged     bool      `db:"acknowledged"`
+	AcknowledgedAt   null.Time `db:"acknowledged_at"`
+	Comment          string    `db:"comment"`
+	CreatedAt        time.Time `db:"created_at"`
+}
+
+// ComplianceAlertsQ is a helper struct to aid in configuring queries that loads
+// slices of ComplianceAlert structs.
+type ComplianceAlertsQ struct {
+	Err    error
+	parent *Q
+	sql    sq.SelectBuilder
+}
+
+// PagingToken returns a cursor for this alert
+func (alert *ComplianceAlert) PagingToken() string {
+	id := TotalOrderID{ID: alert.ID}
+	return id.PagingToken()
+}
+
+// Operations returns ids of operations the alert is based on
+func (alert *ComplianceAlert) Operations() ([]int64, error) {
+	var result []int64
+	err := json.Unmarshal([]byte(alert.OperationsString), &result)
+	if err != nil {
+		err = errors.Wrap(err, 1)
+	}
+	return result, err
+}
+
+// SetOperations sets ids of operations the alert is based on
+func (alert *ComplianceAlert) SetOperations(ids []int64) error {
+	if ids == nil {
+		ids = []int64{}
+	}
+
+	data, err := json.Marshal(ids)
+	if err != nil {
+		return err
+	}
+	alert.OperationsString = string(data)
+	return nil
+}
+
+// ComplianceAlerts provides a helper to filter rows from the `compliance_alerts` table
+func (q *Q) ComplianceAlerts() *ComplianceAlertsQ {
+	return &ComplianceAlertsQ{
+		parent: q,
+		sql:    selectComplianceAlert,
+	}
+}
+
+// ComplianceAlertByID tries to select alert by id. If not found, returns nil,nil
+func (q *Q) ComplianceAlertByID(id int64) (*ComplianceAlert, error) {
+	var alert ComplianceAlert
+	err := q.Get(&alert, selectComplianceAlert.Where("ca.id = ?", id))
+	if err != nil {
+		if q.Repo.NoRows(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &alert, nil
+}
+
+// ComplianceAlertInsert stores new alert
+func (q *Q) ComplianceAlertInsert(alert *ComplianceAlert) error {
+	if alert == nil {
+		return nil
+	}
+
+	insert := insertComplianceAlert.Values(
+		alert.Rule,
+		alert.Address,
+		alert.AssetCode,
+		alert.LedgerSequence,
+		alert.Description,
+		alert.OperationsString,
+	)
+	_, err := q.Exec(insert)
+	if err != nil {
+		log.WithStack(err).WithError(err).WithField("rule", alert.Rule).Error("Failed to insert compliance alert")
+	}
+	return err
+}
+
+// ComplianceAlertAcknowledge marks alert as reviewed
+func (q *Q) ComplianceAlertAcknowledge(id int64, comment string, at time.Time) (bool, error) {
+	update := sq.Update("compliance_alerts").SetMap(map[string]interface{}{
+		"acknowledged":    true,
+		"acknowledged_at": at,
+		"comment":         comment,
+	}).Where("id = ? AND acknowledged = false", id)
+	result, err := q.Exec(update)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	return rows != 0, err
+}
+
+// HasOpenComplianceAlert returns true if not acknowledged alert of the rule for
+// the account and asset was created at or after `since`
+func (q *Q) HasOpenComplianceAlert(rule, address, assetCode string, since time.Time) (bool, error) {
+	var exists bool
+	err := q.GetRaw(&exists, `SELECT EXISTS(
+		SELECT 1 FROM compliance_alerts
+		WHERE rule = $1 AND address = $2 AND asset_code = $3 AND acknowledged = false AND created_at >= $4
+	)`, rule, address, assetCode, since)
+	return exists, err
+}
+
+// ForAccount filters alerts by account
+func (q *ComplianceAlertsQ) ForAccount(address string) *ComplianceAlertsQ {
+	q.sql = q.sql.Where("ca.address = ?", address)
+	return q
+}
+
+// ForRule filters alerts by rule
+func (q *ComplianceAlertsQ) ForRule(rule string) *ComplianceAlertsQ {
+	q.sql = q.sql.Where("ca.rule = ?", rule)
+	return q
+}
+
+// Acknowledged filters alerts by acknowledgement status
+func (q *ComplianceAlertsQ) Acknowledged(acknowledged bool) *ComplianceAlertsQ {
+	q.sql = q.sql.Where("ca.acknowledged = ?", acknowledged)
+	return q
+}
+
+// Page specifies the paging constraints for the query being built by `q`.
+func (q *ComplianceAlertsQ) Page(page db2.PageQuery) *ComplianceAlertsQ {
+	if q.Err != nil {
+		return q
+	}
+
+	q.sql, q.Err = page.ApplyTo(q.sql, "ca.id")
+	return q
+}
+
+// Select loads the results of the query specified by `q` into `dest`.
+func (q *ComplianceAlertsQ) Select(dest interface{}) error {
+	if q.Err != nil {
+		return q.Err
+	}
+
+	q.Err = q.parent.Select(dest, q.sql)
+	return q.Err
+}
+
+var selectComplianceAlert = sq.Select("ca.*").From("compliance_alerts ca")
+var insertComplianceAlert = sq.Insert("compliance_alerts").Columns(
+	"rule",
+	"address",
+	"asset_code",
+	"ledger_sequence",
+	"description",
+	"operations",
+)
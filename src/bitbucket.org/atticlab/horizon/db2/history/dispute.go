@@ -0,0 +1,278 @@
+package history
+
+import (
+	"encoding/json"
+	"time"
+
+	"bitbucket.org/atticlab/horizon/db2"
+	"bitbucket.org/atticlab/horizon/log"
+	"github.com/go-errors/errors"
+	"github.com/guregu/null"
+	sq "github.com/lann/squirrel"
+)
+
+// DisputeState is a stage of dispute case
+type DisputeState string
+
+const (
+	DisputeStateOpen              DisputeState = "open"
+	DisputeStateMerchantResponded DisputeState = "merchant_responded"
+	// dispute was closed without returning the payment
+	DisputeStateResolved DisputeState = "resolved"
+	// payment was returned by reversal or refund operation
+	DisputeStateReversed DisputeState = "reversed"
+)
+
+// IsValid returns true if state is known
+func (s DisputeState) IsValid() bool {
+	switch s {
+	case DisputeStateOpen, DisputeStateMerchantResponded, DisputeStateResolved, DisputeStateReversed:
+		return true
+	}
+	return false
+}
+
+// IsActive returns true if dispute is not closed yet
+func (s DisputeState) IsActive() bool {
+	return s == DisputeStateOpen || s == DisputeStateMerchantResponded
+}
+
+// CanMoveTo returns true if dispute in state `s` can be moved to state `next`.
+// Closed disputes can not be changed.
+func (s DisputeState) CanMoveTo(next DisputeState) bool {
+	switch s {
+	case DisputeStateOpen:
+		return next == DisputeStateMerchantResponded || next == DisputeStateResolved || next == DisputeStateReversed
+	case DisputeStateMerchantResponded:
+		return next == DisputeStateResolved || next == DisputeStateReversed
+	}
+	return false
+}
+
+// Dispute is a row of data from the `disputes` table - case opened against a
+// payment contested by its sender
+type Dispute struct {
+	ID         int64        `db:"id"`
+	PaymentID  int64        `db:"payment_id"`
+	State      DisputeState `db:"state"`
+	Claimant   string       `db:"claimant"`
+	Respondent string       `db:"respondent"`
+	AssetCode  string       `db:"asset_code"`
+	Amount     int64        `db:"amount"`
+	// freeze blocking all outgoing payments of the asset from respondent while
+	// dispute is active
+	FreezeID null.Int `db:"freeze_id"`
+	// reversal or refund operation which returned the payment
+	ResolutionOperationID null.Int  `db:"resolution_operation_id"`
+	CreatedAt             time.Time `db:"created_at"`
+	UpdatedAt             time.Time `db:"updated_at"`
+}
+
+// PagingToken returns a cursor for this dispute
+func (dispute *Dispute) PagingToken() string {
+	id := TotalOrderID{ID: dispute.ID}
+	return id.PagingToken()
+}
+
+// DisputeNote is a row of data from the `dispute_notes` table - note or
+// change of state of the dispute
+type DisputeNote struct {
+	ID        int64 `db:"id"`
+	DisputeID int64 `db:"dispute_id"`
+	// empty if state was not changed
+	State DisputeState `db:"state"`
+	Note  string       `db:"note"`
+	// json array of references to evidence documents
+	EvidenceString string    `db:"evidence"`
+	CreatedAt      time.Time `db:"created_at"`
+}
+
+// Evidence returns references to evidence documents attached to the note
+func (note *DisputeNote) Evidence() ([]string, error) {
+	var result []string
+	err := json.Unmarshal([]byte(note.EvidenceString), &result)
+	if err != nil {
+		err = errors.Wrap(err, 1)
+	}
+	return result, err
+}
+
+// SetEvidence sets references to evidence documents attached to the note
+func (note *DisputeNote) SetEvidence(evidence []string) error {
+	if evidence == nil {
+		evidence = []string{}
+	}
+
+	data, err := json.Marshal(evidence)
+	if err != nil {
+		return err
+	}
+	note.EvidenceString = string(data)
+	return nil
+}
+
+// DisputesQ is a helper struct to aid in configuring queries that loads
+// slices of Dispute structs.
+type DisputesQ struct {
+	Err    error
+	parent *Q
+	sql    sq.SelectBuilder
+}
+
+// Disputes provides a helper to filter rows from the `disputes` table
+func (q *Q) Disputes() *DisputesQ {
+	return &DisputesQ{
+		parent: q,
+		sql:    selectDispute,
+	}
+}
+
+// DisputeByID tries to select dispute by id. If not found, returns nil,nil
+func (q *Q) DisputeByID(id int64) (*Dispute, error) {
+	return q.getDispute(selectDispute.Where("d.id = ?", id))
+}
+
+// ActiveDisputeByPayment tries to select not closed dispute of the payment. If not found, returns nil,nil
+func (q *Q) ActiveDisputeByPayment(paymentID int64) (*Dispute, error) {
+	return q.getDispute(selectDispute.Where("d.payment_id = ? AND d.state IN (?, ?)",
+		paymentID, string(DisputeStateOpen), string(DisputeStateMerchantResponded)))
+}
+
+func (q *Q) getDispute(sql sq.SelectBuilder) (*Dispute, error) {
+	var dispute Dispute
+	err := q.Get(&dispute, sql)
+	if err != nil {
+		if q.Repo.NoRows(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &dispute, nil
+}
+
+// DisputeInsert stores new dispute and sets its id
+func (q *Q) DisputeInsert(dispute *Dispute) error {
+	if dispute == nil {
+		return nil
+	}
+
+	insert := sq.Insert("disputes").Columns(
+		"payment_id",
+		"state",
+		"claimant",
+		"respondent",
+		"asset_code",
+		"amount",
+		"freeze_id",
+	).Values(
+		dispute.PaymentID,
+		string(dispute.State),
+		dispute.Claimant,
+		dispute.Respondent,
+		dispute.AssetCode,
+		dispute.Amount,
+		dispute.FreezeID,
+	).Suffix("RETURNING id")
+	err := q.Get(&dispute.ID, insert)
+	if err != nil {
+		log.WithStack(err).WithError(err).WithField("payment_id", dispute.PaymentID).Error("Failed to insert dispute")
+	}
+	return err
+}
+
+// DisputeUpdate updates state and resolution operation of the dispute
+func (q *Q) DisputeUpdate(dispute *Dispute) (bool, error) {
+	if dispute == nil {
+		return false, nil
+	}
+
+	update := sq.Update("disputes").SetMap(map[string]interface{}{
+		"state":                   string(dispute.State),
+		"resolution_operation_id": dispute.ResolutionOperationID,
+		"updated_at":              time.Now(),
+	}).Where("id = ?", dispute.ID)
+	result, err := q.Exec(update)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	return rows != 0, err
+}
+
+// DisputeNoteInsert stores note of the dispute
+func (q *Q) DisputeNoteInsert(note *DisputeNote) error {
+	if note == nil {
+		return nil
+	}
+
+	if note.EvidenceString == "" {
+		note.EvidenceString = "[]"
+	}
+
+	insert := sq.Insert("dispute_notes").Columns(
+		"dispute_id",
+		"state",
+		"note",
+		"evidence",
+	).Values(
+		note.DisputeID,
+		string(note.State),
+		note.Note,
+		note.EvidenceString,
+	)
+	_, err := q.Exec(insert)
+	if err != nil {
+		log.WithStack(err).WithError(err).WithField("dispute_id", note.DisputeID).Error("Failed to insert dispute note")
+	}
+	return err
+}
+
+// DisputeNotes selects all notes of the dispute in order of creation
+func (q *Q) DisputeNotes(disputeID int64) ([]DisputeNote, error) {
+	var notes []DisputeNote
+	err := q.Select(&notes, selectDisputeNote.Where("dn.dispute_id = ?", disputeID).OrderBy("dn.id asc"))
+	return notes, err
+}
+
+// ForPayment filters disputes by disputed payment
+func (q *DisputesQ) ForPayment(paymentID int64) *DisputesQ {
+	q.sql = q.sql.Where("d.payment_id = ?", paymentID)
+	return q
+}
+
+// ForAccount filters disputes where account is claimant or respondent
+func (q *DisputesQ) ForAccount(address string) *DisputesQ {
+	q.sql = q.sql.Where("(d.claimant = ? OR d.respondent = ?)", address, address)
+	return q
+}
+
+// InState filters disputes by state
+func (q *DisputesQ) InState(state DisputeState) *DisputesQ {
+	q.sql = q.sql.Where("d.state = ?", string(state))
+	return q
+}
+
+// Page specifies the paging constraints for the query being built by `q`.
+func (q *DisputesQ) Page(page db2.PageQuery) *DisputesQ {
+	if q.Err != nil {
+		return q
+	}
+
+	q.sql, q.Err = page.ApplyTo(q.sql, "d.id")
+	return q
+}
+
+// Select loads the results of the query specified by `q` into `dest`.
+func (q *DisputesQ) Select(dest interface{}) error {
+	if q.Err != nil {
+		return q.Err
+	}
+
+	q.Err = q.parent.Select(dest, q.sql)
+	return q.Err
+}
+
+var selectDispute = sq.Select("d.*").From("disputes d")
+var selectDisputeNote = sq.Select("dn.*").From("dispute_notes dn")
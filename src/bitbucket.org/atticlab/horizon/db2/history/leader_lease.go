@@ -0,0 +1,92 @@
+package history
+
+import (
+	"time"
+
+	sq "github.com/lann/squirrel"
+)
+
+// LeaderLease is a row of data from the `leader_leases` table - lease held by
+// the horizon replica elected to perform the task
+type LeaderLease struct {
+	Name      string    `db:"name"`
+	Holder    string    `db:"holder"`
+	ExpiresAt time.Time `db:"expires_at"`
+}
+
+// LockLeaderLease locks lease until the end of current transaction
+func (q *Q) LockLeaderLease(name string) error {
+	_, err := q.ExecRaw("SELECT pg_advisory_xact_lock(hashtext('leader_leases'), hashtext($1))", name)
+	return err
+}
+
+// LeaderLeaseByName selects not expired lease. If not found, returns nil,nil
+func (q *Q) LeaderLeaseByName(name string, now time.Time) (*LeaderLease, error) {
+	var lease LeaderLease
+	err := q.Get(&lease, selectLeaderLease.Where("ll.name = ? AND ll.expires_at > ?", name, now))
+	if err != nil {
+		if q.Repo.NoRows(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &lease, nil
+}
+
+// LeaderLeaseSave stores lease, replacing the previous holder
+func (q *Q) LeaderLeaseSave(lease *LeaderLease) error {
+	if lease == nil {
+		return nil
+	}
+
+	_, err := q.Exec(sq.Delete("leader_leases").Where("name = ?", lease.Name))
+	if err != nil {
+		return err
+	}
+
+	insert := sq.Insert("leader_leases").Columns(
+		"name",
+		"holder",
+		"expires_at",
+	).Values(
+		lease.Name,
+		lease.Holder,
+		lease.ExpiresAt,
+	)
+	_, err = q.Exec(insert)
+	return err
+}
+
+// LeaderLeaseDelete removes lease, if it's held by holder
+func (q *Q) LeaderLeaseDelete(name, holder string) error {
+	_, err := q.Exec(sq.Delete("leader_leases").Where("name = ? AND holder = ?", name, holder))
+	return err
+}
+
+// LeaderFenceAdvance records fencing token of the lease within current
+// transaction. Returns false, if greater token is already recorded, i.e. the
+// lease was taken over by another holder. Row stays locked until the
+// transaction ends, so holders with different tokens can't commit concurrently.
+func (q *Q) LeaderFenceAdvance(name string, token int64) (bool, error) {
+	result, err := q.ExecRaw("UPDATE leader_fences SET token = $2 WHERE name = $1 AND token <= $2", name, token)
+	if err != nil {
+		return false, err
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil || updated > 0 {
+		return updated > 0, err
+	}
+
+	var exists bool
+	err = q.GetRaw(&exists, "SELECT EXISTS(SELECT 1 FROM leader_fences WHERE name = $1)", name)
+	if err != nil || exists {
+		return false, err
+	}
+
+	_, err = q.Exec(sq.Insert("leader_fences").Columns("name", "token").Values(name, token))
+	return err == nil, err
+}
+
+var selectLeaderLease = sq.Select("ll.*").From("leader_leases ll")
@@ -7,6 +7,7 @@ import (
 
 	"bitbucket.org/atticlab/go-smart-base/xdr"
 	"bitbucket.org/atticlab/horizon/db2"
+	"github.com/guregu/null"
 	sq "github.com/lann/squirrel"
 )
 
@@ -67,9 +68,67 @@ type QInterface interface {
 	// Options
 	// Tries to select options by name. If not found, returns nil,nil
 	OptionsByName(name string) (*Options, error)
+	OptionsAll() ([]Options, error)
 	OptionsInsert(options *Options) (err error)
 	OptionsUpdate(options *Options) (bool, error)
 	OptionsDelete(name string) (bool, error)
+
+	// Screening
+	// Tries to select screening list entry by address. If not found, returns nil,nil
+	ScreeningListEntryByAddress(address string) (*ScreeningListEntry, error)
+	ScreeningListInsert(entry *ScreeningListEntry) error
+	ScreeningListUpdate(entry *ScreeningListEntry) (bool, error)
+	ScreeningListDelete(address string) (bool, error)
+	// Records result of the operation screening
+	ScreeningResultInsert(result *ScreeningResult) error
+
+	// Compliance alerts
+	// Tries to select compliance alert by id. If not found, returns nil,nil
+	ComplianceAlertByID(id int64) (*ComplianceAlert, error)
+	// Marks alert as acknowledged. Returns false, if alert does not exist or already acknowledged
+	ComplianceAlertAcknowledge(id int64, comment string, at time.Time) (bool, error)
+
+	// Account freezes
+	// Tries to select freeze by id. If not found, returns nil,nil
+	AccountFreezeByID(id int64) (*AccountFreeze, error)
+	// Selects freezes of the account, which are not lifted and not expired at `now`
+	ActiveAccountFreezes(address string, now time.Time) ([]AccountFreeze, error)
+	AccountFreezeInsert(freeze *AccountFreeze) error
+	AccountFreezeLift(id int64, at time.Time) (bool, error)
+	// Returns true if the freeze was lifted by the administrative operation
+	AccountFreezeLiftedBy(freezeID int64, operationID null.Int) (bool, error)
+	// Stores change of account's traits
+	AccountTraitsEventInsert(event *AccountTraitsEvent) error
+
+	// Reversal windows
+	// Tries to select window by asset code, issuer and account types. If not found, returns nil,nil
+	ReversalWindowByKey(assetCode, assetIssuer string, senderType, receiverType *int32) (*ReversalWindow, error)
+	// Selects the most specific window applying to the payment. If not found, returns nil,nil
+	ReversalWindowFor(assetCode, assetIssuer string, senderType, receiverType xdr.AccountType) (*ReversalWindow, error)
+	ReversalWindowInsert(window *ReversalWindow) error
+	ReversalWindowUpdate(window *ReversalWindow) (bool, error)
+	ReversalWindowDelete(id int64) (bool, error)
+
+	// Stores amount of the payment returned by reversal or refund
+	PaymentRefundInsert(refund *PaymentRefund) error
+	// Returns total amount of the payment reversed and refunded so far
+	PaymentRefundedAmount(paymentID int64) (int64, error)
+	// Reserves amount of the payment returned by submitted operation, if returned and reserved amount does not exceed limit
+	PaymentRefundReserve(reservation *PaymentRefundReservation, limit int64, now time.Time) (bool, error)
+	// Removes reservation of the submitted operation
+	PaymentRefundReservationDelete(txHash string, opIndex int) error
+
+	// Stores amount of the asset issued or redeemed by the operation
+	AssetSupplyChangeInsert(change *AssetSupplyChange) error
+
+	// Disputes
+	// Tries to select dispute by id. If not found, returns nil,nil
+	DisputeByID(id int64) (*Dispute, error)
+	// Tries to select not closed dispute of the payment. If not found, returns nil,nil
+	ActiveDisputeByPayment(paymentID int64) (*Dispute, error)
+	DisputeInsert(dispute *Dispute) error
+	DisputeUpdate(dispute *Dispute) (bool, error)
+	DisputeNoteInsert(note *DisputeNote) error
 }
 
 // Q is default implementation of QInterface
@@ -88,6 +147,11 @@ func (q *Q) LatestLedger(dest interface{}) error {
 	return q.GetRaw(dest, `SELECT COALESCE(MAX(sequence), 0) FROM history_ledgers`)
 }
 
+// LatestLedgerClosedBefore loads sequence of the latest ledger closed before `t`
+func (q *Q) LatestLedgerClosedBefore(dest interface{}, t time.Time) error {
+	return q.GetRaw(dest, `SELECT COALESCE(MAX(sequence), 0) FROM history_ledgers WHERE closed_at < $1`, t)
+}
+
 // OldestOutdatedLedgers populates a slice of ints with the first million
 // outdated ledgers, based upon the provided `currentVersion` number
 func (q *Q) OldestOutdatedLedgers(dest interface{}, currentVersion int) error {
@@ -144,6 +208,14 @@ type Asset struct {
 	Code        string `db:"code"`
 	Issuer      string `db:"issuer"`
 	IsAnonymous bool   `db:"is_anonymous"`
+	Name        string `db:"name"`
+	// number of decimal places used to display amounts of the asset
+	Decimals    int    `db:"decimals"`
+	Description string `db:"description"`
+	LogoURL     string `db:"logo_url"`
+	// json array of regulatory flags
+	RegulatoryFlagsString string      `db:"regulatory_flags"`
+	Status                AssetStatus `db:"status"`
 }
 
 // AssetQ is a helper struct to aid in configuring queries that loads
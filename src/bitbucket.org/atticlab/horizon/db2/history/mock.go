@@ -3,6 +3,7 @@ package history
 import (
 	"bitbucket.org/atticlab/go-smart-base/xdr"
 	"bitbucket.org/atticlab/horizon/log"
+	"github.com/guregu/null"
 	"github.com/stretchr/testify/mock"
 	"math/rand"
 	"time"
@@ -150,6 +151,15 @@ func (m *QMock) OptionsByName(name string) (*Options, error) {
 	}
 	return options.(*Options), err
 }
+func (m *QMock) OptionsAll() ([]Options, error) {
+	a := m.Called()
+	options := a.Get(0)
+	err := a.Error(1)
+	if options == nil {
+		return nil, err
+	}
+	return options.([]Options), err
+}
 func (m *QMock) OptionsInsert(options *Options) (error) {
 	a := m.Called(options)
 	return a.Error(0)
@@ -163,6 +173,137 @@ func (m *QMock) OptionsDelete(name string) (bool, error) {
 	return a.Bool(0), a.Error(1)
 }
 
+func (m *QMock) ScreeningListEntryByAddress(address string) (*ScreeningListEntry, error) {
+	a := m.Called(address)
+	entry := a.Get(0)
+	err := a.Error(1)
+	if entry == nil {
+		return nil, err
+	}
+	return entry.(*ScreeningListEntry), err
+}
+func (m *QMock) ScreeningListInsert(entry *ScreeningListEntry) error {
+	a := m.Called(entry)
+	return a.Error(0)
+}
+func (m *QMock) ScreeningListUpdate(entry *ScreeningListEntry) (bool, error) {
+	a := m.Called(entry)
+	return a.Bool(0), a.Error(1)
+}
+func (m *QMock) ScreeningListDelete(address string) (bool, error) {
+	a := m.Called(address)
+	return a.Bool(0), a.Error(1)
+}
+func (m *QMock) ScreeningResultInsert(result *ScreeningResult) error {
+	a := m.Called(result)
+	return a.Error(0)
+}
+
+func (m *QMock) ComplianceAlertByID(id int64) (*ComplianceAlert, error) {
+	a := m.Called(id)
+	alert := a.Get(0)
+	err := a.Error(1)
+	if alert == nil {
+		return nil, err
+	}
+	return alert.(*ComplianceAlert), err
+}
+func (m *QMock) ComplianceAlertAcknowledge(id int64, comment string, at time.Time) (bool, error) {
+	a := m.Called(id, comment, at)
+	return a.Bool(0), a.Error(1)
+}
+
+func (m *QMock) AccountFreezeByID(id int64) (*AccountFreeze, error) {
+	a := m.Called(id)
+	freeze := a.Get(0)
+	err := a.Error(1)
+	if freeze == nil {
+		return nil, err
+	}
+	return freeze.(*AccountFreeze), err
+}
+func (m *QMock) ActiveAccountFreezes(address string, now time.Time) ([]AccountFreeze, error) {
+	a := m.Called(address, now)
+	freezes := a.Get(0)
+	err := a.Error(1)
+	if freezes == nil {
+		return nil, err
+	}
+	return freezes.([]AccountFreeze), err
+}
+func (m *QMock) AccountFreezeInsert(freeze *AccountFreeze) error {
+	a := m.Called(freeze)
+	return a.Error(0)
+}
+func (m *QMock) AccountFreezeLift(id int64, at time.Time) (bool, error) {
+	a := m.Called(id, at)
+	return a.Bool(0), a.Error(1)
+}
+func (m *QMock) AccountFreezeLiftedBy(freezeID int64, operationID null.Int) (bool, error) {
+	a := m.Called(freezeID, operationID)
+	return a.Bool(0), a.Error(1)
+}
+func (m *QMock) AccountTraitsEventInsert(event *AccountTraitsEvent) error {
+	a := m.Called(event)
+	return a.Error(0)
+}
+
+func (m *QMock) ReversalWindowByKey(assetCode, assetIssuer string, senderType, receiverType *int32) (*ReversalWindow, error) {
+	a := m.Called(assetCode, assetIssuer, senderType, receiverType)
+	window := a.Get(0)
+	err := a.Error(1)
+	if window == nil {
+		return nil, err
+	}
+	return window.(*ReversalWindow), err
+}
+func (m *QMock) ReversalWindowFor(assetCode, assetIssuer string, senderType, receiverType xdr.AccountType) (*ReversalWindow, error) {
+	a := m.Called(assetCode, assetIssuer, senderType, receiverType)
+	window := a.Get(0)
+	err := a.Error(1)
+	if window == nil {
+		return nil, err
+	}
+	return window.(*ReversalWindow), err
+}
+func (m *QMock) ReversalWindowInsert(window *ReversalWindow) error {
+	a := m.Called(window)
+	return a.Error(0)
+}
+func (m *QMock) ReversalWindowUpdate(window *ReversalWindow) (bool, error) {
+	a := m.Called(window)
+	return a.Bool(0), a.Error(1)
+}
+func (m *QMock) ReversalWindowDelete(id int64) (bool, error) {
+	a := m.Called(id)
+	return a.Bool(0), a.Error(1)
+}
+
+func (m *QMock) PaymentRefundInsert(refund *PaymentRefund) error {
+	a := m.Called(refund)
+	return a.Error(0)
+}
+
+func (m *QMock) PaymentRefundedAmount(paymentID int64) (int64, error) {
+	a := m.Called(paymentID)
+	return a.Get(0).(int64), a.Error(1)
+}
+
+func (m *QMock) PaymentRefundReserve(reservation *PaymentRefundReservation, limit int64, now time.Time) (bool, error) {
+	a := m.Called(reservation, limit, now)
+	return a.Bool(0), a.Error(1)
+}
+
+func (m *QMock) PaymentRefundReservationDelete(txHash string, opIndex int) error {
+	a := m.Called(txHash, opIndex)
+	return a.Error(0)
+}
+
+func (m *QMock) AssetSupplyChangeInsert(change *AssetSupplyChange) error {
+	a := m.Called(change)
+	return a.Error(0)
+}
+
 func CreateRandomAccountStats(account string, counterpartyType xdr.AccountType, asset string) AccountStatistics {
 	return CreateRandomAccountStatsWithMinValue(account, counterpartyType, asset, 0)
 }
@@ -190,3 +331,38 @@ func Max(x int64, y int64) int64 {
 	}
 	return y
 }
+
+func (m *QMock) DisputeByID(id int64) (*Dispute, error) {
+	a := m.Called(id)
+	dispute := a.Get(0)
+	err := a.Error(1)
+	if dispute == nil {
+		return nil, err
+	}
+	return dispute.(*Dispute), err
+}
+
+func (m *QMock) ActiveDisputeByPayment(paymentID int64) (*Dispute, error) {
+	a := m.Called(paymentID)
+	dispute := a.Get(0)
+	err := a.Error(1)
+	if dispute == nil {
+		return nil, err
+	}
+	return dispute.(*Dispute), err
+}
+
+func (m *QMock) DisputeInsert(dispute *Dispute) error {
+	a := m.Called(dispute)
+	return a.Error(0)
+}
+
+func (m *QMock) DisputeUpdate(dispute *Dispute) (bool, error) {
+	a := m.Called(dispute)
+	return a.Bool(0), a.Error(1)
+}
+
+func (m *QMock) DisputeNoteInsert(note *DisputeNote) error {
+	a := m.Called(note)
+	return a.Error(0)
+}
@@ -3,11 +3,13 @@ package history
 import (
 	"encoding/json"
 	"strings"
+	"bitbucket.org/atticlab/go-smart-base/amount"
 	"bitbucket.org/atticlab/go-smart-base/xdr"
 	"bitbucket.org/atticlab/horizon/db2"
 	"bitbucket.org/atticlab/horizon/toid"
 	"github.com/go-errors/errors"
 	"github.com/guregu/null"
+	"github.com/jmoiron/sqlx"
 	sq "github.com/lann/squirrel"
 	"time"
 )
@@ -15,13 +17,17 @@ import (
 // Operation is a row of data from the `history_operations` table
 type Operation struct {
 	TotalOrderID
-	TransactionID    int64             `db:"transaction_id"`
-	TransactionHash  string            `db:"transaction_hash"`
-	ApplicationOrder int32             `db:"application_order"`
-	Type             xdr.OperationType `db:"type"`
-	DetailsString    null.String       `db:"details"`
-	SourceAccount    string            `db:"source_account"`
-	ClosedAt         time.Time         `db:"closed_at"`
+	TransactionID       int64             `db:"transaction_id"`
+	TransactionHash     string            `db:"transaction_hash"`
+	ApplicationOrder    int32             `db:"application_order"`
+	Type                xdr.OperationType `db:"type"`
+	DetailsString       null.String       `db:"details"`
+	SourceAccount       string            `db:"source_account"`
+	ClosedAt            time.Time         `db:"closed_at"`
+	TransactionMemo     null.String       `db:"memo"`
+	TransactionMemoType string            `db:"memo_type"`
+	// total amount returned by reversals and refunds. Null if payment was not returned
+	RefundedAmount      null.Int          `db:"refunded_amount"`
 
 	rawDetails []byte
 }
@@ -106,6 +112,9 @@ type OperationsQ struct {
 	Err    error
 	parent *Q
 	sql    sq.SelectBuilder
+
+	// id of the history account selected by ForAccount, used to define counterparties
+	accountID *int64
 }
 
 // UnmarshalDetails unmarshals the details of this operation into `dest`
@@ -140,6 +149,24 @@ func (q *Q) OperationByID(dest interface{}, id int64) error {
 	return q.Get(dest, sql)
 }
 
+// OperationWithRefundsByID loads a single operation with `id` into `dest`
+// along with amount returned by reversals and refunds of the payment
+func (q *Q) OperationWithRefundsByID(dest interface{}, id int64) error {
+	sql := selectOperation.
+		Columns(selectRefundedAmount).
+		Limit(1).
+		Where("hop.id = ?", id)
+
+	return q.Get(dest, sql)
+}
+
+// WithRefundedAmount loads amount returned by reversals and refunds of the
+// payments along with the operations
+func (q *OperationsQ) WithRefundedAmount() *OperationsQ {
+	q.sql = q.sql.Columns(selectRefundedAmount)
+	return q
+}
+
 // ForAccount filters the operations collection to a specific account
 func (q *OperationsQ) ForAccount(aid string) *OperationsQ {
 	var account Account
@@ -152,6 +179,7 @@ func (q *OperationsQ) ForAccount(aid string) *OperationsQ {
 		"history_operation_participants hopp ON "+
 			"hopp.history_operation_id = hop.id",
 	).Where("hopp.history_account_id = ?", account.ID)
+	q.accountID = &account.ID
 
 	return q
 }
@@ -200,6 +228,14 @@ func (q *OperationsQ) ForLedger(seq int32) *OperationsQ {
 	return q
 }
 
+// UpToLedger filters the query to only include operations of ledgers up to
+// seq inclusive
+func (q *OperationsQ) UpToLedger(seq int32) *OperationsQ {
+	end := toid.ID{LedgerSequence: seq + 1}
+	q.sql = q.sql.Where("hop.id < ?", end.ToInt64())
+	return q
+}
+
 // ForTransaction filters the query to a only operations in a specific
 // transaction, specified by the transactions's hex-encoded hash.
 func (q *OperationsQ) ForTransaction(hash string) *OperationsQ {
@@ -234,6 +270,87 @@ func (q *OperationsQ) OnlyPayments() *OperationsQ {
 	return q
 }
 
+// ForTypes filters the query to only include operations of specified types
+func (q *OperationsQ) ForTypes(types []xdr.OperationType) *OperationsQ {
+	if len(types) == 0 {
+		return q
+	}
+
+	q.sql = q.sql.Where(sq.Eq{"hop.type": types})
+	return q
+}
+
+// ForAsset filters the query to only include operations sending or receiving
+// asset with specified code. If issuer is not empty, it must match too.
+func (q *OperationsQ) ForAsset(code, issuer string) *OperationsQ {
+	if issuer == "" {
+		q.sql = q.sql.Where("(hop.details->>'asset_code' = ? OR hop.details->>'source_asset_code' = ?)", code, code)
+		return q
+	}
+
+	q.sql = q.sql.Where("((hop.details->>'asset_code' = ? AND hop.details->>'asset_issuer' = ?) OR "+
+		"(hop.details->>'source_asset_code' = ? AND hop.details->>'source_asset_issuer' = ?))", code, issuer, code, issuer)
+	return q
+}
+
+// AmountRange filters the query to only include operations with amount within
+// [minAmount, maxAmount]. Zero bound is ignored.
+func (q *OperationsQ) AmountRange(minAmount, maxAmount xdr.Int64) *OperationsQ {
+	if minAmount > 0 {
+		q.sql = q.sql.Where("(hop.details->>'amount')::numeric >= ?", amount.String(minAmount))
+	}
+
+	if maxAmount > 0 {
+		q.sql = q.sql.Where("(hop.details->>'amount')::numeric <= ?", amount.String(maxAmount))
+	}
+	return q
+}
+
+// ForCounterpartyType filters the query to only include operations having
+// participant of specified account type. If query is filtered by account
+// (ForAccount must be called first), counterparty is any other participant,
+// otherwise - any participant except operation's source.
+func (q *OperationsQ) ForCounterpartyType(accountType xdr.AccountType) *OperationsQ {
+	if q.Err != nil {
+		return q
+	}
+
+	counterparties := "EXISTS (SELECT 1 FROM history_operation_participants chopp " +
+		"JOIN history_accounts cha ON cha.id = chopp.history_account_id " +
+		"WHERE chopp.history_operation_id = hop.id AND cha.account_type = ?"
+	if q.accountID != nil {
+		q.sql = q.sql.Where(counterparties+" AND cha.id <> ?)", int32(accountType), *q.accountID)
+		return q
+	}
+
+	q.sql = q.sql.Where(counterparties+" AND cha.address <> hop.source_account)", int32(accountType))
+	return q
+}
+
+// ForMemo filters the query to only include operations of transactions with specified memo
+func (q *OperationsQ) ForMemo(memo string) *OperationsQ {
+	q.sql = q.sql.Where("ht.memo = ?", memo)
+	return q
+}
+
+// ForMemos filters the query to only include operations of transactions with one of specified memos
+func (q *OperationsQ) ForMemos(memos []string) *OperationsQ {
+	q.sql = q.sql.Where(sq.Eq{"ht.memo": memos})
+	return q
+}
+
+// ForDestination filters the query to only include payments and path payments sent to the account
+func (q *OperationsQ) ForDestination(address string) *OperationsQ {
+	q.sql = q.sql.Where("hop.details->>'to' = ?", address)
+	return q
+}
+
+// ForSource filters the query to only include payments and path payments sent from the account
+func (q *OperationsQ) ForSource(address string) *OperationsQ {
+	q.sql = q.sql.Where("hop.details->>'from' = ?", address)
+	return q
+}
+
 // Page specifies the paging constraints for the query being built by `q`.
 func (q *OperationsQ) Page(page db2.PageQuery) *OperationsQ {
 	if q.Err != nil {
@@ -262,6 +379,18 @@ func (q *OperationsQ) Select(dest interface{}) error {
 	return q.Err
 }
 
+// Rows runs the query specified by `q` ordered by id and returns a cursor over
+// the results. Used to stream result sets too large to be loaded at once.
+func (q *OperationsQ) Rows() (*sqlx.Rows, error) {
+	if q.Err != nil {
+		return nil, q.Err
+	}
+
+	var rows *sqlx.Rows
+	rows, q.Err = q.parent.Query(q.sql.OrderBy("hop.id asc"))
+	return rows, q.Err
+}
+
 var selectOperation = sq.Select(
 	"hop.id, " +
 		"hop.transaction_id, " +
@@ -270,11 +399,17 @@ var selectOperation = sq.Select(
 		"hop.details, " +
 		"hop.source_account, " +
 		"ht.transaction_hash, " +
+		"ht.memo, " +
+		"ht.memo_type, " +
 		"hl.closed_at").
 	From("history_operations hop").
 	LeftJoin("history_transactions ht ON ht.id = hop.transaction_id").
 	LeftJoin("history_ledgers hl ON hl.sequence = ht.ledger_sequence")
 
+// selectRefundedAmount is a column of total amount returned for the payment,
+// selected only by queries rendering payments
+const selectRefundedAmount = "(SELECT SUM(pr.amount) FROM payment_refunds pr WHERE pr.payment_id = hop.id) AS refunded_amount"
+
 var OperationInsert = sq.Insert("history_operations").Columns(
 	"id",
 	"transaction_id",
@@ -0,0 +1,42 @@
+package history
+
+import (
+	"bitbucket.org/atticlab/go-smart-base/xdr"
+)
+
+// OperationsFilter holds optional filters for operations query. Zero values are ignored.
+type OperationsFilter struct {
+	Types            []xdr.OperationType
+	AssetCode        string
+	AssetIssuer      string
+	MinAmount        xdr.Int64
+	MaxAmount        xdr.Int64
+	CounterpartyType *xdr.AccountType
+	Memo             string
+}
+
+// Filter applies all the filters set in `filter` to the query being built by `q`.
+// Must be called after ForAccount to correctly define counterparties.
+func (q *OperationsQ) Filter(filter OperationsFilter) *OperationsQ {
+	if q.Err != nil {
+		return q
+	}
+
+	q.ForTypes(filter.Types)
+
+	if filter.AssetCode != "" {
+		q.ForAsset(filter.AssetCode, filter.AssetIssuer)
+	}
+
+	q.AmountRange(filter.MinAmount, filter.MaxAmount)
+
+	if filter.CounterpartyType != nil {
+		q.ForCounterpartyType(*filter.CounterpartyType)
+	}
+
+	if filter.Memo != "" {
+		q.ForMemo(filter.Memo)
+	}
+
+	return q
+}
@@ -0,0 +1,58 @@
+package history
+
+import (
+	"testing"
+
+	"bitbucket.org/atticlab/go-smart-base/xdr"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestOperationsFilter(t *testing.T) {
+	Convey("OperationsFilter", t, func() {
+		q := &OperationsQ{sql: selectOperation}
+		Convey("Empty filter does not change query", func() {
+			expected, _, err := selectOperation.ToSql()
+			So(err, ShouldBeNil)
+			actual, args, err := q.Filter(OperationsFilter{}).sql.ToSql()
+			So(err, ShouldBeNil)
+			So(actual, ShouldEqual, expected)
+			So(args, ShouldBeEmpty)
+		})
+		Convey("All filters are applied", func() {
+			counterpartyType := xdr.AccountTypeAccountMerchant
+			filter := OperationsFilter{
+				Types:            []xdr.OperationType{xdr.OperationTypeRefund},
+				AssetCode:        "EUAH",
+				MinAmount:        5000000000,
+				MaxAmount:        10000000000,
+				CounterpartyType: &counterpartyType,
+				Memo:             "invoice",
+			}
+			sql, args, err := q.Filter(filter).sql.ToSql()
+			So(err, ShouldBeNil)
+			So(sql, ShouldContainSubstring, "hop.type IN (?)")
+			So(sql, ShouldContainSubstring, "hop.details->>'asset_code' = ?")
+			So(sql, ShouldContainSubstring, "(hop.details->>'amount')::numeric >= ?")
+			So(sql, ShouldContainSubstring, "(hop.details->>'amount')::numeric <= ?")
+			So(sql, ShouldContainSubstring, "cha.address <> hop.source_account")
+			So(sql, ShouldContainSubstring, "ht.memo = ?")
+			So(args, ShouldResemble, []interface{}{
+				xdr.OperationTypeRefund,
+				"EUAH", "EUAH",
+				"500.0000000",
+				"1000.0000000",
+				int32(xdr.AccountTypeAccountMerchant),
+				"invoice",
+			})
+		})
+		Convey("Counterparty excludes filtered account", func() {
+			accountID := int64(10)
+			q.accountID = &accountID
+			counterpartyType := xdr.AccountTypeAccountMerchant
+			sql, args, err := q.Filter(OperationsFilter{CounterpartyType: &counterpartyType}).sql.ToSql()
+			So(err, ShouldBeNil)
+			So(sql, ShouldContainSubstring, "cha.id <> ?")
+			So(args, ShouldResemble, []interface{}{int32(xdr.AccountTypeAccountMerchant), accountID})
+		})
+	})
+}
@@ -8,8 +8,3 @@ type Options struct {
 	Name string `db:"name"`
 	Data string `db:"data"`
 }
-
-func (o *Options) MaxReversalDuration() *MaxReversalDuration {
-	result := MaxReversalDuration(*o)
-	return &result
-}
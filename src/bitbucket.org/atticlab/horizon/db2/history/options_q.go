@@ -22,6 +22,18 @@ func (q *Q) OptionsByName(name string) (*Options, error) {
 	return &options, nil
 }
 
+// OptionsAll selects all stored options
+func (q *Q) OptionsAll() ([]Options, error) {
+	var options []Options
+	err := q.Select(&options, selectOptions)
+	if err != nil {
+		log.WithStack(err).WithError(err).Error("Failed to get options")
+		return nil, err
+	}
+
+	return options, nil
+}
+
 // Tries to insert options
 func (q *Q) OptionsInsert(options *Options) (err error) {
 	if options == nil {
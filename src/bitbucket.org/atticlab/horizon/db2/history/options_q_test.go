@@ -47,6 +47,11 @@ func TestOptionsQ(t *testing.T) {
 			assert.Nil(t, err)
 			assert.Equal(t, expectedOptions, actualOptions)
 
+			// can select all
+			all, err := h.OptionsAll()
+			assert.Nil(t, err)
+			assert.Contains(t, all, *expectedOptions)
+
 			// can update
 			expectedOptions.Data = "updated data"
 			isUpdated, err := h.OptionsUpdate(expectedOptions)
@@ -0,0 +1,125 @@
+package history
+
+import (
+	"time"
+
+	"bitbucket.org/atticlab/go-smart-base/xdr"
+	"bitbucket.org/atticlab/horizon/log"
+	sq "github.com/lann/squirrel"
+)
+
+// PaymentRefund is a row of data from the `payment_refunds` table - amount of
+// the payment returned to its sender by reversal or refund operation
+type PaymentRefund struct {
+	// id of reversal or refund operation
+	ID        int64             `db:"id"`
+	PaymentID int64             `db:"payment_id"`
+	Type      xdr.OperationType `db:"type"`
+	Amount    int64             `db:"amount"`
+}
+
+// PaymentRefundInsert stores amount returned by reversal or refund operation
+func (q *Q) PaymentRefundInsert(refund *PaymentRefund) error {
+	if refund == nil {
+		return nil
+	}
+
+	insert := insertPaymentRefund.Values(refund.ID, refund.PaymentID, refund.Type, refund.Amount)
+	_, err := q.Exec(insert)
+	if err != nil {
+		log.WithStack(err).WithError(err).WithField("refund", *refund).Error("Failed to insert payment refund")
+	}
+	return err
+}
+
+// PaymentRefundedAmount returns total amount of the payment reversed and refunded so far
+func (q *Q) PaymentRefundedAmount(paymentID int64) (int64, error) {
+	var total int64
+	err := q.GetRaw(&total, "SELECT COALESCE(SUM(amount), 0) FROM payment_refunds WHERE payment_id = $1", paymentID)
+	return total, err
+}
+
+// PaymentRefundReservation is a row of data from the `payment_refund_reservations`
+// table - amount of the payment returned by reversal or refund operation of
+// submitted transaction, which is not yet ingested
+type PaymentRefundReservation struct {
+	TxHash    string    `db:"tx_hash"`
+	OpIndex   int       `db:"op_index"`
+	PaymentID int64     `db:"payment_id"`
+	Amount    int64     `db:"amount"`
+	ExpiresAt time.Time `db:"expires_at"`
+}
+
+// PaymentRefundReserve stores reservation, if amount of the payment returned by
+// ingested operations and reserved by other transactions leaves at least
+// reserved amount of `limit`. Reservations of the payment are serialized by
+// advisory lock. Reservations of ingested transactions are not counted, as
+// their amounts are already stored as refunds.
+func (q *Q) PaymentRefundReserve(reservation *PaymentRefundReservation, limit int64, now time.Time) (bool, error) {
+	repo := q.Repo.Clone()
+	err := repo.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer repo.Rollback()
+
+	_, err = repo.ExecRaw("SELECT pg_advisory_xact_lock(hashtext('payment_refund'), hashtext($1::text))", reservation.PaymentID)
+	if err != nil {
+		return false, err
+	}
+
+	var returned int64
+	err = repo.GetRaw(&returned, `SELECT
+		(SELECT COALESCE(SUM(amount), 0) FROM payment_refunds WHERE payment_id = $1) +
+		(SELECT COALESCE(SUM(prr.amount), 0) FROM payment_refund_reservations prr
+			WHERE prr.payment_id = $1 AND prr.tx_hash <> $2 AND prr.expires_at > $3
+				AND NOT EXISTS (SELECT 1 FROM history_transactions ht WHERE ht.transaction_hash = prr.tx_hash))`,
+		reservation.PaymentID, reservation.TxHash, now)
+	if err != nil {
+		return false, err
+	}
+
+	if reservation.Amount > limit-returned {
+		return false, nil
+	}
+
+	_, err = repo.Exec(sq.Delete("payment_refund_reservations").Where(
+		"(payment_id = ? AND expires_at <= ?) OR (tx_hash = ? AND op_index = ?)",
+		reservation.PaymentID, now, reservation.TxHash, reservation.OpIndex))
+	if err != nil {
+		return false, err
+	}
+
+	insert := sq.Insert("payment_refund_reservations").Columns(
+		"tx_hash",
+		"op_index",
+		"payment_id",
+		"amount",
+		"expires_at",
+	).Values(
+		reservation.TxHash,
+		reservation.OpIndex,
+		reservation.PaymentID,
+		reservation.Amount,
+		reservation.ExpiresAt,
+	)
+	_, err = repo.Exec(insert)
+	if err != nil {
+		return false, err
+	}
+
+	return true, repo.Commit()
+}
+
+// PaymentRefundReservationDelete removes reservation of the operation
+func (q *Q) PaymentRefundReservationDelete(txHash string, opIndex int) error {
+	_, err := q.Exec(sq.Delete("payment_refund_reservations").Where("tx_hash = ? AND op_index = ?", txHash, opIndex))
+	return err
+}
+
+var insertPaymentRefund = sq.Insert("payment_refunds").Columns(
+	"id",
+	"payment_id",
+	"type",
+	"amount",
+)
@@ -0,0 +1,201 @@
+package history
+
+import (
+	"time"
+
+	"bitbucket.org/atticlab/go-smart-base/xdr"
+	"bitbucket.org/atticlab/horizon/db2"
+	"bitbucket.org/atticlab/horizon/log"
+	"github.com/guregu/null"
+	sq "github.com/lann/squirrel"
+)
+
+// ReversalWindow is a row of data from the `reversal_windows` table - period
+// payments of the asset between accounts of specified types can be reversed within
+type ReversalWindow struct {
+	ID int64 `db:"id"`
+	// empty if window applies to all assets
+	AssetCode string `db:"asset_code"`
+	// empty if window applies to all issuers of the asset code
+	AssetIssuer string `db:"asset_issuer"`
+	// account type of payment sender. Null if window applies to all types
+	SenderType null.Int `db:"sender_type"`
+	// account type of payment receiver. Null if window applies to all types
+	ReceiverType null.Int `db:"receiver_type"`
+	// seconds payment can be reversed within
+	Duration int64 `db:"duration"`
+}
+
+// ReversalWindowsQ is a helper struct to aid in configuring queries that loads
+// slices of ReversalWindow structs.
+type ReversalWindowsQ struct {
+	Err    error
+	parent *Q
+	sql    sq.SelectBuilder
+}
+
+// GetDuration returns period payment can be reversed within
+func (w *ReversalWindow) GetDuration() time.Duration {
+	return time.Duration(w.Duration) * time.Second
+}
+
+// PagingToken returns a cursor for this window
+func (w *ReversalWindow) PagingToken() string {
+	id := TotalOrderID{ID: w.ID}
+	return id.PagingToken()
+}
+
+// ReversalWindows provides a helper to filter rows from the `reversal_windows` table
+func (q *Q) ReversalWindows() *ReversalWindowsQ {
+	return &ReversalWindowsQ{
+		parent: q,
+		sql:    selectReversalWindow,
+	}
+}
+
+// ReversalWindowByKey tries to select window by asset code, issuer and account types.
+// Nil account type matches window applying to all types. If not found, returns nil,nil
+func (q *Q) ReversalWindowByKey(assetCode, assetIssuer string, senderType, receiverType *int32) (*ReversalWindow, error) {
+	sql := selectReversalWindow.Where("rw.asset_code = ? AND rw.asset_issuer = ?", assetCode, assetIssuer)
+	sql = whereAccountType(sql, "rw.sender_type", senderType)
+	sql = whereAccountType(sql, "rw.receiver_type", receiverType)
+
+	var window ReversalWindow
+	err := q.Get(&window, sql)
+	if err != nil {
+		if q.Repo.NoRows(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &window, nil
+}
+
+func whereAccountType(sql sq.SelectBuilder, column string, accountType *int32) sq.SelectBuilder {
+	if accountType == nil {
+		return sql.Where(column + " IS NULL")
+	}
+	return sql.Where(column+" = ?", *accountType)
+}
+
+// ReversalWindowFor selects the most specific window applying to payment of
+// the asset from sender to receiver. Account types outweigh asset, same as for
+// commissions, issuer refines asset code. If no window applies, returns nil,nil
+func (q *Q) ReversalWindowFor(assetCode, assetIssuer string, senderType, receiverType xdr.AccountType) (*ReversalWindow, error) {
+	sql := selectReversalWindow.
+		Where("(rw.asset_code = '' OR rw.asset_code = ?)", assetCode).
+		Where("(rw.asset_issuer = '' OR rw.asset_issuer = ?)", assetIssuer).
+		Where("(rw.sender_type IS NULL OR rw.sender_type = ?)", int32(senderType)).
+		Where("(rw.receiver_type IS NULL OR rw.receiver_type = ?)", int32(receiverType)).
+		OrderBy("(CASE WHEN rw.asset_code = '' THEN 0 ELSE 1 END) + " +
+			"(CASE WHEN rw.asset_issuer = '' THEN 0 ELSE 1 END) + " +
+			"(CASE WHEN rw.sender_type IS NULL THEN 0 ELSE 3 END) + " +
+			"(CASE WHEN rw.receiver_type IS NULL THEN 0 ELSE 3 END) DESC").
+		Limit(1)
+
+	var window ReversalWindow
+	err := q.Get(&window, sql)
+	if err != nil {
+		if q.Repo.NoRows(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &window, nil
+}
+
+// ReversalWindowInsert stores new window
+func (q *Q) ReversalWindowInsert(window *ReversalWindow) error {
+	if window == nil {
+		return nil
+	}
+
+	insert := insertReversalWindow.Values(
+		window.AssetCode,
+		window.AssetIssuer,
+		window.SenderType,
+		window.ReceiverType,
+		window.Duration,
+	)
+	_, err := q.Exec(insert)
+	if err != nil {
+		log.WithStack(err).WithError(err).WithField("window", *window).Error("Failed to insert reversal window")
+	}
+	return err
+}
+
+// ReversalWindowUpdate updates duration of the window
+func (q *Q) ReversalWindowUpdate(window *ReversalWindow) (bool, error) {
+	if window == nil {
+		return false, nil
+	}
+
+	update := sq.Update("reversal_windows").Set("duration", window.Duration).Where("id = ?", window.ID)
+	result, err := q.Exec(update)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	return rows != 0, err
+}
+
+// ReversalWindowDelete deletes window by id
+func (q *Q) ReversalWindowDelete(id int64) (bool, error) {
+	result, err := q.Exec(sq.Delete("reversal_windows").Where("id = ?", id))
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	return rows != 0, err
+}
+
+// ForAsset filters windows by asset code
+func (q *ReversalWindowsQ) ForAsset(assetCode string) *ReversalWindowsQ {
+	q.sql = q.sql.Where("rw.asset_code = ?", assetCode)
+	return q
+}
+
+// ForAssetIssuer filters windows by asset issuer
+func (q *ReversalWindowsQ) ForAssetIssuer(assetIssuer string) *ReversalWindowsQ {
+	q.sql = q.sql.Where("rw.asset_issuer = ?", assetIssuer)
+	return q
+}
+
+// ForAccountType filters windows applying to sender or receiver of the account type
+func (q *ReversalWindowsQ) ForAccountType(accountType int32) *ReversalWindowsQ {
+	q.sql = q.sql.Where("(rw.sender_type = ? OR rw.receiver_type = ?)", accountType, accountType)
+	return q
+}
+
+// Page specifies the paging constraints for the query being built by `q`.
+func (q *ReversalWindowsQ) Page(page db2.PageQuery) *ReversalWindowsQ {
+	if q.Err != nil {
+		return q
+	}
+
+	q.sql, q.Err = page.ApplyTo(q.sql, "rw.id")
+	return q
+}
+
+// Select loads the results of the query specified by `q` into `dest`.
+func (q *ReversalWindowsQ) Select(dest interface{}) error {
+	if q.Err != nil {
+		return q.Err
+	}
+
+	q.Err = q.parent.Select(dest, q.sql)
+	return q.Err
+}
+
+var selectReversalWindow = sq.Select("rw.*").From("reversal_windows rw")
+var insertReversalWindow = sq.Insert("reversal_windows").Columns(
+	"asset_code",
+	"asset_issuer",
+	"sender_type",
+	"receiver_type",
+	"duration",
+)
@@ -0,0 +1,62 @@
+package history
+
+import (
+	"testing"
+
+	"bitbucket.org/atticlab/go-smart-base/keypair"
+	"bitbucket.org/atticlab/go-smart-base/xdr"
+	"bitbucket.org/atticlab/horizon/test"
+	"github.com/guregu/null"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReversalWindowFor(t *testing.T) {
+	tt := test.Start(t).Scenario("base")
+	defer tt.Finish()
+	q := &Q{tt.HorizonRepo()}
+
+	bank, err := keypair.Random()
+	assert.Nil(t, err)
+	issuer, err := keypair.Random()
+	assert.Nil(t, err)
+	merchant := null.IntFrom(int64(xdr.AccountTypeAccountMerchant))
+
+	windows := []ReversalWindow{
+		{AssetCode: "UAH", Duration: 10},
+		{AssetCode: "UAH", AssetIssuer: bank.Address(), Duration: 20},
+		{AssetCode: "UAH", AssetIssuer: issuer.Address(), Duration: 30},
+		{AssetCode: "UAH", ReceiverType: merchant, Duration: 40},
+	}
+	for i := range windows {
+		assert.Nil(t, q.ReversalWindowInsert(&windows[i]))
+	}
+
+	Convey("ReversalWindowFor", t, func() {
+		check := func(issuer string, receiverType xdr.AccountType, expectedDuration int64) {
+			window, err := q.ReversalWindowFor("UAH", issuer, xdr.AccountTypeAccountRegisteredUser, receiverType)
+			So(err, ShouldBeNil)
+			So(window, ShouldNotBeNil)
+			So(window.Duration, ShouldEqual, expectedDuration)
+		}
+
+		Convey("Issuers of the same code have own windows", func() {
+			check(bank.Address(), xdr.AccountTypeAccountRegisteredUser, 20)
+			check(issuer.Address(), xdr.AccountTypeAccountRegisteredUser, 30)
+		})
+		Convey("Window without issuer applies to other issuers", func() {
+			other, err := keypair.Random()
+			So(err, ShouldBeNil)
+			check(other.Address(), xdr.AccountTypeAccountRegisteredUser, 10)
+		})
+		Convey("Account types outweigh issuer", func() {
+			check(bank.Address(), xdr.AccountTypeAccountMerchant, 40)
+		})
+		Convey("Windows are keyed by issuer", func() {
+			window, err := q.ReversalWindowByKey("UAH", issuer.Address(), nil, nil)
+			So(err, ShouldBeNil)
+			So(window.Duration, ShouldEqual, 30)
+			So(q.ReversalWindowInsert(&ReversalWindow{AssetCode: "UAH", AssetIssuer: issuer.Address(), Duration: 50}), ShouldNotBeNil)
+		})
+	})
+}
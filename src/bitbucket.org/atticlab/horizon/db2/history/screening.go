@@ -0,0 +1,166 @@
+package history
+
+import (
+	"time"
+
+	"bitbucket.org/atticlab/horizon/log"
+	sq "github.com/lann/squirrel"
+)
+
+const (
+	// ScreeningDecisionReview is the decision of operations allowed on condition of review
+	ScreeningDecisionReview = "review"
+	// ComplianceRuleScreeningReview is the rule of alerts raised for operations
+	// screening requested to review
+	ComplianceRuleScreeningReview = "screening_review"
+)
+
+// ScreeningListEntry is a row of `screening_list` - address counterparties are screened against
+type ScreeningListEntry struct {
+	Address   string    `db:"address"`
+	Reason    string    `db:"reason"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// ScreeningResult is a row of `screening_results` - result of screening of the operation
+type ScreeningResult struct {
+	ID             int64     `db:"id"`
+	TxHash         string    `db:"tx_hash"`
+	OperationIndex int32     `db:"operation_index"`
+	OperationType  int32     `db:"operation_type"`
+	Source         string    `db:"source"`
+	Destination    string    `db:"destination"`
+	AssetCode      string    `db:"asset_code"`
+	AssetIssuer    string    `db:"asset_issuer"`
+	Provider       string    `db:"provider"`
+	Decision       string    `db:"decision"`
+	Reason         string    `db:"reason"`
+	CreatedAt      time.Time `db:"created_at"`
+}
+
+// ScreeningListEntryByAddress tries to select screening list entry by address. If not found, returns nil,nil
+func (q *Q) ScreeningListEntryByAddress(address string) (*ScreeningListEntry, error) {
+	var entry ScreeningListEntry
+	err := q.Get(&entry, selectScreeningList.Where("sl.address = ?", address))
+	if err != nil {
+		if q.Repo.NoRows(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// ScreeningListInsert adds address to the screening list
+func (q *Q) ScreeningListInsert(entry *ScreeningListEntry) error {
+	if entry == nil {
+		return nil
+	}
+
+	_, err := q.Exec(sq.Insert("screening_list").Columns("address", "reason").Values(entry.Address, entry.Reason))
+	if err != nil {
+		log.WithStack(err).WithError(err).WithField("address", entry.Address).Error("Failed to insert screening list entry")
+	}
+	return err
+}
+
+// ScreeningListUpdate updates reason of the listed address
+func (q *Q) ScreeningListUpdate(entry *ScreeningListEntry) (bool, error) {
+	if entry == nil {
+		return false, nil
+	}
+
+	update := sq.Update("screening_list").Set("reason", entry.Reason).Where("address = ?", entry.Address)
+	result, err := q.Exec(update)
+	if err != nil {
+		log.WithStack(err).WithError(err).WithField("address", entry.Address).Error("Failed to update screening list entry")
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	return rows != 0, err
+}
+
+// ScreeningListDelete removes address from the screening list
+func (q *Q) ScreeningListDelete(address string) (bool, error) {
+	result, err := q.Exec(sq.Delete("screening_list").Where("address = ?", address))
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	return rows != 0, err
+}
+
+// ScreeningResultInsert records result of the operation screening
+func (q *Q) ScreeningResultInsert(result *ScreeningResult) error {
+	if result == nil {
+		return nil
+	}
+
+	insert := insertScreeningResult.Values(
+		result.TxHash,
+		result.OperationIndex,
+		result.OperationType,
+		result.Source,
+		result.Destination,
+		result.AssetCode,
+		result.AssetIssuer,
+		result.Provider,
+		result.Decision,
+		result.Reason,
+	)
+	_, err := q.Exec(insert)
+	if err != nil {
+		log.WithStack(err).WithError(err).WithField("tx_hash", result.TxHash).Error("Failed to insert screening result")
+	}
+	return err
+}
+
+// ScreeningResultsByTransaction selects screening results of the transaction
+func (q *Q) ScreeningResultsByTransaction(dest interface{}, txHash string) error {
+	sql := selectScreeningResult.Where("sr.tx_hash = ?", txHash).OrderBy("sr.id asc")
+	return q.Select(dest, sql)
+}
+
+// ScreeningReviewAlertsInsert raises compliance alerts for operations of the
+// ledger, which were allowed by screening on condition of review. Operations
+// already raised are skipped, so it is safe to call on reingestion.
+func (q *Q) ScreeningReviewAlertsInsert(ledgerSequence int32) (int64, error) {
+	result, err := q.ExecRaw(`
+		INSERT INTO compliance_alerts (rule, address, asset_code, ledger_sequence, description, operations)
+		SELECT ?, sr.source, sr.asset_code, ht.ledger_sequence,
+			'Screening by ' || sr.provider || ' requires review' || CASE WHEN sr.reason = '' THEN '' ELSE ': ' || sr.reason END,
+			'[' || hop.id || ']'
+		FROM screening_results sr
+		JOIN history_transactions ht ON ht.transaction_hash = sr.tx_hash
+		JOIN history_operations hop ON hop.transaction_id = ht.id AND hop.application_order = sr.operation_index + 1
+		WHERE ht.ledger_sequence = ? AND sr.decision = ?
+			AND NOT EXISTS (
+				SELECT 1 FROM compliance_alerts ca
+				WHERE ca.rule = ? AND ca.ledger_sequence = ht.ledger_sequence AND ca.operations = '[' || hop.id || ']'
+			)`,
+		ComplianceRuleScreeningReview, ledgerSequence, ScreeningDecisionReview, ComplianceRuleScreeningReview,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+var selectScreeningList = sq.Select("sl.*").From("screening_list sl")
+var selectScreeningResult = sq.Select("sr.*").From("screening_results sr")
+var insertScreeningResult = sq.Insert("screening_results").Columns(
+	"tx_hash",
+	"operation_index",
+	"operation_type",
+	"source",
+	"destination",
+	"asset_code",
+	"asset_issuer",
+	"provider",
+	"decision",
+	"reason",
+)
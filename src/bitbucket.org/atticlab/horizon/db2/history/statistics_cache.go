@@ -0,0 +1,188 @@
+package history
+
+import (
+	"encoding/json"
+	"time"
+
+	"bitbucket.org/atticlab/go-smart-base/xdr"
+	"github.com/go-errors/errors"
+	sq "github.com/lann/squirrel"
+)
+
+// StatisticsCache is a row of data from the `statistics_cache` table - account's
+// statistics updated with submitted, but not yet ingested payments
+type StatisticsCache struct {
+	Account   string `db:"address"`
+	AssetCode string `db:"asset_code"`
+	Balance   int64  `db:"balance"`
+	// json array of statistics per counterparty type
+	StatisticsString string    `db:"statistics"`
+	ExpiresAt        time.Time `db:"expires_at"`
+}
+
+// Statistics returns statistics per counterparty type
+func (cache *StatisticsCache) Statistics() (map[xdr.AccountType]AccountStatistics, error) {
+	var stats []AccountStatistics
+	err := json.Unmarshal([]byte(cache.StatisticsString), &stats)
+	if err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+
+	result := make(map[xdr.AccountType]AccountStatistics, len(stats))
+	for _, stat := range stats {
+		result[xdr.AccountType(stat.CounterpartyType)] = stat
+	}
+	return result, nil
+}
+
+// SetStatistics sets statistics per counterparty type
+func (cache *StatisticsCache) SetStatistics(stats map[xdr.AccountType]AccountStatistics) error {
+	values := make([]AccountStatistics, 0, len(stats))
+	for _, stat := range stats {
+		values = append(values, stat)
+	}
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		return errors.Wrap(err, 1)
+	}
+	cache.StatisticsString = string(data)
+	return nil
+}
+
+// StatisticsProcessedOp is a row of data from the `statistics_processed_ops`
+// table - payment accounted in statistics cache
+type StatisticsProcessedOp struct {
+	Account    string    `db:"address"`
+	TxHash     string    `db:"tx_hash"`
+	Index      int       `db:"op_index"`
+	IsIncoming bool      `db:"is_incoming"`
+	Amount     int64     `db:"amount"`
+	UpdatedAt  time.Time `db:"updated_at"`
+	ExpiresAt  time.Time `db:"expires_at"`
+}
+
+// LockStatistics locks statistics cache of account-asset pair until the end of
+// current transaction
+func (q *Q) LockStatistics(address, assetCode string) error {
+	_, err := q.ExecRaw("SELECT pg_advisory_xact_lock(hashtext($1), hashtext($2))", address, assetCode)
+	return err
+}
+
+// StatisticsCacheByAccount selects not expired statistics cache. If not found, returns nil,nil
+func (q *Q) StatisticsCacheByAccount(address, assetCode string, now time.Time) (*StatisticsCache, error) {
+	var cache StatisticsCache
+	err := q.Get(&cache, selectStatisticsCache.Where("sc.address = ? AND sc.asset_code = ? AND sc.expires_at > ?",
+		address, assetCode, now))
+	if err != nil {
+		if q.Repo.NoRows(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &cache, nil
+}
+
+// StatisticsCacheSave replaces statistics cache of account-asset pair and removes
+// expired cache of all accounts. Expired cache is removed by one transaction at a
+// time, so concurrent saves do not deadlock on rows of each other.
+func (q *Q) StatisticsCacheSave(cache *StatisticsCache, now time.Time) error {
+	if cache == nil {
+		return nil
+	}
+
+	var isCleaner bool
+	err := q.GetRaw(&isCleaner, "SELECT pg_try_advisory_xact_lock(hashtext('statistics_cache'))")
+	if err != nil {
+		return err
+	}
+
+	del := sq.Delete("statistics_cache").Where("address = ? AND asset_code = ?", cache.Account, cache.AssetCode)
+	if isCleaner {
+		del = sq.Delete("statistics_cache").Where("(address = ? AND asset_code = ?) OR expires_at <= ?",
+			cache.Account, cache.AssetCode, now)
+	}
+
+	_, err = q.Exec(del)
+	if err != nil {
+		return err
+	}
+
+	insert := sq.Insert("statistics_cache").Columns(
+		"address",
+		"asset_code",
+		"balance",
+		"statistics",
+		"expires_at",
+	).Values(
+		cache.Account,
+		cache.AssetCode,
+		cache.Balance,
+		cache.StatisticsString,
+		cache.ExpiresAt,
+	)
+	_, err = q.Exec(insert)
+	return err
+}
+
+// StatisticsProcessedOpByKey selects not expired processed op. If not found, returns nil,nil
+func (q *Q) StatisticsProcessedOpByKey(address, txHash string, index int, isIncoming bool, now time.Time) (*StatisticsProcessedOp, error) {
+	var op StatisticsProcessedOp
+	err := q.Get(&op, selectStatisticsProcessedOp.Where(
+		"spo.address = ? AND spo.tx_hash = ? AND spo.op_index = ? AND spo.is_incoming = ? AND spo.expires_at > ?",
+		address, txHash, index, isIncoming, now))
+	if err != nil {
+		if q.Repo.NoRows(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &op, nil
+}
+
+// StatisticsProcessedOpInsert stores processed op and removes expired ops of the account
+func (q *Q) StatisticsProcessedOpInsert(op *StatisticsProcessedOp) error {
+	if op == nil {
+		return nil
+	}
+
+	_, err := q.Exec(sq.Delete("statistics_processed_ops").Where(
+		"address = ? AND (expires_at <= ? OR (tx_hash = ? AND op_index = ? AND is_incoming = ?))",
+		op.Account, op.UpdatedAt, op.TxHash, op.Index, op.IsIncoming))
+	if err != nil {
+		return err
+	}
+
+	insert := sq.Insert("statistics_processed_ops").Columns(
+		"address",
+		"tx_hash",
+		"op_index",
+		"is_incoming",
+		"amount",
+		"updated_at",
+		"expires_at",
+	).Values(
+		op.Account,
+		op.TxHash,
+		op.Index,
+		op.IsIncoming,
+		op.Amount,
+		op.UpdatedAt,
+		op.ExpiresAt,
+	)
+	_, err = q.Exec(insert)
+	return err
+}
+
+// StatisticsProcessedOpDelete removes processed op
+func (q *Q) StatisticsProcessedOpDelete(address, txHash string, index int, isIncoming bool) error {
+	_, err := q.Exec(sq.Delete("statistics_processed_ops").Where(
+		"address = ? AND tx_hash = ? AND op_index = ? AND is_incoming = ?",
+		address, txHash, index, isIncoming))
+	return err
+}
+
+var selectStatisticsCache = sq.Select("sc.*").From("statistics_cache sc")
+var selectStatisticsProcessedOp = sq.Select("spo.*").From("statistics_processed_ops spo")
@@ -0,0 +1,66 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"bitbucket.org/atticlab/go-smart-base/keypair"
+	"bitbucket.org/atticlab/horizon/test"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestStatisticsCacheSave(t *testing.T) {
+	tt := test.Start(t).Scenario("base")
+	defer tt.Finish()
+	q := &Q{tt.HorizonRepo()}
+
+	Convey("StatisticsCacheSave", t, func() {
+		now := time.Now()
+		newCache := func(expiresAt time.Time) *StatisticsCache {
+			kp, err := keypair.Random()
+			So(err, ShouldBeNil)
+			cache := StatisticsCache{
+				Account:   kp.Address(),
+				AssetCode: "UAH",
+				Balance:   100,
+				ExpiresAt: expiresAt,
+			}
+			err = cache.SetStatistics(nil)
+			So(err, ShouldBeNil)
+			return &cache
+		}
+		count := func(cache *StatisticsCache) int {
+			var result int
+			err := q.GetRaw(&result, "SELECT COUNT(*) FROM statistics_cache WHERE address = $1 AND asset_code = $2",
+				cache.Account, cache.AssetCode)
+			So(err, ShouldBeNil)
+			return result
+		}
+
+		expired := newCache(now.Add(-time.Minute))
+		err := q.StatisticsCacheSave(expired, now.Add(-time.Hour))
+		So(err, ShouldBeNil)
+		So(count(expired), ShouldEqual, 1)
+
+		Convey("Replaces cache of the account", func() {
+			active := newCache(now.Add(time.Hour))
+			err := q.StatisticsCacheSave(active, now)
+			So(err, ShouldBeNil)
+			active.Balance = 200
+			err = q.StatisticsCacheSave(active, now)
+			So(err, ShouldBeNil)
+			So(count(active), ShouldEqual, 1)
+
+			stored, err := q.StatisticsCacheByAccount(active.Account, active.AssetCode, now)
+			So(err, ShouldBeNil)
+			So(stored.Balance, ShouldEqual, 200)
+		})
+		Convey("Removes expired cache of other accounts", func() {
+			active := newCache(now.Add(time.Hour))
+			err := q.StatisticsCacheSave(active, now)
+			So(err, ShouldBeNil)
+			So(count(active), ShouldEqual, 1)
+			So(count(expired), ShouldEqual, 0)
+		})
+	})
+}
@@ -163,6 +163,17 @@ func (q *TransactionsQ) ForLedger(seq int32) *TransactionsQ {
 	return q
 }
 
+// ForMemo filters the query to only include transactions with specified memo.
+// If memoType is not empty, it must match too.
+func (q *TransactionsQ) ForMemo(memoType, memo string) *TransactionsQ {
+	q.sql = q.sql.Where("ht.memo = ?", memo)
+	if memoType != "" {
+		q.sql = q.sql.Where("ht.memo_type = ?", memoType)
+	}
+
+	return q
+}
+
 // Page specifies the paging constraints for the query being built by `q`.
 func (q *TransactionsQ) Page(page db2.PageQuery) *TransactionsQ {
 	if q.Err != nil {
@@ -0,0 +1,138 @@
+package history
+
+import (
+	"time"
+
+	sq "github.com/lann/squirrel"
+)
+
+// SequenceReservation is a row of data from the `txsub_sequence_reservations`
+// table - sequence number of the account claimed by submitted transaction
+type SequenceReservation struct {
+	Address   string    `db:"address"`
+	Sequence  int64     `db:"sequence"`
+	Hash      string    `db:"hash"`
+	ExpiresAt time.Time `db:"expires_at"`
+	// true if transaction was accepted by stellar-core
+	Accepted bool `db:"accepted"`
+}
+
+// LockSequence locks reservation of account's sequence until the end of
+// current transaction
+func (q *Q) LockSequence(address string, sequence int64) error {
+	_, err := q.ExecRaw("SELECT pg_advisory_xact_lock(hashtext($1), hashtext($2::text))", address, sequence)
+	return err
+}
+
+// SequenceReservationByKey selects not expired reservation. If not found, returns nil,nil
+func (q *Q) SequenceReservationByKey(address string, sequence int64, now time.Time) (*SequenceReservation, error) {
+	var reservation SequenceReservation
+	err := q.Get(&reservation, selectSequenceReservation.Where("tsr.address = ? AND tsr.sequence = ? AND tsr.expires_at > ?",
+		address, sequence, now))
+	if err != nil {
+		if q.Repo.NoRows(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &reservation, nil
+}
+
+// SequenceReservationInsert stores reservation and removes expired reservations of the account
+func (q *Q) SequenceReservationInsert(reservation *SequenceReservation, now time.Time) error {
+	if reservation == nil {
+		return nil
+	}
+
+	_, err := q.Exec(sq.Delete("txsub_sequence_reservations").Where("address = ? AND (expires_at <= ? OR sequence = ?)",
+		reservation.Address, now, reservation.Sequence))
+	if err != nil {
+		return err
+	}
+
+	insert := sq.Insert("txsub_sequence_reservations").Columns(
+		"address",
+		"sequence",
+		"hash",
+		"expires_at",
+	).Values(
+		reservation.Address,
+		reservation.Sequence,
+		reservation.Hash,
+		reservation.ExpiresAt,
+	)
+	_, err = q.Exec(insert)
+	return err
+}
+
+// SequenceReservationDelete removes reservation, if it's held by transaction with hash
+func (q *Q) SequenceReservationDelete(address string, sequence int64, hash string) error {
+	_, err := q.Exec(sq.Delete("txsub_sequence_reservations").Where("address = ? AND sequence = ? AND hash = ?",
+		address, sequence, hash))
+	return err
+}
+
+// SequenceReservationAccept marks reservation held by transaction with hash as
+// accepted and extends it until expiresAt
+func (q *Q) SequenceReservationAccept(address string, sequence int64, hash string, expiresAt time.Time) error {
+	_, err := q.Exec(sq.Update("txsub_sequence_reservations").Set("accepted", true).Set("expires_at", expiresAt).
+		Where("address = ? AND sequence = ? AND hash = ?", address, sequence, hash))
+	return err
+}
+
+// SequencesAccepted selects the highest not expired accepted sequences of the addresses
+func (q *Q) SequencesAccepted(addresses []string, now time.Time) (map[string]int64, error) {
+	var rows []struct {
+		Address  string `db:"address"`
+		Sequence int64  `db:"sequence"`
+	}
+	err := q.Select(&rows, sq.Select("tsr.address, MAX(tsr.sequence) AS sequence").From("txsub_sequence_reservations tsr").
+		Where(sq.Eq{"tsr.address": addresses}).Where("tsr.accepted AND tsr.expires_at > ?", now).GroupBy("tsr.address"))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		result[row.Address] = row.Sequence
+	}
+	return result, nil
+}
+
+// TxSubPendingInsert stores hash of submitted transaction. Submission time of
+// already stored transaction is not changed.
+func (q *Q) TxSubPendingInsert(hash string, submittedAt time.Time) error {
+	_, err := q.ExecRaw(`INSERT INTO txsub_pending (hash, submitted_at)
+		SELECT $1::varchar, $2::timestamp WHERE NOT EXISTS (SELECT 1 FROM txsub_pending WHERE hash = $1)`,
+		hash, submittedAt)
+	return err
+}
+
+// TxSubPendingDelete removes hash of submitted transaction
+func (q *Q) TxSubPendingDelete(hash string) error {
+	_, err := q.Exec(sq.Delete("txsub_pending").Where("hash = ?", hash))
+	return err
+}
+
+// TxSubPendingHashes selects hashes of all submitted transactions
+func (q *Q) TxSubPendingHashes() ([]string, error) {
+	var hashes []string
+	err := q.Select(&hashes, sq.Select("tp.hash").From("txsub_pending tp").OrderBy("tp.submitted_at"))
+	return hashes, err
+}
+
+// TxSubPendingDeleteBefore removes transactions submitted before the provided
+// time and returns number of transactions left
+func (q *Q) TxSubPendingDeleteBefore(submittedAt time.Time) (int, error) {
+	_, err := q.Exec(sq.Delete("txsub_pending").Where("submitted_at < ?", submittedAt))
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = q.GetRaw(&count, "SELECT COUNT(*) FROM txsub_pending")
+	return count, err
+}
+
+var selectSequenceReservation = sq.Select("tsr.*").From("txsub_sequence_reservations tsr")
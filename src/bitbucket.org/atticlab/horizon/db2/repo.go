@@ -9,6 +9,7 @@ import (
 	"github.com/jmoiron/sqlx"
 	sq "github.com/lann/squirrel"
 	"bitbucket.org/atticlab/horizon/log"
+	"bitbucket.org/atticlab/horizon/tracing"
 	"golang.org/x/net/context"
 )
 
@@ -70,8 +71,10 @@ func (r *Repo) Get(dest interface{}, query sq.Sqlizer) error {
 func (r *Repo) GetRaw(dest interface{}, query string, args ...interface{}) error {
 	query = r.conn().Rebind(query)
 	start := time.Now()
+	span := r.startSpan("get", query)
 	err := r.conn().Get(dest, query, args...)
 	r.log("get", start, query, args)
+	r.endSpan(span, err)
 
 	if err == nil {
 		return nil
@@ -117,8 +120,10 @@ func (r *Repo) ExecAll(script string) error {
 func (r *Repo) ExecRaw(query string, args ...interface{}) (sql.Result, error) {
 	query = r.conn().Rebind(query)
 	start := time.Now()
+	span := r.startSpan("exec", query)
 	result, err := r.conn().Exec(query, args...)
 	r.log("exec", start, query, args)
+	r.endSpan(span, err)
 
 	if err == nil {
 		return result, nil
@@ -150,8 +155,10 @@ func (r *Repo) Query(query sq.Sqlizer) (*sqlx.Rows, error) {
 func (r *Repo) QueryRaw(query string, args ...interface{}) (*sqlx.Rows, error) {
 	query = r.conn().Rebind(query)
 	start := time.Now()
+	span := r.startSpan("query", query)
 	result, err := r.conn().Queryx(query, args...)
 	r.log("query", start, query, args)
+	r.endSpan(span, err)
 
 	if err == nil {
 		return result, nil
@@ -194,8 +201,10 @@ func (r *Repo) SelectRaw(
 	r.clearSliceIfPossible(dest)
 	query = r.conn().Rebind(query)
 	start := time.Now()
+	span := r.startSpan("select", query)
 	err := r.conn().Select(dest, query, args...)
 	r.log("select", start, query, args)
+	r.endSpan(span, err)
 
 	if err == nil {
 		return nil
@@ -259,6 +268,21 @@ func (r *Repo) log(typ string, start time.Time, query string, args []interface{}
 	}
 }
 
+// startSpan starts span of the query, child of the span in repo's context
+func (r *Repo) startSpan(typ string, query string) *tracing.Span {
+	_, span := tracing.StartWithKind(r.logCtx(), "sql: "+typ, tracing.SpanKindClient)
+	span.SetAttribute("db.system", "postgresql")
+	span.SetAttribute("db.statement", query)
+	return span
+}
+
+func (r *Repo) endSpan(span *tracing.Span, err error) {
+	if !r.NoRows(err) {
+		span.SetError(err)
+	}
+	span.End()
+}
+
 func (r *Repo) logBegin() {
 	log.Ctx(r.logCtx()).Debug("sql: begin")
 }
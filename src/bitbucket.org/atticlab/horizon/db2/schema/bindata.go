@@ -1,8 +1,30 @@
 // Code generated by go-bindata.
 // sources:
 // latest.sql
+// migrations/10_operation_filters.sql
+// migrations/11_memo_index.sql
+// migrations/12_screening.sql
+// migrations/13_compliance_alerts.sql
+// migrations/14_account_freezes.sql
+// migrations/15_reversal_windows.sql
+// migrations/16_payment_refunds.sql
+// migrations/17_disputes.sql
+// migrations/18_statistics_cache.sql
+// migrations/19_txsub_shared_state.sql
 // migrations/1_initial_schema.sql
+// migrations/20_leader_leases.sql
+// migrations/21_asset_metadata.sql
+// migrations/22_asset_supply.sql
+// migrations/23_screening_assets.sql
+// migrations/24_payment_party_index.sql
+// migrations/25_account_freeze_events.sql
+// migrations/26_txsub_accepted_sequences.sql
+// migrations/27_asset_supply_backfill.sql
+// migrations/28_payment_refund_reservations.sql
+// migrations/29_leader_fences.sql
 // migrations/2_index_participants_by_toid.sql
+// migrations/30_statistics_cache_expiration.sql
+// migrations/31_reversal_window_issuer.sql
 // migrations/3_aggregate_expenses_for_accounts.sql
 // migrations/7_account_limits.sql
 // migrations/8_account_limits_two_way.sql
@@ -96,6 +118,206 @@ func latestSql() (*asset, error) {
 	return a, nil
 }
 
+var _migrations10_operation_filtersSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\x03\xa5\x52\xcd\x6a\xc2\x40\x10\xbe\xef\x53\xcc\xcd\x84\x36\x2f\x60\x40\x90\x26\x88\x97\x28\xb6\x42\x6f\xcb\xba\x19\x74\xc1\xec\x84\x9d\x09\x9a\xb7\xaf\xb6\xc4\x1a\x12\x41\xda\xdb\xc0\x7c\x7f\xf3\x31\x49\x02\x2f\x95\xdb\x07\x23\x08\xdb\x5a\xa9\xb7\x4d\x3e\xff\xc8\x61\x59\x64\xf9\x27\x1c\xa8\xd6\xbb\x56\x1b\x66\x14\x6d\xa9\x44\x58\x15\x70\x70\x2c\x14\x5a\x4d\x35\x5e\x58\x8e\x3c\xc3\xf6\x7d\x59\x2c\x60\x27\x01\x11\xa2\x28\x2a\x51\x8c\x3b\x32\x24\xb3\x19\x4c\x7e\xc9\x93\x78\x3a\x15\x3c\x4b\xfc\x0a\xa3\x18\xc7\xdc\x60\xb8\xa1\xe2\x74\x34\x0c\x53\x13\x2c\xfe\x2f\xd3\x40\xe3\x71\xb4\x1e\xf4\xb9\x84\xa6\xa2\xc6\xcb\x5f\xaa\xfa\x26\x5e\xe5\x7d\x53\x61\x70\x76\xe0\xe0\x7c\x89\x67\xdd\xc9\x1a\x6b\xaf\x04\xd6\xe4\xbb\x59\x4b\x5b\xf7\x1a\xe9\x30\x7d\xe3\x7b\xf4\xc5\x43\x25\x77\x5f\x90\xd1\xc9\x2b\x95\x6d\x56\xeb\x47\x5f\x90\x8e\x6c\x07\x95\x8e\x81\x7e\x0e\xec\x6d\x9e\xbb\x28\x55\x5f\x09\x71\xa4\x85\xa8\x02\x00\x00")
+
+func migrations10_operation_filtersSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations10_operation_filtersSql,
+		"migrations/10_operation_filters.sql",
+	)
+}
+
+func migrations10_operation_filtersSql() (*asset, error) {
+	bytes, err := migrations10_operation_filtersSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/10_operation_filters.sql", size: 680, mode: os.FileMode(420), modTime: time.Unix(1792401655, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations11_memo_indexSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\x03\x65\x8d\xb1\x0a\xc2\x30\x18\x06\xf7\xff\x29\xbe\xd1\xa2\x79\x82\x4e\x62\x83\x06\x4a\x2a\x69\x8b\x6e\xa1\x95\x60\x33\xa4\x29\xe9\x0f\x9a\xb7\x97\xe2\x22\xb8\xdc\x70\x1c\x9c\x10\xd8\x07\xff\x4c\x03\x3b\xf4\x0b\xd1\xc9\xc8\x63\x27\xa1\x74\x25\xef\x98\xf8\x6d\xc7\x6c\x83\x0b\x11\x8d\xc6\xe4\x57\x8e\x29\x5b\x4e\xc3\xbc\x0e\x0f\xf6\x71\x5e\xd1\xb7\x4a\x9f\x31\x72\x72\x0e\xbb\xad\x3c\x60\xa3\xe5\xbc\xb8\x02\xb7\x8b\x34\xf2\xeb\xa1\x5a\xe8\xa6\x83\xee\xeb\xba\x28\x89\xc4\xcf\xb9\x8a\xaf\x99\xa8\x32\xcd\xf5\xff\x5c\xd2\x07\xde\x1f\xf9\xb5\xa4\x00\x00\x00")
+
+func migrations11_memo_indexSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations11_memo_indexSql,
+		"migrations/11_memo_index.sql",
+	)
+}
+
+func migrations11_memo_indexSql() (*asset, error) {
+	bytes, err := migrations11_memo_indexSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/11_memo_index.sql", size: 164, mode: os.FileMode(420), modTime: time.Unix(1792401655, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations12_screeningSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\x03\xc5\x93\xcb\x6e\xc2\x30\x10\x45\xf7\xfe\x8a\xd9\x91\xa8\xb0\xe8\x43\x6c\x58\xa5\x4d\x5a\xa1\xa6\x01\xa5\x89\x54\x56\x91\x89\x47\x89\x25\xb0\x23\xdb\xbc\xfa\xf5\xb5\x79\x89\x36\x44\x2c\xbb\x9d\xb9\x3e\xbe\x73\xc7\x1e\x0c\xe0\x6e\xc9\x2b\x45\x0d\x42\xde\x10\xf2\x92\x46\x41\x16\x41\x16\x3c\xc7\x11\xe8\x52\x21\x0a\x2e\xaa\x62\xc1\xb5\x21\x1e\x01\xa0\x8c\x29\xd4\x1a\xd6\x54\x95\x35\x55\xde\xf0\xc9\x87\x64\x92\x41\x92\xc7\x71\xdf\xf6\x15\x52\x2d\x05\x18\xdc\x9a\x73\x1d\xc2\xe8\x35\xc8\xe3\x0c\x7a\x3d\x27\xb1\x50\x7b\x1b\x2b\xa8\x01\xc3\x97\xa8\x0d\x5d\x36\xb0\xe1\xa6\x96\xab\x43\x05\xbe\xa5\xc0\xf6\x69\x21\x37\x9e\xef\x00\xd3\x74\xfc\x11\xa4\x33\x78\x8f\x66\xde\xd1\x8f\x4f\xfc\x51\xa7\x79\x2b\x58\x2d\x8c\xde\xfb\xe7\x0c\xe6\xbc\xd2\xa8\x38\x5d\x38\x96\xd9\x16\x35\xd5\x35\xb8\x61\x68\x69\x50\xb9\xc9\x76\xf6\x54\x6b\x32\xd9\xa0\x4d\x89\x4b\x51\x70\xc1\x70\x0b\x5c\x18\xac\xac\xfe\xba\xc6\xec\x1a\xbc\x2a\xd1\x72\xa5\x4a\xec\xcc\x8f\xd9\x3c\xb8\xd8\x33\x3a\x35\x8d\x92\x6b\xce\x0e\x56\xf7\x82\xc7\x87\xbf\x90\x92\xeb\x4b\xc2\xfd\xf0\x7f\xb7\xc4\xd9\xaf\x05\x8d\x93\x30\xfa\x6a\x2f\xa8\x98\xef\x8a\xd3\x3e\x26\x49\xbb\x0f\xf9\xe7\x38\x79\x83\xb9\xb1\x75\xf0\x8e\x4a\x8b\xbd\x4d\x3d\x07\x72\x1b\x7b\x92\xf6\x2f\x12\x70\xd6\x07\x17\x1f\x25\x94\x1b\x41\x48\x98\x4e\xa6\x5d\x6f\x6d\x74\xbd\xeb\xbe\xd1\x88\xfc\x00\x2f\x45\x5b\xe4\x74\x03\x00\x00")
+
+func migrations12_screeningSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations12_screeningSql,
+		"migrations/12_screening.sql",
+	)
+}
+
+func migrations12_screeningSql() (*asset, error) {
+	bytes, err := migrations12_screeningSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/12_screening.sql", size: 884, mode: os.FileMode(420), modTime: time.Unix(1792396319, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations13_compliance_alertsSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\x03\x8d\x52\x4d\x53\xc2\x30\x10\xbd\xe7\x57\xec\x8d\x76\x84\x83\x1f\xe3\x85\xf1\x50\x6d\x54\xc6\x5a\x98\xda\x8e\x72\xea\x84\x76\x85\x8c\x69\x52\x93\x20\xea\xaf\x37\x14\xad\x40\xc7\xc1\x63\xf6\xbd\xbc\xb7\xbb\x6f\x07\x03\x38\xaa\xf8\x5c\x33\x8b\x90\xd5\x84\x5c\x25\x34\x48\x29\xa4\xc1\x65\x44\xa1\x50\x55\x2d\x38\x93\x05\xe6\x4c\xa0\xb6\x86\x78\x04\x80\x97\x30\xe3\x73\x83\x9a\x33\xd1\x77\x6f\xbd\x14\x08\x6f\x4c\x17\x0b\xa6\xbd\xd3\x13\x1f\xe2\x71\x0a\x71\x16\x45\x6b\x90\x95\xa5\x46\x63\x5a\xfc\xfc\x6c\x0f\x37\x06\x6d\x5e\xa8\xf2\x57\xe2\x78\x4f\x42\x60\x39\x47\x9d\x1b\x7c\x5d\xa2\x6b\x05\xb8\xb4\xe8\x0a\x3b\x9c\x12\x4d\xa1\x79\x6d\xb9\x92\x60\xf1\xdd\xee\x80\xaa\x46\x37\x9f\x83\x4c\x17\x63\xc5\x8b\x54\xab\xc6\xc2\x8d\xa5\x94\x40\x26\x5b\x02\x84\xf4\x3a\xc8\xa2\x14\x9e\x99\x30\xb8\x4f\xcf\x99\x05\xcb\x2b\x34\x96\x55\x35\xac\xb8\x5d\xa8\xe5\xa6\x02\x9f\x4a\x36\x74\xb7\xc0\x0a\xa5\xdd\xb5\x6d\x55\x7b\xbd\x86\xa3\xd1\x2d\xff\x90\x5a\xf7\xb7\xeb\xc3\xf3\xd7\x02\x93\x64\x74\x1f\x24\x53\xb8\xa3\x53\x8f\x97\x3e\xf1\x87\x6d\x8c\xa3\x38\xa4\x4f\xdd\x18\xf3\xd9\x47\xfe\x13\xcc\x38\xee\xe2\x90\x3d\x8c\xe2\x1b\x98\x59\x8d\x08\xde\x37\xb3\xdf\x04\xdd\xdf\x4a\xcc\x19\x1d\xf0\x71\x9b\x97\xff\x70\x70\x5d\xc3\xe3\x2d\x4d\xa8\x33\xdb\xce\xe3\x62\xb3\xf8\xf5\x40\x83\xad\x3b\x0d\xd5\x4a\x12\x12\x26\xe3\xc9\x5f\x77\x3a\x24\x5f\x5a\x04\xc7\xc6\xd8\x02\x00\x00")
+
+func migrations13_compliance_alertsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations13_compliance_alertsSql,
+		"migrations/13_compliance_alerts.sql",
+	)
+}
+
+func migrations13_compliance_alertsSql() (*asset, error) {
+	bytes, err := migrations13_compliance_alertsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/13_compliance_alerts.sql", size: 728, mode: os.FileMode(420), modTime: time.Unix(1792396553, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations14_account_freezesSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\x03\xdd\x94\x4d\x53\xc2\x30\x10\x86\xef\xfd\x15\x7b\xb3\x1d\xe9\x41\x74\xb8\x70\x42\xa9\xca\x88\xc5\xa9\x30\xea\x29\x93\xb6\x0b\x64\x6c\x93\x4e\xb2\x80\xf8\xeb\x4d\xf9\xd2\x22\xf8\x35\x8e\x07\x6f\x49\xf6\xdd\x77\xdf\x34\xcf\xd4\xf7\xe1\x30\x17\x23\xcd\x09\x61\x50\x38\xce\x59\x14\xb4\xfa\x01\xf4\x5b\xa7\xdd\x00\x78\x92\xa8\x89\x24\x36\xd4\x88\xcf\x68\x1c\xd7\x01\x10\x29\xc4\x62\x64\x50\x0b\x9e\xd5\xec\x9e\xa7\xa9\x46\x63\x60\xca\x75\x32\xe6\xda\x6d\x9c\x78\x10\xf6\xfa\x10\x0e\xba\xdd\xb2\xee\xfb\x80\x79\x41\x73\xe0\xc6\x20\x41\xa2\x52\x84\x1c\xb9\x34\xb0\x74\x05\x35\x04\x9e\x65\xcb\xb2\x29\x0d\xcb\x05\x5b\xe8\xd6\x9e\x47\xf5\x57\x4f\x68\x07\xe7\xad\x41\xb7\x0f\x07\x07\xa5\x7d\x9c\xa9\xe4\x91\x09\x99\xa8\x5c\xc8\x11\x2b\xf8\x3c\x47\x49\x06\x62\xa5\x32\x3b\xe5\x7d\xdb\x90\x67\x06\x5f\x3b\xd5\x84\xbe\xdf\xaa\x91\x1b\x25\xab\x19\x8f\xeb\xd5\x7b\x5b\xd7\xd2\x0e\x08\x9f\x68\x5f\x78\x7c\x2a\x84\xfd\x78\x8c\x5b\x99\xc8\xd1\x10\xcf\x0b\x98\x09\x1a\xdb\x54\x8b\x13\x78\x56\x72\x31\x31\x13\x43\xc2\xf4\x0b\xc2\xc4\x66\xfb\x5c\xf9\x3e\x90\x54\x33\xd7\x2b\x0d\x6e\xa2\xce\x75\x2b\x7a\x80\xab\xe0\xc1\x15\xa9\xe7\x78\xcd\x0d\x14\x9d\xb0\x1d\xdc\x6f\x43\xc1\x78\x42\x62\x8a\x2c\x9e\xb3\x35\x0a\xbd\x70\x5b\x04\x83\xdb\x4e\x78\x01\x31\xd9\x2d\xb8\x2b\x9d\x07\x77\x97\x41\x14\xbc\xb9\x5b\xe7\x76\x11\xaa\xb9\x07\x43\xd2\x5c\x90\x61\x63\x61\x48\xe9\xf9\x8f\x68\xc4\x69\xf9\x26\x1b\xae\x1a\xd5\xea\x32\x2d\x5b\x9a\x0a\x49\xb5\xff\x81\xe3\x56\xc6\xdf\x25\xf3\xef\x80\xab\x3e\xff\x1e\xe0\xaa\xa2\x9d\xdc\xd5\x2c\x35\xe5\x10\xff\xcd\xaf\xaf\xad\x66\xd2\x71\xda\x51\xef\xe6\x43\xe6\x9a\xbb\x24\x2b\xc6\x9b\xce\x0b\x42\x8c\xff\xef\x4c\x05\x00\x00")
+
+func migrations14_account_freezesSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations14_account_freezesSql,
+		"migrations/14_account_freezes.sql",
+	)
+}
+
+func migrations14_account_freezesSql() (*asset, error) {
+	bytes, err := migrations14_account_freezesSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/14_account_freezes.sql", size: 1356, mode: os.FileMode(420), modTime: time.Unix(1792396913, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations15_reversal_windowsSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\x03\x75\x92\xc1\x6e\x83\x30\x10\x44\xef\xfe\x8a\xbd\x05\xd4\x50\x29\xbd\xe6\x44\x83\x5b\xa1\x52\x48\x09\x48\x4d\x2f\xc8\x98\x55\x62\x15\x6c\x64\x3b\x41\xfc\x7d\x01\x45\x29\x51\xda\x93\xe5\x9d\xf1\xac\xf7\x69\x3d\x0f\x1e\x1a\x71\xd0\xcc\x22\xe4\x2d\x21\x9b\x94\xfa\x19\x85\xcc\x7f\x8e\x28\x68\x3c\xa3\x36\xac\x2e\x3a\x21\x2b\xd5\x19\xe2\x10\x00\x51\x41\x29\x0e\x06\xb5\x60\xf5\x72\xb8\x7b\x1e\x60\xd3\xda\x1e\x98\x31\x68\x81\xab\x0a\xa1\x61\x96\x1f\xd1\x00\x93\x97\xf2\xe0\x9b\xce\x62\x92\xcf\x4c\xf3\x23\xd3\xce\xea\xc9\x85\x38\xc9\x20\xce\xa3\x08\x02\xfa\xe2\xe7\x51\x06\x8b\xc5\x25\x55\x9e\xea\x1a\x18\xe7\xea\x24\x2d\xd8\xbe\xbd\x8d\x1d\x0b\x83\xcf\xa0\xac\x50\x17\x93\x2c\xa4\xc5\x03\xea\xf1\xb9\x46\x8e\xe2\xfc\x87\x30\xe4\x1a\xe4\x4a\x56\x06\x5a\xd6\x37\x38\x44\x73\x26\xa1\xc4\xcb\xb0\x58\x41\x27\xec\x51\xc8\x47\xf8\x42\xad\xa0\x41\x26\x6f\xad\x52\xd9\xb9\x7d\xc8\xac\x4e\x03\x3e\xa1\xe4\xc8\x65\x68\x75\x1d\x69\xec\xb7\x4d\xc3\x77\x3f\xdd\xc3\x1b\xdd\x3b\xa2\x72\x89\xbb\xbe\x32\xce\xe3\xf0\x23\xa7\x10\xc6\x01\xfd\xbc\x43\x5d\x94\x7d\xf1\x8d\x3d\x24\xf1\x9d\x04\xf9\x2e\x8c\x5f\xa1\xb4\x1a\x11\x9c\x5f\xac\x4b\xd8\x24\x7e\x44\x77\x1b\xea\xcc\xa8\x2c\xc1\x5b\xb9\x33\xe9\x86\xcc\x24\x8e\x5f\xf2\x66\x6b\x10\xa8\x4e\x12\x12\xa4\xc9\xf6\x9f\x35\x58\x93\x1f\x1b\x5f\xa1\xe0\x36\x02\x00\x00")
+
+func migrations15_reversal_windowsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations15_reversal_windowsSql,
+		"migrations/15_reversal_windows.sql",
+	)
+}
+
+func migrations15_reversal_windowsSql() (*asset, error) {
+	bytes, err := migrations15_reversal_windowsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/15_reversal_windows.sql", size: 566, mode: os.FileMode(420), modTime: time.Unix(1792397414, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations16_payment_refundsSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\x03\x75\x52\xc1\x4e\xe3\x30\x10\xbd\xfb\x2b\xe6\x46\xbb\x24\xd5\x72\x05\x09\x54\x5a\xef\x52\x6d\x49\x50\x9a\x0a\x38\x55\x6e\x33\x69\x2d\x12\x3b\xb2\x9d\x65\xf3\xf7\x8c\x9b\x84\x96\xd2\xf5\xc9\xf6\x9b\xf7\x66\xde\xb3\xc3\x10\x2e\x4b\xb9\x35\xc2\x21\x2c\x2b\xc6\xc2\x10\x0c\xfe\x45\x63\x45\x61\x41\xa8\x8c\x4e\x79\xad\x32\x0b\x3a\x87\x4a\x34\x25\x2a\x67\x47\xb0\xa8\x4b\x7f\x21\x4a\x5d\xd3\x99\x6a\x5c\x6d\x14\x66\x90\x6b\x03\x6e\x87\x7d\xa5\x57\x2b\x6b\xeb\x40\x69\x07\xf8\x6f\x83\x54\xd2\x41\x1d\x97\x4d\x12\x3e\x4e\x39\xa4\xe3\xfb\x39\xef\xb1\x55\xd7\x93\x0d\x18\x00\x49\xc8\xcc\x37\xeb\xc7\x02\xea\xd1\x16\x80\xae\x90\x06\x97\x5a\x51\x1d\x15\xad\xe5\x56\x92\x72\x14\xa7\x10\x2d\xe7\xf3\x80\x6e\x7b\xc5\xf3\xa8\x6b\x2a\x04\xba\xc4\x2d\x9a\x2f\x40\x3b\xdc\x39\xca\x53\x32\x7b\x1c\x27\xaf\xf0\x87\xbf\x0e\x64\x36\x64\xc3\x1b\xd6\x7b\x98\x45\x53\xfe\x72\xea\x61\xb5\x6e\x56\xbd\xe5\x38\x3a\x45\x61\xb9\x98\x45\xbf\x61\xed\x0c\x22\x0c\x0e\xc3\x7a\x55\x32\xbe\x16\x9b\xb7\x5c\x16\x05\xe4\x46\x97\xff\x79\x17\x51\x18\x14\x59\x43\x36\xb6\x68\x1d\x66\xc1\xfe\x01\xb4\x2a\x9a\x43\x3c\xd6\x8b\xbd\x4b\xb7\x3b\xce\x43\x2a\xc8\xd0\x09\x59\x58\x36\x8b\x16\x3c\x49\xc9\x40\x1a\x7f\x9b\x90\x5c\x06\x47\xb4\x60\x1f\x5a\xd0\x25\x34\x64\x0b\x3e\xe7\x93\x94\x82\xd9\xe9\x6a\x44\x30\xed\x06\x7e\xdb\x49\x87\xb7\xb7\x17\x07\xf2\xc5\xf0\xfa\xba\xcd\x34\xe8\x18\x7b\x31\xcf\x39\x25\xb5\xfa\x9e\xa0\xea\x12\x8d\xdc\xc0\x0f\xb8\xfa\xd9\xae\x4f\x15\xf6\x2b\x89\x1f\x61\x27\xad\xd3\xa6\x59\x1d\xec\x7a\x69\xf6\xfc\xc0\x13\x0e\x47\xb2\x70\x07\xc7\xb3\xb4\x11\x7f\x7e\xfe\xa9\x7e\x57\x8c\x4d\x93\xf8\xe9\xfc\x67\xbc\x61\x1f\x17\x29\x2a\x93\x2b\x03\x00\x00")
+
+func migrations16_payment_refundsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations16_payment_refundsSql,
+		"migrations/16_payment_refunds.sql",
+	)
+}
+
+func migrations16_payment_refundsSql() (*asset, error) {
+	bytes, err := migrations16_payment_refundsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/16_payment_refunds.sql", size: 811, mode: os.FileMode(420), modTime: time.Unix(1792401716, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations17_disputesSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\x03\xad\x54\x4d\x93\x93\x40\x10\xbd\xf3\x2b\xfa\x06\x29\x93\x83\x1f\xe5\x65\x4f\xd1\xa0\x52\x46\xb2\x66\x43\xe9\x96\x65\x51\x13\xa6\x93\x8c\xc2\x0c\x35\x33\x04\xb3\xbf\xde\x1e\x13\x20\xb8\x21\xa5\xa5\x37\xe8\x6e\x5e\xbf\xee\x7e\x8f\xc9\x04\x9e\x14\x62\xab\x99\x45\x48\x4a\xcf\x7b\xbd\x0c\xa7\xab\x10\x56\xd3\x57\xf3\x10\xb8\x30\x65\x65\xd1\x78\x81\x07\x20\x38\xac\xc5\xd6\xa0\x16\x2c\x1f\xd3\x7b\xc9\x0e\x05\x4a\x9b\x1e\xe3\x42\x5a\x88\x17\x2b\x88\x93\xf9\xdc\x65\x8d\x75\x88\x7b\xa6\xb3\x1d\xd3\xc1\xf3\x67\xa3\x5e\x76\x32\x01\x83\x92\xa3\x06\xb5\x01\xbb\xc3\xa6\x13\x6f\x50\xa9\x26\xcb\x99\x28\x18\xc1\x36\x20\x2f\x5f\x3c\x02\xd1\x98\xa1\xd8\x0f\xc3\x8c\x41\x58\x03\x9b\x4a\x72\x03\x4c\x23\xe4\x2a\xfb\x4e\xd9\x7a\x27\xf2\xb6\x18\x84\x01\x55\xa2\x24\x44\x8d\xa6\x54\x44\xeb\x4a\x53\x66\x0c\xda\x34\x53\xbc\x1b\xee\xe9\x6f\xc3\xb1\x42\x55\x84\x70\x61\x29\x1b\x8d\xf8\x80\xdd\xc6\xda\x29\x68\x04\xc3\x72\x50\x9a\x9e\x1d\x5b\x47\x88\x4e\x22\x94\x74\x5c\xb3\x1d\x85\x6d\xa5\x25\x51\x77\x53\x76\x3b\x22\xc2\x2a\xaf\x5c\x5d\xda\x7e\xd1\x87\xcf\x34\xd2\x21\x78\xca\x2c\x58\x51\x20\x9d\xa5\x28\xa1\x16\x76\xa7\xaa\x63\x04\x1e\x94\xc4\x96\x25\xcc\xc2\x37\xd3\x64\xbe\x02\xa9\xea\x60\xe4\x00\xaa\x92\xff\x1b\xc0\xed\x32\xfa\x30\x5d\xde\xc3\xfb\xf0\x3e\x10\x7c\xe4\x8d\x6e\x5a\x95\x45\xf1\x2c\xfc\xdc\xaa\x2c\x5d\x1f\xd2\xd3\x68\xb0\x88\xdb\x30\x24\x77\x51\xfc\x16\xd6\x96\x96\x07\x41\xa7\x3a\xc2\x19\x84\x69\xb5\x33\x88\xd3\x54\x90\x42\xae\x22\x9d\x49\x62\x10\xab\xab\xe9\xa3\x25\x71\xf4\x31\x79\x04\xca\x32\x4b\x92\xfd\xdb\x61\xe1\xd3\xbb\x70\x19\x9e\x6c\x15\xc5\x10\xf8\x4e\xb3\xfe\x18\xfc\x02\x9d\x0c\xa9\xaa\xa1\xc1\xfd\xb3\x15\xf7\x8c\x9c\x4a\x35\xe4\xe6\xa6\xe2\xb2\x9b\x9d\x5f\x7f\x75\x6e\x3c\x53\x33\x03\x85\xda\x3b\x41\xaa\x31\x60\x51\xda\x03\x88\xcd\xa9\xc8\x25\xa9\x13\x38\x5a\x5b\xe4\x57\xff\x06\xad\x5e\x7c\xdf\x35\x72\x04\xc1\xe2\x0f\x3b\x54\x40\x4c\xbe\x19\xb2\x05\xd3\x9a\x1d\x9c\xed\xc9\x30\xa8\x51\x66\xb4\x3b\xab\x00\xf7\x82\xbb\x17\xe0\x2a\xab\xdc\xee\x0c\x7d\xd3\x06\x07\x80\xbf\x7c\xf5\xff\x8b\x55\xfe\x54\xe9\xc7\x33\x38\x05\x34\xeb\xec\x14\x70\xcc\xf5\x65\xd0\xdd\xe6\xa4\x2f\x6f\x72\xf6\xd7\x9e\xa9\x5a\x7a\xde\x6c\xb9\xb8\xbd\x74\xec\x9b\x0b\x19\x0a\xfe\x04\x1d\x58\xe7\xb1\xf7\x05\x00\x00")
+
+func migrations17_disputesSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations17_disputesSql,
+		"migrations/17_disputes.sql",
+	)
+}
+
+func migrations17_disputesSql() (*asset, error) {
+	bytes, err := migrations17_disputesSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/17_disputes.sql", size: 1527, mode: os.FileMode(420), modTime: time.Unix(1792397783, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations18_statistics_cacheSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\x03\xad\x93\x41\x8f\xd3\x30\x10\x85\xef\xfe\x15\x73\x6c\x45\xba\x12\x08\x71\xe9\xa9\xd0\x08\x55\x94\x74\x55\x5a\x89\x3d\x45\x63\x7b\x48\x0c\x1b\xdb\xb2\x27\x6c\xc3\xaf\xc7\xcd\x66\x4b\x2a\x88\x16\x24\x8e\xc9\x7b\x76\xe6\x7d\x6f\xb2\x58\xc0\x8b\xc6\x54\x01\x99\xe0\xe8\x85\x58\x2c\x00\x95\x72\xad\x65\x88\x8c\x6c\x22\x1b\x15\xa1\xf5\x3a\x19\x34\x3c\x18\xae\x21\xb6\xb2\x31\x9c\x1e\x33\x90\x2d\x83\x75\x0c\x1d\x31\x18\x5b\x51\x3c\x9b\x3c\x76\x0d\x59\x8e\x37\xe7\xcb\x8e\x31\xbd\x31\x36\x09\xa8\xc1\x7d\x81\x40\xda\x44\x90\x1d\x78\x17\xb9\x0a\x14\xc7\x9f\x91\xa8\xbe\x91\xd5\xe2\xdd\x3e\x5f\x1d\x72\x38\xac\xde\x6e\xf3\x91\x5e\x2a\x54\x35\x89\x99\x00\x40\xad\xd3\xd9\x08\xdf\x31\xa8\x1a\xc3\xec\xcd\xeb\x39\x14\xbb\x03\x14\xc7\xed\x36\x3b\xeb\x31\x12\x97\xca\x69\xba\x58\x5e\xbe\xba\xb6\x48\xbc\x47\xab\x08\xa4\xa9\x4c\x4a\x3b\x96\xd2\xdc\x5f\xa3\xb3\x80\x21\x60\x77\x9e\x7a\x34\xa3\xa7\x00\x3d\x1f\x0a\x1e\x03\x77\xc0\x9d\xa7\x74\x66\x64\x61\x3a\x5d\xdf\x47\x27\x6f\xd2\xb8\x25\x32\xb0\x69\x12\x25\x6c\x7c\xcf\xd2\xb5\x8f\x6f\xe0\x87\xb3\x74\x75\xe4\x76\xbf\xf9\xb8\xda\xdf\xc1\x87\xfc\x6e\x36\x84\xcd\x46\xa9\xe6\x62\xbe\xec\xcb\x7a\x82\xfd\xd4\x5a\x4f\xfb\x77\x66\x53\x44\x7d\x70\x2a\x5d\x4d\xba\x74\x3e\xfe\x15\x59\x3e\x95\x35\xc6\x7a\x52\x77\xbe\x34\x56\xd3\x29\x8d\xc1\x54\x25\x58\x63\xd1\xc4\x24\x2a\xd7\xa4\x5d\x01\xe9\xdc\x3d\xa1\xbd\xae\xad\xe9\x37\xef\x0f\x95\x0c\x1b\xf8\x2f\x08\xff\x17\xf5\x21\x71\x76\x89\x96\x8d\x73\x3c\x36\x31\xf0\xdd\x14\xeb\xfc\xf3\x24\xdf\x52\x76\x65\x3f\x54\x92\xd3\x76\xed\x8a\x49\x27\x1c\x3f\x6d\x8a\xf7\x20\x39\x10\xc1\xec\x57\x90\xa1\xf4\xcb\x1f\xbb\x76\x0f\x56\x88\xf5\x7e\x77\xfb\x4c\xb5\xcb\x09\x53\xbf\x1d\x4b\xf1\x13\x7b\xbb\xa7\x6f\x06\x04\x00\x00")
+
+func migrations18_statistics_cacheSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations18_statistics_cacheSql,
+		"migrations/18_statistics_cache.sql",
+	)
+}
+
+func migrations18_statistics_cacheSql() (*asset, error) {
+	bytes, err := migrations18_statistics_cacheSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/18_statistics_cache.sql", size: 1030, mode: os.FileMode(420), modTime: time.Unix(1792398447, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations19_txsub_shared_stateSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\x03\x8d\x92\x4b\x4f\xc3\x30\x10\x84\xef\xfe\x15\x73\x6c\x45\xc3\x09\x71\xe9\xa9\xd0\x08\x55\x94\xb4\xea\x43\xa2\xa7\xc8\x71\x96\xc6\xa2\xb1\x83\xbd\xe9\x83\x5f\x8f\x43\x1f\x6a\x45\x11\x5c\x77\x67\x67\xc7\xdf\x3a\x8a\x70\x53\xea\xa5\x93\x4c\x98\x57\x42\x44\x11\xd8\x49\xe3\xa5\x62\x6d\x8d\x87\xaf\xb3\x52\x33\x53\x0e\xb6\xf0\x4c\xab\x95\x74\x91\xb2\x8e\x3a\xc8\x6a\x86\xb1\x8c\x1d\x31\xb4\x59\x52\xe8\xe6\xb7\x98\x16\xd2\x05\x75\xb6\x6b\xac\x0a\xeb\xf4\xa7\x35\x70\x54\xad\xb4\x92\x1e\xb5\x0f\x4a\x54\xd6\xf3\xd2\x91\x07\x6f\x83\x3f\x32\xa9\xde\xc9\xe4\xe2\x71\x12\xf7\x66\x31\x66\xbd\x87\x61\xbc\x6f\xa5\x55\xa8\x87\x09\xd1\x12\x40\x21\x7d\x81\xb5\x74\x2a\x6c\x68\xdd\xdf\xb5\x91\x8c\x66\x48\xe6\xc3\x21\xc6\x93\xc1\x4b\x6f\xb2\xc0\x73\xbc\xe8\x04\xe1\x29\x73\x2a\x19\xac\xcb\x90\x4c\x96\x15\x36\x9a\x0b\x5b\xef\x2b\x08\xa9\xe8\x64\x20\xda\x5d\x71\xdc\x3e\x48\xfa\xf1\xeb\xe5\xf6\x34\xdb\xa5\x17\x9e\xa3\xe4\x52\x80\xf9\x74\x90\x3c\x21\x63\x47\x84\xd6\xb9\xb4\x31\x0e\x1c\x3c\x7d\xd4\x64\x14\xc1\xd4\x65\x46\xce\xc3\xbe\x41\x2a\x65\x6b\xc3\x1e\x6a\x25\x43\xa2\x06\xd9\x39\xed\xb3\x23\x5c\x03\x73\x74\x4c\x03\x46\x72\x6b\xb9\x17\x36\x98\x64\x9e\x87\x9a\xbf\x4a\xea\x9b\xce\x31\x4b\xa6\x97\xda\xf0\x45\xef\x57\xc4\x4d\x93\xb6\x95\x0e\xce\xff\x85\xda\x8c\x9c\x1d\xa6\x75\xc8\xd5\x39\x05\x68\x8b\x03\x9d\xd3\x07\xec\xdb\x8d\x11\xa2\x3f\x19\x8d\xff\x7e\x6b\xf7\xa7\xee\x70\x8d\xae\xf8\x02\x58\x52\xd6\x75\xd5\x02\x00\x00")
+
+func migrations19_txsub_shared_stateSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations19_txsub_shared_stateSql,
+		"migrations/19_txsub_shared_state.sql",
+	)
+}
+
+func migrations19_txsub_shared_stateSql() (*asset, error) {
+	bytes, err := migrations19_txsub_shared_stateSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/19_txsub_shared_state.sql", size: 725, mode: os.FileMode(420), modTime: time.Unix(1792398879, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 var _migrations1_initial_schemaSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xc4\x5a\x6d\x8f\xdb\xb8\x11\xfe\xbe\xbf\x62\x70\x5f\xbc\x8b\xae\xdb\x0b\xae\x38\x5c\xbd\xd8\x03\x9c\x5d\xa5\x31\xea\x95\x13\x5b\x6e\x12\x1c\x0e\x04\x2d\x8d\x65\x36\x12\xa9\x90\xd4\xc6\xbe\xa2\xff\xbd\xd0\xab\xf5\x2e\x79\x63\xe7\x3e\x5a\x1a\xce\xcc\x33\x33\x7c\x66\x44\x7a\x3c\x86\xbf\xf8\xcc\x95\x54\x23\xac\x83\xab\xf1\xf8\x6a\x3c\x86\x77\x42\x69\x57\xe2\xea\xfd\x1c\x1c\xaa\xe9\x86\x2a\x04\x27\xf4\xe3\xd7\x57\x2b\xc3\x02\xa5\xa9\x46\x1f\xb9\x26\x9a\xf9\x28\x42\x0d\xf7\xf0\xe3\x5d\xfc\xca\x13\xf6\xe7\xfa\x53\xdb\x63\x91\x34\x72\x5b\x38\x8c\xbb\x70\x0f\xa3\xb5\xf5\xe6\x97\xd1\x5d\xa6\x8e\x3b\x54\x3a\xc4\x16\x7c\x2b\xa4\xcf\xb8\x4b\x94\x96\x8c\xbb\x0a\xee\x41\xf0\x54\xc7\x0e\xed\xcf\x64\x1b\x72\x5b\x33\xc1\xc9\x46\x38\x0c\xa3\xf7\x5b\xea\x29\x2c\x99\xf1\x19\x27\x3e\x2a\x45\xdd\x58\xe0\x2b\x95\x9c\x71\xf7\xee\x2a\x85\x67\x52\x1f\x27\x10\x78\x81\xab\xbe\x78\x77\x60\x1d\x02\x9c\x80\xf1\xd1\x32\xcc\xd5\x6c\x61\xde\xc1\xca\xde\xa1\x4f\x27\x30\xbe\x83\xc5\x57\x8e\x72\x02\xe3\x18\xf9\xc3\xd2\x98\x5a\xc6\x51\x12\x66\x6f\xc0\x5c\x58\x60\x7c\x9c\xad\xac\x55\xa6\x10\x3e\xcc\xac\xb7\xb0\x7a\x78\x6b\x3c\x4d\x21\x70\x89\x4d\x35\xf5\x44\x64\xbd\x64\xfe\xa8\xa5\xe2\xc8\xc3\xe2\xe9\xc9\x30\xad\x0e\x37\x12\x01\x58\x98\x75\x25\x30\x5b\xc1\xe8\xdd\xfc\x6f\x81\x1b\x25\x2f\x90\xc2\x46\x27\x94\xd4\x03\x8f\x72\x37\xa4\x2e\x8e\xaa\x7e\xec\x94\x16\x12\xcf\x17\x85\x44\x5f\x39\x08\xe1\xc6\x63\x76\x7b\x00\xca\x2e\xbc\x0c\x7f\x6a\x36\x82\x1f\x95\x2c\xe8\x43\x80\xb0\x15\x12\xa2\xe7\x51\xc5\x29\xd4\x0a\xc4\x16\xae\x3f\xe3\xe1\x16\x9e\xa9\x17\xe2\x0d\x04\x94\x49\x15\x87\x24\x2e\x43\xa4\xd2\xde\x91\x80\xea\x1d\xdc\xa7\x5e\xdf\x96\x53\x18\x89\x39\xb8\xa5\xa1\xa7\x89\xa6\x1b\x0f\x55\x40\x6d\x8c\xca\x79\x54\x79\xfb\x95\xe9\x1d\x11\xcc\x29\x54\x68\x39\xee\x2c\xf2\xec\x40\xa8\x6d\x8b\x90\x6b\x95\xc1\xb7\xa6\xaf\xe7\xc6\x11\x7c\x1a\xbb\x3c\x02\x77\x60\xe5\x66\x27\xc5\x7c\xc4\xeb\x6a\x5a\xe1\xfa\x0a\x00\x80\x39\xb0\x61\x2e\xe3\x3a\xce\x94\xb9\x9e\xcf\x6f\xe3\xe7\xd4\x71\x24\x2a\x05\xf6\x8e\x4a\x6a\x6b\x94\xf0\x4c\xe5\x81\x71\xf7\xfa\xe7\xbf\xdf\xa4\x22\x89\x26\x12\x07\x94\x71\x8d\x2e\xca\x8a\x96\x4d\xbc\xe7\x19\xb7\x45\xbc\x73\x03\x7a\x88\xa8\x41\xc1\x46\x08\x0f\x29\xcf\xa5\xe1\xd1\x78\x33\x5d\xcf\x2d\x78\x33\x9d\xaf\x8c\xe2\x5a\x11\xea\x97\x2c\xf6\x98\xcf\x34\x3a\x84\xaa\x38\xbb\xff\x51\x82\x6f\xae\x6e\x6a\x15\x9e\xc6\x04\xb7\x5b\xb4\xcf\x1d\xe8\x54\x69\x1a\xe7\x4a\xf8\x49\x5b\xdc\x33\x39\x11\xa0\xa4\x31\x9b\xb5\x49\xfe\x20\xa4\x83\xf2\x87\x96\xc8\x77\x24\xc5\x41\x4d\x99\xd7\x1b\x14\x0f\x1d\x17\xe5\x99\x83\x92\x2a\x4d\x83\xa2\xf0\x4b\x88\xdc\x6e\x73\x34\x11\x26\x3b\xaa\x76\xcd\x75\x58\x91\x0f\x24\x3e\x33\x11\x2a\xd2\xbb\x30\x8d\x91\xa4\x5c\xd1\xa4\x67\xc4\x59\xc9\xfd\xc8\x2a\xea\xc7\x8a\x85\x63\x56\x86\xc9\xdb\x9e\x50\x51\x15\x6a\x88\xfa\x9e\xd2\xd4\x0f\x20\xda\xfe\x51\x07\x8c\x9e\xc0\x1f\x82\x63\x75\x8d\x44\xaa\x7b\x17\x25\xb2\x61\xe0\x0c\x96\xcd\xeb\x28\xfd\xe9\x07\x42\x6a\x94\xe4\x19\xa5\x62\x82\xd7\xb0\xbc\xaa\x56\x94\xd0\xd4\x23\xb6\x60\x5c\x35\x17\xe4\x16\x91\x04\x42\x78\xcd\x6f\xa3\x51\x81\x6c\xb1\x95\x29\xa2\xd7\x12\x15\xca\xe7\x36\x11\x9f\xee\x89\xde\x13\x85\x9a\x28\xf6\x47\x5d\xaa\xbd\x94\x8f\x69\x0b\xa8\xd4\xcc\x66\x01\x3d\x3b\xaf\x36\xdb\x38\xb2\x6c\x33\xa6\xe1\xdb\xbd\x9f\x40\x4e\xc5\x4f\x98\x43\x14\x7e\xc9\xc2\xb0\x32\xde\xaf\x0d\xf3\xa1\x23\x12\x45\xf0\x99\xf4\x30\x1b\x31\x82\x95\x35\x5d\x5a\x49\xfb\x7f\x15\x3f\x98\x99\x0f\x4b\x23\x6e\xd8\xaf\x3f\xa5\x8f\xcc\x05\x3c\xcd\xcc\x7f\x4f\xe7\x6b\x23\xff\x3d\xfd\x78\xfc\xfd\x30\x7d\x78\x6b\xc0\xab\xb3\x00\x85\xc5\x07\xd3\x78\x84\xd7\x9f\x7a\x10\x4f\xe7\x96\xb1\x3c\x11\x70\xae\xbb\x47\xfc\xaf\xcc\xe9\xc5\x72\xa9\x42\xed\x1b\x01\x8a\xf4\xd8\x3a\x26\x04\x81\xc7\xec\x04\x57\xdc\x8f\xbe\xb1\x1d\x25\x8f\x94\x08\xa5\x8d\x59\xa9\xb7\x70\x7f\xc6\x53\xa3\xd1\x64\x52\x93\x18\xb0\x29\x8a\xf0\x2e\x47\x0b\x6d\x56\xe2\xd8\xb7\xd0\x42\xd3\xda\xe6\x04\x7c\x0b\x29\xb4\x79\x76\x5e\x5a\xe8\xb1\xf2\xbd\x88\xe1\x44\xb0\xdf\x48\x0d\x3d\xd6\xea\xe4\xd0\xb6\xa0\x83\x1e\x0a\x4b\x2e\x57\xb2\x19\x45\x14\xfd\x1b\x3c\x8e\xa5\x53\x58\xcf\x90\x37\x94\x41\xba\xc9\xa0\x51\xf6\x68\xba\x7d\x5e\xa1\xad\xad\xb9\x6d\xd6\xfb\x53\xa6\x35\xbd\x27\xc8\x9f\xd1\x13\x01\x82\xc6\x7d\x8d\xaa\xf7\xd1\xec\x14\x7a\xba\xe5\xa5\x8f\xd1\x87\x6f\xe3\xab\x28\x0a\x6d\xaf\x15\x73\x39\xd5\xa1\xc4\xa6\xef\xc0\x7f\xfc\x7c\xf3\xdb\xef\x47\x16\xfe\xef\xff\x9a\x78\xf8\xb7\xdf\xab\x43\x1c\xfa\x22\xf9\x62\xac\x73\x76\xae\x8b\x0b\x8e\x9d\xac\x7e\xd4\x55\x57\x93\x22\x63\x3e\x92\x8d\x08\xb9\xa3\xa2\xcc\xfd\x22\x29\x77\x31\x26\xc3\xe2\x66\x62\x4e\xb6\x75\x52\xdb\x83\xf6\x7b\xb2\x5d\x16\xe6\xbc\xaf\xbb\x43\x22\xff\xb0\x98\xaf\x9f\xcc\x28\xa5\x2b\xc3\xca\x51\x72\xdc\xeb\x67\xea\x5d\x8f\x06\x0d\x14\xa3\xc9\x44\xa2\x6b\x7b\x54\xa9\x1a\xa3\x9f\x0d\x45\x6b\xb3\x3a\x09\x47\x0f\xfb\x75\x21\xe9\x09\x45\xf0\x19\x0f\xc7\xc3\x20\x73\x65\x2d\xa7\x33\xb3\x03\x6d\x9d\xf0\x4e\x4c\x60\x5c\x4a\xd3\xc7\xc7\x82\xb5\x21\x3e\xc2\xbb\xe5\xec\x69\xba\xfc\x04\xff\x32\x3e\xc1\x35\x73\x4e\xef\xc1\x17\x44\xda\x66\xb3\x0b\x6b\xa7\x9f\xbd\x68\x37\xf9\x80\x92\x41\x9a\x99\x8f\xc6\xc7\x17\x34\xaa\x78\x5d\x41\x1f\x2c\xcc\xe6\xb6\xb5\x5e\xcd\xcc\x7f\xc2\x46\x4b\x44\xb8\x4e\x85\x6f\x6b\x7d\xa1\xc9\xd3\xa8\xbd\x9d\xcd\xcd\xb8\x57\x0e\xf2\xb1\xda\x61\x9b\x5c\x4b\x1a\xea\xd9\x9c\x4b\xd4\x0d\x73\xaf\xd2\xcb\x6f\xeb\x6d\xbb\xb1\xc6\x09\x92\xcd\x21\x79\xff\xad\x6e\xaf\xcd\xd9\xfb\x75\xe6\x7d\x45\x77\x11\x43\x76\xec\x56\x72\xbf\xe9\x33\xfb\x36\x3b\x41\x6b\xf3\xfc\x48\xab\xe7\xf4\x99\x39\x83\xbd\x3d\x4e\xf5\xb7\x8d\x07\x05\x3d\x08\x44\x40\x82\x8b\x80\x48\x15\x17\x71\xb4\xf4\xbf\x17\xc1\xaa\xa3\xc9\x4f\xf4\x36\x87\xb3\x03\x2a\xeb\x2e\x62\xca\xce\x2a\x4b\x20\x9a\xdd\x2b\xee\xde\x8b\xf8\x58\x33\x30\x6c\xdb\x36\x78\xcb\xb8\x83\x7b\x52\xbd\x0d\x20\x82\x93\xf4\xc8\xff\xac\xae\xf7\x5a\x2b\xe2\xc8\xaf\x26\xca\xec\x9d\x08\x9e\x00\xe4\xcc\xe1\xef\x32\xd4\xef\x7e\x92\x82\x12\xf7\xb6\x28\x8c\xef\x85\xb4\xa4\x4c\x0f\x88\x0a\x73\x6e\xe0\xc3\x5b\x63\x69\xb4\xde\xb1\xdc\x83\x96\x21\xc2\x62\xd9\x7e\x93\x92\x88\x74\x07\x36\x65\xa8\x08\x6e\x34\xb6\x9f\xa7\xfb\x74\x9a\xe8\xe5\xc7\x48\xa8\xa7\x1c\xd2\xbd\x1b\xa9\xcc\xcf\xe0\x2f\xe1\x7a\x93\x9d\x5e\x0e\xc9\x25\x87\x83\xb8\x68\x49\x97\xec\xbc\x84\x01\xdb\xd5\x55\x2e\x19\x2e\x9c\x82\xda\x9d\x46\x2f\x96\xca\x82\xe1\xc8\x0a\x57\x4c\xdf\x27\x33\xc5\x3b\xad\x3e\x58\x05\xd9\xe1\x88\x9a\x6e\xcf\xbe\x0f\xb4\xc6\x7b\xbb\x3e\x8c\x4d\x8b\x86\x83\xcd\x06\xd9\xef\x03\x30\x3f\x87\xea\x03\xd5\xfa\x61\x52\x56\x7d\x3c\xc2\xbf\x38\x37\x54\x4d\x35\x0e\x7d\xa7\x32\x44\x59\x69\xf9\x98\xfb\x12\x14\xd1\x65\x6f\x08\xa0\xf2\x8a\xd3\xc0\x5d\xa8\x67\xd6\xad\x0c\x02\xd2\xd4\x39\xe3\x99\x5e\xef\x2f\xf4\xb1\x90\x2a\x6e\x99\x57\x5f\xf8\xb9\x50\x4f\x48\x7b\x3e\x8a\xd3\xf1\xc5\xb7\x4b\xdd\xd8\x8b\x07\x75\x2d\xa9\x83\xf9\x6c\x94\x7d\xea\x92\x8d\x10\x9f\xcf\x53\x50\x1d\x06\x7a\x47\xb0\xeb\xeb\xec\xda\x6e\xfc\xeb\xaf\x30\x52\xc2\x4b\xff\x6b\x13\x97\xe2\x68\x32\xd1\xb8\xd7\x37\x37\xb7\xd0\x2e\x68\x0b\x67\x98\x20\x53\x2a\x44\xd9\x2e\xba\x11\xa1\xbb\xd3\x83\xcc\x97\x44\xbb\x1d\x28\x89\x56\x5c\xc8\x46\xef\x78\x3f\xc1\x3d\xfc\xf4\x53\x21\x7b\x6d\x7f\x91\x04\x5b\xf8\x81\x87\x1a\xe3\x4c\x14\xff\x5d\xf9\x28\xbe\xf2\x2b\x47\x8a\x00\xe2\x3f\x8e\x35\x97\x8b\x4d\x95\x4d\x1d\xbc\xeb\x11\x2c\x6f\xa8\xae\x45\x05\x8e\x18\x24\x36\x5c\x73\xd6\xda\xba\x64\xb2\xaa\xea\x92\xc9\xbf\x7c\x72\xa1\xff\x07\x00\x00\xff\xff\x47\xfc\xd6\x1f\x94\x2a\x00\x00")
 
 func migrations1_initial_schemaSqlBytes() ([]byte, error) {
@@ -116,6 +338,206 @@ func migrations1_initial_schemaSql() (*asset, error) {
 	return a, nil
 }
 
+var _migrations20_leader_leasesSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\x03\x6d\x8f\xbd\x6e\xc2\x40\x10\x84\xfb\x7b\x8a\x29\x41\xc1\x14\x51\x48\x43\xe5\x80\x8b\x28\x0e\x20\xcb\x14\x54\x68\x65\x16\xdf\x29\xf7\xa7\xbb\x4b\x48\x78\x7a\xce\x41\x72\x9a\xb4\xb3\xdf\xcc\xce\x14\x05\x1e\x8c\xea\x03\x25\xc6\xde\x0b\x51\x14\xd0\x4c\x91\x23\x58\x73\x97\x94\xed\x91\x24\xc3\x59\xfd\x03\xe9\x82\xba\x3a\x8b\xc0\x5e\xab\x8e\xe0\x39\x9c\x5d\x30\x03\x43\x48\x14\x3f\x66\xe0\x79\x3f\x47\x16\x38\x26\xe5\xac\x58\x35\x55\xd9\x56\x68\xcb\x97\xba\x1a\x72\x4f\x1c\x8e\xf7\x78\x31\x11\x80\x25\xc3\xf8\xa2\xd0\x49\x0a\x93\xe7\xa7\x29\x36\xdb\x16\x9b\x7d\x5d\x63\xd7\xbc\xbe\x97\xcd\x01\x6f\xd5\x61\x96\x41\xe9\x74\xb6\x8e\xe8\xe3\x62\xf1\xc7\x0e\x77\xfe\xf6\x2a\x70\x3c\x52\x42\x52\x26\x3f\x27\xe3\x71\x51\x49\xba\xcf\xbb\x82\xdc\x9b\x47\x8b\x98\x2e\x7f\x97\x8e\xcb\xd7\xee\x62\x85\x58\x37\xdb\xdd\x7f\x5d\x97\xe2\x06\xd1\xfb\xa0\x6e\x26\x01\x00\x00")
+
+func migrations20_leader_leasesSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20_leader_leasesSql,
+		"migrations/20_leader_leases.sql",
+	)
+}
+
+func migrations20_leader_leasesSql() (*asset, error) {
+	bytes, err := migrations20_leader_leasesSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20_leader_leases.sql", size: 294, mode: os.FileMode(420), modTime: time.Unix(1792399838, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations21_asset_metadataSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\x03\x95\x52\x3d\x4f\xc3\x30\x10\xdd\xf3\x2b\x6e\x2b\x08\x65\x00\xa1\x2e\x99\x02\x09\x93\x49\x50\x95\x4c\x08\x55\x47\xea\xa6\x46\x8e\x1d\xd9\xe7\x42\xff\x3d\x8e\x02\xa5\x40\x88\xc5\x7a\x7a\x1f\xf7\xee\x5e\x1c\xc3\x45\x27\x5a\x83\xc4\xa1\xee\xa3\x28\x65\x55\xbe\x82\x2a\xbd\x61\x39\xa0\xb5\x9c\x20\xcd\x32\xb8\x2d\x59\x7d\x5f\x80\xc2\x8e\xc3\x1e\x4d\xb3\x43\x73\xb6\xbc\x3e\x87\xa2\xac\xa0\xa8\x19\x83\x2c\xbf\x4b\x6b\x56\xc1\x62\x91\x44\x71\x0c\xca\x75\xcf\xdc\x80\xde\xc2\x86\x37\xa2\x43\x09\xbd\xc4\x86\x5b\x70\x96\x6f\x80\x34\x6c\x84\xf5\x93\x03\x60\xa7\x9d\x22\x3b\x20\x69\xc7\x47\xc7\xf9\x1d\x3e\x04\x2d\x08\x45\xbf\xfd\xaf\x92\x10\xdb\x36\x46\xf4\x24\xb4\x02\xe2\x6f\x13\x0a\x43\x82\x59\x09\xa9\x5b\xbd\x76\x46\xce\xf0\xfd\x05\x5e\xac\x77\x40\x63\x7c\x46\x9f\xcd\xf0\xd6\x49\x24\x6d\x0e\xb0\x95\xd8\xfe\x27\xef\x17\x75\x3d\x52\xff\xb0\x7d\x7c\x0a\x2d\x6e\x09\xc9\xd9\xe3\xff\x2e\x97\x53\xff\xc3\x86\xc4\x9e\x7b\xa9\x21\xc4\xb1\x19\x99\x7e\x55\x53\xdd\xc8\x56\xe5\xc3\x77\xf9\xa9\x1d\x4e\x51\x3f\xe3\x84\xf0\x9f\xd7\x0e\xe1\x4e\x1e\x1b\x86\x8e\x0d\x0a\xe1\x86\xb6\x27\xd1\x3b\x99\xcc\xf0\x9b\x21\x03\x00\x00")
+
+func migrations21_asset_metadataSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations21_asset_metadataSql,
+		"migrations/21_asset_metadata.sql",
+	)
+}
+
+func migrations21_asset_metadataSql() (*asset, error) {
+	bytes, err := migrations21_asset_metadataSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/21_asset_metadata.sql", size: 801, mode: os.FileMode(420), modTime: time.Unix(1792400259, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations22_asset_supplySql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\x03\x8d\x92\x4f\x6f\x82\x40\x10\xc5\xef\xfb\x29\xde\x11\x53\x49\xff\xa4\xe9\xc5\x13\x2d\xb4\x31\xa5\x68\x28\x24\xf5\x44\x56\x19\x75\x13\x97\x25\xbb\x4b\x0d\xfd\xf4\x05\xd1\xa2\x8d\x31\xde\x76\x66\x7e\x99\x37\xf3\x66\x5d\x17\x37\x52\xac\x34\xb7\x84\xb4\x64\xcc\x75\xc1\xa5\xaa\x0a\x6b\x20\x8c\xa9\x28\x87\xd2\xd0\x94\x13\xc9\xe6\x3d\xaf\x51\xf2\x5a\x52\x5b\x5e\x6a\x25\x6f\xad\x82\x5d\x53\x87\x6a\xa8\xe5\x2e\xe2\xc6\x90\x65\x2f\x71\xe0\x25\x01\x12\xef\x39\x0c\xba\x54\x66\xaa\xb2\xdc\xd4\xd9\x62\xcd\x8b\x15\x19\xe6\x30\x40\x34\x4d\xc5\xca\x90\x16\x7c\x33\x6c\x62\x55\x52\x33\x8b\x50\x45\xd6\x55\x44\x61\x11\x4d\x12\x44\x69\x18\xb6\xf5\xae\xd1\x42\xe5\x84\x6f\xae\x9b\x4e\xda\xb9\x7f\x18\x9c\x41\xf6\x23\x1d\xa0\xa7\xc7\x53\x68\xbf\xdc\x3f\x05\xf8\xc1\xab\x97\x86\x09\xee\x5a\xa6\x5f\xfb\x12\xb5\xd8\x28\x43\x79\xc6\x2d\xac\x90\x64\x2c\x97\x25\xb6\xc2\xae\x55\xd5\x65\xf0\xa3\x0a\x3a\xd1\x9e\xc6\xe3\x0f\x2f\x9e\xe1\x3d\x98\x39\x22\x1f\xb0\xc1\x88\x1d\xdc\x1a\x47\x7e\xf0\x75\xd6\xad\x6c\x5e\x67\xbb\x3c\x26\xd1\x59\x00\xe9\xe7\x38\x7a\xc3\xdc\x6a\x22\x38\xbd\x4f\xc3\x13\x43\x86\xfd\xbc\x8d\xec\x55\xaa\x7f\x27\xb9\x4e\xf9\xf8\x82\xed\x66\xee\xd1\x0f\xf3\xd5\xb6\x60\xcc\x8f\x27\xd3\x0b\xff\x62\xc4\x7e\x01\x87\x8c\x4a\xa0\x95\x02\x00\x00")
+
+func migrations22_asset_supplySqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations22_asset_supplySql,
+		"migrations/22_asset_supply.sql",
+	)
+}
+
+func migrations22_asset_supplySql() (*asset, error) {
+	bytes, err := migrations22_asset_supplySqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/22_asset_supply.sql", size: 661, mode: os.FileMode(420), modTime: time.Unix(1792400440, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations23_screening_assetsSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\x03\x95\x8f\xb1\x0a\xc2\x30\x14\x45\xf7\x7c\xc5\xdb\xaa\x48\x07\x45\x5c\x3a\x45\x13\xa7\x98\x4a\x49\xe6\x12\xe2\xa3\x0d\x68\x2b\xef\xb5\xfa\xfb\xe2\x22\x82\x4a\xf1\x03\xee\xb9\xe7\xe4\x39\x2c\x2e\xa9\xa1\x30\x20\xf8\xab\x10\xd2\x38\x5d\x81\x93\x5b\xa3\x81\x23\x21\x76\xa9\x6b\x6a\x42\x1e\xcf\x03\x83\x54\x0a\x76\xa5\xf1\x07\x0b\x81\x19\x87\x3a\xf6\x27\x84\x5b\xa0\xd8\x06\x9a\x2d\x57\x73\xb0\xa5\x03\xeb\x8d\x01\xa5\xf7\xd2\x1b\x07\x59\x56\xfc\x0b\x4d\xcc\x23\xd2\x0b\xbb\x59\xff\xc0\x8a\xfc\x4d\x5e\xf5\xf7\x6e\x4a\x5f\x55\xe5\xf1\xdb\xd5\x94\xe1\xe7\xee\xd9\x5d\x88\x07\x0c\x94\xb6\x9c\x3d\x01\x00\x00")
+
+func migrations23_screening_assetsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations23_screening_assetsSql,
+		"migrations/23_screening_assets.sql",
+	)
+}
+
+func migrations23_screening_assetsSql() (*asset, error) {
+	bytes, err := migrations23_screening_assetsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/23_screening_assets.sql", size: 317, mode: os.FileMode(420), modTime: time.Unix(1792402041, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations24_payment_party_indexSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\x03\x9d\x90\xc1\x4a\xc3\x40\x10\x86\xef\xfb\x14\xff\xad\x09\x76\x5f\xa0\x81\x82\x98\x20\xbd\xa4\xa5\x5a\xf0\x16\x36\x9b\xa9\x59\x48\x76\x96\xdd\x49\x35\x6f\x6f\xaa\xa0\xa0\x3d\x79\x19\x98\x0f\xfe\x8f\x99\x5f\x6b\xdc\x8d\xee\x35\x1a\x21\x9c\x82\x52\x5a\x23\x98\x79\x24\x2f\x09\xc6\x77\xcb\x22\xfd\x0f\x49\xcb\xc4\x39\xf2\x08\x8e\x88\x64\xc9\x5d\xa8\x43\x3b\x43\x7a\x82\xb1\x96\x27\x2f\x6b\x4c\xe9\x0b\x5a\x1e\xc3\xe0\x8c\xb7\x84\x38\x0d\x94\xd4\xc3\xb1\xba\x7f\xae\xb0\xab\xcb\xea\x05\x3d\x87\xa6\x9d\x9b\x4f\xdb\xbe\x46\xef\x92\x70\x9c\x1b\x0e\xb4\x1c\xe3\xd8\x27\x9c\x9e\x76\xf5\x23\x5a\x89\x44\xc8\xb2\xac\x23\x31\x6e\x48\xd0\xdb\x2d\x56\xd7\xd8\x2a\xdf\x6c\x84\xde\x25\x5f\xc3\x75\x79\x71\x53\x2f\xfc\x0f\xb9\xf0\x6f\xf5\xb5\x97\xef\x9e\x4a\x7e\xf3\x4a\x95\xc7\xfd\xe1\xef\x2b\xc5\x0d\x2e\x5c\xa8\x0f\xed\x81\x1e\xb5\x68\x01\x00\x00")
+
+func migrations24_payment_party_indexSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations24_payment_party_indexSql,
+		"migrations/24_payment_party_index.sql",
+	)
+}
+
+func migrations24_payment_party_indexSql() (*asset, error) {
+	bytes, err := migrations24_payment_party_indexSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/24_payment_party_index.sql", size: 360, mode: os.FileMode(420), modTime: time.Unix(1792402139, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations25_account_freeze_eventsSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\x03\x95\x53\x4d\x6f\x82\x40\x14\xbc\xf3\x2b\xe6\x56\x4d\x25\xe9\x9d\xf4\x40\x61\xd3\x92\x50\x68\x51\xd2\xde\x08\xc2\xaa\x9b\x28\x4b\x60\xd5\xda\x5f\xdf\x07\x14\xb5\x16\xfc\xb8\xb1\xbb\x33\xf3\x86\x79\xef\xe9\x3a\xee\x57\x62\x5e\xc4\x8a\x23\xcc\x35\x4d\xd7\x11\xa7\x2b\x91\x89\x52\xd1\x9d\xd8\x70\xc8\x9c\x57\x5f\x32\x43\x52\x70\x82\xa5\x50\x0b\x8e\x59\xc1\xf9\x37\x3d\x16\xf5\x89\x6f\x78\xa6\x46\x28\x25\x0a\x2e\xb2\x39\x2f\x2b\xd8\x9e\x58\x22\x95\xc8\xa4\x42\xba\xce\x97\x22\xa9\x4a\x11\x69\xa5\x99\xee\x84\x05\x98\x98\x4f\x2e\x43\x9c\x24\x72\x9d\xa9\xa8\xd1\x2d\x61\xda\x36\x2c\xdf\x0d\x5f\xbd\x83\x4e\x24\x52\x4c\xc5\x5c\x64\xca\xe8\xe4\x92\x63\xa1\xca\x68\x41\xde\x65\xb1\xbb\x28\xa1\x59\x01\x33\x27\x0c\xa1\xe7\xbc\x87\x0c\x8e\x67\xb3\xcf\x53\x1f\xd1\x74\x17\x1d\x02\xf0\xbd\x7f\x3e\xc3\xb1\xe3\x3d\x63\xaa\xe8\x88\xc1\x71\x99\xa1\x71\x56\xff\xaf\xd7\xde\x32\x27\xbf\xd4\x5b\x6d\xd4\xb6\xc0\xf2\x4d\x97\x8d\x2d\x36\x68\x0c\xd6\x4f\x0f\x43\x32\x43\x8d\x6d\x3d\x6f\x49\x93\xba\x84\x19\x75\x8f\x7f\xe5\xa2\x51\x69\xed\xf6\xe6\x70\x80\x5e\x0c\xa2\x86\x12\x2b\x56\x43\x7c\xbc\xb0\x80\x61\x29\x66\x34\x13\x74\x01\x67\x0c\x2f\x74\xdd\x9b\xe2\x69\xf4\x2a\x13\xbf\x73\x77\x65\x44\xfb\x10\x5a\x1b\x75\x4a\x78\xc4\x5d\xa3\x78\x67\xd4\x13\xbf\xdf\x00\x5b\x6e\x33\x4d\xb3\x03\xff\xed\x36\x3f\x46\x17\xa7\x33\xba\x4e\xe4\x99\x61\xb8\xa4\x7c\x04\xbc\x62\x25\x6a\xad\x8e\x9d\xe8\x26\xb7\xad\xed\x67\xfd\x00\xd8\xcd\xbb\xc4\x3e\x04\x00\x00")
+
+func migrations25_account_freeze_eventsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations25_account_freeze_eventsSql,
+		"migrations/25_account_freeze_events.sql",
+	)
+}
+
+func migrations25_account_freeze_eventsSql() (*asset, error) {
+	bytes, err := migrations25_account_freeze_eventsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/25_account_freeze_events.sql", size: 1086, mode: os.FileMode(420), modTime: time.Unix(1792402190, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations26_txsub_accepted_sequencesSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\x03\x8d\x8f\x3b\x6e\xc3\x30\x10\x44\x7b\x9e\x62\x7a\x87\x27\x70\xa5\x84\x72\x45\x4b\x81\x21\xd5\xc6\x8a\x59\xc7\x84\x29\x52\xe1\x52\xf9\xdc\x3e\x8c\x81\x04\x86\xab\x94\xbb\x18\xbc\x37\xa3\x35\x36\xb3\x7f\xcd\x54\x18\xe3\xa2\x94\xd6\xc8\x2c\x9c\xdf\xa9\xf8\x14\x91\x4e\x28\x67\x46\xc9\x14\x85\xdc\xf5\x45\xce\xf1\x52\xf8\x05\xd3\x17\xa4\x70\x08\x94\xb5\x4b\x99\x1f\xe0\x8b\x40\xf8\x6d\xe5\xe8\x18\x6b\x9c\x42\x72\x17\x41\xbd\xd7\x9a\x96\x75\x9a\xbd\x48\x25\x08\x7e\x28\x21\x54\xd1\x12\xbc\x23\x51\x8d\x1d\xda\x03\x86\xe6\xd1\xb6\x28\x9f\x35\x79\xfc\xc5\x1c\x6f\xca\x08\x1a\x63\xf0\xd4\xdb\x71\xdf\xdd\xb4\x48\x29\x30\x45\x74\xfd\x80\x6e\xb4\x16\xa6\xdd\x35\xa3\x1d\x70\xa2\x20\xbc\xbd\x2e\xfa\x5b\x68\xd2\x47\x54\xff\xd6\x99\x43\xff\x7c\xef\xdb\xaa\x6f\xa2\xb2\x8e\x3a\x32\x01\x00\x00")
+
+func migrations26_txsub_accepted_sequencesSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations26_txsub_accepted_sequencesSql,
+		"migrations/26_txsub_accepted_sequences.sql",
+	)
+}
+
+func migrations26_txsub_accepted_sequencesSql() (*asset, error) {
+	bytes, err := migrations26_txsub_accepted_sequencesSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/26_txsub_accepted_sequences.sql", size: 306, mode: os.FileMode(420), modTime: time.Unix(1792402819, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations27_asset_supply_backfillSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\x03\xad\x55\x5d\x8f\x9b\x30\x10\x7c\xe7\x57\xec\x1b\xd0\x26\xd1\x5d\xfb\xd6\x6b\x53\x45\x77\x54\x4d\x95\x42\x15\x72\x6a\xdf\x90\x83\x37\xc1\x3a\xc0\x14\xc3\x9d\xd2\x5f\xdf\x35\x86\x1c\xf9\x6c\xda\x6b\xa4\x24\xd8\x1e\x8f\xd7\x33\xcb\xee\x70\x08\xaf\x33\xb1\x2e\x59\x85\x70\x5f\x58\xd6\x70\x08\x4c\x29\xac\x22\x55\x17\x45\xba\x89\xe2\x84\xe5\x6b\x54\x20\x57\x20\x0b\x24\x98\x90\xb9\x02\xa1\xe7\x2a\xe4\xb0\xc4\x95\x2c\x11\xaa\x84\xbe\x6c\x99\x22\x3c\x31\x05\x8c\x73\xe4\x23\xf8\xc6\x36\x19\xe6\x95\x1a\x40\xc1\xaa\x84\x7e\xba\x61\x89\x8f\x58\x2a\x96\x12\x32\xe7\xfa\xc4\x12\x57\x75\xce\x69\x48\x54\x25\xc6\x72\x9d\x8b\x5f\x9a\x7c\xa3\x89\x45\x09\x1c\x2b\x26\x52\xda\x49\xe4\xaa\xa2\x03\x9b\x35\x13\x04\xc5\x73\xd3\x0f\x8d\xa5\x25\x32\xbe\x81\x84\x3d\x12\x00\xba\xf8\x35\xb5\x7a\x10\x45\x41\x91\x59\x53\x3f\xf4\xe6\x0b\x98\xfa\x8b\xe0\xf8\x6d\x9d\x2d\x61\x24\xf8\xa0\xc5\xc4\x92\x63\xf7\x2c\x94\xaa\xb1\x1c\x40\xf3\xcf\xf5\x95\x38\x62\xa6\x9f\xe2\x54\x2a\xe4\x11\xab\x5c\x2b\xf4\x66\xde\xed\x82\x82\x53\x23\x62\xb1\xc0\x7c\xf4\xb0\x47\x78\x38\xdd\x72\x77\x0b\xb7\x93\xd0\x83\xef\x9f\x3d\xbf\x81\x28\xcc\x39\x96\xf0\xe1\x00\x0f\x8b\x0e\xc2\x32\x59\xe7\x15\x78\x33\xda\x77\x05\x9e\x7f\x77\x82\x8a\x94\x46\xf1\xf8\xcf\x64\x49\x3a\xda\xde\xd5\xfa\x34\x0f\xbe\x82\x43\x4b\xe4\xe6\x09\xa3\x3b\x97\x09\xd4\x0a\x93\xc8\xbe\x2e\xa0\xc7\xad\xd3\xc3\xf1\xd8\x7e\xd6\xc8\x86\x49\x08\x47\x24\x3b\xbe\xc3\xdc\xa0\xb7\x67\x4f\x4f\x92\x21\x98\xcc\xbc\xf0\xd6\x73\x76\xb7\xaf\x4a\x99\xd9\x83\x3d\x4e\x25\xeb\x32\xc6\x88\xc5\xb1\xd6\xc1\x76\x35\xad\xf1\xe0\xcf\x84\x95\x3c\xa0\x6b\xb5\x89\x0c\xad\xa1\xeb\x7c\xe8\x11\x3a\x7b\x4c\xc6\x05\xdb\x7d\xf7\x2e\xaf\x33\x2c\x45\x0c\xaf\xe0\xfa\xca\x7c\x68\x72\x29\xd6\x82\x4c\xd2\x37\x6e\x80\x44\xd4\x18\x92\x08\xfd\xb6\x6c\xa2\xde\xeb\x91\x48\x5a\xa4\x14\x98\x7b\xd0\x3b\x03\x3e\x82\x8e\x16\x82\x39\xec\x4e\x1e\xc6\xeb\xdf\x81\x1f\x2c\xf6\x60\x9d\x4a\x26\x4c\x3a\xe1\xde\x9f\x06\x3e\x4c\x66\x33\x93\x12\xa4\x58\x65\xdc\xd0\x85\x64\xa7\x1c\x5c\x9a\x0e\xdd\x11\xcf\x59\x71\x19\xb4\x4d\x87\x93\x60\xe3\xfa\xf3\xaa\x6d\x9f\xf1\x61\xf7\x9e\xe7\xed\xb8\xcc\x84\xbf\xd3\xb1\x4d\x15\xfe\x42\x2d\x2f\x11\xf1\x94\x7a\xf6\xe9\x2d\x3a\xdf\x5f\x98\xc3\xff\x41\x34\x57\xd7\x2d\xeb\x4b\x30\xf5\xb7\x2c\x29\xf2\x35\x15\x22\x2a\x59\x40\x6a\x52\xe1\x52\xf8\xb3\xc6\x3c\x46\x2a\x7d\x8e\x29\xcf\x30\x1e\xc3\xdb\x37\x14\x07\x05\x81\x04\xb6\xcc\x49\xbb\xc5\x1a\xa6\x61\x93\xfc\xfe\x7d\x63\x88\x7e\x17\x9c\xb3\x45\xd9\x85\xf7\x63\x03\x39\x53\x6c\xdd\x96\x4a\x33\x7b\x3f\xa6\xe1\x22\x04\xa7\xb5\xf1\xda\xa8\x71\xb4\x47\xc5\xad\x18\xf1\xa8\xdf\xac\x5a\x7e\xc1\xdd\x9b\xa6\x99\x6f\x9b\xfb\x9d\x7c\xca\x9b\x99\x25\x8b\x1f\x56\x22\x25\x4d\xa0\x94\x4f\xa6\x33\xea\xfe\xad\x58\x86\xba\xc1\x36\x93\x47\xba\xec\x00\x94\xd4\xc0\x4d\xb3\xe3\x01\x8b\xca\xfa\x0d\xfa\xa4\x8e\xf5\x3d\x08\x00\x00")
+
+func migrations27_asset_supply_backfillSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations27_asset_supply_backfillSql,
+		"migrations/27_asset_supply_backfill.sql",
+	)
+}
+
+func migrations27_asset_supply_backfillSql() (*asset, error) {
+	bytes, err := migrations27_asset_supply_backfillSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/27_asset_supply_backfill.sql", size: 2109, mode: os.FileMode(420), modTime: time.Unix(1792403473, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations28_payment_refund_reservationsSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\x03\x85\x51\x41\x6e\xc2\x30\x10\xbc\xfb\x15\x73\x0c\x2a\xb9\x55\xbd\x70\xa2\x25\xaa\x50\x69\x82\x52\x22\x95\x53\xe4\xc0\x12\x5b\x6a\xec\xc8\xde\x00\xe9\xeb\xeb\x0a\xd2\x16\x09\xd1\x93\xbd\x9e\xd9\xd9\x99\x75\x1c\xe3\xae\xd1\xb5\x93\x4c\x28\x5a\x21\xe2\x18\xb2\xb1\x9d\x61\xd8\x1d\x58\x11\x5a\xd9\x37\x14\x4a\x47\xdc\x39\x43\x5b\x54\x3d\x7c\x57\x35\x9a\x39\x14\x8e\xf6\xe4\xbc\xfc\x80\x75\xe1\xbe\xeb\xcc\x76\x8c\x83\xd2\x1b\x05\xed\x61\x2c\xa3\x27\x86\x36\x35\xf9\xc0\x16\x4f\x79\x32\x5d\x25\x58\x4d\x1f\x17\xc9\x20\x5c\x9e\xda\xc2\xe1\xc9\xed\x25\x6b\x6b\xbc\x88\x04\xc0\xc7\x52\x49\xaf\xb0\x97\x6e\xa3\xa4\x8b\x1e\xee\x47\x48\xb3\x15\xd2\x62\xb1\x18\x07\xdc\xb6\xa5\x36\x5b\x3a\x06\x79\xa6\x9a\xdc\x05\x38\x88\xeb\xe0\x57\xd7\x81\x71\x81\x9e\x13\x5e\x41\xe8\xd8\xea\xe0\xa4\x94\x0c\xd6\x4d\x70\x2d\x9b\x16\x07\xcd\xca\x76\xa7\x17\x7c\x5a\x43\x17\x2d\xcb\x7c\xfe\x3a\xcd\xd7\x78\x49\xd6\xd1\xd9\xf3\xf8\xc7\xdc\x48\x8c\x26\x62\xc8\x3d\x4f\x67\xc9\xfb\xad\xdc\x65\xd5\x97\xc3\xbe\xb3\xf4\x16\x13\xc5\xdb\x3c\x7d\x46\xc5\x8e\x08\xd1\x6f\xda\xef\x69\xf1\x9f\x2f\x9d\xd9\x83\x11\x62\x96\x67\xcb\xff\xb7\x3e\x11\x5f\x73\xaa\xf8\x10\x0d\x02\x00\x00")
+
+func migrations28_payment_refund_reservationsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations28_payment_refund_reservationsSql,
+		"migrations/28_payment_refund_reservations.sql",
+	)
+}
+
+func migrations28_payment_refund_reservationsSql() (*asset, error) {
+	bytes, err := migrations28_payment_refund_reservationsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/28_payment_refund_reservations.sql", size: 525, mode: os.FileMode(420), modTime: time.Unix(1792404363, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations29_leader_fencesSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\x03\x6d\x90\x31\x6f\xc2\x30\x10\x85\x77\xff\x8a\x37\x82\x9a\x64\xaa\xba\x30\xa5\x25\x03\x6a\x0a\x28\x4a\x06\xa6\xca\x49\x0e\xdb\x0a\xb1\x2b\x9f\xdb\x88\x7f\x5f\x1b\x54\xa6\xae\xef\x7d\xf7\xde\xdd\xe5\x39\x9e\x66\xa3\xbc\x0c\x84\xee\x4b\x88\x3c\x47\xd0\x04\xe5\x29\x2a\x1c\x70\x26\x3b\x18\xab\x10\xdc\x44\x16\xee\x7c\x73\x2f\x24\x99\x32\x2c\xda\x0c\x1a\xda\x5d\x46\xf2\xd0\x92\x31\xb8\x79\x36\x21\xd0\x88\xc5\xf9\xe9\x8f\x0e\x92\xa7\x22\x05\x77\x1c\x9d\xfe\x7a\x1f\x67\x70\x70\x3e\x0a\xee\x3b\xb0\x19\x29\xd1\xda\x24\xed\x8a\xb1\xcf\x40\x85\x2a\x60\x2c\x22\x62\xb8\x10\x6f\x4d\x55\xb6\x15\xda\xf2\xb5\xae\x52\x40\xac\xfc\x4c\xbb\x11\x8b\x95\x00\xac\x9c\x09\x3f\xd2\x0f\x5a\xfa\xd5\xcb\xf3\x1a\xfb\x43\x8b\x7d\x57\xd7\x38\x36\xbb\x8f\xb2\x39\xe1\xbd\x3a\x65\x11\xbc\xdf\xd1\x1b\x65\x6c\x78\x40\x62\xbd\xb9\x5d\xfe\xf8\xc4\xd6\x2d\x56\x88\x6d\x73\x38\xfe\xd7\xb8\x11\xbf\x83\x13\x45\x61\x36\x01\x00\x00")
+
+func migrations29_leader_fencesSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations29_leader_fencesSql,
+		"migrations/29_leader_fences.sql",
+	)
+}
+
+func migrations29_leader_fencesSql() (*asset, error) {
+	bytes, err := migrations29_leader_fencesSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/29_leader_fences.sql", size: 310, mode: os.FileMode(420), modTime: time.Unix(1792404699, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 var _migrations2_index_participants_by_toidSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x8f\xb1\xca\xc2\x50\x0c\x46\xf7\x3c\x45\xc6\xff\x47\xfa\x04\x9d\xc4\x16\xe9\xd2\x4a\xb5\xe0\x76\x49\xdb\x8b\xcd\xe0\xcd\x25\x37\x20\x7d\x7b\x41\x07\x5b\xbb\xb8\x86\x8f\x73\x72\xb2\x0c\x77\x77\xbe\x29\x99\xc7\x2e\x02\x1c\xda\x72\x7f\x29\xb1\xaa\x8b\xf2\x8a\x93\x44\xd7\xcf\x6e\x12\x1e\xb1\xa9\x71\xe2\x64\xa2\xb3\x93\xe8\x95\x8c\x25\xb8\x48\x6a\x3c\x70\xa4\x60\x09\xbb\x73\x55\x1f\xb1\x37\xf5\x1e\xff\xb6\x5b\x1e\xff\xf3\x2f\xbc\xbd\xf1\xb6\xc6\x9b\x52\x48\x34\xfc\x28\x58\xae\x5f\x0a\x58\x26\x15\xf2\x08\x00\x45\xdb\x9c\xb6\x49\xf9\xea\xfe\xf9\x25\x87\x67\x00\x00\x00\xff\xff\x33\xec\x54\x7a\x15\x01\x00\x00")
 
 func migrations2_index_participants_by_toidSqlBytes() ([]byte, error) {
@@ -136,6 +558,46 @@ func migrations2_index_participants_by_toidSql() (*asset, error) {
 	return a, nil
 }
 
+var _migrations30_statistics_cache_expirationSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\x03\x8d\x8e\xb1\x0a\xc2\x30\x18\x84\xf7\x3c\xc5\x6d\x2a\x92\x27\xe8\x24\xb6\x48\x97\xb6\x54\x0b\x6e\x21\x8d\xbf\x9a\xa1\x49\x49\x7e\xab\x7d\x7b\x4b\x15\x11\x5c\x5c\xef\x8e\xef\x3e\x29\xb1\xee\xec\x25\x68\x26\x34\xbd\x10\x52\x82\x1e\xbd\x0d\x74\x42\x64\xcd\x36\xb2\x35\x11\x46\x9b\x2b\xc1\x46\x04\xea\xfc\x30\x75\xde\x21\xea\x81\xe0\xcf\xd0\x6e\x84\x36\xc6\xdf\x1c\x2f\xde\x4b\xb1\xad\xb3\xcd\x21\x43\x5e\xa4\xd9\xf1\x8b\xa3\xe6\x56\xb5\xa3\x9a\x3f\xa6\x78\xe2\x94\xc5\xcf\x02\xcd\x3e\x2f\x76\x68\x39\x10\x61\xf9\xf2\x89\x4a\xf3\x2a\x99\x05\x3f\xc2\xa9\xbf\x3b\x21\xd2\xba\xac\xfe\xba\x4a\xc4\x13\x56\x59\x62\xa5\xee\x00\x00\x00")
+
+func migrations30_statistics_cache_expirationSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations30_statistics_cache_expirationSql,
+		"migrations/30_statistics_cache_expiration.sql",
+	)
+}
+
+func migrations30_statistics_cache_expirationSql() (*asset, error) {
+	bytes, err := migrations30_statistics_cache_expirationSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/30_statistics_cache_expiration.sql", size: 238, mode: os.FileMode(420), modTime: time.Unix(1792405212, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations31_reversal_window_issuerSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\x03\xb5\x90\x4f\x4b\xc4\x30\x14\xc4\xef\xf9\x14\xef\xb6\x2d\x6e\x0f\x82\x78\xe9\x29\x36\x51\x0a\x31\xd5\x6e\x02\xde\x4a\xb6\x7d\xda\xa2\xfd\x43\x12\x5b\xfa\xed\x5d\x77\x57\xd9\x65\x65\x11\xc1\xeb\x9b\x79\xc3\xcc\x2f\x8a\xe0\xa2\x6d\x5e\xac\xf1\x08\x7a\x20\x24\x8a\x00\xdb\xc1\xcf\x60\x9c\x43\x0f\x8d\x73\xef\x68\xa1\x35\xbe\xac\xd1\x81\xe9\xe6\xaf\x53\xff\x0c\xbe\xc6\xbd\xad\xec\x2b\x24\x54\x28\x9e\x83\xa2\x37\x82\x83\xc5\x11\xad\x33\x6f\xc5\xd4\x74\x55\x3f\x39\xa0\x8c\x41\x92\x09\x7d\x2f\x77\x2f\xc5\x3e\x66\x34\xb6\xac\x8d\x0d\xae\xaf\x42\x90\x99\x02\xa9\x85\x00\xc6\x6f\xa9\x16\x0a\x16\x8b\x98\x10\x96\x67\x0f\x90\x4a\xc6\x9f\x4e\x52\x8b\xf5\x5c\xbc\xe2\x1c\x93\x24\xe7\x54\x71\xd0\x32\x7d\xd4\xfc\xbc\x19\x32\x79\xda\x4e\xaf\x52\x79\x07\x6b\x6f\x11\x21\xd8\xf5\xfb\x9c\xb4\x3c\xea\xba\xdc\x0c\xa0\x82\xaf\x12\x1e\x38\xec\x2a\xb4\x85\x9f\x87\x8d\x27\xba\x0c\x0f\x24\x8b\x25\x36\xe3\xa1\x18\xc6\x5b\xac\xdf\x98\x59\x3f\x75\xbf\x9b\x75\x96\xe8\x36\xe0\x07\xa4\xff\x48\xe3\xcf\xfb\x3f\x00\xa5\xb3\x25\x50\x66\x02\x00\x00")
+
+func migrations31_reversal_window_issuerSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations31_reversal_window_issuerSql,
+		"migrations/31_reversal_window_issuer.sql",
+	)
+}
+
+func migrations31_reversal_window_issuerSql() (*asset, error) {
+	bytes, err := migrations31_reversal_window_issuerSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/31_reversal_window_issuer.sql", size: 614, mode: os.FileMode(420), modTime: time.Unix(1792405260, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 var _migrations3_aggregate_expenses_for_accountsSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x93\x41\x4b\xc3\x30\x14\xc7\xcf\xcd\xa7\x78\xc7\x0d\x37\x50\x11\x2f\x3b\x55\x5b\x61\x58\xbb\x51\x3a\x70\xa7\xf0\x4c\xc2\x16\x6c\x93\x92\xbc\x3a\xeb\xa7\x97\x6d\xa5\x8c\x6d\xda\xe6\x96\xf0\xfb\xff\x78\x90\xff\x9b\x4e\xe1\xa6\xd4\x1b\x87\xa4\x60\x55\x31\xf6\x9c\xc5\x61\x1e\x43\x1e\x3e\x25\x31\xa0\x10\xb6\x36\xc4\x3d\x21\x69\x4f\x5a\x78\x18\x31\x00\x00\x94\xd2\x29\xef\xe1\xf4\x88\x2d\x3a\x14\xa4\x1c\x7c\xa1\x6b\xb4\xd9\x8c\x1e\x1f\xc6\x90\x2e\x72\x48\x57\x49\x32\x39\xe6\xbc\x57\xc4\x85\x95\xea\xbf\xdc\xdd\xfd\x79\xee\x30\x86\x72\x15\x3a\x6a\x38\x35\xd5\x3e\xee\x4b\x2c\x0a\x6d\xa8\x43\x21\x8a\x5f\xc2\x55\x92\xc3\xed\x31\x24\x51\x17\x0d\xd7\x46\xd8\x52\x41\x10\x7c\xe8\x4d\x3f\x6d\x6b\x1a\x86\xef\x94\xfa\xbc\xb4\x07\x3d\x78\xab\xef\xb5\x97\xd6\xd0\xb6\xd3\x0f\xc6\xbb\xe9\x7b\x78\x34\xa6\xc6\x62\xa8\xbd\xa5\x87\xce\x5e\x57\x12\x49\x49\x8e\x04\x41\xb0\x7f\x20\x5d\x2a\x4f\x58\x56\xb0\xd3\xb4\x3d\x5c\xe1\xc7\x1a\x75\xf6\xc7\xcb\x6c\xfe\x16\x66\x6b\x78\x8d\xd7\xa3\xb6\x5f\x93\x93\xc2\x4c\x2e\x4b\x30\x66\xe3\x59\xd7\xd8\x79\x1a\xc5\xef\x57\x1a\xcb\x5b\x17\xd7\xf2\x1b\x16\xe9\xd5\x4e\xb7\xc8\xde\x76\xba\x0f\x91\xdd\x19\xc6\xa2\x6c\xb1\x1c\x64\x9f\x1d\xd1\xbf\x56\x67\xc6\x7e\x03\x00\x00\xff\xff\x26\xb0\x63\x72\x6c\x03\x00\x00")
 
 func migrations3_aggregate_expenses_for_accountsSqlBytes() ([]byte, error) {
@@ -309,8 +771,30 @@ func AssetNames() []string {
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
 	"latest.sql": latestSql,
+	"migrations/10_operation_filters.sql": migrations10_operation_filtersSql,
+	"migrations/11_memo_index.sql": migrations11_memo_indexSql,
+	"migrations/12_screening.sql": migrations12_screeningSql,
+	"migrations/13_compliance_alerts.sql": migrations13_compliance_alertsSql,
+	"migrations/14_account_freezes.sql": migrations14_account_freezesSql,
+	"migrations/15_reversal_windows.sql": migrations15_reversal_windowsSql,
+	"migrations/16_payment_refunds.sql": migrations16_payment_refundsSql,
+	"migrations/17_disputes.sql": migrations17_disputesSql,
+	"migrations/18_statistics_cache.sql": migrations18_statistics_cacheSql,
+	"migrations/19_txsub_shared_state.sql": migrations19_txsub_shared_stateSql,
 	"migrations/1_initial_schema.sql": migrations1_initial_schemaSql,
+	"migrations/20_leader_leases.sql": migrations20_leader_leasesSql,
+	"migrations/21_asset_metadata.sql": migrations21_asset_metadataSql,
+	"migrations/22_asset_supply.sql": migrations22_asset_supplySql,
+	"migrations/23_screening_assets.sql": migrations23_screening_assetsSql,
+	"migrations/24_payment_party_index.sql": migrations24_payment_party_indexSql,
+	"migrations/25_account_freeze_events.sql": migrations25_account_freeze_eventsSql,
+	"migrations/26_txsub_accepted_sequences.sql": migrations26_txsub_accepted_sequencesSql,
+	"migrations/27_asset_supply_backfill.sql": migrations27_asset_supply_backfillSql,
+	"migrations/28_payment_refund_reservations.sql": migrations28_payment_refund_reservationsSql,
+	"migrations/29_leader_fences.sql": migrations29_leader_fencesSql,
 	"migrations/2_index_participants_by_toid.sql": migrations2_index_participants_by_toidSql,
+	"migrations/30_statistics_cache_expiration.sql": migrations30_statistics_cache_expirationSql,
+	"migrations/31_reversal_window_issuer.sql": migrations31_reversal_window_issuerSql,
 	"migrations/3_aggregate_expenses_for_accounts.sql": migrations3_aggregate_expenses_for_accountsSql,
 	"migrations/7_account_limits.sql": migrations7_account_limitsSql,
 	"migrations/8_account_limits_two_way.sql": migrations8_account_limits_two_waySql,
@@ -361,8 +845,30 @@ type bintree struct {
 var _bintree = &bintree{nil, map[string]*bintree{
 	"latest.sql": &bintree{latestSql, map[string]*bintree{}},
 	"migrations": &bintree{nil, map[string]*bintree{
+		"10_operation_filters.sql": &bintree{migrations10_operation_filtersSql, map[string]*bintree{}},
+		"11_memo_index.sql": &bintree{migrations11_memo_indexSql, map[string]*bintree{}},
+		"12_screening.sql": &bintree{migrations12_screeningSql, map[string]*bintree{}},
+		"13_compliance_alerts.sql": &bintree{migrations13_compliance_alertsSql, map[string]*bintree{}},
+		"14_account_freezes.sql": &bintree{migrations14_account_freezesSql, map[string]*bintree{}},
+		"15_reversal_windows.sql": &bintree{migrations15_reversal_windowsSql, map[string]*bintree{}},
+		"16_payment_refunds.sql": &bintree{migrations16_payment_refundsSql, map[string]*bintree{}},
+		"17_disputes.sql": &bintree{migrations17_disputesSql, map[string]*bintree{}},
+		"18_statistics_cache.sql": &bintree{migrations18_statistics_cacheSql, map[string]*bintree{}},
+		"19_txsub_shared_state.sql": &bintree{migrations19_txsub_shared_stateSql, map[string]*bintree{}},
 		"1_initial_schema.sql": &bintree{migrations1_initial_schemaSql, map[string]*bintree{}},
+		"20_leader_leases.sql": &bintree{migrations20_leader_leasesSql, map[string]*bintree{}},
+		"21_asset_metadata.sql": &bintree{migrations21_asset_metadataSql, map[string]*bintree{}},
+		"22_asset_supply.sql": &bintree{migrations22_asset_supplySql, map[string]*bintree{}},
+		"23_screening_assets.sql": &bintree{migrations23_screening_assetsSql, map[string]*bintree{}},
+		"24_payment_party_index.sql": &bintree{migrations24_payment_party_indexSql, map[string]*bintree{}},
+		"25_account_freeze_events.sql": &bintree{migrations25_account_freeze_eventsSql, map[string]*bintree{}},
+		"26_txsub_accepted_sequences.sql": &bintree{migrations26_txsub_accepted_sequencesSql, map[string]*bintree{}},
+		"27_asset_supply_backfill.sql": &bintree{migrations27_asset_supply_backfillSql, map[string]*bintree{}},
+		"28_payment_refund_reservations.sql": &bintree{migrations28_payment_refund_reservationsSql, map[string]*bintree{}},
+		"29_leader_fences.sql": &bintree{migrations29_leader_fencesSql, map[string]*bintree{}},
 		"2_index_participants_by_toid.sql": &bintree{migrations2_index_participants_by_toidSql, map[string]*bintree{}},
+		"30_statistics_cache_expiration.sql": &bintree{migrations30_statistics_cache_expirationSql, map[string]*bintree{}},
+		"31_reversal_window_issuer.sql": &bintree{migrations31_reversal_window_issuerSql, map[string]*bintree{}},
 		"3_aggregate_expenses_for_accounts.sql": &bintree{migrations3_aggregate_expenses_for_accountsSql, map[string]*bintree{}},
 		"7_account_limits.sql": &bintree{migrations7_account_limitsSql, map[string]*bintree{}},
 		"8_account_limits_two_way.sql": &bintree{migrations8_account_limits_two_waySql, map[string]*bintree{}},
@@ -0,0 +1,13 @@
+-- +migrate Up
+
+CREATE INDEX hop_by_asset_code ON history_operations USING btree (((details ->> 'asset_code')::text), ((details ->> 'asset_issuer')::text));
+CREATE INDEX hop_by_source_asset_code ON history_operations USING btree (((details ->> 'source_asset_code')::text), ((details ->> 'source_asset_issuer')::text));
+CREATE INDEX hop_by_amount ON history_operations USING btree (((details ->> 'amount')::numeric));
+CREATE INDEX index_history_accounts_on_account_type ON history_accounts USING btree (account_type);
+
+-- +migrate Down
+
+DROP INDEX hop_by_asset_code;
+DROP INDEX hop_by_source_asset_code;
+DROP INDEX hop_by_amount;
+DROP INDEX index_history_accounts_on_account_type;
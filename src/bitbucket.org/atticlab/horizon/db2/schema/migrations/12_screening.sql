@@ -0,0 +1,32 @@
+-- +migrate Up
+
+CREATE TABLE screening_list
+(
+  address varchar(64) NOT NULL,
+  reason text NOT NULL DEFAULT '',
+  created_at timestamp without time zone NOT NULL DEFAULT now(),
+  PRIMARY KEY(address)
+);
+
+CREATE TABLE screening_results
+(
+  id bigserial,
+  tx_hash character varying(64) NOT NULL,
+  operation_index integer NOT NULL,
+  operation_type integer NOT NULL,
+  source varchar(64) NOT NULL,
+  destination varchar(64) NOT NULL,
+  provider varchar(32) NOT NULL,
+  decision varchar(16) NOT NULL,
+  reason text NOT NULL DEFAULT '',
+  created_at timestamp without time zone NOT NULL DEFAULT now(),
+  PRIMARY KEY(id)
+);
+
+CREATE INDEX screening_results_by_tx_hash ON screening_results USING btree (tx_hash);
+CREATE INDEX screening_results_by_decision ON screening_results USING btree (decision, created_at);
+
+-- +migrate Down
+
+DROP TABLE screening_results;
+DROP TABLE screening_list;
@@ -0,0 +1,24 @@
+-- +migrate Up
+
+CREATE TABLE compliance_alerts
+(
+  id bigserial,
+  rule varchar(32) NOT NULL,
+  address varchar(64) NOT NULL,
+  asset_code varchar(12) NOT NULL,
+  ledger_sequence integer NOT NULL,
+  description text NOT NULL,
+  operations text NOT NULL,
+  acknowledged boolean NOT NULL DEFAULT false,
+  acknowledged_at timestamp without time zone,
+  comment text NOT NULL DEFAULT '',
+  created_at timestamp without time zone NOT NULL DEFAULT now(),
+  PRIMARY KEY(id)
+);
+
+CREATE INDEX compliance_alerts_by_address ON compliance_alerts USING btree (address, rule, asset_code);
+CREATE INDEX compliance_alerts_open ON compliance_alerts USING btree (id) WHERE (acknowledged = false);
+
+-- +migrate Down
+
+DROP TABLE compliance_alerts;
@@ -0,0 +1,42 @@
+-- +migrate Up
+
+CREATE TABLE account_freezes
+(
+  id bigserial,
+  address varchar(64) NOT NULL,
+  -- empty asset code means freeze of all assets
+  asset_code varchar(12) NOT NULL DEFAULT '',
+  block_incoming_payments boolean NOT NULL DEFAULT false,
+  block_outcoming_payments boolean NOT NULL DEFAULT false,
+  reason_code varchar(32) NOT NULL,
+  comment text NOT NULL DEFAULT '',
+  expires_at timestamp without time zone,
+  lifted_at timestamp without time zone,
+  created_at timestamp without time zone NOT NULL DEFAULT now(),
+  PRIMARY KEY(id)
+);
+
+CREATE INDEX account_freezes_active_by_address ON account_freezes USING btree (address) WHERE lifted_at IS NULL;
+
+CREATE TABLE account_traits_history
+(
+  id bigserial,
+  address varchar(64) NOT NULL,
+  event varchar(16) NOT NULL,
+  freeze_id bigint,
+  asset_code varchar(12) NOT NULL DEFAULT '',
+  block_incoming_payments boolean NOT NULL DEFAULT false,
+  block_outcoming_payments boolean NOT NULL DEFAULT false,
+  reason_code varchar(32) NOT NULL DEFAULT '',
+  comment text NOT NULL DEFAULT '',
+  expires_at timestamp without time zone,
+  created_at timestamp without time zone NOT NULL DEFAULT now(),
+  PRIMARY KEY(id)
+);
+
+CREATE INDEX account_traits_history_by_address ON account_traits_history USING btree (address, id);
+
+-- +migrate Down
+
+DROP TABLE account_traits_history;
+DROP TABLE account_freezes;
@@ -0,0 +1,20 @@
+-- +migrate Up
+
+CREATE TABLE reversal_windows
+(
+  id bigserial,
+  -- empty asset code matches any asset
+  asset_code varchar(12) NOT NULL DEFAULT '',
+  -- null account type matches any type
+  sender_type integer,
+  receiver_type integer,
+  -- seconds payment can be reversed within. Zero means payment can not be reversed
+  duration bigint NOT NULL,
+  PRIMARY KEY(id)
+);
+
+CREATE UNIQUE INDEX reversal_windows_by_key ON reversal_windows USING btree (asset_code, COALESCE(sender_type, -1), COALESCE(receiver_type, -1));
+
+-- +migrate Down
+
+DROP TABLE reversal_windows;
@@ -0,0 +1,30 @@
+-- +migrate Up
+
+-- reversals and refunds of payments. Sum of amounts returned for the payment
+-- must not exceed payment amount
+CREATE TABLE payment_refunds
+(
+  -- id of reversal or refund operation
+  id bigint NOT NULL,
+  payment_id bigint NOT NULL,
+  type integer NOT NULL,
+  amount bigint NOT NULL,
+  PRIMARY KEY(id)
+);
+
+CREATE INDEX payment_refunds_by_payment ON payment_refunds USING btree (payment_id);
+
+-- backfill from reversals and refunds already ingested, the only operations
+-- with payment_id in details
+INSERT INTO payment_refunds (id, payment_id, type, amount)
+SELECT
+  hop.id,
+  (hop.details->>'payment_id')::bigint,
+  hop.type,
+  ((hop.details->>'amount')::numeric * 10000000)::bigint
+FROM history_operations hop
+WHERE hop.details ? 'payment_id';
+
+-- +migrate Down
+
+DROP TABLE payment_refunds;
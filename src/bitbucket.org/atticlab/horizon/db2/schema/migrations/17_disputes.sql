@@ -0,0 +1,45 @@
+-- +migrate Up
+
+CREATE TABLE disputes
+(
+  id bigserial,
+  payment_id bigint NOT NULL,
+  state varchar(32) NOT NULL,
+  -- sender of the disputed payment
+  claimant varchar(64) NOT NULL,
+  -- receiver of the disputed payment, its funds are locked while dispute is open
+  respondent varchar(64) NOT NULL,
+  asset_code varchar(12) NOT NULL,
+  amount bigint NOT NULL,
+  freeze_id bigint,
+  -- reversal or refund operation which returned the payment
+  resolution_operation_id bigint,
+  created_at timestamp without time zone NOT NULL DEFAULT now(),
+  updated_at timestamp without time zone NOT NULL DEFAULT now(),
+  PRIMARY KEY(id)
+);
+
+CREATE INDEX disputes_by_payment ON disputes USING btree (payment_id);
+CREATE INDEX disputes_by_claimant ON disputes USING btree (claimant, id);
+CREATE INDEX disputes_by_respondent ON disputes USING btree (respondent, id);
+CREATE UNIQUE INDEX disputes_active_by_payment ON disputes USING btree (payment_id) WHERE state IN ('open', 'merchant_responded');
+
+CREATE TABLE dispute_notes
+(
+  id bigserial,
+  dispute_id bigint NOT NULL,
+  -- state dispute was moved to, empty if state was not changed
+  state varchar(32) NOT NULL DEFAULT '',
+  note text NOT NULL DEFAULT '',
+  -- json array of references to evidence documents
+  evidence text NOT NULL DEFAULT '[]',
+  created_at timestamp without time zone NOT NULL DEFAULT now(),
+  PRIMARY KEY(id)
+);
+
+CREATE INDEX dispute_notes_by_dispute ON dispute_notes USING btree (dispute_id, id);
+
+-- +migrate Down
+
+DROP TABLE dispute_notes;
+DROP TABLE disputes;
@@ -0,0 +1,34 @@
+-- +migrate Up
+
+-- account statistics updated with submitted, but not yet ingested payments.
+-- Used instead of redis by postgres statistics backend
+CREATE TABLE statistics_cache
+(
+  address varchar(64) NOT NULL,
+  asset_code varchar(12) NOT NULL,
+  balance bigint NOT NULL,
+  -- json array of statistics per counterparty type
+  statistics text NOT NULL,
+  expires_at timestamp without time zone NOT NULL,
+  PRIMARY KEY(address, asset_code)
+);
+
+-- payments accounted in statistics_cache
+CREATE TABLE statistics_processed_ops
+(
+  address varchar(64) NOT NULL,
+  tx_hash varchar(64) NOT NULL,
+  op_index integer NOT NULL,
+  is_incoming boolean NOT NULL,
+  amount bigint NOT NULL,
+  updated_at timestamp without time zone NOT NULL,
+  expires_at timestamp without time zone NOT NULL,
+  PRIMARY KEY(address, tx_hash, op_index, is_incoming)
+);
+
+CREATE INDEX statistics_processed_ops_by_expiration ON statistics_processed_ops USING btree (expires_at);
+
+-- +migrate Down
+
+DROP TABLE statistics_processed_ops;
+DROP TABLE statistics_cache;
@@ -0,0 +1,26 @@
+-- +migrate Up
+
+-- transactions submitted to stellar-core, but not yet ingested. Shared by
+-- horizon replicas using postgres txsub backend
+CREATE TABLE txsub_pending
+(
+  hash varchar(64) NOT NULL PRIMARY KEY,
+  submitted_at timestamp without time zone NOT NULL
+);
+
+CREATE INDEX txsub_pending_by_submitted_at ON txsub_pending USING btree (submitted_at);
+
+-- sequence numbers of accounts claimed by submitted transactions
+CREATE TABLE txsub_sequence_reservations
+(
+  address varchar(64) NOT NULL,
+  sequence bigint NOT NULL,
+  hash varchar(64) NOT NULL,
+  expires_at timestamp without time zone NOT NULL,
+  PRIMARY KEY(address, sequence)
+);
+
+-- +migrate Down
+
+DROP TABLE txsub_sequence_reservations;
+DROP TABLE txsub_pending;
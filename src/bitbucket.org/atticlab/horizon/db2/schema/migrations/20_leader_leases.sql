@@ -0,0 +1,13 @@
+-- +migrate Up
+
+-- leases electing the only horizon replica performing a task, e.g. ingestion
+CREATE TABLE leader_leases
+(
+  name varchar(64) NOT NULL PRIMARY KEY,
+  holder varchar(255) NOT NULL,
+  expires_at timestamp without time zone NOT NULL
+);
+
+-- +migrate Down
+
+DROP TABLE leader_leases;
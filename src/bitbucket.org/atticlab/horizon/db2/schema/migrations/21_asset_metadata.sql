@@ -0,0 +1,19 @@
+-- +migrate Up
+
+ALTER TABLE asset ADD COLUMN name varchar(64) NOT NULL DEFAULT '';
+-- number of decimal places used to display amounts of the asset
+ALTER TABLE asset ADD COLUMN decimals int NOT NULL DEFAULT 2;
+ALTER TABLE asset ADD COLUMN description text NOT NULL DEFAULT '';
+ALTER TABLE asset ADD COLUMN logo_url text NOT NULL DEFAULT '';
+-- json array of regulatory flags of the asset
+ALTER TABLE asset ADD COLUMN regulatory_flags text NOT NULL DEFAULT '[]';
+ALTER TABLE asset ADD COLUMN status varchar(16) NOT NULL DEFAULT 'active';
+
+-- +migrate Down
+
+ALTER TABLE asset DROP COLUMN status;
+ALTER TABLE asset DROP COLUMN regulatory_flags;
+ALTER TABLE asset DROP COLUMN logo_url;
+ALTER TABLE asset DROP COLUMN description;
+ALTER TABLE asset DROP COLUMN decimals;
+ALTER TABLE asset DROP COLUMN name;
@@ -0,0 +1,21 @@
+-- +migrate Up
+
+-- amounts issued or redeemed by payments from/to the issuer of the asset
+CREATE TABLE asset_supply_changes
+(
+  id bigserial,
+  operation_id bigint NOT NULL,
+  asset_code varchar(12) NOT NULL,
+  asset_issuer varchar(64) NOT NULL,
+  issued bigint NOT NULL DEFAULT 0,
+  redeemed bigint NOT NULL DEFAULT 0,
+  closed_at timestamp without time zone NOT NULL,
+  PRIMARY KEY(id)
+);
+
+CREATE INDEX asset_supply_changes_by_asset ON asset_supply_changes USING btree (asset_code, asset_issuer, closed_at);
+CREATE INDEX asset_supply_changes_by_operation ON asset_supply_changes USING btree (operation_id);
+
+-- +migrate Down
+
+DROP TABLE asset_supply_changes;
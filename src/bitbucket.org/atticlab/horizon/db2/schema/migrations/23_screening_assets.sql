@@ -0,0 +1,9 @@
+-- +migrate Up
+
+ALTER TABLE screening_results ADD COLUMN asset_code varchar(12) NOT NULL DEFAULT '';
+ALTER TABLE screening_results ADD COLUMN asset_issuer varchar(64) NOT NULL DEFAULT '';
+
+-- +migrate Down
+
+ALTER TABLE screening_results DROP COLUMN asset_issuer;
+ALTER TABLE screening_results DROP COLUMN asset_code;
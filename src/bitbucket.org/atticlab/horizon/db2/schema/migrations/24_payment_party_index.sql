@@ -0,0 +1,10 @@
+-- +migrate Up
+
+-- payments and path payments sent from or received by the account, used by compliance rules
+CREATE INDEX hop_by_from ON history_operations USING btree (((details ->> 'from')::text), id);
+CREATE INDEX hop_by_to ON history_operations USING btree (((details ->> 'to')::text), id);
+
+-- +migrate Down
+
+DROP INDEX hop_by_from;
+DROP INDEX hop_by_to;
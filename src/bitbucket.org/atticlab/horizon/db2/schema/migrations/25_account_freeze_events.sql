@@ -0,0 +1,20 @@
+-- +migrate Up
+
+-- administrative operation created the freeze or the event, so reingested operations do not duplicate them
+ALTER TABLE account_freezes ADD COLUMN operation_id bigint;
+ALTER TABLE account_traits_history ADD COLUMN operation_id bigint;
+
+CREATE UNIQUE INDEX account_freezes_by_operation ON account_freezes USING btree (operation_id);
+CREATE UNIQUE INDEX account_traits_history_by_operation ON account_traits_history USING btree (operation_id, event, COALESCE(freeze_id, 0));
+-- freezes waiting for expiration
+CREATE INDEX account_freezes_by_expiration ON account_freezes USING btree (expires_at) WHERE lifted_at IS NULL;
+CREATE UNIQUE INDEX account_traits_history_expire_by_freeze ON account_traits_history USING btree (freeze_id) WHERE event = 'expire';
+
+-- +migrate Down
+
+DROP INDEX account_traits_history_expire_by_freeze;
+DROP INDEX account_freezes_by_expiration;
+DROP INDEX account_traits_history_by_operation;
+DROP INDEX account_freezes_by_operation;
+ALTER TABLE account_traits_history DROP COLUMN operation_id;
+ALTER TABLE account_freezes DROP COLUMN operation_id;
@@ -0,0 +1,8 @@
+-- +migrate Up
+
+-- reservation of the transaction accepted by stellar-core, its sequence unblocks queued submissions on all replicas
+ALTER TABLE txsub_sequence_reservations ADD COLUMN accepted boolean NOT NULL DEFAULT false;
+
+-- +migrate Down
+
+ALTER TABLE txsub_sequence_reservations DROP COLUMN accepted;
@@ -0,0 +1,50 @@
+-- +migrate Up
+
+-- asset_supply_changes of operations ingested before the table was added. Payments, path payments, reversals and
+-- refunds are recognized by their details, as stored by ingestion; operations already having changes are skipped.
+INSERT INTO asset_supply_changes (operation_id, asset_code, asset_issuer, issued, redeemed, closed_at)
+SELECT ops.id,
+       ops.asset_code,
+       ops.asset_issuer,
+       CASE WHEN ops.sender = ops.asset_issuer THEN ops.amount ELSE 0 END,
+       CASE WHEN ops.receiver = ops.asset_issuer THEN ops.amount ELSE 0 END,
+       hl.closed_at
+FROM (
+  -- payments, reversals and refunds
+  SELECT ho.id,
+         ho.details->>'asset_code' AS asset_code,
+         ho.details->>'asset_issuer' AS asset_issuer,
+         COALESCE(ho.details->>'from', ho.details->>'source_account') AS sender,
+         COALESCE(ho.details->>'to', ho.details->>'payment_source') AS receiver,
+         ((ho.details->>'amount')::numeric * 10000000)::bigint AS amount
+  FROM history_operations ho
+  WHERE (ho.details ? 'to' OR ho.details ? 'payment_source') AND NOT ho.details ? 'source_amount'
+  UNION ALL
+  -- sent asset of path payments
+  SELECT ho.id,
+         ho.details->>'source_asset_code',
+         ho.details->>'source_asset_issuer',
+         ho.details->>'from',
+         '',
+         ((ho.details->>'source_amount')::numeric * 10000000)::bigint
+  FROM history_operations ho
+  WHERE ho.details ? 'source_amount'
+  UNION ALL
+  -- received asset of path payments
+  SELECT ho.id,
+         ho.details->>'asset_code',
+         ho.details->>'asset_issuer',
+         '',
+         ho.details->>'to',
+         ((ho.details->>'amount')::numeric * 10000000)::bigint
+  FROM history_operations ho
+  WHERE ho.details ? 'source_amount'
+) ops
+JOIN history_ledgers hl ON hl.sequence = (ops.id >> 32)::integer
+WHERE ops.asset_code IS NOT NULL
+  AND (ops.sender = ops.asset_issuer) <> (ops.receiver = ops.asset_issuer)
+  AND NOT EXISTS (SELECT 1 FROM asset_supply_changes c WHERE c.operation_id = ops.id);
+
+-- +migrate Down
+
+-- backfilled rows are the same as rows stored by ingestion, so they are kept
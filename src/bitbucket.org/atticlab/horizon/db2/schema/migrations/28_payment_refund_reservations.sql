@@ -0,0 +1,18 @@
+-- +migrate Up
+
+-- amount of the payment returned by submitted reversal or refund, which is not yet ingested
+CREATE TABLE payment_refund_reservations
+(
+  tx_hash varchar(64) NOT NULL,
+  op_index integer NOT NULL,
+  payment_id bigint NOT NULL,
+  amount bigint NOT NULL,
+  expires_at timestamp without time zone NOT NULL,
+  PRIMARY KEY(tx_hash, op_index)
+);
+
+CREATE INDEX payment_refund_reservations_by_payment ON payment_refund_reservations USING btree (payment_id);
+
+-- +migrate Down
+
+DROP TABLE payment_refund_reservations;
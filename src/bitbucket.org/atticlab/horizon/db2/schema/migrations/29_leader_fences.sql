@@ -0,0 +1,13 @@
+-- +migrate Up
+
+-- the greatest fencing token of the lease, which holder has committed work of the task.
+-- Used by leases stored outside of history db, e.g. in redis.
+CREATE TABLE leader_fences
+(
+  name varchar(64) NOT NULL PRIMARY KEY,
+  token bigint NOT NULL
+);
+
+-- +migrate Down
+
+DROP TABLE leader_fences;
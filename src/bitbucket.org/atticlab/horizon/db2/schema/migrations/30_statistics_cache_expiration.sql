@@ -0,0 +1,8 @@
+-- +migrate Up
+
+-- expired statistics cache is removed on save of any account's cache
+CREATE INDEX statistics_cache_by_expiration ON statistics_cache USING btree (expires_at);
+
+-- +migrate Down
+
+DROP INDEX statistics_cache_by_expiration;
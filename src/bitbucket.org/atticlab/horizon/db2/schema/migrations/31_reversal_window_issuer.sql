@@ -0,0 +1,13 @@
+-- +migrate Up
+
+-- empty asset issuer matches any issuer of the asset code
+ALTER TABLE reversal_windows ADD COLUMN asset_issuer varchar(64) NOT NULL DEFAULT '';
+
+DROP INDEX reversal_windows_by_key;
+CREATE UNIQUE INDEX reversal_windows_by_key ON reversal_windows USING btree (asset_code, asset_issuer, COALESCE(sender_type, -1), COALESCE(receiver_type, -1));
+
+-- +migrate Down
+
+DROP INDEX reversal_windows_by_key;
+ALTER TABLE reversal_windows DROP COLUMN asset_issuer;
+CREATE UNIQUE INDEX reversal_windows_by_key ON reversal_windows USING btree (asset_code, COALESCE(sender_type, -1), COALESCE(receiver_type, -1));
@@ -2,38 +2,131 @@ package friendbot
 
 import (
 	"errors"
-	"sync"
 
+	"bitbucket.org/atticlab/go-smart-base/amount"
 	. "bitbucket.org/atticlab/go-smart-base/build"
-	"bitbucket.org/atticlab/go-smart-base/keypair"
+	"bitbucket.org/atticlab/go-smart-base/xdr"
 	"bitbucket.org/atticlab/horizon/txsub"
 	"bitbucket.org/atticlab/horizon/txsub/results"
 	"golang.org/x/net/context"
 )
 
+var (
+	// ErrQuotaExceeded is returned if IP or address made too many requests
+	// during the quota window
+	ErrQuotaExceeded = errors.New("friendbot quota exceeded")
+	// ErrNothingToFund is returned if request neither creates account nor
+	// funds an asset
+	ErrNothingToFund = errors.New("nothing to fund")
+)
+
 // Bot represents the friendbot subsystem.
 type Bot struct {
 	Submitter *txsub.System
-	Secret    string
 	Network   string
+	// Pool of funding accounts the transactions are sent from
+	Pool *Pool
+	// AccountTypes allowed to create, the first one is the default. Only
+	// anonymous users are created if empty.
+	AccountTypes []xdr.AccountType
+	// Amount of the asset sent on request
+	Amount int64
+	// IPQuota and AddressQuota limit requests per client IP and per funded
+	// address, not limited if nil
+	IPQuota      Quota
+	AddressQuota Quota
+}
 
-	sequence uint64
-	lock     sync.Mutex
+// Request describes what friendbot sends to the account at Address
+type Request struct {
+	Address string
+	// CreateAccount is true if account does not exist yet and must be created
+	// with AccountType
+	CreateAccount bool
+	AccountType   xdr.AccountType
+	// AssetCode and AssetIssuer of the asset sent to the account, no asset is
+	// sent if empty
+	AssetCode   string
+	AssetIssuer string
+}
+
+// DefaultAccountType returns type of the account created if request does not
+// specify one
+func (bot *Bot) DefaultAccountType() xdr.AccountType {
+	if len(bot.AccountTypes) == 0 {
+		return xdr.AccountTypeAccountAnonymousUser
+	}
+	return bot.AccountTypes[0]
 }
 
-// Pay funds the account at `address`
-func (bot *Bot) Pay(ctx context.Context, address string) (result txsub.Result) {
+// IsAllowedType returns true if friendbot may create account of the type
+func (bot *Bot) IsAllowedType(accountType xdr.AccountType) bool {
+	if len(bot.AccountTypes) == 0 {
+		return accountType == xdr.AccountTypeAccountAnonymousUser
+	}
+
+	for _, allowed := range bot.AccountTypes {
+		if allowed == accountType {
+			return true
+		}
+	}
+	return false
+}
+
+// TakeQuotas counts request from ip for address. Returns ErrQuotaExceeded if
+// either of them made too many requests, request is counted by neither quota
+// in that case.
+func (bot *Bot) TakeQuotas(ip, address string) error {
+	err := takeQuota(bot.IPQuota, ip)
+	if err != nil {
+		return err
+	}
+
+	err = takeQuota(bot.AddressQuota, address)
+	if err != nil && bot.IPQuota != nil {
+		returnErr := bot.IPQuota.Return(ip)
+		if returnErr != nil {
+			return returnErr
+		}
+	}
+	return err
+}
+
+func takeQuota(quota Quota, key string) error {
+	if quota == nil {
+		return nil
+	}
+
+	ok, err := quota.Take(key)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrQuotaExceeded
+	}
+	return nil
+}
+
+// Fund creates and/or funds the account described by the request. Transaction
+// is sent from the first free funding account of the pool.
+func (bot *Bot) Fund(ctx context.Context, req Request) (result txsub.Result) {
+	account, err := bot.Pool.acquire(ctx)
+	if err != nil {
+		result.Err = err
+		return
+	}
+	defer bot.Pool.release(account)
 
 	// establish initial sequence if needed
-	if bot.sequence == 0 {
-		result.Err = bot.refreshSequence(ctx)
+	if account.sequence == 0 {
+		result.Err = bot.loadSequence(account)
 		if result.Err != nil {
 			return
 		}
 	}
 
 	var envelope string
-	envelope, result.Err = bot.makeTx(address)
+	envelope, result.Err = bot.makeTx(account, req)
 	if result.Err != nil {
 		return
 	}
@@ -43,61 +136,76 @@ func (bot *Bot) Pay(ctx context.Context, address string) (result txsub.Result) {
 	select {
 	case result := <-resultChan:
 		if result.Err != nil {
-			bot.refreshSequence(ctx)
+			// failed transaction may or may not consume the sequence
+			account.sequence = 0
+			return result
 		}
+		account.sequence++
 		return result
 	case <-ctx.Done():
+		account.sequence = 0
 		return txsub.Result{Err: results.ErrCanceled}
 	}
 }
 
-func (bot *Bot) makeTx(address string) (string, error) {
-	bot.lock.Lock()
-	bot.lock.Unlock()
+func (bot *Bot) makeTx(account *fundingAccount, req Request) (string, error) {
+	if !req.CreateAccount && req.AssetCode == "" {
+		return "", ErrNothingToFund
+	}
 
-	tx := Transaction(
-		SourceAccount{bot.Secret},
-		Sequence{bot.sequence + 1},
+	muts := []TransactionMutator{
+		SourceAccount{account.secret},
+		Sequence{account.sequence + 1},
 		Network{bot.Network},
-		CreateAccount(
-			Destination{address},
-			NativeAmount{"10000.00"},
-		),
-	)
+	}
+
+	if req.CreateAccount {
+		muts = append(muts, CreateAccount(
+			Destination{req.Address},
+			createAccountType{req.AccountType},
+		))
+	}
 
+	if req.AssetCode != "" {
+		muts = append(muts, Payment(
+			Destination{req.Address},
+			CreditAmount{req.AssetCode, req.AssetIssuer, amount.String(xdr.Int64(bot.Amount))},
+		))
+	}
+
+	tx := Transaction(muts...)
 	if tx.Err != nil {
 		return "", tx.Err
 	}
 
-	bot.sequence++
-
-	txe := tx.Sign(bot.Secret)
+	txe := tx.Sign(account.secret)
 
 	return txe.Base64()
 }
 
-func (bot *Bot) refreshSequence(ctx context.Context) error {
-	bot.lock.Lock()
-	bot.lock.Unlock()
-
-	addy := bot.address()
-	sp := bot.Submitter.Sequences
-
-	seqs, err := sp.Get([]string{addy})
+func (bot *Bot) loadSequence(account *fundingAccount) error {
+	seqs, err := bot.Submitter.Sequences.Get([]string{account.address})
 	if err != nil {
 		return err
 	}
 
-	seq, ok := seqs[addy]
+	seq, ok := seqs[account.address]
 	if !ok {
 		return errors.New("friendbot account not found")
 	}
 
-	bot.sequence = seq
+	account.sequence = seq
 	return nil
 }
 
-func (bot *Bot) address() string {
-	kp := keypair.MustParse(bot.Secret)
-	return kp.Address()
+// createAccountType sets type of the created account
+type createAccountType struct {
+	Type xdr.AccountType
+}
+
+// MutateCreateAccount for createAccountType sets the CreateAccountOp's
+// AccountType field
+func (m createAccountType) MutateCreateAccount(o *xdr.CreateAccountOp) error {
+	o.Body.AccountType = m.Type
+	return nil
 }
@@ -2,22 +2,168 @@ package friendbot
 
 import (
 	"testing"
+	"time"
 
+	"bitbucket.org/atticlab/go-smart-base/xdr"
+	"bitbucket.org/atticlab/horizon/leader"
 	"bitbucket.org/atticlab/horizon/test"
+	. "github.com/smartystreets/goconvey/convey"
+	"golang.org/x/net/context"
 )
 
+// leaseMock is a lease held by the first holder until released
+type leaseMock struct {
+	holder string
+}
+
+func (l *leaseMock) Acquire(holder string, ttl time.Duration) (string, error) {
+	if l.holder == "" {
+		l.holder = holder
+	}
+	return l.holder, nil
+}
+
+func (l *leaseMock) Release(holder string) error {
+	if l.holder == holder {
+		l.holder = ""
+	}
+	return nil
+}
+
+func (l *leaseMock) Holder() (string, error) {
+	return l.holder, nil
+}
+
 // REGRESSION:  ensure that we can craft a transaction
 func _TestFriendbot_makeTx(t *testing.T) {
 	tt := test.Start(t).Scenario("base")
 	defer tt.Finish()
 
+	pool, err := NewPool([]string{"SAQWC7EPIYF3XGILYVJM4LVAVSLZKT27CTEI3AFBHU2VRCMQ3P3INPG5"})
+	tt.Require.NoError(err)
+
 	fb := &Bot{
-		Secret:   "SAQWC7EPIYF3XGILYVJM4LVAVSLZKT27CTEI3AFBHU2VRCMQ3P3INPG5",
-		Network:  "Test SDF Network ; September 2015",
-		sequence: 2,
+		Network: "Test SDF Network ; September 2015",
+		Pool:    pool,
 	}
 
-	_, err := fb.makeTx("GDJIN6W6PLTPKLLM57UW65ZH4BITUXUMYQHIMAZFYXF45PZVAWDBI77Z")
+	account, err := pool.acquire(tt.Ctx)
+	tt.Require.NoError(err)
+	account.sequence = 2
+
+	_, err = fb.makeTx(account, Request{
+		Address:       "GDJIN6W6PLTPKLLM57UW65ZH4BITUXUMYQHIMAZFYXF45PZVAWDBI77Z",
+		CreateAccount: true,
+		AccountType:   xdr.AccountTypeAccountAnonymousUser,
+	})
 
 	tt.Require.NoError(err)
 }
+
+func TestFriendbot(t *testing.T) {
+	Convey("Friendbot:", t, func() {
+		Convey("Account types", func() {
+			bot := &Bot{}
+			So(bot.DefaultAccountType(), ShouldEqual, xdr.AccountTypeAccountAnonymousUser)
+			So(bot.IsAllowedType(xdr.AccountTypeAccountAnonymousUser), ShouldBeTrue)
+			So(bot.IsAllowedType(xdr.AccountTypeAccountBank), ShouldBeFalse)
+
+			bot.AccountTypes = []xdr.AccountType{xdr.AccountTypeAccountRegisteredUser, xdr.AccountTypeAccountMerchant}
+			So(bot.DefaultAccountType(), ShouldEqual, xdr.AccountTypeAccountRegisteredUser)
+			So(bot.IsAllowedType(xdr.AccountTypeAccountMerchant), ShouldBeTrue)
+			So(bot.IsAllowedType(xdr.AccountTypeAccountAnonymousUser), ShouldBeFalse)
+		})
+
+		Convey("Memory quota", func() {
+			now := time.Date(2017, 3, 1, 0, 0, 0, 0, time.UTC)
+			quota := NewMemoryQuota(2, time.Hour)
+			quota.now = func() time.Time { return now }
+
+			for i := 0; i < 2; i++ {
+				ok, err := quota.Take("127.0.0.1")
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			}
+
+			ok, _ := quota.Take("127.0.0.1")
+			So(ok, ShouldBeFalse)
+			ok, _ = quota.Take("127.0.0.2")
+			So(ok, ShouldBeTrue)
+
+			now = now.Add(time.Hour)
+			ok, _ = quota.Take("127.0.0.1")
+			So(ok, ShouldBeTrue)
+			So(len(quota.windows), ShouldEqual, 1)
+		})
+
+		Convey("Quotas of the bot", func() {
+			bot := &Bot{
+				IPQuota:      NewMemoryQuota(2, time.Hour),
+				AddressQuota: NewMemoryQuota(1, time.Hour),
+			}
+			address := "GDJIN6W6PLTPKLLM57UW65ZH4BITUXUMYQHIMAZFYXF45PZVAWDBI77Z"
+			So(bot.TakeQuotas("127.0.0.1", address), ShouldBeNil)
+			So(bot.TakeQuotas("127.0.0.1", address), ShouldEqual, ErrQuotaExceeded)
+
+			// request rejected by address quota is not counted by IP quota
+			other := "GAWIB7ETYGSWULO4VB7D6S42YLPGIC7TY7Y2SSJKVOTMQXV5TILYWBUA"
+			So(bot.TakeQuotas("127.0.0.1", other), ShouldBeNil)
+		})
+
+		Convey("Pool", func() {
+			_, err := NewPool(nil)
+			So(err, ShouldNotBeNil)
+			_, err = NewPool([]string{"GDJIN6W6PLTPKLLM57UW65ZH4BITUXUMYQHIMAZFYXF45PZVAWDBI77Z"})
+			So(err, ShouldNotBeNil)
+
+			pool, err := NewPool([]string{
+				"SAQWC7EPIYF3XGILYVJM4LVAVSLZKT27CTEI3AFBHU2VRCMQ3P3INPG5",
+				"SDB6PLQGTJQUBQ3MGZKCYP5KNGL57TZUM3CK7NNPI5KYE7ACGSCTKRWQ",
+			})
+			So(err, ShouldBeNil)
+			So(pool.Size(), ShouldEqual, 2)
+
+			first, err := pool.acquire(context.Background())
+			So(err, ShouldBeNil)
+			second, err := pool.acquire(context.Background())
+			So(err, ShouldBeNil)
+			So(first.address, ShouldNotEqual, second.address)
+
+			Convey("skips accounts used by other replicas", func() {
+				pool.release(first)
+				pool.release(second)
+				leases := map[string]*leaseMock{}
+				pool.Share("replica", time.Minute, func(address string) leader.Lease {
+					leases[address] = &leaseMock{}
+					return leases[address]
+				})
+				leases[first.address].holder = "other"
+
+				account, err := pool.acquire(context.Background())
+				So(err, ShouldBeNil)
+				So(account, ShouldEqual, second)
+				So(leases[second.address].holder, ShouldEqual, "replica")
+
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+				defer cancel()
+				_, err = pool.acquire(ctx)
+				So(err, ShouldNotBeNil)
+
+				pool.release(account)
+				So(leases[second.address].holder, ShouldEqual, "")
+			})
+
+			Convey("waits for released account", func() {
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+				defer cancel()
+				_, err := pool.acquire(ctx)
+				So(err, ShouldNotBeNil)
+
+				pool.release(first)
+				account, err := pool.acquire(context.Background())
+				So(err, ShouldBeNil)
+				So(account, ShouldEqual, first)
+			})
+		})
+	})
+}
@@ -0,0 +1,139 @@
+package friendbot
+
+import (
+	"errors"
+	"time"
+
+	"bitbucket.org/atticlab/go-smart-base/keypair"
+	"bitbucket.org/atticlab/go-smart-base/strkey"
+	"bitbucket.org/atticlab/horizon/leader"
+	"bitbucket.org/atticlab/horizon/log"
+	"bitbucket.org/atticlab/horizon/txsub/results"
+	"golang.org/x/net/context"
+)
+
+// leaseRetryInterval is a pause before funding accounts are tried again, when
+// all of them are used by other replicas
+const leaseRetryInterval = 100 * time.Millisecond
+
+// fundingAccount is an account friendbot sends transactions from
+type fundingAccount struct {
+	secret  string
+	address string
+	// sequence of the last transaction, loaded from stellar-core if zero
+	sequence uint64
+	// lease of the account shared with other replicas, nil if not shared
+	lease leader.Lease
+}
+
+// Pool holds funding accounts of friendbot. Account is held by one request
+// until its transaction is applied, so concurrent requests are sent from
+// different accounts and never race for the same sequence. Replicas sharing
+// funding accounts must hold their leases, see Share.
+type Pool struct {
+	accounts chan *fundingAccount
+	holder   string
+	leaseTTL time.Duration
+	log      *log.Entry
+}
+
+// NewPool creates pool of funding accounts from their seeds
+func NewPool(secrets []string) (*Pool, error) {
+	if len(secrets) == 0 {
+		return nil, errors.New("no funding accounts")
+	}
+
+	pool := &Pool{
+		accounts: make(chan *fundingAccount, len(secrets)),
+		log:      log.WithField("service", "friendbot"),
+	}
+
+	for _, secret := range secrets {
+		_, err := strkey.Decode(strkey.VersionByteSeed, secret)
+		if err != nil {
+			return nil, err
+		}
+
+		pool.accounts <- &fundingAccount{
+			secret:  secret,
+			address: keypair.MustParse(secret).Address(),
+		}
+	}
+
+	return pool, nil
+}
+
+// Size returns number of funding accounts in the pool
+func (pool *Pool) Size() int {
+	return cap(pool.accounts)
+}
+
+// Share makes the pool use funding account only while this replica holds its
+// lease, created by newLease. Lease expires after ttl, if replica fails to
+// release it. Must be called before the pool is used.
+func (pool *Pool) Share(holder string, ttl time.Duration, newLease func(address string) leader.Lease) {
+	pool.holder = holder
+	pool.leaseTTL = ttl
+	for i := 0; i < pool.Size(); i++ {
+		account := <-pool.accounts
+		account.lease = newLease(account.address)
+		pool.accounts <- account
+	}
+}
+
+// acquire waits for free funding account, which is not used by other replicas
+func (pool *Pool) acquire(ctx context.Context) (*fundingAccount, error) {
+	for tries := 1; ; tries++ {
+		var account *fundingAccount
+		select {
+		case account = <-pool.accounts:
+		case <-ctx.Done():
+			return nil, results.ErrCanceled
+		}
+
+		ok, err := pool.lease(account)
+		if err != nil {
+			pool.accounts <- account
+			return nil, err
+		}
+		if ok {
+			return account, nil
+		}
+		pool.accounts <- account
+
+		if tries%pool.Size() != 0 {
+			continue
+		}
+		select {
+		case <-time.After(leaseRetryInterval):
+		case <-ctx.Done():
+			return nil, results.ErrCanceled
+		}
+	}
+}
+
+// lease returns false if account is used by another replica. Sequence of the
+// account is reloaded, as another replica might have used it.
+func (pool *Pool) lease(account *fundingAccount) (bool, error) {
+	if account.lease == nil {
+		return true, nil
+	}
+
+	holder, err := account.lease.Acquire(pool.holder, pool.leaseTTL)
+	if err != nil || holder != pool.holder {
+		return false, err
+	}
+	account.sequence = 0
+	return true, nil
+}
+
+// release returns funding account to the pool
+func (pool *Pool) release(account *fundingAccount) {
+	if account.lease != nil {
+		err := account.lease.Release(pool.holder)
+		if err != nil {
+			pool.log.WithField("account", account.address).WithError(err).Error("Failed to release funding account lease")
+		}
+	}
+	pool.accounts <- account
+}
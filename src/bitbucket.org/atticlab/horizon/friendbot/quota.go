@@ -0,0 +1,93 @@
+package friendbot
+
+import (
+	"sync"
+	"time"
+)
+
+// Quota limits number of requests made by a key (IP or address) during a
+// fixed window
+type Quota interface {
+	// Take counts request of the key. Returns false if the key exceeded the
+	// quota of the current window, rejected requests are not counted.
+	Take(key string) (bool, error)
+	// Return gives back request of the key taken in the current window, e.g.
+	// when the request is rejected by another quota
+	Return(key string) error
+}
+
+// MemoryQuota is a Quota stored in memory of the process. Used if horizon is
+// not shared with other replicas or redis is not configured.
+type MemoryQuota struct {
+	limit  int
+	window time.Duration
+	now    func() time.Time
+
+	lock      sync.Mutex
+	windows   map[string]*quotaWindow
+	lastSweep time.Time
+}
+
+type quotaWindow struct {
+	start time.Time
+	count int
+}
+
+// NewMemoryQuota creates quota allowing limit requests per window
+func NewMemoryQuota(limit int, window time.Duration) *MemoryQuota {
+	return &MemoryQuota{
+		limit:   limit,
+		window:  window,
+		now:     time.Now,
+		windows: make(map[string]*quotaWindow),
+	}
+}
+
+// Take implements Quota
+func (q *MemoryQuota) Take(key string) (bool, error) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	now := q.now()
+	q.sweep(now)
+
+	w, ok := q.windows[key]
+	if !ok || !now.Before(w.start.Add(q.window)) {
+		w = &quotaWindow{start: now}
+		q.windows[key] = w
+	}
+
+	if w.count >= q.limit {
+		return false, nil
+	}
+
+	w.count++
+	return true, nil
+}
+
+// Return implements Quota
+func (q *MemoryQuota) Return(key string) error {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	w, ok := q.windows[key]
+	if ok && w.count > 0 && q.now().Before(w.start.Add(q.window)) {
+		w.count--
+	}
+	return nil
+}
+
+// sweep removes expired windows once per window, so keys seen once do not
+// grow the map forever
+func (q *MemoryQuota) sweep(now time.Time) {
+	if now.Before(q.lastSweep.Add(q.window)) {
+		return
+	}
+
+	for key, w := range q.windows {
+		if !now.Before(w.start.Add(q.window)) {
+			delete(q.windows, key)
+		}
+	}
+	q.lastSweep = now
+}
@@ -0,0 +1,213 @@
+package horizon
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	hredis "bitbucket.org/atticlab/horizon/redis"
+)
+
+// statuses of health checks
+const (
+	healthStatusOK       = "ok"
+	healthStatusFailing  = "failing"
+	healthStatusDisabled = "disabled"
+	// dependency is failing, but instance serves requests without it
+	healthStatusDegraded = "degraded"
+)
+
+const defaultHealthCheckTimeout = 2 * time.Second
+
+var errHealthCheckTimeout = errors.New("check timed out")
+
+var errDraining = errors.New("instance is shutting down")
+
+// healthCheck is a result of the check of single dependency
+type healthCheck struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ingestionCheck is a result of the check of ingestion lag
+type ingestionCheck struct {
+	healthCheck
+	HorizonSequence     int32 `json:"horizon_latest_ledger"`
+	StellarCoreSequence int32 `json:"core_latest_ledger"`
+	Lag                 int32 `json:"lag"`
+	MaxLag              int32 `json:"max_lag"`
+}
+
+// readinessReport is a result of readiness checks. Instance is ready, if none
+// of the checks are failing. Degraded instance is ready.
+type readinessReport struct {
+	Status      string         `json:"status"`
+	Draining    healthCheck    `json:"draining"`
+	CoreDB      healthCheck    `json:"core_db"`
+	HistoryDB   healthCheck    `json:"history_db"`
+	Redis       healthCheck    `json:"redis"`
+	StellarCore healthCheck    `json:"stellar_core"`
+	Ingestion   ingestionCheck `json:"ingestion"`
+}
+
+func (r *readinessReport) checks() []healthCheck {
+	return []healthCheck{r.Draining, r.CoreDB, r.HistoryDB, r.Redis, r.StellarCore, r.Ingestion.healthCheck}
+}
+
+// Ready returns true if none of the checks are failing
+func (r *readinessReport) Ready() bool {
+	return !r.hasStatus(healthStatusFailing)
+}
+
+func (r *readinessReport) hasStatus(status string) bool {
+	for _, check := range r.checks() {
+		if check.Status == status {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckReadiness checks dependencies of the instance concurrently, each check
+// is limited by configured timeout
+func (a *App) CheckReadiness() readinessReport {
+	timeout := a.config.Health.CheckTimeout
+	if timeout <= 0 {
+		timeout = defaultHealthCheckTimeout
+	}
+
+	var report readinessReport
+	report.Draining = newDrainingCheck(a.IsDraining())
+
+	var wg sync.WaitGroup
+	run := func(result *healthCheck, check func() error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			*result = newHealthCheck(withTimeout(timeout, check))
+		}()
+	}
+
+	run(&report.CoreDB, func() error {
+		return a.coreQ.Repo.DB.Ping()
+	})
+	run(&report.HistoryDB, func() error {
+		return a.historyQ.Repo.DB.Ping()
+	})
+
+	if a.redis == nil {
+		report.Redis = healthCheck{Status: healthStatusDisabled}
+	} else {
+		run(&report.Redis, func() error {
+			_, err := hredis.CheckHealth()
+			return err
+		})
+	}
+
+	if a.config.StellarCoreURL == "" {
+		report.StellarCore = healthCheck{Status: healthStatusDisabled}
+	} else {
+		run(&report.StellarCore, func() error {
+			return checkStellarCore(a.config.StellarCoreURL, timeout)
+		})
+	}
+
+	// ledger state is refreshed by UpdateLedgerState on each pump tick
+	report.Ingestion = newIngestionCheck(
+		a.latestLedgerState.Horizon,
+		a.latestLedgerState.Core,
+		a.config.Health.MaxIngestionLag,
+	)
+
+	wg.Wait()
+
+	// statistics and txsub state are kept in postgres while redis is unavailable
+	if a.config.Redis.DegradeToPostgres {
+		report.Redis = degradeHealthCheck(report.Redis)
+	}
+
+	report.Status = healthStatusOK
+	switch {
+	case !report.Ready():
+		report.Status = healthStatusFailing
+	case report.hasStatus(healthStatusDegraded):
+		report.Status = healthStatusDegraded
+	}
+	return report
+}
+
+// degradeHealthCheck reports failing check of optional dependency as degraded
+func degradeHealthCheck(check healthCheck) healthCheck {
+	if check.Status == healthStatusFailing {
+		check.Status = healthStatusDegraded
+	}
+	return check
+}
+
+// newDrainingCheck fails while instance is shutting down
+func newDrainingCheck(draining bool) healthCheck {
+	if draining {
+		return newHealthCheck(errDraining)
+	}
+	return healthCheck{Status: healthStatusOK}
+}
+
+func newHealthCheck(err error) healthCheck {
+	if err != nil {
+		return healthCheck{Status: healthStatusFailing, Error: err.Error()}
+	}
+	return healthCheck{Status: healthStatusOK}
+}
+
+// newIngestionCheck compares latest ledger of history with stellar-core. Lag
+// is not checked if maxLag is zero.
+func newIngestionCheck(horizonSeq, coreSeq, maxLag int32) ingestionCheck {
+	result := ingestionCheck{
+		HorizonSequence:     horizonSeq,
+		StellarCoreSequence: coreSeq,
+		Lag:                 coreSeq - horizonSeq,
+		MaxLag:              maxLag,
+	}
+
+	switch {
+	case maxLag == 0:
+		result.Status = healthStatusDisabled
+	case result.Lag > maxLag:
+		result.healthCheck = newHealthCheck(fmt.Errorf("history is %d ledgers behind stellar-core", result.Lag))
+	default:
+		result.Status = healthStatusOK
+	}
+	return result
+}
+
+// withTimeout runs check and returns error if it does not finish in time. Check
+// keeps running in background after timeout.
+func withTimeout(timeout time.Duration, check func() error) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- check()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(timeout):
+		return errHealthCheckTimeout
+	}
+}
+
+func checkStellarCore(url string, timeout time.Duration) error {
+	client := http.Client{Timeout: timeout}
+	resp, err := client.Get(fmt.Sprint(url, "/info"))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("stellar-core responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
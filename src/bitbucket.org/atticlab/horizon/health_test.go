@@ -0,0 +1,70 @@
+package horizon
+
+import (
+	"testing"
+
+	"bitbucket.org/atticlab/horizon/test"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestReadiness(t *testing.T) {
+	Convey("Ingestion lag", t, func() {
+		check := newIngestionCheck(90, 100, 10)
+		So(check.Status, ShouldEqual, healthStatusOK)
+		So(check.Lag, ShouldEqual, 10)
+
+		check = newIngestionCheck(89, 100, 10)
+		So(check.Status, ShouldEqual, healthStatusFailing)
+		So(check.Error, ShouldNotBeEmpty)
+
+		check = newIngestionCheck(1, 100, 0)
+		So(check.Status, ShouldEqual, healthStatusDisabled)
+	})
+
+	Convey("Report", t, func() {
+		ok := healthCheck{Status: healthStatusOK}
+		report := readinessReport{
+			Draining:    newDrainingCheck(false),
+			CoreDB:      ok,
+			HistoryDB:   ok,
+			Redis:       healthCheck{Status: healthStatusDisabled},
+			StellarCore: ok,
+			Ingestion:   newIngestionCheck(100, 100, 10),
+		}
+		So(report.Ready(), ShouldBeTrue)
+
+		Convey("Degraded redis does not fail readiness", func() {
+			report.Redis = degradeHealthCheck(newHealthCheck(errHealthCheckTimeout))
+			So(report.Redis.Status, ShouldEqual, healthStatusDegraded)
+			So(report.Redis.Error, ShouldNotBeEmpty)
+			So(report.Ready(), ShouldBeTrue)
+			So(report.hasStatus(healthStatusDegraded), ShouldBeTrue)
+		})
+
+		Convey("Draining instance is not ready", func() {
+			report.Draining = newDrainingCheck(true)
+			So(report.Draining.Status, ShouldEqual, healthStatusFailing)
+			So(report.Ready(), ShouldBeFalse)
+		})
+
+		report.HistoryDB = newHealthCheck(errHealthCheckTimeout)
+		So(report.Ready(), ShouldBeFalse)
+	})
+}
+
+func TestReadinessDraining(t *testing.T) {
+	test.LoadScenario("base")
+	app := NewTestApp()
+	defer app.Close()
+	rh := NewRequestHelper(app)
+
+	Convey("Readiness of draining instance", t, func() {
+		w := rh.Get("/ready", test.RequestHelperNoop)
+		So(w.Body.String(), ShouldContainSubstring, `"draining":{"status":"ok"}`)
+
+		app.draining = 1
+		w = rh.Get("/ready", test.RequestHelperNoop)
+		So(w.Code, ShouldEqual, 503)
+		So(w.Body.String(), ShouldContainSubstring, errDraining.Error())
+	})
+}
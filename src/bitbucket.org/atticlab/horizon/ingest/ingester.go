@@ -1,6 +1,8 @@
 package ingest
 
 import (
+	"time"
+
 	"bitbucket.org/atticlab/horizon/db2/core"
 	"bitbucket.org/atticlab/horizon/db2/history"
 	"bitbucket.org/atticlab/horizon/errors"
@@ -14,6 +16,31 @@ func (i *System) Close() {
 	i.tick.Stop()
 }
 
+// Shutdown stops polling and waits for the running session to flush the
+// ledger being ingested. Returns false if session did not finish in time.
+func (i *System) Shutdown(timeout time.Duration) bool {
+	i.Close()
+	i.stopOnce.Do(func() {
+		close(i.stop)
+	})
+
+	finished := make(chan struct{})
+	go func() {
+		i.running.Lock()
+		i.running.Unlock()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+		log.Info("ingestion stopped")
+		return true
+	case <-time.After(timeout):
+		log.Warn("ingestion did not stop in time")
+		return false
+	}
+}
+
 // ReingestAll re-ingests all ledgers
 func (i *System) ReingestAll() (int, error) {
 	err := i.updateLedgerState()
@@ -98,6 +125,7 @@ func (i *System) ReingestRange(start, end int32) (int, error) {
 		CurrentVersion,
 	)
 	is.ClearExisting = true
+	is.Genesis = i.Genesis
 	err := is.Run()
 	return is.Ingested, err
 }
@@ -115,15 +143,22 @@ func (i *System) Start() {
 }
 
 func (i *System) run() {
-	for _ = range i.tick.C {
-		log.Debug("ticking ingester")
-		i.runOnce()
+	for {
+		select {
+		case <-i.tick.C:
+			log.Debug("ticking ingester")
+			i.runOnce()
+		case <-i.stop:
+			return
+		}
 	}
 }
 
 // run causes the importer to check stellar-core to see if we can import new
 // data.
 func (i *System) runOnce() {
+	i.running.Lock()
+	defer i.running.Unlock()
 
 	defer func() {
 		if rec := recover(); rec != nil {
@@ -133,10 +168,19 @@ func (i *System) runOnce() {
 		}
 	}()
 
+	// ingestion must stop on shutdown or when another replica becomes the leader
+	stop, done := i.sessionStop()
+	defer close(done)
+
 	// 1. find the latest ledger
 	// 2. if any available, import until none available
 	// 3. if any were imported, go to 1
 	for {
+		// stop is closed asynchronously, so leadership is checked directly too
+		if isClosed(stop) || !i.isLeader() {
+			return
+		}
+
 		// 1.
 		err := i.updateLedgerState()
 
@@ -158,6 +202,13 @@ func (i *System) runOnce() {
 			i.Metrics,
 			CurrentVersion,
 		)
+		is.Compliance = i.Compliance
+		is.Options = i.Options
+		is.Genesis = i.Genesis
+		is.Stop = stop
+		if i.Leadership != nil {
+			is.Fence = i.Leadership.Fence
+		}
 
 		err = is.Run()
 
@@ -174,6 +225,41 @@ func (i *System) runOnce() {
 
 }
 
+// sessionStop returns a channel, which is closed on shutdown or when this
+// replica loses leadership. Done must be closed when ingestion finishes.
+func (i *System) sessionStop() (stop <-chan struct{}, done chan struct{}) {
+	done = make(chan struct{})
+	if i.Leadership == nil {
+		return i.stop, done
+	}
+
+	lost := i.Leadership.Lost()
+	result := make(chan struct{})
+	go func() {
+		select {
+		case <-i.stop:
+		case <-lost:
+		case <-done:
+		}
+		close(result)
+	}()
+	return result, done
+}
+
+// isLeader returns true if ingestion is not restricted or this replica is the leader
+func (i *System) isLeader() bool {
+	return i.Leadership == nil || !isClosed(i.Leadership.Lost())
+}
+
+func isClosed(c <-chan struct{}) bool {
+	select {
+	case <-c:
+		return true
+	default:
+		return false
+	}
+}
+
 func (i *System) updateLedgerState() error {
 	cq := &core.Q{Repo: i.CoreDB}
 	hq := &history.Q{Repo: i.HorizonDB}
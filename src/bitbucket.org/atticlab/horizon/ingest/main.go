@@ -4,11 +4,16 @@
 package ingest
 
 import (
+	"sync"
 	"time"
 
 	"bitbucket.org/atticlab/horizon/cache"
+	"bitbucket.org/atticlab/horizon/compliance"
+	"bitbucket.org/atticlab/horizon/config"
 	"bitbucket.org/atticlab/horizon/db2"
+	"bitbucket.org/atticlab/horizon/db2/history"
 	"bitbucket.org/atticlab/horizon/ingest/session"
+	"bitbucket.org/atticlab/horizon/options"
 )
 
 const (
@@ -23,6 +28,16 @@ const (
 	CurrentVersion = 8
 )
 
+// Leadership reports whether this replica is elected to ingest
+type Leadership interface {
+	// Lost returns a channel, which is closed when this replica stops being the
+	// leader. Channel is already closed if replica is not the leader.
+	Lost() <-chan struct{}
+	// Fence returns error, unless this replica still holds the leadership.
+	// Called within transaction of the ingested ledger before it's committed.
+	Fence(q *history.Q) error
+}
+
 // System represents the data ingestion subsystem of horizon.
 type System struct {
 	// HorizonDB is the connection to the horizon database that ingested data will
@@ -39,7 +54,28 @@ type System struct {
 	// Network is the passphrase for the network being imported
 	Network string
 
-	tick            *time.Ticker
+	// Compliance checks newly ingested payments for suspicious activity. Not
+	// applied on reingestion. Disabled if nil
+	Compliance *compliance.Engine
+
+	// Options is notified when ingested admin operations change system options.
+	// Disabled if nil
+	Options *options.Registry
+
+	// Genesis lists system accounts and assets stored on ingestion of the
+	// first ledger. Nothing is stored if nil
+	Genesis *config.Genesis
+
+	// Leadership restricts ingestion to the replica elected among several with
+	// ingestion enabled. Not restricted if nil
+	Leadership Leadership
+
+	tick *time.Ticker
+	// closed on shutdown to end the running session after the current ledger
+	stop     chan struct{}
+	stopOnce sync.Once
+	// held while ingestion session is running
+	running         sync.Mutex
 	historySequence int32
 	coreSequence    int32
 }
@@ -54,6 +90,7 @@ func New(network string, core, horizon *db2.Repo, historyAccountCache *cache.His
 		HistoryAccountCache: historyAccountCache,
 		Metrics:             session.NewMetrics(),
 		tick:                time.NewTicker(1 * time.Second),
+		stop:                make(chan struct{}),
 	}
 }
 
@@ -0,0 +1,50 @@
+package session
+
+import (
+	"time"
+
+	"bitbucket.org/atticlab/go-smart-base/xdr"
+	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/log"
+)
+
+// ingestSupplyChange stores amount of the asset issued by payment from its
+// issuer or redeemed by payment to the issuer. Empty from or to means that
+// side of the payment is not in the asset, e.g. destination of path payment
+// receives another asset.
+func (is *Session) ingestSupplyChange(asset xdr.Asset, from, to string, amount xdr.Int64) error {
+	var assetType xdr.AssetType
+	var code, issuer string
+	err := asset.Extract(&assetType, &code, &issuer)
+	if err != nil {
+		return err
+	}
+
+	if assetType == xdr.AssetTypeAssetTypeNative {
+		return nil
+	}
+
+	change := history.AssetSupplyChange{
+		OperationID: is.Cursor.OperationID(),
+		AssetCode:   code,
+		AssetIssuer: issuer,
+		ClosedAt:    time.Unix(is.Cursor.Ledger().CloseTime, 0).UTC(),
+	}
+
+	switch {
+	case from == issuer && to == issuer:
+		return nil
+	case from == issuer:
+		change.Issued = int64(amount)
+	case to == issuer:
+		change.Redeemed = int64(amount)
+	default:
+		return nil
+	}
+
+	err = is.Ingestion.HistoryQ().AssetSupplyChangeInsert(&change)
+	if err != nil {
+		log.WithField("service", "asset_supply_ingester").WithError(err).Error("Failed to store asset supply change")
+	}
+	return err
+}
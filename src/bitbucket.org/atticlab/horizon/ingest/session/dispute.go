@@ -0,0 +1,94 @@
+package session
+
+import (
+	"fmt"
+
+	"bitbucket.org/atticlab/go-smart-base/amount"
+	"bitbucket.org/atticlab/go-smart-base/xdr"
+	"bitbucket.org/atticlab/horizon/admin"
+	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/db2/history/details"
+	"bitbucket.org/atticlab/horizon/log"
+	"github.com/guregu/null"
+)
+
+// ingestDisputeResolution links reversal or refund of the payment to the active
+// dispute of the payment, if any. Once disputed amount is returned in full, closes
+// the dispute and unlocks respondent's funds. Must be called after the returned
+// amount is stored.
+func (is *Session) ingestDisputeResolution(payment *history.Operation) error {
+	logger := log.WithField("service", "dispute_ingester").WithField("payment_id", payment.ID)
+
+	historyQ := is.Ingestion.HistoryQ()
+	dispute, err := historyQ.ActiveDisputeByPayment(payment.ID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get active dispute")
+		return err
+	}
+
+	if dispute == nil {
+		return nil
+	}
+
+	var paymentDetails details.Payment
+	err = payment.UnmarshalDetails(&paymentDetails)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get disputed payment details")
+		return err
+	}
+
+	paymentAmount, err := amount.Parse(paymentDetails.Amount)
+	if err != nil {
+		logger.WithError(err).Error("Failed to parse disputed payment amount")
+		return err
+	}
+
+	returned, err := historyQ.PaymentRefundedAmount(payment.ID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get returned amount of the payment")
+		return err
+	}
+
+	// dispute amount is the part of the payment not returned before the dispute was opened
+	returnedSinceOpen := returned - (int64(paymentAmount) - dispute.Amount)
+	if returnedSinceOpen < dispute.Amount {
+		err = historyQ.DisputeNoteInsert(&history.DisputeNote{
+			DisputeID: dispute.ID,
+			State:     dispute.State,
+			Note: fmt.Sprintf("Payment partially returned by operation %d, %s of %s returned", is.Cursor.OperationID(),
+				amount.String(xdr.Int64(returnedSinceOpen)), amount.String(xdr.Int64(dispute.Amount))),
+		})
+		if err != nil {
+			logger.WithError(err).Error("Failed to store dispute note")
+			return err
+		}
+		return nil
+	}
+
+	dispute.State = history.DisputeStateReversed
+	dispute.ResolutionOperationID = null.IntFrom(is.Cursor.OperationID())
+	_, err = historyQ.DisputeUpdate(dispute)
+	if err != nil {
+		logger.WithError(err).Error("Failed to update dispute")
+		return err
+	}
+
+	note := fmt.Sprintf("Payment returned by operation %d", is.Cursor.OperationID())
+	err = historyQ.DisputeNoteInsert(&history.DisputeNote{
+		DisputeID: dispute.ID,
+		State:     dispute.State,
+		Note:      note,
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to store dispute note")
+		return err
+	}
+
+	err = admin.UnlockDisputedFunds(historyQ, dispute, note, dispute.ResolutionOperationID, is.closedAt())
+	if err != nil {
+		logger.WithError(err).Error("Failed to unlock disputed funds")
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package session
+
+import (
+	"testing"
+
+	"bitbucket.org/atticlab/go-smart-base/keypair"
+	"bitbucket.org/atticlab/go-smart-base/xdr"
+	"bitbucket.org/atticlab/horizon/cache"
+	"bitbucket.org/atticlab/horizon/config"
+	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/ingest/session/ingestion"
+	"bitbucket.org/atticlab/horizon/test"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestIngestGenesis(t *testing.T) {
+	tt := test.Start(t).ScenarioWithoutHorizon("base")
+	defer tt.Finish()
+
+	Convey("ingestGenesis", t, func() {
+		bank, err := keypair.Random()
+		So(err, ShouldBeNil)
+		issuer, err := keypair.Random()
+		So(err, ShouldBeNil)
+
+		q := &history.Q{Repo: tt.HorizonRepo()}
+		is := &Session{
+			Ingestion: ingestion.New(tt.HorizonRepo(), cache.NewHistoryAccount(q), 1),
+			Genesis: &config.Genesis{
+				Accounts: []config.GenesisAccount{
+					{Address: bank.Address(), Type: xdr.AccountTypeAccountBank},
+					{Address: issuer.Address(), Type: xdr.AccountTypeAccountGeneralAgent},
+					// duplicates are stored once
+					{Address: bank.Address(), Type: xdr.AccountTypeAccountBank},
+				},
+				Assets: []config.GenesisAsset{
+					{Code: "EUAH", Issuer: bank.Address(), IsAnonymous: true},
+					{Code: "EUAH", Issuer: issuer.Address()},
+				},
+			},
+		}
+
+		So(is.Ingestion.Start(), ShouldBeNil)
+		So(is.ingestGenesis(), ShouldBeNil)
+		So(is.Ingestion.Close(), ShouldBeNil)
+
+		var account history.Account
+		So(q.AccountByAddress(&account, bank.Address()), ShouldBeNil)
+		So(account.AccountType, ShouldEqual, xdr.AccountTypeAccountBank)
+		So(q.AccountByAddress(&account, issuer.Address()), ShouldBeNil)
+		So(account.AccountType, ShouldEqual, xdr.AccountTypeAccountGeneralAgent)
+
+		var asset history.Asset
+		assetType := int(xdr.AssetTypeAssetTypeCreditAlphanum4)
+		So(q.AssetByParams(&asset, assetType, "EUAH", bank.Address()), ShouldBeNil)
+		So(asset.IsAnonymous, ShouldBeTrue)
+		So(asset.Status, ShouldEqual, history.AssetStatusActive)
+		So(q.AssetByParams(&asset, assetType, "EUAH", issuer.Address()), ShouldBeNil)
+		So(asset.IsAnonymous, ShouldBeFalse)
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"bitbucket.org/atticlab/horizon/db2/core"
 	"bitbucket.org/atticlab/horizon/db2/history"
 	"bitbucket.org/atticlab/horizon/db2/sqx"
+	"bitbucket.org/atticlab/horizon/toid"
 	"github.com/guregu/null"
 	sq "github.com/lann/squirrel"
 	"database/sql"
@@ -176,6 +177,18 @@ func (ingest *Ingestion) clearRange(start int64, end int64, table string, idCol
 	return err
 }
 
+// clearComplianceAlerts removes alerts raised on ledgers of the range, they are
+// raised again when the ledgers are reingested
+func (ingest *Ingestion) clearComplianceAlerts(start int64, end int64) error {
+	del := sq.Delete("compliance_alerts").Where(
+		"ledger_sequence >= ? AND ledger_sequence < ?",
+		toid.Parse(start).LedgerSequence,
+		toid.Parse(end).LedgerSequence,
+	)
+	_, err := ingest.DB.Exec(del)
+	return err
+}
+
 func (ingest *Ingestion) commit() error {
 	err := ingest.DB.Commit()
 	if err != nil {
@@ -77,7 +77,11 @@ func (ingest *Ingestion) Clear(start int64, end int64) error {
 		ingest.DB.Exec(del)
 	}
 
-	err := ingest.clearRange(start, end, "history_effects", "history_operation_id")
+	err := ingest.clearComplianceAlerts(start, end)
+	if err != nil {
+		return err
+	}
+	err = ingest.clearRange(start, end, "history_effects", "history_operation_id")
 	if err != nil {
 		return err
 	}
@@ -85,6 +89,14 @@ func (ingest *Ingestion) Clear(start int64, end int64) error {
 	if err != nil {
 		return err
 	}
+	err = ingest.clearRange(start, end, "payment_refunds", "id")
+	if err != nil {
+		return err
+	}
+	err = ingest.clearRange(start, end, "asset_supply_changes", "operation_id")
+	if err != nil {
+		return err
+	}
 	err = ingest.clearRange(start, end, "history_operations", "id")
 	if err != nil {
 		return err
@@ -118,6 +130,12 @@ func (ingest *Ingestion) Close() error {
 	return ingest.commit()
 }
 
+// Write writes the currently buffered rows to the db within the current
+// transaction, so they can be queried before the transaction is committed.
+func (ingest *Ingestion) Write() error {
+	return ingest.flushInserters()
+}
+
 // Flush writes the currently buffered rows to the db, and if successful
 // starts a new transaction.
 func (ingest *Ingestion) Flush() error {
@@ -0,0 +1,40 @@
+package ingestion
+
+import (
+	"testing"
+
+	"bitbucket.org/atticlab/horizon/cache"
+	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/test"
+	"bitbucket.org/atticlab/horizon/toid"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestIngestionClear(t *testing.T) {
+	tt := test.Start(t).ScenarioWithoutHorizon("base")
+	defer tt.Finish()
+
+	Convey("Clear removes compliance alerts of the range", t, func() {
+		q := &history.Q{Repo: tt.HorizonRepo()}
+		for _, seq := range []int32{2, 3} {
+			alert := history.ComplianceAlert{
+				Rule:           "volume_spike",
+				Address:        "GAWIB7ETYGSWULO4VB7D6S42YLPGIC7TY7Y2SSJKVOTMQXV5TILYWBUA",
+				AssetCode:      "EUAH",
+				LedgerSequence: seq,
+			}
+			So(alert.SetOperations(nil), ShouldBeNil)
+			So(q.ComplianceAlertInsert(&alert), ShouldBeNil)
+		}
+
+		ingestion := New(tt.HorizonRepo(), cache.NewHistoryAccount(q), 1)
+		So(ingestion.Start(), ShouldBeNil)
+		So(ingestion.Clear(toid.New(2, 0, 0).ToInt64(), toid.New(3, 0, 0).ToInt64()), ShouldBeNil)
+		So(ingestion.Close(), ShouldBeNil)
+
+		var alerts []history.ComplianceAlert
+		So(q.ComplianceAlerts().Select(&alerts), ShouldBeNil)
+		So(len(alerts), ShouldEqual, 1)
+		So(alerts[0].LedgerSequence, ShouldEqual, 3)
+	})
+}
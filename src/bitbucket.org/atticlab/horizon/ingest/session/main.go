@@ -2,8 +2,12 @@ package session
 
 import (
 	"bitbucket.org/atticlab/horizon/cache"
+	"bitbucket.org/atticlab/horizon/compliance"
+	"bitbucket.org/atticlab/horizon/config"
 	"bitbucket.org/atticlab/horizon/db2"
+	"bitbucket.org/atticlab/horizon/db2/history"
 	"bitbucket.org/atticlab/horizon/ingest/session/ingestion"
+	"bitbucket.org/atticlab/horizon/options"
 )
 
 // Session represents a single attempt at ingesting data into the history
@@ -19,6 +23,27 @@ type Session struct {
 	// Metrics is a reference to where the session should record its metric information
 	Metrics *IngesterMetrics
 
+	// Compliance checks ingested payments for suspicious activity. Disabled if nil
+	Compliance *compliance.Engine
+
+	// Options is notified after admin operations changing system options are
+	// committed. Disabled if nil
+	Options *options.Registry
+
+	// Genesis lists system accounts and assets stored on ingestion of the
+	// first ledger. Nothing is stored if nil
+	Genesis *config.Genesis
+
+	// Stop ends the session after the ledger being ingested is flushed, when
+	// closed. Session runs until the last ledger if nil
+	Stop <-chan struct{}
+
+	// Fence is called within transaction of each ledger before it's
+	// committed. Ledger is rolled back and session fails, if it returns error,
+	// so replica, which lost leadership, does not store ledgers. Not called if
+	// nil
+	Fence func(q *history.Q) error
+
 	//
 	// Results fields
 	//
@@ -26,6 +51,13 @@ type Session struct {
 	// Ingested is the number of ledgers that were successfully ingested during
 	// this session.
 	Ingested int
+
+	horizonDB *db2.Repo
+	// payments of the ledger being ingested, checked by compliance engine
+	payments []compliance.Payment
+	// true if admin operations of the ledger being ingested changed system
+	// options or assets cached by horizon instances
+	optionsChanged bool
 }
 
 // NewSession initialize a new ingestion session, from `first` to `last`
@@ -36,5 +68,6 @@ func NewSession(first, last int32, horizonDB *db2.Repo, coreDB *db2.Repo, histor
 		Ingestion: ingestion.New(hdb, historyAccountCache, currentVersion),
 		Cursor:    NewCursor(coreDB, first, last, metrics.LoadLedgerTimer),
 		Metrics:   metrics,
+		horizonDB: horizonDB,
 	}
 }
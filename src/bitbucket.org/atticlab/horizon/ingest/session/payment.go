@@ -2,6 +2,7 @@ package session
 
 import (
 	"bitbucket.org/atticlab/go-smart-base/xdr"
+	"bitbucket.org/atticlab/horizon/compliance"
 	"bitbucket.org/atticlab/horizon/db2/history"
 	"bitbucket.org/atticlab/horizon/log"
 	"database/sql"
@@ -33,6 +34,20 @@ func (is *Session) ingestPayment(sourceAddress, destAddress string, sourceAmount
 		}
 	}
 
+	if is.Compliance != nil {
+		is.payments = append(is.payments, compliance.Payment{
+			OperationID:  is.Cursor.OperationID(),
+			From:         sourceAddress,
+			FromType:     sourceAccount.AccountType,
+			To:           destAddress,
+			ToType:       destAccount.AccountType,
+			SourceAsset:  sourceAsset,
+			SourceAmount: sourceAmount,
+			DestAsset:    destAsset,
+			DestAmount:   destAmount,
+		})
+	}
+
 	ledgerCloseTime := time.Unix(is.Cursor.Ledger().CloseTime, 0).Local()
 	now := time.Now()
 	err = is.Ingestion.UpdateStatistics(sourceAddress, sourceAsset, destAccount.AccountType, int64(sourceAmount), ledgerCloseTime, now, false)
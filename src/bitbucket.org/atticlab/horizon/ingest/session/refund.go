@@ -29,6 +29,22 @@ func (is *Session) ingestRefund(storedPaymentID int64, refundSourceAddress, paym
 		return err
 	}
 
+	err = is.Ingestion.HistoryQ().PaymentRefundInsert(&history.PaymentRefund{
+		ID:        is.Cursor.OperationID(),
+		PaymentID: storedPaymentID,
+		Type:      xdr.OperationTypeRefund,
+		Amount:    int64(amount),
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to store refund")
+		return err
+	}
+
+	err = is.ingestDisputeResolution(&storedOp)
+	if err != nil {
+		return err
+	}
+
 	now := time.Now()
 	err = is.Ingestion.UpdateStatistics(refundSource.Address, assetCode, paymentSource.AccountType, -int64(amount), storedOp.ClosedAt, now, true)
 	if err != nil {
@@ -8,13 +8,16 @@ import (
 	"bitbucket.org/atticlab/go-smart-base/amount"
 	"bitbucket.org/atticlab/go-smart-base/xdr"
 	"bitbucket.org/atticlab/horizon/admin"
+	"bitbucket.org/atticlab/horizon/cache"
+	"bitbucket.org/atticlab/horizon/compliance"
 	"bitbucket.org/atticlab/horizon/db2/history"
 	"bitbucket.org/atticlab/horizon/db2/history/details"
 	"bitbucket.org/atticlab/horizon/ingest/participants"
 	"bitbucket.org/atticlab/horizon/ingest/session/helpers"
 	"bitbucket.org/atticlab/horizon/log"
+	"bitbucket.org/atticlab/horizon/tracing"
 	"encoding/json"
-	"github.com/spf13/viper"
+	"golang.org/x/net/context"
 )
 
 // Run starts an attempt to ingest the range of ledgers specified in this
@@ -28,19 +31,13 @@ func (is *Session) Run() error {
 	defer is.Ingestion.Rollback()
 
 	for is.Cursor.NextLedger() {
-		err = is.clearLedger()
+		err = is.runLedger()
 		if err != nil {
 			return err
 		}
 
-		err = is.ingestLedger()
-		if err != nil {
-			return err
-		}
-
-		err = is.flush()
-		if err != nil {
-			return err
+		if is.stopped() {
+			break
 		}
 	}
 
@@ -50,6 +47,50 @@ func (is *Session) Run() error {
 
 }
 
+// stopped returns true if session must end before the next ledger
+func (is *Session) stopped() bool {
+	select {
+	case <-is.Stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// runLedger ingests the current ledger within its own trace span. Queries of
+// the ingestion are children of the span.
+func (is *Session) runLedger() (err error) {
+	ctx, span := tracing.Start(context.Background(), "ingest.ledger")
+	span.SetAttribute("ledger", is.Cursor.LedgerSequence())
+	parentCtx := is.Ingestion.DB.Ctx
+	is.Ingestion.DB.Ctx = ctx
+	defer func() {
+		is.Ingestion.DB.Ctx = parentCtx
+		span.SetError(err)
+		span.End()
+	}()
+
+	err = is.clearLedger()
+	if err != nil {
+		return err
+	}
+
+	err = is.ingestLedger()
+	if err != nil {
+		return err
+	}
+
+	err = is.flush()
+	if err != nil {
+		return err
+	}
+
+	is.raiseScreeningReviews()
+	is.expireFreezes()
+	is.publishOptions()
+	return nil
+}
+
 func (is *Session) clearLedger() error {
 	if !is.ClearExisting {
 		return nil
@@ -63,7 +104,113 @@ func (is *Session) clearLedger() error {
 	return err
 }
 
+// checkCompliance applies compliance rules to payments of the ingested ledger
+// and stores alerts within the ingestion transaction, so the ledger is not
+// committed without its alerts. Rows of the ledger must be written, as rules
+// load payments of the current window from history.
+func (is *Session) checkCompliance() error {
+	payments := is.payments
+	is.payments = nil
+	if is.Compliance == nil || len(payments) == 0 {
+		return nil
+	}
+
+	ledger := compliance.Ledger{
+		Sequence: is.Cursor.LedgerSequence(),
+		ClosedAt: time.Unix(is.Cursor.Ledger().CloseTime, 0).Local(),
+		Payments: payments,
+	}
+
+	q := &history.Q{Repo: is.Ingestion.DB}
+	alerts, err := is.Compliance.Evaluate(compliance.NewDBProvider(q), &ledger)
+	if err != nil {
+		return err
+	}
+
+	for i := range alerts {
+		err = q.ComplianceAlertInsert(&alerts[i])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// raiseScreeningReviews raises compliance alerts for operations of the ingested
+// ledger, which screening allowed on condition of review. Failures are logged
+// and do not stop ingestion.
+func (is *Session) raiseScreeningReviews() {
+	q := &history.Q{is.horizonDB}
+	raised, err := q.ScreeningReviewAlertsInsert(is.Cursor.LedgerSequence())
+	if err != nil {
+		log.WithField("ledger", is.Cursor.LedgerSequence()).WithError(err).Error("Failed to raise screening review alerts")
+		return
+	}
+
+	if raised > 0 {
+		log.WithField("ledger", is.Cursor.LedgerSequence()).WithField("alerts", raised).Info("Raised screening review alerts")
+	}
+}
+
+// expireFreezes records expiration of account freezes expired by close time of
+// the ingested ledger in accounts' traits history. Failures are logged and do
+// not stop ingestion, expired freezes are recorded on the next ledger.
+func (is *Session) expireFreezes() {
+	q := &history.Q{is.horizonDB}
+	expired, err := q.AccountFreezesExpire(is.closedAt())
+	if err != nil {
+		log.WithField("ledger", is.Cursor.LedgerSequence()).WithError(err).Error("Failed to record expired freezes")
+		return
+	}
+
+	if expired > 0 {
+		log.WithField("ledger", is.Cursor.LedgerSequence()).WithField("freezes", expired).Info("Recorded expired freezes")
+	}
+}
+
+// closedAt returns close time of the ingested ledger
+func (is *Session) closedAt() time.Time {
+	return time.Unix(is.Cursor.Ledger().CloseTime, 0).Local()
+}
+
+// publishOptions notifies horizon instances that system options or assets were
+// changed by the committed ledger
+func (is *Session) publishOptions() {
+	changed := is.optionsChanged
+	is.optionsChanged = false
+	if !changed {
+		return
+	}
+
+	if is.Options == nil {
+		// nobody to notify, still drop assets cached by this instance
+		cache.InvalidateHistoryAssets()
+		return
+	}
+
+	err := is.Options.Publish()
+	if err != nil {
+		log.WithField("ledger", is.Cursor.LedgerSequence()).WithError(err).Error("Failed to publish options")
+	}
+}
+
 func (is *Session) flush() error {
+	err := is.Ingestion.Write()
+	if err != nil {
+		return err
+	}
+
+	err = is.checkCompliance()
+	if err != nil {
+		return err
+	}
+
+	if is.Fence != nil {
+		err = is.Fence(&history.Q{Repo: is.Ingestion.DB})
+		if err != nil {
+			return err
+		}
+	}
 	return is.Ingestion.Flush()
 }
 
@@ -80,51 +227,67 @@ func (is *Session) ingestLedger() error {
 		return err
 	}
 
-	// If this is ledger 1, create the root account
+	// If this is ledger 1, store system accounts and assets
 	if is.Cursor.LedgerSequence() == 1 {
-		master := history.NewAccount(1, viper.GetString("bank-master-key"), xdr.AccountTypeAccountBank)
-		err = is.Ingestion.Account(master, false, nil, nil)
+		err = is.ingestGenesis()
 		if err != nil {
 			return err
 		}
+	}
 
-		general := history.NewAccount(2, viper.GetString("general-agent-key"), xdr.AccountTypeAccountGeneralAgent)
-		err = is.Ingestion.Account(general, false, nil, nil)
+	for is.Cursor.NextTx() {
+		err = is.ingestTransaction()
 		if err != nil {
 			return err
 		}
+	}
 
-		commission := history.NewAccount(3, viper.GetString("bank-commission-key"), xdr.AccountTypeAccountCommission)
-		if master.Address != commission.Address {
-			err = is.Ingestion.Account(commission, false, nil, nil)
-			if err != nil {
-				return err
-			}
-		}
-		storedAsset := history.Asset{
-			Type: int(xdr.AssetTypeAssetTypeCreditAlphanum4),
-			Code: "EUAH",
-			Issuer: viper.GetString("bank-master-key"),
-			IsAnonymous: true,
+	is.Ingested++
+	if is.Metrics != nil {
+		is.Metrics.IngestLedgerTimer.Update(time.Since(start))
+	}
+
+	return nil
+}
+
+// ingestGenesis stores system accounts and assets listed in genesis. Accounts
+// listed more than once (e.g. bank used as commission account) are stored once.
+func (is *Session) ingestGenesis() error {
+	if is.Genesis == nil {
+		return nil
+	}
+
+	stored := make(map[string]bool, len(is.Genesis.Accounts))
+	var id int64
+	for _, account := range is.Genesis.Accounts {
+		if stored[account.Address] {
+			continue
 		}
-		err = (&history.Q{is.Ingestion.DB}).InsertAsset(&storedAsset)
+		stored[account.Address] = true
+
+		id++
+		err := is.Ingestion.Account(history.NewAccount(id, account.Address, account.Type), false, nil, nil)
 		if err != nil {
 			return err
 		}
 	}
 
-	for is.Cursor.NextTx() {
-		err = is.ingestTransaction()
+	q := &history.Q{is.Ingestion.DB}
+	for _, asset := range is.Genesis.Assets {
+		storedAsset := history.Asset{
+			Type:        int(asset.AssetType()),
+			Code:        asset.Code,
+			Issuer:      asset.Issuer,
+			IsAnonymous: asset.IsAnonymous,
+			Decimals:    history.DefaultAssetDecimals,
+			Status:      history.AssetStatusActive,
+		}
+		err := q.InsertAsset(&storedAsset)
 		if err != nil {
 			return err
 		}
 	}
 
-	is.Ingested++
-	if is.Metrics != nil {
-		is.Metrics.IngestLedgerTimer.Update(time.Since(start))
-	}
-
 	return nil
 }
 
@@ -156,6 +319,11 @@ func (is *Session) ingestOperation() error {
 		if err != nil {
 			return err
 		}
+
+		err = is.ingestSupplyChange(op.Asset, from.Address(), to.Address(), op.Amount)
+		if err != nil {
+			return err
+		}
 	case xdr.OperationTypePathPayment:
 		op := is.Cursor.Operation().Body.MustPathPaymentOp()
 		from := is.Cursor.OperationSourceAccount()
@@ -178,6 +346,16 @@ func (is *Session) ingestOperation() error {
 			return err
 		}
 
+		err = is.ingestSupplyChange(op.SendAsset, from.Address(), "", sourceAmount)
+		if err != nil {
+			return err
+		}
+
+		err = is.ingestSupplyChange(op.DestAsset, "", to.Address(), destAmount)
+		if err != nil {
+			return err
+		}
+
 	case xdr.OperationTypeCreateAccount:
 		// Import the new account if one was created
 		op := is.Cursor.Operation().Body.MustCreateAccountOp()
@@ -198,7 +376,8 @@ func (is *Session) ingestOperation() error {
 			return err
 		}
 
-		adminActionProvider := admin.NewAdminActionProvider(&history.Q{is.Ingestion.DB})
+		adminActionProvider := admin.NewAdminActionProvider(&history.Q{is.Ingestion.DB}).
+			ForOperation(is.Cursor.OperationID(), is.closedAt())
 		adminAction, err := adminActionProvider.CreateNewParser(opData)
 		if err != nil {
 			return err
@@ -214,6 +393,7 @@ func (is *Session) ingestOperation() error {
 			logger.WithError(adminAction.GetError()).Error("Failed to apply admin action")
 			break
 		}
+		is.optionsChanged = is.optionsChanged || admin.ChangesOptions(adminAction) || admin.ChangesAssets(adminAction)
 	case xdr.OperationTypePaymentReversal:
 		// Update statistics for both accounts
 		op := is.Cursor.Operation().Body.MustPaymentReversalOp()
@@ -228,6 +408,11 @@ func (is *Session) ingestOperation() error {
 		if err != nil {
 			return err
 		}
+
+		err = is.ingestSupplyChange(op.Asset, reversalSource.Address(), paymentSource, op.Amount)
+		if err != nil {
+			return err
+		}
 	case xdr.OperationTypeRefund:
 		// Update statistics for both accounts
 		op := is.Cursor.Operation().Body.MustRefundOp()
@@ -242,6 +427,11 @@ func (is *Session) ingestOperation() error {
 		if err != nil {
 			return err
 		}
+
+		err = is.ingestSupplyChange(op.Asset, refundSource.Address(), paymentSource, op.Amount)
+		if err != nil {
+			return err
+		}
 	}
 
 	err = is.ingestOperationParticipants()
@@ -1,26 +1,65 @@
 package horizon
 
 import (
-	"bitbucket.org/atticlab/go-smart-base/strkey"
+	"time"
+
 	"bitbucket.org/atticlab/horizon/friendbot"
+	"bitbucket.org/atticlab/horizon/leader"
+	"bitbucket.org/atticlab/horizon/log"
+	"bitbucket.org/atticlab/horizon/redis"
 )
 
+// fundingLeaseTTL limits time funding account stays used by failed replica.
+// Must exceed time transaction takes to be applied.
+const fundingLeaseTTL = 2 * time.Minute
+
 func initFriendbot(app *App) {
-	if app.config.FriendbotSecret == "" {
+	secrets := app.config.Friendbot.Secrets(app.config.FriendbotSecret)
+	if len(secrets) == 0 {
 		return
 	}
 
-	// ensure its a seed if its not blank
-	strkey.MustDecode(strkey.VersionByteSeed, app.config.FriendbotSecret)
+	pool, err := friendbot.NewPool(secrets)
+	if err != nil {
+		log.WithField("service", "friendbot").WithError(err).Panic("Invalid funding account seed")
+	}
+
+	// replicas share funding accounts, so each one is used by one replica at a time
+	if app.redis != nil {
+		pool.Share(leader.DefaultID(), fundingLeaseTTL, func(address string) leader.Lease {
+			return redis.NewLeaderLease("friendbot:" + address)
+		})
+	}
 
 	app.friendbot = &friendbot.Bot{
-		Secret:    app.config.FriendbotSecret,
-		Submitter: app.submitter,
-		Network:   app.networkPassphrase,
+		Submitter:    app.submitter,
+		Network:      app.networkPassphrase,
+		Pool:         pool,
+		AccountTypes: app.config.Friendbot.AccountTypes,
+		Amount:       app.config.Friendbot.Amount,
+		IPQuota:      newFriendbotQuota(app, "ip", app.config.Friendbot.QuotaPerIP),
+		AddressQuota: newFriendbotQuota(app, "address", app.config.Friendbot.QuotaPerAddress),
 	}
+}
 
+// newFriendbotQuota creates quota shared by replicas via redis if it's
+// configured, nil if limit is not set
+func newFriendbotQuota(app *App, scope string, limit int) friendbot.Quota {
+	if limit <= 0 {
+		return nil
+	}
+
+	window := app.config.Friendbot.QuotaWindow
+	if window <= 0 {
+		window = 24 * time.Hour
+	}
+
+	if app.redis != nil {
+		return redis.NewFriendbotQuota(scope, limit, window)
+	}
+	return friendbot.NewMemoryQuota(limit, window)
 }
 
 func init() {
-	appInit.Add("friendbot", initFriendbot, "txsub", "stellarCoreInfo")
+	appInit.Add("friendbot", initFriendbot, "txsub", "stellarCoreInfo", "redis")
 }
@@ -1,10 +1,17 @@
 package horizon
 
 import (
+	"bitbucket.org/atticlab/horizon/compliance"
+	conf "bitbucket.org/atticlab/horizon/config"
 	"bitbucket.org/atticlab/horizon/ingest"
+	"bitbucket.org/atticlab/horizon/leader"
+	"bitbucket.org/atticlab/horizon/redis"
 	"log"
 )
 
+// ingestionTask names the lease electing ingesting replica
+const ingestionTask = "ingestion"
+
 func initIngester(app *App) {
 	if !app.config.Ingest {
 		return
@@ -15,9 +22,28 @@ func initIngester(app *App) {
 	}
 
 	app.ingester = ingest.New(app.networkPassphrase, app.CoreRepo(nil), app.HorizonRepo(nil), app.SharedCache().AccountHistoryCache)
+	if app.config.Compliance.Enabled {
+		app.ingester.Compliance = compliance.NewEngine(app.config.Compliance)
+	}
+	app.ingester.Options = app.options
+	app.ingester.Genesis = app.config.Genesis
+
+	if app.config.IngestLeader.IsEnabled() {
+		app.ingestLeader = leader.NewElector(ingestionTask, newIngestLease(app), app.config.IngestLeader.LeaseTTL)
+		app.ingester.Leadership = app.ingestLeader
+		go app.ingestLeader.Run(app.ctx)
+	}
+
 	app.ingester.Start()
 }
 
+func newIngestLease(app *App) leader.Lease {
+	if app.config.IngestLeader.Election == conf.IngestLeaderElectionRedis {
+		return redis.NewLeaderLease(ingestionTask)
+	}
+	return leader.NewPostgresLease(ingestionTask, app.HorizonRepo(nil))
+}
+
 func init() {
-	appInit.Add("ingester", initIngester, "app-context", "log", "horizon-db", "core-db", "stellarCoreInfo", "cache")
+	appInit.Add("ingester", initIngester, "app-context", "log", "horizon-db", "core-db", "stellarCoreInfo", "cache", "options", "redis")
 }
@@ -6,9 +6,23 @@ import (
 )
 
 // initLog initialized the logging subsystem, attaching app.log and
-// app.logMetrics.  It also configured the logger's level using Config.LogLevel.
+// app.logMetrics.  It also configured the logger's level using Config.LogLevel,
+// levels of services and format of the output. Secrets of the config are
+// redacted from logs.
 func initLog(app *App) {
-	log.DefaultLogger.Logger.Level = app.config.LogLevel
+	log.SetFormat(app.config.LogJSON)
+	log.DefaultLevels.SetDefault(app.config.LogLevel)
+	for service, level := range app.config.LogServiceLevels {
+		log.DefaultLevels.Set(service, level)
+	}
+
+	log.DefaultRedaction.AddSecret(app.config.FriendbotSecret)
+	for _, secret := range app.config.Friendbot.PoolSecrets {
+		log.DefaultRedaction.AddSecret(secret)
+	}
+	log.DefaultRedaction.AddSecret(app.config.BankMasterKey)
+	log.DefaultRedaction.AddSecret(app.config.BankCommissionKey)
+	log.DefaultRedaction.AddSecret(app.config.LogglyToken)
 }
 
 // initSentry initialized the default sentry client with the configured DSN
@@ -38,7 +52,8 @@ func initLogglyLog(app *App) {
 	}).Info("Initializing loggly hook")
 
 	hook := log.NewLogglyHook(app.config.LogglyToken)
-	log.DefaultLogger.Logger.Hooks.Add(hook)
+	// entries of services are filtered by their levels before sent to loggly
+	log.DefaultHooks.Add(hook)
 
 	go func() {
 		<-app.ctx.Done()
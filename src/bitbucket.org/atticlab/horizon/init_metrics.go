@@ -5,6 +5,7 @@ import (
 
 	"github.com/rcrowley/go-metrics"
 	"bitbucket.org/atticlab/horizon/log"
+	"bitbucket.org/atticlab/horizon/txsub/transactions/statistics"
 )
 
 func initMetrics(app *App) {
@@ -24,6 +25,13 @@ func initDbMetrics(app *App) {
 	app.metrics.Register("goroutines", app.goroutineGauge)
 }
 
+func initRedisMetrics(app *App) {
+	app.redisAvailableGauge = metrics.NewGauge()
+	app.redisActiveConnGauge = metrics.NewGauge()
+	app.metrics.Register("redis.available", app.redisAvailableGauge)
+	app.metrics.Register("redis.active_connections", app.redisActiveConnGauge)
+}
+
 func initIngesterMetrics(app *App) {
 	if app.ingester == nil {
 		return
@@ -34,6 +42,11 @@ func initIngesterMetrics(app *App) {
 		app.ingester.Metrics.ClearLedgerTimer)
 	app.metrics.Register("indester.load_ledger",
 		app.ingester.Metrics.LoadLedgerTimer)
+
+	if app.ingestLeader != nil {
+		app.metrics.Register("ingester.leader", app.ingestLeader.Metrics.IsLeaderGauge)
+		app.metrics.Register("ingester.leader_changes", app.ingestLeader.Metrics.ChangesMeter)
+	}
 }
 
 func initLogMetrics(app *App) {
@@ -50,6 +63,9 @@ func initTxSubMetrics(app *App) {
 	app.metrics.Register("txsub.succeeded", app.submitter.Metrics.SuccessfulSubmissionsMeter)
 	app.metrics.Register("txsub.failed", app.submitter.Metrics.FailedSubmissionsMeter)
 	app.metrics.Register("txsub.total", app.submitter.Metrics.SubmissionTimer)
+	app.metrics.Register("statistics.retries", statistics.DefaultMetrics.RetryMeter)
+	app.metrics.Register("statistics.failures", statistics.DefaultMetrics.FailureMeter)
+	app.metrics.Register("statistics.degraded", statistics.DefaultMetrics.DegradedMeter)
 }
 
 // initWebMetrics registers the metrics for the web server into the provided
@@ -64,6 +80,7 @@ func init() {
 	appInit.Add("metrics", initMetrics)
 	appInit.Add("log.metrics", initLogMetrics, "metrics")
 	appInit.Add("db-metrics", initDbMetrics, "metrics", "horizon-db", "core-db")
+	appInit.Add("redis.metrics", initRedisMetrics, "metrics", "redis")
 	appInit.Add("web.metrics", initWebMetrics, "web.init", "metrics")
 	appInit.Add("txsub.metrics", initTxSubMetrics, "txsub", "metrics")
 	appInit.Add("ingester.metrics", initIngesterMetrics, "ingester", "metrics")
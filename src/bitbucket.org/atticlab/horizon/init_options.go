@@ -0,0 +1,27 @@
+package horizon
+
+import (
+	"bitbucket.org/atticlab/horizon/cache"
+	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/log"
+	"bitbucket.org/atticlab/horizon/options"
+)
+
+// initOptions loads runtime-editable options and keeps them up to date with
+// changes published by the ingesting instance
+func initOptions(app *App) {
+	app.options = options.NewRegistry(&history.Q{Repo: app.HorizonRepo(nil)}, &app.config, app.redis)
+	// assets are changed by admin operations, reload notification is published
+	// once they are committed
+	app.options.OnReload(cache.InvalidateHistoryAssets)
+	err := app.options.Reload()
+	if err != nil {
+		log.WithField("service", "options").WithError(err).Panic("Failed to load options")
+	}
+
+	go app.options.Listen(app.ctx)
+}
+
+func init() {
+	appInit.Add("options", initOptions, "app-context", "log", "horizon-db", "redis")
+}
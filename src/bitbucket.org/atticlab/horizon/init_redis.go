@@ -1,16 +1,29 @@
 package horizon
 
 import (
+	conf "bitbucket.org/atticlab/horizon/config"
 	"bitbucket.org/atticlab/horizon/log"
 	"bitbucket.org/atticlab/horizon/redis"
 )
 
 func initRedis(app *App) {
-	err := redis.Init(app.config.RedisURL)
+	isConfigured := app.config.RedisURL != "" || app.config.Redis.IsSentinel() || app.config.Redis.IsCluster()
+	if !isConfigured && app.config.StatisticsBackend == conf.StatisticsBackendPostgres && app.config.TxSubBackend != conf.TxSubBackendRedis &&
+		app.config.IngestLeader.Election != conf.IngestLeaderElectionRedis {
+		log.WithField("service", "redis").Info("Redis is not configured, statistics are stored in postgres")
+		return
+	}
+
+	err := redis.InitWithConfig(app.config.RedisURL, app.config.Redis)
 	if err != nil {
-		log.WithField("service", "redis").WithError(err).Panic("Failed to initialize")
+		_, isConfigError := err.(redis.ConfigError)
+		if !app.config.Redis.DegradeToPostgres || isConfigError {
+			log.WithField("service", "redis").WithError(err).Panic("Failed to initialize")
+		}
+		log.WithField("service", "redis").WithError(err).Error("Redis is unavailable, starting in degrade mode")
 	}
 	app.redis = redis.GetPool()
+	redis.StartHealthCheck(app.ctx, app.config.Redis.HealthCheckInterval)
 }
 
 func init() {
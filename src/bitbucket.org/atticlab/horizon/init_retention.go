@@ -0,0 +1,24 @@
+package horizon
+
+import (
+	"bitbucket.org/atticlab/horizon/retention"
+)
+
+// initPruner starts pruning of history tables. Only the ingesting instance prunes
+// history, so there is a single writer to the history tables. If several
+// instances have ingestion enabled, only the elected ingestion leader prunes.
+func initPruner(app *App) {
+	if !app.config.Ingest || !app.config.Retention.IsEnabled() {
+		return
+	}
+
+	app.pruner = retention.New(app.HorizonRepo(nil), app.config.Retention)
+	if app.ingestLeader != nil {
+		app.pruner.IsActive = app.ingestLeader.IsLeader
+	}
+	app.pruner.Start()
+}
+
+func init() {
+	appInit.Add("pruner", initPruner, "app-context", "log", "horizon-db", "ingester")
+}
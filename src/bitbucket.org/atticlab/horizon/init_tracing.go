@@ -0,0 +1,43 @@
+package horizon
+
+import (
+	"net/http"
+	"os"
+	"time"
+
+	conf "bitbucket.org/atticlab/horizon/config"
+	"bitbucket.org/atticlab/horizon/log"
+	"bitbucket.org/atticlab/horizon/tracing"
+)
+
+// initTracing enables export of trace spans, if exporter is configured
+func initTracing(app *App) {
+	var exporter tracing.Exporter
+	config := app.config.Tracing
+	switch config.Exporter {
+	case conf.TracingExporterNone:
+		return
+	case conf.TracingExporterOTLP:
+		exporter = tracing.NewOTLPExporter(config.OTLPEndpoint, config.ServiceName, &http.Client{
+			Timeout: 10 * time.Second,
+		})
+	case conf.TracingExporterStdout:
+		exporter = tracing.NewWriterExporter(os.Stdout)
+	case conf.TracingExporterFile:
+		file, err := os.OpenFile(config.File, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			log.WithField("service", "tracing").WithError(err).Panic("Failed to open traces file")
+		}
+		exporter = tracing.NewWriterExporter(file)
+	}
+
+	processor := tracing.NewBatchProcessor(exporter, 5*time.Second)
+	go processor.Run(app.ctx)
+	tracing.Init(processor, config.SampleRatio)
+	log.WithField("service", "tracing").WithField("exporter", config.Exporter).
+		WithField("sample_ratio", config.SampleRatio).Info("Tracing is enabled")
+}
+
+func init() {
+	appInit.Add("tracing", initTracing, "app-context", "log")
+}
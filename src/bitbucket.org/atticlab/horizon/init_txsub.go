@@ -1,8 +1,10 @@
 package horizon
 
 import (
+	conf "bitbucket.org/atticlab/horizon/config"
 	"bitbucket.org/atticlab/horizon/db2/core"
 	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/redis"
 	"bitbucket.org/atticlab/horizon/txsub"
 	"bitbucket.org/atticlab/horizon/txsub/results/db"
 	"bitbucket.org/atticlab/horizon/txsub/sequence"
@@ -13,16 +15,18 @@ func initSubmissionSystem(app *App) {
 	cq := &core.Q{Repo: app.CoreRepo(nil)}
 	hq := &history.Q{Repo: app.HorizonRepo(nil)}
 
+	pending, queue := newSubmissionState(app)
 	app.submitter = &txsub.System{
-		Pending:         txsub.NewDefaultSubmissionList(),
-		Submitter:       txsub.NewDefaultSubmitter(http.DefaultClient, app.config.StellarCoreURL, cq, hq, &app.config, app.SharedCache()),
-		SubmissionQueue: sequence.NewManager(),
+		Pending:         pending,
+		Submitter:       txsub.NewDefaultSubmitter(http.DefaultClient, app.config.StellarCoreURL, cq, hq, &app.config, app.SharedCache(), app.options),
+		SubmissionQueue: queue,
 		Results: &results.DB{
 			Core:    cq,
 			History: hq,
 		},
 		Sequences:         cq.SequenceProvider(),
 		NetworkPassphrase: app.networkPassphrase,
+		Options:           app.options,
 	}
 
 	go func() {
@@ -35,6 +39,27 @@ func initSubmissionSystem(app *App) {
 
 }
 
+// newSubmissionState creates open submission list and sequence queue, shared
+// by horizon replicas if configured
+func newSubmissionState(app *App) (txsub.OpenSubmissionList, *sequence.Manager) {
+	switch app.config.TxSubBackend {
+	case conf.TxSubBackendRedis:
+		var fallback txsub.SharedStore
+		if app.config.Redis.DegradeToPostgres {
+			fallback = txsub.NewPostgresStore(app.HorizonRepo(nil))
+		}
+		store := txsub.NewDegradingStore(redis.NewTxSubStore(), fallback, func(err error) bool {
+			return redis.IsUnavailable(err) || redis.IsRetryable(err)
+		})
+		return txsub.NewSharedSubmissionList(store), sequence.NewSharedManager(store)
+	case conf.TxSubBackendPostgres:
+		store := txsub.NewPostgresStore(app.HorizonRepo(nil))
+		return txsub.NewSharedSubmissionList(store), sequence.NewSharedManager(store)
+	default:
+		return txsub.NewDefaultSubmissionList(), sequence.NewManager()
+	}
+}
+
 func init() {
-	appInit.Add("txsub", initSubmissionSystem, "app-context", "log", "horizon-db", "core-db", "pump", "cache", "stellarCoreInfo")
+	appInit.Add("txsub", initSubmissionSystem, "app-context", "log", "horizon-db", "core-db", "pump", "cache", "stellarCoreInfo", "options", "redis")
 }
@@ -28,6 +28,8 @@ type Web struct {
 	requestTimer metrics.Timer
 	failureMeter metrics.Meter
 	successMeter metrics.Meter
+	// request timers per route and status
+	routeMetrics *routeMetrics
 }
 
 // initWeb installed a new Web instance onto the provided app object.
@@ -37,6 +39,7 @@ func initWeb(app *App) {
 		requestTimer: metrics.NewTimer(),
 		failureMeter: metrics.NewMeter(),
 		successMeter: metrics.NewMeter(),
+		routeMetrics: newRouteMetrics(),
 	}
 
 	// register problems
@@ -54,6 +57,8 @@ func initWebMiddleware(app *App) {
 	r.Use(app.Middleware)
 	r.Use(middleware.RequestID)
 	r.Use(contextMiddleware(app.ctx))
+	r.Use(tracingMiddleware)
+	r.Use(peerAddrMiddleware)
 	r.Use(xff.Handler)
 	r.Use(LoggerMiddleware)
 	r.Use(requestMetricsMiddleware)
@@ -71,6 +76,9 @@ func initWebMiddleware(app *App) {
 	} else {
 		log.Warn("No rate limit")
 	}
+
+	// route requests before handling, so matched route is available to request metrics
+	r.Use(r.Router)
 }
 
 // initWebActions installs the routing configuration of horizon onto the
@@ -80,6 +88,8 @@ func initWebActions(app *App) {
 	r.Get("/", &RootAction{})
 	r.Get("/metrics", &MetricsAction{})
 	r.Get("/options", &OptionsAction{})
+	r.Get("/health", http.HandlerFunc(app.ServeLiveness))
+	r.Get("/ready", http.HandlerFunc(app.ServeReadiness))
 
 	// ledger actions
 	r.Get("/ledgers", &LedgerIndexAction{})
@@ -94,10 +104,13 @@ func initWebActions(app *App) {
 	r.Get("/accounts/:id", &AccountShowAction{})
 	r.Get("/accounts/:account_id/statistics", &AccountStatisticsAction{})
 	r.Get("/accounts/:account_id/traits", &AccountTraitsAction{})
+	r.Get("/accounts/:account_id/traits/history", &AccountTraitsHistoryAction{})
 	r.Get("/accounts/:account_id/limits", &AccountLimitsAction{})
 	r.Get("/accounts/:account_id/transactions", &TransactionIndexAction{})
 	r.Get("/accounts/:account_id/operations", &OperationIndexAction{})
 	r.Get("/accounts/:account_id/payments", &PaymentsIndexAction{})
+	r.Get("/accounts/:account_id/statement", &AccountStatementAction{})
+	r.Post("/accounts/:account_id/reconciliation", &AccountReconciliationAction{})
 	r.Get("/accounts/:account_id/effects", &EffectIndexAction{})
 	r.Get("/accounts/:account_id/offers", &OffersByAccountAction{})
 	r.Get("/accounts/:account_id/trades", &TradeIndexAction{})
@@ -113,6 +126,7 @@ func initWebActions(app *App) {
 	r.Get("/transactions/:tx_id/operations", &OperationIndexAction{})
 	r.Get("/transactions/:tx_id/payments", &PaymentsIndexAction{})
 	r.Get("/transactions/:tx_id/effects", &EffectIndexAction{})
+	r.Get("/transactions/:tx_id/screening", &TransactionScreeningAction{})
 	r.Get("/traits", &AccountTraitsIndexAction{})
 
 	// operation actions
@@ -135,11 +149,21 @@ func initWebActions(app *App) {
 	r.Get("/commission", &CommissionIndexAction{})
 	r.Get("/commission/calculate", &CalculateCommissionAction{})
 
+	r.Get("/reversal_windows", &ReversalWindowIndexAction{})
+
+	// dispute cases
+	r.Get("/disputes", &DisputeIndexAction{})
+	r.Get("/disputes/:id", &DisputeShowAction{})
+
 	// friendbot
 	r.Post("/friendbot", &FriendbotAction{})
 	r.Get("/friendbot", &FriendbotAction{})
 
 	r.Get("/assets", &AssetIndexAction{})
+	r.Get("/assets/:code/:issuer", &AssetShowAction{})
+	r.Get("/assets/:code/:issuer/supply", &AssetSupplyAction{})
+
+	r.Get("/compliance/alerts", &ComplianceAlertsIndexAction{})
 
 	r.NotFound(&NotFoundAction{})
 }
@@ -153,11 +177,16 @@ func initWebRateLimiter(app *App) {
 		log.Panic("Rate limiter requires redis")
 	}
 
-	rateLimitStore, err := redigostore.New(app.redis, "throttle:", 0)
+	redisStore, err := redigostore.New(app.redis, "throttle:", 0)
 	if err != nil {
 		log.WithField("error", err).Panic("Failed to create redis rate limiter store")
 	}
 
+	var rateLimitStore throttled.GCRAStore = redisStore
+	if app.config.Redis.DegradeToPostgres {
+		rateLimitStore = newDegradableRateLimitStore(redisStore)
+	}
+
 	rateLimiter, err := throttled.NewGCRARateLimiter(rateLimitStore, *app.config.RateLimit)
 	if err != nil {
 		log.WithField("error", err).Panic("Failed to create rate limiter")
@@ -202,6 +231,7 @@ func init() {
 		"web.init",
 		"web.rate-limiter",
 		"web.metrics",
+		"tracing",
 	)
 	appInit.Add(
 		"web.actions",
@@ -0,0 +1,242 @@
+// Package leader elects the only horizon replica performing a task, e.g.
+// ingestion, using a lease stored in postgres or redis.
+package leader
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/log"
+	"github.com/rcrowley/go-metrics"
+	"golang.org/x/net/context"
+)
+
+// Lease is held by one replica at a time. Holder must renew it before ttl
+// passes, otherwise another replica can acquire it.
+type Lease interface {
+	// Acquire takes the lease for holder or renews it, if it's already held by
+	// holder. Returns the current holder.
+	Acquire(holder string, ttl time.Duration) (string, error)
+	// Release gives up the lease, if it's held by holder
+	Release(holder string) error
+	// Holder returns the current holder, empty if lease is free or expired
+	Holder() (string, error)
+}
+
+// TxLease is a lease, which holder can be verified within transaction of
+// history db, so the lease can't change hands until the transaction ends
+type TxLease interface {
+	Lease
+	// HolderInTx returns the current holder within the transaction of q
+	HolderInTx(q *history.Q) (string, error)
+}
+
+// TokenLease is a lease stored outside of history db. Each change of the
+// holder issues greater fencing token, which holder records in history db
+// within the transaction of its work.
+type TokenLease interface {
+	Lease
+	// HolderToken returns the current holder and fencing token of its tenure
+	HolderToken() (string, int64, error)
+}
+
+// ErrNotLeader is returned by Fence, if this replica does not hold the lease
+var ErrNotLeader = errors.New("replica is not the leader")
+
+// errNoFencing is returned by Fence, if the lease can't be verified within
+// transaction of history db
+var errNoFencing = errors.New("lease does not support fencing")
+
+// Metrics of the election
+type Metrics struct {
+	// IsLeaderGauge is 1 while this replica is the leader
+	IsLeaderGauge metrics.Gauge
+	// ChangesMeter marks changes of the leader seen by this replica
+	ChangesMeter metrics.Meter
+}
+
+// Elector periodically acquires the lease and tracks whether this replica is
+// the leader. On errors of the lease backend replica steps down, as it can't
+// be sure nobody else took the lease.
+type Elector struct {
+	ID      string
+	Metrics Metrics
+
+	task     string
+	lease    Lease
+	ttl      time.Duration
+	interval time.Duration
+	log      *log.Entry
+
+	lock   sync.RWMutex
+	leader string
+	// closed while this replica is not the leader
+	lost chan struct{}
+}
+
+// NewElector creates elector of the task. Lease is renewed three times per ttl.
+func NewElector(task string, lease Lease, ttl time.Duration) *Elector {
+	lost := make(chan struct{})
+	close(lost)
+
+	id := DefaultID()
+	return &Elector{
+		ID: id,
+		Metrics: Metrics{
+			IsLeaderGauge: metrics.NewGauge(),
+			ChangesMeter:  metrics.NewMeter(),
+		},
+		task:     task,
+		lease:    lease,
+		ttl:      ttl,
+		interval: ttl / 3,
+		log:      log.WithField("service", "leader").WithField("task", task).WithField("id", id),
+		lost:     lost,
+	}
+}
+
+// DefaultID identifies this process among replicas by host name and pid
+func DefaultID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s:%d", host, os.Getpid())
+}
+
+// Run renews the lease until ctx is done, then releases it
+func (e *Elector) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		e.Elect()
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			e.Resign()
+			return
+		}
+	}
+}
+
+// Elect acquires or renews the lease once
+func (e *Elector) Elect() {
+	holder, err := e.lease.Acquire(e.ID, e.ttl)
+	if err != nil {
+		e.log.WithError(err).Error("Failed to acquire lease")
+		holder = ""
+	}
+	e.setLeader(holder)
+}
+
+// Resign steps down and releases the lease, so another replica takes over
+// without waiting for expiration
+func (e *Elector) Resign() {
+	if !e.IsLeader() {
+		return
+	}
+
+	e.setLeader("")
+	err := e.lease.Release(e.ID)
+	if err != nil {
+		e.log.WithError(err).Error("Failed to release lease")
+	}
+}
+
+// Fence verifies this replica still holds the lease before work of the leader
+// is committed in transaction of q. Lease stored in history db is checked
+// within the transaction, so another replica can't take it over until commit.
+// For other leases the fencing token is recorded within the transaction, so
+// work of the previous holder can't be committed after the lease is taken over.
+// Replica steps down, if the lease is held by another one.
+func (e *Elector) Fence(q *history.Q) error {
+	var holder string
+	var err error
+	switch lease := e.lease.(type) {
+	case TxLease:
+		holder, err = lease.HolderInTx(q)
+	case TokenLease:
+		holder, err = e.fenceToken(q, lease)
+	default:
+		err = errNoFencing
+	}
+	if err != nil {
+		return err
+	}
+
+	if holder != e.ID {
+		e.setLeader(holder)
+		return ErrNotLeader
+	}
+	return nil
+}
+
+// fenceToken returns holder of the lease, if token of this replica's tenure is
+// recorded, otherwise empty holder
+func (e *Elector) fenceToken(q *history.Q, lease TokenLease) (string, error) {
+	holder, token, err := lease.HolderToken()
+	if err != nil || holder != e.ID {
+		return holder, err
+	}
+
+	current, err := q.LeaderFenceAdvance(e.task, token)
+	if err != nil || !current {
+		return "", err
+	}
+	return holder, nil
+}
+
+// IsLeader returns true if this replica holds the lease
+func (e *Elector) IsLeader() bool {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+	return e.leader == e.ID
+}
+
+// Leader returns id of the replica holding the lease, empty if unknown
+func (e *Elector) Leader() string {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+	return e.leader
+}
+
+// Lost returns a channel, which is closed when this replica stops being the
+// leader. Channel is already closed if replica is not the leader.
+func (e *Elector) Lost() <-chan struct{} {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+	return e.lost
+}
+
+func (e *Elector) setLeader(holder string) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if holder == e.leader {
+		return
+	}
+
+	wasLeader := e.leader == e.ID
+	isLeader := holder == e.ID
+	e.leader = holder
+	e.Metrics.ChangesMeter.Mark(1)
+
+	switch {
+	case isLeader && !wasLeader:
+		e.lost = make(chan struct{})
+		e.Metrics.IsLeaderGauge.Update(1)
+		e.log.Info("Elected as leader")
+	case wasLeader && !isLeader:
+		close(e.lost)
+		e.Metrics.IsLeaderGauge.Update(0)
+		e.log.WithField("leader", holder).Warn("Lost leadership")
+	default:
+		e.log.WithField("leader", holder).Info("Leader changed")
+	}
+}
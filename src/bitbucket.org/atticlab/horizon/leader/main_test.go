@@ -0,0 +1,135 @@
+package leader
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/test"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type leaseMock struct {
+	holder string
+	token  int64
+	err    error
+}
+
+func (l *leaseMock) Acquire(holder string, ttl time.Duration) (string, error) {
+	if l.err != nil {
+		return "", l.err
+	}
+	if l.holder == "" {
+		l.holder = holder
+		l.token++
+	}
+	return l.holder, nil
+}
+
+func (l *leaseMock) HolderToken() (string, int64, error) {
+	return l.holder, l.token, l.err
+}
+
+func (l *leaseMock) Holder() (string, error) {
+	return l.holder, l.err
+}
+
+func (l *leaseMock) Release(holder string) error {
+	if l.holder == holder {
+		l.holder = ""
+	}
+	return nil
+}
+
+func TestElector(t *testing.T) {
+	Convey("Elector", t, func() {
+		lease := &leaseMock{}
+		elector := NewElector("test", lease, 3*time.Second)
+
+		So(elector.IsLeader(), ShouldBeFalse)
+		So(isClosed(elector.Lost()), ShouldBeTrue)
+
+		Convey("acquires free lease", func() {
+			elector.Elect()
+			So(elector.IsLeader(), ShouldBeTrue)
+			So(elector.Leader(), ShouldEqual, elector.ID)
+			So(elector.Metrics.IsLeaderGauge.Value(), ShouldEqual, 1)
+
+			lost := elector.Lost()
+			So(isClosed(lost), ShouldBeFalse)
+
+			Convey("steps down on lease errors", func() {
+				lease.err = errors.New("unavailable")
+				elector.Elect()
+				So(elector.IsLeader(), ShouldBeFalse)
+				So(isClosed(lost), ShouldBeTrue)
+				So(elector.Metrics.IsLeaderGauge.Value(), ShouldEqual, 0)
+			})
+
+			Convey("releases lease on resign", func() {
+				elector.Resign()
+				So(elector.IsLeader(), ShouldBeFalse)
+				So(isClosed(lost), ShouldBeTrue)
+				So(lease.holder, ShouldEqual, "")
+			})
+		})
+
+		Convey("follows another leader", func() {
+			lease.holder = "other"
+			elector.Elect()
+			So(elector.IsLeader(), ShouldBeFalse)
+			So(elector.Leader(), ShouldEqual, "other")
+
+			elector.Resign()
+			So(lease.holder, ShouldEqual, "other")
+		})
+	})
+}
+
+func TestElectorFence(t *testing.T) {
+	tt := test.Start(t).Scenario("base")
+	defer tt.Finish()
+	q := &history.Q{Repo: tt.HorizonRepo()}
+
+	Convey("Elector fence", t, func() {
+		lease := &leaseMock{}
+		elector := NewElector("test", lease, 3*time.Second)
+		elector.Elect()
+		So(elector.IsLeader(), ShouldBeTrue)
+		lost := elector.Lost()
+
+		Convey("passes while lease is held", func() {
+			So(elector.Fence(q), ShouldBeNil)
+			So(elector.Fence(q), ShouldBeNil)
+		})
+
+		Convey("steps down if lease is taken over", func() {
+			lease.holder = "other"
+			So(elector.Fence(q), ShouldEqual, ErrNotLeader)
+			So(elector.IsLeader(), ShouldBeFalse)
+			So(isClosed(lost), ShouldBeTrue)
+		})
+
+		Convey("steps down if newer holder has committed", func() {
+			// lease expired and was taken by another replica, which committed
+			// its work before this replica's check of the lease
+			current, err := q.LeaderFenceAdvance("test", lease.token+1)
+			So(err, ShouldBeNil)
+			So(current, ShouldBeTrue)
+
+			So(elector.Fence(q), ShouldEqual, ErrNotLeader)
+			So(elector.IsLeader(), ShouldBeFalse)
+			So(isClosed(lost), ShouldBeTrue)
+		})
+	})
+}
+
+func isClosed(c <-chan struct{}) bool {
+	select {
+	case <-c:
+		return true
+	default:
+		return false
+	}
+}
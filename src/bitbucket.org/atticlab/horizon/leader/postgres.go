@@ -0,0 +1,91 @@
+package leader
+
+import (
+	"time"
+
+	"bitbucket.org/atticlab/horizon/db2"
+	"bitbucket.org/atticlab/horizon/db2/history"
+)
+
+// PostgresLease stores lease in history db. Acquisitions are serialized by
+// advisory lock of the lease.
+type PostgresLease struct {
+	name string
+	repo *db2.Repo
+}
+
+// NewPostgresLease creates lease of the task
+func NewPostgresLease(name string, repo *db2.Repo) *PostgresLease {
+	return &PostgresLease{
+		name: name,
+		repo: repo,
+	}
+}
+
+func (l *PostgresLease) q() *history.Q {
+	return &history.Q{Repo: l.repo.Clone()}
+}
+
+// Acquire implements Lease
+func (l *PostgresLease) Acquire(holder string, ttl time.Duration) (string, error) {
+	q := l.q()
+	err := q.Begin()
+	if err != nil {
+		return "", err
+	}
+	defer q.Rollback()
+
+	err = q.LockLeaderLease(l.name)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	lease, err := q.LeaderLeaseByName(l.name, now)
+	if err != nil {
+		return "", err
+	}
+
+	if lease != nil && lease.Holder != holder {
+		return lease.Holder, nil
+	}
+
+	err = q.LeaderLeaseSave(&history.LeaderLease{
+		Name:      l.name,
+		Holder:    holder,
+		ExpiresAt: now.Add(ttl),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return holder, q.Commit()
+}
+
+// Holder implements Lease
+func (l *PostgresLease) Holder() (string, error) {
+	return l.holder(l.q())
+}
+
+// HolderInTx implements TxLease. Lease is locked until the transaction of q
+// ends.
+func (l *PostgresLease) HolderInTx(q *history.Q) (string, error) {
+	err := q.LockLeaderLease(l.name)
+	if err != nil {
+		return "", err
+	}
+	return l.holder(q)
+}
+
+func (l *PostgresLease) holder(q *history.Q) (string, error) {
+	lease, err := q.LeaderLeaseByName(l.name, time.Now())
+	if err != nil || lease == nil {
+		return "", err
+	}
+	return lease.Holder, nil
+}
+
+// Release implements Lease
+func (l *PostgresLease) Release(holder string) error {
+	return l.q().LeaderLeaseDelete(l.name, holder)
+}
@@ -0,0 +1,218 @@
+package log
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// ServiceField is a field of the entry log levels are selected by
+const ServiceField = "service"
+
+// Levels holds log level of each service. Level of the entry is selected by
+// its `service` field, entries without service use default level. Levels can
+// be changed at runtime.
+type Levels struct {
+	lock         sync.RWMutex
+	defaultLevel logrus.Level
+	services     map[string]logrus.Level
+	// logger's level is kept at the most verbose of all levels, so entries of
+	// services with lower level than default are not dropped by logrus
+	logger *logrus.Logger
+}
+
+// NewLevels creates levels of the logger
+func NewLevels(logger *logrus.Logger) *Levels {
+	return &Levels{
+		defaultLevel: logger.Level,
+		services:     make(map[string]logrus.Level),
+		logger:       logger,
+	}
+}
+
+// Default returns level of entries without level of their service
+func (l *Levels) Default() logrus.Level {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	return l.defaultLevel
+}
+
+// SetDefault sets level of entries without level of their service
+func (l *Levels) SetDefault(level logrus.Level) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.defaultLevel = level
+	l.updateLogger()
+}
+
+// Set sets level of the service
+func (l *Levels) Set(service string, level logrus.Level) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.services[service] = level
+	l.updateLogger()
+}
+
+// Reset makes service use default level
+func (l *Levels) Reset(service string) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	delete(l.services, service)
+	l.updateLogger()
+}
+
+// Services returns levels set for services
+func (l *Levels) Services() map[string]logrus.Level {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	result := make(map[string]logrus.Level, len(l.services))
+	for service, level := range l.services {
+		result[service] = level
+	}
+	return result
+}
+
+// Enabled returns true if entry must be written
+func (l *Levels) Enabled(entry *logrus.Entry) bool {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	level := l.defaultLevel
+	if service, ok := entry.Data[ServiceField].(string); ok {
+		if serviceLevel, ok := l.services[service]; ok {
+			level = serviceLevel
+		}
+	}
+
+	return entry.Level <= level
+}
+
+func (l *Levels) updateLogger() {
+	level := l.defaultLevel
+	for _, serviceLevel := range l.services {
+		if serviceLevel > level {
+			level = serviceLevel
+		}
+	}
+	l.logger.Level = level
+}
+
+// ParseServiceLevels parses comma separated list of service=level pairs
+func ParseServiceLevels(value string) (map[string]logrus.Level, error) {
+	result := make(map[string]logrus.Level)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid service level %q, expected service=level", pair)
+		}
+
+		level, err := logrus.ParseLevel(parts[1])
+		if err != nil {
+			return nil, err
+		}
+		result[parts[0]] = level
+	}
+
+	return result, nil
+}
+
+// LevelHook passes entries enabled by levels of their services to the output
+// and hooks added to it (e.g. Loggly). It must be added to the logger right
+// after RedactionHook, logger's own output must be discarded.
+type LevelHook struct {
+	levels *Levels
+
+	lock      sync.RWMutex
+	hooks     []logrus.Hook
+	out       io.Writer
+	formatter logrus.Formatter
+}
+
+// NewLevelHook creates hook filtering entries by levels
+func NewLevelHook(levels *Levels) *LevelHook {
+	return &LevelHook{
+		levels: levels,
+	}
+}
+
+// Add adds hook fired for enabled entries only
+func (h *LevelHook) Add(hook logrus.Hook) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.hooks = append(h.hooks, hook)
+}
+
+// SetOutput makes hook write enabled entries to out
+func (h *LevelHook) SetOutput(out io.Writer, formatter logrus.Formatter) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.out = out
+	h.formatter = formatter
+}
+
+// Fire is triggered by logrus, in response to a logging event
+func (h *LevelHook) Fire(entry *logrus.Entry) error {
+	if !h.levels.Enabled(entry) {
+		return nil
+	}
+
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	for _, hook := range h.hooks {
+		if !hasLevel(hook.Levels(), entry.Level) {
+			continue
+		}
+		if err := hook.Fire(entry); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to fire hook: %v\n", err)
+		}
+	}
+
+	if h.out == nil {
+		return nil
+	}
+
+	serialized, err := h.formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+	_, err = h.out.Write(serialized)
+	return err
+}
+
+// Levels returns the logging levels that will trigger this hook to run.  In
+// this case, all of them.
+func (h *LevelHook) Levels() []logrus.Level {
+	return []logrus.Level{
+		logrus.DebugLevel,
+		logrus.InfoLevel,
+		logrus.WarnLevel,
+		logrus.ErrorLevel,
+		logrus.FatalLevel,
+		logrus.PanicLevel,
+	}
+}
+
+func hasLevel(levels []logrus.Level, level logrus.Level) bool {
+	for _, l := range levels {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
+
+// discardFormatter is a formatter of the logger, which output is written by LevelHook
+type discardFormatter struct{}
+
+func (f discardFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	return nil, nil
+}
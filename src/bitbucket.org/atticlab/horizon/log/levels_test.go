@@ -0,0 +1,86 @@
+package log
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/Sirupsen/logrus"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// firedHook records messages of entries it was fired for
+type firedHook struct {
+	messages []string
+}
+
+func (h *firedHook) Fire(entry *logrus.Entry) error {
+	h.messages = append(h.messages, entry.Message)
+	return nil
+}
+
+func (h *firedHook) Levels() []logrus.Level {
+	return []logrus.Level{logrus.DebugLevel, logrus.InfoLevel, logrus.WarnLevel, logrus.ErrorLevel}
+}
+
+func TestLevels(t *testing.T) {
+	Convey("Levels", t, func() {
+		output := new(bytes.Buffer)
+		l, _ := New()
+		levels := NewLevels(l.Logger)
+		hook := NewLevelHook(levels)
+		hook.SetOutput(output, &logrus.TextFormatter{DisableColors: true})
+		l.Logger.Hooks.Add(hook)
+		l.Logger.Out = ioutil.Discard
+		l.Logger.Formatter = discardFormatter{}
+		fired := &firedHook{}
+		hook.Add(fired)
+
+		Convey("service level overrides default", func() {
+			levels.Set("submitter", logrus.DebugLevel)
+			So(l.Logger.Level, ShouldEqual, logrus.DebugLevel)
+
+			l.Debug("default")
+			l.WithField(ServiceField, "statistics_manager").Debug("statistics")
+			l.WithField(ServiceField, "submitter").Debug("submitter")
+
+			So(output.String(), ShouldNotContainSubstring, "msg=default")
+			So(output.String(), ShouldNotContainSubstring, "msg=statistics")
+			So(output.String(), ShouldContainSubstring, "msg=submitter")
+			So(fired.messages, ShouldResemble, []string{"submitter"})
+		})
+
+		Convey("service level can be lower than default", func() {
+			levels.SetDefault(logrus.InfoLevel)
+			levels.Set("submitter", logrus.ErrorLevel)
+
+			l.Info("default")
+			l.WithField(ServiceField, "submitter").Warn("submitter")
+
+			So(output.String(), ShouldContainSubstring, "msg=default")
+			So(output.String(), ShouldNotContainSubstring, "msg=submitter")
+			So(fired.messages, ShouldResemble, []string{"default"})
+		})
+
+		Convey("reset service uses default", func() {
+			levels.Set("submitter", logrus.DebugLevel)
+			levels.Reset("submitter")
+			So(l.Logger.Level, ShouldEqual, logrus.WarnLevel)
+			So(levels.Services(), ShouldBeEmpty)
+		})
+	})
+
+	Convey("ParseServiceLevels", t, func() {
+		levels, err := ParseServiceLevels("submitter=debug, statistics_manager=info,")
+		So(err, ShouldBeNil)
+		So(levels, ShouldResemble, map[string]logrus.Level{
+			"submitter":          logrus.DebugLevel,
+			"statistics_manager": logrus.InfoLevel,
+		})
+
+		_, err = ParseServiceLevels("submitter")
+		So(err, ShouldNotBeNil)
+		_, err = ParseServiceLevels("submitter=verbose")
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/Sirupsen/logrus"
 	"github.com/go-errors/errors"
 	"golang.org/x/net/context"
+	"io/ioutil"
 	"os"
 	// glog "log"
 )
@@ -12,6 +13,16 @@ var contextKey = 0
 var DefaultLogger *Entry
 var DefaultMetrics *Metrics
 
+// DefaultLevels are levels of services logged by DefaultLogger
+var DefaultLevels *Levels
+
+// DefaultRedaction removes secrets from entries of DefaultLogger
+var DefaultRedaction *RedactionHook
+
+// DefaultHooks writes entries of DefaultLogger enabled by DefaultLevels and
+// passes them to hooks added to it
+var DefaultHooks *LevelHook
+
 const (
 	PanicLevel = logrus.PanicLevel
 	ErrorLevel = logrus.ErrorLevel
@@ -24,6 +35,23 @@ type F logrus.Fields
 
 func init() {
 	DefaultLogger, DefaultMetrics = New()
+	DefaultLevels = NewLevels(DefaultLogger.Logger)
+	DefaultRedaction = NewRedactionHook()
+	DefaultLogger.Logger.Hooks.Add(DefaultRedaction)
+	DefaultHooks = NewLevelHook(DefaultLevels)
+	DefaultLogger.Logger.Hooks.Add(DefaultHooks)
+}
+
+// SetFormat makes DefaultLogger write entries as JSON or text, filtered by
+// DefaultLevels
+func SetFormat(json bool) {
+	var formatter logrus.Formatter = &logrus.TextFormatter{}
+	if json {
+		formatter = &logrus.JSONFormatter{}
+	}
+	DefaultHooks.SetOutput(DefaultLogger.Logger.Out, formatter)
+	DefaultLogger.Logger.Out = ioutil.Discard
+	DefaultLogger.Logger.Formatter = discardFormatter{}
 }
 
 // New creates a new logger according to horizon specifications.
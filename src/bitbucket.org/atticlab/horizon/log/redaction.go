@@ -0,0 +1,146 @@
+package log
+
+import (
+	"strings"
+	"sync"
+
+	"bitbucket.org/atticlab/go-smart-base/xdr"
+	"github.com/Sirupsen/logrus"
+)
+
+// Redacted replaces secret values in logged fields
+const Redacted = "[REDACTED]"
+
+// parts of field names holding secrets, compared in lower case without separators
+var secretKeyParts = []string{"secret", "seed", "password", "privatekey"}
+
+// RedactionHook removes secrets from the message and fields of the entry before
+// it is written or sent by other hooks, so it must be added first. Secrets
+// are:
+//   - values of fields with names like secret, seed or password;
+//   - registered values (e.g. friendbot secret), wherever they appear;
+//   - signatures of transaction envelopes, logged as xdr or base64 string.
+type RedactionHook struct {
+	lock    sync.RWMutex
+	secrets []string
+}
+
+// NewRedactionHook creates new hook
+func NewRedactionHook() *RedactionHook {
+	return &RedactionHook{}
+}
+
+// AddSecret registers value, which must never be logged
+func (h *RedactionHook) AddSecret(secret string) {
+	if secret == "" {
+		return
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.secrets = append(h.secrets, secret)
+}
+
+// Fire is triggered by logrus, in response to a logging event
+func (h *RedactionHook) Fire(e *logrus.Entry) error {
+	e.Message = h.redactString(e.Message)
+
+	// fields may be shared with the logger entry was created from, so they are copied
+	data := make(logrus.Fields, len(e.Data))
+	for key, value := range e.Data {
+		data[key] = h.redactField(key, value)
+	}
+	e.Data = data
+	return nil
+}
+
+// Levels returns the logging levels that will trigger this hook to run.  In
+// this case, all of them.
+func (h *RedactionHook) Levels() []logrus.Level {
+	return []logrus.Level{
+		logrus.DebugLevel,
+		logrus.InfoLevel,
+		logrus.WarnLevel,
+		logrus.ErrorLevel,
+		logrus.FatalLevel,
+		logrus.PanicLevel,
+	}
+}
+
+func (h *RedactionHook) redactField(key string, value interface{}) interface{} {
+	if isSecretKey(key) {
+		return Redacted
+	}
+
+	switch v := value.(type) {
+	case string:
+		if redacted, ok := redactEnvelope(v); ok {
+			return redacted
+		}
+		return h.redactString(v)
+	case xdr.TransactionEnvelope:
+		return withoutSignatures(v)
+	case *xdr.TransactionEnvelope:
+		if v == nil {
+			return v
+		}
+		return withoutSignatures(*v)
+	case error:
+		return h.redactString(v.Error())
+	default:
+		return value
+	}
+}
+
+func (h *RedactionHook) redactString(value string) string {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	for _, secret := range h.secrets {
+		value = strings.Replace(value, secret, Redacted, -1)
+	}
+	return value
+}
+
+func isSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	key = strings.Replace(key, "_", "", -1)
+	key = strings.Replace(key, "-", "", -1)
+	for _, part := range secretKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactEnvelope removes signatures from base64 encoded transaction envelope.
+// Returns false if value is not an envelope.
+func redactEnvelope(value string) (string, bool) {
+	// the shortest envelope is far longer, skip decoding of regular strings
+	if len(value) < 64 || strings.ContainsAny(value, " \n") {
+		return "", false
+	}
+
+	var envelope xdr.TransactionEnvelope
+	err := xdr.SafeUnmarshalBase64(value, &envelope)
+	if err != nil || len(envelope.Signatures) == 0 {
+		return "", false
+	}
+
+	result, err := xdr.MarshalBase64(withoutSignatures(envelope))
+	if err != nil {
+		return Redacted, true
+	}
+	return result, true
+}
+
+// withoutSignatures returns copy of the envelope with signatures cleared, hints
+// are kept to identify signers
+func withoutSignatures(envelope xdr.TransactionEnvelope) xdr.TransactionEnvelope {
+	signatures := make([]xdr.DecoratedSignature, len(envelope.Signatures))
+	for i, signature := range envelope.Signatures {
+		signatures[i] = xdr.DecoratedSignature{Hint: signature.Hint}
+	}
+	envelope.Signatures = signatures
+	return envelope
+}
@@ -0,0 +1,63 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"bitbucket.org/atticlab/go-smart-base/xdr"
+	"github.com/Sirupsen/logrus"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRedactionHook(t *testing.T) {
+	Convey("RedactionHook", t, func() {
+		output := new(bytes.Buffer)
+		l, _ := New()
+		l.Logger.Formatter.(*logrus.TextFormatter).DisableColors = true
+		l.Logger.Out = output
+		hook := NewRedactionHook()
+		hook.AddSecret("SBQWY3DNPFWGSZTFNV4WQZLBOJ2GQYLTMJSWK3TTMVRXEZLUOJQWO5A")
+		l.Logger.Hooks.Add(hook)
+
+		Convey("redacts fields with secret names", func() {
+			l.WithFields(logrus.Fields{
+				"bank_master_key_seed": "seed",
+				"Password":             "password",
+				"account":              "GABC",
+			}).Warn("test")
+
+			So(output.String(), ShouldNotContainSubstring, "=seed")
+			So(output.String(), ShouldNotContainSubstring, "=password")
+			So(output.String(), ShouldContainSubstring, "account=GABC")
+		})
+
+		Convey("redacts registered secrets", func() {
+			l.WithField("err", errors.New("invalid SBQWY3DNPFWGSZTFNV4WQZLBOJ2GQYLTMJSWK3TTMVRXEZLUOJQWO5A")).
+				Warn("failed with SBQWY3DNPFWGSZTFNV4WQZLBOJ2GQYLTMJSWK3TTMVRXEZLUOJQWO5A")
+
+			So(output.String(), ShouldNotContainSubstring, "SBQWY3DNPFWGSZTFNV4WQZLBOJ2GQYLTMJSWK3TTMVRXEZLUOJQWO5A")
+			So(output.String(), ShouldContainSubstring, Redacted)
+		})
+
+		Convey("does not modify fields of parent entry", func() {
+			parent := l.WithField("secret", "value")
+			parent.Warn("test")
+			So(parent.Data["secret"], ShouldEqual, "value")
+		})
+
+		Convey("clears signatures of envelopes", func() {
+			envelope := xdr.TransactionEnvelope{
+				Signatures: []xdr.DecoratedSignature{{
+					Hint:      xdr.SignatureHint{1, 2, 3, 4},
+					Signature: xdr.Signature{5, 6, 7, 8},
+				}},
+			}
+
+			redacted := hook.redactField("envelope", envelope).(xdr.TransactionEnvelope)
+			So(redacted.Signatures[0].Hint, ShouldResemble, envelope.Signatures[0].Hint)
+			So(redacted.Signatures[0].Signature, ShouldBeEmpty)
+			So(envelope.Signatures[0].Signature, ShouldResemble, xdr.Signature{5, 6, 7, 8})
+		})
+	})
+}
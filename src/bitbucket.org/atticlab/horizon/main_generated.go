@@ -19,6 +19,13 @@ func (action AccountLimitsAction) ServeHTTPC(c web.C, w http.ResponseWriter, r *
 	ap.Execute(&action)
 }
 
+// ServeHTTPC is a method for web.Handler
+func (action AccountReconciliationAction) ServeHTTPC(c web.C, w http.ResponseWriter, r *http.Request) {
+	ap := &action.Action
+	ap.Prepare(c, w, r)
+	ap.Execute(&action)
+}
+
 // ServeHTTPC is a method for web.Handler
 func (action AccountShowAction) ServeHTTPC(c web.C, w http.ResponseWriter, r *http.Request) {
 	ap := &action.Action
@@ -33,6 +40,13 @@ func (action AccountShowBalancesAction) ServeHTTPC(c web.C, w http.ResponseWrite
 	ap.Execute(&action)
 }
 
+// ServeHTTPC is a method for web.Handler
+func (action AccountStatementAction) ServeHTTPC(c web.C, w http.ResponseWriter, r *http.Request) {
+	ap := &action.Action
+	ap.Prepare(c, w, r)
+	ap.Execute(&action)
+}
+
 // ServeHTTPC is a method for web.Handler
 func (action AccountStatisticsAction) ServeHTTPC(c web.C, w http.ResponseWriter, r *http.Request) {
 	ap := &action.Action
@@ -47,6 +61,13 @@ func (action AccountTraitsAction) ServeHTTPC(c web.C, w http.ResponseWriter, r *
 	ap.Execute(&action)
 }
 
+// ServeHTTPC is a method for web.Handler
+func (action AccountTraitsHistoryAction) ServeHTTPC(c web.C, w http.ResponseWriter, r *http.Request) {
+	ap := &action.Action
+	ap.Prepare(c, w, r)
+	ap.Execute(&action)
+}
+
 // ServeHTTPC is a method for web.Handler
 func (action AccountTraitsIndexAction) ServeHTTPC(c web.C, w http.ResponseWriter, r *http.Request) {
 	ap := &action.Action
@@ -75,6 +96,13 @@ func (action CommissionIndexAction) ServeHTTPC(c web.C, w http.ResponseWriter, r
 	ap.Execute(&action)
 }
 
+// ServeHTTPC is a method for web.Handler
+func (action ComplianceAlertsIndexAction) ServeHTTPC(c web.C, w http.ResponseWriter, r *http.Request) {
+	ap := &action.Action
+	ap.Prepare(c, w, r)
+	ap.Execute(&action)
+}
+
 // ServeHTTPC is a method for web.Handler
 func (action DataShowAction) ServeHTTPC(c web.C, w http.ResponseWriter, r *http.Request) {
 	ap := &action.Action
@@ -82,6 +110,20 @@ func (action DataShowAction) ServeHTTPC(c web.C, w http.ResponseWriter, r *http.
 	ap.Execute(&action)
 }
 
+// ServeHTTPC is a method for web.Handler
+func (action DisputeIndexAction) ServeHTTPC(c web.C, w http.ResponseWriter, r *http.Request) {
+	ap := &action.Action
+	ap.Prepare(c, w, r)
+	ap.Execute(&action)
+}
+
+// ServeHTTPC is a method for web.Handler
+func (action DisputeShowAction) ServeHTTPC(c web.C, w http.ResponseWriter, r *http.Request) {
+	ap := &action.Action
+	ap.Prepare(c, w, r)
+	ap.Execute(&action)
+}
+
 // ServeHTTPC is a method for web.Handler
 func (action EffectIndexAction) ServeHTTPC(c web.C, w http.ResponseWriter, r *http.Request) {
 	ap := &action.Action
@@ -180,6 +222,13 @@ func (action RateLimitExceededAction) ServeHTTPC(c web.C, w http.ResponseWriter,
 	ap.Execute(&action)
 }
 
+// ServeHTTPC is a method for web.Handler
+func (action ReversalWindowIndexAction) ServeHTTPC(c web.C, w http.ResponseWriter, r *http.Request) {
+	ap := &action.Action
+	ap.Prepare(c, w, r)
+	ap.Execute(&action)
+}
+
 // ServeHTTPC is a method for web.Handler
 func (action RootAction) ServeHTTPC(c web.C, w http.ResponseWriter, r *http.Request) {
 	ap := &action.Action
@@ -208,6 +257,13 @@ func (action TransactionIndexAction) ServeHTTPC(c web.C, w http.ResponseWriter,
 	ap.Execute(&action)
 }
 
+// ServeHTTPC is a method for web.Handler
+func (action TransactionScreeningAction) ServeHTTPC(c web.C, w http.ResponseWriter, r *http.Request) {
+	ap := &action.Action
+	ap.Prepare(c, w, r)
+	ap.Execute(&action)
+}
+
 // ServeHTTPC is a method for web.Handler
 func (action TransactionShowAction) ServeHTTPC(c web.C, w http.ResponseWriter, r *http.Request) {
 	ap := &action.Action
@@ -0,0 +1,66 @@
+package horizon
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"bitbucket.org/atticlab/horizon/log"
+	"bitbucket.org/atticlab/horizon/prometheus"
+	"github.com/rcrowley/go-metrics"
+	"gopkg.in/tylerb/graceful.v1"
+)
+
+// ServePrometheusMetrics renders the metrics registry and per route request
+// timers in the Prometheus text exposition format.
+func (a *App) ServePrometheusMetrics(w http.ResponseWriter, r *http.Request) {
+	a.UpdateMetrics(a.ctx)
+
+	w.Header().Set("Content-Type", prometheus.ContentType)
+	writer := prometheus.NewWriter(w, prometheus.Namespace)
+	writer.WriteRegistry(a.metrics)
+	a.web.routeMetrics.Each(func(labels requestLabels, timer metrics.Timer) {
+		writer.WriteMetric("http.requests", prometheus.Labels{
+			"route":  labels.Route,
+			"method": labels.Method,
+			"status": labels.Status,
+		}, timer)
+	})
+
+	err := writer.Flush()
+	if err != nil {
+		log.WithField("err", err).Warn("Failed to write prometheus metrics")
+	}
+}
+
+// newAdminServer creates server of prometheus metrics, log levels and health
+// checks on the admin port, so metrics and log levels are not exposed on the
+// public port. Without admin port prometheus metrics are served on the public
+// port as /metrics?format=prometheus. Server is stopped on app shutdown.
+func (a *App) newAdminServer() *graceful.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", a.ServePrometheusMetrics)
+	mux.HandleFunc("/log/levels", a.ServeLogLevels)
+	mux.HandleFunc("/health", a.ServeLiveness)
+	mux.HandleFunc("/ready", a.ServeReadiness)
+
+	return &graceful.Server{
+		Timeout: 10 * time.Second,
+		// public server handles signals and shuts the app down
+		NoSignalHandling: true,
+		Server: &http.Server{
+			Addr:    fmt.Sprintf(":%d", a.config.AdminPort),
+			Handler: mux,
+		},
+	}
+}
+
+func (a *App) serveAdmin() {
+	log.Infof("Starting admin server on %s", a.adminServer.Addr)
+	err := a.adminServer.ListenAndServe()
+	if err != nil {
+		log.WithField("err", err).Error("Admin server stopped")
+		return
+	}
+	log.Info("Admin server stopped")
+}
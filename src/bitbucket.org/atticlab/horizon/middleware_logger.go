@@ -8,6 +8,7 @@ import (
 
 	gctx "github.com/goji/context"
 	"bitbucket.org/atticlab/horizon/log"
+	"bitbucket.org/atticlab/horizon/tracing"
 	"github.com/zenazn/goji/web"
 	"github.com/zenazn/goji/web/middleware"
 	"github.com/zenazn/goji/web/mutil"
@@ -21,6 +22,9 @@ func LoggerMiddleware(c *web.C, h http.Handler) http.Handler {
 		mw := mutil.WrapWriter(w)
 
 		logger := log.WithField("req", middleware.GetReqID(*c))
+		if span := tracing.FromContext(ctx); span != nil {
+			logger = logger.WithField("trace_id", span.TraceID())
+		}
 
 		ctx = log.Set(ctx, logger)
 		gctx.Set(c, ctx)
@@ -0,0 +1,19 @@
+package horizon
+
+import (
+	"net/http"
+
+	"github.com/zenazn/goji/web"
+)
+
+// peerAddrEnvKey is a key of goji env holding address of the peer
+const peerAddrEnvKey = "peer_addr"
+
+// peerAddrMiddleware keeps address of the peer before it's replaced with
+// X-Forwarded-For by xff middleware, which can't be trusted for limits
+func peerAddrMiddleware(c *web.C, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.Env[peerAddrEnvKey] = r.RemoteAddr
+		h.ServeHTTP(w, r)
+	})
+}
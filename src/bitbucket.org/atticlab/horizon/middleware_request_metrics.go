@@ -2,11 +2,78 @@ package horizon
 
 import (
 	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
 
+	"github.com/rcrowley/go-metrics"
 	"github.com/zenazn/goji/web"
 	"github.com/zenazn/goji/web/mutil"
 )
 
+// requestLabels are dimensions of request metrics
+type requestLabels struct {
+	Route  string
+	Method string
+	Status string
+}
+
+type byRequestLabels []requestLabels
+
+func (a byRequestLabels) Len() int      { return len(a) }
+func (a byRequestLabels) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a byRequestLabels) Less(i, j int) bool {
+	if a[i].Route != a[j].Route {
+		return a[i].Route < a[j].Route
+	}
+	if a[i].Method != a[j].Method {
+		return a[i].Method < a[j].Method
+	}
+	return a[i].Status < a[j].Status
+}
+
+// routeMetrics keeps request timers per route, method and response status
+type routeMetrics struct {
+	lock   sync.Mutex
+	timers map[requestLabels]metrics.Timer
+}
+
+func newRouteMetrics() *routeMetrics {
+	return &routeMetrics{
+		timers: make(map[requestLabels]metrics.Timer),
+	}
+}
+
+// Timer returns timer of requests with labels, creating it if needed
+func (m *routeMetrics) Timer(labels requestLabels) metrics.Timer {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	timer, ok := m.timers[labels]
+	if !ok {
+		timer = metrics.NewTimer()
+		m.timers[labels] = timer
+	}
+	return timer
+}
+
+// Each calls fn for each timer in order of labels
+func (m *routeMetrics) Each(fn func(labels requestLabels, timer metrics.Timer)) {
+	m.lock.Lock()
+	labels := make([]requestLabels, 0, len(m.timers))
+	for l := range m.timers {
+		labels = append(labels, l)
+	}
+	m.lock.Unlock()
+
+	sort.Sort(byRequestLabels(labels))
+
+	for _, l := range labels {
+		fn(l, m.Timer(l))
+	}
+}
+
 // Middleware that records metrics.
 //
 // It records success and failures using a meter, and times every request
@@ -15,10 +82,22 @@ func requestMetricsMiddleware(c *web.C, h http.Handler) http.Handler {
 		app := c.Env["app"].(*App)
 		mw := mutil.WrapWriter(w)
 
+		then := time.Now()
 		app.web.requestTimer.Time(func() {
 			h.ServeHTTP(mw.(http.ResponseWriter), r)
 		})
 
+		// route is known after request is routed by the last middleware
+		route := "unmatched"
+		if pattern, ok := web.GetMatch(*c).RawPattern().(string); ok {
+			route = pattern
+		}
+		app.web.routeMetrics.Timer(requestLabels{
+			Route:  route,
+			Method: r.Method,
+			Status: strconv.Itoa(mw.Status()),
+		}).UpdateSince(then)
+
 		if 200 <= mw.Status() && mw.Status() < 400 {
 			// a success is in [200, 400)
 			app.web.successMeter.Mark(1)
@@ -0,0 +1,46 @@
+package horizon
+
+import (
+	"fmt"
+	"net/http"
+
+	"bitbucket.org/atticlab/horizon/tracing"
+	gctx "github.com/goji/context"
+	"github.com/zenazn/goji/web"
+	"github.com/zenazn/goji/web/middleware"
+	"github.com/zenazn/goji/web/mutil"
+)
+
+// tracingMiddleware starts server span of the request, continuing trace of
+// the client if `traceparent` header is set. Span is bound to request's
+// context, so spans of db queries and txsub are its children.
+func tracingMiddleware(c *web.C, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !tracing.IsEnabled() {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := tracing.Extract(gctx.FromC(*c), r.Header)
+		ctx, span := tracing.StartWithKind(ctx, "HTTP "+r.Method, tracing.SpanKindServer)
+		defer span.End()
+		gctx.Set(c, ctx)
+
+		span.SetAttribute("http.method", r.Method)
+		span.SetAttribute("http.target", r.URL.Path)
+		span.SetAttribute("http.request_id", middleware.GetReqID(*c))
+
+		mw := mutil.WrapWriter(w)
+		h.ServeHTTP(mw, r)
+
+		// route is known after request is routed by the last middleware
+		if pattern, ok := web.GetMatch(*c).RawPattern().(string); ok {
+			span.SetAttribute("http.route", pattern)
+			span.Name = fmt.Sprintf("HTTP %s %s", r.Method, pattern)
+		}
+		span.SetAttribute("http.status_code", mw.Status())
+		if mw.Status() >= 500 {
+			span.SetError(fmt.Errorf("request failed with status %d", mw.Status()))
+		}
+	})
+}
@@ -0,0 +1,192 @@
+// Package options contains the registry of system options which can be changed
+// at runtime by administrative operations. Each option has a typed definition;
+// its effective value is taken from the `options` table, falling back to the
+// startup configuration and then to the built-in default.
+package options
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"bitbucket.org/atticlab/go-smart-base/amount"
+	"bitbucket.org/atticlab/go-smart-base/xdr"
+	conf "bitbucket.org/atticlab/horizon/config"
+	"bitbucket.org/atticlab/horizon/db2/history"
+)
+
+const (
+	MaxReversalDuration        = history.OPTIONS_MAX_REVERSAL_DURATION
+	StatisticsTimeout          = "statistics_timeout"
+	ProcessedOpTimeout         = "processed_op_timeout"
+	SubmissionTimeout          = "submission_timeout"
+	AnonymousMaxDailyOutcome   = "anonymous_max_daily_outcome"
+	AnonymousMaxMonthlyOutcome = "anonymous_max_monthly_outcome"
+	AnonymousMaxAnnualOutcome  = "anonymous_max_annual_outcome"
+	AnonymousMaxBalance        = "anonymous_max_balance"
+)
+
+// Type defines how value of the option is parsed and stored
+type Type string
+
+const (
+	// TypeDuration values are stored as number of seconds. Go duration strings
+	// (e.g. "2m30s") are accepted as well
+	TypeDuration Type = "duration"
+	// TypeAmount values are stored as amount strings (e.g. "500.0000000")
+	TypeAmount Type = "amount"
+)
+
+// Source describes where effective value of the option comes from
+type Source string
+
+const (
+	SourceDefault  Source = "default"
+	SourceConfig   Source = "config"
+	SourceDatabase Source = "database"
+)
+
+// Definition describes option which can be changed at runtime. Values are
+// kept in option units: seconds for durations, stroops for amounts.
+type Definition struct {
+	Name        string
+	Type        Type
+	Description string
+	// Min is the lowest valid value
+	Min int64
+	// Default is used if option is neither configured nor stored
+	Default int64
+
+	// config returns value from startup configuration, zero means not configured
+	config func(config *conf.Config) int64
+}
+
+// Definitions lists all known options
+var Definitions = []Definition{
+	{
+		Name:        MaxReversalDuration,
+		Type:        TypeDuration,
+		Description: "Period within which payment can be reversed",
+		Default:     int64(24 * time.Hour / time.Second),
+	},
+	{
+		Name:        StatisticsTimeout,
+		Type:        TypeDuration,
+		Description: "Expiration of account statistics cached in redis",
+		Min:         1,
+		Default:     60,
+		config: func(config *conf.Config) int64 {
+			return int64(config.StatisticsTimeout / time.Second)
+		},
+	},
+	{
+		Name:        ProcessedOpTimeout,
+		Type:        TypeDuration,
+		Description: "Expiration of processed operation marks stored in redis",
+		Min:         1,
+		Default:     30,
+		config: func(config *conf.Config) int64 {
+			return int64(config.ProcessedOpTimeout / time.Second)
+		},
+	},
+	{
+		Name:        SubmissionTimeout,
+		Type:        TypeDuration,
+		Description: "Period submitted transaction waits for result before submission times out",
+		Min:         1,
+		Default:     60,
+	},
+	{
+		Name:        AnonymousMaxDailyOutcome,
+		Type:        TypeAmount,
+		Description: "Maximum daily outcome limit for anonymous users",
+		config: func(config *conf.Config) int64 {
+			return config.AnonymousUserRestrictions.MaxDailyOutcome
+		},
+	},
+	{
+		Name:        AnonymousMaxMonthlyOutcome,
+		Type:        TypeAmount,
+		Description: "Maximum monthly outcome limit for anonymous users",
+		config: func(config *conf.Config) int64 {
+			return config.AnonymousUserRestrictions.MaxMonthlyOutcome
+		},
+	},
+	{
+		Name:        AnonymousMaxAnnualOutcome,
+		Type:        TypeAmount,
+		Description: "Maximum annual outcome limit for anonymous users",
+		config: func(config *conf.Config) int64 {
+			return config.AnonymousUserRestrictions.MaxAnnualOutcome
+		},
+	},
+	{
+		Name:        AnonymousMaxBalance,
+		Type:        TypeAmount,
+		Description: "Maximum balance of anonymous users",
+		config: func(config *conf.Config) int64 {
+			return config.AnonymousUserRestrictions.MaxBalance
+		},
+	},
+}
+
+// Find returns definition of the option with specified name
+func Find(name string) (Definition, bool) {
+	for _, definition := range Definitions {
+		if definition.Name == name {
+			return definition, true
+		}
+	}
+	return Definition{}, false
+}
+
+// Parse parses and validates raw value of the option
+func (d *Definition) Parse(raw string) (int64, error) {
+	var value int64
+	switch d.Type {
+	case TypeDuration:
+		seconds, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			duration, durationErr := time.ParseDuration(raw)
+			if durationErr != nil {
+				return 0, errors.New("must be number of seconds or duration string")
+			}
+			seconds = int64(duration / time.Second)
+		}
+		value = seconds
+	case TypeAmount:
+		parsed, err := amount.Parse(raw)
+		if err != nil {
+			return 0, errors.New("must be valid amount")
+		}
+		value = int64(parsed)
+	default:
+		return 0, fmt.Errorf("unknown option type %s", d.Type)
+	}
+
+	if value < d.Min {
+		return 0, fmt.Errorf("must not be less than %s", d.Format(d.Min))
+	}
+	return value, nil
+}
+
+// Format converts value of the option to the form it is stored in
+func (d *Definition) Format(value int64) string {
+	if d.Type == TypeAmount {
+		return amount.String(xdr.Int64(value))
+	}
+	return strconv.FormatInt(value, 10)
+}
+
+// Value is effective value of the option
+type Value struct {
+	Definition
+	Value  int64
+	Source Source
+}
+
+// String returns value in the form it is stored in
+func (v *Value) String() string {
+	return v.Format(v.Value)
+}
@@ -0,0 +1,79 @@
+package options
+
+import (
+	"testing"
+	"time"
+
+	conf "bitbucket.org/atticlab/horizon/config"
+	"bitbucket.org/atticlab/horizon/db2/history"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestOptions(t *testing.T) {
+	Convey("Parse:", t, func() {
+		duration, _ := Find(StatisticsTimeout)
+		value, err := duration.Parse("120")
+		So(err, ShouldBeNil)
+		So(value, ShouldEqual, 120)
+		value, err = duration.Parse("2m30s")
+		So(err, ShouldBeNil)
+		So(value, ShouldEqual, 150)
+		_, err = duration.Parse("0")
+		So(err, ShouldNotBeNil)
+		_, err = duration.Parse("soon")
+		So(err, ShouldNotBeNil)
+
+		amount, _ := Find(AnonymousMaxBalance)
+		value, err = amount.Parse("14000.5")
+		So(err, ShouldBeNil)
+		So(value, ShouldEqual, 140005000000)
+		So(amount.Format(value), ShouldEqual, "14000.5000000")
+		_, err = amount.Parse("-1")
+		So(err, ShouldNotBeNil)
+
+		_, ok := Find("unknown")
+		So(ok, ShouldBeFalse)
+	})
+	Convey("Registry:", t, func() {
+		historyQ := &history.QMock{}
+		config := &conf.Config{
+			StatisticsTimeout: 90 * time.Second,
+			AnonymousUserRestrictions: conf.AnonymousUserRestrictions{
+				MaxBalance: 100,
+			},
+		}
+		historyQ.On("OptionsAll").Return([]history.Options{
+			{Name: StatisticsTimeout, Data: "300"},
+			{Name: ProcessedOpTimeout, Data: "invalid"},
+			{Name: "removed_option", Data: "1"},
+		}, nil).Once()
+
+		registry := NewRegistry(historyQ, config, nil)
+		reloaded := 0
+		registry.OnReload(func() {
+			reloaded++
+		})
+		So(registry.Duration(StatisticsTimeout, 0), ShouldEqual, 90*time.Second)
+		So(registry.Reload(), ShouldBeNil)
+		So(reloaded, ShouldEqual, 1)
+
+		value, ok := registry.Get(StatisticsTimeout)
+		So(ok, ShouldBeTrue)
+		So(value.Source, ShouldEqual, SourceDatabase)
+		So(value.String(), ShouldEqual, "300")
+		So(registry.Duration(StatisticsTimeout, 0), ShouldEqual, 5*time.Minute)
+
+		value, _ = registry.Get(ProcessedOpTimeout)
+		So(value.Source, ShouldEqual, SourceDefault)
+		So(value.Value, ShouldEqual, 30)
+
+		value, _ = registry.Get(AnonymousMaxBalance)
+		So(value.Source, ShouldEqual, SourceConfig)
+		So(registry.AnonymousUserRestrictions(conf.AnonymousUserRestrictions{}).MaxBalance, ShouldEqual, 100)
+
+		So(len(registry.Values()), ShouldEqual, len(Definitions))
+
+		var empty *Registry
+		So(empty.Duration(SubmissionTimeout, time.Second), ShouldEqual, time.Second)
+	})
+}
@@ -0,0 +1,255 @@
+package options
+
+import (
+	"sync"
+	"time"
+
+	conf "bitbucket.org/atticlab/horizon/config"
+	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/log"
+	"github.com/garyburd/redigo/redis"
+	"golang.org/x/net/context"
+)
+
+const (
+	// Channel is the redis pub/sub channel used to notify horizon instances
+	// that options were changed
+	Channel = "horizon:options"
+	// reconnectDelay is the time to wait before restoring lost subscription
+	reconnectDelay = 5 * time.Second
+)
+
+// Registry holds effective values of the options. Values stored in db are
+// reloaded on notifications published to the redis channel.
+type Registry struct {
+	historyQ history.QInterface
+	pool     *redis.Pool
+	log      *log.Entry
+
+	lock       sync.RWMutex
+	configured map[string]int64
+	stored     map[string]int64
+	// called after each reload, used to drop data cached by horizon instance
+	onReload []func()
+}
+
+// NewRegistry creates registry with values from startup configuration. Call
+// Reload to load values stored in db.
+func NewRegistry(historyQ history.QInterface, config *conf.Config, pool *redis.Pool) *Registry {
+	configured := make(map[string]int64)
+	for _, definition := range Definitions {
+		if config == nil || definition.config == nil {
+			continue
+		}
+
+		value := definition.config(config)
+		if value != 0 {
+			configured[definition.Name] = value
+		}
+	}
+
+	return &Registry{
+		historyQ:   historyQ,
+		pool:       pool,
+		log:        log.WithField("service", "options"),
+		configured: configured,
+		stored:     make(map[string]int64),
+	}
+}
+
+// OnReload registers fn to be called after each successful reload, including
+// reloads on notifications from other horizon instances. Must be called before
+// Listen.
+func (r *Registry) OnReload(fn func()) {
+	r.onReload = append(r.onReload, fn)
+}
+
+// Reload loads values of the options stored in db. Invalid and unknown values
+// are logged and ignored.
+func (r *Registry) Reload() error {
+	options, err := r.historyQ.OptionsAll()
+	if err != nil {
+		return err
+	}
+
+	stored := make(map[string]int64)
+	for _, option := range options {
+		definition, ok := Find(option.Name)
+		if !ok {
+			r.log.WithField("option", option.Name).Debug("Ignoring unknown stored option")
+			continue
+		}
+
+		value, err := definition.Parse(option.Data)
+		if err != nil {
+			r.log.WithField("option", definition.Name).WithError(err).Error("Ignoring invalid stored option")
+			continue
+		}
+		stored[definition.Name] = value
+	}
+
+	r.lock.Lock()
+	r.stored = stored
+	r.lock.Unlock()
+
+	for _, fn := range r.onReload {
+		fn()
+	}
+	return nil
+}
+
+// Get returns effective value of the option
+func (r *Registry) Get(name string) (Value, bool) {
+	definition, ok := Find(name)
+	if !ok {
+		return Value{}, false
+	}
+
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.value(definition), true
+}
+
+// Values returns effective values of all options
+func (r *Registry) Values() []Value {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	result := make([]Value, 0, len(Definitions))
+	for _, definition := range Definitions {
+		result = append(result, r.value(definition))
+	}
+	return result
+}
+
+func (r *Registry) value(definition Definition) Value {
+	if value, ok := r.stored[definition.Name]; ok {
+		return Value{Definition: definition, Value: value, Source: SourceDatabase}
+	}
+
+	if value, ok := r.configured[definition.Name]; ok {
+		return Value{Definition: definition, Value: value, Source: SourceConfig}
+	}
+
+	return Value{Definition: definition, Value: definition.Default, Source: SourceDefault}
+}
+
+// Int64 returns value of the option. Returns fallback if registry is nil.
+func (r *Registry) Int64(name string, fallback int64) int64 {
+	if r == nil {
+		return fallback
+	}
+
+	value, ok := r.Get(name)
+	if !ok {
+		return fallback
+	}
+	return value.Value
+}
+
+// Duration returns value of the duration option. Returns fallback if registry is nil.
+func (r *Registry) Duration(name string, fallback time.Duration) time.Duration {
+	if r == nil {
+		return fallback
+	}
+	return time.Duration(r.Int64(name, int64(fallback/time.Second))) * time.Second
+}
+
+// AnonymousUserRestrictions returns limits for anonymous users. Returns
+// fallback if registry is nil.
+func (r *Registry) AnonymousUserRestrictions(fallback conf.AnonymousUserRestrictions) conf.AnonymousUserRestrictions {
+	return conf.AnonymousUserRestrictions{
+		MaxDailyOutcome:   r.Int64(AnonymousMaxDailyOutcome, fallback.MaxDailyOutcome),
+		MaxMonthlyOutcome: r.Int64(AnonymousMaxMonthlyOutcome, fallback.MaxMonthlyOutcome),
+		MaxAnnualOutcome:  r.Int64(AnonymousMaxAnnualOutcome, fallback.MaxAnnualOutcome),
+		MaxBalance:        r.Int64(AnonymousMaxBalance, fallback.MaxBalance),
+	}
+}
+
+// Publish reloads options and notifies other horizon instances to reload them
+// and drop cached data. Must be called after changes to the options or assets
+// are committed.
+func (r *Registry) Publish() error {
+	err := r.Reload()
+	if err != nil {
+		return err
+	}
+
+	if r.pool == nil {
+		// redis is not used, other instances reload options on restart
+		return nil
+	}
+
+	conn := r.pool.Get()
+	defer conn.Close()
+	_, err = conn.Do("PUBLISH", Channel, "reload")
+	return err
+}
+
+// Listen reloads options on notifications until ctx is done. Options are also
+// reloaded each time subscription is (re)established, so notifications
+// published while connection was lost are not missed.
+func (r *Registry) Listen(ctx context.Context) {
+	if r.pool == nil {
+		return
+	}
+
+	for {
+		err := r.listen(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		r.log.WithError(err).Error("Lost options notifications subscription")
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+func (r *Registry) listen(ctx context.Context) error {
+	conn := redis.PubSubConn{Conn: r.pool.Get()}
+	defer conn.Close()
+
+	err := conn.Subscribe(Channel)
+	if err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Unsubscribe()
+		case <-done:
+		}
+	}()
+
+	for {
+		switch msg := conn.Receive().(type) {
+		case redis.Message:
+			r.reload()
+		case redis.Subscription:
+			if msg.Count == 0 {
+				return nil
+			}
+			r.reload()
+		case error:
+			return msg
+		}
+	}
+}
+
+func (r *Registry) reload() {
+	err := r.Reload()
+	if err != nil {
+		r.log.WithError(err).Error("Failed to reload options")
+		return
+	}
+	r.log.Debug("Options reloaded")
+}
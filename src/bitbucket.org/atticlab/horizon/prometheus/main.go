@@ -0,0 +1,194 @@
+// Package prometheus renders metrics of the go-metrics registry in the
+// Prometheus text exposition format.
+package prometheus
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+// ContentType is a content type of the text exposition format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Namespace prefixes names of all horizon metrics
+const Namespace = "horizon"
+
+var quantiles = []float64{0.5, 0.75, 0.95, 0.99, 0.999}
+
+// Labels are dimensions of the metric
+type Labels map[string]string
+
+// Writer writes metrics in the text exposition format. All samples of the
+// metric must be written one after another.
+type Writer struct {
+	w         *bufio.Writer
+	namespace string
+	// types of metrics already written
+	types map[string]string
+	err   error
+}
+
+// NewWriter creates new writer. Names of the metrics are prefixed with namespace.
+func NewWriter(w io.Writer, namespace string) *Writer {
+	return &Writer{
+		w:         bufio.NewWriter(w),
+		namespace: namespace,
+		types:     make(map[string]string),
+	}
+}
+
+// WriteRegistry writes all metrics of the registry in order of names
+func (w *Writer) WriteRegistry(registry metrics.Registry) {
+	all := make(map[string]interface{})
+	registry.Each(func(name string, metric interface{}) {
+		all[name] = metric
+	})
+
+	names := make([]string, 0, len(all))
+	for name := range all {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		w.WriteMetric(name, nil, all[name])
+	}
+}
+
+// WriteMetric writes go-metrics metric. Timers are written as summaries in
+// seconds, meters as counters, counters and gauges as gauges.
+func (w *Writer) WriteMetric(name string, labels Labels, metric interface{}) {
+	name = Name(w.namespace, name)
+	switch m := metric.(type) {
+	case metrics.Counter:
+		w.writeSample(name, "gauge", labels, float64(m.Count()))
+	case metrics.Gauge:
+		w.writeSample(name, "gauge", labels, float64(m.Value()))
+	case metrics.GaugeFloat64:
+		w.writeSample(name, "gauge", labels, m.Value())
+	case metrics.Healthcheck:
+		m.Check()
+		value := 1.0
+		if m.Error() != nil {
+			value = 0
+		}
+		w.writeSample(name, "gauge", labels, value)
+	case metrics.Meter:
+		w.writeSample(name+"_total", "counter", labels, float64(m.Snapshot().Count()))
+	case metrics.Histogram:
+		h := m.Snapshot()
+		w.writeSummary(name, labels, h.Percentiles(quantiles), float64(h.Sum()), h.Count())
+	case metrics.Timer:
+		t := m.Snapshot()
+		seconds := float64(time.Second)
+		ps := t.Percentiles(quantiles)
+		for i := range ps {
+			ps[i] /= seconds
+		}
+		w.writeSummary(name+"_seconds", labels, ps, float64(t.Sum())/seconds, t.Count())
+	}
+}
+
+// Flush writes buffered data and returns the first error occurred
+func (w *Writer) Flush() error {
+	if w.err != nil {
+		return w.err
+	}
+	return w.w.Flush()
+}
+
+func (w *Writer) writeSummary(name string, labels Labels, ps []float64, sum float64, count int64) {
+	w.writeType(name, "summary")
+	for i, q := range quantiles {
+		w.writeLine(name, withLabel(labels, "quantile", strconv.FormatFloat(q, 'g', -1, 64)), ps[i])
+	}
+	w.writeLine(name+"_sum", labels, sum)
+	w.writeLine(name+"_count", labels, float64(count))
+}
+
+func (w *Writer) writeSample(name, typ string, labels Labels, value float64) {
+	w.writeType(name, typ)
+	w.writeLine(name, labels, value)
+}
+
+func (w *Writer) writeType(name, typ string) {
+	if _, ok := w.types[name]; ok {
+		return
+	}
+	w.types[name] = typ
+	w.printf("# TYPE %s %s\n", name, typ)
+}
+
+func (w *Writer) writeLine(name string, labels Labels, value float64) {
+	w.printf("%s%s %s\n", name, formatLabels(labels), formatValue(value))
+}
+
+func (w *Writer) printf(format string, args ...interface{}) {
+	if w.err != nil {
+		return
+	}
+	_, w.err = fmt.Fprintf(w.w, format, args...)
+}
+
+// Name converts go-metrics name to prometheus metric name, e.g.
+// `txsub.buffered` to `horizon_txsub_buffered`
+func Name(namespace, name string) string {
+	if namespace != "" {
+		name = namespace + "_" + name
+	}
+
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == ':':
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+func withLabel(labels Labels, name, value string) Labels {
+	result := make(Labels, len(labels)+1)
+	for k, v := range labels {
+		result[k] = v
+	}
+	result[name] = value
+	return result
+}
+
+func formatLabels(labels Labels) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", Name("", name), strconv.Quote(labels[name])))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsNaN(value):
+		return "NaN"
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,58 @@
+package prometheus
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestWriter(t *testing.T) {
+	Convey("Name", t, func() {
+		So(Name(Namespace, "txsub.buffered"), ShouldEqual, "horizon_txsub_buffered")
+		So(Name("", "99.9%"), ShouldEqual, "99_9_")
+	})
+	Convey("Registry", t, func() {
+		registry := metrics.NewRegistry()
+		gauge := metrics.NewGauge()
+		gauge.Update(3)
+		registry.Register("txsub.open", gauge)
+		meter := metrics.NewMeter()
+		meter.Mark(2)
+		registry.Register("txsub.failed", meter)
+		timer := metrics.NewTimer()
+		timer.Update(2 * time.Second)
+		registry.Register("requests.total", timer)
+
+		var out bytes.Buffer
+		w := NewWriter(&out, Namespace)
+		w.WriteRegistry(registry)
+		So(w.Flush(), ShouldBeNil)
+
+		result := out.String()
+		So(result, ShouldContainSubstring, "# TYPE horizon_txsub_open gauge\nhorizon_txsub_open 3\n")
+		So(result, ShouldContainSubstring, "# TYPE horizon_txsub_failed_total counter\nhorizon_txsub_failed_total 2\n")
+		So(result, ShouldContainSubstring, "# TYPE horizon_requests_total_seconds summary\n")
+		So(result, ShouldContainSubstring, "horizon_requests_total_seconds{quantile=\"0.5\"} 2\n")
+		So(result, ShouldContainSubstring, "horizon_requests_total_seconds_sum 2\n")
+		So(result, ShouldContainSubstring, "horizon_requests_total_seconds_count 1\n")
+		// metrics are sorted by name
+		So(strings.Index(result, "requests_total"), ShouldBeLessThan, strings.Index(result, "txsub_failed"))
+	})
+	Convey("Labels", t, func() {
+		var out bytes.Buffer
+		w := NewWriter(&out, Namespace)
+		for _, status := range []string{"200", "404"} {
+			counter := metrics.NewCounter()
+			counter.Inc(1)
+			w.WriteMetric("requests", Labels{"status": status, "route": "/accounts/:id"}, counter)
+		}
+		So(w.Flush(), ShouldBeNil)
+		So(out.String(), ShouldEqual, "# TYPE horizon_requests gauge\n"+
+			"horizon_requests{route=\"/accounts/:id\",status=\"200\"} 1\n"+
+			"horizon_requests{route=\"/accounts/:id\",status=\"404\"} 1\n")
+	})
+}
@@ -0,0 +1,72 @@
+package horizon
+
+import (
+	"time"
+
+	"bitbucket.org/atticlab/horizon/log"
+	"bitbucket.org/atticlab/horizon/redis"
+	"github.com/PuerkitoBio/throttled"
+)
+
+// degradableRateLimitStore is a rate limit store, which lets all requests
+// through while redis is unavailable, so api keeps working in degrade mode.
+type degradableRateLimitStore struct {
+	store throttled.GCRAStore
+	log   *log.Entry
+}
+
+func newDegradableRateLimitStore(store throttled.GCRAStore) *degradableRateLimitStore {
+	return &degradableRateLimitStore{
+		store: store,
+		log:   log.WithField("service", "rate_limit_store"),
+	}
+}
+
+func (s *degradableRateLimitStore) isDegraded(err error) bool {
+	if err == nil && redis.IsAvailable() {
+		return false
+	}
+
+	if err != nil && !redis.IsUnavailable(err) {
+		return false
+	}
+
+	s.log.WithError(err).Debug("Redis is unavailable, skipping rate limit")
+	return true
+}
+
+func (s *degradableRateLimitStore) GetWithTime(key string) (int64, time.Time, error) {
+	if s.isDegraded(nil) {
+		return -1, time.Now(), nil
+	}
+
+	value, now, err := s.store.GetWithTime(key)
+	if s.isDegraded(err) {
+		return -1, time.Now(), nil
+	}
+	return value, now, err
+}
+
+func (s *degradableRateLimitStore) SetIfNotExistsWithTTL(key string, value int64, ttl time.Duration) (bool, error) {
+	if s.isDegraded(nil) {
+		return true, nil
+	}
+
+	isSet, err := s.store.SetIfNotExistsWithTTL(key, value, ttl)
+	if s.isDegraded(err) {
+		return true, nil
+	}
+	return isSet, err
+}
+
+func (s *degradableRateLimitStore) CompareAndSwapWithTTL(key string, old, new int64, ttl time.Duration) (bool, error) {
+	if s.isDegraded(nil) {
+		return true, nil
+	}
+
+	isSwapped, err := s.store.CompareAndSwapWithTTL(key, old, new, ttl)
+	if s.isDegraded(err) {
+		return true, nil
+	}
+	return isSwapped, err
+}
@@ -0,0 +1,481 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/garyburd/redigo/redis"
+)
+
+const cluster_slots = 16384
+
+// refreshInterval is the minimal time between background reloads of the slots
+// map triggered by redirects
+const refreshInterval = time.Second
+
+var errCrossSlot = errors.New("CROSSSLOT keys of the transaction must be stored in the same slot")
+
+// cluster keeps map of slots to master nodes of Redis Cluster
+type cluster struct {
+	seeds    []string
+	password string
+
+	mu    sync.RWMutex
+	slots [cluster_slots]string
+
+	// guards background refresh, so burst of redirects causes single reload
+	refreshMu   sync.Mutex
+	refreshing  bool
+	refreshedAt time.Time
+}
+
+func newCluster(seeds []string, password string) *cluster {
+	return &cluster{
+		seeds:    append([]string{}, seeds...),
+		password: password,
+	}
+}
+
+// Refresh loads slots map from the first responsive node
+func (c *cluster) Refresh() error {
+	var lastErr error
+	for _, addr := range c.nodes() {
+		slots, err := c.loadSlots(addr)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		c.mu.Lock()
+		c.slots = slots
+		c.mu.Unlock()
+		return nil
+	}
+
+	if lastErr == nil {
+		lastErr = errors.New("no cluster nodes configured")
+	}
+	return fmt.Errorf("failed to load cluster slots: %s", lastErr.Error())
+}
+
+// refreshAsync reloads slots map in background, unless reload is in progress
+// or was started less than refreshInterval ago
+func (c *cluster) refreshAsync() {
+	c.refreshMu.Lock()
+	if c.refreshing || time.Since(c.refreshedAt) < refreshInterval {
+		c.refreshMu.Unlock()
+		return
+	}
+	c.refreshing = true
+	c.refreshedAt = time.Now()
+	c.refreshMu.Unlock()
+
+	go func() {
+		defer func() {
+			c.refreshMu.Lock()
+			c.refreshing = false
+			c.refreshMu.Unlock()
+		}()
+		c.Refresh()
+	}()
+}
+
+// Masters returns addresses of all known masters
+func (c *cluster) Masters() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var result []string
+	known := make(map[string]bool)
+	for _, addr := range c.slots {
+		if addr != "" && !known[addr] {
+			known[addr] = true
+			result = append(result, addr)
+		}
+	}
+	return result
+}
+
+// nodes returns known masters followed by seed nodes
+func (c *cluster) nodes() []string {
+	return append(c.Masters(), c.seeds...)
+}
+
+func (c *cluster) nodeBySlot(slot int) string {
+	c.mu.RLock()
+	addr := c.slots[slot]
+	c.mu.RUnlock()
+	if addr == "" {
+		return c.seeds[0]
+	}
+	return addr
+}
+
+func (c *cluster) setSlot(slot int, addr string) {
+	c.mu.Lock()
+	c.slots[slot] = addr
+	c.mu.Unlock()
+}
+
+func (c *cluster) loadSlots(addr string) (slots [cluster_slots]string, err error) {
+	conn, err := dialNode(addr, c.password)
+	if err != nil {
+		return slots, err
+	}
+	defer conn.Close()
+
+	ranges, err := redis.Values(conn.Do("CLUSTER", "SLOTS"))
+	if err != nil {
+		return slots, err
+	}
+
+	for _, rawRange := range ranges {
+		slotRange, err := redis.Values(rawRange, nil)
+		if err != nil {
+			return slots, err
+		}
+
+		if len(slotRange) < 3 {
+			return slots, errors.New("unexpected CLUSTER SLOTS response")
+		}
+
+		start, err := redis.Int(slotRange[0], nil)
+		if err != nil {
+			return slots, err
+		}
+
+		end, err := redis.Int(slotRange[1], nil)
+		if err != nil {
+			return slots, err
+		}
+
+		master, err := redis.Values(slotRange[2], nil)
+		if err != nil || len(master) < 2 {
+			return slots, errors.New("unexpected CLUSTER SLOTS response")
+		}
+
+		host, err := redis.String(master[0], nil)
+		if err != nil {
+			return slots, err
+		}
+
+		port, err := redis.Int(master[1], nil)
+		if err != nil {
+			return slots, err
+		}
+
+		if start < 0 || end >= cluster_slots || start > end {
+			return slots, fmt.Errorf("invalid slot range %d-%d", start, end)
+		}
+
+		nodeAddr := net.JoinHostPort(host, strconv.Itoa(port))
+		for slot := start; slot <= end; slot++ {
+			slots[slot] = nodeAddr
+		}
+	}
+
+	return slots, nil
+}
+
+func (c *cluster) dial() (redis.Conn, error) {
+	return &clusterConn{
+		cluster: c,
+		conns:   make(map[string]redis.Conn),
+	}, nil
+}
+
+// clusterConn is a redis.Conn routing commands to master nodes owning slots
+// of the command keys. While WATCH, MULTI or pipeline is in progress, all
+// commands are sent to the same node, so all keys of the transaction must
+// share hash tag.
+type clusterConn struct {
+	cluster *cluster
+	conns   map[string]redis.Conn
+	// node of the transaction or pipeline in progress
+	bound    redis.Conn
+	watching bool
+	inMulti  bool
+	// MULTI is postponed until first keyed command selects the node
+	multiSent bool
+	err       error
+}
+
+func (c *clusterConn) node(addr string) (redis.Conn, error) {
+	conn, ok := c.conns[addr]
+	if ok && conn.Err() == nil {
+		return conn, nil
+	}
+
+	if ok {
+		conn.Close()
+		delete(c.conns, addr)
+	}
+
+	conn, err := dialNode(addr, c.cluster.password)
+	if err != nil {
+		return nil, err
+	}
+	c.conns[addr] = conn
+	return conn, nil
+}
+
+// nodeFor returns connection to the node command must be sent to
+func (c *clusterConn) nodeFor(commandName string, args []interface{}) (redis.Conn, error) {
+	if c.bound != nil {
+		return c.bound, nil
+	}
+
+	key, ok := commandKey(commandName, args)
+	if !ok {
+		return c.anyNode()
+	}
+	return c.node(c.cluster.nodeBySlot(keySlot(key)))
+}
+
+func (c *clusterConn) anyNode() (redis.Conn, error) {
+	for _, conn := range c.conns {
+		if conn.Err() == nil {
+			return conn, nil
+		}
+	}
+
+	var lastErr error
+	for _, addr := range c.cluster.nodes() {
+		conn, err := c.node(addr)
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+func (c *clusterConn) unbind() {
+	c.bound = nil
+	c.watching = false
+	c.inMulti = false
+	c.multiSent = false
+}
+
+func (c *clusterConn) Do(commandName string, args ...interface{}) (interface{}, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	command := strings.ToUpper(commandName)
+	switch command {
+	case "":
+		if c.bound == nil {
+			return nil, nil
+		}
+		if !c.inMulti && !c.watching {
+			defer c.unbind()
+		}
+		return c.bound.Do("")
+	case "MULTI":
+		c.inMulti = true
+		if c.bound == nil {
+			return "OK", nil
+		}
+		c.multiSent = true
+		return c.bound.Do(commandName, args...)
+	case "EXEC", "DISCARD":
+		defer c.unbind()
+		if !c.multiSent {
+			// transaction without keyed commands
+			if command == "EXEC" {
+				return []interface{}{}, nil
+			}
+			return "OK", nil
+		}
+		return c.bound.Do(commandName, args...)
+	case "UNWATCH":
+		if c.bound == nil {
+			return "OK", nil
+		}
+		if !c.inMulti {
+			defer c.unbind()
+		}
+		return c.bound.Do(commandName, args...)
+	case "WATCH":
+		if c.bound != nil {
+			key, _ := commandKey(command, args)
+			conn, err := c.node(c.cluster.nodeBySlot(keySlot(key)))
+			if err != nil {
+				return nil, err
+			}
+			if conn != c.bound {
+				return nil, errCrossSlot
+			}
+		}
+	}
+
+	conn, err := c.nodeFor(command, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if command == "WATCH" {
+		c.bound = conn
+		c.watching = true
+	}
+
+	if c.inMulti && !c.multiSent {
+		_, err = conn.Do("MULTI")
+		if err != nil {
+			return nil, err
+		}
+		c.bound = conn
+		c.multiSent = true
+	}
+
+	reply, err := conn.Do(commandName, args...)
+	addr, moved := c.handleRedirect(err)
+	if !moved || c.bound != nil {
+		return reply, err
+	}
+
+	// command outside of transaction can be safely repeated on the new owner
+	conn, err = c.node(addr)
+	if err != nil {
+		return nil, err
+	}
+	return conn.Do(commandName, args...)
+}
+
+// handleRedirect updates slots map on MOVED error and returns address of
+// the new owner of the slot
+func (c *clusterConn) handleRedirect(err error) (string, bool) {
+	redisErr, ok := err.(redis.Error)
+	if !ok {
+		return "", false
+	}
+
+	parts := strings.Fields(string(redisErr))
+	if len(parts) != 3 || parts[0] != "MOVED" {
+		return "", false
+	}
+
+	slot, convErr := strconv.Atoi(parts[1])
+	if convErr != nil || slot < 0 || slot >= cluster_slots {
+		return "", false
+	}
+
+	c.cluster.setSlot(slot, parts[2])
+	// whole map is likely to be changed, reload it in background
+	c.cluster.refreshAsync()
+	return parts[2], true
+}
+
+func (c *clusterConn) Send(commandName string, args ...interface{}) error {
+	if c.err != nil {
+		return c.err
+	}
+
+	conn, err := c.nodeFor(commandName, args)
+	if err != nil {
+		return err
+	}
+
+	// pipeline is bound to the node of its first command until it's flushed by Do("")
+	c.bound = conn
+	return conn.Send(commandName, args...)
+}
+
+func (c *clusterConn) Flush() error {
+	if c.bound == nil {
+		return nil
+	}
+	return c.bound.Flush()
+}
+
+func (c *clusterConn) Receive() (interface{}, error) {
+	if c.bound == nil {
+		return nil, errors.New("redigo: no pending commands")
+	}
+	return c.bound.Receive()
+}
+
+func (c *clusterConn) Err() error {
+	if c.err != nil {
+		return c.err
+	}
+
+	if c.bound != nil {
+		return c.bound.Err()
+	}
+	return nil
+}
+
+func (c *clusterConn) Close() error {
+	var err error
+	for addr, conn := range c.conns {
+		if closeErr := conn.Close(); closeErr != nil {
+			err = closeErr
+		}
+		delete(c.conns, addr)
+	}
+	c.unbind()
+	if c.err == nil {
+		c.err = errors.New("redigo: connection closed")
+	}
+	return err
+}
+
+// commandKey returns key of the command, or false if command has no keys
+func commandKey(commandName string, args []interface{}) (string, bool) {
+	keyIndex := 0
+	switch strings.ToUpper(commandName) {
+	case "", "PING", "AUTH", "ECHO", "TIME", "INFO", "ROLE", "CLUSTER", "SELECT", "QUIT",
+		"MULTI", "EXEC", "DISCARD", "UNWATCH", "PUBLISH", "SUBSCRIBE", "UNSUBSCRIBE",
+		"PSUBSCRIBE", "PUNSUBSCRIBE", "SCRIPT":
+		return "", false
+	case "EVAL", "EVALSHA":
+		keyIndex = 2
+	}
+
+	if len(args) <= keyIndex {
+		return "", false
+	}
+
+	switch key := args[keyIndex].(type) {
+	case string:
+		return key, true
+	case []byte:
+		return string(key), true
+	default:
+		return fmt.Sprint(key), true
+	}
+}
+
+// keySlot returns cluster slot of the key. If key contains hash tag, only tag
+// is hashed.
+func keySlot(key string) int {
+	if start := strings.IndexByte(key, '{'); start >= 0 {
+		if end := strings.IndexByte(key[start+1:], '}'); end > 0 {
+			key = key[start+1 : start+1+end]
+		}
+	}
+	return int(crc16([]byte(key)) % cluster_slots)
+}
+
+// crc16 implements CRC16-XMODEM used by Redis Cluster
+func crc16(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
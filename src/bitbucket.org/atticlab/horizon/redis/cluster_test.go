@@ -0,0 +1,50 @@
+package redis
+
+import (
+	"testing"
+
+	"bitbucket.org/atticlab/go-smart-base/keypair"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCluster(t *testing.T) {
+	Convey("keySlot", t, func() {
+		So(keySlot("foo"), ShouldEqual, 12182)
+		So(keySlot("123456789"), ShouldEqual, 12739)
+		So(keySlot("{user1000}.following"), ShouldEqual, keySlot("{user1000}.followers"))
+		// empty hash tag is ignored
+		So(keySlot("foo{}{bar}"), ShouldEqual, 8363)
+		So(keySlot("foo{bar}{zap}"), ShouldEqual, keySlot("bar"))
+	})
+	Convey("Statistics and processed op keys share slot", t, func() {
+		account, err := keypair.Random()
+		So(err, ShouldBeNil)
+		statsKey := GetAccountStatisticsKey(account.Address(), "UAH")
+		opKey := GetProcessedOpKey(account.Address(), "tx_hash", 1, true)
+		So(statsKey, ShouldEqual, "as:{"+account.Address()+"}:UAH")
+		So(keySlot(statsKey), ShouldEqual, keySlot(opKey))
+	})
+	Convey("Redirects trigger single refresh per interval", t, func() {
+		c := newCluster(nil, "")
+		c.refreshAsync()
+		c.refreshMu.Lock()
+		first := c.refreshedAt
+		c.refreshMu.Unlock()
+		So(first.IsZero(), ShouldBeFalse)
+
+		c.refreshAsync()
+		c.refreshMu.Lock()
+		So(c.refreshedAt, ShouldResemble, first)
+		c.refreshMu.Unlock()
+	})
+	Convey("commandKey", t, func() {
+		key, ok := commandKey("WATCH", []interface{}{"as:{a}:UAH"})
+		So(ok, ShouldBeTrue)
+		So(key, ShouldEqual, "as:{a}:UAH")
+		key, ok = commandKey("EVALSHA", []interface{}{"sha", 1, []byte("throttle:key")})
+		So(ok, ShouldBeTrue)
+		So(key, ShouldEqual, "throttle:key")
+		_, ok = commandKey("MULTI", nil)
+		So(ok, ShouldBeFalse)
+	})
+}
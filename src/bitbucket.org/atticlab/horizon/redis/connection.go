@@ -1,7 +1,11 @@
 package redis
 
 import (
+	"bitbucket.org/atticlab/horizon/tracing"
 	"github.com/garyburd/redigo/redis"
+	"golang.org/x/net/context"
+	"net"
+	"strings"
 	"time"
 )
 
@@ -57,6 +61,8 @@ type ConnectionInterface interface {
 
 type Connection struct {
 	redis.Conn
+	// ctx is parent of command spans, if set
+	ctx context.Context
 }
 
 func NewConnection(c redis.Conn) *Connection {
@@ -65,6 +71,32 @@ func NewConnection(c redis.Conn) *Connection {
 	}
 }
 
+// NewContextConnection returns connection, which records spans of commands
+// as children of the span in ctx
+func NewContextConnection(ctx context.Context, c redis.Conn) *Connection {
+	return &Connection{
+		Conn: c,
+		ctx:  ctx,
+	}
+}
+
+// Do sends command to redis and records span of it, if tracing is enabled.
+// Spans of commands sent on connection without context are roots of their own
+// traces.
+func (r *Connection) Do(commandName string, args ...interface{}) (interface{}, error) {
+	ctx := r.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	_, span := tracing.StartWithKind(ctx, "redis "+commandName, tracing.SpanKindClient)
+	span.SetAttribute("db.system", "redis")
+	span.SetAttribute("db.operation", commandName)
+	reply, err := r.Conn.Do(commandName, args...)
+	span.SetError(err)
+	span.End()
+	return reply, err
+}
+
 // Sets the specified fields to their respective values in the hash stored at key.
 // This command overwrites any existing fields in the hash.
 // If key does not exist, a new key holding a hash is created.
@@ -150,3 +182,42 @@ func (r *Connection) Ping() error {
 func IsConnectionClosed(err error) bool {
 	return err.Error() == "redigo: connection closed"
 }
+
+// IsRetryable returns true if command failed due to connection loss, failover
+// or cluster resharding, so it can be retried with new connection
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if IsConnectionClosed(err) {
+		return true
+	}
+
+	if _, ok := err.(net.Error); ok {
+		return true
+	}
+
+	if redisErr, ok := err.(redis.Error); ok {
+		for _, prefix := range []string{"MOVED", "ASK", "READONLY", "TRYAGAIN", "CLUSTERDOWN", "LOADING", "MASTERDOWN"} {
+			if strings.HasPrefix(string(redisErr), prefix) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// IsUnavailable returns true if redis can not be reached
+func IsUnavailable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if _, ok := err.(net.Error); ok {
+		return true
+	}
+
+	return IsConnectionClosed(err) || err == errNotInitialized
+}
@@ -1,6 +1,9 @@
 package redis
 
-import "bitbucket.org/atticlab/horizon/log"
+import (
+	"bitbucket.org/atticlab/horizon/log"
+	"golang.org/x/net/context"
+)
 
 const max_connection_reties = 10
 
@@ -9,12 +12,19 @@ type ConnectionProviderInterface interface {
 }
 
 type ConnectionProvider struct {
+	ctx context.Context
 }
 
 func NewConnectionProvider() ConnectionProviderInterface {
 	return &ConnectionProvider{}
 }
 
+// NewContextConnectionProvider returns provider of connections, which trace
+// commands within ctx
+func NewContextConnectionProvider(ctx context.Context) ConnectionProviderInterface {
+	return &ConnectionProvider{ctx: ctx}
+}
+
 func (c ConnectionProvider) GetConnection() ConnectionInterface {
 	if redisPool == nil {
 		log.Panic("Redis must be initialized")
@@ -25,7 +35,7 @@ func (c ConnectionProvider) GetConnection() ConnectionInterface {
 			conn.Close()
 			continue
 		}
-		return NewConnection(conn)
+		return NewContextConnection(c.ctx, conn)
 	}
-	return NewConnection(redisPool.Get())
+	return NewContextConnection(c.ctx, redisPool.Get())
 }
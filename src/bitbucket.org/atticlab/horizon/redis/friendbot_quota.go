@@ -0,0 +1,85 @@
+package redis
+
+import (
+	"time"
+
+	"github.com/garyburd/redigo/redis"
+)
+
+// takeQuotaScript increments request counter below the limit and starts the
+// window on the first request. Returns 1 if request is counted, 0 otherwise.
+var takeQuotaScript = redis.NewScript(1, `
+local count = tonumber(redis.call("GET", KEYS[1]) or "0")
+if count >= tonumber(ARGV[2]) then
+	return 0
+end
+if redis.call("INCR", KEYS[1]) == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return 1`)
+
+// returnQuotaScript decrements request counter of the current window
+var returnQuotaScript = redis.NewScript(1, `
+local count = tonumber(redis.call("GET", KEYS[1]) or "0")
+if count > 0 then
+	redis.call("DECR", KEYS[1])
+end
+return 0`)
+
+// FriendbotQuota limits friendbot requests per key during fixed window, shared
+// by horizon replicas. Implements friendbot.Quota.
+type FriendbotQuota struct {
+	scope  string
+	limit  int
+	window time.Duration
+	pool   *redis.Pool
+}
+
+// NewFriendbotQuota creates quota of limit requests per window. Scope separates
+// counters of different quotas, e.g. per IP and per address. Redis must be
+// initialized.
+func NewFriendbotQuota(scope string, limit int, window time.Duration) *FriendbotQuota {
+	return &FriendbotQuota{
+		scope:  scope,
+		limit:  limit,
+		window: window,
+		pool:   redisPool,
+	}
+}
+
+func (q *FriendbotQuota) conn() (redis.Conn, error) {
+	if q.pool == nil {
+		return nil, errNotInitialized
+	}
+
+	conn := q.pool.Get()
+	return conn, conn.Err()
+}
+
+// Take counts request of the key, returns false if the key exceeded the quota.
+// Rejected requests are not counted.
+func (q *FriendbotQuota) Take(key string) (bool, error) {
+	conn, err := q.conn()
+	if conn != nil {
+		defer conn.Close()
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return redis.Bool(takeQuotaScript.Do(conn, GetFriendbotQuotaKey(q.scope, key), int64(q.window/time.Millisecond), q.limit))
+}
+
+// Return gives back request of the key counted in the current window
+func (q *FriendbotQuota) Return(key string) error {
+	conn, err := q.conn()
+	if conn != nil {
+		defer conn.Close()
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = returnQuotaScript.Do(conn, GetFriendbotQuotaKey(q.scope, key))
+	return err
+}
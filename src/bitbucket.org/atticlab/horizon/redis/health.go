@@ -0,0 +1,138 @@
+package redis
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"bitbucket.org/atticlab/horizon/log"
+	"golang.org/x/net/context"
+)
+
+// Health is a result of the latest redis health check
+type Health struct {
+	Mode      Mode `json:"mode"`
+	Available bool `json:"available"`
+	// address of the master in sentinel mode or masters in cluster mode
+	Masters   []string  `json:"masters,omitempty"`
+	LastError string    `json:"last_error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+var errNotInitialized = errors.New("redis is not initialized")
+
+var (
+	healthLock sync.RWMutex
+	health     Health
+)
+
+// GetHealth returns result of the latest health check
+func GetHealth() Health {
+	healthLock.RLock()
+	defer healthLock.RUnlock()
+	return health
+}
+
+// IsAvailable returns true if redis responded on the latest health check
+func IsAvailable() bool {
+	return GetHealth().Available
+}
+
+// CheckHealth pings redis and stores result. In cluster mode all masters are
+// pinged.
+func CheckHealth() (Health, error) {
+	result := Health{
+		Mode:      redisMode,
+		CheckedAt: time.Now(),
+	}
+
+	var err error
+	switch {
+	case redisPool == nil:
+		err = errNotInitialized
+	case redisMode == ModeCluster:
+		result.Masters, err = checkClusterHealth()
+	default:
+		result.Masters, err = checkNodeHealth()
+	}
+
+	result.Available = err == nil
+	if err != nil {
+		result.LastError = err.Error()
+	}
+
+	healthLock.Lock()
+	previous := health
+	health = result
+	healthLock.Unlock()
+
+	if previous.Available != result.Available && !previous.CheckedAt.IsZero() {
+		entry := log.WithField("service", "redis").WithField("mode", result.Mode)
+		if result.Available {
+			entry.Info("Redis is available again")
+		} else {
+			entry.WithError(err).Error("Redis became unavailable")
+		}
+	}
+
+	return result, err
+}
+
+func checkNodeHealth() ([]string, error) {
+	conn := redisPool.Get()
+	defer conn.Close()
+
+	_, err := conn.Do("PING")
+	if err != nil {
+		return nil, err
+	}
+
+	if redisSentinel == nil {
+		return nil, nil
+	}
+
+	addr, err := redisSentinel.MasterAddr()
+	if err != nil {
+		return nil, err
+	}
+	return []string{addr}, nil
+}
+
+func checkClusterHealth() ([]string, error) {
+	masters := redisCluster.Masters()
+	for _, addr := range masters {
+		conn, err := dialNode(addr, redisCluster.password)
+		if err != nil {
+			redisCluster.Refresh()
+			return masters, err
+		}
+
+		_, err = conn.Do("PING")
+		conn.Close()
+		if err != nil {
+			redisCluster.Refresh()
+			return masters, err
+		}
+	}
+	return masters, nil
+}
+
+// StartHealthCheck checks redis health with interval until ctx is done
+func StartHealthCheck(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				CheckHealth()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
@@ -1,14 +1,60 @@
 package redis
 
-import "strings"
+import (
+	"strconv"
+	"strings"
+)
 
 type namespace string
 
 const (
 	namespace_account_stats namespace = "as:"
-	namespace_processed_op namespace = "pop:"
+	namespace_processed_op  namespace = "pop:"
+	namespace_txsub         namespace = "txsub:"
+	namespace_leader        namespace = "leader:"
+	namespace_friendbot     namespace = "fb:"
 )
 
-func getKey(ns namespace, keyParts... string) string {
-	return string(ns) + strings.Join(keyParts, ":")
+// getKey builds key of the namespace. Tag is wrapped into hash tag, so keys
+// with the same tag are stored in the same slot of Redis Cluster and can be
+// watched and updated in one transaction.
+func getKey(ns namespace, tag string, keyParts ...string) string {
+	key := string(ns) + "{" + tag + "}"
+	if len(keyParts) == 0 {
+		return key
+	}
+	return key + ":" + strings.Join(keyParts, ":")
+}
+
+// GetTxSubPendingKey returns key of the sorted set of open submissions
+func GetTxSubPendingKey() string {
+	return getKey(namespace_txsub, "pending")
+}
+
+// GetSequenceReservationKey returns key of the reservation of account's sequence
+func GetSequenceReservationKey(address string, sequence uint64) string {
+	return getKey(namespace_txsub, address, "seq", strconv.FormatUint(sequence, 10))
+}
+
+// GetSequenceAcceptedKey returns key of the highest account's sequence
+// accepted by stellar-core
+func GetSequenceAcceptedKey(address string) string {
+	return getKey(namespace_txsub, address, "accepted")
+}
+
+// GetLeaderLeaseKey returns key of the lease electing replica performing the task
+func GetLeaderLeaseKey(task string) string {
+	return getKey(namespace_leader, task)
+}
+
+// GetLeaderTokenKey returns key of the fencing token of the lease. Stored in
+// the same slot as the lease.
+func GetLeaderTokenKey(task string) string {
+	return getKey(namespace_leader, task, "token")
+}
+
+// GetFriendbotQuotaKey returns key of the friendbot request counter of the key
+// (IP or address) in the scope
+func GetFriendbotQuotaKey(scope, key string) string {
+	return getKey(namespace_friendbot, key, scope)
 }
@@ -0,0 +1,114 @@
+package redis
+
+import (
+	"time"
+
+	"github.com/garyburd/redigo/redis"
+)
+
+// acquireLeaseScript takes free lease or renews lease held by the holder, and
+// returns the current holder. Each take of free lease increments fencing token.
+var acquireLeaseScript = redis.NewScript(2, `
+local holder = redis.call("GET", KEYS[1])
+if not holder then
+	redis.call("INCR", KEYS[2])
+	redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+	return ARGV[1]
+end
+if holder == ARGV[1] then
+	redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return holder`)
+
+// LeaderLease is a lease electing the only horizon replica performing the
+// task. Implements leader.TokenLease.
+type LeaderLease struct {
+	key      string
+	tokenKey string
+	pool     *redis.Pool
+}
+
+// NewLeaderLease creates lease of the task. Redis must be initialized.
+func NewLeaderLease(task string) *LeaderLease {
+	return &LeaderLease{
+		key:      GetLeaderLeaseKey(task),
+		tokenKey: GetLeaderTokenKey(task),
+		pool:     redisPool,
+	}
+}
+
+func (l *LeaderLease) conn() (redis.Conn, error) {
+	if l.pool == nil {
+		return nil, errNotInitialized
+	}
+
+	conn := l.pool.Get()
+	return conn, conn.Err()
+}
+
+// Acquire takes the lease for holder or renews it, if it's already held by
+// holder. Returns the current holder.
+func (l *LeaderLease) Acquire(holder string, ttl time.Duration) (string, error) {
+	conn, err := l.conn()
+	if conn != nil {
+		defer conn.Close()
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return redis.String(acquireLeaseScript.Do(conn, l.key, l.tokenKey, holder, int64(ttl/time.Millisecond)))
+}
+
+// Release gives up the lease, if it's held by holder
+func (l *LeaderLease) Release(holder string) error {
+	conn, err := l.conn()
+	if conn != nil {
+		defer conn.Close()
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = releaseScript.Do(conn, l.key, holder)
+	return err
+}
+
+// Holder returns the current holder, empty if lease is free
+func (l *LeaderLease) Holder() (string, error) {
+	conn, err := l.conn()
+	if conn != nil {
+		defer conn.Close()
+	}
+	if err != nil {
+		return "", err
+	}
+
+	holder, err := redis.String(conn.Do("GET", l.key))
+	if err == redis.ErrNil {
+		return "", nil
+	}
+	return holder, err
+}
+
+// HolderToken returns the current holder and fencing token of its tenure.
+// Token grows with every change of the holder.
+func (l *LeaderLease) HolderToken() (string, int64, error) {
+	conn, err := l.conn()
+	if conn != nil {
+		defer conn.Close()
+	}
+	if err != nil {
+		return "", 0, err
+	}
+
+	values, err := redis.Values(conn.Do("MGET", l.key, l.tokenKey))
+	if err != nil {
+		return "", 0, err
+	}
+
+	var holder string
+	var token int64
+	_, err = redis.Scan(values, &holder, &token)
+	return holder, token, err
+}
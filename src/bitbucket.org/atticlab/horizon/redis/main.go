@@ -1,68 +1,138 @@
 package redis
 
 import (
-	"github.com/garyburd/redigo/redis"
+	"bitbucket.org/atticlab/horizon/config"
 	"errors"
-	"time"
+	"github.com/garyburd/redigo/redis"
 	"net/url"
+	"time"
 )
 
+// Mode is a kind of Redis deployment horizon is connected to
+type Mode string
+
+const (
+	ModeSingle   Mode = "single"
+	ModeSentinel Mode = "sentinel"
+	ModeCluster  Mode = "cluster"
+)
+
+// ConfigError is returned by InitWithConfig if redis is configured improperly.
+// Other errors mean redis is unavailable, pool is created in this case.
+type ConfigError struct {
+	error
+}
 
 var redisPool *redis.Pool
+var redisMode = ModeSingle
+var redisCluster *cluster
+var redisSentinel *sentinel
 
 func GetPool() *redis.Pool {
 	return redisPool
 }
 
+// GetMode returns mode redis was initialized in
+func GetMode() Mode {
+	return redisMode
+}
+
 func Init(urlStr string) error {
-	if urlStr == "" {
-		return errors.New("Redis URL can not be empty!")
+	return InitWithConfig(urlStr, config.RedisConfig{})
+}
+
+// InitWithConfig initializes pool of connections to single redis node, master
+// discovered via sentinels or redis cluster. Url is used for password in
+// sentinel and cluster modes and may be empty.
+func InitWithConfig(urlStr string, conf config.RedisConfig) error {
+	if urlStr == "" && !conf.IsSentinel() && !conf.IsCluster() {
+		return ConfigError{errors.New("Redis URL can not be empty!")}
 	}
 
-	redisURL, err := url.Parse(urlStr)
-	if err != nil {
-		return err
+	if conf.IsSentinel() && conf.IsCluster() {
+		return ConfigError{errors.New("Redis sentinel and cluster can not be used together")}
 	}
 
-	redisPool = &redis.Pool{
+	var password string
+	var redisURL *url.URL
+	if urlStr != "" {
+		var err error
+		redisURL, err = url.Parse(urlStr)
+		if err != nil {
+			return ConfigError{err}
+		}
+		password = getPassword(redisURL)
+	}
+
+	pool := &redis.Pool{
 		MaxIdle:      3,
 		IdleTimeout:  240 * time.Second,
 		Dial:         dialRedis(redisURL),
 		TestOnBorrow: pingRedis,
 	}
 
+	redisMode = ModeSingle
+	redisCluster = nil
+	redisSentinel = nil
+	switch {
+	case conf.IsSentinel():
+		redisMode = ModeSentinel
+		redisSentinel = newSentinel(conf.SentinelAddrs, conf.SentinelMaster)
+		pool.Dial = redisSentinel.dial(password)
+		pool.TestOnBorrow = checkMasterRole
+	case conf.IsCluster():
+		redisMode = ModeCluster
+		redisCluster = newCluster(conf.ClusterAddrs, password)
+		pool.Dial = redisCluster.dial
+	}
+	redisPool = pool
+
+	if redisCluster != nil {
+		// slots map is reloaded on health checks, if cluster is unavailable now
+		err := redisCluster.Refresh()
+		if err != nil {
+			CheckHealth()
+			return err
+		}
+	}
+
 	// test the connection
-	c := redisPool.Get()
-	defer c.Close()
+	_, err := CheckHealth()
+	return err
+}
 
-	_, err = c.Do("PING")
-	if err != nil {
-		return err
+func getPassword(redisURL *url.URL) string {
+	if redisURL.User == nil {
+		return ""
 	}
 
-	return nil
+	pass, _ := redisURL.User.Password()
+	return pass
 }
 
 func dialRedis(redisURL *url.URL) func() (redis.Conn, error) {
 	return func() (redis.Conn, error) {
-		c, err := redis.Dial("tcp", redisURL.Host)
-		if err != nil {
-			return nil, err
-		}
+		return dialNode(redisURL.Host, getPassword(redisURL))
+	}
+}
 
-		if redisURL.User == nil {
-			return c, err
-		}
+// dialNode connects to redis node and authenticates, if password is not empty
+func dialNode(addr, password string) (redis.Conn, error) {
+	c, err := redis.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
 
-		if pass, ok := redisURL.User.Password(); ok {
-			if _, err := c.Do("AUTH", pass); err != nil {
-				c.Close()
-				return nil, err
-			}
-		}
+	if password == "" {
+		return c, nil
+	}
 
-		return c, err
+	if _, err := c.Do("AUTH", password); err != nil {
+		c.Close()
+		return nil, err
 	}
+
+	return c, nil
 }
 
 func pingRedis(c redis.Conn, t time.Time) error {
@@ -91,8 +91,8 @@ func (p *ProcessedOpProviderMock) Insert(processedOp *ProcessedOp, timeout time.
 	a := p.Called(processedOp, timeout)
 	return a.Error(0)
 }
-func (p *ProcessedOpProviderMock) Get(txHash string, opIndex int, isIncome bool) (*ProcessedOp, error) {
-	a := p.Called(txHash, opIndex, isIncome)
+func (p *ProcessedOpProviderMock) Get(account, txHash string, opIndex int, isIncome bool) (*ProcessedOp, error) {
+	a := p.Called(account, txHash, opIndex, isIncome)
 	rawProcessedOp := a.Get(0)
 	if rawProcessedOp == nil {
 		return nil, a.Error(1)
@@ -101,8 +101,8 @@ func (p *ProcessedOpProviderMock) Get(txHash string, opIndex int, isIncome bool)
 	return rawProcessedOp.(*ProcessedOp), a.Error(1)
 }
 
-func (p *ProcessedOpProviderMock) Delete(txHash string, opIndex int, isIncome bool) error {
-	a := p.Called(txHash, opIndex, isIncome)
+func (p *ProcessedOpProviderMock) Delete(account, txHash string, opIndex int, isIncome bool) error {
+	a := p.Called(account, txHash, opIndex, isIncome)
 	return a.Error(0)
 }
 
@@ -6,6 +6,8 @@ import (
 )
 
 type ProcessedOp struct {
+	// account statistics of which were updated by the op
+	Account     string
 	TxHash      string
 	Index       int
 	Amount      int64
@@ -14,8 +16,9 @@ type ProcessedOp struct {
 }
 
 // Creates new instance of processed op from response.
-func NewProcessedOp(txHash string, index int, amount int64, isIncoming bool, timeUpdated time.Time) *ProcessedOp {
+func NewProcessedOp(account, txHash string, index int, amount int64, isIncoming bool, timeUpdated time.Time) *ProcessedOp {
 	return &ProcessedOp{
+		Account:     account,
 		TxHash:      txHash,
 		Index:       index,
 		Amount:      amount,
@@ -24,10 +27,10 @@ func NewProcessedOp(txHash string, index int, amount int64, isIncoming bool, tim
 	}
 }
 
-func ReadProcessedOp(txHash string, index int, isIncoming bool, data map[string]int64) *ProcessedOp {
+func ReadProcessedOp(account, txHash string, index int, isIncoming bool, data map[string]int64) *ProcessedOp {
 	timeUpdated := time.Unix(data["tu"], 0)
 	amount := data["a"]
-	return NewProcessedOp(txHash, index, amount, isIncoming, timeUpdated)
+	return NewProcessedOp(account, txHash, index, amount, isIncoming, timeUpdated)
 }
 
 func (op *ProcessedOp) ToArray() []interface{} {
@@ -39,15 +42,17 @@ func (op *ProcessedOp) ToArray() []interface{} {
 }
 
 func (op *ProcessedOp) GetKey() string {
-	return GetProcessedOpKey(op.TxHash, op.Index, op.IsIncoming)
+	return GetProcessedOpKey(op.Account, op.TxHash, op.Index, op.IsIncoming)
 }
 
-func GetProcessedOpKey(txHash string, opIndex int, isIncoming bool) string {
+// GetProcessedOpKey returns key of the processed op. Key is tagged by account, so
+// it's stored in the same cluster slot as account's statistics.
+func GetProcessedOpKey(account, txHash string, opIndex int, isIncoming bool) string {
 	var direction string
 	if isIncoming {
 		direction = "i"
 	} else {
 		direction = "o"
 	}
-	return getKey(namespace_processed_op, txHash, strconv.Itoa(opIndex), direction)
+	return getKey(namespace_processed_op, account, txHash, strconv.Itoa(opIndex), direction)
 }
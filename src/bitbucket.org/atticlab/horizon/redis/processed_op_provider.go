@@ -7,8 +7,8 @@ import (
 
 type ProcessedOpProviderInterface interface {
 	Insert(processedOp *ProcessedOp, timeout time.Duration) error
-	Get(txHash string, opIndex int, isIncoming bool) (*ProcessedOp, error)
-	Delete(txHash string, opIndex int, isIncoming bool) error
+	Get(account, txHash string, opIndex int, isIncoming bool) (*ProcessedOp, error)
+	Delete(account, txHash string, opIndex int, isIncoming bool) error
 }
 
 type ProcessedOpProvider struct {
@@ -35,17 +35,17 @@ func (c *ProcessedOpProvider) Insert(processedOp *ProcessedOp, timeout time.Dura
 }
 
 // Tries to get account's stats map from redis. Returns nil, if does not exist
-func (c *ProcessedOpProvider) Get(txHash string, opIndex int, isIncoming bool) (*ProcessedOp, error) {
-	key := GetProcessedOpKey(txHash, opIndex, isIncoming)
+func (c *ProcessedOpProvider) Get(account, txHash string, opIndex int, isIncoming bool) (*ProcessedOp, error) {
+	key := GetProcessedOpKey(account, txHash, opIndex, isIncoming)
 	data, err := redis.Int64Map(c.conn.HGetAll(key))
 	if err != nil || len(data) == 0 {
 		return nil, err
 	}
 
-	return ReadProcessedOp(txHash, opIndex, isIncoming, data), nil
+	return ReadProcessedOp(account, txHash, opIndex, isIncoming, data), nil
 }
 
-func (c *ProcessedOpProvider) Delete(txHash string, opIndex int, isIncoming bool) error {
-	key := GetProcessedOpKey(txHash, opIndex, isIncoming)
+func (c *ProcessedOpProvider) Delete(account, txHash string, opIndex int, isIncoming bool) error {
+	key := GetProcessedOpKey(account, txHash, opIndex, isIncoming)
 	return c.conn.Delete(key)
 }
@@ -26,7 +26,7 @@ func TestProcessedOp(t *testing.T) {
 	Convey("Does not exist", t, func() {
 		account, err := keypair.Random()
 		So(err, ShouldBeNil)
-		processedOp, err := processedOpProvider.Get(account.Address(), 1, false)
+		processedOp, err := processedOpProvider.Get(account.Address(), account.Address(), 1, false)
 		So(err, ShouldBeNil)
 		So(processedOp, ShouldBeNil)
 	})
@@ -35,14 +35,14 @@ func TestProcessedOp(t *testing.T) {
 		isIncoming := true
 		So(err, ShouldBeNil)
 		txHash := account.Address()
-		processedOp := NewProcessedOp(txHash, rand.Int(), rand.Int63(), true, time.Unix(time.Now().Unix(), 0))
+		processedOp := NewProcessedOp(account.Address(), txHash, rand.Int(), rand.Int63(), true, time.Unix(time.Now().Unix(), 0))
 		err = processedOpProvider.Insert(processedOp, time.Duration(5)*time.Second)
 		So(err, ShouldBeNil)
-		stored, err := processedOpProvider.Get(processedOp.TxHash, processedOp.Index, isIncoming)
+		stored, err := processedOpProvider.Get(processedOp.Account, processedOp.TxHash, processedOp.Index, isIncoming)
 		assert.Equal(t, processedOp, stored)
-		err = processedOpProvider.Delete(processedOp.TxHash, processedOp.Index, isIncoming)
+		err = processedOpProvider.Delete(processedOp.Account, processedOp.TxHash, processedOp.Index, isIncoming)
 		So(err, ShouldBeNil)
-		stored, err = processedOpProvider.Get(processedOp.TxHash, processedOp.Index, isIncoming)
+		stored, err = processedOpProvider.Get(processedOp.Account, processedOp.TxHash, processedOp.Index, isIncoming)
 		So(err, ShouldBeNil)
 		So(stored, ShouldBeNil)
 	})
@@ -52,16 +52,16 @@ func TestProcessedOp(t *testing.T) {
 		txHash := account.Address()
 		opIndex := rand.Int()
 		isIncoming := false
-		processedOp := NewProcessedOp(txHash, opIndex, rand.Int63(), false, time.Unix(time.Now().Unix(), 0))
+		processedOp := NewProcessedOp(account.Address(), txHash, opIndex, rand.Int63(), false, time.Unix(time.Now().Unix(), 0))
 		expireTime := time.Duration(2) * time.Second
 		err = processedOpProvider.Insert(processedOp, expireTime)
 		So(err, ShouldBeNil)
-		storedProcessedOp, err := processedOpProvider.Get(txHash, opIndex, isIncoming)
+		storedProcessedOp, err := processedOpProvider.Get(account.Address(), txHash, opIndex, isIncoming)
 		So(err, ShouldBeNil)
 		assert.Equal(t, processedOp, storedProcessedOp)
 		// timeout expires
 		time.Sleep(expireTime + time.Duration(1)*time.Second)
-		storedProcessedOp, err = processedOpProvider.Get(txHash, opIndex, isIncoming)
+		storedProcessedOp, err = processedOpProvider.Get(account.Address(), txHash, opIndex, isIncoming)
 		So(err, ShouldBeNil)
 		So(storedProcessedOp, ShouldBeNil)
 	})
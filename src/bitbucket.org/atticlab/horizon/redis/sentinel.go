@@ -0,0 +1,121 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/garyburd/redigo/redis"
+)
+
+const sentinel_timeout = 500 * time.Millisecond
+
+// sentinel discovers current master via Redis Sentinel instances
+type sentinel struct {
+	masterName string
+
+	mu    sync.Mutex
+	addrs []string
+}
+
+func newSentinel(addrs []string, masterName string) *sentinel {
+	return &sentinel{
+		masterName: masterName,
+		addrs:      append([]string{}, addrs...),
+	}
+}
+
+// MasterAddr asks sentinels for address of the master. Sentinel which
+// responded is moved to the front of the list, so it is asked first next time.
+func (s *sentinel) MasterAddr() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var lastErr error
+	for i, addr := range s.addrs {
+		masterAddr, err := s.queryMaster(addr)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		// move responsive sentinel to the front
+		for j := i; j > 0; j-- {
+			s.addrs[j] = s.addrs[j-1]
+		}
+		s.addrs[0] = addr
+		return masterAddr, nil
+	}
+
+	if lastErr == nil {
+		lastErr = errors.New("no sentinels configured")
+	}
+	return "", fmt.Errorf("failed to discover master %s: %s", s.masterName, lastErr.Error())
+}
+
+func (s *sentinel) queryMaster(addr string) (string, error) {
+	conn, err := redis.DialTimeout("tcp", addr, sentinel_timeout, sentinel_timeout, sentinel_timeout)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	res, err := redis.Strings(conn.Do("SENTINEL", "get-master-addr-by-name", s.masterName))
+	if err != nil {
+		return "", err
+	}
+
+	if len(res) != 2 {
+		return "", fmt.Errorf("sentinel %s does not know master %s", addr, s.masterName)
+	}
+	return net.JoinHostPort(res[0], res[1]), nil
+}
+
+// dial connects to the current master and checks its role, as sentinel might
+// be not aware of failover yet
+func (s *sentinel) dial(password string) func() (redis.Conn, error) {
+	return func() (redis.Conn, error) {
+		addr, err := s.MasterAddr()
+		if err != nil {
+			return nil, err
+		}
+
+		c, err := dialNode(addr, password)
+		if err != nil {
+			return nil, err
+		}
+
+		err = checkMasterRole(c, time.Time{})
+		if err != nil {
+			c.Close()
+			return nil, err
+		}
+		return c, nil
+	}
+}
+
+// checkMasterRole returns error if connection is not to master, so connections
+// to demoted master are not borrowed from the pool after failover
+func checkMasterRole(c redis.Conn, t time.Time) error {
+	res, err := redis.Values(c.Do("ROLE"))
+	if err != nil {
+		return err
+	}
+
+	if len(res) == 0 {
+		return errors.New("empty response on ROLE")
+	}
+
+	role, err := redis.String(res[0], nil)
+	if err != nil {
+		return err
+	}
+
+	if !strings.EqualFold(role, "master") {
+		return fmt.Errorf("READONLY node has role %s", role)
+	}
+	return nil
+}
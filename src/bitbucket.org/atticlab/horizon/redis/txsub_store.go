@@ -0,0 +1,175 @@
+package redis
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/garyburd/redigo/redis"
+)
+
+var errReservationExpired = errors.New("sequence reservation expired while being claimed")
+
+// releaseScript deletes reservation only if it's held by the transaction
+var releaseScript = redis.NewScript(1, `
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// acceptScript raises accepted sequence stored as decimal string. Sequences
+// exceed precision of lua numbers, so they are compared as strings of digits.
+var acceptScript = redis.NewScript(1, `
+local current = redis.call("GET", KEYS[1])
+if not current or string.len(current) < string.len(ARGV[1]) or
+	(string.len(current) == string.len(ARGV[1]) and current < ARGV[1]) then
+	return redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+end
+return 0`)
+
+// TxSubStore keeps open submissions and sequence reservations of transaction
+// submission system, so they are shared by horizon replicas. Open submissions
+// are stored in one sorted set scored by submission time.
+type TxSubStore struct {
+	pool *redis.Pool
+}
+
+// NewTxSubStore creates new store. Redis must be initialized.
+func NewTxSubStore() *TxSubStore {
+	return &TxSubStore{
+		pool: redisPool,
+	}
+}
+
+func (s *TxSubStore) conn() (redis.Conn, error) {
+	if s.pool == nil {
+		return nil, errNotInitialized
+	}
+
+	conn := s.pool.Get()
+	return conn, conn.Err()
+}
+
+func (s *TxSubStore) do(commandName string, args ...interface{}) (interface{}, error) {
+	conn, err := s.conn()
+	if conn != nil {
+		defer conn.Close()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return conn.Do(commandName, args...)
+}
+
+// Add stores hash of submitted transaction. Submission time of already stored transaction is not changed.
+func (s *TxSubStore) Add(hash string, submittedAt time.Time) error {
+	_, err := s.do("ZADD", GetTxSubPendingKey(), "NX", submittedAt.Unix(), hash)
+	return err
+}
+
+// Remove removes hash of submitted transaction
+func (s *TxSubStore) Remove(hash string) error {
+	_, err := s.do("ZREM", GetTxSubPendingKey(), hash)
+	return err
+}
+
+// Pending returns hashes of all submitted transactions
+func (s *TxSubStore) Pending() ([]string, error) {
+	return redis.Strings(s.do("ZRANGE", GetTxSubPendingKey(), 0, -1))
+}
+
+// Clean removes transactions submitted before the provided time and returns number of transactions left
+func (s *TxSubStore) Clean(submittedBefore time.Time) (int, error) {
+	key := GetTxSubPendingKey()
+	_, err := s.do("ZREMRANGEBYSCORE", key, "-inf", "("+strconv.FormatInt(submittedBefore.Unix(), 10))
+	if err != nil {
+		return 0, err
+	}
+
+	return redis.Int(s.do("ZCARD", key))
+}
+
+// Reserve claims sequence of the address for transaction with hash for ttl,
+// unless it's already claimed. Returns hash of the transaction holding reservation.
+func (s *TxSubStore) Reserve(address string, sequence uint64, hash string, ttl time.Duration) (string, error) {
+	key := GetSequenceReservationKey(address, sequence)
+	// reservation may expire between SET and GET, so claim is retried once
+	for i := 0; i < 2; i++ {
+		resp, err := s.do("SET", key, hash, "NX", "PX", int64(ttl/time.Millisecond))
+		if err != nil {
+			return "", err
+		}
+
+		if resp != nil {
+			return hash, nil
+		}
+
+		holder, err := redis.String(s.do("GET", key))
+		if err == redis.ErrNil {
+			continue
+		}
+		return holder, err
+	}
+
+	return "", errReservationExpired
+}
+
+// Release removes reservation, if it's held by transaction with hash
+func (s *TxSubStore) Release(address string, sequence uint64, hash string) error {
+	conn, err := s.conn()
+	if conn != nil {
+		defer conn.Close()
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = releaseScript.Do(conn, GetSequenceReservationKey(address, sequence), hash)
+	return err
+}
+
+// Accept raises the highest accepted sequence of the address and keeps it for ttl
+func (s *TxSubStore) Accept(address string, sequence uint64, hash string, ttl time.Duration) error {
+	conn, err := s.conn()
+	if conn != nil {
+		defer conn.Close()
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = acceptScript.Do(conn, GetSequenceAcceptedKey(address), strconv.FormatUint(sequence, 10), int64(ttl/time.Millisecond))
+	return err
+}
+
+// Accepted returns the highest accepted sequences of the addresses. Keys of
+// the addresses may be stored on different cluster nodes, so they are read one
+// by one.
+func (s *TxSubStore) Accepted(addresses []string) (map[string]uint64, error) {
+	conn, err := s.conn()
+	if conn != nil {
+		defer conn.Close()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]uint64)
+	for _, address := range addresses {
+		raw, err := redis.String(conn.Do("GET", GetSequenceAcceptedKey(address)))
+		if err == redis.ErrNil {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		sequence, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		result[address] = sequence
+	}
+	return result, nil
+}
@@ -193,6 +193,16 @@ var (
 			"several minutes before trying your request again.",
 	}
 
+	// ShuttingDown is a well-known problem type.  Use it as a shortcut
+	// in your actions.
+	ShuttingDown = P{
+		Type:   "shutting_down",
+		Title:  "Shutting Down",
+		Status: http.StatusServiceUnavailable,
+		Detail: "This horizon server is shutting down and does not accept new " +
+			"transactions.  Please retry your request in a few seconds.",
+	}
+
 	// Timeout is a well-known problem type.  Use it as a shortcut
 	// in your actions.
 	Timeout = P{
@@ -43,6 +43,15 @@ var goodbyeEvent = Event{
 	Retry: 10,
 }
 
+// On shutdown open streams are ended with this event. Retry hints the client to
+// reconnect after the instance is replaced or the load balancer moves it to
+// another one.
+var shutdownEvent = Event{
+	Data:  "shutdown",
+	Event: "close",
+	Retry: 5000,
+}
+
 // Eventable represents an object that can be converted to an SSE compatible
 // event.
 type Eventable interface {
@@ -75,6 +84,9 @@ func (s *Streamer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 			WriteEvent(s.Ctx, w, eventable.SseEvent())
+		case <-ShuttingDown():
+			WriteEvent(s.Ctx, w, shutdownEvent)
+			return
 		case <-s.Ctx.Done():
 			return
 		}
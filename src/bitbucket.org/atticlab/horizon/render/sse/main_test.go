@@ -37,4 +37,14 @@ func TestSsePackage(t *testing.T) {
 		So(log.String(), ShouldContainSubstring, "level=error")
 		So(log.String(), ShouldContainSubstring, "busted")
 	})
+
+	Convey("stream.Shutdown ends the stream with retry hint", t, func() {
+		w := httptest.NewRecorder()
+		stream, ok := NewStream(ctx, w, nil)
+		So(ok, ShouldBeTrue)
+
+		stream.Shutdown()
+		So(stream.IsDone(), ShouldBeTrue)
+		So(w.Body.String(), ShouldContainSubstring, "retry: 5000\nevent: close\ndata: \"shutdown\"\n\n")
+	})
 }
@@ -8,6 +8,9 @@ var pump <-chan struct{}
 var lock sync.Mutex
 var nextTick chan struct{}
 
+var shutdown = make(chan struct{})
+var shutdownOnce sync.Once
+
 // SetPump established the pump that will be used to drive streaming responses.
 // Everytime the provided channel sends any open connections will be triggered
 // to run their queries again and delivery any new results to clients.
@@ -52,3 +55,16 @@ func run() {
 		}
 	}
 }
+
+// Shutdown makes open streams send the final event with retry hint and end.
+// It is safe to call more than once.
+func Shutdown() {
+	shutdownOnce.Do(func() {
+		close(shutdown)
+	})
+}
+
+// ShuttingDown returns a channel that is closed by Shutdown
+func ShuttingDown() <-chan struct{} {
+	return shutdown
+}
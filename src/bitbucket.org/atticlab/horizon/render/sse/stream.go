@@ -11,6 +11,8 @@ type Stream interface {
 	Send(Event)
 	SentCount() int
 	Done()
+	// Shutdown ends the stream with the event asking client to reconnect later
+	Shutdown()
 	SetLimit(limit int)
 	IsDone() bool
 	Err(error)
@@ -50,6 +52,11 @@ func (s *stream) Done() {
 	s.done = true
 }
 
+func (s *stream) Shutdown() {
+	WriteEvent(s.ctx, s.w, shutdownEvent)
+	s.done = true
+}
+
 func (s *stream) IsDone() bool {
 	if s.limit == 0 {
 		return s.done
@@ -6,6 +6,7 @@ import (
 	"bitbucket.org/atticlab/horizon/render/hal"
 	"fmt"
 	"golang.org/x/net/context"
+	"time"
 )
 
 // AccountTraits shows if account's incoming, outgoing payments are blocked
@@ -18,6 +19,40 @@ type AccountTraits struct {
 	AccountID string `json:"account_id"`
 	BlockIn   bool   `json:"block_incoming_payments"`
 	BlockOut  bool   `json:"block_outcoming_payments"`
+	// active temporary freezes
+	Freezes []AccountFreeze `json:"freezes,omitempty"`
+}
+
+// AccountFreeze is temporary restriction of account's payments
+type AccountFreeze struct {
+	ID         int64      `json:"id"`
+	AssetCode  string     `json:"asset_code,omitempty"`
+	BlockIn    bool       `json:"block_incoming_payments"`
+	BlockOut   bool       `json:"block_outcoming_payments"`
+	ReasonCode string     `json:"reason_code"`
+	Comment    string     `json:"comment,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// AccountTraitsEvent is a change of account's traits
+type AccountTraitsEvent struct {
+	Links struct {
+		Account hal.Link `json:"account"`
+		Traits  hal.Link `json:"traits"`
+	} `json:"_links"`
+	ID         int64      `json:"id"`
+	PT         string     `json:"paging_token"`
+	AccountID  string     `json:"account_id"`
+	Event      string     `json:"event"`
+	FreezeID   *int64     `json:"freeze_id,omitempty"`
+	AssetCode  string     `json:"asset_code,omitempty"`
+	BlockIn    bool       `json:"block_incoming_payments"`
+	BlockOut   bool       `json:"block_outcoming_payments"`
+	ReasonCode string     `json:"reason_code,omitempty"`
+	Comment    string     `json:"comment,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
 }
 
 func (at *AccountTraits) Populate(ctx context.Context, hat history.Account) (err error) {
@@ -34,3 +69,42 @@ func (at *AccountTraits) Populate(ctx context.Context, hat history.Account) (err
 func (at AccountTraits) PagingToken() string {
 	return at.PT
 }
+
+// PopulateFreezes fills out active freezes of the account
+func (at *AccountTraits) PopulateFreezes(rows []history.AccountFreeze) {
+	at.Freezes = make([]AccountFreeze, len(rows))
+	for i, row := range rows {
+		at.Freezes[i] = AccountFreeze{
+			ID:         row.ID,
+			AssetCode:  row.AssetCode,
+			BlockIn:    row.BlockIncomingPayments,
+			BlockOut:   row.BlockOutcomingPayments,
+			ReasonCode: string(row.ReasonCode),
+			Comment:    row.Comment,
+			ExpiresAt:  row.ExpiresAt.Ptr(),
+			CreatedAt:  row.CreatedAt,
+		}
+	}
+}
+
+func (e *AccountTraitsEvent) Populate(ctx context.Context, row history.AccountTraitsEvent) {
+	e.ID = row.ID
+	e.PT = row.PagingToken()
+	e.AccountID = row.Address
+	e.Event = string(row.Event)
+	e.FreezeID = row.FreezeID.Ptr()
+	e.AssetCode = row.AssetCode
+	e.BlockIn = row.BlockIncomingPayments
+	e.BlockOut = row.BlockOutcomingPayments
+	e.ReasonCode = row.ReasonCode
+	e.Comment = row.Comment
+	e.ExpiresAt = row.ExpiresAt.Ptr()
+	e.CreatedAt = row.CreatedAt
+	lb := hal.LinkBuilder{httpx.BaseURL(ctx)}
+	e.Links.Account = lb.Link(fmt.Sprintf("/accounts/%s", row.Address))
+	e.Links.Traits = lb.Link(fmt.Sprintf("/accounts/%s/traits", row.Address))
+}
+
+func (e AccountTraitsEvent) PagingToken() string {
+	return e.PT
+}
@@ -0,0 +1,71 @@
+package resource
+
+import (
+	"bitbucket.org/atticlab/go-smart-base/amount"
+	"bitbucket.org/atticlab/go-smart-base/xdr"
+	"bitbucket.org/atticlab/horizon/db2/core"
+	"bitbucket.org/atticlab/horizon/db2/history"
+	"golang.org/x/net/context"
+)
+
+// AssetSupply is the amount of the asset issued to non-issuer accounts with
+// breakdown by holder account type and daily history of issuance
+type AssetSupply struct {
+	AssetCode     string                     `json:"asset_code"`
+	AssetIssuer   string                     `json:"asset_issuer"`
+	TotalIssued   string                     `json:"total_issued"`
+	ByAccountType []AssetSupplyByAccountType `json:"by_account_type"`
+	History       []AssetDailySupply         `json:"history"`
+}
+
+// AssetSupplyByAccountType is the amount of the asset held by accounts of
+// one type
+type AssetSupplyByAccountType struct {
+	AccountTypeI int32  `json:"account_type_i"`
+	AccountType  string `json:"account_type"`
+	Holders      int64  `json:"holders"`
+	Amount       string `json:"amount"`
+}
+
+// AssetDailySupply is the amount of the asset issued and redeemed during the
+// day and supply at the end of the day
+type AssetDailySupply struct {
+	Date     string `json:"date"`
+	Issued   string `json:"issued"`
+	Redeemed string `json:"redeemed"`
+	Supply   string `json:"supply"`
+}
+
+// Populate fills out the resource's fields
+func (res *AssetSupply) Populate(
+	ctx context.Context,
+	code, issuer string,
+	byAccountType []core.AssetSupplyByAccountType,
+	daily []history.AssetDailySupply,
+) {
+	res.AssetCode = code
+	res.AssetIssuer = issuer
+
+	var total int64
+	res.ByAccountType = make([]AssetSupplyByAccountType, len(byAccountType))
+	for i, row := range byAccountType {
+		res.ByAccountType[i].AccountTypeI, res.ByAccountType[i].AccountType = PopulateAccountType(row.AccountType)
+		res.ByAccountType[i].Holders = row.Holders
+		res.ByAccountType[i].Amount = amount.String(xdr.Int64(row.Amount))
+		total += row.Amount
+	}
+	res.TotalIssued = amount.String(xdr.Int64(total))
+
+	res.History = make([]AssetDailySupply, len(daily))
+	for i, row := range daily {
+		res.History[i].Populate(row)
+	}
+}
+
+// Populate fills out the resource's fields
+func (res *AssetDailySupply) Populate(row history.AssetDailySupply) {
+	res.Date = row.Day.Format("2006-01-02")
+	res.Issued = amount.String(xdr.Int64(row.Issued))
+	res.Redeemed = amount.String(xdr.Int64(row.Redeemed))
+	res.Supply = amount.String(xdr.Int64(row.Supply))
+}
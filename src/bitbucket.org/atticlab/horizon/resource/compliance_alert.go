@@ -0,0 +1,70 @@
+package resource
+
+import (
+	"fmt"
+	"time"
+
+	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/httpx"
+	"bitbucket.org/atticlab/horizon/render/hal"
+	"golang.org/x/net/context"
+)
+
+// ComplianceAlert is suspicious activity detected on ingestion
+type ComplianceAlert struct {
+	Links struct {
+		Account  hal.Link   `json:"account"`
+		Ledger   hal.Link   `json:"ledger"`
+		Evidence []hal.Link `json:"evidence"`
+	} `json:"_links"`
+	ID             int64      `json:"id"`
+	PT             string     `json:"paging_token"`
+	Rule           string     `json:"rule"`
+	Account        string     `json:"account_id"`
+	AssetCode      string     `json:"asset_code"`
+	LedgerSequence int32      `json:"ledger"`
+	Description    string     `json:"description"`
+	Operations     []int64    `json:"operations"`
+	Acknowledged   bool       `json:"acknowledged"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+	Comment        string     `json:"comment,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// Populate fills out the resource's fields
+func (res *ComplianceAlert) Populate(ctx context.Context, row history.ComplianceAlert) error {
+	operations, err := row.Operations()
+	if err != nil {
+		return err
+	}
+
+	res.ID = row.ID
+	res.PT = row.PagingToken()
+	res.Rule = row.Rule
+	res.Account = row.Address
+	res.AssetCode = row.AssetCode
+	res.LedgerSequence = row.LedgerSequence
+	res.Description = row.Description
+	res.Operations = operations
+	res.Acknowledged = row.Acknowledged
+	res.AcknowledgedAt = nil
+	if row.AcknowledgedAt.Valid {
+		res.AcknowledgedAt = &row.AcknowledgedAt.Time
+	}
+	res.Comment = row.Comment
+	res.CreatedAt = row.CreatedAt
+
+	lb := hal.LinkBuilder{httpx.BaseURL(ctx)}
+	res.Links.Account = lb.Link("/accounts", res.Account)
+	res.Links.Ledger = lb.Link(fmt.Sprintf("/ledgers/%d", res.LedgerSequence))
+	res.Links.Evidence = make([]hal.Link, len(operations))
+	for i, id := range operations {
+		res.Links.Evidence[i] = lb.Link(fmt.Sprintf("/operations/%d", id))
+	}
+	return nil
+}
+
+// PagingToken implementation for hal.Pageable
+func (res ComplianceAlert) PagingToken() string {
+	return res.PT
+}
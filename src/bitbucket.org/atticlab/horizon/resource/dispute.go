@@ -0,0 +1,106 @@
+package resource
+
+import (
+	"fmt"
+	"time"
+
+	"bitbucket.org/atticlab/go-smart-base/amount"
+	"bitbucket.org/atticlab/go-smart-base/xdr"
+	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/httpx"
+	"bitbucket.org/atticlab/horizon/render/hal"
+	"golang.org/x/net/context"
+)
+
+// Dispute is a case opened against a payment contested by its sender
+type Dispute struct {
+	Links struct {
+		Self       hal.Link  `json:"self"`
+		Payment    hal.Link  `json:"payment"`
+		Claimant   hal.Link  `json:"claimant"`
+		Respondent hal.Link  `json:"respondent"`
+		Resolution *hal.Link `json:"resolution,omitempty"`
+	} `json:"_links"`
+	ID                    int64     `json:"id"`
+	PT                    string    `json:"paging_token"`
+	PaymentID             int64     `json:"payment_id"`
+	State                 string    `json:"state"`
+	Claimant              string    `json:"claimant"`
+	Respondent            string    `json:"respondent"`
+	AssetCode             string    `json:"asset_code"`
+	Amount                string    `json:"amount"`
+	FreezeID              *int64    `json:"freeze_id,omitempty"`
+	ResolutionOperationID *int64    `json:"resolution_operation_id,omitempty"`
+	CreatedAt             time.Time `json:"created_at"`
+	UpdatedAt             time.Time `json:"updated_at"`
+	// history of the case, set only for single dispute
+	Notes []DisputeNote `json:"notes,omitempty"`
+}
+
+// DisputeNote is a note or change of state of the dispute
+type DisputeNote struct {
+	ID        int64     `json:"id"`
+	State     string    `json:"state,omitempty"`
+	Note      string    `json:"note,omitempty"`
+	Evidence  []string  `json:"evidence"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Populate fills out the resource's fields
+func (res *Dispute) Populate(ctx context.Context, row history.Dispute) {
+	res.ID = row.ID
+	res.PT = row.PagingToken()
+	res.PaymentID = row.PaymentID
+	res.State = string(row.State)
+	res.Claimant = row.Claimant
+	res.Respondent = row.Respondent
+	res.AssetCode = row.AssetCode
+	res.Amount = amount.String(xdr.Int64(row.Amount))
+	res.FreezeID = nil
+	if row.FreezeID.Valid {
+		res.FreezeID = &row.FreezeID.Int64
+	}
+	res.ResolutionOperationID = nil
+	if row.ResolutionOperationID.Valid {
+		res.ResolutionOperationID = &row.ResolutionOperationID.Int64
+	}
+	res.CreatedAt = row.CreatedAt
+	res.UpdatedAt = row.UpdatedAt
+	res.Notes = nil
+
+	lb := hal.LinkBuilder{httpx.BaseURL(ctx)}
+	res.Links.Self = lb.Link(fmt.Sprintf("/disputes/%d", res.ID))
+	res.Links.Payment = lb.Link(fmt.Sprintf("/operations/%d", res.PaymentID))
+	res.Links.Claimant = lb.Link("/accounts", res.Claimant)
+	res.Links.Respondent = lb.Link("/accounts", res.Respondent)
+	res.Links.Resolution = nil
+	if res.ResolutionOperationID != nil {
+		resolution := lb.Link(fmt.Sprintf("/operations/%d", *res.ResolutionOperationID))
+		res.Links.Resolution = &resolution
+	}
+}
+
+// PopulateNotes sets history of the case
+func (res *Dispute) PopulateNotes(rows []history.DisputeNote) error {
+	res.Notes = make([]DisputeNote, len(rows))
+	for i, row := range rows {
+		evidence, err := row.Evidence()
+		if err != nil {
+			return err
+		}
+
+		res.Notes[i] = DisputeNote{
+			ID:        row.ID,
+			State:     string(row.State),
+			Note:      row.Note,
+			Evidence:  evidence,
+			CreatedAt: row.CreatedAt,
+		}
+	}
+	return nil
+}
+
+// PagingToken implementation for hal.Pageable
+func (res Dispute) PagingToken() string {
+	return res.PT
+}
@@ -1,14 +1,16 @@
 package resource
 
 import (
+	"bitbucket.org/atticlab/go-smart-base/amount"
 	"bitbucket.org/atticlab/go-smart-base/xdr"
 	"bitbucket.org/atticlab/horizon/assets"
+	"bitbucket.org/atticlab/horizon/db2/core"
 	"bitbucket.org/atticlab/horizon/db2/history"
 	"fmt"
 	"golang.org/x/net/context"
 )
 
-func (this *HistoryAsset) Populate(ctx context.Context, row history.Asset) {
+func (this *HistoryAsset) Populate(ctx context.Context, row history.Asset) error {
 	this.ID = row.Id
 	this.IsAnonymous = row.IsAnonymous
 	var err error
@@ -19,8 +21,23 @@ func (this *HistoryAsset) Populate(ctx context.Context, row history.Asset) {
 
 	this.Code = row.Code
 	this.Issuer = row.Issuer
+	this.Name = row.Name
+	this.Decimals = row.Decimals
+	this.Description = row.Description
+	this.LogoURL = row.LogoURL
+	this.Status = string(row.Status)
+	this.RegulatoryFlags, err = row.RegulatoryFlags()
+	return err
 }
 
 func (this HistoryAsset) PagingToken() string {
 	return fmt.Sprintf("%d", this.ID)
 }
+
+// Populate fills out the resource's fields
+func (this *AssetDetails) Populate(ctx context.Context, row history.Asset, stats core.AssetStats) error {
+	this.CirculatingSupply = amount.String(xdr.Int64(stats.Supply))
+	this.Holders = stats.Holders
+	this.Trustlines = stats.Trustlines
+	return this.HistoryAsset.Populate(ctx, row)
+}
@@ -45,6 +45,17 @@ func PopulateAccountTypeP(accountType xdr.AccountType) (*int32, *string) {
 	return &accTypeI, &accType
 }
 
+// AccountTypeByName returns account type represented by the name in horizon's
+// JSON responses
+func AccountTypeByName(name string) (xdr.AccountType, bool) {
+	for accountType, typ := range AccountTypeNames {
+		if typ == name {
+			return accountType, true
+		}
+	}
+	return 0, false
+}
+
 // Account is the summary of an account
 type Account struct {
 	Links struct {
@@ -133,8 +144,25 @@ type Asset details.Asset
 
 type HistoryAsset struct {
 	Asset
-	ID          int64 `json:"id"`
-	IsAnonymous bool  `json:"is_anonymous"`
+	ID              int64    `json:"id"`
+	IsAnonymous     bool     `json:"is_anonymous"`
+	Name            string   `json:"name"`
+	Decimals        int      `json:"decimals"`
+	Description     string   `json:"description"`
+	LogoURL         string   `json:"logo_url"`
+	RegulatoryFlags []string `json:"regulatory_flags"`
+	Status          string   `json:"status"`
+}
+
+// AssetDetails is an asset with its circulating supply and holders computed
+// from trustlines
+type AssetDetails struct {
+	HistoryAsset
+	CirculatingSupply string `json:"circulating_supply"`
+	// number of accounts with positive balance
+	Holders int64 `json:"holders"`
+	// number of accounts trusting the asset
+	Trustlines int64 `json:"trustlines"`
 }
 
 // Balance represents an account's holdings for a single currency type
@@ -242,6 +270,8 @@ type Root struct {
 	HorizonSequence     int32  `json:"horizon_latest_ledger"`
 	StellarCoreSequence int32  `json:"core_latest_ledger"`
 	NetworkPassphrase   string `json:"network_passphrase"`
+	// IngestionLeader is id of the replica elected to ingest
+	IngestionLeader string `json:"ingestion_leader,omitempty"`
 }
 
 // Signer represents one of an account's signers.
@@ -1,6 +1,7 @@
 package operations
 
 import (
+	"bitbucket.org/atticlab/go-smart-base/amount"
 	"bitbucket.org/atticlab/go-smart-base/xdr"
 	"bitbucket.org/atticlab/horizon/db2/history"
 	"bitbucket.org/atticlab/horizon/db2/history/details"
@@ -29,6 +30,16 @@ var TypeNames = map[xdr.OperationType]string{
 	xdr.OperationTypeRefund:			 "refund",
 }
 
+// TypeByName returns operation type by the name used in horizon's JSON responses
+func TypeByName(name string) (xdr.OperationType, bool) {
+	for opType, opName := range TypeNames {
+		if opName == name {
+			return opType, true
+		}
+	}
+	return 0, false
+}
+
 // New creates a new operation resource, finding the appropriate type to use
 // based upon the row's type.
 func New(
@@ -47,6 +58,9 @@ func New(
 	case xdr.OperationTypePayment:
 		e := Payment{Base: base}
 		err = row.UnmarshalDetails(&e)
+		if err == nil {
+			err = e.populateRefunds(row)
+		}
 		result = e
 	case xdr.OperationTypePathPayment:
 		e := PathPayment{}
@@ -135,6 +149,22 @@ type CreateAccount struct {
 type Payment struct {
 	Base
 	details.Payment
+	// amount returned to the sender by reversals and refunds so far
+	RefundedAmount string `json:"refunded_amount,omitempty"`
+	// amount which can still be returned to the sender
+	RefundableAmount string `json:"refundable_amount,omitempty"`
+}
+
+func (p *Payment) populateRefunds(row history.Operation) error {
+	paymentAmount, err := amount.Parse(p.Amount)
+	if err != nil {
+		return err
+	}
+
+	refunded := xdr.Int64(row.RefundedAmount.Int64)
+	p.RefundedAmount = amount.String(refunded)
+	p.RefundableAmount = amount.String(paymentAmount - refunded)
+	return nil
 }
 
 type PathPayment struct {
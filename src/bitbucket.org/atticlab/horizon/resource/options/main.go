@@ -2,4 +2,5 @@ package options
 
 type Options struct {
 	MaxReversalDuration MaxReversalDuration `json:"max_reversal_duration"`
+	Values              []Option            `json:"options"`
 }
@@ -1,7 +1,6 @@
 package options
 
 import (
-	"bitbucket.org/atticlab/horizon/db2/history"
 	"time"
 )
 
@@ -10,8 +9,7 @@ type MaxReversalDuration struct {
 	DurationStr       string `json:"duration_str"`
 }
 
-func (m *MaxReversalDuration) Populate(maxReversalDuration history.MaxReversalDuration) {
-	duration, _ := maxReversalDuration.GetMaxDuration()
+func (m *MaxReversalDuration) Populate(duration time.Duration) {
 	m.DurationInSeconds = int64(duration/time.Second)
 	m.DurationStr = duration.String()
 }
@@ -0,0 +1,22 @@
+package options
+
+import (
+	registry "bitbucket.org/atticlab/horizon/options"
+)
+
+// Option is effective value of the runtime-editable option
+type Option struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	Value       string `json:"value"`
+	Source      string `json:"source"`
+}
+
+func (o *Option) Populate(value registry.Value) {
+	o.Name = value.Name
+	o.Type = string(value.Type)
+	o.Description = value.Description
+	o.Value = value.String()
+	o.Source = string(value.Source)
+}
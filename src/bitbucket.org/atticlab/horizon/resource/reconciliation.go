@@ -0,0 +1,117 @@
+package resource
+
+import (
+	"errors"
+
+	"bitbucket.org/atticlab/go-smart-base/amount"
+	"bitbucket.org/atticlab/go-smart-base/xdr"
+	"bitbucket.org/atticlab/horizon/db2/history"
+)
+
+// ExpectedPayment is a payment merchant expects to receive for the order identified by memo
+type ExpectedPayment struct {
+	// MemoType is one of text, id, hash or return. Text if empty
+	MemoType string `json:"memo_type"`
+	Memo     string `json:"memo"`
+	Amount   string `json:"amount"`
+
+	rawAmount xdr.Int64
+}
+
+// Parse validates expected payment and parses its amount
+func (p *ExpectedPayment) Parse() (err error) {
+	if p.MemoType == "" {
+		p.MemoType = "text"
+	}
+
+	switch p.MemoType {
+	case "text", "id", "hash", "return":
+	default:
+		return errors.New("memo_type must be one of: text, id, hash, return")
+	}
+
+	p.rawAmount, err = amount.Parse(p.Amount)
+	return
+}
+
+// Key identifies the order by memo type and memo
+func (p *ExpectedPayment) Key() string {
+	return reconciliationKey(p.MemoType, p.Memo)
+}
+
+func reconciliationKey(memoType, memo string) string {
+	return memoType + ":" + memo
+}
+
+// Reconciliation is the result of matching expected payments against received ones
+type Reconciliation struct {
+	Matched    []ReconciliationEntry `json:"matched"`
+	Missing    []ReconciliationEntry `json:"missing"`
+	Mismatched []ReconciliationEntry `json:"mismatched"`
+}
+
+// ReconciliationEntry describes state of a single expected payment
+type ReconciliationEntry struct {
+	MemoType       string   `json:"memo_type"`
+	Memo           string   `json:"memo"`
+	ExpectedAmount string   `json:"expected_amount"`
+	ReceivedAmount string   `json:"received_amount,omitempty"`
+	Payments       []string `json:"payments,omitempty"`
+}
+
+// Populate matches expected payments against received `payments`. Payment is matched, if
+// total amount received with the memo and memo type, less amounts returned by
+// reversals and refunds, is equal to the expected one.
+func (r *Reconciliation) Populate(expected []ExpectedPayment, payments []history.Operation) error {
+	byMemo := make(map[string][]history.Operation)
+	for _, payment := range payments {
+		if !payment.TransactionMemo.Valid {
+			continue
+		}
+		key := reconciliationKey(payment.TransactionMemoType, payment.TransactionMemo.String)
+		byMemo[key] = append(byMemo[key], payment)
+	}
+
+	r.Matched = []ReconciliationEntry{}
+	r.Missing = []ReconciliationEntry{}
+	r.Mismatched = []ReconciliationEntry{}
+	for _, item := range expected {
+		entry := ReconciliationEntry{
+			MemoType:       item.MemoType,
+			Memo:           item.Memo,
+			ExpectedAmount: amount.String(item.rawAmount),
+		}
+
+		received, ok := byMemo[item.Key()]
+		if !ok {
+			r.Missing = append(r.Missing, entry)
+			continue
+		}
+
+		var total xdr.Int64
+		entry.Payments = make([]string, len(received))
+		for i := range received {
+			var d statementDetails
+			err := received[i].UnmarshalDetails(&d)
+			if err != nil {
+				return err
+			}
+
+			receivedAmount, err := amount.Parse(d.Amount)
+			if err != nil {
+				return err
+			}
+			total += receivedAmount - xdr.Int64(received[i].RefundedAmount.Int64)
+			entry.Payments[i] = received[i].PagingToken()
+		}
+
+		entry.ReceivedAmount = amount.String(total)
+		if total == item.rawAmount {
+			r.Matched = append(r.Matched, entry)
+		} else {
+			r.Mismatched = append(r.Mismatched, entry)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package resource
+
+import (
+	"time"
+
+	"bitbucket.org/atticlab/go-smart-base/xdr"
+	"bitbucket.org/atticlab/horizon/db2/history"
+)
+
+// ReversalWindow is period within which payments of the asset between
+// accounts of specified types can be reversed
+type ReversalWindow struct {
+	ID                int64   `json:"id"`
+	PT                string  `json:"paging_token"`
+	AssetCode         *string `json:"asset_code,omitempty"`
+	AssetIssuer       *string `json:"asset_issuer,omitempty"`
+	SenderType        *string `json:"sender_type,omitempty"`
+	SenderTypeI       *int32  `json:"sender_type_i,omitempty"`
+	ReceiverType      *string `json:"receiver_type,omitempty"`
+	ReceiverTypeI     *int32  `json:"receiver_type_i,omitempty"`
+	DurationInSeconds int64   `json:"duration_in_seconds"`
+	DurationStr       string  `json:"duration_str"`
+}
+
+// Populate fills out the resource's fields
+func (res *ReversalWindow) Populate(row history.ReversalWindow) {
+	res.ID = row.ID
+	res.PT = row.PagingToken()
+	res.AssetCode = nil
+	if row.AssetCode != "" {
+		res.AssetCode = &row.AssetCode
+	}
+
+	res.AssetIssuer = nil
+	if row.AssetIssuer != "" {
+		res.AssetIssuer = &row.AssetIssuer
+	}
+
+	res.SenderTypeI, res.SenderType = nil, nil
+	if row.SenderType.Valid {
+		res.SenderTypeI, res.SenderType = PopulateAccountTypeP(xdr.AccountType(row.SenderType.Int64))
+	}
+
+	res.ReceiverTypeI, res.ReceiverType = nil, nil
+	if row.ReceiverType.Valid {
+		res.ReceiverTypeI, res.ReceiverType = PopulateAccountTypeP(xdr.AccountType(row.ReceiverType.Int64))
+	}
+
+	res.DurationInSeconds = row.Duration
+	res.DurationStr = (time.Duration(row.Duration) * time.Second).String()
+}
+
+// PagingToken implementation for hal.Pageable
+func (res ReversalWindow) PagingToken() string {
+	return res.PT
+}
@@ -17,7 +17,7 @@ func (res *Root) Populate(ctx context.Context, hSeq, cSeq int32, hVersion, cVers
 	lb := hal.LinkBuilder{httpx.BaseURL(ctx)}
 	res.Links.Account = lb.Link("/accounts/{account_id}")
 	res.Links.AccountTransactions = lb.PagedLink("/accounts/{account_id}/transactions")
-	res.Links.Friendbot = lb.Link("/friendbot{?addr}")
+	res.Links.Friendbot = lb.Link("/friendbot{?addr,account_type,asset_code,asset_issuer}")
 	res.Links.Metrics = lb.Link("/metrics")
 	res.Links.OrderBook = lb.Link("/order_book{?selling_asset_type,selling_asset_code,selling_issuer,buying_asset_type,buying_asset_code,buying_issuer}")
 	res.Links.Self = lb.Link("/")
@@ -0,0 +1,62 @@
+package resource
+
+import (
+	"fmt"
+	"time"
+
+	"bitbucket.org/atticlab/go-smart-base/xdr"
+	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/httpx"
+	"bitbucket.org/atticlab/horizon/render/hal"
+	"golang.org/x/net/context"
+)
+
+// TransactionScreening contains results of screening of the transaction's operations
+type TransactionScreening struct {
+	Links struct {
+		Self        hal.Link `json:"self"`
+		Transaction hal.Link `json:"transaction"`
+	} `json:"_links"`
+	TransactionHash string            `json:"transaction_hash"`
+	Results         []ScreeningResult `json:"results"`
+}
+
+// ScreeningResult is the decision of single screening provider for the operation
+type ScreeningResult struct {
+	OperationIndex int32     `json:"operation_index"`
+	Type           string    `json:"type"`
+	TypeI          int32     `json:"type_i"`
+	Source         string    `json:"source"`
+	Destination    string    `json:"destination"`
+	AssetCode      string    `json:"asset_code,omitempty"`
+	AssetIssuer    string    `json:"asset_issuer,omitempty"`
+	Provider       string    `json:"provider"`
+	Decision       string    `json:"decision"`
+	Reason         string    `json:"reason,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// Populate fills out the resource's fields
+func (res *TransactionScreening) Populate(ctx context.Context, txHash string, rows []history.ScreeningResult) {
+	res.TransactionHash = txHash
+	res.Results = make([]ScreeningResult, len(rows))
+	for i, row := range rows {
+		res.Results[i] = ScreeningResult{
+			OperationIndex: row.OperationIndex,
+			Type:           xdr.OperationType(row.OperationType).String(),
+			TypeI:          row.OperationType,
+			Source:         row.Source,
+			Destination:    row.Destination,
+			AssetCode:      row.AssetCode,
+			AssetIssuer:    row.AssetIssuer,
+			Provider:       row.Provider,
+			Decision:       row.Decision,
+			Reason:         row.Reason,
+			CreatedAt:      row.CreatedAt,
+		}
+	}
+
+	lb := hal.LinkBuilder{httpx.BaseURL(ctx)}
+	res.Links.Transaction = lb.Link(fmt.Sprintf("/transactions/%s", txHash))
+	res.Links.Self = lb.Link(fmt.Sprintf("/transactions/%s/screening", txHash))
+}
@@ -0,0 +1,173 @@
+package resource
+
+import (
+	"fmt"
+	"time"
+
+	"bitbucket.org/atticlab/go-smart-base/amount"
+	"bitbucket.org/atticlab/go-smart-base/xdr"
+	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/db2/history/details"
+	"bitbucket.org/atticlab/horizon/resource/operations"
+)
+
+// StatementEntry is a single debit/credit line of an account statement
+type StatementEntry struct {
+	ID              string    `json:"id"`
+	PagingToken     string    `json:"paging_token"`
+	TransactionHash string    `json:"transaction_hash"`
+	Type            string    `json:"type"`
+	TypeI           int32     `json:"type_i"`
+	ClosedAt        time.Time `json:"closed_at"`
+	Counterparty    string    `json:"counterparty"`
+	Debit           string    `json:"debit"`
+	Credit          string    `json:"credit"`
+	Commission      string    `json:"commission"`
+	Balance         string    `json:"balance"`
+
+	// Change is the net effect of the entry on the account's balance
+	Change int64 `json:"-"`
+}
+
+// statementDetails is a union of the details of all operations which move funds
+type statementDetails struct {
+	details.Asset
+	Fee               details.Fee `json:"fee"`
+	From              string      `json:"from"`
+	To                string      `json:"to"`
+	SourceAccount     string      `json:"source_account"`
+	PaymentSource     string      `json:"payment_source"`
+	Amount            string      `json:"amount"`
+	SourceAmount      string      `json:"source_amount"`
+	SourceAssetCode   string      `json:"source_asset_code"`
+	SourceAssetIssuer string      `json:"source_asset_issuer"`
+	Commission        string      `json:"commission"`
+}
+
+// Populate fills out the entry from the operation as seen by account `address`.
+// Returns false, if operation does not change balance of the asset with
+// `assetCode` and `assetIssuer` for the account.
+func (e *StatementEntry) Populate(row history.Operation, address, assetCode, assetIssuer string) (bool, error) {
+	var d statementDetails
+	err := row.UnmarshalDetails(&d)
+	if err != nil {
+		return false, err
+	}
+
+	isAsset := d.Code == assetCode && d.Issuer == assetIssuer
+	var debit, credit, commission xdr.Int64
+	switch row.Type {
+	case xdr.OperationTypePayment:
+		if !isAsset {
+			return false, nil
+		}
+
+		if d.From == address {
+			debit, err = parseStatementAmount(d.Amount)
+			if err != nil {
+				return false, err
+			}
+
+			commission, err = parseOptionalStatementAmount(d.Fee.AmountCharged)
+			if err != nil {
+				return false, err
+			}
+			e.Counterparty = d.To
+		}
+
+		if d.To == address {
+			credit, err = parseStatementAmount(d.Amount)
+			if err != nil {
+				return false, err
+			}
+			e.Counterparty = d.From
+		}
+	case xdr.OperationTypePathPayment:
+		if d.From == address && d.SourceAssetCode == assetCode && d.SourceAssetIssuer == assetIssuer {
+			debit, err = parseStatementAmount(d.SourceAmount)
+			if err != nil {
+				return false, err
+			}
+
+			commission, err = parseOptionalStatementAmount(d.Fee.AmountCharged)
+			if err != nil {
+				return false, err
+			}
+			e.Counterparty = d.To
+		}
+
+		if d.To == address && isAsset {
+			credit, err = parseStatementAmount(d.Amount)
+			if err != nil {
+				return false, err
+			}
+			e.Counterparty = d.From
+		}
+	case xdr.OperationTypePaymentReversal, xdr.OperationTypeRefund:
+		if !isAsset {
+			return false, nil
+		}
+
+		if d.SourceAccount == address {
+			debit, err = parseStatementAmount(d.Amount)
+			if err != nil {
+				return false, err
+			}
+			e.Counterparty = d.PaymentSource
+		}
+
+		if d.PaymentSource == address {
+			credit, err = parseStatementAmount(d.Amount)
+			if err != nil {
+				return false, err
+			}
+
+			// commission of reversed payment is returned to the sender
+			if row.Type == xdr.OperationTypePaymentReversal && d.Commission != "" {
+				commission, err = parseStatementAmount(d.Commission)
+				if err != nil {
+					return false, err
+				}
+				commission = -commission
+			}
+			e.Counterparty = d.SourceAccount
+		}
+	default:
+		return false, nil
+	}
+
+	if debit == 0 && credit == 0 && commission == 0 {
+		return false, nil
+	}
+
+	e.ID = fmt.Sprintf("%d", row.ID)
+	e.PagingToken = row.PagingToken()
+	e.TransactionHash = row.TransactionHash
+	e.Type = operations.TypeNames[row.Type]
+	e.TypeI = int32(row.Type)
+	e.ClosedAt = row.ClosedAt
+	e.Debit = amount.String(debit)
+	e.Credit = amount.String(credit)
+	e.Commission = amount.String(commission)
+	e.Change = int64(credit - debit - commission)
+	return true, nil
+}
+
+// SetBalance sets balance of the account after the entry was applied
+func (e *StatementEntry) SetBalance(balance int64) {
+	e.Balance = amount.String(xdr.Int64(balance))
+}
+
+func parseStatementAmount(raw string) (xdr.Int64, error) {
+	if raw == "" {
+		return 0, nil
+	}
+	return amount.Parse(raw)
+}
+
+func parseOptionalStatementAmount(raw *string) (xdr.Int64, error) {
+	if raw == nil {
+		return 0, nil
+	}
+	return parseStatementAmount(*raw)
+}
@@ -0,0 +1,93 @@
+package retention
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"bitbucket.org/atticlab/horizon/db2"
+)
+
+// Exporter writes rows of history tables into gzipped json lines files
+type Exporter struct {
+	dir string
+}
+
+// NewExporter creates new exporter writing files into dir
+func NewExporter(dir string) *Exporter {
+	return &Exporter{
+		dir: dir,
+	}
+}
+
+// FileName returns name of the file rows of the table with ids in [start, end) are exported to
+func (e *Exporter) FileName(table string, start, end int64) string {
+	return filepath.Join(e.dir, fmt.Sprintf("%s_%d_%d.jsonl.gz", table, start, end))
+}
+
+// Export writes rows of the table with idCol in [start, end) to the file. File is
+// created atomically: data is written to temporary file, which is renamed when done.
+func (e *Exporter) Export(repo *db2.Repo, table, idCol string, start, end int64) error {
+	err := os.MkdirAll(e.dir, 0755)
+	if err != nil {
+		return err
+	}
+
+	fileName := e.FileName(table, start, end)
+	tmpFileName := fileName + ".tmp"
+	file, err := os.Create(tmpFileName)
+	if err != nil {
+		return err
+	}
+
+	err = e.write(file, repo, table, idCol, start, end)
+	if err == nil {
+		err = file.Sync()
+	}
+
+	closeErr := file.Close()
+	if err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		os.Remove(tmpFileName)
+		return err
+	}
+
+	return os.Rename(tmpFileName, fileName)
+}
+
+func (e *Exporter) write(w io.Writer, repo *db2.Repo, table, idCol string, start, end int64) error {
+	rows, err := repo.QueryRaw(
+		fmt.Sprintf("SELECT row_to_json(t)::text FROM %s t WHERE t.%s >= ? AND t.%s < ? ORDER BY t.%s", table, idCol, idCol, idCol),
+		start, end,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	gz := gzip.NewWriter(w)
+	for rows.Next() {
+		var row string
+		err = rows.Scan(&row)
+		if err != nil {
+			return err
+		}
+
+		_, err = io.WriteString(gz, row+"\n")
+		if err != nil {
+			return err
+		}
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return err
+	}
+
+	return gz.Close()
+}
@@ -0,0 +1,294 @@
+// Package retention contains the pruner, which deletes history rows older than
+// the configured retention policy. Pruned rows are exported to compressed files
+// before being deleted. Aggregates (ledgers, accounts, account statistics, assets)
+// are never pruned.
+//
+// Operations referenced by open disputes and unacknowledged compliance alerts
+// are kept along with all later history until the dispute is closed or the
+// alert is acknowledged. Other references are not followed: closed disputes and
+// their notes, acknowledged alerts, account freezes, account traits history and
+// asset supply changes keep ids of pruned operations, and links to them respond
+// with 404.
+package retention
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	conf "bitbucket.org/atticlab/horizon/config"
+	"bitbucket.org/atticlab/horizon/db2"
+	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/log"
+	"bitbucket.org/atticlab/horizon/toid"
+	sq "github.com/lann/squirrel"
+)
+
+const (
+	// DefaultBatchSize is the number of ledgers pruned in a single db transaction
+	DefaultBatchSize = 100
+	// DefaultInterval is the time between pruning sessions
+	DefaultInterval = time.Hour
+)
+
+// table describes history table which can be pruned
+type table struct {
+	Name  string
+	IDCol string
+	// Retention returns period rows of the table must be kept for
+	Retention func(policy conf.RetentionPolicy) time.Duration
+}
+
+// tables are ordered so that dependent rows are pruned before rows they refer to.
+// Dependent rows are never kept longer than rows they refer to.
+var tables = []table{
+	{"history_effects", "history_operation_id", func(p conf.RetentionPolicy) time.Duration {
+		return minRetention(p.Effects, p.Operations, p.Transactions)
+	}},
+	{"history_operation_participants", "history_operation_id", func(p conf.RetentionPolicy) time.Duration {
+		return minRetention(p.Operations, p.Transactions)
+	}},
+	{"payment_refunds", "id", func(p conf.RetentionPolicy) time.Duration {
+		return minRetention(p.Operations, p.Transactions)
+	}},
+	{"history_operations", "id", func(p conf.RetentionPolicy) time.Duration {
+		return minRetention(p.Operations, p.Transactions)
+	}},
+	{"history_transaction_participants", "history_transaction_id", func(p conf.RetentionPolicy) time.Duration {
+		return p.Transactions
+	}},
+	{"history_transactions", "id", func(p conf.RetentionPolicy) time.Duration {
+		return p.Transactions
+	}},
+}
+
+// minRetention returns min non zero period. Returns 0 if all periods are zero.
+func minRetention(periods ...time.Duration) (result time.Duration) {
+	for _, period := range periods {
+		if period <= 0 {
+			continue
+		}
+
+		if result == 0 || period < result {
+			result = period
+		}
+	}
+	return
+}
+
+// Pruner periodically deletes history rows which are out of retention period
+type Pruner struct {
+	repo     *db2.Repo
+	policy   conf.RetentionPolicy
+	exporter *Exporter
+	log      *log.Entry
+
+	// IsActive restricts pruning to the time it returns true, e.g. while the
+	// replica is the ingestion leader. Not restricted if nil
+	IsActive func() bool
+
+	tick *time.Ticker
+	done chan struct{}
+}
+
+// New creates new pruner. Call Start to begin pruning.
+func New(repo *db2.Repo, policy conf.RetentionPolicy) *Pruner {
+	if policy.BatchSize <= 0 {
+		policy.BatchSize = DefaultBatchSize
+	}
+
+	if policy.Interval <= 0 {
+		policy.Interval = DefaultInterval
+	}
+
+	var exporter *Exporter
+	if policy.ExportDir != "" {
+		exporter = NewExporter(policy.ExportDir)
+	}
+
+	return &Pruner{
+		repo:     repo,
+		policy:   policy,
+		exporter: exporter,
+		log:      log.WithField("service", "pruner"),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start causes the pruner to periodically prune history tables
+func (p *Pruner) Start() {
+	if p.exporter == nil {
+		p.log.Warn("Export directory is not set. Pruned rows will not be exported")
+	}
+
+	p.tick = time.NewTicker(p.policy.Interval)
+	go p.run()
+}
+
+// Close stops the pruner. Batch in progress is finished.
+func (p *Pruner) Close() {
+	p.log.Info("canceling pruner")
+	if p.tick != nil {
+		p.tick.Stop()
+	}
+	close(p.done)
+}
+
+func (p *Pruner) run() {
+	p.runOnce()
+	for {
+		select {
+		case <-p.tick.C:
+			p.runOnce()
+		case <-p.done:
+			return
+		}
+	}
+}
+
+func (p *Pruner) runOnce() {
+	if p.IsActive != nil && !p.IsActive() {
+		p.log.Debug("Pruner is not active, skipping")
+		return
+	}
+
+	err := p.Prune(time.Now())
+	if err != nil {
+		p.log.WithStack(err).WithError(err).Error("Failed to prune history")
+	}
+}
+
+// Prune deletes rows of all tables that are out of retention period at `now`
+func (p *Pruner) Prune(now time.Time) error {
+	q := history.Q{Repo: p.repo}
+	protectedSeq, err := p.protectedLedger()
+	if err != nil {
+		return err
+	}
+
+	for _, t := range tables {
+		retention := t.Retention(p.policy)
+		if retention <= 0 {
+			continue
+		}
+
+		var cutoffSeq int32
+		err = q.LatestLedgerClosedBefore(&cutoffSeq, now.Add(-retention))
+		if err != nil {
+			return err
+		}
+
+		if protectedSeq > 0 && cutoffSeq >= protectedSeq {
+			cutoffSeq = protectedSeq - 1
+		}
+
+		if cutoffSeq == 0 {
+			continue
+		}
+
+		err = p.pruneTable(t, cutoffSeq)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// protectedLedger returns the earliest ledger with operations referenced by
+// open disputes or unacknowledged compliance alerts. Returns 0 if there are
+// no such operations.
+func (p *Pruner) protectedLedger() (int32, error) {
+	var operationID sql.NullInt64
+	err := p.repo.GetRaw(&operationID, `SELECT MIN(id) FROM (
+		SELECT payment_id AS id FROM disputes WHERE state IN ($1, $2)
+		UNION ALL
+		SELECT op::bigint AS id FROM compliance_alerts, json_array_elements_text(operations::json) op
+		WHERE acknowledged = false
+	) referenced`, string(history.DisputeStateOpen), string(history.DisputeStateMerchantResponded))
+	if err != nil || !operationID.Valid {
+		return 0, err
+	}
+
+	return toid.Parse(operationID.Int64).LedgerSequence, nil
+}
+
+// pruneTable deletes rows of ledgers up to cutoffSeq inclusive in batches.
+// Each batch starts at the next existing row, so gaps in history do not
+// produce empty batches and export files.
+func (p *Pruner) pruneTable(t table, cutoffSeq int32) error {
+	end := cutoffSeq + 1
+	var start int32
+	for {
+		select {
+		case <-p.done:
+			return nil
+		default:
+		}
+
+		startID := toid.ID{LedgerSequence: start}
+		var nextID sql.NullInt64
+		err := p.repo.GetRaw(&nextID, fmt.Sprintf("SELECT MIN(%s) FROM %s WHERE %s >= $1", t.IDCol, t.Name, t.IDCol), startID.ToInt64())
+		if err != nil {
+			return err
+		}
+
+		if !nextID.Valid {
+			return nil
+		}
+
+		start = toid.Parse(nextID.Int64).LedgerSequence
+		if start >= end {
+			return nil
+		}
+
+		batchEnd := start + p.policy.BatchSize
+		if batchEnd > end {
+			batchEnd = end
+		}
+
+		startID = toid.ID{LedgerSequence: start}
+		endID := toid.ID{LedgerSequence: batchEnd}
+		err = p.pruneRange(t, startID.ToInt64(), endID.ToInt64())
+		if err != nil {
+			return err
+		}
+		start = batchEnd
+	}
+}
+
+// pruneRange exports and deletes rows of the table with ids in [start, end)
+func (p *Pruner) pruneRange(t table, start, end int64) error {
+	repo := p.repo.Clone()
+	err := repo.Begin()
+	if err != nil {
+		return err
+	}
+	defer repo.Rollback()
+
+	if p.exporter != nil {
+		err = p.exporter.Export(repo, t.Name, t.IDCol, start, end)
+		if err != nil {
+			return err
+		}
+	}
+
+	del := sq.Delete(t.Name).Where(fmt.Sprintf("%s >= ? AND %s < ?", t.IDCol, t.IDCol), start, end)
+	result, err := repo.Exec(del)
+	if err != nil {
+		return err
+	}
+
+	err = repo.Commit()
+	if err != nil {
+		return err
+	}
+
+	deleted, _ := result.RowsAffected()
+	p.log.WithFields(log.F{
+		"table":   t.Name,
+		"start":   start,
+		"end":     end,
+		"deleted": deleted,
+	}).Info("Pruned history range")
+	return nil
+}
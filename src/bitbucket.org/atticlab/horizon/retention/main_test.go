@@ -0,0 +1,112 @@
+package retention
+
+import (
+	"testing"
+	"time"
+
+	conf "bitbucket.org/atticlab/horizon/config"
+	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/test"
+	"bitbucket.org/atticlab/horizon/toid"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRetention(t *testing.T) {
+	Convey("Retention policy:", t, func() {
+		day := 24 * time.Hour
+		retentionOf := func(policy conf.RetentionPolicy) map[string]time.Duration {
+			result := make(map[string]time.Duration)
+			for _, t := range tables {
+				result[t.Name] = t.Retention(policy)
+			}
+			return result
+		}
+		Convey("Nothing is pruned by default", func() {
+			policy := conf.RetentionPolicy{}
+			So(policy.IsEnabled(), ShouldBeFalse)
+			for _, retention := range retentionOf(policy) {
+				So(retention, ShouldEqual, 0)
+			}
+		})
+		Convey("Effects are kept only for their own period", func() {
+			policy := conf.RetentionPolicy{Effects: 2 * 365 * day}
+			So(policy.IsEnabled(), ShouldBeTrue)
+			So(retentionOf(policy), ShouldResemble, map[string]time.Duration{
+				"history_effects":                  2 * 365 * day,
+				"history_operation_participants":   0,
+				"payment_refunds":                  0,
+				"history_operations":               0,
+				"history_transaction_participants": 0,
+				"history_transactions":             0,
+			})
+		})
+		Convey("Dependent rows are not kept longer than rows they refer to", func() {
+			policy := conf.RetentionPolicy{Effects: 2 * 365 * day, Transactions: 365 * day}
+			So(retentionOf(policy), ShouldResemble, map[string]time.Duration{
+				"history_effects":                  365 * day,
+				"history_operation_participants":   365 * day,
+				"payment_refunds":                  365 * day,
+				"history_operations":               365 * day,
+				"history_transaction_participants": 365 * day,
+				"history_transactions":             365 * day,
+			})
+		})
+		Convey("Export file name", func() {
+			exporter := NewExporter("/tmp/export")
+			So(exporter.FileName("history_effects", 10, 20), ShouldEqual, "/tmp/export/history_effects_10_20.jsonl.gz")
+		})
+	})
+}
+
+func TestProtectedLedger(t *testing.T) {
+	tt := test.Start(t).Scenario("base")
+	defer tt.Finish()
+	q := &history.Q{Repo: tt.HorizonRepo()}
+	pruner := New(tt.HorizonRepo(), conf.RetentionPolicy{Operations: time.Hour})
+
+	Convey("Protected ledger:", t, func() {
+		seq, err := pruner.protectedLedger()
+		So(err, ShouldBeNil)
+		So(seq, ShouldEqual, 0)
+
+		dispute := history.Dispute{
+			PaymentID:  toid.New(4, 1, 1).ToInt64(),
+			State:      history.DisputeStateOpen,
+			Claimant:   "claimant",
+			Respondent: "respondent",
+			AssetCode:  "UAH",
+			Amount:     100,
+		}
+		So(q.DisputeInsert(&dispute), ShouldBeNil)
+
+		alert := history.ComplianceAlert{
+			Rule:           "rule",
+			Address:        "address",
+			AssetCode:      "UAH",
+			LedgerSequence: 5,
+		}
+		So(alert.SetOperations([]int64{toid.New(5, 1, 1).ToInt64(), toid.New(3, 1, 1).ToInt64()}), ShouldBeNil)
+		So(q.ComplianceAlertInsert(&alert), ShouldBeNil)
+		So(q.GetRaw(&alert.ID, "SELECT id FROM compliance_alerts"), ShouldBeNil)
+
+		Convey("earliest operation of unacknowledged alert", func() {
+			seq, err := pruner.protectedLedger()
+			So(err, ShouldBeNil)
+			So(seq, ShouldEqual, 3)
+		})
+		Convey("acknowledged alerts and closed disputes are not protected", func() {
+			_, err := q.ComplianceAlertAcknowledge(alert.ID, "", time.Now())
+			So(err, ShouldBeNil)
+			seq, err := pruner.protectedLedger()
+			So(err, ShouldBeNil)
+			So(seq, ShouldEqual, 4)
+
+			dispute.State = history.DisputeStateResolved
+			_, err = q.DisputeUpdate(&dispute)
+			So(err, ShouldBeNil)
+			seq, err = pruner.protectedLedger()
+			So(err, ShouldBeNil)
+			So(seq, ShouldEqual, 0)
+		})
+	})
+}
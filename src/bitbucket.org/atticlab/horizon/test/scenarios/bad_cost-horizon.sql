@@ -728,6 +728,16 @@ GRANT ALL ON SCHEMA public TO nullstyle;
 GRANT ALL ON SCHEMA public TO PUBLIC;
 
 
+CREATE TABLE payment_refunds
+(
+  id bigint NOT NULL,
+  payment_id bigint NOT NULL,
+  type integer NOT NULL,
+  amount bigint NOT NULL,
+  PRIMARY KEY(id)
+);
+
+
 --
 -- PostgreSQL database dump complete
 --
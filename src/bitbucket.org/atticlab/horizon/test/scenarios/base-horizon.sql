@@ -70,6 +70,23 @@ DROP TABLE IF EXISTS public.gorp_migrations;
 DROP SEQUENCE IF EXISTS public.commission_id_seq;
 DROP TABLE IF EXISTS public.commission;
 DROP TABLE IF EXISTS public.options CASCADE;
+DROP TABLE IF EXISTS public.account_freezes CASCADE;
+DROP TABLE IF EXISTS public.account_traits_history CASCADE;
+DROP TABLE IF EXISTS public.reversal_windows CASCADE;
+DROP TABLE IF EXISTS public.payment_refunds CASCADE;
+DROP TABLE IF EXISTS public.disputes CASCADE;
+DROP TABLE IF EXISTS public.dispute_notes CASCADE;
+DROP TABLE IF EXISTS public.statistics_cache CASCADE;
+DROP TABLE IF EXISTS public.statistics_processed_ops CASCADE;
+DROP TABLE IF EXISTS public.txsub_pending CASCADE;
+DROP TABLE IF EXISTS public.txsub_sequence_reservations CASCADE;
+DROP TABLE IF EXISTS public.leader_leases CASCADE;
+DROP TABLE IF EXISTS public.leader_fences CASCADE;
+DROP TABLE IF EXISTS public.asset_supply_changes CASCADE;
+DROP TABLE IF EXISTS public.screening_list CASCADE;
+DROP TABLE IF EXISTS public.screening_results CASCADE;
+DROP TABLE IF EXISTS public.compliance_alerts CASCADE;
+DROP TABLE IF EXISTS public.payment_refund_reservations CASCADE;
 DROP SEQUENCE IF EXISTS public.asset_id_seq;
 DROP TABLE IF EXISTS public.asset;
 DROP TABLE IF EXISTS public.account_statistics;
@@ -163,7 +180,13 @@ CREATE TABLE asset (
     type integer NOT NULL,
     code character varying(12) NOT NULL,
     issuer character varying(64) NOT NULL,
-    is_anonymous boolean NOT NULL
+    is_anonymous boolean NOT NULL,
+    name character varying(64) DEFAULT ''::character varying NOT NULL,
+    decimals integer DEFAULT 2 NOT NULL,
+    description text DEFAULT ''::text NOT NULL,
+    logo_url text DEFAULT ''::text NOT NULL,
+    regulatory_flags text DEFAULT '[]'::text NOT NULL,
+    status character varying(16) DEFAULT 'active'::character varying NOT NULL
 );
 
 
@@ -328,6 +351,204 @@ CREATE TABLE options
   PRIMARY KEY(name)
 );
 
+CREATE TABLE account_freezes
+(
+  id bigserial,
+  address varchar(64) NOT NULL,
+  asset_code varchar(12) NOT NULL DEFAULT '',
+  block_incoming_payments boolean NOT NULL DEFAULT false,
+  block_outcoming_payments boolean NOT NULL DEFAULT false,
+  reason_code varchar(32) NOT NULL,
+  comment text NOT NULL DEFAULT '',
+  expires_at timestamp without time zone,
+  lifted_at timestamp without time zone,
+  created_at timestamp without time zone NOT NULL DEFAULT now(),
+  operation_id bigint,
+  PRIMARY KEY(id)
+);
+
+CREATE TABLE account_traits_history
+(
+  id bigserial,
+  address varchar(64) NOT NULL,
+  event varchar(16) NOT NULL,
+  freeze_id bigint,
+  asset_code varchar(12) NOT NULL DEFAULT '',
+  block_incoming_payments boolean NOT NULL DEFAULT false,
+  block_outcoming_payments boolean NOT NULL DEFAULT false,
+  reason_code varchar(32) NOT NULL DEFAULT '',
+  comment text NOT NULL DEFAULT '',
+  expires_at timestamp without time zone,
+  created_at timestamp without time zone NOT NULL DEFAULT now(),
+  operation_id bigint,
+  PRIMARY KEY(id)
+);
+
+CREATE TABLE reversal_windows
+(
+  id bigserial,
+  asset_code varchar(12) NOT NULL DEFAULT '',
+  sender_type integer,
+  receiver_type integer,
+  duration bigint NOT NULL,
+  asset_issuer varchar(64) NOT NULL DEFAULT '',
+  PRIMARY KEY(id)
+);
+
+CREATE UNIQUE INDEX reversal_windows_by_key ON reversal_windows USING btree (asset_code, asset_issuer, COALESCE(sender_type, -1), COALESCE(receiver_type, -1));
+
+CREATE TABLE payment_refunds
+(
+  id bigint NOT NULL,
+  payment_id bigint NOT NULL,
+  type integer NOT NULL,
+  amount bigint NOT NULL,
+  PRIMARY KEY(id)
+);
+
+CREATE TABLE disputes
+(
+  id bigserial,
+  payment_id bigint NOT NULL,
+  state varchar(32) NOT NULL,
+  claimant varchar(64) NOT NULL,
+  respondent varchar(64) NOT NULL,
+  asset_code varchar(12) NOT NULL,
+  amount bigint NOT NULL,
+  freeze_id bigint,
+  resolution_operation_id bigint,
+  created_at timestamp without time zone NOT NULL DEFAULT now(),
+  updated_at timestamp without time zone NOT NULL DEFAULT now(),
+  PRIMARY KEY(id)
+);
+
+CREATE TABLE dispute_notes
+(
+  id bigserial,
+  dispute_id bigint NOT NULL,
+  state varchar(32) NOT NULL DEFAULT '',
+  note text NOT NULL DEFAULT '',
+  evidence text NOT NULL DEFAULT '[]',
+  created_at timestamp without time zone NOT NULL DEFAULT now(),
+  PRIMARY KEY(id)
+);
+
+CREATE TABLE statistics_cache
+(
+  address varchar(64) NOT NULL,
+  asset_code varchar(12) NOT NULL,
+  balance bigint NOT NULL,
+  statistics text NOT NULL,
+  expires_at timestamp without time zone NOT NULL,
+  PRIMARY KEY(address, asset_code)
+);
+
+CREATE INDEX statistics_cache_by_expiration ON statistics_cache USING btree (expires_at);
+
+CREATE TABLE statistics_processed_ops
+(
+  address varchar(64) NOT NULL,
+  tx_hash varchar(64) NOT NULL,
+  op_index integer NOT NULL,
+  is_incoming boolean NOT NULL,
+  amount bigint NOT NULL,
+  updated_at timestamp without time zone NOT NULL,
+  expires_at timestamp without time zone NOT NULL,
+  PRIMARY KEY(address, tx_hash, op_index, is_incoming)
+);
+
+CREATE TABLE txsub_pending
+(
+  hash varchar(64) NOT NULL PRIMARY KEY,
+  submitted_at timestamp without time zone NOT NULL
+);
+
+CREATE TABLE txsub_sequence_reservations
+(
+  address varchar(64) NOT NULL,
+  sequence bigint NOT NULL,
+  hash varchar(64) NOT NULL,
+  expires_at timestamp without time zone NOT NULL,
+  accepted boolean NOT NULL DEFAULT false,
+  PRIMARY KEY(address, sequence)
+);
+
+CREATE TABLE leader_leases
+(
+  name varchar(64) NOT NULL PRIMARY KEY,
+  holder varchar(255) NOT NULL,
+  expires_at timestamp without time zone NOT NULL
+);
+
+CREATE TABLE leader_fences
+(
+  name varchar(64) NOT NULL PRIMARY KEY,
+  token bigint NOT NULL
+);
+
+CREATE TABLE asset_supply_changes
+(
+  id bigserial,
+  operation_id bigint NOT NULL,
+  asset_code varchar(12) NOT NULL,
+  asset_issuer varchar(64) NOT NULL,
+  issued bigint NOT NULL DEFAULT 0,
+  redeemed bigint NOT NULL DEFAULT 0,
+  closed_at timestamp without time zone NOT NULL,
+  PRIMARY KEY(id)
+);
+
+CREATE TABLE screening_list
+(
+  address varchar(64) NOT NULL,
+  reason text NOT NULL DEFAULT '',
+  created_at timestamp without time zone NOT NULL DEFAULT now(),
+  PRIMARY KEY(address)
+);
+
+CREATE TABLE screening_results
+(
+  id bigserial,
+  tx_hash character varying(64) NOT NULL,
+  operation_index integer NOT NULL,
+  operation_type integer NOT NULL,
+  source varchar(64) NOT NULL,
+  destination varchar(64) NOT NULL,
+  provider varchar(32) NOT NULL,
+  decision varchar(16) NOT NULL,
+  reason text NOT NULL DEFAULT '',
+  created_at timestamp without time zone NOT NULL DEFAULT now(),
+  asset_code varchar(12) NOT NULL DEFAULT '',
+  asset_issuer varchar(64) NOT NULL DEFAULT '',
+  PRIMARY KEY(id)
+);
+
+CREATE TABLE compliance_alerts
+(
+  id bigserial,
+  rule varchar(32) NOT NULL,
+  address varchar(64) NOT NULL,
+  asset_code varchar(12) NOT NULL,
+  ledger_sequence integer NOT NULL,
+  description text NOT NULL,
+  operations text NOT NULL,
+  acknowledged boolean NOT NULL DEFAULT false,
+  acknowledged_at timestamp without time zone,
+  comment text NOT NULL DEFAULT '',
+  created_at timestamp without time zone NOT NULL DEFAULT now(),
+  PRIMARY KEY(id)
+);
+
+CREATE TABLE payment_refund_reservations
+(
+  tx_hash varchar(64) NOT NULL,
+  op_index integer NOT NULL,
+  payment_id bigint NOT NULL,
+  amount bigint NOT NULL,
+  expires_at timestamp without time zone NOT NULL,
+  PRIMARY KEY(tx_hash, op_index)
+);
+
 
 --
 -- Name: history_transaction_participants; Type: TABLE; Schema: public; Owner: -
@@ -0,0 +1,294 @@
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"bitbucket.org/atticlab/horizon/log"
+	"golang.org/x/net/context"
+)
+
+// Exporter sends finished spans to the tracing backend
+type Exporter interface {
+	Export(spans []*Span) error
+}
+
+// BatchProcessor buffers finished spans and exports them in batches from
+// background goroutine, so requests do not wait for the exporter. Spans are
+// dropped if buffer is full.
+type BatchProcessor struct {
+	exporter     Exporter
+	queue        chan *Span
+	maxBatchSize int
+	interval     time.Duration
+	log          *log.Entry
+}
+
+// NewBatchProcessor creates processor exporting spans every interval
+func NewBatchProcessor(exporter Exporter, interval time.Duration) *BatchProcessor {
+	return &BatchProcessor{
+		exporter:     exporter,
+		queue:        make(chan *Span, 2048),
+		maxBatchSize: 512,
+		interval:     interval,
+		log:          log.WithField("service", "tracing"),
+	}
+}
+
+// OnEnd queues finished span for export
+func (p *BatchProcessor) OnEnd(span *Span) {
+	select {
+	case p.queue <- span:
+	default:
+		p.log.Debug("Span queue is full, dropping span")
+	}
+}
+
+// Run exports queued spans until ctx is canceled. Spans left in queue are
+// exported before return.
+func (p *BatchProcessor) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	batch := make([]*Span, 0, p.maxBatchSize)
+	for {
+		select {
+		case span := <-p.queue:
+			batch = append(batch, span)
+			if len(batch) >= p.maxBatchSize {
+				batch = p.export(batch)
+			}
+		case <-ticker.C:
+			batch = p.export(batch)
+		case <-ctx.Done():
+			for {
+				select {
+				case span := <-p.queue:
+					batch = append(batch, span)
+				default:
+					p.export(batch)
+					return
+				}
+			}
+		}
+	}
+}
+
+func (p *BatchProcessor) export(batch []*Span) []*Span {
+	if len(batch) == 0 {
+		return batch
+	}
+
+	err := p.exporter.Export(batch)
+	if err != nil {
+		p.log.WithError(err).WithField("spans", len(batch)).Warn("Failed to export spans")
+	}
+
+	return batch[:0]
+}
+
+// WriterExporter writes spans as JSON lines. Used to store traces in file or
+// print them to stdout for offline use.
+type WriterExporter struct {
+	lock sync.Mutex
+	w    io.Writer
+}
+
+// NewWriterExporter creates exporter writing to w
+func NewWriterExporter(w io.Writer) *WriterExporter {
+	return &WriterExporter{
+		w: w,
+	}
+}
+
+// writtenSpan is a representation of the span written by WriterExporter
+type writtenSpan struct {
+	TraceID    string                 `json:"trace_id"`
+	SpanID     string                 `json:"span_id"`
+	ParentID   string                 `json:"parent_span_id,omitempty"`
+	Name       string                 `json:"name"`
+	Kind       SpanKind               `json:"kind"`
+	StartTime  time.Time              `json:"start_time"`
+	EndTime    time.Time              `json:"end_time"`
+	Duration   string                 `json:"duration"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+}
+
+func (e *WriterExporter) Export(spans []*Span) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	encoder := json.NewEncoder(e.w)
+	for _, span := range spans {
+		var parentID string
+		if span.ParentID.IsValid() {
+			parentID = span.ParentID.String()
+		}
+
+		err := encoder.Encode(writtenSpan{
+			TraceID:    span.Context.TraceID.String(),
+			SpanID:     span.Context.SpanID.String(),
+			ParentID:   parentID,
+			Name:       span.Name,
+			Kind:       span.Kind,
+			StartTime:  span.StartTime,
+			EndTime:    span.EndTime,
+			Duration:   span.EndTime.Sub(span.StartTime).String(),
+			Attributes: span.Attributes,
+			Error:      span.Error,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// OTLPExporter sends spans to OpenTelemetry collector using OTLP/HTTP
+// protocol with JSON encoding
+type OTLPExporter struct {
+	endpoint    string
+	serviceName string
+	client      *http.Client
+}
+
+// NewOTLPExporter creates exporter. Endpoint is a base url of the collector,
+// e.g. http://localhost:4318
+func NewOTLPExporter(endpoint, serviceName string, client *http.Client) *OTLPExporter {
+	return &OTLPExporter{
+		endpoint:    strings.TrimSuffix(endpoint, "/") + "/v1/traces",
+		serviceName: serviceName,
+		client:      client,
+	}
+}
+
+func (e *OTLPExporter) Export(spans []*Span) error {
+	body, err := json.Marshal(e.request(spans))
+	if err != nil {
+		return err
+	}
+
+	resp, err := e.client.Post(e.endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("collector responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+type otlpAttribute struct {
+	Key   string                 `json:"key"`
+	Value map[string]interface{} `json:"value"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              SpanKind        `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+// otlp status codes
+const (
+	otlpStatusOK    = 1
+	otlpStatusError = 2
+)
+
+func (e *OTLPExporter) request(spans []*Span) map[string]interface{} {
+	result := make([]otlpSpan, 0, len(spans))
+	for _, span := range spans {
+		s := otlpSpan{
+			TraceID:           span.Context.TraceID.String(),
+			SpanID:            span.Context.SpanID.String(),
+			Name:              span.Name,
+			Kind:              span.Kind,
+			StartTimeUnixNano: strconv.FormatInt(span.StartTime.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(span.EndTime.UnixNano(), 10),
+			Attributes:        otlpAttributes(span.Attributes),
+			Status:            otlpStatus{Code: otlpStatusOK},
+		}
+		if span.ParentID.IsValid() {
+			s.ParentSpanID = span.ParentID.String()
+		}
+		if span.Error != "" {
+			s.Status = otlpStatus{Code: otlpStatusError, Message: span.Error}
+		}
+		result = append(result, s)
+	}
+
+	return map[string]interface{}{
+		"resourceSpans": []interface{}{
+			map[string]interface{}{
+				"resource": map[string]interface{}{
+					"attributes": otlpAttributes(map[string]interface{}{
+						"service.name": e.serviceName,
+					}),
+				},
+				"scopeSpans": []interface{}{
+					map[string]interface{}{
+						"scope": map[string]interface{}{"name": e.serviceName},
+						"spans": result,
+					},
+				},
+			},
+		},
+	}
+}
+
+// otlpAttributes converts attributes to OTLP key-values in order of keys
+func otlpAttributes(attributes map[string]interface{}) []otlpAttribute {
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := make([]otlpAttribute, 0, len(keys))
+	for _, key := range keys {
+		var value map[string]interface{}
+		switch v := attributes[key].(type) {
+		case bool:
+			value = map[string]interface{}{"boolValue": v}
+		case int:
+			value = map[string]interface{}{"intValue": strconv.Itoa(v)}
+		case int32:
+			value = map[string]interface{}{"intValue": strconv.FormatInt(int64(v), 10)}
+		case int64:
+			value = map[string]interface{}{"intValue": strconv.FormatInt(v, 10)}
+		case float64:
+			value = map[string]interface{}{"doubleValue": v}
+		case string:
+			value = map[string]interface{}{"stringValue": v}
+		default:
+			value = map[string]interface{}{"stringValue": fmt.Sprint(v)}
+		}
+		result = append(result, otlpAttribute{Key: key, Value: value})
+	}
+
+	return result
+}
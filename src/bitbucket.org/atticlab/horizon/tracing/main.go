@@ -0,0 +1,257 @@
+// Package tracing records spans of horizon's work (http requests, db queries,
+// redis commands, transaction submission and ingestion) and exports them in
+// OpenTelemetry format. Trace context is propagated with W3C `traceparent`
+// header.
+//
+// Tracing is disabled until Init is called, all spans are noop in that case.
+package tracing
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+var contextKey = 0
+
+// TraceID identifies all spans of one trace
+type TraceID [16]byte
+
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// IsValid returns false for all-zero id
+func (id TraceID) IsValid() bool {
+	return id != TraceID{}
+}
+
+// SpanID identifies span within the trace
+type SpanID [8]byte
+
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// IsValid returns false for all-zero id
+func (id SpanID) IsValid() bool {
+	return id != SpanID{}
+}
+
+// SpanContext is a part of the span propagated to its children and remote services
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+}
+
+// IsValid returns true if both ids are set
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// SpanKind describes relationship of the span to remote parent or children
+type SpanKind int
+
+// Values match OpenTelemetry span kinds
+const (
+	SpanKindInternal SpanKind = 1
+	SpanKindServer   SpanKind = 2
+	SpanKindClient   SpanKind = 3
+)
+
+// Span is a single timed operation of the trace. Methods of the nil span are
+// noop, so callers do not check if tracing is enabled.
+type Span struct {
+	Name       string
+	Kind       SpanKind
+	Context    SpanContext
+	ParentID   SpanID
+	StartTime  time.Time
+	EndTime    time.Time
+	Attributes map[string]interface{}
+	// Error is set if operation failed
+	Error string
+
+	lock   sync.Mutex
+	ended  bool
+	tracer *Tracer
+}
+
+// SetAttribute sets attribute of the span
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.Attributes[key] = value
+}
+
+// SetError marks span as failed, if err is not nil
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.Error = err.Error()
+}
+
+// End finishes the span and passes it to exporter. Subsequent calls are noop.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+
+	s.lock.Lock()
+	if s.ended {
+		s.lock.Unlock()
+		return
+	}
+	s.ended = true
+	s.EndTime = time.Now()
+	s.lock.Unlock()
+
+	if s.Context.Sampled {
+		s.tracer.processor.OnEnd(s)
+	}
+}
+
+// TraceID returns id of the trace as hex string or empty string for nil span
+func (s *Span) TraceID() string {
+	if s == nil {
+		return ""
+	}
+	return s.Context.TraceID.String()
+}
+
+// Tracer creates spans and passes finished ones to the processor
+type Tracer struct {
+	processor *BatchProcessor
+	// traces with id below the threshold are sampled
+	sampleThreshold uint64
+	sampleAll       bool
+}
+
+// NewTracer creates tracer exporting spans of all traces in batches
+func NewTracer(processor *BatchProcessor) *Tracer {
+	return &Tracer{
+		processor: processor,
+		sampleAll: true,
+	}
+}
+
+// SetSampleRatio makes tracer export only the ratio (0..1) of traces started
+// by horizon. Decision is made by trace id, so it is the same on all
+// instances. Children of incoming and local spans follow the decision of the
+// parent.
+func (t *Tracer) SetSampleRatio(ratio float64) *Tracer {
+	t.sampleAll = ratio >= 1
+	switch {
+	case ratio <= 0:
+		t.sampleThreshold = 0
+	case ratio < 1:
+		t.sampleThreshold = uint64(ratio * math.MaxUint64)
+	}
+	return t
+}
+
+func (t *Tracer) shouldSample(id TraceID) bool {
+	if t.sampleAll {
+		return true
+	}
+	return binary.BigEndian.Uint64(id[8:]) < t.sampleThreshold
+}
+
+// Start starts new span, child of the span in ctx or of the remote span
+// extracted from incoming request. Returned context holds the new span.
+func (t *Tracer) Start(ctx context.Context, name string, kind SpanKind) (context.Context, *Span) {
+	if t == nil {
+		return ctx, nil
+	}
+
+	span := &Span{
+		Name:       name,
+		Kind:       kind,
+		StartTime:  time.Now(),
+		Attributes: make(map[string]interface{}),
+		tracer:     t,
+	}
+
+	parent := SpanContextFromContext(ctx)
+	if parent.IsValid() {
+		span.Context.TraceID = parent.TraceID
+		span.Context.Sampled = parent.Sampled
+		span.ParentID = parent.SpanID
+	} else {
+		span.Context.TraceID = newTraceID()
+		span.Context.Sampled = t.shouldSample(span.Context.TraceID)
+	}
+	span.Context.SpanID = newSpanID()
+
+	return context.WithValue(ctx, &contextKey, span), span
+}
+
+var defaultTracer *Tracer
+
+// Init enables tracing with spans of sampleRatio of traces exported by the processor
+func Init(processor *BatchProcessor, sampleRatio float64) {
+	defaultTracer = NewTracer(processor).SetSampleRatio(sampleRatio)
+}
+
+// IsEnabled returns true if tracing was initialized
+func IsEnabled() bool {
+	return defaultTracer != nil
+}
+
+// Start starts span of internal operation with the default tracer
+func Start(ctx context.Context, name string) (context.Context, *Span) {
+	return StartWithKind(ctx, name, SpanKindInternal)
+}
+
+// StartWithKind starts span with the default tracer
+func StartWithKind(ctx context.Context, name string, kind SpanKind) (context.Context, *Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return defaultTracer.Start(ctx, name, kind)
+}
+
+// FromContext returns span bound to the context or nil
+func FromContext(ctx context.Context) *Span {
+	if ctx == nil {
+		return nil
+	}
+
+	span, _ := ctx.Value(&contextKey).(*Span)
+	return span
+}
+
+// SpanContextFromContext returns context of the span bound to ctx or of the
+// remote parent
+func SpanContextFromContext(ctx context.Context) SpanContext {
+	if span := FromContext(ctx); span != nil {
+		return span.Context
+	}
+
+	remote, _ := ctx.Value(&remoteKey).(SpanContext)
+	return remote
+}
+
+func newTraceID() (id TraceID) {
+	rand.Read(id[:])
+	return
+}
+
+func newSpanID() (id SpanID) {
+	rand.Read(id[:])
+	return
+}
@@ -0,0 +1,154 @@
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"golang.org/x/net/context"
+)
+
+type exporterMock struct {
+	spans []*Span
+}
+
+func (e *exporterMock) Export(spans []*Span) error {
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func TestTracing(t *testing.T) {
+	Convey("Propagation", t, func() {
+		value := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+		sc, ok := ParseTraceParent(value)
+		So(ok, ShouldBeTrue)
+		So(sc.TraceID.String(), ShouldEqual, "4bf92f3577b34da6a3ce929d0e0e4736")
+		So(sc.SpanID.String(), ShouldEqual, "00f067aa0ba902b7")
+		So(sc.Sampled, ShouldBeTrue)
+		So(FormatTraceParent(sc), ShouldEqual, value)
+
+		Convey("Invalid values are ignored", func() {
+			for _, invalid := range []string{
+				"",
+				"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+				"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+				"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+				"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			} {
+				_, ok := ParseTraceParent(invalid)
+				So(ok, ShouldBeFalse)
+			}
+		})
+
+		Convey("Incoming trace is continued", func() {
+			header := http.Header{}
+			header.Set(TraceParentHeader, value)
+			tracer := NewTracer(NewBatchProcessor(&exporterMock{}, 0))
+			ctx, span := tracer.Start(Extract(context.Background(), header), "request", SpanKindServer)
+			So(span.Context.TraceID, ShouldResemble, sc.TraceID)
+			So(span.ParentID, ShouldResemble, sc.SpanID)
+
+			outgoing := http.Header{}
+			Inject(ctx, outgoing)
+			So(outgoing.Get(TraceParentHeader), ShouldEqual, FormatTraceParent(span.Context))
+		})
+	})
+
+	Convey("Spans", t, func() {
+		exporter := &exporterMock{}
+		processor := NewBatchProcessor(exporter, 0)
+		tracer := NewTracer(processor)
+
+		ctx, parent := tracer.Start(context.Background(), "parent", SpanKindServer)
+		_, child := tracer.Start(ctx, "child", SpanKindInternal)
+		child.SetAttribute("db.statement", "SELECT 1")
+		child.SetError(errors.New("failed"))
+		child.End()
+		child.End()
+		parent.End()
+
+		So(child.Context.TraceID, ShouldResemble, parent.Context.TraceID)
+		So(child.ParentID, ShouldResemble, parent.Context.SpanID)
+		So(parent.ParentID.IsValid(), ShouldBeFalse)
+		So(len(processor.queue), ShouldEqual, 2)
+
+		Convey("Sampling", func() {
+			tracer.SetSampleRatio(0)
+			ctx, root := tracer.Start(context.Background(), "root", SpanKindServer)
+			_, child := tracer.Start(ctx, "child", SpanKindInternal)
+			root.End()
+			child.End()
+			So(root.Context.Sampled, ShouldBeFalse)
+			So(child.Context.Sampled, ShouldBeFalse)
+			So(len(processor.queue), ShouldEqual, 2)
+
+			// incoming decision is followed
+			header := http.Header{}
+			header.Set(TraceParentHeader, FormatTraceParent(parent.Context))
+			_, remote := tracer.Start(Extract(context.Background(), header), "remote", SpanKindServer)
+			So(remote.Context.Sampled, ShouldBeTrue)
+
+			tracer.SetSampleRatio(0.5)
+			var id TraceID
+			id[8] = 0x10
+			So(tracer.shouldSample(id), ShouldBeTrue)
+			id[8] = 0xf0
+			So(tracer.shouldSample(id), ShouldBeFalse)
+
+			tracer.SetSampleRatio(1)
+			So(tracer.shouldSample(id), ShouldBeTrue)
+		})
+
+		Convey("Nil span is noop", func() {
+			var span *Span
+			span.SetAttribute("key", "value")
+			span.SetError(errors.New("failed"))
+			span.End()
+			So(span.TraceID(), ShouldEqual, "")
+		})
+
+		Convey("Writer exporter", func() {
+			var buf bytes.Buffer
+			err := NewWriterExporter(&buf).Export([]*Span{child})
+			So(err, ShouldBeNil)
+
+			var written map[string]interface{}
+			So(json.Unmarshal(buf.Bytes(), &written), ShouldBeNil)
+			So(written["trace_id"], ShouldEqual, parent.TraceID())
+			So(written["parent_span_id"], ShouldEqual, parent.Context.SpanID.String())
+			So(written["error"], ShouldEqual, "failed")
+		})
+
+		Convey("OTLP exporter", func() {
+			var received map[string]interface{}
+			var path string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path = r.URL.Path
+				body, _ := ioutil.ReadAll(r.Body)
+				json.Unmarshal(body, &received)
+			}))
+			defer server.Close()
+
+			err := NewOTLPExporter(server.URL, "horizon", http.DefaultClient).Export([]*Span{child})
+			So(err, ShouldBeNil)
+			So(path, ShouldEqual, "/v1/traces")
+			resourceSpans := received["resourceSpans"].([]interface{})[0].(map[string]interface{})
+			scopeSpans := resourceSpans["scopeSpans"].([]interface{})[0].(map[string]interface{})
+			span := scopeSpans["spans"].([]interface{})[0].(map[string]interface{})
+			So(span["traceId"], ShouldEqual, parent.TraceID())
+			So(span["name"], ShouldEqual, "child")
+			So(span["status"].(map[string]interface{})["code"], ShouldEqual, otlpStatusError)
+			So(span["attributes"], ShouldResemble, []interface{}{
+				map[string]interface{}{
+					"key":   "db.statement",
+					"value": map[string]interface{}{"stringValue": "SELECT 1"},
+				},
+			})
+		})
+	})
+}
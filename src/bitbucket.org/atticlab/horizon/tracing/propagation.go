@@ -0,0 +1,83 @@
+package tracing
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"golang.org/x/net/context"
+)
+
+// TraceParentHeader is a W3C trace context header
+const TraceParentHeader = "traceparent"
+
+const sampledFlag = 0x01
+
+var remoteKey = 0
+
+// ParseTraceParent parses value of the traceparent header. Returns false if
+// value is invalid.
+func ParseTraceParent(value string) (SpanContext, bool) {
+	var sc SpanContext
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" {
+		return sc, false
+	}
+
+	// version 00 has exactly four parts, future versions may add more
+	if parts[0] == "00" && len(parts) != 4 {
+		return sc, false
+	}
+
+	if !decodeHex(sc.TraceID[:], parts[1]) || !decodeHex(sc.SpanID[:], parts[2]) {
+		return sc, false
+	}
+
+	var flags [1]byte
+	if !decodeHex(flags[:], parts[3]) {
+		return sc, false
+	}
+	sc.Sampled = flags[0]&sampledFlag != 0
+
+	return sc, sc.IsValid()
+}
+
+// FormatTraceParent formats span context as value of the traceparent header
+func FormatTraceParent(sc SpanContext) string {
+	var flags byte
+	if sc.Sampled {
+		flags = sampledFlag
+	}
+	return fmt.Sprintf("00-%s-%s-%02x", sc.TraceID, sc.SpanID, flags)
+}
+
+// Extract returns context holding remote parent span from the headers of the
+// incoming request. Context is returned unchanged if header is missing or invalid.
+func Extract(ctx context.Context, header http.Header) context.Context {
+	sc, ok := ParseTraceParent(header.Get(TraceParentHeader))
+	if !ok {
+		return ctx
+	}
+
+	return context.WithValue(ctx, &remoteKey, sc)
+}
+
+// Inject sets headers of the outgoing request, so remote service continues the trace
+func Inject(ctx context.Context, header http.Header) {
+	sc := SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return
+	}
+
+	header.Set(TraceParentHeader, FormatTraceParent(sc))
+}
+
+func decodeHex(dst []byte, value string) bool {
+	if len(value) != 2*len(dst) || strings.ToLower(value) != value {
+		return false
+	}
+
+	_, err := hex.Decode(dst, []byte(value))
+	return err == nil
+}
@@ -0,0 +1,153 @@
+package txsub
+
+import (
+	"time"
+
+	"bitbucket.org/atticlab/horizon/log"
+	"bitbucket.org/atticlab/horizon/txsub/sequence"
+)
+
+// SharedStore keeps open submissions and sequence reservations shared by
+// horizon replicas
+type SharedStore interface {
+	SubmissionStore
+	sequence.Reservations
+}
+
+// NewDegradingStore returns a store, which passes calls to primary and, while
+// isUnavailable reports its errors, to fallback. Without fallback, state is not
+// shared while primary is unavailable: sequences are reserved and open
+// submissions are tracked by the replica only. Open submissions are removed,
+// listed and cleaned in both stores, so submissions added during the outage
+// are finished once primary is restored.
+func NewDegradingStore(primary, fallback SharedStore, isUnavailable func(error) bool) SharedStore {
+	return &degradingStore{
+		primary:       primary,
+		fallback:      fallback,
+		isUnavailable: isUnavailable,
+		log:           log.WithField("service", "degrading_txsub_store"),
+	}
+}
+
+type degradingStore struct {
+	primary       SharedStore
+	fallback      SharedStore
+	isUnavailable func(error) bool
+	log           *log.Entry
+}
+
+// degraded returns true if err means primary is unavailable
+func (s *degradingStore) degraded(err error) bool {
+	if err == nil || !s.isUnavailable(err) {
+		return false
+	}
+
+	s.log.WithError(err).Warn("Shared txsub store is unavailable - degrading")
+	return true
+}
+
+func (s *degradingStore) Add(hash string, submittedAt time.Time) error {
+	err := s.primary.Add(hash, submittedAt)
+	if !s.degraded(err) {
+		return err
+	}
+
+	if s.fallback == nil {
+		return nil
+	}
+	return s.fallback.Add(hash, submittedAt)
+}
+
+func (s *degradingStore) Remove(hash string) error {
+	err := s.primary.Remove(hash)
+	if s.degraded(err) {
+		err = nil
+	}
+
+	if s.fallback == nil || err != nil {
+		return err
+	}
+	return s.fallback.Remove(hash)
+}
+
+func (s *degradingStore) Pending() ([]string, error) {
+	result, err := s.primary.Pending()
+	if s.degraded(err) {
+		err = nil
+	}
+
+	if s.fallback == nil || err != nil {
+		return result, err
+	}
+
+	fallback, err := s.fallback.Pending()
+	if err != nil {
+		return nil, err
+	}
+	return append(result, fallback...), nil
+}
+
+func (s *degradingStore) Clean(submittedBefore time.Time) (int, error) {
+	open, err := s.primary.Clean(submittedBefore)
+	if s.degraded(err) {
+		err = nil
+	}
+
+	if s.fallback == nil || err != nil {
+		return open, err
+	}
+
+	fallbackOpen, err := s.fallback.Clean(submittedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return open + fallbackOpen, nil
+}
+
+func (s *degradingStore) Reserve(address string, sequence uint64, hash string, ttl time.Duration) (string, error) {
+	holder, err := s.primary.Reserve(address, sequence, hash, ttl)
+	if !s.degraded(err) {
+		return holder, err
+	}
+
+	if s.fallback == nil {
+		return hash, nil
+	}
+	return s.fallback.Reserve(address, sequence, hash, ttl)
+}
+
+func (s *degradingStore) Release(address string, sequence uint64, hash string) error {
+	err := s.primary.Release(address, sequence, hash)
+	if !s.degraded(err) {
+		return err
+	}
+
+	if s.fallback == nil {
+		return nil
+	}
+	return s.fallback.Release(address, sequence, hash)
+}
+
+func (s *degradingStore) Accept(address string, sequence uint64, hash string, ttl time.Duration) error {
+	err := s.primary.Accept(address, sequence, hash, ttl)
+	if !s.degraded(err) {
+		return err
+	}
+
+	if s.fallback == nil {
+		return nil
+	}
+	return s.fallback.Accept(address, sequence, hash, ttl)
+}
+
+func (s *degradingStore) Accepted(addresses []string) (map[string]uint64, error) {
+	result, err := s.primary.Accepted(addresses)
+	if !s.degraded(err) {
+		return result, err
+	}
+
+	if s.fallback == nil {
+		return map[string]uint64{}, nil
+	}
+	return s.fallback.Accepted(addresses)
+}
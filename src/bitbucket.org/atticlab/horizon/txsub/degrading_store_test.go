@@ -0,0 +1,110 @@
+package txsub
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var errStoreUnavailable = errors.New("store is unavailable")
+
+// unavailableStore fails all calls
+type unavailableStore struct{}
+
+func (s unavailableStore) Add(hash string, submittedAt time.Time) error {
+	return errStoreUnavailable
+}
+func (s unavailableStore) Remove(hash string) error {
+	return errStoreUnavailable
+}
+func (s unavailableStore) Pending() ([]string, error) {
+	return nil, errStoreUnavailable
+}
+func (s unavailableStore) Clean(submittedBefore time.Time) (int, error) {
+	return 0, errStoreUnavailable
+}
+func (s unavailableStore) Reserve(address string, sequence uint64, hash string, ttl time.Duration) (string, error) {
+	return "", errStoreUnavailable
+}
+func (s unavailableStore) Release(address string, sequence uint64, hash string) error {
+	return errStoreUnavailable
+}
+func (s unavailableStore) Accept(address string, sequence uint64, hash string, ttl time.Duration) error {
+	return errStoreUnavailable
+}
+func (s unavailableStore) Accepted(addresses []string) (map[string]uint64, error) {
+	return nil, errStoreUnavailable
+}
+
+// reservingMemoryStore is a SharedStore granting all reservations
+type reservingMemoryStore struct {
+	*memoryStore
+	accepted map[string]uint64
+}
+
+func (s *reservingMemoryStore) Reserve(address string, sequence uint64, hash string, ttl time.Duration) (string, error) {
+	return hash, nil
+}
+func (s *reservingMemoryStore) Release(address string, sequence uint64, hash string) error {
+	return nil
+}
+func (s *reservingMemoryStore) Accept(address string, sequence uint64, hash string, ttl time.Duration) error {
+	s.accepted[address] = sequence
+	return nil
+}
+func (s *reservingMemoryStore) Accepted(addresses []string) (map[string]uint64, error) {
+	return s.accepted, nil
+}
+
+func TestDegradingStore(t *testing.T) {
+	isUnavailable := func(err error) bool {
+		return err == errStoreUnavailable
+	}
+
+	Convey("degradingStore", t, func() {
+		Convey("Without fallback state is local", func() {
+			store := NewDegradingStore(unavailableStore{}, nil, isUnavailable)
+			So(store.Add("a", time.Now()), ShouldBeNil)
+			holder, err := store.Reserve("1", 2, "a", time.Minute)
+			So(err, ShouldBeNil)
+			So(holder, ShouldEqual, "a")
+			So(store.Accept("1", 2, "a", time.Minute), ShouldBeNil)
+			accepted, err := store.Accepted([]string{"1"})
+			So(err, ShouldBeNil)
+			So(accepted, ShouldBeEmpty)
+			pending, err := store.Pending()
+			So(err, ShouldBeNil)
+			So(pending, ShouldBeEmpty)
+		})
+
+		Convey("Fallback keeps state during outage", func() {
+			fallback := &reservingMemoryStore{
+				memoryStore: &memoryStore{submissions: map[string]time.Time{}},
+				accepted:    map[string]uint64{},
+			}
+			store := NewDegradingStore(unavailableStore{}, fallback, isUnavailable)
+			So(store.Add("a", time.Now()), ShouldBeNil)
+			pending, err := store.Pending()
+			So(err, ShouldBeNil)
+			So(pending, ShouldResemble, []string{"a"})
+			So(store.Accept("1", 2, "a", time.Minute), ShouldBeNil)
+			accepted, err := store.Accepted([]string{"1"})
+			So(err, ShouldBeNil)
+			So(accepted["1"], ShouldEqual, 2)
+			So(store.Remove("a"), ShouldBeNil)
+			open, err := store.Clean(time.Now())
+			So(err, ShouldBeNil)
+			So(open, ShouldEqual, 0)
+		})
+
+		Convey("Other errors are returned", func() {
+			failing := errors.New("failed")
+			store := NewDegradingStore(unavailableStore{}, nil, func(err error) bool {
+				return err == failing
+			})
+			So(store.Add("a", time.Now()), ShouldEqual, errStoreUnavailable)
+		})
+	})
+}
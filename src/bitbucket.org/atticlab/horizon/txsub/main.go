@@ -54,6 +54,16 @@ type OpenSubmissionList interface {
 	Pending(context.Context) []string
 }
 
+// LocalOpenSubmissionList is implemented by lists shared by horizon replicas,
+// so submissions waited by clients of this instance can be told from others.
+type LocalOpenSubmissionList interface {
+	OpenSubmissionList
+
+	// LocalPending returns a list of transaction hashes that have at least one
+	// listener registered in this process.
+	LocalPending(context.Context) []string
+}
+
 // Submitter represents the low-level "submit a transaction to stellar-core"
 // provider.
 type Submitter interface {
@@ -3,13 +3,14 @@ package txsub
 import (
 	"bitbucket.org/atticlab/horizon/txsub/transactions"
 	"github.com/stretchr/testify/mock"
+	"golang.org/x/net/context"
 )
 
 type TransactionValidatorMock struct {
 	mock.Mock
 }
 
-func (v *TransactionValidatorMock) CheckTransaction(envelopeInfo *transactions.EnvelopeInfo) error {
+func (v *TransactionValidatorMock) CheckTransaction(ctx context.Context, envelopeInfo *transactions.EnvelopeInfo) error {
 	a := v.Called(envelopeInfo)
 	return a.Error(0)
 }
@@ -0,0 +1,101 @@
+package txsub
+
+import (
+	"time"
+
+	"bitbucket.org/atticlab/horizon/db2"
+	"bitbucket.org/atticlab/horizon/db2/history"
+)
+
+// PostgresStore keeps open submissions and sequence reservations in history
+// db, so they are shared by horizon replicas connected to the same db. It
+// implements SubmissionStore and sequence.Reservations.
+type PostgresStore struct {
+	repo *db2.Repo
+}
+
+// NewPostgresStore creates new store
+func NewPostgresStore(repo *db2.Repo) *PostgresStore {
+	return &PostgresStore{
+		repo: repo,
+	}
+}
+
+func (s *PostgresStore) q() *history.Q {
+	return &history.Q{Repo: s.repo.Clone()}
+}
+
+func (s *PostgresStore) Add(hash string, submittedAt time.Time) error {
+	return s.q().TxSubPendingInsert(hash, submittedAt)
+}
+
+func (s *PostgresStore) Remove(hash string) error {
+	return s.q().TxSubPendingDelete(hash)
+}
+
+func (s *PostgresStore) Pending() ([]string, error) {
+	return s.q().TxSubPendingHashes()
+}
+
+func (s *PostgresStore) Clean(submittedBefore time.Time) (int, error) {
+	return s.q().TxSubPendingDeleteBefore(submittedBefore)
+}
+
+// Reserve claims sequence of the address in transaction holding lock of the
+// reservation, so concurrent claims are serialized.
+func (s *PostgresStore) Reserve(address string, sequence uint64, hash string, ttl time.Duration) (string, error) {
+	q := s.q()
+	err := q.Begin()
+	if err != nil {
+		return "", err
+	}
+	defer q.Rollback()
+
+	err = q.LockSequence(address, int64(sequence))
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	reservation, err := q.SequenceReservationByKey(address, int64(sequence), now)
+	if err != nil {
+		return "", err
+	}
+
+	if reservation != nil {
+		return reservation.Hash, nil
+	}
+
+	err = q.SequenceReservationInsert(&history.SequenceReservation{
+		Address:   address,
+		Sequence:  int64(sequence),
+		Hash:      hash,
+		ExpiresAt: now.Add(ttl),
+	}, now)
+	if err != nil {
+		return "", err
+	}
+
+	return hash, q.Commit()
+}
+
+func (s *PostgresStore) Release(address string, sequence uint64, hash string) error {
+	return s.q().SequenceReservationDelete(address, int64(sequence), hash)
+}
+
+func (s *PostgresStore) Accept(address string, sequence uint64, hash string, ttl time.Duration) error {
+	return s.q().SequenceReservationAccept(address, int64(sequence), hash, time.Now().Add(ttl))
+}
+
+func (s *PostgresStore) Accepted(addresses []string) (map[string]uint64, error) {
+	accepted, err := s.q().SequencesAccepted(addresses, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]uint64, len(accepted))
+	for address, sequence := range accepted {
+		result[address] = uint64(sequence)
+	}
+	return result, nil
+}
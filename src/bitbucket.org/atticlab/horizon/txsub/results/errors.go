@@ -13,6 +13,9 @@ var (
 	ErrNoResults = errors.New("No result found")
 	ErrCanceled  = errors.New("canceled")
 	ErrTimeout   = errors.New("timeout")
+	// ErrShuttingDown is returned for transactions submitted after shutdown of
+	// horizon started
+	ErrShuttingDown = errors.New("shutting down")
 
 	// ErrBadSequence is a canned error response for transactions whose sequence
 	// number is wrong.
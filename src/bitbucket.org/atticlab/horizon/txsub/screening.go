@@ -0,0 +1,38 @@
+package txsub
+
+import (
+	conf "bitbucket.org/atticlab/horizon/config"
+	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/txsub/transactions/validators"
+)
+
+// NewScreener creates screening validator according to config. Returns nil, if screening is disabled.
+func NewScreener(config conf.ScreeningConfig, historyQ history.QInterface) (validators.ScreeningValidatorInterface, error) {
+	if !config.IsEnabled() {
+		return nil, nil
+	}
+
+	var screeners []validators.ScreeningValidatorInterface
+	if config.ListEnabled || config.ListFile != "" {
+		var fileAddresses map[string]string
+		if config.ListFile != "" {
+			var err error
+			fileAddresses, err = validators.LoadScreeningListFile(config.ListFile)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		var listQ history.QInterface
+		if config.ListEnabled {
+			listQ = historyQ
+		}
+		screeners = append(screeners, validators.NewListScreeningValidator(listQ, fileAddresses))
+	}
+
+	if config.URL != "" {
+		screeners = append(screeners, validators.NewHTTPScreeningValidator(config.URL, config.Timeout, config.FailClosed))
+	}
+
+	return validators.NewScreeningValidatorChain(screeners...), nil
+}
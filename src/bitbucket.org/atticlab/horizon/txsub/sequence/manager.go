@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
+
+	"bitbucket.org/atticlab/horizon/log"
 )
 
 // Manager provides a system for tracking the transaction submission queue for
@@ -11,20 +14,38 @@ import (
 // registered using the Push() method, and as the system is updated with
 // account sequence information (through the Update() method) requests are
 // notified that they can safely submit to stellar-core.
+//
+// Queues are kept in memory of the process. If Reservations are set, sequence
+// numbers are also claimed in the store shared by all horizon replicas (see
+// Reserve()) and sequences accepted by stellar-core through any replica unblock
+// queued submissions (see Accept()).
 type Manager struct {
 	mutex   sync.Mutex
 	MaxSize int
 	queues  map[string]*Queue
+
+	Reservations Reservations
+	// time sequence number is held by the submitted transaction
+	ReservationTimeout time.Duration
 }
 
 // NewManager returns a new manager
 func NewManager() *Manager {
 	return &Manager{
-		MaxSize: 1024, //TODO: make MaxSize configurable
-		queues:  map[string]*Queue{},
+		MaxSize:            1024, //TODO: make MaxSize configurable
+		queues:             map[string]*Queue{},
+		ReservationTimeout: 1 * time.Minute,
 	}
 }
 
+// NewSharedManager returns a new manager, which claims sequence numbers in
+// reservations shared by horizon replicas
+func NewSharedManager(reservations Reservations) *Manager {
+	result := NewManager()
+	result.Reservations = reservations
+	return result
+}
+
 func (m *Manager) String() string {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
@@ -79,7 +100,42 @@ func (m *Manager) Push(address string, sequence uint64) <-chan error {
 
 // Update notifies the manager of newly loaded account sequence information.  The manager uses this information
 // to notify requests to submit that they should proceed.  See Queue#Update for the actual meat of the logic.
+// Sequences accepted through other horizon replicas, but not yet closed in a ledger, are taken into account.
 func (m *Manager) Update(updates map[string]uint64) {
+	m.update(m.withAccepted(updates))
+}
+
+// withAccepted returns updates raised to the sequences accepted through any
+// horizon replica. If shared reservations are unavailable, updates are
+// returned as is.
+func (m *Manager) withAccepted(updates map[string]uint64) map[string]uint64 {
+	if m.Reservations == nil || len(updates) == 0 {
+		return updates
+	}
+
+	addresses := make([]string, 0, len(updates))
+	for address := range updates {
+		addresses = append(addresses, address)
+	}
+
+	accepted, err := m.Reservations.Accepted(addresses)
+	if err != nil {
+		log.WithField("service", "sequence_manager").WithError(err).Error("Failed to get accepted sequences")
+		return updates
+	}
+
+	result := make(map[string]uint64, len(updates))
+	for address, seq := range updates {
+		if acceptedSeq, ok := accepted[address]; ok && acceptedSeq > seq {
+			seq = acceptedSeq
+		}
+		result[address] = seq
+	}
+	return result
+}
+
+// update assumes updates already include accepted sequences
+func (m *Manager) update(updates map[string]uint64) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
@@ -96,6 +152,49 @@ func (m *Manager) Update(updates map[string]uint64) {
 	}
 }
 
+// Reserve claims sequence of the address for transaction with hash. Must be
+// called after submission is unblocked by the queue and before it is sent to
+// stellar-core. Returns ErrBadSequence, if sequence is already claimed by
+// another transaction. Resubmission of the same transaction is allowed.
+func (m *Manager) Reserve(address string, sequence uint64, hash string) error {
+	if m.Reservations == nil {
+		return nil
+	}
+
+	holder, err := m.Reservations.Reserve(address, sequence, hash, m.ReservationTimeout)
+	if err != nil {
+		return err
+	}
+
+	if holder != hash {
+		return ErrBadSequence
+	}
+
+	return nil
+}
+
+// Release frees sequence of the address claimed by transaction with hash, so
+// another transaction can be submitted with it.
+func (m *Manager) Release(address string, sequence uint64, hash string) error {
+	if m.Reservations == nil {
+		return nil
+	}
+
+	return m.Reservations.Release(address, sequence, hash)
+}
+
+// Accept notifies the manager that transaction with hash was accepted by
+// stellar-core, allowing the next submission of the address to proceed on this
+// and, if Reservations are set, on other horizon replicas.
+func (m *Manager) Accept(address string, sequence uint64, hash string) error {
+	m.update(map[string]uint64{address: sequence})
+	if m.Reservations == nil {
+		return nil
+	}
+
+	return m.Reservations.Accept(address, sequence, hash, m.ReservationTimeout)
+}
+
 // size returns the count of submissions buffered within this manager.  This
 // internal version assumes you have locked the manager previously.
 func (m *Manager) size() int {
@@ -1,8 +1,10 @@
 package sequence
 
 import (
+	"fmt"
 	. "github.com/smartystreets/goconvey/convey"
 	"testing"
+	"time"
 )
 
 func TestManager(t *testing.T) {
@@ -51,3 +53,95 @@ func TestManager(t *testing.T) {
 		})
 	})
 }
+
+// memoryReservations are reservations shared by managers in tests
+type memoryReservations struct {
+	held     map[string]string
+	accepted map[string]uint64
+}
+
+func newMemoryReservations() *memoryReservations {
+	return &memoryReservations{
+		held:     map[string]string{},
+		accepted: map[string]uint64{},
+	}
+}
+
+func (r *memoryReservations) Reserve(address string, sequence uint64, hash string, ttl time.Duration) (string, error) {
+	key := fmt.Sprintf("%s:%d", address, sequence)
+	if holder, ok := r.held[key]; ok {
+		return holder, nil
+	}
+	r.held[key] = hash
+	return hash, nil
+}
+
+func (r *memoryReservations) Release(address string, sequence uint64, hash string) error {
+	key := fmt.Sprintf("%s:%d", address, sequence)
+	if r.held[key] == hash {
+		delete(r.held, key)
+	}
+	return nil
+}
+
+func (r *memoryReservations) Accept(address string, sequence uint64, hash string, ttl time.Duration) error {
+	if r.accepted[address] < sequence {
+		r.accepted[address] = sequence
+	}
+	return nil
+}
+
+func (r *memoryReservations) Accepted(addresses []string) (map[string]uint64, error) {
+	result := map[string]uint64{}
+	for _, address := range addresses {
+		if seq, ok := r.accepted[address]; ok {
+			result[address] = seq
+		}
+	}
+	return result, nil
+}
+
+func TestSharedManager(t *testing.T) {
+	Convey("Shared manager", t, func() {
+		reservations := newMemoryReservations()
+		// managers of two horizon replicas
+		first := NewSharedManager(reservations)
+		second := NewSharedManager(reservations)
+
+		Convey("Reserve rejects conflicting transaction", func() {
+			So(first.Reserve("1", 2, "a"), ShouldBeNil)
+			So(second.Reserve("1", 2, "b"), ShouldEqual, ErrBadSequence)
+			So(second.Reserve("1", 3, "b"), ShouldBeNil)
+		})
+
+		Convey("Reserve allows resubmission of the same transaction", func() {
+			So(first.Reserve("1", 2, "a"), ShouldBeNil)
+			So(second.Reserve("1", 2, "a"), ShouldBeNil)
+		})
+
+		Convey("Release frees sequence", func() {
+			So(first.Reserve("1", 2, "a"), ShouldBeNil)
+			So(second.Release("1", 2, "b"), ShouldBeNil)
+			So(second.Reserve("1", 2, "b"), ShouldEqual, ErrBadSequence)
+			So(first.Release("1", 2, "a"), ShouldBeNil)
+			So(second.Reserve("1", 2, "b"), ShouldBeNil)
+		})
+
+		Convey("Sequence accepted by another replica unblocks queue", func() {
+			So(first.Reserve("1", 2, "a"), ShouldBeNil)
+			So(first.Accept("1", 2, "a"), ShouldBeNil)
+
+			results := second.Push("1", 3)
+			// account's sequence is not changed until the ledger is closed
+			second.Update(map[string]uint64{"1": 1})
+			So(<-results, ShouldBeNil)
+			So(second.Size(), ShouldEqual, 0)
+		})
+
+		Convey("Manager without reservations accepts any transaction", func() {
+			mgr := NewManager()
+			So(mgr.Reserve("1", 2, "a"), ShouldBeNil)
+			So(mgr.Reserve("1", 2, "b"), ShouldBeNil)
+		})
+	})
+}
@@ -0,0 +1,27 @@
+package sequence
+
+import (
+	"time"
+)
+
+// Reservations is a store shared by horizon replicas, which keeps hash of the
+// transaction accepted for submission at sequence number of the account. It
+// prevents replicas from submitting conflicting transactions with the same
+// sequence number.
+type Reservations interface {
+	// Reserve claims sequence of the address for transaction with hash for ttl,
+	// unless it's already claimed. Returns hash of the transaction holding
+	// reservation.
+	Reserve(address string, sequence uint64, hash string, ttl time.Duration) (string, error)
+
+	// Release removes reservation, if it's held by transaction with hash
+	Release(address string, sequence uint64, hash string) error
+
+	// Accept records that transaction holding reservation was accepted by
+	// stellar-core, so sequence is treated as used for ttl
+	Accept(address string, sequence uint64, hash string, ttl time.Duration) error
+
+	// Accepted returns the highest accepted sequences of the addresses.
+	// Addresses without accepted sequences are omitted.
+	Accepted(addresses []string) (map[string]uint64, error)
+}
@@ -0,0 +1,110 @@
+package txsub
+
+import (
+	"time"
+
+	"bitbucket.org/atticlab/horizon/log"
+	"golang.org/x/net/context"
+)
+
+// SubmissionStore keeps hashes of open submissions in storage shared by
+// horizon replicas.
+type SubmissionStore interface {
+	// Add stores hash of the submitted transaction. Submission time of already
+	// stored transaction must not be changed.
+	Add(hash string, submittedAt time.Time) error
+
+	// Remove removes hash of the transaction
+	Remove(hash string) error
+
+	// Pending returns hashes of all stored transactions
+	Pending() ([]string, error)
+
+	// Clean removes transactions submitted before the provided time and
+	// returns number of transactions left
+	Clean(submittedBefore time.Time) (int, error)
+}
+
+// NewSharedSubmissionList returns a list that keeps listeners in memory, while
+// open submissions are shared with other horizon replicas through the store.
+// Any replica finds results of transactions submitted through the others, so
+// shared open submissions are finished and cleaned even if the replica which
+// accepted them is gone.
+func NewSharedSubmissionList(store SubmissionStore) OpenSubmissionList {
+	return &sharedSubmissionList{
+		local: &submissionList{
+			submissions: map[string]*openSubmission{},
+		},
+		store: store,
+		log:   log.WithField("service", "shared_submission_list"),
+	}
+}
+
+type sharedSubmissionList struct {
+	local *submissionList
+	store SubmissionStore
+	log   *log.Entry
+}
+
+func (s *sharedSubmissionList) Add(ctx context.Context, hash string, l Listener) error {
+	err := s.local.Add(ctx, hash, l)
+	if err != nil {
+		return err
+	}
+
+	return s.store.Add(hash, time.Now())
+}
+
+func (s *sharedSubmissionList) Finish(ctx context.Context, r Result) error {
+	err := s.local.Finish(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	return s.store.Remove(r.Hash)
+}
+
+func (s *sharedSubmissionList) Clean(ctx context.Context, maxAge time.Duration) (int, error) {
+	localOpen, err := s.local.Clean(ctx, maxAge)
+	if err != nil {
+		return 0, err
+	}
+
+	open, err := s.store.Clean(time.Now().Add(-maxAge))
+	if err != nil {
+		return localOpen, err
+	}
+
+	return open, nil
+}
+
+// LocalPending implements LocalOpenSubmissionList
+func (s *sharedSubmissionList) LocalPending(ctx context.Context) []string {
+	return s.local.Pending(ctx)
+}
+
+// Pending returns hashes of local and shared open submissions. If store is
+// unavailable, only local submissions are returned.
+func (s *sharedSubmissionList) Pending(ctx context.Context) []string {
+	result := s.local.Pending(ctx)
+
+	shared, err := s.store.Pending()
+	if err != nil {
+		s.log.WithError(err).Error("Failed to get shared open submissions")
+		return result
+	}
+
+	seen := make(map[string]bool, len(result))
+	for _, hash := range result {
+		seen[hash] = true
+	}
+
+	for _, hash := range shared {
+		if !seen[hash] {
+			seen[hash] = true
+			result = append(result, hash)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,113 @@
+package txsub
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"bitbucket.org/atticlab/horizon/test"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// memoryStore is a SubmissionStore shared by lists in tests
+type memoryStore struct {
+	sync.Mutex
+	submissions map[string]time.Time
+}
+
+func (s *memoryStore) Add(hash string, submittedAt time.Time) error {
+	s.Lock()
+	defer s.Unlock()
+	if _, ok := s.submissions[hash]; !ok {
+		s.submissions[hash] = submittedAt
+	}
+	return nil
+}
+
+func (s *memoryStore) Remove(hash string) error {
+	s.Lock()
+	defer s.Unlock()
+	delete(s.submissions, hash)
+	return nil
+}
+
+func (s *memoryStore) Pending() ([]string, error) {
+	s.Lock()
+	defer s.Unlock()
+	result := make([]string, 0, len(s.submissions))
+	for hash := range s.submissions {
+		result = append(result, hash)
+	}
+	return result, nil
+}
+
+func (s *memoryStore) Clean(submittedBefore time.Time) (int, error) {
+	s.Lock()
+	defer s.Unlock()
+	for hash, submittedAt := range s.submissions {
+		if submittedAt.Before(submittedBefore) {
+			delete(s.submissions, hash)
+		}
+	}
+	return len(s.submissions), nil
+}
+
+func TestSharedSubmissionList(t *testing.T) {
+	ctx := test.Context()
+
+	Convey("sharedSubmissionList", t, func() {
+		store := &memoryStore{submissions: map[string]time.Time{}}
+		// lists of two horizon replicas
+		first := NewSharedSubmissionList(store)
+		second := NewSharedSubmissionList(store)
+		hashes := []string{
+			"0000000000000000000000000000000000000000000000000000000000000000",
+			"0000000000000000000000000000000000000000000000000000000000000001",
+		}
+
+		listeners := []chan Result{
+			make(chan Result, 1),
+			make(chan Result, 1),
+		}
+
+		Convey("Pending() returns submissions added through any replica", func() {
+			first.Add(ctx, hashes[0], listeners[0])
+			second.Add(ctx, hashes[1], listeners[1])
+
+			So(first.Pending(ctx), ShouldContain, hashes[1])
+			So(len(first.Pending(ctx)), ShouldEqual, 2)
+			So(second.Pending(ctx), ShouldContain, hashes[0])
+			So(len(second.Pending(ctx)), ShouldEqual, 2)
+		})
+
+		Convey("Finish() notifies local listeners and removes shared submission", func() {
+			first.Add(ctx, hashes[0], listeners[0])
+			second.Add(ctx, hashes[0], listeners[1])
+			r := Result{Hash: hashes[0]}
+
+			err := second.Finish(ctx, r)
+			So(err, ShouldBeNil)
+			So(<-listeners[1], ShouldResemble, r)
+			So(len(listeners[0]), ShouldEqual, 0)
+
+			// first replica still waits for the result
+			So(first.Pending(ctx), ShouldResemble, []string{hashes[0]})
+			So(len(second.Pending(ctx)), ShouldEqual, 0)
+
+			err = first.Finish(ctx, r)
+			So(err, ShouldBeNil)
+			So(<-listeners[0], ShouldResemble, r)
+			So(len(first.Pending(ctx)), ShouldEqual, 0)
+		})
+
+		Convey("Clean() removes old shared submissions", func() {
+			store.Add(hashes[0], time.Now().Add(-time.Hour))
+			second.Add(ctx, hashes[1], listeners[1])
+
+			left, err := first.Clean(ctx, time.Minute)
+			So(err, ShouldBeNil)
+			So(left, ShouldEqual, 1)
+			So(first.Pending(ctx), ShouldResemble, []string{hashes[1]})
+		})
+	})
+}
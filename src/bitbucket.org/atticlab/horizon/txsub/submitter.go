@@ -14,7 +14,9 @@ import (
 	"bitbucket.org/atticlab/horizon/db2/core"
 	"bitbucket.org/atticlab/horizon/db2/history"
 	"bitbucket.org/atticlab/horizon/log"
+	"bitbucket.org/atticlab/horizon/options"
 	"bitbucket.org/atticlab/horizon/render/problem"
+	"bitbucket.org/atticlab/horizon/tracing"
 	"bitbucket.org/atticlab/horizon/txsub/results"
 	"bitbucket.org/atticlab/horizon/txsub/transactions"
 	"bitbucket.org/atticlab/horizon/txsub/transactions/statistics"
@@ -38,8 +40,9 @@ func NewDefaultSubmitter(
 	historyDb *history.Q,
 	config *conf.Config,
 	sharedCache *cache.SharedCache,
+	registry *options.Registry,
 ) Submitter {
-	return createSubmitter(h, url, coreDb, historyDb, config, sharedCache)
+	return createSubmitter(h, url, coreDb, historyDb, config, sharedCache, registry)
 }
 
 // coreSubmissionResponse is the json response from stellar-core's tx endpoint
@@ -64,7 +67,25 @@ type submitter struct {
 	commissionManager  *commissions.CommissionsManager
 }
 
-func createSubmitter(h *http.Client, url string, coreDb *core.Q, historyDb *history.Q, config *conf.Config, sharedCache *cache.SharedCache) *submitter {
+func createSubmitter(h *http.Client, url string, coreDb *core.Q, historyDb *history.Q, config *conf.Config, sharedCache *cache.SharedCache, registry *options.Registry) *submitter {
+	var statsManager statistics.ManagerInterface
+	if config.StatisticsBackend == conf.StatisticsBackendPostgres {
+		statsManager = statistics.NewPostgresManager(historyDb.Repo, accounttype.GetAll(), config).SetOptions(registry)
+	} else {
+		redisManager := statistics.NewManager(historyDb, accounttype.GetAll(), config).SetOptions(registry)
+		if config.Redis.DegradeToPostgres {
+			redisManager.SetDegradeToPostgres(statistics.NewPostgresManager(historyDb.Repo, accounttype.GetAll(), config).SetOptions(registry))
+		}
+		statsManager = redisManager
+	}
+	manager := transactions.NewManager(coreDb, historyDb, statsManager, config, sharedCache)
+	manager.Options = registry
+	screener, err := NewScreener(config.Screening, historyDb)
+	if err != nil {
+		log.WithField("service", "submitter").WithError(err).Panic("Failed to create screener")
+	}
+	manager.Screener = screener
+
 	return &submitter{
 		http:               h,
 		coreURL:            url,
@@ -72,7 +93,7 @@ func createSubmitter(h *http.Client, url string, coreDb *core.Q, historyDb *hist
 		historyQ:           historyDb,
 		config:             config,
 		commissionManager:  commissions.New(sharedCache, historyDb),
-		defaultTxValidator: NewTransactionValidator(transactions.NewManager(coreDb, historyDb, statistics.NewManager(historyDb, accounttype.GetAll(), config), config, sharedCache)),
+		defaultTxValidator: NewTransactionValidator(manager),
 		Log:                log.WithField("service", "submitter"),
 	}
 }
@@ -83,8 +104,18 @@ func (sub *submitter) Submit(ctx context.Context, env *transactions.EnvelopeInfo
 	start := time.Now()
 	defer func() { result.Duration = time.Since(start) }()
 
+	ctx, span := tracing.Start(ctx, "txsub.submit")
+	span.SetAttribute("tx.hash", env.ContentHash)
+	defer func() {
+		span.SetError(result.Err)
+		span.End()
+	}()
+
 	sub.Log.Debug("Setting commission")
+	_, stage := tracing.Start(ctx, "txsub.set_commissions")
 	err := sub.commissionManager.SetCommissions(env.Tx)
+	stage.SetError(err)
+	stage.End()
 	if err != nil {
 		log.WithField("Error", err).Error("Failed to set commissions")
 		result.Err = &problem.ServerError
@@ -93,7 +124,10 @@ func (sub *submitter) Submit(ctx context.Context, env *transactions.EnvelopeInfo
 
 	// check constraints for tx
 	sub.Log.Debug("Checking tx")
-	err = sub.defaultTxValidator.CheckTransaction(env)
+	checkCtx, stage := tracing.Start(ctx, "txsub.check_transaction")
+	err = sub.defaultTxValidator.CheckTransaction(checkCtx, env)
+	stage.SetError(err)
+	stage.End()
 	if err != nil {
 		result.Err = err
 		return
@@ -124,7 +158,11 @@ func (sub *submitter) Submit(ctx context.Context, env *transactions.EnvelopeInfo
 	}
 
 	// perform the submission
+	coreCtx, stage := tracing.StartWithKind(ctx, "stellar-core /tx", tracing.SpanKindClient)
+	tracing.Inject(coreCtx, req.Header)
 	resp, err := sub.http.Do(req)
+	stage.SetError(err)
+	stage.End()
 	if err != nil {
 		result.Err = errors.Wrap(err, 1)
 		return
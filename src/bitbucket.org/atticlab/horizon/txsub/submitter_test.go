@@ -117,7 +117,7 @@ func TestDefaultSubmitter(t *testing.T) {
 func createSubmitterWithTxV(h *http.Client, url string, coreDb *core.Q, historyDb *history.Q, config *config.Config, txValidator TransactionValidatorInterface) *submitter {
 	sub := createSubmitter(h, url, coreDb, historyDb, config, &cache.SharedCache{
 		AccountHistoryCache: cache.NewHistoryAccount(historyDb),
-	})
+	}, nil)
 	sub.defaultTxValidator = txValidator
 	return sub
 }
@@ -6,6 +6,8 @@ import (
 
 	"bitbucket.org/atticlab/horizon/errors"
 	"bitbucket.org/atticlab/horizon/log"
+	"bitbucket.org/atticlab/horizon/options"
+	"bitbucket.org/atticlab/horizon/tracing"
 	"bitbucket.org/atticlab/horizon/txsub/results"
 	"bitbucket.org/atticlab/horizon/txsub/sequence"
 	"bitbucket.org/atticlab/horizon/txsub/transactions"
@@ -13,12 +15,22 @@ import (
 	"golang.org/x/net/context"
 )
 
+// drainInterval is a period of ticks checking open submissions on shutdown
+const drainInterval = 500 * time.Millisecond
+
 // System represents a completely configured transaction submission system.
 // Its methods tie together the various pieces used to reliably submit transactions
 // to a stellar-core instance.
 type System struct {
 	initializer sync.Once
 
+	// guards draining and inProgress
+	lock sync.Mutex
+	// set on shutdown, new submissions are rejected
+	draining bool
+	// number of submissions not yet added to the open list or finished
+	inProgress int
+
 	Pending           OpenSubmissionList
 	Results           ResultProvider
 	Sequences         SequenceProvider
@@ -26,6 +38,8 @@ type System struct {
 	SubmissionQueue   *sequence.Manager
 	NetworkPassphrase string
 	SubmissionTimeout time.Duration
+	// Options overrides SubmissionTimeout, if set
+	Options *options.Registry
 
 	Metrics struct {
 		// SubmissionTimer exposes timing metrics about the rate and latency of
@@ -57,6 +71,12 @@ func (sys *System) Submit(ctx context.Context, env string) (result <-chan Result
 	response := make(chan Result, 1)
 	result = response
 
+	if !sys.begin() {
+		sys.finish(response, Result{Err: results.ErrShuttingDown, EnvelopeXDR: env})
+		return
+	}
+	defer sys.end()
+
 	// calculate hash of transaction
 	info, err := extractEnvelopeInfo(ctx, env, sys.NetworkPassphrase)
 	if err != nil {
@@ -93,8 +113,13 @@ func (sys *System) Submit(ctx context.Context, env string) (result <-chan Result
 	// which will cause the channel returned by Push() to emit if possible.
 	sys.SubmissionQueue.Update(curSeq)
 
+	_, queueSpan := tracing.Start(ctx, "txsub.sequence_queue")
+	queueSpan.SetAttribute("tx.source", info.SourceAddress)
+	queueSpan.SetAttribute("tx.sequence", int64(info.Sequence))
 	select {
 	case err := <-seq:
+		queueSpan.SetError(err)
+		queueSpan.End()
 		if err == sequence.ErrBadSequence {
 			// convert the internal only ErrBadSequence into the FailedTransactionError
 			err = results.ErrBadSequence
@@ -105,14 +130,37 @@ func (sys *System) Submit(ctx context.Context, env string) (result <-chan Result
 			return
 		}
 
+		// claim sequence number across horizon replicas, so conflicting
+		// transaction accepted by another replica is not submitted
+		err = sys.SubmissionQueue.Reserve(info.SourceAddress, info.Sequence, info.ContentHash)
+		if err == sequence.ErrBadSequence {
+			err = results.ErrBadSequence
+		}
+
+		if err != nil {
+			sys.finish(response, Result{Err: err, EnvelopeXDR: env})
+			return
+		}
+
 		sr := sys.submitOnce(ctx, &info)
 
+		// if submission failed, sequence number can be used by another transaction
+		if sr.Err != nil {
+			err = sys.SubmissionQueue.Release(info.SourceAddress, info.Sequence, info.ContentHash)
+			if err != nil {
+				log.Ctx(ctx).WithStack(err).Error(err)
+			}
+		}
+
 		// if submission succeeded
 		if sr.Err == nil {
 			// add transactions to open list
 			sys.Pending.Add(ctx, info.ContentHash, response)
 			// update the submission queue, allowing the next submission to proceed
-			sys.SubmissionQueue.Update(map[string]uint64{info.SourceAddress: info.Sequence})
+			err = sys.SubmissionQueue.Accept(info.SourceAddress, info.Sequence, info.ContentHash)
+			if err != nil {
+				log.Ctx(ctx).WithStack(err).Error(err)
+			}
 			return
 		}
 
@@ -140,6 +188,8 @@ func (sys *System) Submit(ctx context.Context, env string) (result <-chan Result
 		}
 
 	case <-ctx.Done():
+		queueSpan.SetError(results.ErrCanceled)
+		queueSpan.End()
 		sys.finish(response, Result{Err: results.ErrCanceled, EnvelopeXDR: env})
 	}
 
@@ -212,7 +262,7 @@ func (sys *System) Tick(ctx context.Context) {
 		}
 	}
 
-	stillOpen, err := sys.Pending.Clean(ctx, sys.SubmissionTimeout)
+	stillOpen, err := sys.Pending.Clean(ctx, sys.Options.Duration(options.SubmissionTimeout, sys.SubmissionTimeout))
 	if err != nil {
 		logger.WithStack(err).Error(err)
 	}
@@ -221,6 +271,69 @@ func (sys *System) Tick(ctx context.Context) {
 	sys.Metrics.BufferedSubmissionsGauge.Update(int64(sys.SubmissionQueue.Size()))
 }
 
+// Drain rejects new submissions and waits until submissions in progress get
+// results or are added to the open list, and all open submissions of this
+// process are finished. Returns false if some of them are still waiting after
+// timeout.
+func (sys *System) Drain(ctx context.Context, timeout time.Duration) bool {
+	sys.lock.Lock()
+	sys.draining = true
+	sys.lock.Unlock()
+
+	deadline := time.After(timeout)
+	ticker := time.NewTicker(drainInterval)
+	defer ticker.Stop()
+
+	for {
+		inProgress, open := sys.inProgressCount(), len(sys.localPending(ctx))
+		if inProgress == 0 && open == 0 {
+			return true
+		}
+
+		select {
+		case <-ticker.C:
+			sys.Tick(ctx)
+		case <-deadline:
+			log.Ctx(ctx).
+				WithField("in_progress", inProgress).
+				WithField("open", open).
+				Warn("txsub did not drain in time")
+			return false
+		}
+	}
+}
+
+// begin registers submission in progress. Returns false if system is draining
+func (sys *System) begin() bool {
+	sys.lock.Lock()
+	defer sys.lock.Unlock()
+	if sys.draining {
+		return false
+	}
+	sys.inProgress++
+	return true
+}
+
+func (sys *System) end() {
+	sys.lock.Lock()
+	defer sys.lock.Unlock()
+	sys.inProgress--
+}
+
+func (sys *System) inProgressCount() int {
+	sys.lock.Lock()
+	defer sys.lock.Unlock()
+	return sys.inProgress
+}
+
+// localPending returns open submissions waited by clients of this process
+func (sys *System) localPending(ctx context.Context) []string {
+	if list, ok := sys.Pending.(LocalOpenSubmissionList); ok {
+		return list.LocalPending(ctx)
+	}
+	return sys.Pending.Pending(ctx)
+}
+
 // Init initializes `sys`
 func (sys *System) Init() {
 	sys.initializer.Do(func() {
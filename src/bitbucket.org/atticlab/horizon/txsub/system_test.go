@@ -139,6 +139,32 @@ func TestTxsub(t *testing.T) {
 			})
 		})
 
+		Convey("Drain", func() {
+			Convey("waits for open submissions to finish", func() {
+				l := make(chan Result, 1)
+				system.Pending.Add(ctx, successTx.Hash, l)
+				results.Results = []Result{successTx}
+
+				So(system.Drain(ctx, time.Second), ShouldBeTrue)
+				So(len(l), ShouldEqual, 1)
+			})
+
+			Convey("gives up after timeout", func() {
+				l := make(chan Result, 1)
+				system.Pending.Add(ctx, successTx.Hash, l)
+
+				So(system.Drain(ctx, 10*time.Millisecond), ShouldBeFalse)
+				So(len(l), ShouldEqual, 0)
+			})
+
+			Convey("rejects new submissions", func() {
+				So(system.Drain(ctx, time.Second), ShouldBeTrue)
+
+				r := <-system.Submit(ctx, successTx.EnvelopeXDR)
+				So(r.Err, ShouldEqual, subResults.ErrShuttingDown)
+			})
+		})
+
 	})
 }
 
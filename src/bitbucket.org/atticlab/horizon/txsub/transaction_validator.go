@@ -4,10 +4,11 @@ import (
 	"bitbucket.org/atticlab/horizon/log"
 	"bitbucket.org/atticlab/horizon/render/problem"
 	"bitbucket.org/atticlab/horizon/txsub/transactions"
+	"golang.org/x/net/context"
 )
 
 type TransactionValidatorInterface interface {
-	CheckTransaction(envelopeInfo *transactions.EnvelopeInfo) error
+	CheckTransaction(ctx context.Context, envelopeInfo *transactions.EnvelopeInfo) error
 }
 
 type TransactionValidator struct {
@@ -22,10 +23,10 @@ func NewTransactionValidator(manager *transactions.Manager) *TransactionValidato
 	}
 }
 
-// Validates transaction and operations
-func (v *TransactionValidator) CheckTransaction(envelopeInfo *transactions.EnvelopeInfo) error {
+// Validates transaction and operations. Storage calls are made within ctx.
+func (v *TransactionValidator) CheckTransaction(ctx context.Context, envelopeInfo *transactions.EnvelopeInfo) error {
 	txFrame := transactions.NewTransactionFrame(envelopeInfo)
-	isValid, err := txFrame.CheckValid(v.manager)
+	isValid, err := txFrame.CheckValid(v.manager.WithContext(ctx))
 	if err != nil {
 		v.log.WithStack(err).WithError(err).Error("Failed to validate tx")
 		return &problem.ServerError
@@ -3,9 +3,13 @@ package transactions
 import (
 	"bitbucket.org/atticlab/horizon/cache"
 	"bitbucket.org/atticlab/horizon/config"
+	"bitbucket.org/atticlab/horizon/db2"
 	"bitbucket.org/atticlab/horizon/db2/core"
 	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/options"
 	"bitbucket.org/atticlab/horizon/txsub/transactions/statistics"
+	"bitbucket.org/atticlab/horizon/txsub/transactions/validators"
+	"golang.org/x/net/context"
 )
 
 type Manager struct {
@@ -14,6 +18,10 @@ type Manager struct {
 	HistoryQ     history.QInterface
 	StatsManager statistics.ManagerInterface
 	Config       *config.Config
+	// Screener screens counterparties of payment-like operations. Screening is disabled if nil
+	Screener validators.ScreeningValidatorInterface
+	// Options holds runtime-editable options. Startup configuration is used if nil
+	Options *options.Registry
 }
 
 func NewManager(core core.QInterface, history history.QInterface, statsManager statistics.ManagerInterface, config *config.Config, sharedCache *cache.SharedCache) *Manager {
@@ -25,3 +33,31 @@ func NewManager(core core.QInterface, history history.QInterface, statsManager s
 		SharedCache:  sharedCache,
 	}
 }
+
+// WithContext returns copy of the manager, which runs db queries and redis
+// commands within ctx, so they are logged and traced as part of the request
+func (m *Manager) WithContext(ctx context.Context) *Manager {
+	result := *m
+	if q, ok := m.HistoryQ.(*history.Q); ok {
+		result.HistoryQ = &history.Q{Repo: &db2.Repo{DB: q.Repo.DB, Ctx: ctx}}
+	}
+	if q, ok := m.CoreQ.(*core.Q); ok {
+		result.CoreQ = &core.Q{Repo: &db2.Repo{DB: q.Repo.DB, Ctx: ctx}}
+	}
+	if m.StatsManager != nil {
+		result.StatsManager = m.StatsManager.WithContext(ctx)
+	}
+	return &result
+}
+
+// AnonymousUserRestrictions returns effective limits for anonymous users of
+// the asset. Amounts configured for the asset take precedence over global
+// ones, which are overridden by runtime options.
+func (m *Manager) AnonymousUserRestrictions(asset history.Asset) config.AnonymousUserRestrictions {
+	global := m.Options.AnonymousUserRestrictions(m.Config.AnonymousUserRestrictions)
+	restrictions, ok := m.Config.AssetRestrictions[config.AssetKey{Code: asset.Code, Issuer: asset.Issuer}]
+	if !ok {
+		return global
+	}
+	return restrictions.Apply(global)
+}
@@ -0,0 +1,52 @@
+package transactions
+
+import (
+	"testing"
+
+	"bitbucket.org/atticlab/go-smart-base/keypair"
+	"bitbucket.org/atticlab/horizon/config"
+	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/options"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestManagerAnonymousUserRestrictions(t *testing.T) {
+	Convey("AnonymousUserRestrictions", t, func() {
+		bank, err := keypair.Random()
+		So(err, ShouldBeNil)
+		issuer, err := keypair.Random()
+		So(err, ShouldBeNil)
+
+		maxBalance := int64(100)
+		conf := &config.Config{
+			AnonymousUserRestrictions: config.AnonymousUserRestrictions{
+				MaxDailyOutcome: 10,
+				MaxBalance:      20,
+			},
+			AssetRestrictions: map[config.AssetKey]config.AssetRestrictions{
+				{Code: "EUAH", Issuer: bank.Address()}: {MaxBalance: &maxBalance},
+			},
+		}
+
+		historyQ := &history.QMock{}
+		historyQ.On("OptionsAll").Return([]history.Options{
+			{Name: options.AnonymousMaxDailyOutcome, Data: "0.0000030"},
+		}, nil).Once()
+		registry := options.NewRegistry(historyQ, conf, nil)
+		So(registry.Reload(), ShouldBeNil)
+
+		manager := &Manager{Config: conf, Options: registry}
+
+		Convey("Asset restrictions are applied over runtime options", func() {
+			restrictions := manager.AnonymousUserRestrictions(history.Asset{Code: "EUAH", Issuer: bank.Address()})
+			So(restrictions.MaxBalance, ShouldEqual, 100)
+			So(restrictions.MaxDailyOutcome, ShouldEqual, 30)
+		})
+
+		Convey("Asset of another issuer uses global restrictions", func() {
+			restrictions := manager.AnonymousUserRestrictions(history.Asset{Code: "EUAH", Issuer: issuer.Address()})
+			So(restrictions.MaxBalance, ShouldEqual, 20)
+			So(restrictions.MaxDailyOutcome, ShouldEqual, 30)
+		})
+	})
+}
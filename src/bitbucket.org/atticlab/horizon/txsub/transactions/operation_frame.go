@@ -29,6 +29,10 @@ type OperationFrame struct {
 	Index         int
 	log           *log.Entry
 	now           *time.Time
+	// screening result waiting to be recorded, if operation was screened
+	screening *history.ScreeningResult
+	// true if amount returned by reversal or refund is reserved
+	returnReserved bool
 }
 
 func NewOperationFrame(op *xdr.Operation, tx *TransactionFrame, index int, now time.Time) OperationFrame {
@@ -110,6 +114,7 @@ func (opFrame *OperationFrame) CheckValid(manager *Manager) (bool, error) {
 	}
 
 	opFrame.log.WithField("sourceAccount", opFrame.SourceAccount.Address).Debug("Loaded source account")
+	opFrame.screening = nil
 	// prepare result for op Result
 	opFrame.Result.Result = xdr.OperationResult{
 		Code: xdr.OperationResultCodeOpInner,
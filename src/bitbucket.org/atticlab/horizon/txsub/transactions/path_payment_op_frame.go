@@ -24,18 +24,22 @@ type PathPaymentOpFrame struct {
 	traitsValidator           validators.TraitsValidatorInterface
 	defaultOutLimitsValidator validators.OutgoingLimitsValidatorInterface
 	defaultInLimitsValidator  validators.IncomingLimitsValidatorInterface
+
+	// type of the submitted operation. Differs from op type, if path payment is created for payment
+	operationType             xdr.OperationType
 }
 
 func NewPathPaymentOpFrame(opFrame *OperationFrame) *PathPaymentOpFrame {
 	return &PathPaymentOpFrame{
 		OperationFrame: opFrame,
 		pathPayment:    opFrame.Op.Body.MustPathPaymentOp(),
+		operationType:  opFrame.Op.Body.Type,
 	}
 }
 
-func (p *PathPaymentOpFrame) GetTraitsValidator() validators.TraitsValidatorInterface {
+func (p *PathPaymentOpFrame) GetTraitsValidator(historyQ history.QInterface) validators.TraitsValidatorInterface {
 	if p.traitsValidator == nil {
-		p.traitsValidator = validators.NewTraitsValidator()
+		p.traitsValidator = validators.NewTraitsValidator(historyQ, *p.now)
 	}
 	return p.traitsValidator
 }
@@ -51,14 +55,14 @@ func (p *PathPaymentOpFrame) GetOutgoingLimitsValidator(paymentData *statistics.
 	if p.defaultOutLimitsValidator != nil {
 		return p.defaultOutLimitsValidator
 	}
-	return validators.NewOutgoingLimitsValidator(paymentData, manager.StatsManager, manager.HistoryQ, manager.Config.AnonymousUserRestrictions, *p.now)
+	return validators.NewOutgoingLimitsValidator(paymentData, manager.StatsManager, manager.HistoryQ, manager.AnonymousUserRestrictions(paymentData.Asset), *p.now)
 }
 
 func (p *PathPaymentOpFrame) GetIncomingLimitsValidator(paymentData *statistics.PaymentData, manager *Manager) validators.IncomingLimitsValidatorInterface {
 	if p.defaultInLimitsValidator != nil {
 		return p.defaultInLimitsValidator
 	}
-	return validators.NewIncomingLimitsValidator(paymentData, manager.HistoryQ, manager.StatsManager, manager.Config.AnonymousUserRestrictions, *p.now)
+	return validators.NewIncomingLimitsValidator(paymentData, manager.HistoryQ, manager.StatsManager, manager.AnonymousUserRestrictions(paymentData.Asset), *p.now)
 }
 
 func (p *PathPaymentOpFrame) GetAssetsValidator(historyQ history.QInterface) validators.AssetsValidatorInterface {
@@ -174,7 +178,7 @@ func (p *PathPaymentOpFrame) checkLimits(manager *Manager) (bool, error) {
 
 	// 2. Check traits for accounts
 	p.log.WithField("sourceAccount", p.SourceAccount.Address).WithField("destAccount", p.destAccount.Address).Debug("Checking traits")
-	accountRestricted, err := p.GetTraitsValidator().CheckTraits(p.SourceAccount, p.destAccount)
+	accountRestricted, err := p.GetTraitsValidator(manager.HistoryQ).CheckTraits(p.SourceAccount, p.destAccount, p.sendAsset.Code, p.destAsset.Code)
 	if err != nil {
 		return false, err
 	}
@@ -185,7 +189,19 @@ func (p *PathPaymentOpFrame) checkLimits(manager *Manager) (bool, error) {
 		return false, nil
 	}
 
-	// 3. Check restrictions for sender
+	// 3. Screen counterparties
+	screeningRestricted, err := p.screen(manager, p.operationType, p.destAccount.Address, p.pathPayment.DestAsset, p.pathPayment.DestAmount)
+	if err != nil {
+		return false, err
+	}
+
+	if screeningRestricted != nil {
+		p.getInnerResult().Code = xdr.PathPaymentResultCodePathPaymentMalformed
+		p.Result.Info = results.AdditionalErrorInfoError(screeningRestricted)
+		return false, nil
+	}
+
+	// 4. Check restrictions for sender
 	operationData := statistics.NewOperationData(p.SourceAccount, p.Index, p.ParentTxFrame.TxHash)
 	outPaymentData := statistics.NewPaymentData(p.destAccount, &p.destTrustline, p.sendAsset, int64(p.pathPayment.SendMax), operationData)
 	outgoingValidator := p.GetOutgoingLimitsValidator(&outPaymentData, manager)
@@ -73,6 +73,8 @@ func (p *PaymentOpFrame) DoCheckValid(manager *Manager) (bool, error) {
 		return false, nil
 	}
 
+	// screening result is recorded by the transaction, which holds this frame
+	p.screening = p.pathPayment.screening
 	p.getInnerResult().Code = xdr.PaymentResultCodePaymentSuccess
 	return true, nil
 }
@@ -111,9 +113,10 @@ func (p *PaymentOpFrame) createPathPayment(manager *Manager) *PathPaymentOpFrame
 	opFrame.innerOp = nil
 	innerOp, _ := opFrame.GetInnerOp()
 	ppayment := innerOp.(*PathPaymentOpFrame)
+	ppayment.operationType = p.Op.Body.Type
 	ppayment.accountTypeValidator = p.GetAccountTypeValidator()
 	ppayment.assetsValidator = p.GetAssetsValidator(manager.HistoryQ)
-	ppayment.traitsValidator = p.GetTraitsValidator()
+	ppayment.traitsValidator = p.GetTraitsValidator(manager.HistoryQ)
 	ppayment.defaultOutLimitsValidator = p.defaultOutLimitsValidator
 	ppayment.defaultInLimitsValidator = p.defaultInLimitsValidator
 	return ppayment
@@ -127,9 +130,9 @@ func (p *PaymentOpFrame) GetAssetsValidator(historyQ history.QInterface) validat
 	return p.assetsValidator
 }
 
-func (p *PaymentOpFrame) GetTraitsValidator() validators.TraitsValidatorInterface {
+func (p *PaymentOpFrame) GetTraitsValidator(historyQ history.QInterface) validators.TraitsValidatorInterface {
 	if p.traitsValidator == nil {
-		p.traitsValidator = validators.NewTraitsValidator()
+		p.traitsValidator = validators.NewTraitsValidator(historyQ, *p.now)
 	}
 	return p.traitsValidator
 }
@@ -117,14 +117,14 @@ func TestPaymentOpFrame(t *testing.T) {
 	accountTypeVMock.On("VerifyAccountTypesForPayment", mock.Anything, mock.Anything).Return(nil)
 	Convey("Failed to get traits", t, func() {
 		errorData := "failed to get traits"
-		traitsMock.On("CheckTraits", &from, &to).Return(nil, errors.New(errorData)).Once()
+		traitsMock.On("CheckTraits", &from, &to, destAsset.Code, destAsset.Code).Return(nil, errors.New(errorData)).Once()
 		isValid, err := opFrame.CheckValid(manager)
 		So(err.Error(), ShouldEqual, errorData)
 		So(isValid, ShouldBeFalse)
 	})
 	Convey("One of accounts - restricted", t, func() {
 		errorData := "account_restricted"
-		traitsMock.On("CheckTraits", &from, &to).Return(&results.RestrictedForAccountError{
+		traitsMock.On("CheckTraits", &from, &to, destAsset.Code, destAsset.Code).Return(&results.RestrictedForAccountError{
 			Reason: errorData,
 		}, nil).Once()
 		isValid, err := opFrame.CheckValid(manager)
@@ -133,7 +133,7 @@ func TestPaymentOpFrame(t *testing.T) {
 		So(opFrame.GetResult().Result.MustTr().MustPaymentResult().Code, ShouldEqual, xdr.PaymentResultCodePaymentMalformed)
 		So(opFrame.GetResult().Info.GetError(), ShouldEqual, errorData)
 	})
-	traitsMock.On("CheckTraits", &from, &to).Return(nil, nil)
+	traitsMock.On("CheckTraits", &from, &to, destAsset.Code, destAsset.Code).Return(nil, nil)
 	Convey("Failed to validate out limits", t, func() {
 		errorData := "limits_failed"
 		outLimitsValidator.On("VerifyLimits").Return(nil, errors.New(errorData)).Once()
@@ -178,6 +178,37 @@ func TestPaymentOpFrame(t *testing.T) {
 		So(isValid, ShouldBeTrue)
 		So(opFrame.GetResult().Result.MustTr().MustPaymentResult().Code, ShouldEqual, xdr.PaymentResultCodePaymentSuccess)
 	})
+	Convey("Screening", t, func() {
+		screener := &validators.ScreeningValidatorMock{}
+		manager.Screener = screener
+		defer func() { manager.Screener = nil }()
+		Convey("Review is kept for transaction to record", func() {
+			screener.On("Screen", mock.Anything).Return(&validators.ScreeningResult{
+				Provider: validators.ScreeningProviderList,
+				Decision: validators.ScreeningDecisionReview,
+			}, nil).Once()
+			isValid, err := opFrame.CheckValid(manager)
+			So(err, ShouldBeNil)
+			So(isValid, ShouldBeTrue)
+			So(opFrame.screening, ShouldNotBeNil)
+			So(opFrame.screening.Decision, ShouldEqual, string(validators.ScreeningDecisionReview))
+			So(opFrame.screening.AssetIssuer, ShouldEqual, destAsset.Issuer)
+		})
+		Convey("Deny is recorded and rejects payment", func() {
+			screener.On("Screen", mock.Anything).Return(&validators.ScreeningResult{
+				Provider: validators.ScreeningProviderList,
+				Decision: validators.ScreeningDecisionDeny,
+				Reason:   "sanctioned",
+			}, nil).Once()
+			historyQMock.On("ScreeningResultInsert", mock.Anything).Return(nil).Once()
+			isValid, err := opFrame.CheckValid(manager)
+			So(err, ShouldBeNil)
+			So(isValid, ShouldBeFalse)
+			So(opFrame.GetResult().Result.MustTr().MustPaymentResult().Code, ShouldEqual, xdr.PaymentResultCodePaymentMalformed)
+			So(opFrame.GetResult().Info.GetError(), ShouldEqual, "sanctioned")
+			historyQMock.AssertCalled(t, "ScreeningResultInsert", mock.Anything)
+		})
+	})
 
 }
 
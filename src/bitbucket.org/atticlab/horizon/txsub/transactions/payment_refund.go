@@ -0,0 +1,69 @@
+package transactions
+
+import (
+	"time"
+
+	"bitbucket.org/atticlab/go-smart-base/xdr"
+	"bitbucket.org/atticlab/horizon/db2/history"
+)
+
+// PaymentRefundReservationTTL is the period amount returned by submitted
+// reversal or refund stays reserved, if its transaction is not ingested, e.g.
+// when it was rejected by stellar-core
+var PaymentRefundReservationTTL = 5 * time.Minute
+
+// returnedByTx returns amount of the payment returned by reversals and refunds
+// preceding the operation in its transaction
+func (opFrame *OperationFrame) returnedByTx(paymentID int64) int64 {
+	var total int64
+	ops := opFrame.ParentTxFrame.Tx.Tx.Operations
+	for i := 0; i < opFrame.Index && i < len(ops); i++ {
+		switch ops[i].Body.Type {
+		case xdr.OperationTypePaymentReversal:
+			reversal := ops[i].Body.MustPaymentReversalOp()
+			if int64(reversal.PaymentId) == paymentID {
+				total += int64(reversal.Amount)
+			}
+		case xdr.OperationTypeRefund:
+			refund := ops[i].Body.MustRefundOp()
+			if int64(refund.PaymentId) == paymentID {
+				total += int64(refund.Amount)
+			}
+		}
+	}
+	return total
+}
+
+// reserveReturn reserves amount of the payment returned by the operation, so
+// neither operations of the same transaction nor concurrent submissions return
+// more than paymentAmount in total. Returns false, if amount exceeds the rest
+// of the payment.
+func (opFrame *OperationFrame) reserveReturn(manager *Manager, paymentID, paymentAmount, amount int64) (bool, error) {
+	reserved, err := manager.HistoryQ.PaymentRefundReserve(&history.PaymentRefundReservation{
+		TxHash:    opFrame.ParentTxFrame.TxHash,
+		OpIndex:   opFrame.Index,
+		PaymentID: paymentID,
+		Amount:    amount,
+		ExpiresAt: opFrame.now.Add(PaymentRefundReservationTTL),
+	}, paymentAmount-opFrame.returnedByTx(paymentID), *opFrame.now)
+	if err != nil {
+		return false, err
+	}
+
+	opFrame.returnReserved = reserved
+	return reserved, nil
+}
+
+// releaseReturn releases amount reserved by the operation, if any
+func (opFrame *OperationFrame) releaseReturn(manager *Manager) error {
+	if !opFrame.returnReserved {
+		return nil
+	}
+
+	err := manager.HistoryQ.PaymentRefundReservationDelete(opFrame.ParentTxFrame.TxHash, opFrame.Index)
+	if err != nil {
+		return err
+	}
+	opFrame.returnReserved = false
+	return nil
+}
@@ -5,7 +5,10 @@ import (
 	"bitbucket.org/atticlab/go-smart-base/xdr"
 	"bitbucket.org/atticlab/horizon/db2/history"
 	"bitbucket.org/atticlab/horizon/db2/history/details"
+	"bitbucket.org/atticlab/horizon/options"
+	"bitbucket.org/atticlab/horizon/txsub/results"
 	"database/sql"
+	"math/big"
 	"time"
 )
 
@@ -36,11 +39,24 @@ func (p *PaymentReversalOpFrame) DoCheckValid(manager *Manager) (bool, error) {
 		return false, err
 	}
 
-	if isValid {
-		p.getInnerResult().Code = xdr.PaymentReversalResultCodePaymentReversalSuccess
+	if !isValid {
+		return false, nil
+	}
+
+	restricted, err := p.screen(manager, p.Op.Body.Type, p.paymentReversal.PaymentSource.Address(), p.paymentReversal.Asset, p.paymentReversal.Amount)
+	if err != nil {
+		p.log.WithError(err).Error("Failed to screen reversal!")
+		return false, err
 	}
 
-	return isValid, nil
+	if restricted != nil {
+		p.getInnerResult().Code = xdr.PaymentReversalResultCodePaymentReversalMalformed
+		p.Result.Info = results.AdditionalErrorInfoError(restricted)
+		return false, nil
+	}
+
+	p.getInnerResult().Code = xdr.PaymentReversalResultCodePaymentReversalSuccess
+	return true, nil
 }
 
 func (p *PaymentReversalOpFrame) isPaymentValid() bool {
@@ -65,16 +81,6 @@ func (p *PaymentReversalOpFrame) validateAgainstPayment(manager *Manager) (bool,
 		return false, nil
 	}
 
-	isExpirationValid, err := p.checkExpiration(manager, &operation)
-	if err != nil {
-		p.log.WithError(err).Error("Failed to check expiration")
-		return false, err
-	}
-
-	if !isExpirationValid {
-		return false, nil
-	}
-
 	if operation.SourceAccount != p.paymentReversal.PaymentSource.Address() {
 		p.getInnerResult().Code = xdr.PaymentReversalResultCodePaymentReversalInvalidSource
 		return false, nil
@@ -87,11 +93,22 @@ func (p *PaymentReversalOpFrame) validateAgainstPayment(manager *Manager) (bool,
 		return false, err
 	}
 
-	return p.validateReversalPaymentDetails(&paymentDetails), nil
+	isValid, err := p.validateReversalPaymentDetails(manager, &paymentDetails)
+	if err != nil || !isValid {
+		return false, err
+	}
+
+	isExpirationValid, err := p.checkExpiration(manager, &operation, &paymentDetails)
+	if err != nil {
+		p.log.WithError(err).Error("Failed to check expiration")
+		return false, err
+	}
+
+	return isExpirationValid, nil
 }
 
-func (p *PaymentReversalOpFrame) checkExpiration(manager *Manager, operation *history.Operation) (bool, error) {
-	maxReversalDuration, err := p.getMaxReverseTime(manager)
+func (p *PaymentReversalOpFrame) checkExpiration(manager *Manager, operation *history.Operation, paymentDetails *details.Payment) (bool, error) {
+	maxReversalDuration, err := p.getMaxReverseTime(manager, paymentDetails)
 	if err != nil {
 		p.log.WithError(err).Error("Failed to get max reverse time!")
 		return false, err
@@ -105,7 +122,31 @@ func (p *PaymentReversalOpFrame) checkExpiration(manager *Manager, operation *hi
 	return true, nil
 }
 
-func (p *PaymentReversalOpFrame) getMaxReverseTime(manager *Manager) (time.Duration, error) {
+// getMaxReverseTime returns period the payment can be reversed within. Window
+// configured for the payment's asset and account types is used, if any,
+// otherwise global max reversal duration.
+func (p *PaymentReversalOpFrame) getMaxReverseTime(manager *Manager, paymentDetails *details.Payment) (time.Duration, error) {
+	sender, err := manager.AccountHistoryCache.Get(paymentDetails.From)
+	if err != nil {
+		p.log.WithError(err).Error("Failed to get payment sender")
+		return time.Duration(0), err
+	}
+
+	// payment receiver is the source of the reversal
+	window, err := manager.HistoryQ.ReversalWindowFor(paymentDetails.Code, paymentDetails.Issuer, sender.AccountType, p.SourceAccount.AccountType)
+	if err != nil {
+		p.log.WithError(err).Error("Failed to get reversal window")
+		return time.Duration(0), err
+	}
+
+	if window != nil {
+		return window.GetDuration(), nil
+	}
+
+	if manager.Options != nil {
+		return manager.Options.Duration(options.MaxReversalDuration, MAX_REVERSE_TIME), nil
+	}
+
 	maxDurationOption, err := manager.HistoryQ.OptionsByName(history.OPTIONS_MAX_REVERSAL_DURATION)
 	if err != nil {
 		p.log.WithError(err).Error("Failed to get max reversal duration from db")
@@ -116,47 +157,70 @@ func (p *PaymentReversalOpFrame) getMaxReverseTime(manager *Manager) (time.Durat
 		return MAX_REVERSE_TIME, nil
 	}
 
-	maxDuration, err := maxDurationOption.MaxReversalDuration().GetMaxDuration()
+	definition, _ := options.Find(options.MaxReversalDuration)
+	seconds, err := definition.Parse(maxDurationOption.Data)
 	if err != nil {
 		p.log.WithError(err).Error("Failed to get max reversal duration from option")
 		return time.Duration(0), err
 	}
 
-	return maxDuration, nil
+	return time.Duration(seconds) * time.Second, nil
 }
 
-func (p *PaymentReversalOpFrame) validateReversalPaymentDetails(paymentDetails *details.Payment) bool {
+func (p *PaymentReversalOpFrame) validateReversalPaymentDetails(manager *Manager, paymentDetails *details.Payment) (bool, error) {
 	if paymentDetails.To != p.SourceAccount.Address {
 		p.getInnerResult().Code = xdr.PaymentReversalResultCodePaymentReversalInvalidPaymentSender
-		return false
+		return false, nil
 	}
 
-	if int64(p.paymentReversal.Amount) != int64(amount.MustParse(paymentDetails.Amount)) {
+	paymentAmount := int64(amount.MustParse(paymentDetails.Amount))
+	if int64(p.paymentReversal.Amount) > paymentAmount {
 		p.getInnerResult().Code = xdr.PaymentReversalResultCodePaymentReversalInvalidAmount
-		return false
+		return false, nil
 	}
 
-	if !p.isCommissionValid(&paymentDetails.Fee) {
+	if !p.isCommissionValid(&paymentDetails.Fee, paymentAmount) {
 		p.getInnerResult().Code = xdr.PaymentReversalResultCodePaymentReversalInvalidCommission
-		return false
+		return false, nil
 	}
 
 	if !p.isAssetValid(&paymentDetails.Asset) {
 		p.getInnerResult().Code = xdr.PaymentReversalResultCodePaymentReversalInvalidAsset
-		return false
+		return false, nil
+	}
+
+	// payment can be reversed partially, but not more than was not yet returned by previous reversals and refunds
+	reserved, err := p.reserveReturn(manager, int64(p.paymentReversal.PaymentId), paymentAmount, int64(p.paymentReversal.Amount))
+	if err != nil {
+		p.log.WithError(err).Error("Failed to reserve returned amount of the payment")
+		return false, err
 	}
 
-	return true
+	if !reserved {
+		p.getInnerResult().Code = xdr.PaymentReversalResultCodePaymentReversalInvalidAmount
+		return false, nil
+	}
+
+	return true, nil
 }
 
-func (p *PaymentReversalOpFrame) isCommissionValid(fee *details.Fee) bool {
+// isCommissionValid checks that reversed commission is the part of the charged one
+// proportional to the reversed amount of the payment
+func (p *PaymentReversalOpFrame) isCommissionValid(fee *details.Fee, paymentAmount int64) bool {
 	commission := int64(p.paymentReversal.CommissionAmount)
 	if fee.AmountCharged == nil {
 		return commission == 0
 	}
 
 	actualCommission := int64(amount.MustParse(*fee.AmountCharged))
-	return actualCommission == commission
+	if int64(p.paymentReversal.Amount) == paymentAmount {
+		return actualCommission == commission
+	}
+
+	// charged * reversed / payment might overflow int64
+	expected := new(big.Int).Mul(big.NewInt(actualCommission), big.NewInt(int64(p.paymentReversal.Amount)))
+	expected.Quo(expected, big.NewInt(paymentAmount))
+	return expected.Int64() == commission
 }
 
 func (p *PaymentReversalOpFrame) isAssetValid(asset *details.Asset) bool {
@@ -181,5 +245,5 @@ func (p *PaymentReversalOpFrame) getInnerResult() *xdr.PaymentReversalResult {
 }
 
 func (p *PaymentReversalOpFrame) DoRollbackCachedData(manager *Manager) error {
-	return nil
+	return p.releaseReturn(manager)
 }
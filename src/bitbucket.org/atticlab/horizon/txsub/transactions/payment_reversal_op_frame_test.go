@@ -1,6 +1,7 @@
 package transactions
 
 import (
+	"bitbucket.org/atticlab/go-smart-base/amount"
 	"bitbucket.org/atticlab/go-smart-base/build"
 	"bitbucket.org/atticlab/go-smart-base/keypair"
 	"bitbucket.org/atticlab/go-smart-base/xdr"
@@ -10,6 +11,7 @@ import (
 	"bitbucket.org/atticlab/horizon/db2/history/details"
 	"bitbucket.org/atticlab/horizon/log"
 	"bitbucket.org/atticlab/horizon/test"
+	"bitbucket.org/atticlab/horizon/txsub/transactions/validators"
 	"database/sql"
 	"encoding/json"
 	"errors"
@@ -66,12 +68,18 @@ func TestPaymentReversalOpFrame(t *testing.T) {
 	historyQ.On("AccountByAddress", root.Address()).Return(history.Account{
 		Address: root.Address(),
 	}, nil)
+	historyQ.On("AccountByAddress", paymentSenderKP.Address()).Return(history.Account{
+		Address:     paymentSenderKP.Address(),
+		AccountType: xdr.AccountTypeAccountAnonymousUser,
+	}, nil)
+	historyQ.On("ReversalWindowFor", assetCode, mock.Anything, xdr.AccountTypeAccountAnonymousUser, mock.Anything).Return(nil, nil)
+	historyQ.On("PaymentRefundReserve", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
 	Convey("Negative amount", t, func() {
 		operation := validOperation
 		paymentReversalOp := *operation.Body.PaymentReversalOp
 		operation.Body.PaymentReversalOp = &paymentReversalOp
 		operation.Body.PaymentReversalOp.Amount = xdr.Int64(-100)
-		opFrame := NewOperationFrame(&operation, txE, 1, now)
+		opFrame := NewOperationFrame(&operation, txE, 0, now)
 		isValid, err := opFrame.CheckValid(manager)
 		So(err, ShouldBeNil)
 		So(isValid, ShouldBeFalse)
@@ -82,7 +90,7 @@ func TestPaymentReversalOpFrame(t *testing.T) {
 		paymentReversalOp := *operation.Body.PaymentReversalOp
 		operation.Body.PaymentReversalOp = &paymentReversalOp
 		operation.Body.PaymentReversalOp.CommissionAmount = xdr.Int64(-10)
-		opFrame := NewOperationFrame(&operation, txE, 1, now)
+		opFrame := NewOperationFrame(&operation, txE, 0, now)
 		isValid, err := opFrame.CheckValid(manager)
 		So(err, ShouldBeNil)
 		So(isValid, ShouldBeFalse)
@@ -91,7 +99,7 @@ func TestPaymentReversalOpFrame(t *testing.T) {
 	Convey("Given valid payment reversal op", t, func() {
 		log.Error("Given valid payment reversal op")
 		operation := validOperation
-		opFrame := NewOperationFrame(&operation, txE, 1, now)
+		opFrame := NewOperationFrame(&operation, txE, 0, now)
 		Convey("Failed to get payment", func() {
 			expectedError := errors.New("Failed to get payment from db")
 			historyQ.On("OperationByID", mock.Anything, paymentID).Return(expectedError).Once()
@@ -277,4 +285,194 @@ func TestPaymentReversalOpFrame(t *testing.T) {
 		})
 
 	})
+	Convey("Given reversal window", t, func() {
+		windowQ := history.QMock{}
+		windowManager := NewManager(&coreQ, &windowQ, nil, &config, &cache.SharedCache{
+			AccountHistoryCache: cache.NewHistoryAccount(&windowQ),
+		})
+		windowQ.On("AccountByAddress", root.Address()).Return(history.Account{
+			Address:     root.Address(),
+			AccountType: xdr.AccountTypeAccountMerchant,
+		}, nil)
+		windowQ.On("AccountByAddress", paymentSenderKP.Address()).Return(history.Account{
+			Address:     paymentSenderKP.Address(),
+			AccountType: xdr.AccountTypeAccountRegisteredUser,
+		}, nil)
+
+		storedPayment := history.Operation{
+			Type:          xdr.OperationTypePayment,
+			ClosedAt:      now.Add(-10 * 24 * time.Hour),
+			SourceAccount: paymentSenderKP.Address(),
+		}
+		jsonDetails, err := json.Marshal(details.Payment{
+			From:   paymentSenderKP.Address(),
+			To:     root.Address(),
+			Amount: paymentAmount,
+			Asset: details.Asset{
+				Type:   "credit_alphanum4",
+				Code:   assetCode,
+				Issuer: root.Address(),
+			},
+			Fee: details.Fee{
+				AmountCharged: &commissionAmount,
+			},
+		})
+		assert.Nil(t, err)
+		storedPayment.DetailsString = null.StringFrom(string(jsonDetails))
+		windowQ.On("OperationByID", mock.Anything, paymentID).Run(func(args mock.Arguments) {
+			op := args.Get(0).(*history.Operation)
+			*op = storedPayment
+		}).Return(nil)
+		windowQ.On("PaymentRefundReserve", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+
+		checkWindow := func(duration time.Duration, expectedCode xdr.PaymentReversalResultCode) {
+			windowQ.On("ReversalWindowFor", assetCode, root.Address(), xdr.AccountTypeAccountRegisteredUser, xdr.AccountTypeAccountMerchant).Return(&history.ReversalWindow{
+				AssetCode:   assetCode,
+				AssetIssuer: root.Address(),
+				Duration:    int64(duration / time.Second),
+			}, nil).Once()
+			operation := validOperation
+			opFrame := NewOperationFrame(&operation, txE, 0, now)
+			isValid, err := opFrame.CheckValid(windowManager)
+			So(err, ShouldBeNil)
+			So(isValid, ShouldEqual, expectedCode == xdr.PaymentReversalResultCodePaymentReversalSuccess)
+			So(opFrame.GetResult().Result.MustTr().MustPaymentReversalResult().Code, ShouldEqual, expectedCode)
+		}
+		Convey("Chargeback window is longer than default", func() {
+			checkWindow(30*24*time.Hour, xdr.PaymentReversalResultCodePaymentReversalSuccess)
+		})
+		Convey("Payments can not be reversed", func() {
+			checkWindow(0, xdr.PaymentReversalResultCodePaymentReversalPaymentExpired)
+		})
+	})
+	Convey("Given partially returned payment", t, func() {
+		partialQ := history.QMock{}
+		partialManager := NewManager(&coreQ, &partialQ, nil, &config, &cache.SharedCache{
+			AccountHistoryCache: cache.NewHistoryAccount(&partialQ),
+		})
+		partialQ.On("AccountByAddress", root.Address()).Return(history.Account{
+			Address: root.Address(),
+		}, nil)
+		partialQ.On("AccountByAddress", paymentSenderKP.Address()).Return(history.Account{
+			Address: paymentSenderKP.Address(),
+		}, nil)
+		partialQ.On("ReversalWindowFor", assetCode, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+		partialQ.On("OptionsByName", history.OPTIONS_MAX_REVERSAL_DURATION).Return(nil, nil)
+
+		charged := "10"
+		storedPayment := history.Operation{
+			Type:          xdr.OperationTypePayment,
+			ClosedAt:      now,
+			SourceAccount: paymentSenderKP.Address(),
+		}
+		jsonDetails, err := json.Marshal(details.Payment{
+			From:   paymentSenderKP.Address(),
+			To:     root.Address(),
+			Amount: "100",
+			Asset: details.Asset{
+				Type:   "credit_alphanum4",
+				Code:   assetCode,
+				Issuer: root.Address(),
+			},
+			Fee: details.Fee{
+				AmountCharged: &charged,
+			},
+		})
+		assert.Nil(t, err)
+		storedPayment.DetailsString = null.StringFrom(string(jsonDetails))
+		partialQ.On("OperationByID", mock.Anything, paymentID).Run(func(args mock.Arguments) {
+			op := args.Get(0).(*history.Operation)
+			*op = storedPayment
+		}).Return(nil)
+
+		checkPartial := func(reversed, commission string, returned int64, expectedCode xdr.PaymentReversalResultCode) {
+			reserved := int64(amount.MustParse(reversed)) <= int64(amount.MustParse("100"))-returned
+			partialQ.On("PaymentRefundReserve", mock.Anything, int64(amount.MustParse("100")), now).Return(reserved, nil).Once()
+			partialTx := build.Transaction(build.PaymentReversal(build.CreditAmount{
+				Code:   assetCode,
+				Issuer: root.Address(),
+				Amount: reversed,
+			}, build.CommissionAmount{
+				Amount: commission,
+			}, build.PaymentID{
+				ID: paymentID,
+			}, build.PaymentSender{
+				AddressOrSeed: paymentSenderKP.Address(),
+			}), build.Sequence{1}, build.SourceAccount{root.Address()})
+			partialTxE := NewTransactionFrame(&EnvelopeInfo{
+				Tx: partialTx.Sign(root.Seed()).E,
+			})
+			opFrame := NewOperationFrame(&partialTxE.Tx.Tx.Operations[0], partialTxE, 0, now)
+			isValid, err := opFrame.CheckValid(partialManager)
+			So(err, ShouldBeNil)
+			So(isValid, ShouldEqual, expectedCode == xdr.PaymentReversalResultCodePaymentReversalSuccess)
+			So(opFrame.GetResult().Result.MustTr().MustPaymentReversalResult().Code, ShouldEqual, expectedCode)
+		}
+		Convey("Proportional commission is reversed", func() {
+			checkPartial("40", "4", int64(amount.MustParse("50")), xdr.PaymentReversalResultCodePaymentReversalSuccess)
+		})
+		Convey("Whole commission for part of the payment", func() {
+			checkPartial("40", "10", 0, xdr.PaymentReversalResultCodePaymentReversalInvalidCommission)
+		})
+		Convey("Cumulative amount exceeds payment", func() {
+			checkPartial("40", "4", int64(amount.MustParse("70")), xdr.PaymentReversalResultCodePaymentReversalInvalidAmount)
+		})
+		Convey("Screening", func() {
+			screener := &validators.ScreeningValidatorMock{}
+			partialManager.Screener = screener
+			Convey("Deny is recorded and rejects reversal", func() {
+				screener.On("Screen", mock.Anything).Return(&validators.ScreeningResult{
+					Provider: validators.ScreeningProviderList,
+					Decision: validators.ScreeningDecisionDeny,
+					Reason:   "sanctioned",
+				}, nil).Once()
+				partialQ.On("ScreeningResultInsert", mock.Anything).Run(func(args mock.Arguments) {
+					result := args.Get(0).(*history.ScreeningResult)
+					So(result.Destination, ShouldEqual, paymentSenderKP.Address())
+					So(result.AssetCode, ShouldEqual, assetCode)
+					So(result.AssetIssuer, ShouldEqual, root.Address())
+				}).Return(nil).Once()
+				checkPartial("40", "4", 0, xdr.PaymentReversalResultCodePaymentReversalMalformed)
+				partialQ.AssertNumberOfCalls(t, "ScreeningResultInsert", 1)
+			})
+			Convey("Allow is not recorded by operation", func() {
+				screener.On("Screen", mock.Anything).Return(&validators.ScreeningResult{
+					Provider: validators.ScreeningProviderList,
+					Decision: validators.ScreeningDecisionAllow,
+				}, nil).Once()
+				checkPartial("40", "4", 0, xdr.PaymentReversalResultCodePaymentReversalSuccess)
+				partialQ.AssertNotCalled(t, "ScreeningResultInsert", mock.Anything)
+			})
+		})
+		Convey("Reversals of the same transaction", func() {
+			reversal := build.PaymentReversal(build.CreditAmount{
+				Code:   assetCode,
+				Issuer: root.Address(),
+				Amount: "60",
+			}, build.CommissionAmount{
+				Amount: "6",
+			}, build.PaymentID{
+				ID: paymentID,
+			}, build.PaymentSender{
+				AddressOrSeed: paymentSenderKP.Address(),
+			})
+			doubleTx := build.Transaction(reversal, reversal, build.Sequence{1}, build.SourceAccount{root.Address()})
+			doubleTxE := NewTransactionFrame(&EnvelopeInfo{
+				Tx:          doubleTx.Sign(root.Seed()).E,
+				ContentHash: "double_reversal",
+			})
+
+			// the second reversal may return only the rest left by the first one
+			partialQ.On("PaymentRefundReserve", mock.Anything, int64(amount.MustParse("100")), mock.Anything).Return(true, nil).Once()
+			partialQ.On("PaymentRefundReserve", mock.Anything, int64(amount.MustParse("40")), mock.Anything).Return(false, nil).Once()
+			partialQ.On("PaymentRefundReservationDelete", "double_reversal", 0).Return(nil).Once()
+
+			isValid, err := doubleTxE.CheckValid(partialManager)
+			So(err, ShouldBeNil)
+			So(isValid, ShouldBeFalse)
+			So(doubleTxE.GetResult(), ShouldNotBeNil)
+			// reservation of the first reversal is released with the failed transaction
+			partialQ.AssertCalled(t, "PaymentRefundReservationDelete", "double_reversal", 0)
+		})
+	})
 }
@@ -5,6 +5,7 @@ import (
 	"bitbucket.org/atticlab/go-smart-base/xdr"
 	"bitbucket.org/atticlab/horizon/db2/history"
 	"bitbucket.org/atticlab/horizon/db2/history/details"
+	"bitbucket.org/atticlab/horizon/txsub/results"
 	"database/sql"
 )
 
@@ -38,11 +39,24 @@ func (p *RefundOpFrame) DoCheckValid(manager *Manager) (bool, error) {
 		return false, err
 	}
 
-	if isValid {
-		p.getInnerResult().Code = xdr.RefundResultCodeRefundSuccess
+	if !isValid {
+		return false, nil
+	}
+
+	restricted, err := p.screen(manager, p.Op.Body.Type, p.refund.PaymentSource.Address(), p.refund.Asset, p.refund.Amount)
+	if err != nil {
+		p.log.WithError(err).Error("Failed to screen refund!")
+		return false, err
 	}
 
-	return isValid, nil
+	if restricted != nil {
+		p.getInnerResult().Code = xdr.RefundResultCodeRefundMalformed
+		p.Result.Info = results.AdditionalErrorInfoError(restricted)
+		return false, nil
+	}
+
+	p.getInnerResult().Code = xdr.RefundResultCodeRefundSuccess
+	return true, nil
 }
 
 func (p *RefundOpFrame) isPaymentValid() bool {
@@ -79,7 +93,23 @@ func (p *RefundOpFrame) validateAgainstPayment(manager *Manager) (bool, error) {
 		return false, err
 	}
 
-	return p.validateRefundDetails(&paymentDetails), nil
+	if !p.validateRefundDetails(&paymentDetails) {
+		return false, nil
+	}
+
+	// sum of all refunds and reversals must not exceed original amount
+	reserved, err := p.reserveReturn(manager, int64(p.refund.PaymentId), int64(p.refund.OriginalAmount), int64(p.refund.Amount))
+	if err != nil {
+		p.log.WithError(err).Error("Failed to reserve returned amount of the payment")
+		return false, err
+	}
+
+	if !reserved {
+		p.getInnerResult().Code = xdr.RefundResultCodeRefundInvalidAmount
+		return false, nil
+	}
+
+	return true, nil
 }
 
 func (p *RefundOpFrame) validateRefundDetails(paymentDetails *details.Payment) bool {
@@ -124,5 +154,5 @@ func (p *RefundOpFrame) getInnerResult() *xdr.RefundResult {
 }
 
 func (p *RefundOpFrame) DoRollbackCachedData(manager *Manager) error {
-	return nil
+	return p.releaseReturn(manager)
 }
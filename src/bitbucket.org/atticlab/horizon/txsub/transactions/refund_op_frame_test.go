@@ -1,6 +1,7 @@
 package transactions
 
 import (
+	"bitbucket.org/atticlab/go-smart-base/amount"
 	"bitbucket.org/atticlab/go-smart-base/build"
 	"bitbucket.org/atticlab/go-smart-base/keypair"
 	"bitbucket.org/atticlab/go-smart-base/xdr"
@@ -10,6 +11,7 @@ import (
 	"bitbucket.org/atticlab/horizon/db2/history/details"
 	"bitbucket.org/atticlab/horizon/log"
 	"bitbucket.org/atticlab/horizon/test"
+	"bitbucket.org/atticlab/horizon/txsub/transactions/validators"
 	"database/sql"
 	"encoding/json"
 	"errors"
@@ -67,21 +69,68 @@ func TestRefundOpFrame(t *testing.T) {
 	historyQ.On("AccountByAddress", root.Address()).Return(history.Account{
 		Address: root.Address(),
 	}, nil)
+	historyQ.On("PaymentRefundReserve", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
 	Convey("Negative amount", t, func() {
 		operation := validOperation
 		refundOp := *operation.Body.RefundOp
 		operation.Body.RefundOp = &refundOp
 		operation.Body.RefundOp.Amount = xdr.Int64(-100)
-		opFrame := NewOperationFrame(&operation, txE, 1, now)
+		opFrame := NewOperationFrame(&operation, txE, 0, now)
 		isValid, err := opFrame.CheckValid(manager)
 		So(err, ShouldBeNil)
 		So(isValid, ShouldBeFalse)
 		So(opFrame.GetResult().Result.MustTr().MustRefundResult().Code, ShouldEqual, xdr.RefundResultCodeRefundMalformed)
 	})
+	Convey("Payment already partially returned", t, func() {
+		refundedQ := history.QMock{}
+		refundedManager := NewManager(&coreQ, &refundedQ, nil, &config, &cache.SharedCache{
+			AccountHistoryCache: cache.NewHistoryAccount(&refundedQ),
+		})
+		refundedQ.On("AccountByAddress", root.Address()).Return(history.Account{
+			Address:     root.Address(),
+			AccountType: xdr.AccountTypeAccountMerchant,
+		}, nil)
+		storedPayment := history.Operation{
+			Type:          xdr.OperationTypePayment,
+			ClosedAt:      now,
+			SourceAccount: paymentSenderKP.Address(),
+		}
+		jsonDetails, err := json.Marshal(details.Payment{
+			From:   paymentSenderKP.Address(),
+			To:     root.Address(),
+			Amount: paymentAmount,
+			Asset: details.Asset{
+				Type:   "credit_alphanum4",
+				Code:   assetCode,
+				Issuer: root.Address(),
+			},
+		})
+		assert.Nil(t, err)
+		storedPayment.DetailsString = null.StringFrom(string(jsonDetails))
+		refundedQ.On("OperationByID", mock.Anything, paymentID).Run(func(args mock.Arguments) {
+			op := args.Get(0).(*history.Operation)
+			*op = storedPayment
+		}).Return(nil)
+		checkRefunded := func(returned int64, expectedCode xdr.RefundResultCode) {
+			refundedQ.On("PaymentRefundReserve", mock.Anything, int64(amount.MustParse(paymentAmount)), now).Return(returned == 0, nil).Once()
+			operation := validOperation
+			opFrame := NewOperationFrame(&operation, txE, 0, now)
+			isValid, err := opFrame.CheckValid(refundedManager)
+			So(err, ShouldBeNil)
+			So(isValid, ShouldEqual, expectedCode == xdr.RefundResultCodeRefundSuccess)
+			So(opFrame.GetResult().Result.MustTr().MustRefundResult().Code, ShouldEqual, expectedCode)
+		}
+		Convey("Remaining amount is less than refund", func() {
+			checkRefunded(1, xdr.RefundResultCodeRefundInvalidAmount)
+		})
+		Convey("Nothing returned yet", func() {
+			checkRefunded(0, xdr.RefundResultCodeRefundSuccess)
+		})
+	})
 	Convey("Given valid payment reversal op", t, func() {
 		log.Error("Given valid payment reversal op")
 		operation := validOperation
-		opFrame := NewOperationFrame(&operation, txE, 1, now)
+		opFrame := NewOperationFrame(&operation, txE, 0, now)
 		Convey("Failed to get payment", func() {
 			expectedError := errors.New("Failed to get payment from db")
 			historyQ.On("OperationByID", mock.Anything, paymentID).Return(expectedError).Once()
@@ -213,6 +262,44 @@ func TestRefundOpFrame(t *testing.T) {
 					So(isValid, ShouldBeTrue)
 					So(opFrame.GetResult().Result.MustTr().MustRefundResult().Code, ShouldEqual, xdr.RefundResultCodeRefundSuccess)
 				})
+				Convey("Screening", func() {
+					screener := &validators.ScreeningValidatorMock{}
+					manager.Screener = screener
+					defer func() { manager.Screener = nil }()
+					storedPayment := validStoredPayment
+					historyQ.On("OperationByID", mock.Anything, paymentID).Run(func(args mock.Arguments) {
+						op := args.Get(0).(*history.Operation)
+						*op = storedPayment
+					}).Return(nil).Once()
+					Convey("Allow is not recorded by operation", func() {
+						screener.On("Screen", mock.Anything).Return(&validators.ScreeningResult{
+							Provider: validators.ScreeningProviderList,
+							Decision: validators.ScreeningDecisionAllow,
+						}, nil).Once()
+						isValid, err := opFrame.CheckValid(manager)
+						So(err, ShouldBeNil)
+						So(isValid, ShouldBeTrue)
+						So(opFrame.GetResult().Result.MustTr().MustRefundResult().Code, ShouldEqual, xdr.RefundResultCodeRefundSuccess)
+						So(opFrame.screening, ShouldNotBeNil)
+					})
+					Convey("Deny is recorded and rejects refund", func() {
+						screener.On("Screen", mock.Anything).Return(&validators.ScreeningResult{
+							Provider: validators.ScreeningProviderList,
+							Decision: validators.ScreeningDecisionDeny,
+							Reason:   "sanctioned",
+						}, nil).Once()
+						historyQ.On("ScreeningResultInsert", mock.Anything).Run(func(args mock.Arguments) {
+							result := args.Get(0).(*history.ScreeningResult)
+							So(result.Decision, ShouldEqual, string(validators.ScreeningDecisionDeny))
+							So(result.AssetIssuer, ShouldEqual, root.Address())
+						}).Return(nil).Once()
+						isValid, err := opFrame.CheckValid(manager)
+						So(err, ShouldBeNil)
+						So(isValid, ShouldBeFalse)
+						So(opFrame.GetResult().Result.MustTr().MustRefundResult().Code, ShouldEqual, xdr.RefundResultCodeRefundMalformed)
+						So(opFrame.screening, ShouldBeNil)
+					})
+				})
 			})
 		})
 
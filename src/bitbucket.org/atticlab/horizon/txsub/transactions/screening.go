@@ -0,0 +1,81 @@
+package transactions
+
+import (
+	"bitbucket.org/atticlab/go-smart-base/amount"
+	"bitbucket.org/atticlab/go-smart-base/xdr"
+	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/txsub/results"
+	"bitbucket.org/atticlab/horizon/txsub/transactions/validators"
+)
+
+// screen checks counterparties of the payment-like operation using screener of the manager.
+// Returns restriction, if operation must be rejected. Deny decision is recorded at once, other
+// decisions are recorded by recordScreening only if the whole transaction passes validation.
+func (opFrame *OperationFrame) screen(manager *Manager, opType xdr.OperationType, destination string, asset xdr.Asset, opAmount xdr.Int64) (*results.RestrictedForAccountError, error) {
+	if manager.Screener == nil {
+		return nil, nil
+	}
+
+	var assetType, assetCode, assetIssuer string
+	err := asset.Extract(&assetType, &assetCode, &assetIssuer)
+	if err != nil {
+		return nil, err
+	}
+
+	request := validators.ScreeningRequest{
+		TxHash:         opFrame.ParentTxFrame.TxHash,
+		OperationIndex: opFrame.Index,
+		OperationType:  opType,
+		Type:           opType.String(),
+		Source:         opFrame.SourceAccount.Address,
+		Destination:    destination,
+		AssetCode:      assetCode,
+		AssetIssuer:    assetIssuer,
+		Amount:         amount.String(opAmount),
+	}
+
+	opFrame.log.WithField("destination", destination).Debug("Screening counterparties")
+	result, err := manager.Screener.Screen(&request)
+	if err != nil {
+		return nil, err
+	}
+
+	opFrame.screening = &history.ScreeningResult{
+		TxHash:         request.TxHash,
+		OperationIndex: int32(request.OperationIndex),
+		OperationType:  int32(opType),
+		Source:         request.Source,
+		Destination:    request.Destination,
+		AssetCode:      request.AssetCode,
+		AssetIssuer:    request.AssetIssuer,
+		Provider:       result.Provider,
+		Decision:       string(result.Decision),
+		Reason:         result.Reason,
+	}
+
+	if result.Decision != validators.ScreeningDecisionDeny {
+		return nil, nil
+	}
+
+	err = opFrame.recordScreening(manager)
+	if err != nil {
+		return nil, err
+	}
+
+	return &results.RestrictedForAccountError{
+		Reason: result.Reason,
+	}, nil
+}
+
+// recordScreening stores screening result of the operation, if it was screened.
+// Operations with review decision are raised to compliance officers once the
+// transaction is ingested.
+func (opFrame *OperationFrame) recordScreening(manager *Manager) error {
+	screening := opFrame.screening
+	opFrame.screening = nil
+	if screening == nil {
+		return nil
+	}
+
+	return manager.HistoryQ.ScreeningResultInsert(screening)
+}
@@ -0,0 +1,243 @@
+package statistics
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"bitbucket.org/atticlab/go-smart-base/amount"
+	"bitbucket.org/atticlab/go-smart-base/keypair"
+	"bitbucket.org/atticlab/go-smart-base/xdr"
+	"bitbucket.org/atticlab/horizon/accounttypes"
+	"bitbucket.org/atticlab/horizon/db2"
+	"bitbucket.org/atticlab/horizon/db2/core"
+	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/db2/sqx"
+	"bitbucket.org/atticlab/horizon/redis"
+	"bitbucket.org/atticlab/horizon/test"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisBackend(t *testing.T) {
+	tt := test.Start(t).Scenario("base")
+	defer tt.Finish()
+	err := redis.Init(test.RedisURL())
+	assert.Nil(t, err)
+
+	config := test.NewTestConfig()
+	historyQ := &history.Q{Repo: tt.HorizonRepo()}
+	testBackend(t, tt.HorizonRepo(), NewManager(historyQ, accounttype.GetAll(), &config))
+}
+
+func TestPostgresBackend(t *testing.T) {
+	tt := test.Start(t).Scenario("base")
+	defer tt.Finish()
+
+	config := test.NewTestConfig()
+	testBackend(t, tt.HorizonRepo(), NewPostgresManager(tt.HorizonRepo(), accounttype.GetAll(), &config))
+}
+
+// testBackend checks behaviour, which must be the same for all statistics backends.
+// repo is history db used by the backend to load stats of ingested payments.
+func testBackend(t *testing.T, repo *db2.Repo, manager ManagerInterface) {
+	direction := PaymentDirectionIncoming
+	counterparty := xdr.AccountTypeAccountBank
+	opAmount := int64(100 * amount.One)
+
+	Convey("Statistics backend", t, func() {
+		destKP, err := keypair.Random()
+		So(err, ShouldBeNil)
+		now := time.Now()
+		newPayment := func(index int) PaymentData {
+			return newBackendTestPayment(destKP.Address(), index, opAmount)
+		}
+
+		Convey("Op is accounted once", func() {
+			payment := newPayment(1)
+			stats, err := manager.UpdateGet(&payment, direction, now)
+			So(err, ShouldBeNil)
+			So(stats.Balance, ShouldEqual, opAmount)
+			So(stats.AccountsStatistics[counterparty].DailyIncome, ShouldEqual, opAmount)
+
+			stats, err = manager.UpdateGet(&payment, direction, now)
+			So(err, ShouldBeNil)
+			So(stats.Balance, ShouldEqual, opAmount)
+			So(stats.AccountsStatistics[counterparty].DailyIncome, ShouldEqual, opAmount)
+
+			Convey("Other ops are added", func() {
+				other := newPayment(2)
+				stats, err := manager.UpdateGet(&other, direction, now)
+				So(err, ShouldBeNil)
+				So(stats.Balance, ShouldEqual, 2*opAmount)
+				So(stats.AccountsStatistics[counterparty].DailyIncome, ShouldEqual, 2*opAmount)
+
+				Convey("Canceled op is subtracted", func() {
+					err := manager.CancelOp(&payment, direction, now)
+					So(err, ShouldBeNil)
+					stats, err := manager.UpdateGet(&other, direction, now)
+					So(err, ShouldBeNil)
+					So(stats.Balance, ShouldEqual, opAmount)
+					So(stats.AccountsStatistics[counterparty].DailyIncome, ShouldEqual, opAmount)
+
+					Convey("Op is canceled once", func() {
+						err := manager.CancelOp(&payment, direction, now)
+						So(err, ShouldBeNil)
+						stats, err := manager.UpdateGet(&other, direction, now)
+						So(err, ShouldBeNil)
+						So(stats.AccountsStatistics[counterparty].DailyIncome, ShouldEqual, opAmount)
+					})
+				})
+			})
+		})
+		Convey("Stats are loaded from history", func() {
+			stored := createRandomStats(destKP.Address(), "UAH", now.AddDate(0, 0, -1), accounttype.GetAll())
+			inserter := sqx.BatchInsertFromInsert(repo, history.AccountStatisticsInsert)
+			for _, value := range stored.AccountsStatistics {
+				stat := value
+				err := inserter.Insert(&stat)
+				So(err, ShouldBeNil)
+			}
+			err := inserter.Flush()
+			So(err, ShouldBeNil)
+
+			payment := newPayment(4)
+			payment.DestinationTrustLine = &core.Trustline{
+				Balance: xdr.Int64(stored.Balance),
+			}
+			stats, err := manager.UpdateGet(&payment, direction, now)
+			So(err, ShouldBeNil)
+			assertSameStats(getExpectedStats(&stored, payment, direction, now, true), stats)
+		})
+		Convey("Obsolete stats are cleared", func() {
+			yesterday := now.AddDate(0, 0, -1)
+			payment := newPayment(5)
+			other := newPayment(6)
+			oldStats, err := manager.UpdateGet(&payment, direction, yesterday)
+			So(err, ShouldBeNil)
+			expected := getExpectedStats(oldStats, other, direction, now, true)
+
+			stats, err := manager.UpdateGet(&other, direction, now)
+			So(err, ShouldBeNil)
+			assertSameStats(expected, stats)
+			So(stats.AccountsStatistics[counterparty].DailyIncome, ShouldEqual, opAmount)
+
+			Convey("Op of previous day is canceled", func() {
+				expected := copyAccountStats(stats)
+				expected.Balance -= opAmount
+				for key, value := range expected.AccountsStatistics {
+					value.ClearObsoleteStats(now)
+					if key == counterparty {
+						// op was added day ago, so daily stats are not changed
+						value.Update(-opAmount, yesterday, now, true)
+					}
+					expected.AccountsStatistics[key] = value
+				}
+
+				err := manager.CancelOp(&payment, direction, now)
+				So(err, ShouldBeNil)
+				stats, err := manager.UpdateGet(&other, direction, now)
+				So(err, ShouldBeNil)
+				assertSameStats(expected, stats)
+				So(stats.AccountsStatistics[counterparty].DailyIncome, ShouldEqual, opAmount)
+			})
+		})
+		Convey("Cancel of unknown op", func() {
+			payment := newPayment(3)
+			err := manager.CancelOp(&payment, direction, now)
+			So(err, ShouldBeNil)
+		})
+		Convey("Concurrent ops are not lost", func() {
+			// redis backend retries conflicting transactions limited number of times
+			numOfOps := 5
+			var wg sync.WaitGroup
+			errs := make(chan error, numOfOps)
+			for i := 0; i < numOfOps; i++ {
+				wg.Add(1)
+				go func(index int) {
+					defer wg.Done()
+					payment := newPayment(index)
+					_, err := manager.UpdateGet(&payment, direction, now)
+					errs <- err
+				}(i + 10)
+			}
+			wg.Wait()
+			close(errs)
+			for err := range errs {
+				So(err, ShouldBeNil)
+			}
+
+			payment := newPayment(10)
+			stats, err := manager.UpdateGet(&payment, direction, now)
+			So(err, ShouldBeNil)
+			So(stats.AccountsStatistics[counterparty].DailyIncome, ShouldEqual, int64(numOfOps)*opAmount)
+		})
+	})
+}
+
+// assertSameStats compares amounts of statistics, as backends may store time with different precision
+func assertSameStats(expected, actual *redis.AccountStatistics) {
+	So(actual, ShouldNotBeNil)
+	So(actual.Balance, ShouldEqual, expected.Balance)
+	So(len(actual.AccountsStatistics), ShouldEqual, len(expected.AccountsStatistics))
+	for key, expectedValue := range expected.AccountsStatistics {
+		actualValue, ok := actual.AccountsStatistics[key]
+		So(ok, ShouldBeTrue)
+		expectedValue.UpdatedAt = actualValue.UpdatedAt
+		So(actualValue, ShouldResemble, expectedValue)
+	}
+}
+
+func newBackendTestPayment(destination string, index int, opAmount int64) PaymentData {
+	operationData := NewOperationData(&history.Account{
+		Address:     test.BankMasterSeed().Address(),
+		AccountType: xdr.AccountTypeAccountBank,
+	}, index, fmt.Sprintf("tx_hash_%d", index))
+	return NewPaymentData(&history.Account{
+		Address:     destination,
+		AccountType: xdr.AccountTypeAccountAnonymousUser,
+	}, nil, history.Asset{
+		Code:   "UAH",
+		Issuer: test.BankMasterSeed().Address(),
+	}, opAmount, operationData)
+}
+
+func BenchmarkRedisBackend(b *testing.B) {
+	test.LoadScenario("base")
+	err := redis.Init(test.RedisURL())
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	config := test.NewTestConfig()
+	historyQ := &history.Q{Repo: &db2.Repo{DB: test.Database()}}
+	benchmarkBackend(b, NewManager(historyQ, accounttype.GetAll(), &config))
+}
+
+func BenchmarkPostgresBackend(b *testing.B) {
+	test.LoadScenario("base")
+	config := test.NewTestConfig()
+	benchmarkBackend(b, NewPostgresManager(&db2.Repo{DB: test.Database()}, accounttype.GetAll(), &config))
+}
+
+// benchmarkBackend measures throughput of UpdateGet. Each goroutine submits
+// payments to its own account, as it's usual for submission of different txs.
+func benchmarkBackend(b *testing.B, manager ManagerInterface) {
+	b.RunParallel(func(pb *testing.PB) {
+		destKP, err := keypair.Random()
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		index := 0
+		for pb.Next() {
+			index++
+			payment := newBackendTestPayment(destKP.Address(), index, amount.One)
+			_, err := manager.UpdateGet(&payment, PaymentDirectionIncoming, time.Now())
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -3,11 +3,14 @@ package statistics
 import (
 	"bitbucket.org/atticlab/go-smart-base/xdr"
 	"bitbucket.org/atticlab/horizon/config"
+	"bitbucket.org/atticlab/horizon/db2"
 	"bitbucket.org/atticlab/horizon/db2/core"
 	"bitbucket.org/atticlab/horizon/db2/history"
 	"bitbucket.org/atticlab/horizon/log"
+	"bitbucket.org/atticlab/horizon/options"
 	"bitbucket.org/atticlab/horizon/redis"
 	"errors"
+	"golang.org/x/net/context"
 	"time"
 )
 
@@ -17,6 +20,9 @@ type ManagerInterface interface {
 
 	// Cancels Op - removes it from processed ops and subtracts from stats
 	CancelOp(paymentData *PaymentData, paymentDirection PaymentDirection, now time.Time) error
+
+	// Returns manager, which runs queries and redis commands within ctx
+	WithContext(ctx context.Context) ManagerInterface
 }
 
 type Manager struct {
@@ -25,6 +31,11 @@ type Manager struct {
 	processedOpTimeOut time.Duration
 	numOfRetires       int
 	log                *log.Entry
+	// options overrides timeouts, if set
+	options *options.Registry
+	// keeps statistics in history db while redis is unavailable, if set
+	degraded         ManagerInterface
+	isRedisAvailable func() bool
 
 	historyQ                    history.QInterface
 	connectionProvider          redis.ConnectionProviderInterface
@@ -41,6 +52,7 @@ func NewManager(historyQ history.QInterface, counterparties []xdr.AccountType, c
 		processedOpTimeOut: config.ProcessedOpTimeout,
 		numOfRetires:       5,
 		log:                log.WithField("service", "statistics_manager"),
+		isRedisAvailable:   redis.IsAvailable,
 	}
 }
 
@@ -57,6 +69,56 @@ func (m *Manager) SetProcessedOpTimeout(timeout time.Duration) *Manager {
 	return m
 }
 
+// SetOptions makes manager use timeouts from runtime-editable options
+func (m *Manager) SetOptions(registry *options.Registry) *Manager {
+	m.options = registry
+	return m
+}
+
+// SetDegradeToPostgres makes manager pass statistics updates to the postgres
+// manager while redis is unavailable. Statistics cached in redis do not include
+// payments submitted while it was unavailable until they are ingested or the
+// cache expires.
+func (m *Manager) SetDegradeToPostgres(postgres ManagerInterface) *Manager {
+	m.degraded = postgres
+	return m
+}
+
+// WithContext returns copy of the manager, which runs db queries and redis
+// commands within ctx
+func (m *Manager) WithContext(ctx context.Context) ManagerInterface {
+	result := *m
+	if q, ok := m.historyQ.(*history.Q); ok {
+		result.historyQ = &history.Q{Repo: &db2.Repo{DB: q.Repo.DB, Ctx: ctx}}
+	}
+	if m.connectionProvider == nil {
+		result.connectionProvider = redis.NewContextConnectionProvider(ctx)
+	}
+	if m.degraded != nil {
+		result.degraded = m.degraded.WithContext(ctx)
+	}
+	return &result
+}
+
+func (m *Manager) isDegraded(err error) bool {
+	if m.degraded == nil {
+		return false
+	}
+
+	if err != nil {
+		return redis.IsUnavailable(err) || redis.IsRetryable(err)
+	}
+	return !m.isRedisAvailable()
+}
+
+func (m *Manager) getStatisticsTimeout() time.Duration {
+	return m.options.Duration(options.StatisticsTimeout, m.statisticsTimeOut)
+}
+
+func (m *Manager) getProcessedOpTimeout() time.Duration {
+	return m.options.Duration(options.ProcessedOpTimeout, m.processedOpTimeOut)
+}
+
 func (m *Manager) getConnectionProvider() redis.ConnectionProviderInterface {
 	if m.connectionProvider == nil {
 		m.connectionProvider = redis.NewConnectionProvider()
@@ -79,12 +141,22 @@ func (m *Manager) getAccountStatsProvider(conn redis.ConnectionInterface) redis.
 }
 
 func (m *Manager) CancelOp(paymentData *PaymentData, paymentDirection PaymentDirection, now time.Time) error {
+	if m.isDegraded(nil) {
+		m.log.Warn("Redis is unavailable - canceling op in history")
+		DefaultMetrics.DegradedMeter.Mark(1)
+		return m.degraded.CancelOp(paymentData, paymentDirection, now)
+	}
+
+	var err error
 	for i := 0; i < m.numOfRetires; i++ {
 		m.log.WithField("retry", i).Debug("CancelOp started new retry")
+		if i > 0 {
+			DefaultMetrics.RetryMeter.Mark(1)
+		}
 		var needRetry bool
-		needRetry, err := m.cancelOp(paymentData, paymentDirection, now)
+		needRetry, err = m.cancelOp(paymentData, paymentDirection, now)
 		if err != nil {
-			if !redis.IsConnectionClosed(err) {
+			if !redis.IsRetryable(err) {
 				return err
 			}
 			needRetry = true
@@ -95,6 +167,13 @@ func (m *Manager) CancelOp(paymentData *PaymentData, paymentDirection PaymentDir
 		}
 	}
 
+	if m.isDegraded(err) {
+		m.log.WithError(err).Warn("Redis is unavailable - canceling op in history")
+		DefaultMetrics.DegradedMeter.Mark(1)
+		return m.degraded.CancelOp(paymentData, paymentDirection, now)
+	}
+
+	DefaultMetrics.FailureMeter.Mark(1)
 	return errors.New("Failed to cancel op")
 }
 
@@ -132,18 +211,7 @@ func (m *Manager) cancelOp(paymentData *PaymentData, direction PaymentDirection,
 		return false, err
 	}
 
-	if direction.IsIncoming() {
-		accountStats.Balance -= paymentData.Amount
-	}
-
-	counterparty := paymentData.GetCounterparty(direction)
-	for key, value := range accountStats.AccountsStatistics {
-		value.ClearObsoleteStats(now)
-		if key == counterparty.AccountType {
-			value.Update(-paymentData.Amount, processedOp.TimeUpdated, now, direction.IsIncoming())
-		}
-		accountStats.AccountsStatistics[key] = value
-	}
+	cancelStats(accountStats, paymentData, direction, processedOp.TimeUpdated, now)
 
 	// Update stats and del op processed
 	// 4 Start multi
@@ -154,14 +222,13 @@ func (m *Manager) cancelOp(paymentData *PaymentData, direction PaymentDirection,
 	}
 
 	// 5. Save to redis stats
-	err = m.getAccountStatsProvider(conn).Insert(accountStats, m.statisticsTimeOut)
+	err = m.getAccountStatsProvider(conn).Insert(accountStats, m.getStatisticsTimeout())
 	if err != nil {
 		return false, err
 	}
 
-	processedOp = redis.NewProcessedOp(paymentData.TxHash, paymentData.Index, paymentData.Amount, direction.IsIncoming(), now)
 	// 6. Mark Op processed
-	err = m.getProcessedOpProvider(conn).Delete(paymentData.TxHash, paymentData.Index, direction.IsIncoming())
+	err = m.getProcessedOpProvider(conn).Delete(account.Address, paymentData.TxHash, paymentData.Index, direction.IsIncoming())
 	if err != nil {
 		return false, err
 	}
@@ -177,14 +244,21 @@ func (m *Manager) cancelOp(paymentData *PaymentData, direction PaymentDirection,
 }
 
 func (m *Manager) UpdateGet(paymentData *PaymentData, paymentDirection PaymentDirection, now time.Time) (result *redis.AccountStatistics, err error) {
+	if m.isDegraded(nil) {
+		return m.updateGetDegraded(paymentData, paymentDirection, now)
+	}
+
 	var accountStats *redis.AccountStatistics
 	for i := 0; i < m.numOfRetires; i++ {
 		m.log.WithField("retry", i).Debug("UpdateGet started new retry")
+		if i > 0 {
+			DefaultMetrics.RetryMeter.Mark(1)
+		}
 		var needRetry bool
 		accountStats, needRetry, err = m.updateGet(paymentData, paymentDirection, now)
 		if err != nil {
 			m.log.WithError(err).Error("Failed to updateGet statistics")
-			if !redis.IsConnectionClosed(err) {
+			if !redis.IsRetryable(err) {
 				return nil, err
 			}
 			needRetry = true
@@ -195,9 +269,22 @@ func (m *Manager) UpdateGet(paymentData *PaymentData, paymentDirection PaymentDi
 		}
 	}
 
+	if m.isDegraded(err) {
+		return m.updateGetDegraded(paymentData, paymentDirection, now)
+	}
+
+	DefaultMetrics.FailureMeter.Mark(1)
 	return nil, errors.New("Failed to Update and Get Account stats")
 }
 
+// updateGetDegraded updates and stores statistics in history db, so payments
+// submitted while redis is unavailable are counted by following submissions
+func (m *Manager) updateGetDegraded(paymentData *PaymentData, direction PaymentDirection, now time.Time) (*redis.AccountStatistics, error) {
+	m.log.Warn("Redis is unavailable - updating stats in history")
+	DefaultMetrics.DegradedMeter.Mark(1)
+	return m.degraded.UpdateGet(paymentData, direction, now)
+}
+
 func (m *Manager) updateGet(paymentData *PaymentData, direction PaymentDirection, now time.Time) (*redis.AccountStatistics, bool, error) {
 	m.log.Debug("Getting new connection")
 	conn := m.getConnectionProvider().GetConnection()
@@ -252,14 +339,14 @@ func (m *Manager) updateGet(paymentData *PaymentData, direction PaymentDirection
 	}
 
 	// 5. Save to redis stats
-	err = m.getAccountStatsProvider(conn).Insert(accountStats, m.statisticsTimeOut)
+	err = m.getAccountStatsProvider(conn).Insert(accountStats, m.getStatisticsTimeout())
 	if err != nil {
 		return nil, false, err
 	}
 
-	processedOp = redis.NewProcessedOp(paymentData.TxHash, paymentData.Index, paymentData.Amount, direction.IsIncoming(), now)
+	processedOp = redis.NewProcessedOp(paymentData.GetAccount(direction).Address, paymentData.TxHash, paymentData.Index, paymentData.Amount, direction.IsIncoming(), now)
 	// 6. Mark Op processed
-	err = m.getProcessedOpProvider(conn).Insert(processedOp, m.processedOpTimeOut)
+	err = m.getProcessedOpProvider(conn).Insert(processedOp, m.getProcessedOpTimeout())
 	if err != nil {
 		return nil, false, err
 	}
@@ -280,7 +367,8 @@ func (m *Manager) updateGet(paymentData *PaymentData, direction PaymentDirection
 func (m *Manager) getProcessedOp(conn redis.ConnectionInterface, paymentData *PaymentData, paymentDirection PaymentDirection) (*redis.ProcessedOp, error) {
 	// 1. Watch op
 	m.log.Debug("Setting watch for processed op key")
-	opKey := redis.GetProcessedOpKey(paymentData.TxHash, paymentData.Index, paymentDirection.IsIncoming())
+	account := paymentData.GetAccount(paymentDirection).Address
+	opKey := redis.GetProcessedOpKey(account, paymentData.TxHash, paymentData.Index, paymentDirection.IsIncoming())
 	err := conn.Watch(opKey)
 	if err != nil {
 		return nil, err
@@ -289,7 +377,7 @@ func (m *Manager) getProcessedOp(conn redis.ConnectionInterface, paymentData *Pa
 	// 2. Get op
 	m.log.Debug("Checking if op was processed")
 	processedOpProvider := m.getProcessedOpProvider(conn)
-	processedOp, err := processedOpProvider.Get(paymentData.TxHash, paymentData.Index, paymentDirection.IsIncoming())
+	processedOp, err := processedOpProvider.Get(account, paymentData.TxHash, paymentData.Index, paymentDirection.IsIncoming())
 	if err != nil {
 		m.log.WithError(err).Error("Failed to get processed op")
 		return nil, err
@@ -334,7 +422,7 @@ func (m *Manager) manageProcessedOp(conn redis.ConnectionInterface, account stri
 			return nil, false, err
 		}
 
-		err := accountStatsProvider.Insert(accountStats, m.statisticsTimeOut)
+		err := accountStatsProvider.Insert(accountStats, m.getStatisticsTimeout())
 		if err != nil {
 			return nil, false, err
 		}
@@ -349,6 +437,15 @@ func (m *Manager) manageProcessedOp(conn redis.ConnectionInterface, account stri
 }
 
 func (m *Manager) updateStats(accountStats *redis.AccountStatistics, counterparty xdr.AccountType, isIncome bool, opAmount int64, now time.Time) {
+	updateStats(accountStats, counterparty, isIncome, opAmount, now)
+}
+
+func (m *Manager) tryGetStatisticsFromDB(account string, asset history.Asset, trustLine *core.Trustline, now time.Time) (*redis.AccountStatistics, error) {
+	return getStatisticsFromDB(m.historyQ, account, asset, trustLine, now)
+}
+
+// updateStats adds op to account's statistics
+func updateStats(accountStats *redis.AccountStatistics, counterparty xdr.AccountType, isIncome bool, opAmount int64, now time.Time) {
 	if isIncome {
 		accountStats.Balance += opAmount
 	}
@@ -365,13 +462,30 @@ func (m *Manager) updateStats(accountStats *redis.AccountStatistics, counterpart
 	}
 }
 
-func (m *Manager) tryGetStatisticsFromDB(account string, asset history.Asset, trustLine *core.Trustline, now time.Time) (*redis.AccountStatistics, error) {
+// cancelStats subtracts op processed at opUpdatedAt from account's statistics
+func cancelStats(accountStats *redis.AccountStatistics, paymentData *PaymentData, direction PaymentDirection, opUpdatedAt, now time.Time) {
+	if direction.IsIncoming() {
+		accountStats.Balance -= paymentData.Amount
+	}
+
+	counterparty := paymentData.GetCounterparty(direction)
+	for key, value := range accountStats.AccountsStatistics {
+		value.ClearObsoleteStats(now)
+		if key == counterparty.AccountType {
+			value.Update(-paymentData.Amount, opUpdatedAt, now, direction.IsIncoming())
+		}
+		accountStats.AccountsStatistics[key] = value
+	}
+}
+
+// getStatisticsFromDB loads account's statistics of ingested payments
+func getStatisticsFromDB(historyQ history.QInterface, account string, asset history.Asset, trustLine *core.Trustline, now time.Time) (*redis.AccountStatistics, error) {
 	balance := int64(0)
 	if trustLine != nil {
 		balance = int64(trustLine.Balance)
 	}
 	accountStats := redis.NewAccountStatistics(account, asset.Code, balance, make(map[xdr.AccountType]history.AccountStatistics))
-	err := m.historyQ.GetStatisticsByAccountAndAsset(accountStats.AccountsStatistics, account, asset.Code, now)
+	err := historyQ.GetStatisticsByAccountAndAsset(accountStats.AccountsStatistics, account, asset.Code, now)
 	if err != nil {
 		return nil, err
 	}
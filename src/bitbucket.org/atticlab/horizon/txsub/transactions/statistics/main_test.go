@@ -65,7 +65,7 @@ func TestStatistics(t *testing.T) {
 		manager.defaultProcessedOpProvider = processedOpProvider
 		accountStatsProvider := &redis.AccountStatisticsProviderMock{}
 		manager.defaultAccountStatsProvider = accountStatsProvider
-		opKey := redis.GetProcessedOpKey(paymentData.TxHash, paymentData.Index, isIncome)
+		opKey := redis.GetProcessedOpKey(account, paymentData.TxHash, paymentData.Index, isIncome)
 
 		Convey("Failed to watch", func() {
 			errorData := "failed to watch op"
@@ -74,18 +74,30 @@ func TestStatistics(t *testing.T) {
 			So(err.Error(), ShouldEqual, errorData)
 			So(result, ShouldBeNil)
 		})
+		Convey("Redis is unavailable in degrade mode", func() {
+			postgres := &ManagerMock{}
+			manager.SetDegradeToPostgres(postgres)
+			manager.isRedisAvailable = func() bool { return false }
+			returnedStats := createRandomStats(account, assetCode, updatedTime, counterparties)
+			postgres.On("UpdateGet", &paymentData, direction, now).Return(&returnedStats, nil).Once()
+			result, err := manager.UpdateGet(&paymentData, direction, now)
+			So(err, ShouldBeNil)
+			So(result, ShouldEqual, &returnedStats)
+			postgres.AssertExpectations(t)
+			connProvider.AssertNotCalled(t, "GetConnection")
+		})
 		conn.On("Watch", opKey).Return(nil)
 		Convey("Op processed", func() {
 			Convey("Failed to check if op was processed", func() {
 				errorData := "Failed to check if op was processed"
-				processedOpProvider.On("Get", paymentData.TxHash, opIndex, isIncome).Return(nil, errors.New(errorData)).Once()
+				processedOpProvider.On("Get", account, paymentData.TxHash, opIndex, isIncome).Return(nil, errors.New(errorData)).Once()
 				result, err := manager.UpdateGet(&paymentData, direction, now)
 				So(err.Error(), ShouldEqual, errorData)
 				So(result, ShouldBeNil)
 			})
 			Convey("Op was processed", func() {
-				processedOp := redis.NewProcessedOp(paymentData.TxHash, opIndex, paymentData.Amount, isIncome, now)
-				processedOpProvider.On("Get", paymentData.TxHash, opIndex, isIncome).Return(processedOp, nil)
+				processedOp := redis.NewProcessedOp(account, paymentData.TxHash, opIndex, paymentData.Amount, isIncome, now)
+				processedOpProvider.On("Get", account, paymentData.TxHash, opIndex, isIncome).Return(processedOp, nil)
 				Convey("Failed to unwatch", func() {
 					errorData := "failed to connect"
 					conn.On("UnWatch").Return(errors.New(errorData)).Once()
@@ -115,7 +127,7 @@ func TestStatistics(t *testing.T) {
 			})
 		})
 		Convey("Op not processed", func() {
-			processedOpProvider.On("Get", paymentData.TxHash, opIndex, isIncome).Return(nil, nil)
+			processedOpProvider.On("Get", account, paymentData.TxHash, opIndex, isIncome).Return(nil, nil)
 			statsKey := redis.GetAccountStatisticsKey(account, assetCode)
 			Convey("Failed to watch stats", func() {
 				errorData := "failed to watch stats"
@@ -171,7 +183,7 @@ func TestStatistics(t *testing.T) {
 					So(result, ShouldBeNil)
 				})
 				accountStatsProvider.On("Insert", expectedStats, statsTimeout).Return(nil).Once()
-				processedOp := redis.NewProcessedOp(paymentData.TxHash, opIndex, paymentData.Amount, isIncome, now)
+				processedOp := redis.NewProcessedOp(account, paymentData.TxHash, opIndex, paymentData.Amount, isIncome, now)
 				Convey("Failed to insert op processed", func() {
 					errorData := "failed to insert op processed"
 					processedOpProvider.On("Insert", processedOp, opTimeout).Return(errors.New(errorData)).Once()
@@ -212,7 +224,7 @@ func TestStatistics(t *testing.T) {
 		manager.defaultProcessedOpProvider = processedOpProvider
 		accountStatsProvider := &redis.AccountStatisticsProviderMock{}
 		manager.defaultAccountStatsProvider = accountStatsProvider
-		opKey := redis.GetProcessedOpKey(paymentData.TxHash, opIndex, isIncome)
+		opKey := redis.GetProcessedOpKey(account, paymentData.TxHash, opIndex, isIncome)
 
 		Convey("Failed to watch", func() {
 			errorData := "failed to watch op"
@@ -223,12 +235,12 @@ func TestStatistics(t *testing.T) {
 		conn.On("Watch", opKey).Return(nil)
 		Convey("Failed to check if op was processed", func() {
 			errorData := "Failed to check if op was processed"
-			processedOpProvider.On("Get", paymentData.TxHash, opIndex, isIncome).Return(nil, errors.New(errorData)).Once()
+			processedOpProvider.On("Get", account, paymentData.TxHash, opIndex, isIncome).Return(nil, errors.New(errorData)).Once()
 			err := manager.CancelOp(&paymentData, direction, now)
 			So(err.Error(), ShouldEqual, errorData)
 		})
 		Convey("Op was already canceled", func() {
-			processedOpProvider.On("Get", paymentData.TxHash, opIndex, isIncome).Return(nil, nil)
+			processedOpProvider.On("Get", account, paymentData.TxHash, opIndex, isIncome).Return(nil, nil)
 			Convey("Failed to unwatch", func() {
 				errorData := "failed to connect"
 				conn.On("UnWatch").Return(errors.New(errorData)).Once()
@@ -239,8 +251,8 @@ func TestStatistics(t *testing.T) {
 			err := manager.CancelOp(&paymentData, direction, now)
 			So(err, ShouldBeNil)
 		})
-		processedOp := redis.NewProcessedOp(paymentData.TxHash, opIndex, paymentData.Amount, isIncome, now.AddDate(0, 0, -1))
-		processedOpProvider.On("Get", paymentData.TxHash, opIndex, isIncome).Return(processedOp, nil)
+		processedOp := redis.NewProcessedOp(account, paymentData.TxHash, opIndex, paymentData.Amount, isIncome, now.AddDate(0, 0, -1))
+		processedOpProvider.On("Get", account, paymentData.TxHash, opIndex, isIncome).Return(processedOp, nil)
 		Convey("Failed to watch stats", func() {
 			errorData := "failed to watch stats"
 			conn.On("Watch", returnedStats.GetKey()).Return(errors.New(errorData)).Once()
@@ -286,11 +298,11 @@ func TestStatistics(t *testing.T) {
 		accountStatsProvider.On("Insert", expectedStats, statsTimeout).Return(nil).Once()
 		Convey("Failed to delete op processed", func() {
 			errorData := "failed to delete op processed"
-			processedOpProvider.On("Delete", paymentData.TxHash, opIndex, isIncome).Return(errors.New(errorData))
+			processedOpProvider.On("Delete", account, paymentData.TxHash, opIndex, isIncome).Return(errors.New(errorData))
 			err := manager.CancelOp(&paymentData, direction, now)
 			So(err.Error(), ShouldEqual, errorData)
 		})
-		processedOpProvider.On("Delete", paymentData.TxHash, opIndex, isIncome).Return(nil)
+		processedOpProvider.On("Delete", account, paymentData.TxHash, opIndex, isIncome).Return(nil)
 		Convey("Failed to exec", func() {
 			errorData := "failed to exec"
 			conn.On("Exec").Return(false, errors.New(errorData))
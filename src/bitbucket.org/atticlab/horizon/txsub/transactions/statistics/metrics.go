@@ -0,0 +1,26 @@
+package statistics
+
+import (
+	"github.com/rcrowley/go-metrics"
+)
+
+// Metrics of statistics managers
+type Metrics struct {
+	// retries of redis transactions caused by conflicting updates or lost connections
+	RetryMeter metrics.Meter
+	// requests failed after all retries
+	FailureMeter metrics.Meter
+	// requests served from history db, as redis is unavailable
+	DegradedMeter metrics.Meter
+}
+
+// DefaultMetrics are metrics shared by all statistics managers
+var DefaultMetrics = NewMetrics()
+
+func NewMetrics() *Metrics {
+	return &Metrics{
+		RetryMeter:    metrics.NewMeter(),
+		FailureMeter:  metrics.NewMeter(),
+		DegradedMeter: metrics.NewMeter(),
+	}
+}
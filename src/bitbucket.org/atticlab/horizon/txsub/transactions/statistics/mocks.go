@@ -4,6 +4,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"time"
 	"bitbucket.org/atticlab/horizon/redis"
+	"golang.org/x/net/context"
 )
 
 type ManagerMock struct {
@@ -19,3 +20,8 @@ func (m *ManagerMock) CancelOp(paymentData *PaymentData, paymentDirection Paymen
 	return m.Called(paymentData, paymentDirection, now).Error(0)
 }
 
+
+// WithContext returns the mock itself, so expectations are shared
+func (m *ManagerMock) WithContext(ctx context.Context) ManagerInterface {
+	return m
+}
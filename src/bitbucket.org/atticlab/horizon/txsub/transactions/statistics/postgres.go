@@ -0,0 +1,240 @@
+package statistics
+
+import (
+	"time"
+
+	"bitbucket.org/atticlab/go-smart-base/xdr"
+	"bitbucket.org/atticlab/horizon/config"
+	"bitbucket.org/atticlab/horizon/db2"
+	"bitbucket.org/atticlab/horizon/db2/history"
+	"bitbucket.org/atticlab/horizon/log"
+	"bitbucket.org/atticlab/horizon/options"
+	"bitbucket.org/atticlab/horizon/redis"
+	"golang.org/x/net/context"
+)
+
+// PostgresManager keeps account statistics updated with submitted payments in
+// history db instead of redis. Updates of account-asset pair are serialized
+// with transaction level advisory lock, so no retries are needed.
+type PostgresManager struct {
+	counterparties     []xdr.AccountType
+	statisticsTimeOut  time.Duration
+	processedOpTimeOut time.Duration
+	log                *log.Entry
+	// options overrides timeouts, if set
+	options *options.Registry
+
+	repo *db2.Repo
+}
+
+// NewPostgresManager creates new statistics manager. counterparties MUST BE FULL ARRAY OF COUTERPARTIES.
+func NewPostgresManager(repo *db2.Repo, counterparties []xdr.AccountType, config *config.Config) *PostgresManager {
+	return &PostgresManager{
+		repo:               repo,
+		counterparties:     counterparties,
+		statisticsTimeOut:  config.StatisticsTimeout,
+		processedOpTimeOut: config.ProcessedOpTimeout,
+		log:                log.WithField("service", "postgres_statistics_manager"),
+	}
+}
+
+// timeout for statistics must be greater then for processed op
+func (m *PostgresManager) SetStatisticsTimeout(timeout time.Duration) *PostgresManager {
+	m.statisticsTimeOut = timeout
+	return m
+}
+
+// timeout for statistics must be greater then for processed op
+func (m *PostgresManager) SetProcessedOpTimeout(timeout time.Duration) *PostgresManager {
+	m.processedOpTimeOut = timeout
+	return m
+}
+
+// SetOptions makes manager use timeouts from runtime-editable options
+func (m *PostgresManager) SetOptions(registry *options.Registry) *PostgresManager {
+	m.options = registry
+	return m
+}
+
+// WithContext returns copy of the manager, which runs queries within ctx
+func (m *PostgresManager) WithContext(ctx context.Context) ManagerInterface {
+	result := *m
+	result.repo = &db2.Repo{DB: m.repo.DB, Ctx: ctx}
+	return &result
+}
+
+func (m *PostgresManager) getStatisticsTimeout() time.Duration {
+	return m.options.Duration(options.StatisticsTimeout, m.statisticsTimeOut)
+}
+
+func (m *PostgresManager) getProcessedOpTimeout() time.Duration {
+	return m.options.Duration(options.ProcessedOpTimeout, m.processedOpTimeOut)
+}
+
+// inTx runs fn in transaction holding lock of account-asset pair statistics
+func (m *PostgresManager) inTx(address, assetCode string, fn func(q *history.Q) error) error {
+	q := &history.Q{Repo: m.repo.Clone()}
+	err := q.Begin()
+	if err != nil {
+		return err
+	}
+	defer q.Rollback()
+
+	err = q.LockStatistics(address, assetCode)
+	if err != nil {
+		return err
+	}
+
+	err = fn(q)
+	if err != nil {
+		return err
+	}
+
+	return q.Commit()
+}
+
+func (m *PostgresManager) UpdateGet(paymentData *PaymentData, paymentDirection PaymentDirection, now time.Time) (*redis.AccountStatistics, error) {
+	var result *redis.AccountStatistics
+	account := paymentData.GetAccount(paymentDirection)
+	err := m.inTx(account.Address, paymentData.Asset.Code, func(q *history.Q) error {
+		var err error
+		result, err = m.updateGet(q, paymentData, paymentDirection, now)
+		return err
+	})
+	if err != nil {
+		m.log.WithError(err).Error("Failed to updateGet statistics")
+		DefaultMetrics.FailureMeter.Mark(1)
+		return nil, err
+	}
+	return result, nil
+}
+
+func (m *PostgresManager) updateGet(q *history.Q, paymentData *PaymentData, direction PaymentDirection, now time.Time) (*redis.AccountStatistics, error) {
+	account := paymentData.GetAccount(direction)
+	processedOp, err := q.StatisticsProcessedOpByKey(account.Address, paymentData.TxHash, paymentData.Index, direction.IsIncoming(), time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	accountStats, err := m.getAccountStatistics(q, account.Address, paymentData.Asset.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	isCached := accountStats != nil
+	if !isCached {
+		m.log.Debug("Getting stats from history")
+		accountStats, err = getStatisticsFromDB(q, account.Address, paymentData.Asset, paymentData.GetAccountTrustLine(direction), now)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		trustLine := paymentData.GetAccountTrustLine(direction)
+		if accountStats.Balance == 0 && trustLine != nil {
+			accountStats.Balance = int64(trustLine.Balance)
+		}
+	}
+
+	if processedOp != nil {
+		m.log.Debug("Op is processed")
+		if !isCached {
+			err = m.saveAccountStatistics(q, accountStats)
+		}
+		return accountStats, err
+	}
+
+	counterparty := paymentData.GetCounterparty(direction)
+	updateStats(accountStats, counterparty.AccountType, direction.IsIncoming(), paymentData.Amount, now)
+	err = m.saveAccountStatistics(q, accountStats)
+	if err != nil {
+		return nil, err
+	}
+
+	err = q.StatisticsProcessedOpInsert(&history.StatisticsProcessedOp{
+		Account:    account.Address,
+		TxHash:     paymentData.TxHash,
+		Index:      paymentData.Index,
+		IsIncoming: direction.IsIncoming(),
+		Amount:     paymentData.Amount,
+		UpdatedAt:  now,
+		ExpiresAt:  time.Now().Add(m.getProcessedOpTimeout()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return accountStats, nil
+}
+
+func (m *PostgresManager) CancelOp(paymentData *PaymentData, paymentDirection PaymentDirection, now time.Time) error {
+	account := paymentData.GetAccount(paymentDirection)
+	err := m.inTx(account.Address, paymentData.Asset.Code, func(q *history.Q) error {
+		return m.cancelOp(q, paymentData, paymentDirection, now)
+	})
+	if err != nil {
+		DefaultMetrics.FailureMeter.Mark(1)
+	}
+	return err
+}
+
+func (m *PostgresManager) cancelOp(q *history.Q, paymentData *PaymentData, direction PaymentDirection, now time.Time) error {
+	account := paymentData.GetAccount(direction)
+	processedOp, err := q.StatisticsProcessedOpByKey(account.Address, paymentData.TxHash, paymentData.Index, direction.IsIncoming(), time.Now())
+	if err != nil {
+		return err
+	}
+
+	if processedOp == nil {
+		m.log.Debug("Op is canceled")
+		return nil
+	}
+
+	accountStats, err := m.getAccountStatistics(q, account.Address, paymentData.Asset.Code)
+	if err != nil {
+		m.log.WithError(err).Error("Failed to get account statistics")
+		return err
+	}
+
+	if accountStats == nil {
+		m.log.Debug("Stats are not cached - no need to cancel operation")
+		return nil
+	}
+
+	cancelStats(accountStats, paymentData, direction, processedOp.UpdatedAt, now)
+	err = m.saveAccountStatistics(q, accountStats)
+	if err != nil {
+		return err
+	}
+
+	return q.StatisticsProcessedOpDelete(account.Address, paymentData.TxHash, paymentData.Index, direction.IsIncoming())
+}
+
+// getAccountStatistics returns cached statistics or nil, if cache is expired
+func (m *PostgresManager) getAccountStatistics(q *history.Q, address, assetCode string) (*redis.AccountStatistics, error) {
+	cache, err := q.StatisticsCacheByAccount(address, assetCode, time.Now())
+	if err != nil || cache == nil {
+		return nil, err
+	}
+
+	stats, err := cache.Statistics()
+	if err != nil {
+		return nil, err
+	}
+
+	return redis.NewAccountStatistics(address, assetCode, cache.Balance, stats), nil
+}
+
+func (m *PostgresManager) saveAccountStatistics(q *history.Q, accountStats *redis.AccountStatistics) error {
+	cache := history.StatisticsCache{
+		Account:   accountStats.Account,
+		AssetCode: accountStats.AssetCode,
+		Balance:   accountStats.Balance,
+		ExpiresAt: time.Now().Add(m.getStatisticsTimeout()),
+	}
+	err := cache.SetStatistics(accountStats.AccountsStatistics)
+	if err != nil {
+		return err
+	}
+
+	return q.StatisticsCacheSave(&cache, time.Now())
+}
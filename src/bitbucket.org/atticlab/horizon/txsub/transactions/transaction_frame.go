@@ -81,6 +81,14 @@ func (t *TransactionFrame) checkOperations(manager *Manager) (bool, error) {
 		t.rollbackCachedData(manager, opFrames)
 		return false, nil
 	}
+
+	for i := range opFrames {
+		err := opFrames[i].recordScreening(manager)
+		if err != nil {
+			t.log.WithField("operation_i", i).WithError(err).Error("Failed to record screening result")
+			return false, err
+		}
+	}
 	return isValid, nil
 }
 
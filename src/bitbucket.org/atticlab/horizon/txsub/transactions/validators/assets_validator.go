@@ -22,8 +22,18 @@ func NewAssetsValidator(historyQ history.QInterface) *AssetsValidator {
 	}
 }
 
+// GetValidAsset returns stored asset, if it's active. Returns nil for unknown,
+// suspended and retired assets.
 func (v *AssetsValidator) GetValidAsset(asset xdr.Asset) (*history.Asset, error) {
-	return v.assetsProvider.Get(asset)
+	storedAsset, err := v.assetsProvider.Get(asset)
+	if err != nil || storedAsset == nil {
+		return nil, err
+	}
+
+	if !storedAsset.IsActive() {
+		return nil, nil
+	}
+	return storedAsset, nil
 }
 
 func (v *AssetsValidator) IsAssetValid(asset xdr.Asset) (bool, error) {
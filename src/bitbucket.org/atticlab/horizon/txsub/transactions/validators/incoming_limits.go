@@ -109,10 +109,11 @@ func (v *IncomingLimitsValidator) verifyAnonymousAssetLimits() (*results.Exceede
 
 	if updatedBalance > v.anonUserRest.MaxBalance {
 		description := fmt.Sprintf(
-			"User's max balance exceeded: %s + %s out of %s UAH.",
+			"User's max balance exceeded: %s + %s out of %s %s.",
 			amount.String(xdr.Int64(updatedBalance-v.paymentData.Amount)),
 			amount.String(xdr.Int64(v.paymentData.Amount)),
 			amount.String(xdr.Int64(v.anonUserRest.MaxBalance)),
+			v.paymentData.Asset.Code,
 		)
 		return &results.ExceededLimitError{Description: description}, nil
 	}
@@ -172,10 +172,11 @@ func TestIncomingLimits(t *testing.T) {
 			result, err := v.VerifyLimits()
 			So(err, ShouldBeNil)
 			assert.Equal(t, &results.ExceededLimitError{Description: fmt.Sprintf(
-				"User's max balance exceeded: %s + %s out of %s UAH.",
+				"User's max balance exceeded: %s + %s out of %s %s.",
 				amount.String(xdr.Int64(stats.Balance - opAmount)),
 				amount.String(xdr.Int64(opAmount)),
 				amount.String(xdr.Int64(limits.MaxBalance)),
+				opAsset.Code,
 			)}, result)
 		})
 		Convey("Asset is anonymous exceeds max balance, but is not user", func() {
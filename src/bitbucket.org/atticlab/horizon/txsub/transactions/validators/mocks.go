@@ -46,8 +46,8 @@ type TraitsValidatorMock struct {
 	mock.Mock
 }
 
-func (v *TraitsValidatorMock) CheckTraits(source, destination *history.Account) (*results.RestrictedForAccountError, error) {
-	a := v.Called(source, destination)
+func (v *TraitsValidatorMock) CheckTraits(source, destination *history.Account, sendAsset, destAsset string) (*results.RestrictedForAccountError, error) {
+	a := v.Called(source, destination, sendAsset, destAsset)
 	rawResult := a.Get(0)
 	if rawResult != nil {
 		result := rawResult.(*results.RestrictedForAccountError)
@@ -55,8 +55,8 @@ func (v *TraitsValidatorMock) CheckTraits(source, destination *history.Account)
 	}
 	return nil, a.Error(1)
 }
-func (v *TraitsValidatorMock) CheckTraitsForAccount(account *history.Account, isSource bool) (*results.RestrictedForAccountError, error) {
-	a := v.Called(account, isSource)
+func (v *TraitsValidatorMock) CheckTraitsForAccount(account *history.Account, isSource bool, assetCode string) (*results.RestrictedForAccountError, error) {
+	a := v.Called(account, isSource, assetCode)
 	rawResult := a.Get(0)
 	if rawResult != nil {
 		result := rawResult.(*results.RestrictedForAccountError)
@@ -92,3 +92,17 @@ func (v *OutgoingLimitsValidatorMock) VerifyLimits() (*results.ExceededLimitErro
 	}
 	return nil, a.Error(1)
 }
+
+type ScreeningValidatorMock struct {
+	mock.Mock
+}
+
+func (v *ScreeningValidatorMock) Screen(request *ScreeningRequest) (*ScreeningResult, error) {
+	a := v.Called(request)
+	rawResult := a.Get(0)
+	if rawResult != nil {
+		result := rawResult.(*ScreeningResult)
+		return result, a.Error(1)
+	}
+	return nil, a.Error(1)
+}
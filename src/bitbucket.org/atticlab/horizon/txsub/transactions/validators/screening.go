@@ -0,0 +1,95 @@
+package validators
+
+import (
+	"bitbucket.org/atticlab/go-smart-base/xdr"
+)
+
+type ScreeningDecision string
+
+const (
+	// operation is allowed
+	ScreeningDecisionAllow ScreeningDecision = "allow"
+	// operation is allowed, but must be reviewed by compliance officer
+	ScreeningDecisionReview ScreeningDecision = "review"
+	// operation is rejected
+	ScreeningDecisionDeny ScreeningDecision = "deny"
+)
+
+// severity is used to choose the strictest of decisions
+func (d ScreeningDecision) severity() int {
+	switch d {
+	case ScreeningDecisionDeny:
+		return 2
+	case ScreeningDecisionReview:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// IsValid returns true if decision is known
+func (d ScreeningDecision) IsValid() bool {
+	return d == ScreeningDecisionAllow || d == ScreeningDecisionReview || d == ScreeningDecisionDeny
+}
+
+// ScreeningRequest describes payment-like operation to be screened
+type ScreeningRequest struct {
+	TxHash         string            `json:"tx_hash"`
+	OperationIndex int               `json:"operation_index"`
+	OperationType  xdr.OperationType `json:"-"`
+	Type           string            `json:"type"`
+	Source         string            `json:"source"`
+	Destination    string            `json:"destination"`
+	AssetCode      string            `json:"asset_code"`
+	AssetIssuer    string            `json:"asset_issuer"`
+	Amount         string            `json:"amount"`
+}
+
+// ScreeningResult is the decision of the screening validator
+type ScreeningResult struct {
+	Provider string
+	Decision ScreeningDecision
+	Reason   string
+}
+
+type ScreeningValidatorInterface interface {
+	// Screen checks counterparties of the operation. Returns error only if failed to make a decision.
+	Screen(request *ScreeningRequest) (*ScreeningResult, error)
+}
+
+// ScreeningValidatorChain asks all validators and returns the strictest decision
+type ScreeningValidatorChain struct {
+	validators []ScreeningValidatorInterface
+}
+
+func NewScreeningValidatorChain(validators ...ScreeningValidatorInterface) *ScreeningValidatorChain {
+	return &ScreeningValidatorChain{
+		validators: validators,
+	}
+}
+
+func (c *ScreeningValidatorChain) Screen(request *ScreeningRequest) (*ScreeningResult, error) {
+	var result *ScreeningResult
+	for _, validator := range c.validators {
+		current, err := validator.Screen(request)
+		if err != nil {
+			return nil, err
+		}
+
+		if result == nil || current.Decision.severity() > result.Decision.severity() {
+			result = current
+		}
+
+		if result.Decision == ScreeningDecisionDeny {
+			break
+		}
+	}
+
+	if result == nil {
+		result = &ScreeningResult{
+			Provider: "none",
+			Decision: ScreeningDecisionAllow,
+		}
+	}
+	return result, nil
+}
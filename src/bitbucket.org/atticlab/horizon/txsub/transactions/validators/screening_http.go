@@ -0,0 +1,97 @@
+package validators
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"bitbucket.org/atticlab/horizon/log"
+)
+
+const ScreeningProviderHTTP = "http"
+
+// httpScreeningResponse is the expected json response of the screening service
+type httpScreeningResponse struct {
+	Decision ScreeningDecision `json:"decision"`
+	Reason   string            `json:"reason"`
+}
+
+// HTTPScreeningValidator posts screening request to the external service.
+// If service is unavailable or returns unexpected response, operation is
+// allowed (fail-open) or rejected (fail-closed) depending on the policy.
+type HTTPScreeningValidator struct {
+	client     *http.Client
+	url        string
+	failClosed bool
+	log        *log.Entry
+}
+
+func NewHTTPScreeningValidator(url string, timeout time.Duration, failClosed bool) *HTTPScreeningValidator {
+	return &HTTPScreeningValidator{
+		client: &http.Client{
+			Timeout: timeout,
+		},
+		url:        url,
+		failClosed: failClosed,
+		log:        log.WithField("service", "http_screening_validator"),
+	}
+}
+
+func (v *HTTPScreeningValidator) Screen(request *ScreeningRequest) (*ScreeningResult, error) {
+	response, err := v.call(request)
+	if err != nil {
+		v.log.WithError(err).WithField("tx_hash", request.TxHash).Warn("Screening service is unavailable")
+		return v.unavailable(err), nil
+	}
+
+	return &ScreeningResult{
+		Provider: ScreeningProviderHTTP,
+		Decision: response.Decision,
+		Reason:   response.Reason,
+	}, nil
+}
+
+// unavailable returns decision made according to fail-open/fail-closed policy
+func (v *HTTPScreeningValidator) unavailable(err error) *ScreeningResult {
+	result := &ScreeningResult{
+		Provider: ScreeningProviderHTTP,
+		Decision: ScreeningDecisionAllow,
+		Reason:   "Screening service is unavailable: " + err.Error(),
+	}
+
+	if v.failClosed {
+		result.Decision = ScreeningDecisionDeny
+	}
+	return result
+}
+
+func (v *HTTPScreeningValidator) call(request *ScreeningRequest) (*httpScreeningResponse, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := v.client.Post(v.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	var response httpScreeningResponse
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	if err != nil {
+		return nil, err
+	}
+
+	if !response.Decision.IsValid() {
+		return nil, fmt.Errorf("unexpected decision %s", response.Decision)
+	}
+
+	return &response, nil
+}
@@ -0,0 +1,105 @@
+package validators
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"bitbucket.org/atticlab/horizon/db2/history"
+)
+
+const ScreeningProviderList = "list"
+
+// ListScreeningValidator rejects operations with counterparties listed in the
+// screening list managed by admin op or in the screening list file
+type ListScreeningValidator struct {
+	historyQ      history.QInterface
+	fileAddresses map[string]string
+}
+
+// NewListScreeningValidator creates list validator. If historyQ is nil, only
+// fileAddresses (address -> reason) are checked.
+func NewListScreeningValidator(historyQ history.QInterface, fileAddresses map[string]string) *ListScreeningValidator {
+	if fileAddresses == nil {
+		fileAddresses = make(map[string]string)
+	}
+	return &ListScreeningValidator{
+		historyQ:      historyQ,
+		fileAddresses: fileAddresses,
+	}
+}
+
+func (v *ListScreeningValidator) Screen(request *ScreeningRequest) (*ScreeningResult, error) {
+	for _, address := range []string{request.Source, request.Destination} {
+		if address == "" {
+			continue
+		}
+
+		reason, isListed, err := v.isListed(address)
+		if err != nil {
+			return nil, err
+		}
+
+		if isListed {
+			description := fmt.Sprintf("Account (%s) is in the screening list", address)
+			if reason != "" {
+				description += ": " + reason
+			}
+			return &ScreeningResult{
+				Provider: ScreeningProviderList,
+				Decision: ScreeningDecisionDeny,
+				Reason:   description,
+			}, nil
+		}
+	}
+
+	return &ScreeningResult{
+		Provider: ScreeningProviderList,
+		Decision: ScreeningDecisionAllow,
+	}, nil
+}
+
+func (v *ListScreeningValidator) isListed(address string) (string, bool, error) {
+	if reason, ok := v.fileAddresses[address]; ok {
+		return reason, true, nil
+	}
+
+	if v.historyQ == nil {
+		return "", false, nil
+	}
+
+	entry, err := v.historyQ.ScreeningListEntryByAddress(address)
+	if err != nil || entry == nil {
+		return "", false, err
+	}
+
+	return entry.Reason, true, nil
+}
+
+// LoadScreeningListFile reads addresses from file. Each line contains address
+// optionally followed by reason. Empty lines and lines starting with # are ignored.
+func LoadScreeningListFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	result := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		address, reason := line, ""
+		if i := strings.IndexAny(line, " \t"); i >= 0 {
+			address, reason = line[:i], strings.TrimSpace(line[i+1:])
+		}
+		result[address] = reason
+	}
+
+	return result, scanner.Err()
+}
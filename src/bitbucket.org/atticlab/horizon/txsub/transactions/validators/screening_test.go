@@ -0,0 +1,128 @@
+package validators
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"bitbucket.org/atticlab/horizon/db2/history"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestScreening(t *testing.T) {
+	request := &ScreeningRequest{
+		TxHash:      "hash",
+		Type:        "payment",
+		Source:      "source",
+		Destination: "destination",
+		AssetCode:   "UAH",
+		Amount:      "10.0000000",
+	}
+	Convey("List screening:", t, func() {
+		historyQ := &history.QMock{}
+		Convey("Not listed", func() {
+			historyQ.On("ScreeningListEntryByAddress", "source").Return(nil, nil).Once()
+			historyQ.On("ScreeningListEntryByAddress", "destination").Return(nil, nil).Once()
+			result, err := NewListScreeningValidator(historyQ, nil).Screen(request)
+			So(err, ShouldBeNil)
+			So(result.Decision, ShouldEqual, ScreeningDecisionAllow)
+			So(result.Provider, ShouldEqual, ScreeningProviderList)
+		})
+		Convey("Listed by admin", func() {
+			historyQ.On("ScreeningListEntryByAddress", "source").Return(nil, nil).Once()
+			historyQ.On("ScreeningListEntryByAddress", "destination").Return(&history.ScreeningListEntry{
+				Address: "destination",
+				Reason:  "sanctions",
+			}, nil).Once()
+			result, err := NewListScreeningValidator(historyQ, nil).Screen(request)
+			So(err, ShouldBeNil)
+			So(result.Decision, ShouldEqual, ScreeningDecisionDeny)
+			So(result.Reason, ShouldContainSubstring, "sanctions")
+		})
+		Convey("Listed in file", func() {
+			file, err := ioutil.TempFile("", "screening")
+			So(err, ShouldBeNil)
+			defer os.Remove(file.Name())
+			_, err = file.WriteString("# comment\n\nsource\twatch list\nother\n")
+			So(err, ShouldBeNil)
+			file.Close()
+
+			addresses, err := LoadScreeningListFile(file.Name())
+			So(err, ShouldBeNil)
+			So(addresses, ShouldResemble, map[string]string{
+				"source": "watch list",
+				"other":  "",
+			})
+
+			result, err := NewListScreeningValidator(nil, addresses).Screen(request)
+			So(err, ShouldBeNil)
+			So(result.Decision, ShouldEqual, ScreeningDecisionDeny)
+			So(result.Reason, ShouldContainSubstring, "watch list")
+		})
+	})
+	Convey("HTTP screening:", t, func() {
+		var response string
+		var delay time.Duration
+		var received ScreeningRequest
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&received)
+			time.Sleep(delay)
+			w.Write([]byte(response))
+		}))
+		defer server.Close()
+
+		Convey("Service decision is returned", func() {
+			response = `{"decision": "review", "reason": "high risk country"}`
+			result, err := NewHTTPScreeningValidator(server.URL, time.Second, false).Screen(request)
+			So(err, ShouldBeNil)
+			So(received, ShouldResemble, *request)
+			So(result.Decision, ShouldEqual, ScreeningDecisionReview)
+			So(result.Reason, ShouldEqual, "high risk country")
+		})
+		Convey("Invalid decision", func() {
+			response = `{"decision": "maybe"}`
+			result, err := NewHTTPScreeningValidator(server.URL, time.Second, true).Screen(request)
+			So(err, ShouldBeNil)
+			So(result.Decision, ShouldEqual, ScreeningDecisionDeny)
+		})
+		Convey("Timeout fail-open", func() {
+			response = `{"decision": "deny"}`
+			delay = 100 * time.Millisecond
+			result, err := NewHTTPScreeningValidator(server.URL, 10*time.Millisecond, false).Screen(request)
+			So(err, ShouldBeNil)
+			So(result.Decision, ShouldEqual, ScreeningDecisionAllow)
+			So(result.Reason, ShouldContainSubstring, "unavailable")
+		})
+		Convey("Timeout fail-closed", func() {
+			response = `{"decision": "allow"}`
+			delay = 100 * time.Millisecond
+			result, err := NewHTTPScreeningValidator(server.URL, 10*time.Millisecond, true).Screen(request)
+			So(err, ShouldBeNil)
+			So(result.Decision, ShouldEqual, ScreeningDecisionDeny)
+		})
+	})
+	Convey("Chain returns the strictest decision", t, func() {
+		allow := &ScreeningValidatorMock{}
+		allow.On("Screen", request).Return(&ScreeningResult{Provider: "a", Decision: ScreeningDecisionAllow}, nil)
+		review := &ScreeningValidatorMock{}
+		review.On("Screen", request).Return(&ScreeningResult{Provider: "b", Decision: ScreeningDecisionReview}, nil)
+		deny := &ScreeningValidatorMock{}
+		deny.On("Screen", request).Return(&ScreeningResult{Provider: "c", Decision: ScreeningDecisionDeny}, nil)
+
+		result, err := NewScreeningValidatorChain(allow, review, allow).Screen(request)
+		So(err, ShouldBeNil)
+		So(result.Provider, ShouldEqual, "b")
+
+		result, err = NewScreeningValidatorChain(review, deny, allow).Screen(request)
+		So(err, ShouldBeNil)
+		So(result.Provider, ShouldEqual, "c")
+
+		result, err = NewScreeningValidatorChain().Screen(request)
+		So(err, ShouldBeNil)
+		So(result.Decision, ShouldEqual, ScreeningDecisionAllow)
+	})
+}
@@ -4,32 +4,37 @@ import (
 	"bitbucket.org/atticlab/horizon/db2/history"
 	"bitbucket.org/atticlab/horizon/txsub/results"
 	"fmt"
+	"time"
 )
 
 type TraitsValidatorInterface interface {
-	CheckTraits(source, destination *history.Account) (*results.RestrictedForAccountError, error)
-	CheckTraitsForAccount(account *history.Account, isSource bool) (*results.RestrictedForAccountError, error)
+	CheckTraits(source, destination *history.Account, sendAsset, destAsset string) (*results.RestrictedForAccountError, error)
+	CheckTraitsForAccount(account *history.Account, isSource bool, assetCode string) (*results.RestrictedForAccountError, error)
 }
 
 type TraitsValidator struct {
+	historyQ history.QInterface
+	now      time.Time
 }
 
-func NewTraitsValidator() *TraitsValidator {
+func NewTraitsValidator(historyQ history.QInterface, now time.Time) *TraitsValidator {
 	return &TraitsValidator{
+		historyQ: historyQ,
+		now:      now,
 	}
 }
 
-// VerifyRestrictions checks traits of the involved accounts
-func (v *TraitsValidator) CheckTraits(source, destination *history.Account) (*results.RestrictedForAccountError, error) {
-	restriction, err := v.CheckTraitsForAccount(source, true)
+// VerifyRestrictions checks traits and active freezes of the involved accounts
+func (v *TraitsValidator) CheckTraits(source, destination *history.Account, sendAsset, destAsset string) (*results.RestrictedForAccountError, error) {
+	restriction, err := v.CheckTraitsForAccount(source, true, sendAsset)
 	// if id is zero - account is new, so there are no traits for it
 	if restriction == nil && err == nil && destination.ID != 0 {
-		restriction, err = v.CheckTraitsForAccount(destination, false)
+		restriction, err = v.CheckTraitsForAccount(destination, false, destAsset)
 	}
 	return restriction, err
 }
 
-func (v *TraitsValidator) CheckTraitsForAccount(account *history.Account, isSource bool) (*results.RestrictedForAccountError, error) {
+func (v *TraitsValidator) CheckTraitsForAccount(account *history.Account, isSource bool, assetCode string) (*results.RestrictedForAccountError, error) {
 	// Check restrictions
 	if isSource && account.BlockOutcomingPayments {
 		return &results.RestrictedForAccountError{
@@ -43,5 +48,39 @@ func (v *TraitsValidator) CheckTraitsForAccount(account *history.Account, isSour
 		}, nil
 	}
 
+	// Check temporary freezes
+	freezes, err := v.historyQ.ActiveAccountFreezes(account.Address, v.now)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, freeze := range freezes {
+		if !freeze.IsActive(v.now) || !freeze.AppliesTo(assetCode) {
+			continue
+		}
+
+		if isSource && freeze.BlockOutcomingPayments {
+			return freezeRestriction("Outcoming", account.Address, &freeze), nil
+		}
+
+		if !isSource && freeze.BlockIncomingPayments {
+			return freezeRestriction("Incoming", account.Address, &freeze), nil
+		}
+	}
+
 	return nil, nil
 }
+
+func freezeRestriction(direction, address string, freeze *history.AccountFreeze) *results.RestrictedForAccountError {
+	reason := fmt.Sprintf("%s payments for account (%s) are frozen by administrator (reason: %s)", direction, address, freeze.ReasonCode)
+	if freeze.AssetCode != "" {
+		reason += fmt.Sprintf(" for asset %s", freeze.AssetCode)
+	}
+
+	if freeze.ExpiresAt.Valid {
+		reason += fmt.Sprintf(" until %s", freeze.ExpiresAt.Time.UTC().Format(time.RFC3339))
+	}
+	return &results.RestrictedForAccountError{
+		Reason: reason + ".",
+	}
+}
@@ -7,14 +7,18 @@ import (
 	"bitbucket.org/atticlab/horizon/db2/history"
 	"bitbucket.org/atticlab/horizon/txsub/results"
 	"fmt"
+	"github.com/guregu/null"
 	. "github.com/smartystreets/goconvey/convey"
 	"github.com/stretchr/testify/assert"
 	"math/rand"
+	"time"
 )
 
 func TestTraits(t *testing.T) {
-	traits := NewTraitsValidator()
+	now := time.Now()
 	Convey("Traits test:", t, func() {
+		historyQ := &history.QMock{}
+		traits := NewTraitsValidator(historyQ, now)
 		sourceKP, err := keypair.Random()
 		So(err, ShouldBeNil)
 		source := &history.Account{
@@ -31,21 +35,24 @@ func TestTraits(t *testing.T) {
 			},
 			Address: destKP.Address(),
 		}
+		historyQ.On("ActiveAccountFreezes", source.Address, now).Return(nil, nil)
 		Convey("Both accounts does not have traits", func() {
-			result, err := traits.CheckTraits(source, dest)
+			historyQ.On("ActiveAccountFreezes", dest.Address, now).Return(nil, nil).Once()
+			result, err := traits.CheckTraits(source, dest, "UAH", "UAH")
 			So(err, ShouldBeNil)
 			So(result, ShouldBeNil)
 		})
 		Convey("Both accounts have traits, but not blocked", func() {
 			source.BlockIncomingPayments = true
 			dest.BlockOutcomingPayments = true
-			result, err := traits.CheckTraits(source, dest)
+			historyQ.On("ActiveAccountFreezes", dest.Address, now).Return(nil, nil).Once()
+			result, err := traits.CheckTraits(source, dest, "UAH", "UAH")
 			So(err, ShouldBeNil)
 			So(result, ShouldBeNil)
 		})
 		Convey("Source is blocked", func() {
 			source.BlockOutcomingPayments = true
-			result, err := traits.CheckTraits(source, dest)
+			result, err := traits.CheckTraits(source, dest, "UAH", "UAH")
 			So(err, ShouldBeNil)
 			assert.Equal(t, result, &results.RestrictedForAccountError{
 				Reason: fmt.Sprintf("Outcoming payments for account (%s) are restricted by administrator.", source.Address),
@@ -54,12 +61,53 @@ func TestTraits(t *testing.T) {
 		Convey("Dest is blocked", func() {
 			source.BlockOutcomingPayments = false
 			dest.BlockIncomingPayments = true
-			result, err := traits.CheckTraits(source, dest)
+			result, err := traits.CheckTraits(source, dest, "UAH", "UAH")
 			So(err, ShouldBeNil)
 			assert.Equal(t, result, &results.RestrictedForAccountError{
 				Reason: fmt.Sprintf("Incoming payments for account (%s) are restricted by administrator.", dest.Address),
 			})
 		})
+		Convey("Dest is frozen", func() {
+			expiresAt := time.Date(2030, time.January, 2, 15, 4, 5, 0, time.UTC)
+			historyQ.On("ActiveAccountFreezes", dest.Address, now).Return([]history.AccountFreeze{
+				{
+					Address:               dest.Address,
+					AssetCode:             "USD",
+					BlockIncomingPayments: true,
+					ReasonCode:            history.FreezeReasonSanctions,
+				},
+				{
+					Address:                dest.Address,
+					BlockOutcomingPayments: true,
+					ReasonCode:             history.FreezeReasonCourtOrder,
+				},
+				{
+					Address:               dest.Address,
+					AssetCode:             "UAH",
+					BlockIncomingPayments: true,
+					ReasonCode:            history.FreezeReasonFraudInvestigation,
+					ExpiresAt:             null.TimeFrom(expiresAt),
+				},
+			}, nil).Once()
+			result, err := traits.CheckTraits(source, dest, "UAH", "UAH")
+			So(err, ShouldBeNil)
+			assert.Equal(t, result, &results.RestrictedForAccountError{
+				Reason: fmt.Sprintf("Incoming payments for account (%s) are frozen by administrator (reason: fraud_investigation) for asset UAH until 2030-01-02T15:04:05Z.", dest.Address),
+			})
+		})
+		Convey("Expired freeze is ignored", func() {
+			historyQ.On("ActiveAccountFreezes", dest.Address, now).Return([]history.AccountFreeze{
+				{
+					Address:               dest.Address,
+					BlockIncomingPayments: true,
+					ReasonCode:            history.FreezeReasonOther,
+					ExpiresAt:             null.TimeFrom(now.Add(-time.Minute)),
+				},
+			}, nil).Once()
+			result, err := traits.CheckTraits(source, dest, "UAH", "UAH")
+			So(err, ShouldBeNil)
+			So(result, ShouldBeNil)
+		})
 
 	})
 }